@@ -0,0 +1,233 @@
+package influx
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// DefaultAggregateWindow is the default AggregateConfig.Window.
+const DefaultAggregateWindow = time.Minute
+
+// AggregateConfig configures the pre-aggregation buffer enabled by
+// Client.Aggregate.
+type AggregateConfig struct {
+	// Window is how often buffered points are collapsed and flushed.
+	// Defaults to DefaultAggregateWindow.
+	Window time.Duration
+
+	// Database, Precision and RetentionPolicy describe the batches
+	// written on every flush.
+	Database        string
+	Precision       string
+	RetentionPolicy string
+}
+
+// aggKey groups points sharing a measurement and tag set.
+type aggKey struct {
+	name string
+	tags string
+}
+
+// aggField accumulates the min, max, sum and count of one field across
+// the points collapsed into it during the current window.
+type aggField struct {
+	min, max float64
+	sum      float64
+	count    int64
+}
+
+func (f *aggField) add(v float64) {
+	if f.count == 0 {
+		f.min, f.max = v, v
+	} else if v < f.min {
+		f.min = v
+	} else if v > f.max {
+		f.max = v
+	}
+	f.sum += v
+	f.count++
+}
+
+// aggGroup is the in-progress aggregate for one aggKey.
+type aggGroup struct {
+	tags   map[string]string
+	time   time.Time
+	fields map[string]*aggField
+}
+
+// Aggregate enables a pre-aggregation buffer: points passed to
+// AggregatePoint are grouped by measurement and tag set, and every
+// cfg.Window their numeric fields are collapsed into "<field>_min",
+// "<field>_max", "<field>_mean", "<field>_sum" and "<field>_count"
+// fields on a single point, timestamped with the last point seen in the
+// window, and written with WriteBatchPointsContext. Non-numeric fields
+// are dropped from the aggregate; a point with no numeric fields
+// contributes nothing. This lets a high-frequency producer (a sensor
+// polled every few milliseconds, say) be downsampled without the
+// producer itself changing.
+//
+// Calling Aggregate again replaces the previous config and restarts the
+// background goroutine, discarding anything buffered; Close stops it.
+func (c *Client) Aggregate(cfg AggregateConfig) error {
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultAggregateWindow
+	}
+
+	c.aggMu.Lock()
+	c.aggCfg = &cfg
+	c.aggBuf = make(map[aggKey]*aggGroup)
+	c.aggMu.Unlock()
+
+	c.mu.Lock()
+	if c.aggStop != nil {
+		close(c.aggStop)
+	}
+	stop := make(chan struct{})
+	c.aggStop = stop
+	c.mu.Unlock()
+
+	go c.aggregateLoop(cfg, stop)
+	return nil
+}
+
+func (c *Client) aggregateLoop(cfg AggregateConfig, stop chan struct{}) {
+	ticker := newTicker(cfg.Window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C():
+			c.flushAggregate(cfg)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// AggregatePoint adds point to the buffer enabled by Aggregate, to be
+// collapsed and written on the next window flush. It is a no-op if
+// Aggregate hasn't been called.
+func (c *Client) AggregatePoint(point *client.Point) {
+	fields, err := point.Fields()
+	if err != nil {
+		return
+	}
+
+	k := aggKey{name: point.Name(), tags: tagsKey(point.Tags())}
+
+	c.aggMu.Lock()
+	defer c.aggMu.Unlock()
+
+	if c.aggBuf == nil {
+		return
+	}
+
+	g, ok := c.aggBuf[k]
+	if !ok {
+		g = &aggGroup{tags: point.Tags(), fields: make(map[string]*aggField)}
+		c.aggBuf[k] = g
+	}
+	g.time = point.Time()
+
+	for name, v := range fields {
+		f, ok := aggFloat(v)
+		if !ok {
+			continue
+		}
+		af, ok := g.fields[name]
+		if !ok {
+			af = &aggField{}
+			g.fields[name] = af
+		}
+		af.add(f)
+	}
+}
+
+// flushAggregate collapses every group buffered since the last flush
+// into one point each and writes them in a single batch.
+func (c *Client) flushAggregate(cfg AggregateConfig) {
+	c.aggMu.Lock()
+	buf := c.aggBuf
+	c.aggBuf = make(map[aggKey]*aggGroup)
+	c.aggMu.Unlock()
+
+	if len(buf) == 0 {
+		return
+	}
+
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{
+		Database:        cfg.Database,
+		Precision:       cfg.Precision,
+		RetentionPolicy: cfg.RetentionPolicy,
+	})
+	if err != nil {
+		return
+	}
+
+	for k, g := range buf {
+		if len(g.fields) == 0 {
+			continue
+		}
+		fields := make(map[string]interface{}, len(g.fields)*5)
+		for name, f := range g.fields {
+			fields[name+"_min"] = f.min
+			fields[name+"_max"] = f.max
+			fields[name+"_mean"] = f.sum / float64(f.count)
+			fields[name+"_sum"] = f.sum
+			fields[name+"_count"] = f.count
+		}
+		p, err := client.NewPoint(k.name, g.tags, fields, g.time)
+		if err != nil {
+			continue
+		}
+		bp.AddPoint(p)
+	}
+
+	c.WriteBatchPointsContext(context.Background(), bp)
+}
+
+// aggFloat reports the numeric value of a point field, or ok=false for
+// a non-numeric one (string, bool).
+func aggFloat(v interface{}) (f float64, ok bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// Aggregate enables a pre-aggregation buffer on the package-level
+// default Client.
+func Aggregate(cfg AggregateConfig) error {
+	return gClient().Aggregate(cfg)
+}
+
+// AggregatePoint adds point to the package-level default Client's
+// aggregation buffer.
+func AggregatePoint(point *client.Point) {
+	gClient().AggregatePoint(point)
+}
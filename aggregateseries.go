@@ -0,0 +1,151 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeValue is one bucket of a time-grouped aggregate query result,
+// decoded by MeanSeries/SumSeries/CountSeries/MinSeries/MaxSeries/
+// PercentileSeries — named distinctly from the scalar Count/Mean/Sum/
+// Percentile in scalaraggregate.go, which these are the time-grouped
+// counterparts of, and from AggregatePoint (aggregate.go's unrelated
+// client-side pre-aggregation buffer).
+type TimeValue struct {
+	Time  time.Time `inf:"time"`
+	Value float64   `inf:"value"`
+}
+
+// MeanSeries runs SELECT mean(field) FROM measurement on db, bucketed
+// into groupBy-wide GROUP BY time() windows and ANDing where and
+// timeRange onto its WHERE clause (either may be "" to omit its half),
+// and returns one TimeValue per bucket — the time-grouped counterpart of
+// Mean, for a caller building a chart instead of reading a single
+// number.
+func (c *Client) MeanSeries(db, measurement, field string, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	return c.MeanSeriesContext(context.Background(), db, measurement, field, groupBy, where, timeRange)
+}
+
+// MeanSeriesContext is MeanSeries with a context that aborts the request
+// as soon as it is canceled.
+func (c *Client) MeanSeriesContext(ctx context.Context, db, measurement, field string, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	return c.aggregateSeries(ctx, db, measurement, fmt.Sprintf("mean(%s)", Ident(field)), groupBy, where, timeRange)
+}
+
+// SumSeries is MeanSeries for sum(field).
+func (c *Client) SumSeries(db, measurement, field string, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	return c.SumSeriesContext(context.Background(), db, measurement, field, groupBy, where, timeRange)
+}
+
+// SumSeriesContext is SumSeries with a context that aborts the request
+// as soon as it is canceled.
+func (c *Client) SumSeriesContext(ctx context.Context, db, measurement, field string, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	return c.aggregateSeries(ctx, db, measurement, fmt.Sprintf("sum(%s)", Ident(field)), groupBy, where, timeRange)
+}
+
+// CountSeries is MeanSeries for count(field).
+func (c *Client) CountSeries(db, measurement, field string, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	return c.CountSeriesContext(context.Background(), db, measurement, field, groupBy, where, timeRange)
+}
+
+// CountSeriesContext is CountSeries with a context that aborts the
+// request as soon as it is canceled.
+func (c *Client) CountSeriesContext(ctx context.Context, db, measurement, field string, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	return c.aggregateSeries(ctx, db, measurement, fmt.Sprintf("count(%s)", Ident(field)), groupBy, where, timeRange)
+}
+
+// MinSeries is MeanSeries for min(field).
+func (c *Client) MinSeries(db, measurement, field string, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	return c.MinSeriesContext(context.Background(), db, measurement, field, groupBy, where, timeRange)
+}
+
+// MinSeriesContext is MinSeries with a context that aborts the request
+// as soon as it is canceled.
+func (c *Client) MinSeriesContext(ctx context.Context, db, measurement, field string, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	return c.aggregateSeries(ctx, db, measurement, fmt.Sprintf("min(%s)", Ident(field)), groupBy, where, timeRange)
+}
+
+// MaxSeries is MeanSeries for max(field).
+func (c *Client) MaxSeries(db, measurement, field string, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	return c.MaxSeriesContext(context.Background(), db, measurement, field, groupBy, where, timeRange)
+}
+
+// MaxSeriesContext is MaxSeries with a context that aborts the request
+// as soon as it is canceled.
+func (c *Client) MaxSeriesContext(ctx context.Context, db, measurement, field string, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	return c.aggregateSeries(ctx, db, measurement, fmt.Sprintf("max(%s)", Ident(field)), groupBy, where, timeRange)
+}
+
+// PercentileSeries is MeanSeries for percentile(field, pct).
+func (c *Client) PercentileSeries(db, measurement, field string, pct float64, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	return c.PercentileSeriesContext(context.Background(), db, measurement, field, pct, groupBy, where, timeRange)
+}
+
+// PercentileSeriesContext is PercentileSeries with a context that aborts
+// the request as soon as it is canceled.
+func (c *Client) PercentileSeriesContext(ctx context.Context, db, measurement, field string, pct float64, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	aggExpr := fmt.Sprintf("percentile(%s, %v)", Ident(field), pct)
+	return c.aggregateSeries(ctx, db, measurement, aggExpr, groupBy, where, timeRange)
+}
+
+// aggregateSeries runs "SELECT aggExpr AS value FROM measurement WHERE
+// ... GROUP BY time(groupBy)" on db and decodes its time/value columns
+// into one TimeValue per bucket, the shared plumbing MeanSeries/
+// SumSeries/CountSeries/MinSeries/MaxSeries/PercentileSeries all build
+// on, the time-grouped counterpart of scalarAggregate. aggExpr is
+// aliased to "value" so every caller decodes into the same TimeValue
+// shape regardless of which aggregate function or field name produced
+// the column.
+func (c *Client) aggregateSeries(ctx context.Context, db, measurement, aggExpr string, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	cmd := fmt.Sprintf("SELECT %s AS value FROM %s", aggExpr, Ident(measurement))
+
+	var conds []string
+	if where != "" {
+		conds = append(conds, where)
+	}
+	if timeRange != "" {
+		conds = append(conds, timeRange)
+	}
+	if len(conds) > 0 {
+		cmd += " WHERE " + strings.Join(conds, " AND ")
+	}
+	cmd += " " + GroupByTime(groupBy, 0, "")
+
+	var rows []TimeValue
+	if err := c.QueryIntoContext(ctx, &rows, db, cmd, "time", "value"); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// MeanSeries runs MeanSeries using the default Client.
+func MeanSeries(db, measurement, field string, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	return gClient().MeanSeries(db, measurement, field, groupBy, where, timeRange)
+}
+
+// SumSeries runs SumSeries using the default Client.
+func SumSeries(db, measurement, field string, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	return gClient().SumSeries(db, measurement, field, groupBy, where, timeRange)
+}
+
+// CountSeries runs CountSeries using the default Client.
+func CountSeries(db, measurement, field string, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	return gClient().CountSeries(db, measurement, field, groupBy, where, timeRange)
+}
+
+// MinSeries runs MinSeries using the default Client.
+func MinSeries(db, measurement, field string, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	return gClient().MinSeries(db, measurement, field, groupBy, where, timeRange)
+}
+
+// MaxSeries runs MaxSeries using the default Client.
+func MaxSeries(db, measurement, field string, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	return gClient().MaxSeries(db, measurement, field, groupBy, where, timeRange)
+}
+
+// PercentileSeries runs PercentileSeries using the default Client.
+func PercentileSeries(db, measurement, field string, pct float64, groupBy time.Duration, where, timeRange string) ([]TimeValue, error) {
+	return gClient().PercentileSeries(db, measurement, field, pct, groupBy, where, timeRange)
+}
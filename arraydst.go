@@ -0,0 +1,19 @@
+package influx
+
+import "sync/atomic"
+
+// arrayElementsWritten counts, cumulatively across every ParseResult
+// call, how many rows have been decoded into an [N]T destination
+// array, including rows short of N as well as truncated ones.
+var arrayElementsWritten int64
+
+// ArrayElementsWritten returns the number of rows ParseResult has
+// written into [N]T destination arrays so far. A caller sizing its
+// array from a fixed bucket count (e.g. [24]float64 for hourly
+// buckets) can diff two readings around a call to learn how many of
+// its N slots that call actually filled, instead of a query with
+// fewer rows than N silently leaving the rest at their zero value
+// with no way to tell which that was.
+func ArrayElementsWritten() int64 {
+	return atomic.LoadInt64(&arrayElementsWritten)
+}
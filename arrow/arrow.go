@@ -0,0 +1,213 @@
+// Package arrow converts query results into Apache Arrow records —
+// columnar, typed, zero-copy over a contiguous buffer — so a large
+// extract can be handed to DataFusion, read by Python over Arrow IPC,
+// or otherwise consumed without paying JSON's per-row parsing cost.
+//
+// A models.Row's column type isn't known ahead of time the way a
+// database schema's is: ExportRecord infers each column's Arrow type
+// from the first non-null value it finds in that column, defaulting to
+// a string column when every value is null.
+package arrow
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/influxdata/influxdb/models"
+)
+
+// ExportRecord converts row into an arrow.Record: one column per
+// row.Columns entry, plus one further string column per tag in
+// row.Tags (the same value repeated down every row, since a Row's
+// whole series shares one tag set). The caller owns the returned
+// Record and must call its Release when done with it.
+func ExportRecord(row models.Row) (arrow.Record, error) {
+	tagNames := sortedKeys(row.Tags)
+
+	fields := make([]arrow.Field, 0, len(row.Columns)+len(tagNames))
+	types := make([]arrow.DataType, 0, len(row.Columns)+len(tagNames))
+	for _, col := range row.Columns {
+		typ := columnType(row, col)
+		fields = append(fields, arrow.Field{Name: col, Type: typ, Nullable: true})
+		types = append(types, typ)
+	}
+	for _, tag := range tagNames {
+		fields = append(fields, arrow.Field{Name: tag, Type: arrow.BinaryTypes.String})
+		types = append(types, arrow.BinaryTypes.String)
+	}
+
+	pool := memory.NewGoAllocator()
+	builders := make([]array.Builder, len(types))
+	for i, typ := range types {
+		builders[i] = array.NewBuilder(pool, typ)
+		defer builders[i].Release()
+	}
+
+	for _, vals := range row.Values {
+		for i, col := range row.Columns {
+			var v interface{}
+			if i < len(vals) {
+				v = columnValue(col, vals[i])
+			}
+			if err := appendValue(builders[i], v); err != nil {
+				return nil, fmt.Errorf("influx/arrow: column %q: %w", col, err)
+			}
+		}
+		for i, tag := range tagNames {
+			builders[len(row.Columns)+i].(*array.StringBuilder).Append(row.Tags[tag])
+		}
+	}
+
+	cols := make([]arrow.Array, len(builders))
+	for i, b := range builders {
+		cols[i] = b.NewArray()
+		defer cols[i].Release()
+	}
+
+	schema := arrow.NewSchema(fields, nil)
+	return array.NewRecord(schema, cols, int64(len(row.Values))), nil
+}
+
+// ExportRecords is ExportRecord applied to every row in series, one
+// Record per row, in order. A failed conversion releases the records
+// already built before returning the error, so a caller never leaks a
+// partially-returned slice.
+func ExportRecords(series []models.Row) ([]arrow.Record, error) {
+	records := make([]arrow.Record, 0, len(series))
+	for _, row := range series {
+		rec, err := ExportRecord(row)
+		if err != nil {
+			for _, r := range records {
+				r.Release()
+			}
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// columnType infers col's Arrow type from the first non-null value
+// found in it, defaulting to a string column when every value is
+// null (or there are no rows at all).
+func columnType(row models.Row, col string) arrow.DataType {
+	idx := indexOf(row.Columns, col)
+	if idx < 0 {
+		return arrow.BinaryTypes.String
+	}
+	for _, vals := range row.Values {
+		if idx >= len(vals) {
+			continue
+		}
+		switch columnValue(col, vals[idx]).(type) {
+		case nil:
+			continue
+		case int64:
+			return arrow.PrimitiveTypes.Int64
+		case float64:
+			return arrow.PrimitiveTypes.Float64
+		case bool:
+			return arrow.FixedWidthTypes.Boolean
+		case time.Time:
+			return arrow.FixedWidthTypes.Timestamp_ns
+		default:
+			return arrow.BinaryTypes.String
+		}
+	}
+	return arrow.BinaryTypes.String
+}
+
+// columnValue converts val, a column value as JSON-decoded with
+// client/v2's json.Number precision, the same way influx.nativeValue
+// does internally: a json.Number becomes an int64 or float64, and the
+// "time" column's RFC3339 string becomes a time.Time. Everything else
+// passes through unchanged.
+func columnValue(col string, val interface{}) interface{} {
+	if val == nil {
+		return nil
+	}
+	if n, ok := val.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return i
+		}
+		f, _ := n.Float64()
+		return f
+	}
+	if col == "time" {
+		if s, ok := val.(string); ok {
+			if t, err := time.Parse(time.RFC3339, s); err == nil {
+				return t
+			}
+		}
+	}
+	return val
+}
+
+func appendValue(b array.Builder, v interface{}) error {
+	if v == nil {
+		b.AppendNull()
+		return nil
+	}
+	switch bb := b.(type) {
+	case *array.Int64Builder:
+		n, ok := v.(int64)
+		if !ok {
+			f, ok2 := v.(float64)
+			if !ok2 {
+				return fmt.Errorf("value %v (%T) is not a number", v, v)
+			}
+			n = int64(f)
+		}
+		bb.Append(n)
+	case *array.Float64Builder:
+		f, ok := v.(float64)
+		if !ok {
+			n, ok2 := v.(int64)
+			if !ok2 {
+				return fmt.Errorf("value %v (%T) is not a number", v, v)
+			}
+			f = float64(n)
+		}
+		bb.Append(f)
+	case *array.BooleanBuilder:
+		vb, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("value %v (%T) is not a bool", v, v)
+		}
+		bb.Append(vb)
+	case *array.TimestampBuilder:
+		t, ok := v.(time.Time)
+		if !ok {
+			return fmt.Errorf("value %v (%T) is not a time.Time", v, v)
+		}
+		bb.Append(arrow.Timestamp(t.UnixNano()))
+	case *array.StringBuilder:
+		bb.Append(fmt.Sprint(v))
+	default:
+		return fmt.Errorf("unsupported column builder %T", b)
+	}
+	return nil
+}
+
+func indexOf(columns []string, col string) int {
+	for i, c := range columns {
+		if c == col {
+			return i
+		}
+	}
+	return -1
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
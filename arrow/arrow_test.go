@@ -0,0 +1,91 @@
+package arrow
+
+import (
+	"testing"
+
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/influxdata/influxdb/models"
+)
+
+// TestExportRecordTypesColumns confirms each column takes the Arrow
+// type of its first non-null value, and that a tag becomes a
+// constant-valued string column of its own.
+func TestExportRecordTypesColumns(t *testing.T) {
+	row := models.Row{
+		Name:    "cpu",
+		Tags:    map[string]string{"host": "a"},
+		Columns: []string{"time", "value", "ok"},
+		Values: [][]interface{}{
+			{"2020-01-02T03:04:05Z", 1.5, true},
+			{"2020-01-02T03:05:05Z", 2.5, false},
+		},
+	}
+
+	rec, err := ExportRecord(row)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rec.Release()
+
+	if rec.NumRows() != 2 {
+		t.Fatalf("NumRows() = %d, want 2", rec.NumRows())
+	}
+	if rec.NumCols() != 4 {
+		t.Fatalf("NumCols() = %d, want 4 (time, value, ok, host)", rec.NumCols())
+	}
+
+	valueCol, ok := rec.Column(1).(*array.Float64)
+	if !ok {
+		t.Fatalf("value column = %T, want *array.Float64", rec.Column(1))
+	}
+	if valueCol.Value(0) != 1.5 || valueCol.Value(1) != 2.5 {
+		t.Fatalf("value column = %v", valueCol)
+	}
+
+	hostCol, ok := rec.Column(3).(*array.String)
+	if !ok {
+		t.Fatalf("host column = %T, want *array.String", rec.Column(3))
+	}
+	if hostCol.Value(0) != "a" || hostCol.Value(1) != "a" {
+		t.Fatalf("host column = %v", hostCol)
+	}
+}
+
+// TestExportRecordHandlesNulls confirms a null cell becomes a null
+// Arrow value instead of a decode error.
+func TestExportRecordHandlesNulls(t *testing.T) {
+	row := models.Row{
+		Columns: []string{"time", "value"},
+		Values: [][]interface{}{
+			{"2020-01-02T03:04:05Z", 1.5},
+			{"2020-01-02T03:05:05Z", nil},
+		},
+	}
+
+	rec, err := ExportRecord(row)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rec.Release()
+
+	valueCol := rec.Column(1).(*array.Float64)
+	if !valueCol.IsNull(1) {
+		t.Fatal("want row 1's value to be null")
+	}
+}
+
+// TestExportRecordsOneRecordPerSeries confirms ExportRecords returns one
+// Record per series when every conversion succeeds.
+func TestExportRecordsOneRecordPerSeries(t *testing.T) {
+	series := []models.Row{
+		{Columns: []string{"time", "value"}, Values: [][]interface{}{{"2020-01-02T03:04:05Z", 1.0}}},
+	}
+	records, err := ExportRecords(series)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("len(records) = %d, want 1", len(records))
+	}
+	records[0].Release()
+}
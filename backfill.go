@@ -0,0 +1,97 @@
+package influx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BackfillOptions configures Backfill. Select, Into, From, Where and
+// GroupBy are raw InfluxQL fragments (quote identifiers with Ident,
+// string literals with QuoteString yourself, the same as everywhere
+// else in this package that builds a command from caller-supplied
+// pieces) — Backfill only adds the time bounds and slices them.
+type BackfillOptions struct {
+	// Select is the aggregate expression, e.g. "mean(value)".
+	Select string
+	// Into is the destination measurement, e.g. `"downsample_1h"."cpu"`
+	// for a retention-policy-qualified target.
+	Into string
+	// From is the source measurement.
+	From string
+	// Where, if non-empty, is ANDed onto each slice's time bound.
+	Where string
+	// GroupBy, if non-empty, is appended as-is after GROUP BY, e.g.
+	// "time(1h),*".
+	GroupBy string
+
+	// Start and End bound the whole backfill; End is exclusive.
+	Start, End time.Time
+	// SliceSize is the time span each INTO query covers. It must be
+	// positive — this is what keeps any one query small enough not to
+	// time out.
+	SliceSize time.Duration
+
+	// Progress, if non-nil, is called after every slice's query, in
+	// order, with that slice's bounds and its error (nil on success),
+	// so a long backfill can report where it is and a caller can abort
+	// a resumable backfill right where it left off.
+	Progress func(start, end time.Time, err error)
+}
+
+// Backfill runs opts.Select INTO opts.Into FROM opts.From over
+// [opts.Start, opts.End) a SliceSize-wide time slice at a time instead
+// of in one query, since a single INTO query spanning a large time
+// range regularly times out rather than completing slowly. It stops
+// and returns the first slice's error, if any — opts.Progress has
+// already been told about it by then.
+func (c *Client) Backfill(db string, opts BackfillOptions) error {
+	return c.BackfillContext(context.Background(), db, opts)
+}
+
+// BackfillContext is Backfill with a context that aborts the current
+// slice's request as soon as it is canceled.
+func (c *Client) BackfillContext(ctx context.Context, db string, opts BackfillOptions) error {
+	if opts.SliceSize <= 0 {
+		return errors.New("influx: Backfill requires a positive SliceSize")
+	}
+	for start := opts.Start; start.Before(opts.End); start = start.Add(opts.SliceSize) {
+		end := start.Add(opts.SliceSize)
+		if end.After(opts.End) {
+			end = opts.End
+		}
+
+		_, err := c.QueryContext(ctx, db, backfillCmd(opts, start, end))
+		if opts.Progress != nil {
+			opts.Progress(start, end, err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillCmd builds one slice's SELECT ... INTO ... FROM ... statement.
+func backfillCmd(opts BackfillOptions, start, end time.Time) string {
+	cmd := fmt.Sprintf("SELECT %s INTO %s FROM %s WHERE %s", opts.Select, opts.Into, opts.From, Between(start, end))
+	if opts.Where != "" {
+		cmd += " AND " + opts.Where
+	}
+	if opts.GroupBy != "" {
+		cmd += " GROUP BY " + opts.GroupBy
+	}
+	return cmd
+}
+
+// Backfill runs a backfill using the default Client.
+func Backfill(db string, opts BackfillOptions) error {
+	return gClient().Backfill(db, opts)
+}
+
+// BackfillContext is Backfill with a context, using the default
+// Client.
+func BackfillContext(ctx context.Context, db string, opts BackfillOptions) error {
+	return gClient().BackfillContext(ctx, db, opts)
+}
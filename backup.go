@@ -0,0 +1,233 @@
+package influx
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/models"
+)
+
+// DefaultExportChunkSize is the default ExportOptions.ChunkSize.
+const DefaultExportChunkSize = DefaultChunkSize
+
+// DefaultImportBatchSize is the default ImportOptions.BatchSize.
+const DefaultImportBatchSize = 5000
+
+// ExportOptions configures Export.
+type ExportOptions struct {
+	// Measurement is a measurement name or regex source, e.g. "cpu" or
+	// "/.*/" to export every measurement. Defaults to "/.*/".
+	Measurement string
+	// Where, if non-empty, is ANDed onto the time bound below.
+	Where string
+	// Start and End bound the exported time range; either may be zero
+	// to leave that side unbounded.
+	Start, End time.Time
+	// ChunkSize is passed to QueryChunked. Defaults to
+	// DefaultExportChunkSize.
+	ChunkSize int
+}
+
+// Export streams db's data matching opts out to w as line protocol,
+// fetched via a chunked query so exporting a database far larger than
+// memory doesn't require holding it all at once, and returns the
+// number of points written. It exists for a cross-cluster copy or an
+// ad-hoc backup when the operator doesn't have influxd backup/restore
+// access to the source server — only the same HTTP query/write access
+// this package already needs. Import reads Export's output back in.
+func (c *Client) Export(w io.Writer, db string, opts ExportOptions) (int64, error) {
+	return c.ExportContext(context.Background(), w, db, opts)
+}
+
+// ExportContext is Export with a context that aborts the underlying
+// chunked query as soon as it is canceled.
+func (c *Client) ExportContext(ctx context.Context, w io.Writer, db string, opts ExportOptions) (int64, error) {
+	measurement := opts.Measurement
+	if measurement == "" {
+		measurement = "/.*/"
+	}
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultExportChunkSize
+	}
+
+	cmd := "SELECT * FROM " + measurement
+	var conds []string
+	if !opts.Start.IsZero() {
+		conds = append(conds, "time >= "+timeLiteral(opts.Start))
+	}
+	if !opts.End.IsZero() {
+		conds = append(conds, "time < "+timeLiteral(opts.End))
+	}
+	if opts.Where != "" {
+		conds = append(conds, opts.Where)
+	}
+	if len(conds) > 0 {
+		cmd += " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	cr, err := c.QueryChunkedContext(ctx, db, cmd, chunkSize)
+	if err != nil {
+		return 0, err
+	}
+	defer cr.Close()
+
+	var n int64
+	for cr.Next() {
+		columns, values := cr.Columns(), cr.Values()
+		if len(columns) == 0 || len(values) == 0 || values[0] == nil {
+			continue
+		}
+
+		fields := make(map[string]interface{}, len(columns)-1)
+		for i := 1; i < len(columns) && i < len(values); i++ {
+			if values[i] == nil {
+				continue
+			}
+			fields[columns[i]] = exportFieldValue(values[i])
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		p, err := client.NewPoint(cr.Name(), cr.Tags(), fields, parseTime(values[0]))
+		if err != nil {
+			return n, err
+		}
+		if _, err := io.WriteString(w, p.PrecisionString("ns")); err != nil {
+			return n, err
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err := cr.Err(); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+// exportFieldValue converts a decoded query result value back into the
+// shape client.NewPoint expects for a field: a json.Number becomes an
+// int64 when it parses as one exactly, a float64 otherwise, so a
+// re-imported point keeps its original field type instead of every
+// numeric field turning into a float.
+func exportFieldValue(v interface{}) interface{} {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return n.String()
+}
+
+// ImportOptions configures Import.
+type ImportOptions struct {
+	// BatchSize is how many points accumulate before each write.
+	// Defaults to DefaultImportBatchSize.
+	BatchSize int
+	// RetentionPolicy, if non-empty, is the RP each batch is written
+	// under.
+	RetentionPolicy string
+}
+
+// Import reads r as line protocol (the shape Export produces) and
+// writes it to db in batches of opts.BatchSize, respecting c's own
+// RateLimit config the same as any other write, and returns the number
+// of points written. It stops and returns the first error, including a
+// malformed line.
+func (c *Client) Import(r io.Reader, db string, opts ImportOptions) (int64, error) {
+	return c.ImportContext(context.Background(), r, db, opts)
+}
+
+// ImportContext is Import with a context that aborts the current
+// batch's write, and any pending rate-limit wait, as soon as it is
+// canceled.
+func (c *Client) ImportContext(ctx context.Context, r io.Reader, db string, opts ImportOptions) (int64, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultImportBatchSize
+	}
+
+	bp, err := newImportBatch(db, opts.RetentionPolicy)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		pts, err := models.ParsePointsString(line)
+		if err != nil {
+			return n, err
+		}
+		for _, pt := range pts {
+			bp.AddPoint(client.NewPointFrom(pt))
+			n++
+			if len(bp.Points()) >= batchSize {
+				if err := c.WriteBatchPointsContext(ctx, bp); err != nil {
+					return n, err
+				}
+				bp, err = newImportBatch(db, opts.RetentionPolicy)
+				if err != nil {
+					return n, err
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return n, err
+	}
+
+	if len(bp.Points()) > 0 {
+		if err := c.WriteBatchPointsContext(ctx, bp); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// newImportBatch returns an empty BatchPoints for one Import batch.
+func newImportBatch(db, retentionPolicy string) (client.BatchPoints, error) {
+	return client.NewBatchPoints(client.BatchPointsConfig{
+		Database:        db,
+		Precision:       "ns",
+		RetentionPolicy: retentionPolicy,
+	})
+}
+
+// Export streams db's data using the default Client.
+func Export(w io.Writer, db string, opts ExportOptions) (int64, error) {
+	return gClient().Export(w, db, opts)
+}
+
+// ExportContext is Export with a context, using the default Client.
+func ExportContext(ctx context.Context, w io.Writer, db string, opts ExportOptions) (int64, error) {
+	return gClient().ExportContext(ctx, w, db, opts)
+}
+
+// Import reads line protocol into db using the default Client.
+func Import(r io.Reader, db string, opts ImportOptions) (int64, error) {
+	return gClient().Import(r, db, opts)
+}
+
+// ImportContext is Import with a context, using the default Client.
+func ImportContext(ctx context.Context, r io.Reader, db string, opts ImportOptions) (int64, error) {
+	return gClient().ImportContext(ctx, r, db, opts)
+}
@@ -0,0 +1,110 @@
+package influx
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// TestExportContextWritesLineProtocol drives ExportContext against a
+// server returning one chunked response with two series, and confirms
+// the rendered line protocol carries each series' name, tags and
+// fields.
+func TestExportContextWritesLineProtocol(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":[{"series":[
+			{"name":"cpu","tags":{"host":"a"},"columns":["time","value"],"values":[["2020-01-01T00:00:00Z",1.5]]},
+			{"name":"cpu","tags":{"host":"b"},"columns":["time","value"],"values":[["2020-01-01T00:00:01Z",2.5]]}
+		]}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	n, err := c.Export(&buf, "mydb", ExportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("n = %d, want 2", n)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "cpu,host=a value=1.5") {
+		t.Fatalf("output = %q, missing host=a line", out)
+	}
+	if !strings.Contains(out, "cpu,host=b value=2.5") {
+		t.Fatalf("output = %q, missing host=b line", out)
+	}
+}
+
+// TestImportContextWritesBatches confirms Import parses line protocol
+// and writes it to db in batches of the configured size.
+func TestImportContextWritesBatches(t *testing.T) {
+	var writes []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := make([]byte, r.ContentLength)
+		r.Body.Read(body)
+		writes = append(writes, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := "cpu,host=a value=1.5 1577836800000000000\n" +
+		"cpu,host=b value=2.5 1577836801000000000\n" +
+		"cpu,host=c value=3.5 1577836802000000000\n"
+
+	n, err := c.Import(strings.NewReader(lines), "mydb", ImportOptions{BatchSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("n = %d, want 3", n)
+	}
+	if len(writes) != 2 {
+		t.Fatalf("len(writes) = %d, want 2 batches", len(writes))
+	}
+	if !strings.Contains(writes[0], "host=a") || !strings.Contains(writes[0], "host=b") {
+		t.Fatalf("writes[0] = %q", writes[0])
+	}
+	if !strings.Contains(writes[1], "host=c") {
+		t.Fatalf("writes[1] = %q", writes[1])
+	}
+}
+
+// TestImportContextSkipsBlankLines confirms Import tolerates blank
+// lines between points, the way a hand-edited export file might have.
+func TestImportContextSkipsBlankLines(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := "cpu,host=a value=1.5 1577836800000000000\n\n\ncpu,host=b value=2.5 1577836801000000000\n"
+	n, err := c.Import(strings.NewReader(lines), "mydb", ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("n = %d, want 2", n)
+	}
+}
@@ -0,0 +1,130 @@
+package influx
+
+import (
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// tagsPool and fieldsPool recycle the map[string]string and
+// map[string]interface{} Batch.NewPoint and toPoint (so ToPoint,
+// InsertStruct and everything built on them) borrow to build a point:
+// client.NewPoint (via models.NewPoint) encodes a point's tags and
+// fields into its own representation before returning, so the maps
+// are safe to clear and hand to the next NewPoint call instead of
+// discarding them for the GC.
+var (
+	tagsPool = sync.Pool{
+		New: func() interface{} { return make(map[string]string) },
+	}
+	fieldsPool = sync.Pool{
+		New: func() interface{} { return make(map[string]interface{}) },
+	}
+)
+
+// Batch is a reusable client.BatchPoints: its points slice is drawn
+// from the same pool Insert, InsertMany and BufferedWriter's flush loop
+// already use internally (see newPooledBatchPoints), and NewPoint
+// builds each point from pooled tag/field maps, so a caller flushing
+// many batches in a row can reuse one Batch across flushes instead of
+// paying for a fresh BatchPoints, points slice and tag/field map every
+// round. It is not safe for concurrent use.
+type Batch struct {
+	bp      *pooledBatch
+	release func()
+}
+
+// NewBatch returns an empty Batch configured per cfg.
+func NewBatch(cfg client.BatchPointsConfig) (*Batch, error) {
+	bp, release, err := newPooledBatchPoints(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Batch{bp: bp.(*pooledBatch), release: release}, nil
+}
+
+// AddPoint appends an already-built point to b, the same as
+// client.BatchPoints.AddPoint.
+func (b *Batch) AddPoint(p *client.Point) {
+	b.bp.AddPoint(p)
+}
+
+// AddPoints appends already-built points to b, the same as
+// client.BatchPoints.AddPoints.
+func (b *Batch) AddPoints(ps []*client.Point) {
+	b.bp.AddPoints(ps)
+}
+
+// NewPoint builds a point from name, tags and fields and appends it to
+// b, the same as calling client.NewPoint and AddPoint, except tags and
+// fields are returned to Batch's internal map pool once the point is
+// built instead of left for the GC. Pass maps obtained from b.Tags()/
+// b.Fields() to avoid allocating them in the first place; a literal map
+// works too, it just isn't pooled on the way in, only on the way out.
+func (b *Batch) NewPoint(name string, tags map[string]string, fields map[string]interface{}, t time.Time) error {
+	p, err := client.NewPoint(name, tags, fields, t)
+	putTags(tags)
+	putFields(fields)
+	if err != nil {
+		return err
+	}
+	b.bp.AddPoint(p)
+	return nil
+}
+
+// Tags returns an empty map drawn from the pool NewPoint returns tags
+// to, for a caller building a point's tag set field by field instead of
+// a map literal, so the allocation is reused across flushes the same
+// way NewPoint's own maps are.
+func (b *Batch) Tags() map[string]string {
+	return tagsPool.Get().(map[string]string)
+}
+
+// Fields is Tags for a point's field map.
+func (b *Batch) Fields() map[string]interface{} {
+	return fieldsPool.Get().(map[string]interface{})
+}
+
+// putTags clears m and returns it to tagsPool.
+func putTags(m map[string]string) {
+	for k := range m {
+		delete(m, k)
+	}
+	tagsPool.Put(m)
+}
+
+// putFields clears m and returns it to fieldsPool.
+func putFields(m map[string]interface{}) {
+	for k := range m {
+		delete(m, k)
+	}
+	fieldsPool.Put(m)
+}
+
+// Points returns b's currently buffered points, the same as
+// client.BatchPoints.Points.
+func (b *Batch) Points() []*client.Point {
+	return b.bp.Points()
+}
+
+// BatchPoints returns b as a client.BatchPoints, for passing to
+// WriteBatchPointsContext or a client.Client's own Write/WriteCtx.
+func (b *Batch) BatchPoints() client.BatchPoints {
+	return b.bp
+}
+
+// Reset clears b's buffered points, keeping the underlying slice's
+// capacity, and reconfigures it per cfg, so b can be filled and flushed
+// again without a fresh allocation.
+func (b *Batch) Reset(cfg client.BatchPointsConfig) error {
+	return b.bp.reset(cfg)
+}
+
+// Release returns b's backing storage to the shared pool; b must not be
+// used again afterward. Call it once b's batch has been written and its
+// points are no longer needed, mirroring the release func
+// newPooledBatchPoints hands its internal callers.
+func (b *Batch) Release() {
+	b.release()
+}
@@ -0,0 +1,66 @@
+package influx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// TestBatchNewPointReusesMaps confirms NewPoint returns its tags/fields
+// maps to the pool cleared, by checking a map handed out by a later
+// Tags() call is empty.
+func TestBatchNewPointReusesMaps(t *testing.T) {
+	b, err := NewBatch(client.BatchPointsConfig{Database: "mydb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tags := b.Tags()
+	tags["host"] = "a"
+	fields := b.Fields()
+	fields["value"] = 1.5
+
+	if err := b.NewPoint("cpu", tags, fields, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Points()) != 1 {
+		t.Fatalf("len(Points()) = %d, want 1", len(b.Points()))
+	}
+
+	reused := b.Tags()
+	if len(reused) != 0 {
+		t.Fatalf("Tags() returned a dirty map: %v", reused)
+	}
+}
+
+// TestBatchResetClearsPoints confirms Reset empties b's points while
+// keeping it usable for another round of AddPoint calls.
+func TestBatchResetClearsPoints(t *testing.T) {
+	b, err := NewBatch(client.BatchPointsConfig{Database: "mydb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := client.NewPoint("cpu", nil, map[string]interface{}{"value": 1.0}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.AddPoint(p)
+	if len(b.Points()) != 1 {
+		t.Fatalf("len(Points()) = %d, want 1", len(b.Points()))
+	}
+
+	if err := b.Reset(client.BatchPointsConfig{Database: "mydb"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Points()) != 0 {
+		t.Fatalf("len(Points()) after Reset = %d, want 0", len(b.Points()))
+	}
+
+	b.AddPoint(p)
+	if len(b.Points()) != 1 {
+		t.Fatalf("len(Points()) after reuse = %d, want 1", len(b.Points()))
+	}
+	b.Release()
+}
@@ -0,0 +1,74 @@
+package influx
+
+import (
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// batchPool recycles pooledBatch values so Insert, InsertRP,
+// InsertMany and BufferedWriter's flush loop, which each build one
+// BatchPoints per call, don't allocate a fresh points slice on every
+// write under high write rates. client.NewBatchPoints returns an
+// unexported type with no reset hook, so a pool can't reuse its
+// allocations directly. Batch exposes this same pool to callers
+// outside the package.
+var batchPool = sync.Pool{
+	New: func() interface{} { return new(pooledBatch) },
+}
+
+// pooledBatch is a client.BatchPoints implementation whose points slice
+// is reused across newPooledBatchPoints calls.
+type pooledBatch struct {
+	points           []*client.Point
+	database         string
+	precision        string
+	retentionPolicy  string
+	writeConsistency string
+}
+
+func (b *pooledBatch) AddPoint(p *client.Point)     { b.points = append(b.points, p) }
+func (b *pooledBatch) AddPoints(ps []*client.Point) { b.points = append(b.points, ps...) }
+func (b *pooledBatch) Points() []*client.Point      { return b.points }
+func (b *pooledBatch) Precision() string            { return b.precision }
+func (b *pooledBatch) SetPrecision(s string) error  { b.precision = s; return nil }
+func (b *pooledBatch) Database() string             { return b.database }
+func (b *pooledBatch) SetDatabase(s string)         { b.database = s }
+func (b *pooledBatch) WriteConsistency() string     { return b.writeConsistency }
+func (b *pooledBatch) SetWriteConsistency(s string) { b.writeConsistency = s }
+func (b *pooledBatch) RetentionPolicy() string      { return b.retentionPolicy }
+func (b *pooledBatch) SetRetentionPolicy(s string)  { b.retentionPolicy = s }
+
+func (b *pooledBatch) reset(cfg client.BatchPointsConfig) error {
+	precision := cfg.Precision
+	if precision == "" {
+		precision = "ns"
+	}
+	if _, err := time.ParseDuration("1" + precision); err != nil {
+		return err
+	}
+
+	b.points = b.points[:0]
+	b.database = cfg.Database
+	b.precision = precision
+	b.retentionPolicy = cfg.RetentionPolicy
+	b.writeConsistency = cfg.WriteConsistency
+	return nil
+}
+
+// newPooledBatchPoints returns a BatchPoints configured per cfg backed
+// by a pooled pooledBatch, and a release func that must be called once
+// the batch is no longer needed (after WriteBatchPointsContext returns)
+// to return it to the pool. release must not be called, and the result
+// must not be pooled, for a BatchPoints that escapes to a caller:
+// WriteBatchPointsContext runs entirely synchronously, including any
+// spooling or interceptors, so every internal caller here is safe.
+func newPooledBatchPoints(cfg client.BatchPointsConfig) (client.BatchPoints, func(), error) {
+	b := batchPool.Get().(*pooledBatch)
+	if err := b.reset(cfg); err != nil {
+		batchPool.Put(b)
+		return nil, nil, err
+	}
+	return b, func() { batchPool.Put(b) }, nil
+}
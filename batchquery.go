@@ -0,0 +1,36 @@
+package influx
+
+import (
+	"context"
+	"strings"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// QueryBatch runs cmds against db as a single semicolon-joined request,
+// one round trip instead of one per statement, and returns each
+// statement's client.Result in order — pass the result straight to
+// ParseInto to decode every statement's series into its own
+// destination. The returned error is only set on a transport-level
+// failure (the request itself failing); a single statement's own
+// failure is reported in its Result.Err instead, exactly as Query does
+// for a semicolon-joined cmd.
+func (c *Client) QueryBatch(db string, cmds ...string) ([]client.Result, error) {
+	return c.QueryBatchContext(context.Background(), db, cmds...)
+}
+
+// QueryBatchContext is QueryBatch with a context that aborts the
+// request as soon as it is canceled.
+func (c *Client) QueryBatchContext(ctx context.Context, db string, cmds ...string) ([]client.Result, error) {
+	return c.QueryContext(ctx, db, strings.Join(cmds, "; "))
+}
+
+// QueryBatch runs QueryBatch using the default Client.
+func QueryBatch(db string, cmds ...string) ([]client.Result, error) {
+	return gClient().QueryBatch(db, cmds...)
+}
+
+// QueryBatchContext runs QueryBatchContext using the default Client.
+func QueryBatchContext(ctx context.Context, db string, cmds ...string) ([]client.Result, error) {
+	return gClient().QueryBatchContext(ctx, db, cmds...)
+}
@@ -0,0 +1,133 @@
+package influx
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by operations on a Client whose circuit
+// breaker is open, instead of attempting the request against a cluster
+// already known to be down.
+var ErrCircuitOpen = errors.New("influx: circuit breaker open")
+
+// breakerState is a circuit breaker's current phase.
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// CircuitBreakerConfig configures CircuitBreaker.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that trips
+	// the breaker open.
+	FailureThreshold int
+	// CoolDown is how long the breaker stays open before allowing a
+	// single trial request through (half-open).
+	CoolDown time.Duration
+	// OnStateChange, if set, is called whenever the breaker transitions
+	// between closed, open and half-open.
+	OnStateChange func(from, to string)
+}
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// circuitBreaker protects Query/Insert from piling up latency against a
+// cluster that is already down: after FailureThreshold consecutive
+// failures it fails fast for CoolDown before letting one trial request
+// through to probe recovery.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	cfg       CircuitBreakerConfig
+	state     breakerState
+	failures  int
+	reopensAt time.Time
+}
+
+func newCircuitBreaker(cfg CircuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{cfg: cfg}
+}
+
+// allow reports whether a request may proceed, transitioning an open
+// breaker to half-open once CoolDown has elapsed.
+func (cb *circuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == breakerOpen {
+		if clockNow().Before(cb.reopensAt) {
+			return false
+		}
+		cb.setState(breakerHalfOpen)
+	}
+	return true
+}
+
+// record updates the breaker with the outcome of a request let through
+// by allow.
+func (cb *circuitBreaker) record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if err == nil {
+		cb.failures = 0
+		cb.setState(breakerClosed)
+		return
+	}
+
+	cb.failures++
+	if cb.state == breakerHalfOpen || cb.failures >= cb.cfg.FailureThreshold {
+		cb.reopensAt = clockNow().Add(cb.cfg.CoolDown)
+		cb.setState(breakerOpen)
+	}
+}
+
+func (cb *circuitBreaker) setState(to breakerState) {
+	if cb.state == to {
+		return
+	}
+	from := cb.state
+	cb.state = to
+	if cb.cfg.OnStateChange != nil {
+		cb.cfg.OnStateChange(from.String(), to.String())
+	}
+}
+
+// CircuitBreaker wraps c's queries and writes with a circuit breaker
+// per cfg: after cfg.FailureThreshold consecutive failures, further
+// calls fail fast with ErrCircuitOpen for cfg.CoolDown instead of
+// adding latency against a cluster that is already down. Call with a
+// zero CircuitBreakerConfig to remove a previously set breaker.
+func (c *Client) CircuitBreaker(cfg CircuitBreakerConfig) {
+	if cfg.FailureThreshold <= 0 {
+		c.breaker = nil
+		return
+	}
+	c.breaker = newCircuitBreaker(cfg)
+}
+
+// guard runs op if the breaker (if any) allows it, recording the
+// outcome so repeated failures trip the breaker open.
+func (c *Client) guard(op func() error) error {
+	if c.breaker == nil {
+		return op()
+	}
+	if !c.breaker.allow() {
+		return ErrCircuitOpen
+	}
+	err := op()
+	c.breaker.record(err)
+	return err
+}
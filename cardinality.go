@@ -0,0 +1,111 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+)
+
+// ShowSeriesCardinality runs SHOW SERIES CARDINALITY on db, InfluxDB's
+// sketch-based (HyperLogLog) estimate of the database's series count,
+// cheap enough to poll from a capacity dashboard. Use
+// ShowSeriesExactCardinality for the true count instead.
+func (c *Client) ShowSeriesCardinality(db string) (int, error) {
+	return c.ShowSeriesCardinalityContext(context.Background(), db)
+}
+
+// ShowSeriesCardinalityContext is ShowSeriesCardinality with a context
+// that aborts the request as soon as it is canceled.
+func (c *Client) ShowSeriesCardinalityContext(ctx context.Context, db string) (int, error) {
+	cmd := "SHOW SERIES CARDINALITY ON " + Ident(db)
+	return c.queryCardinality(ctx, db, cmd)
+}
+
+// ShowSeriesExactCardinality runs SHOW SERIES EXACT CARDINALITY on db,
+// the database's true series count, walking every series instead of
+// estimating it — far more expensive than ShowSeriesCardinality on a
+// database with many series.
+func (c *Client) ShowSeriesExactCardinality(db string) (int, error) {
+	return c.ShowSeriesExactCardinalityContext(context.Background(), db)
+}
+
+// ShowSeriesExactCardinalityContext is ShowSeriesExactCardinality with
+// a context that aborts the request as soon as it is canceled.
+func (c *Client) ShowSeriesExactCardinalityContext(ctx context.Context, db string) (int, error) {
+	cmd := "SHOW SERIES EXACT CARDINALITY ON " + Ident(db)
+	return c.queryCardinality(ctx, db, cmd)
+}
+
+// ShowTagValuesCardinality runs SHOW TAG VALUES CARDINALITY for
+// measurement's key on db, InfluxDB's sketch-based estimate of that
+// tag's distinct value count. Use ShowTagValuesExactCardinality for the
+// true count instead.
+func (c *Client) ShowTagValuesCardinality(db, measurement, key string) (int, error) {
+	return c.ShowTagValuesCardinalityContext(context.Background(), db, measurement, key)
+}
+
+// ShowTagValuesCardinalityContext is ShowTagValuesCardinality with a
+// context that aborts the request as soon as it is canceled.
+func (c *Client) ShowTagValuesCardinalityContext(ctx context.Context, db, measurement, key string) (int, error) {
+	cmd := fmt.Sprintf("SHOW TAG VALUES CARDINALITY ON %s FROM %s WITH KEY = %s", Ident(db), Ident(measurement), Ident(key))
+	return c.queryCardinality(ctx, db, cmd)
+}
+
+// ShowTagValuesExactCardinality runs SHOW TAG VALUES EXACT CARDINALITY
+// for measurement's key on db, that tag's true distinct value count.
+func (c *Client) ShowTagValuesExactCardinality(db, measurement, key string) (int, error) {
+	return c.ShowTagValuesExactCardinalityContext(context.Background(), db, measurement, key)
+}
+
+// ShowTagValuesExactCardinalityContext is ShowTagValuesExactCardinality
+// with a context that aborts the request as soon as it is canceled.
+func (c *Client) ShowTagValuesExactCardinalityContext(ctx context.Context, db, measurement, key string) (int, error) {
+	cmd := fmt.Sprintf("SHOW TAG VALUES EXACT CARDINALITY ON %s FROM %s WITH KEY = %s", Ident(db), Ident(measurement), Ident(key))
+	return c.queryCardinality(ctx, db, cmd)
+}
+
+// queryCardinality runs cmd, a SHOW ... CARDINALITY statement, and sums
+// its "count" column across every series, the shape every cardinality
+// variant's response shares.
+func (c *Client) queryCardinality(ctx context.Context, db, cmd string) (int, error) {
+	results, err := c.QueryContext(ctx, db, cmd)
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	for _, result := range results {
+		for _, serie := range result.Series {
+			var counts []int
+			if err := ParseResult(&counts, serie, "count"); err != nil {
+				return 0, err
+			}
+			for _, count := range counts {
+				n += count
+			}
+		}
+	}
+	return n, nil
+}
+
+// ShowSeriesCardinality estimates db's series count using the default
+// Client.
+func ShowSeriesCardinality(db string) (int, error) {
+	return gClient().ShowSeriesCardinality(db)
+}
+
+// ShowSeriesExactCardinality counts db's series exactly using the
+// default Client.
+func ShowSeriesExactCardinality(db string) (int, error) {
+	return gClient().ShowSeriesExactCardinality(db)
+}
+
+// ShowTagValuesCardinality estimates a tag's distinct value count using
+// the default Client.
+func ShowTagValuesCardinality(db, measurement, key string) (int, error) {
+	return gClient().ShowTagValuesCardinality(db, measurement, key)
+}
+
+// ShowTagValuesExactCardinality counts a tag's distinct values exactly
+// using the default Client.
+func ShowTagValuesExactCardinality(db, measurement, key string) (int, error) {
+	return gClient().ShowTagValuesExactCardinality(db, measurement, key)
+}
@@ -0,0 +1,167 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TagCombinationCardinality is the number of distinct value pairs a
+// pair of tag keys took on together, across a measurement's series, as
+// found by AnalyzeCardinality.
+type TagCombinationCardinality struct {
+	Keys  []string
+	Count int
+}
+
+// TagCardinalityReport is AnalyzeCardinality's result: measurement's
+// per-tag-key value counts, plus its highest-cardinality tag key
+// combinations, both useful for spotting which tag (or pair of tags) is
+// about to blow up a database's series count before it does.
+type TagCardinalityReport struct {
+	Measurement string
+	// TagCardinality is each tag key's distinct value count, as
+	// reported by SHOW TAG VALUES CARDINALITY.
+	TagCardinality map[string]int
+	// TopCombinations is the topN highest-cardinality 2-key tag
+	// combinations, sorted descending by Count.
+	TopCombinations []TagCombinationCardinality
+}
+
+// AnalyzeCardinality reports measurement's per-tag-key value counts and
+// its topN highest-cardinality tag key combinations on db.
+func (c *Client) AnalyzeCardinality(db, measurement string, topN int) (TagCardinalityReport, error) {
+	return c.AnalyzeCardinalityContext(context.Background(), db, measurement, topN)
+}
+
+// AnalyzeCardinalityContext is AnalyzeCardinality with a context that
+// aborts the request as soon as it is canceled.
+//
+// InfluxDB's own SHOW ... CARDINALITY commands only ever report a
+// single tag key's cardinality, with no way to ask for a combination's
+// cardinality directly, so TopCombinations is computed by walking every
+// series key from SHOW SERIES instead, which is far more expensive than
+// the single-key counts above it — expected, since it's the only way to
+// answer the "which pair of tags is exploding series count together"
+// question InfluxDB itself can't.
+func (c *Client) AnalyzeCardinalityContext(ctx context.Context, db, measurement string, topN int) (TagCardinalityReport, error) {
+	report := TagCardinalityReport{
+		Measurement:    measurement,
+		TagCardinality: make(map[string]int),
+	}
+
+	keys, err := c.ShowTagKeysContext(ctx, db, measurement)
+	if err != nil {
+		return TagCardinalityReport{}, err
+	}
+	for _, key := range keys {
+		n, err := c.ShowTagValuesCardinalityContext(ctx, db, measurement, key)
+		if err != nil {
+			return TagCardinalityReport{}, err
+		}
+		report.TagCardinality[key] = n
+	}
+
+	seriesKeys, err := c.showSeriesKeys(ctx, db, measurement)
+	if err != nil {
+		return TagCardinalityReport{}, err
+	}
+	report.TopCombinations = topTagCombinations(seriesKeys, topN)
+
+	return report, nil
+}
+
+// showSeriesKeys runs SHOW SERIES FROM measurement on db and returns
+// every series key, e.g. "cpu,host=a,region=us".
+func (c *Client) showSeriesKeys(ctx context.Context, db, measurement string) ([]string, error) {
+	cmd := fmt.Sprintf("SHOW SERIES ON %s FROM %s", Ident(db), Ident(measurement))
+	results, err := c.QueryContext(ctx, db, cmd)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, result := range results {
+		for _, serie := range result.Series {
+			var rows []string
+			if err := ParseResult(&rows, serie, "key"); err != nil {
+				return nil, err
+			}
+			keys = append(keys, rows...)
+		}
+	}
+	return keys, nil
+}
+
+// parseSeriesKeyTags splits a series key, e.g. "cpu,host=a,region=us",
+// into its tag map, ignoring the leading measurement-name segment.
+func parseSeriesKeyTags(key string) map[string]string {
+	parts := strings.Split(key, ",")
+	if len(parts) <= 1 {
+		return nil
+	}
+	tags := make(map[string]string, len(parts)-1)
+	for _, part := range parts[1:] {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		tags[kv[0]] = kv[1]
+	}
+	return tags
+}
+
+// topTagCombinations counts, for every pair of tag keys appearing
+// together across seriesKeys, how many distinct value pairs they took
+// on, and returns the topN pairs sorted descending by that count.
+func topTagCombinations(seriesKeys []string, topN int) []TagCombinationCardinality {
+	seriesTags := make([]map[string]string, 0, len(seriesKeys))
+	keySet := make(map[string]bool)
+	for _, sk := range seriesKeys {
+		tags := parseSeriesKeyTags(sk)
+		seriesTags = append(seriesTags, tags)
+		for k := range tags {
+			keySet[k] = true
+		}
+	}
+
+	keys := make([]string, 0, len(keySet))
+	for k := range keySet {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var combos []TagCombinationCardinality
+	for i := 0; i < len(keys); i++ {
+		for j := i + 1; j < len(keys); j++ {
+			values := make(map[string]bool)
+			for _, tags := range seriesTags {
+				v1, ok1 := tags[keys[i]]
+				v2, ok2 := tags[keys[j]]
+				if !ok1 || !ok2 {
+					continue
+				}
+				values[v1+"\x00"+v2] = true
+			}
+			if len(values) == 0 {
+				continue
+			}
+			combos = append(combos, TagCombinationCardinality{
+				Keys:  []string{keys[i], keys[j]},
+				Count: len(values),
+			})
+		}
+	}
+
+	sort.Slice(combos, func(i, j int) bool { return combos[i].Count > combos[j].Count })
+	if topN >= 0 && len(combos) > topN {
+		combos = combos[:topN]
+	}
+	return combos
+}
+
+// AnalyzeCardinality reports measurement's tag cardinality on db using
+// the default Client.
+func AnalyzeCardinality(db, measurement string, topN int) (TagCardinalityReport, error) {
+	return gClient().AnalyzeCardinality(db, measurement, topN)
+}
@@ -0,0 +1,164 @@
+package influx
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// TestParseSeriesKeyTags confirms a series key is split into its tag
+// map, ignoring the leading measurement-name segment.
+func TestParseSeriesKeyTags(t *testing.T) {
+	got := parseSeriesKeyTags("cpu,host=a,region=us")
+	want := map[string]string{"host": "a", "region": "us"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseSeriesKeyTags = %v, want %v", got, want)
+	}
+}
+
+// TestParseSeriesKeyTagsNoTags confirms a series key with no tags (just
+// the measurement name) yields no tags.
+func TestParseSeriesKeyTagsNoTags(t *testing.T) {
+	if got := parseSeriesKeyTags("cpu"); got != nil {
+		t.Fatalf("parseSeriesKeyTags = %v, want nil", got)
+	}
+}
+
+// TestTopTagCombinationsCountsDistinctPairs confirms the pairwise
+// combination counter only counts pairs actually co-occurring, and
+// sorts descending by count.
+func TestTopTagCombinationsCountsDistinctPairs(t *testing.T) {
+	seriesKeys := []string{
+		"cpu,host=a,region=us,env=prod",
+		"cpu,host=b,region=us,env=prod",
+		"cpu,host=c,region=eu,env=prod",
+		"cpu,region=us", // missing host: skipped for the host,region pair
+	}
+
+	combos := topTagCombinations(seriesKeys, 10)
+	if len(combos) == 0 {
+		t.Fatal("combos is empty")
+	}
+
+	byKeys := make(map[string]int)
+	for _, c := range combos {
+		byKeys[strings.Join(c.Keys, "+")] = c.Count
+	}
+	if byKeys["env+host"] != 3 {
+		t.Fatalf("env+host = %d, want 3", byKeys["env+host"])
+	}
+	if byKeys["host+region"] != 3 {
+		t.Fatalf("host+region = %d, want 3", byKeys["host+region"])
+	}
+	if byKeys["env+region"] != 2 {
+		t.Fatalf("env+region = %d, want 2", byKeys["env+region"])
+	}
+}
+
+// TestTopTagCombinationsTruncatesToTopN confirms the result is
+// truncated to topN.
+func TestTopTagCombinationsTruncatesToTopN(t *testing.T) {
+	seriesKeys := []string{
+		"cpu,a=1,b=1,c=1",
+		"cpu,a=2,b=2,c=2",
+	}
+	combos := topTagCombinations(seriesKeys, 1)
+	if len(combos) != 1 {
+		t.Fatalf("len(combos) = %d, want 1", len(combos))
+	}
+}
+
+// TestAnalyzeCardinalityContext drives AnalyzeCardinalityContext against
+// an httptest server standing in for a SHOW TAG KEYS / SHOW TAG VALUES
+// CARDINALITY / SHOW SERIES sequence.
+func TestAnalyzeCardinalityContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cmd := r.URL.Query().Get("q")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasPrefix(cmd, "SHOW TAG KEYS"):
+			json.NewEncoder(w).Encode(seriesResponse("cpu", []string{"tagKey"}, [][]interface{}{{"host"}, {"region"}}))
+		case strings.HasPrefix(cmd, "SHOW TAG VALUES CARDINALITY") && strings.Contains(cmd, `"host"`):
+			json.NewEncoder(w).Encode(seriesResponse("cpu", []string{"count"}, [][]interface{}{{float64(2)}}))
+		case strings.HasPrefix(cmd, "SHOW TAG VALUES CARDINALITY") && strings.Contains(cmd, `"region"`):
+			json.NewEncoder(w).Encode(seriesResponse("cpu", []string{"count"}, [][]interface{}{{float64(2)}}))
+		case strings.HasPrefix(cmd, "SHOW SERIES"):
+			json.NewEncoder(w).Encode(seriesResponse("cpu", []string{"key"}, [][]interface{}{
+				{"cpu,host=a,region=us"},
+				{"cpu,host=b,region=us"},
+			}))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := c.AnalyzeCardinality("mydb", "cpu", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.TagCardinality["host"] != 2 || report.TagCardinality["region"] != 2 {
+		t.Fatalf("TagCardinality = %v", report.TagCardinality)
+	}
+	if len(report.TopCombinations) != 1 || report.TopCombinations[0].Count != 2 {
+		t.Fatalf("TopCombinations = %v", report.TopCombinations)
+	}
+}
+
+// seriesResponse builds the raw JSON shape the InfluxDB HTTP API uses
+// for /query, for one series.
+func seriesResponse(name string, columns []string, values [][]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"results": []interface{}{
+			map[string]interface{}{
+				"series": []interface{}{
+					map[string]interface{}{
+						"name":    name,
+						"columns": columns,
+						"values":  values,
+					},
+				},
+			},
+		},
+	}
+}
+
+// TestDenyTagsRejectsDeniedTag confirms a point carrying a denied tag
+// key is rejected with an error, not silently dropped.
+func TestDenyTagsRejectsDeniedTag(t *testing.T) {
+	mw := DenyTags("request_id")
+	p, err := client.NewPoint("cpu", map[string]string{"request_id": "abc"}, map[string]interface{}{"value": 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mw(p); err == nil {
+		t.Fatal("want error")
+	}
+}
+
+// TestDenyTagsAllowsUndeniedTag confirms a point without a denied tag
+// passes through unchanged.
+func TestDenyTagsAllowsUndeniedTag(t *testing.T) {
+	mw := DenyTags("request_id")
+	p, err := client.NewPoint("cpu", map[string]string{"host": "a"}, map[string]interface{}{"value": 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := mw(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != p {
+		t.Fatal("point was replaced")
+	}
+}
@@ -0,0 +1,25 @@
+package influx
+
+import "sync/atomic"
+
+// caseInsensitiveColumns controls whether resolveRowSlot matches a
+// column or tag name to a struct field case-insensitively when no
+// exact (or snake_case alias) match is found, set by
+// SetCaseInsensitiveColumns. Off by default.
+var caseInsensitiveColumns int32
+
+// SetCaseInsensitiveColumns toggles case- and underscore-insensitive
+// column/tag matching for ParseResult from here on: with it on, a
+// column like "VALUE", or a SELECT ... AS alias whose case and
+// underscores don't line up with its field's (e.g. AS AVGusage for a
+// field tagged "avg_usage"), still matches instead of silently leaving
+// that field unset. Off by default since a mismatch like that is
+// usually an inf tag or alias typo worth surfacing — especially under
+// SetStrictDecoding — rather than papering over.
+func SetCaseInsensitiveColumns(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&caseInsensitiveColumns, 1)
+	} else {
+		atomic.StoreInt32(&caseInsensitiveColumns, 0)
+	}
+}
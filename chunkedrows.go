@@ -0,0 +1,178 @@
+package influx
+
+import (
+	"context"
+	"io"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// ChunkedRows iterates the rows of a chunked query one chunk at a time,
+// the streaming counterpart to Rows: only the current chunk's rows are
+// ever held in memory, so a query returning hundreds of millions of
+// rows can be processed with bounded memory instead of decoding the
+// whole result set (what Query/ParseResults would otherwise require)
+// at once.
+type ChunkedRows struct {
+	cr   *client.ChunkedResponse
+	rows *Rows
+
+	err    error
+	closed bool
+}
+
+const (
+	// DefaultChunkSize is the chunk size InfluxDB itself falls back to
+	// when QueryChunked/QueryChunkedContext is called with chunkSize <=
+	// 0, documented here so ChunkSizeForRowWidth has a floor to compare
+	// against.
+	DefaultChunkSize = 10000
+
+	// targetChunkBytes is the approximate per-chunk response size
+	// ChunkSizeForRowWidth aims for: large enough to amortize the
+	// per-chunk HTTP round trip, small enough that a chunk's rows
+	// decoded into Go values don't themselves become a memory spike.
+	targetChunkBytes = 4 << 20 // 4MiB
+
+	minChunkSizeHint = 100
+	maxChunkSizeHint = 200000
+)
+
+// ChunkSizeForRowWidth returns a heuristic chunk size for QueryChunked:
+// enough rows to fill roughly 4MiB per chunk assuming each row encodes
+// to about rowWidthBytes bytes, clamped to a sane range. It lets a
+// caller who knows their schema's rough row width (a handful of
+// float64 fields versus a row with a dozen string tags) size chunks
+// from that instead of guessing a row count outright, trading more
+// round trips for lower peak memory or vice versa. rowWidthBytes <= 0
+// falls back to DefaultChunkSize.
+func ChunkSizeForRowWidth(rowWidthBytes int) int {
+	if rowWidthBytes <= 0 {
+		return DefaultChunkSize
+	}
+	n := targetChunkBytes / rowWidthBytes
+	switch {
+	case n < minChunkSizeHint:
+		return minChunkSizeHint
+	case n > maxChunkSizeHint:
+		return maxChunkSizeHint
+	default:
+		return n
+	}
+}
+
+// QueryChunked runs cmd against db using InfluxDB's HTTP chunked
+// response transfer encoding, fetching chunkSize rows per chunk
+// (InfluxDB's own default if chunkSize <= 0) instead of the whole
+// result set in one response.
+func (c *Client) QueryChunked(db, cmd string, chunkSize int) (*ChunkedRows, error) {
+	return c.QueryChunkedContext(context.Background(), db, cmd, chunkSize)
+}
+
+// QueryChunkedContext is QueryChunked with a context that aborts the
+// request as soon as it is canceled.
+func (c *Client) QueryChunkedContext(ctx context.Context, db, cmd string, chunkSize int) (*ChunkedRows, error) {
+	db, _ = c.resolveTenant(ctx, db)
+	q := client.Query{
+		Command:   cmd,
+		Database:  db,
+		Chunked:   true,
+		ChunkSize: chunkSize,
+	}
+	replica, idx := c.nextReplica()
+	start := clockNow()
+	cr, err := replica.QueryAsChunk(q)
+	c.recordReplicaLatency(idx, clockNow().Sub(start))
+	if err != nil {
+		return nil, err
+	}
+	return &ChunkedRows{cr: cr}, nil
+}
+
+// QueryChunked runs cmd against db as a chunked query, using the
+// default Client.
+func QueryChunked(db, cmd string, chunkSize int) (*ChunkedRows, error) {
+	return gClient().QueryChunked(db, cmd, chunkSize)
+}
+
+// QueryChunkedContext is QueryChunked with a context, using the
+// default Client.
+func QueryChunkedContext(ctx context.Context, db, cmd string, chunkSize int) (*ChunkedRows, error) {
+	return gClient().QueryChunkedContext(ctx, db, cmd, chunkSize)
+}
+
+// Next advances to the next row, transparently fetching and switching
+// to the next chunk once the current one is exhausted, and returns
+// false once the stream itself is exhausted, Close has been called, or
+// a terminal error is set (see Err). Call Scan or ScanRow to read the
+// row Next just advanced to.
+func (cr *ChunkedRows) Next() bool {
+	if cr.closed || cr.err != nil {
+		return false
+	}
+	for {
+		if cr.rows != nil {
+			if cr.rows.Next() {
+				return true
+			}
+			if err := cr.rows.Err(); err != nil {
+				cr.err = err
+				return false
+			}
+		}
+
+		response, err := cr.cr.NextResponse()
+		if err != nil {
+			if err != io.EOF {
+				cr.err = err
+			}
+			return false
+		}
+		cr.rows = RowsFromResults(response.Results)
+	}
+}
+
+// Scan decodes the current row the same way Rows.Scan does.
+func (cr *ChunkedRows) Scan(dst interface{}) error {
+	return cr.rows.Scan(dst)
+}
+
+// ScanRow positionally scans the current row's values into dsts the
+// same way Rows.ScanRow does.
+func (cr *ChunkedRows) ScanRow(dsts ...interface{}) error {
+	return cr.rows.ScanRow(dsts...)
+}
+
+// Name returns the current row's series name, the same as Rows.Name.
+func (cr *ChunkedRows) Name() string {
+	return cr.rows.Name()
+}
+
+// Tags returns the current row's series tag set, the same as Rows.Tags.
+func (cr *ChunkedRows) Tags() map[string]string {
+	return cr.rows.Tags()
+}
+
+// Columns returns the current row's series columns, the same as
+// Rows.Columns.
+func (cr *ChunkedRows) Columns() []string {
+	return cr.rows.Columns()
+}
+
+// Values returns the current row's raw values, the same as Rows.Values.
+func (cr *ChunkedRows) Values() []interface{} {
+	return cr.rows.Values()
+}
+
+// Err returns the first terminal error encountered fetching a chunk or
+// iterating its rows, or nil if iteration simply ran out of chunks.
+func (cr *ChunkedRows) Err() error {
+	return cr.err
+}
+
+// Close ends iteration early and releases the underlying HTTP
+// response's connection: after Close, Next always returns false.
+func (cr *ChunkedRows) Close() error {
+	cr.closed = true
+	return cr.cr.Close()
+}
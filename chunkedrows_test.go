@@ -0,0 +1,99 @@
+package influx
+
+import (
+	"context"
+	"testing"
+)
+
+// TestChunkedRowsScansAcrossChunks confirms ChunkedRows.Next/Scan walk
+// every row of a chunked query across more than one chunk, the same
+// multi-chunk case TestQueryIterDecodesRowsAcrossChunks exercises for
+// RowIter, here against ChunkedRows/Rows directly.
+func TestChunkedRowsScansAcrossChunks(t *testing.T) {
+	chunks := `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["host","usage"],"values":[["a",0.5]]}]}]}` +
+		`{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["host","usage"],"values":[["b",0.75]]}]}]}`
+
+	setGClient(NewWithClient(&chunkedFakeClient{chunks: chunks}))
+	defer setGClient(nil)
+
+	cr, err := QueryChunkedContext(context.Background(), "db", "SELECT * FROM cpu", 1)
+	if err != nil {
+		t.Fatalf("QueryChunked: %v", err)
+	}
+	defer cr.Close()
+
+	var got []cpuRow
+	for cr.Next() {
+		var row cpuRow
+		if err := cr.Scan(&row); err != nil {
+			t.Fatalf("Scan: %v", err)
+		}
+		got = append(got, row)
+	}
+	if err := cr.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+
+	if len(got) != 2 || got[0].Host != "a" || got[0].Usage != 0.5 ||
+		got[1].Host != "b" || got[1].Usage != 0.75 {
+		t.Fatalf("got = %+v, want [{a 0.5} {b 0.75}]", got)
+	}
+}
+
+// TestChunkedRowsSurfacesResultError confirms a Result.Err arriving
+// mid-stream (a statement failing partway through a chunked query)
+// stops iteration and surfaces through Err, the same as Rows already
+// does for a single, unchunked response.
+func TestChunkedRowsSurfacesResultError(t *testing.T) {
+	chunks := `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["host","usage"],"values":[["a",0.5]]}]}]}` +
+		`{"results":[{"statement_id":0,"error":"engine: boom"}]}`
+
+	setGClient(NewWithClient(&chunkedFakeClient{chunks: chunks}))
+	defer setGClient(nil)
+
+	cr, err := QueryChunkedContext(context.Background(), "db", "SELECT * FROM cpu", 1)
+	if err != nil {
+		t.Fatalf("QueryChunked: %v", err)
+	}
+	defer cr.Close()
+
+	var got []cpuRow
+	for cr.Next() {
+		var row cpuRow
+		if err := cr.Scan(&row); err != nil {
+			t.Fatalf("Scan: %v", err)
+		}
+		got = append(got, row)
+	}
+	if len(got) != 1 || got[0].Host != "a" {
+		t.Fatalf("got = %+v, want [{a 0.5}]", got)
+	}
+	if err := cr.Err(); err == nil || err.Error() != "engine: boom" {
+		t.Fatalf("Err = %v, want \"engine: boom\"", err)
+	}
+}
+
+// TestChunkedRowsCloseStopsIteration confirms Close ends iteration
+// early, the same guarantee documented on ChunkedRows.Close: Next keeps
+// returning false afterward even if more chunks remain unread.
+func TestChunkedRowsCloseStopsIteration(t *testing.T) {
+	chunks := `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["host","usage"],"values":[["a",0.5]]}]}]}` +
+		`{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["host","usage"],"values":[["b",0.75]]}]}]}`
+
+	c := NewWithClient(&chunkedFakeClient{chunks: chunks})
+
+	cr, err := c.QueryChunkedContext(context.Background(), "db", "SELECT * FROM cpu", 1)
+	if err != nil {
+		t.Fatalf("QueryChunked: %v", err)
+	}
+
+	if !cr.Next() {
+		t.Fatal("Next = false on first row, want true")
+	}
+	if err := cr.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if cr.Next() {
+		t.Fatal("Next = true after Close, want false")
+	}
+}
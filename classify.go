@@ -0,0 +1,118 @@
+package influx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IsRetryable reports whether err is worth retrying: a connection-level
+// failure (the same ones AutoReconnect rebuilds the connection for —
+// timeout, refused, reset, unexpected EOF), InfluxDB's "hinted handoff
+// queue full" write backpressure signal, or a 5xx response — the same
+// signals WriteRetry already retries on internally. It's exported so a
+// caller handling a one-off Insert/Query outside those mechanisms, or
+// one that's exhausted every attempt WriteRetry allowed, can still
+// decide whether retrying again is worth it without matching
+// substrings itself.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if isConnError(err) {
+		return true
+	}
+	if strings.Contains(err.Error(), "hinted handoff queue full") {
+		return true
+	}
+	if code, ok := responseStatusCode(err); ok {
+		return code >= 500 && code < 600
+	}
+	return false
+}
+
+// IsNotFound reports whether err is InfluxDB's "not found" response,
+// e.g. from DropSchema dropping a continuous query or retention
+// policy that doesn't exist.
+func IsNotFound(err error) bool {
+	return notFound(err)
+}
+
+// IsAuthError reports whether err is an authentication or
+// authorization failure: a 401/403 response, or one of InfluxDB's own
+// "authorization failed"/"unable to parse authentication credentials"
+// messages.
+func IsAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if code, ok := responseStatusCode(err); ok && (code == 401 || code == 403) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "authorization failed") ||
+		strings.Contains(msg, "unable to parse authentication credentials")
+}
+
+// IsFieldTypeConflict reports whether err is InfluxDB's "field type
+// conflict" error, returned when a point's field type doesn't match
+// the type already recorded for that field on the measurement —
+// either as a total write failure or, via AsPartialWriteError, the
+// reason on a partial one.
+func IsFieldTypeConflict(err error) bool {
+	if perr, ok := AsPartialWriteError(err); ok {
+		return strings.Contains(perr.Reason, "field type conflict")
+	}
+	return err != nil && strings.Contains(err.Error(), "field type conflict")
+}
+
+// ServerError reports that InfluxDB answered with a non-2xx HTTP
+// status, parsed out of client.Client's flat "received status code N
+// from server" message by AsServerError, the same way AsPartialWriteError
+// parses a partial-write message into a PartialWriteError.
+type ServerError struct {
+	StatusCode int
+}
+
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("influx: server responded with status %d", e.StatusCode)
+}
+
+// AsServerError parses err's embedded HTTP status code into a
+// *ServerError, returning ok=false if err is nil or carries none.
+// client.Client surfaces a non-2xx response as a flat errors.New with
+// no status code attached to the error value itself, so the code is
+// recovered by matching its message the way responseStatusCode
+// already does internally.
+func AsServerError(err error) (serr *ServerError, ok bool) {
+	if err == nil {
+		return nil, false
+	}
+	code, ok := responseStatusCode(err)
+	if !ok {
+		return nil, false
+	}
+	return &ServerError{StatusCode: code}, true
+}
+
+// IsNetworkError reports whether err is a failure of the underlying
+// TCP connection itself (timeout, refused, reset, unexpected EOF)
+// rather than an application-level error InfluxDB returned (bad
+// InfluxQL, 4xx/5xx, a field type conflict) — the same distinction
+// AutoReconnect uses to decide whether rebuilding the connection is
+// worth trying.
+func IsNetworkError(err error) bool {
+	return err != nil && isConnError(err)
+}
+
+// responseStatusCode extracts the HTTP status code client.Client
+// embedded in err's message (see isRetryableQueryError), ok is false
+// if err carries none.
+func responseStatusCode(err error) (code int, ok bool) {
+	m := statusCodePattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0, false
+	}
+	code, convErr := strconv.Atoi(m[1])
+	return code, convErr == nil
+}
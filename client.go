@@ -1,49 +1,1044 @@
 package influx
 
 import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/influxdata/influxdb/client/v2"
 )
 
-var gClient client.Client
+// Client wraps an InfluxDB client together with the database, precision
+// and retention policy used to build the points it writes. Every
+// operation has a Context variant that aborts the in-flight HTTP
+// request as soon as the context is canceled, which the package-level
+// gClient singleton could never do.
+type Client struct {
+	mu  sync.RWMutex
+	cli client.Client
 
-func InitClient(addr string) error {
-	var err error
-	gClient, err = client.NewHTTPClient(client.HTTPConfig{Addr: addr})
-	return err
+	// cfg is the config New built cli from, kept so AutoReconnect can
+	// rebuild cli after a connection-level failure. nil for Clients
+	// built by NewUDP or NewWithReplicas.
+	cfg               *client.HTTPConfig
+	reconnectRetries  int
+	reconnectInterval time.Duration
+	queryRetry        *QueryRetryConfig
+
+	// limiter caps write throughput when RateLimit has been called; nil
+	// means unlimited.
+	limiter *rateLimiter
+
+	// breaker fails queries and writes fast when CircuitBreaker has
+	// tripped it open; nil means no breaker.
+	breaker *circuitBreaker
+
+	// writeRetry retries writes on retryable errors when WriteRetry has
+	// been called; nil means no retrying.
+	writeRetry *WriteRetryConfig
+
+	// readOnly rejects every write when set by ReadOnly.
+	readOnly bool
+
+	// dryRun renders and reports every write instead of sending it,
+	// when set by DryRun; nil means writes are sent normally.
+	dryRun *DryRunConfig
+
+	// interceptors wrap every query and write, in the order added by
+	// Use; empty means requests run unwrapped.
+	interceptors []Interceptor
+
+	// dnsRefreshStop stops the background goroutine started by
+	// AutoRefreshDNS; nil means it hasn't been called.
+	dnsRefreshStop chan struct{}
+
+	// credentials is consulted before every query and write when set by
+	// RotateCredentials; nil means the credentials cli was built with
+	// never change.
+	credentials CredentialsProvider
+
+	// headers are extra HTTP headers added to every FluxQueryContext
+	// request by SetHeader; nil means none.
+	headers http.Header
+
+	// spoolCfg, spoolStop and spoolSize back the on-disk write-ahead
+	// spool enabled by Spool; spoolCfg nil means it hasn't been called.
+	spoolCfg  *SpoolConfig
+	spoolStop chan struct{}
+	spoolSize int64
+
+	// fileFallbackCfg backs the Telegraf-compatible rotating file
+	// fallback enabled by FileFallback; nil means it hasn't been
+	// called. fileFallbackMu serializes appends to it, separately from
+	// mu since it guards file I/O rather than Client state.
+	fileFallbackCfg *FileFallbackConfig
+	fileFallbackMu  sync.Mutex
+
+	// pointMiddleware transforms every point written by
+	// WriteBatchPointsContext, in the order added by UsePointMiddleware;
+	// empty means points are written unmodified.
+	pointMiddleware []PointMiddleware
+
+	// timeTruncation rounds a point's timestamp down per its
+	// measurement name before it is written, set by
+	// SetTimeTruncation; nil means no measurement is truncated.
+	timeTruncation map[string]time.Duration
+
+	// tenantResolver resolves a context's tenant ID (see WithTenant) to
+	// a database/retention-policy pair, set by SetTenantResolver; nil
+	// means tenant-aware routing is disabled.
+	tenantResolver TenantResolver
+
+	// addr is cfg.Addr from New, kept for the /api/v2/query endpoint
+	// FluxQueryContext uses: client.Client has no Flux support to
+	// delegate to.
+	addr string
+
+	// replicas are additional read-only connections queries are
+	// round-robined across; set by NewWithReplicas. If empty, queries
+	// run against cli like any other Client.
+	replicas      []client.Client
+	replicaCursor uint32
+
+	// replicaSelection chooses how nextReplica picks among replicas,
+	// set by SetReplicaSelection; the zero value is ReplicaRoundRobin.
+	replicaSelection ReplicaSelection
+	// replicaLatencies holds recordReplicaLatency's most recent sample
+	// for each entry in replicas, in nanoseconds (atomic); consulted by
+	// leastLatencyReplica under ReplicaLeastLatency.
+	replicaLatencies []int64
+
+	// failoverClis are every endpoint set by NewWithFailover, in
+	// preference order; failoverClis[failoverIdx] is the one cli
+	// currently mirrors. Empty means failover isn't in use and queries
+	// and writes go straight to cli.
+	failoverClis []client.Client
+	failoverIdx  uint32 // atomic
+	// failoverStop stops the background goroutine NewWithFailover starts
+	// to probe for a higher-preference endpoint's recovery; nil if
+	// failover isn't in use.
+	failoverStop chan struct{}
+
+	// Token authenticates FluxQueryContext requests via the
+	// "Authorization: Token <Token>" header InfluxDB's /api/v2/query
+	// endpoint expects.
+	Token string
+
+	// DB is the database used by Query, Insert and their Context
+	// variants when the caller passes an empty db, and by Migrate and
+	// DropSchema.
+	DB string
+	// Precision is the write precision applied to points built by
+	// Insert and InsertContext, defaults to "s"; override per call with
+	// InsertOptions.Precision. One of PrecisionNanosecond,
+	// PrecisionMicrosecond, PrecisionMillisecond or PrecisionSecond.
+	//
+	// client.Point stores timestamps with nanosecond resolution
+	// regardless of Precision: Precision only controls how much of that
+	// resolution survives the line-protocol encoding InfluxDB receives,
+	// so a "s" Client silently truncates points built from
+	// sub-second-resolution time.Time values.
+	Precision string
+	// RetentionPolicy is the retention policy applied to points built
+	// by Insert and InsertContext.
+	RetentionPolicy string
+
+	// defaultTags are merged into every point written through
+	// WriteBatchPointsContext, set by SetGlobalTags or WithDefaultTags;
+	// nil means none. A tag already set on the point being written
+	// takes precedence.
+	defaultTags map[string]string
+
+	// tagProviders are evaluated on every WriteBatchPointsContext call
+	// and merged into every point the same way defaultTags is, set by
+	// AddTagProviders. Unlike defaultTags, a tag returned by a provider
+	// can change between calls.
+	tagProviders []TagProvider
+
+	// aggMu guards aggBuf, separately from mu since AggregatePoint is on
+	// the hot path and shouldn't contend with mu's other uses.
+	aggMu sync.Mutex
+	// aggBuf groups points passed to AggregatePoint by measurement and
+	// tag set, pending the next window flush; nil means Aggregate
+	// hasn't been called.
+	aggBuf map[aggKey]*aggGroup
+	// aggCfg and aggStop back the pre-aggregation buffer enabled by
+	// Aggregate; aggCfg nil means it hasn't been called.
+	aggCfg  *AggregateConfig
+	aggStop chan struct{}
+
+	// queryCache backs CachedQueryContext, set by EnableQueryCache; nil
+	// means caching is disabled and CachedQueryContext behaves exactly
+	// like QueryContext. It guards its own entries with its own mutex,
+	// separately from mu, so concurrent cache hits never contend with
+	// mu's other uses.
+	queryCache *queryCache
+
+	// schemaGuard validates outgoing points against cached server field
+	// types when set by EnableSchemaValidation; nil disables validation.
+	// It guards its own cache with its own mutex, separately from mu,
+	// for the same reason queryCache does.
+	schemaGuard *schemaGuard
+
+	// slowQuery reports queries at or beyond its Threshold, set by
+	// LogSlowQueries; nil disables reporting.
+	slowQuery *SlowQueryConfig
+
+	// debugHook reports every query's raw response body, set by
+	// SetDebugHook; nil disables reporting.
+	debugHook *DebugHookConfig
+
+	// collectorStops stops every background goroutine started by
+	// RegisterCollector, one entry per call; Close closes them all.
+	collectorStops []chan struct{}
+
+	// healthMonitorStops stops every background goroutine started by
+	// StartHealthMonitor, one entry per call; Close closes them all.
+	healthMonitorStops []chan struct{}
+
+	// mirror, if set by Mirror, receives a copy of every point
+	// WriteBatchPointsContext writes to the primary, buffered and
+	// flushed to a second InfluxDB asynchronously; nil means mirroring
+	// is disabled.
+	mirror *BufferedWriter
+
+	// writeStats are the counters Stats reports; see WriteStats.
+	writeStats struct {
+		points, bytes, batches, retries, dropped int64 // atomic
+		consecutiveFailures                      int64 // atomic
+
+		mu        sync.Mutex
+		lastErr   error
+		lastFlush time.Time
+	}
+}
+
+// SetDefaultDatabase sets c.DB, the database Query, Insert and their
+// Context variants fall back to when called with an empty db, so
+// callers that always talk to one database don't have to repeat its
+// name on every call. Pass an explicit db to any of those methods to
+// target a different database without changing the default.
+func (c *Client) SetDefaultDatabase(db string) {
+	c.DB = db
+}
+
+// SetGlobalTags sets the tags merged into every point c writes (tags
+// like host, env or service), similar to Telegraf's global_tags. A tag
+// already set on a point being written takes precedence over a global
+// tag of the same name. Calling SetGlobalTags again replaces the
+// previous tags; pass nil to clear them.
+func (c *Client) SetGlobalTags(tags map[string]string) {
+	cp := make(map[string]string, len(tags))
+	for k, v := range tags {
+		cp[k] = v
+	}
+	c.defaultTags = cp
+}
+
+// New creates a Client from an HTTP client config. The returned Client
+// is safe for concurrent use by multiple goroutines, and several
+// Clients may be created to talk to several InfluxDB clusters from one
+// binary.
+func New(cfg client.HTTPConfig) (*Client, error) {
+	cli, err := client.NewHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{cli: cli, cfg: &cfg, addr: cfg.Addr, Precision: "s"}, nil
+}
+
+// NewUDP creates a Client that writes over UDP instead of HTTP. UDP
+// clients cannot run queries: QueryContext and QueryWithParamsContext
+// always fail against a Client built this way.
+func NewUDP(cfg client.UDPConfig) (*Client, error) {
+	cli, err := client.NewUDPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{cli: cli, Precision: "s"}, nil
 }
 
-func queryDB(cli client.Client, db string, cmd string) (res []client.Result, err error) {
+// NewWithClient wraps an already-constructed client.Client as a
+// Client, skipping New's own dialing. It exists so business logic
+// built on Client can be unit-tested against a fake client.Client
+// (e.g. influxtest.Mock) instead of a running InfluxDB.
+func NewWithClient(cli client.Client) *Client {
+	return &Client{cli: cli, Precision: "s"}
+}
+
+// NewUnix creates a Client that connects to InfluxDB over the Unix
+// domain socket at sockPath instead of TCP, for when influxd is
+// colocated with the app and the TCP stack can be skipped. Addr is
+// reported as "http://unix" since client.HTTPConfig requires an
+// http(s) URL even though DialContext ignores it.
+func NewUnix(sockPath string) (*Client, error) {
+	return New(client.HTTPConfig{
+		Addr: "http://unix",
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", sockPath)
+		},
+	})
+}
+
+// Query runs cmd against db.
+func (c *Client) Query(db string, cmd string) ([]client.Result, error) {
+	return c.QueryContext(context.Background(), db, cmd)
+}
+
+// QueryWithTimeout runs cmd against db, canceling it after timeout even
+// if the Client's HTTP client has no Timeout (or a longer one) set.
+func (c *Client) QueryWithTimeout(db, cmd string, timeout time.Duration) ([]client.Result, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return c.QueryContext(ctx, db, cmd)
+}
+
+// QueryContext runs cmd against db, aborting the request as soon as ctx
+// is canceled. If db is empty, c.DB is used instead.
+func (c *Client) QueryContext(ctx context.Context, db string, cmd string) ([]client.Result, error) {
+	if err := c.applyCredentials(); err != nil {
+		return nil, err
+	}
+	db, _ = c.resolveTenant(ctx, db)
 	q := client.Query{
 		Command:  cmd,
 		Database: db,
 	}
-	if response, err := cli.Query(q); err == nil {
-		if response.Error() != nil {
-			return res, response.Error()
+	var response *client.Response
+	info := RequestInfo{Kind: RequestQuery, Database: db, Command: cmd, Label: LabelFromContext(ctx)}
+	start := clockNow()
+	err := c.intercept(ctx, info, func(ctx context.Context) error {
+		return c.guard(func() error {
+			return c.withQueryRetry(ctx, func() error {
+				var opErr error
+				switch {
+				case len(c.failoverClis) > 0:
+					opErr = c.withFailover(func(cli client.Client) error {
+						response, opErr = cli.QueryCtx(ctx, q)
+						return opErr
+					})
+				case c.reconnectRetries > 0 && len(c.replicas) == 0:
+					opErr = c.withReconnect(func(cli client.Client) error {
+						response, opErr = cli.QueryCtx(ctx, q)
+						return opErr
+					})
+				default:
+					replica, idx := c.nextReplica()
+					replicaStart := clockNow()
+					response, opErr = replica.QueryCtx(ctx, q)
+					c.recordReplicaLatency(idx, clockNow().Sub(replicaStart))
+				}
+				return opErr
+			})
+		})
+	})
+	if c.slowQuery != nil {
+		var results []client.Result
+		if response != nil {
+			results = response.Results
 		}
-		res = response.Results
-	} else {
-		return res, err
+		c.reportSlowQuery(db, cmd, time.Since(start), results)
+	}
+	queryErr := err
+	if queryErr == nil && response != nil {
+		queryErr = response.Error()
 	}
-	return res, nil
+	if c.debugHook != nil {
+		c.reportDebug(cmd, response, queryErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if response.Error() != nil {
+		return response.Results, response.Error()
+	}
+	return response.Results, nil
 }
 
-func Query(db string, cmd string) ([]client.Result, error) {
-	return queryDB(gClient, db, cmd)
+// Write precisions for Client.Precision and InsertOptions.Precision.
+const (
+	PrecisionNanosecond  = "ns"
+	PrecisionMicrosecond = "us"
+	PrecisionMillisecond = "ms"
+	PrecisionSecond      = "s"
+)
+
+// Write consistency levels for InsertOptions.WriteConsistency and
+// client.BatchPointsConfig.WriteConsistency, honored by InfluxDB
+// Enterprise clusters.
+const (
+	ConsistencyAny    = "any"
+	ConsistencyOne    = "one"
+	ConsistencyQuorum = "quorum"
+	ConsistencyAll    = "all"
+)
+
+// InsertOptions overrides the Client defaults used to build the
+// BatchPoints that InsertWithOptionsContext writes a single point into.
+// A zero value for any field falls back to the Client's own setting.
+type InsertOptions struct {
+	RetentionPolicy  string
+	Precision        string
+	WriteConsistency string
 }
 
-func Insert(db string, point *client.Point) error {
-	bp, err := client.NewBatchPoints(client.BatchPointsConfig{
-		Database:  db,
-		Precision: "s",
+// Insert writes point to db.
+func (c *Client) Insert(db string, point *client.Point) error {
+	return c.InsertContext(context.Background(), db, point)
+}
+
+// InsertContext writes point to db, aborting the request as soon as ctx
+// is canceled. If db is empty, c.DB is used instead.
+func (c *Client) InsertContext(ctx context.Context, db string, point *client.Point) error {
+	return c.InsertWithOptionsContext(ctx, db, point, InsertOptions{})
+}
+
+// InsertRP writes point to db under retention policy rp, e.g. a
+// downsampled "one_week" RP, without requiring the caller to build
+// line protocol or a BatchPoints by hand.
+func (c *Client) InsertRP(db, rp string, point *client.Point) error {
+	return c.InsertRPContext(context.Background(), db, rp, point)
+}
+
+// InsertRPContext is InsertRP with a context that aborts the request as
+// soon as it is canceled.
+func (c *Client) InsertRPContext(ctx context.Context, db, rp string, point *client.Point) error {
+	return c.InsertWithOptionsContext(ctx, db, point, InsertOptions{RetentionPolicy: rp})
+}
+
+// InsertWithPrecision writes point to db at precision (one of
+// PrecisionNanosecond, PrecisionMicrosecond, PrecisionMillisecond or
+// PrecisionSecond) instead of the Client's own Precision, so a single
+// point built from a sub-second-resolution time.Time survives a write
+// through a Client whose default Precision is coarser (e.g. "s", the
+// default every New Client starts with) without the caller reaching for
+// InsertWithOptionsContext just to override one field.
+func (c *Client) InsertWithPrecision(db, precision string, point *client.Point) error {
+	return c.InsertWithPrecisionContext(context.Background(), db, precision, point)
+}
+
+// InsertWithPrecisionContext is InsertWithPrecision with a context that
+// aborts the request as soon as it is canceled.
+func (c *Client) InsertWithPrecisionContext(ctx context.Context, db, precision string, point *client.Point) error {
+	return c.InsertWithOptionsContext(ctx, db, point, InsertOptions{Precision: precision})
+}
+
+// InsertMany converts each element of slice, a slice of structs or
+// pointers to structs, to a point via ToPoint and writes them all as a
+// single batch, instead of the round trip per element Insert would
+// need. opts, e.g. Omit, apply to every element.
+func (c *Client) InsertMany(db string, slice interface{}, opts ...FieldOption) error {
+	return c.InsertManyContext(context.Background(), db, slice, opts...)
+}
+
+// InsertManyContext is InsertMany with a context that aborts the
+// request as soon as it is canceled.
+func (c *Client) InsertManyContext(ctx context.Context, db string, slice interface{}, opts ...FieldOption) error {
+	points, err := ToPointsContext(ctx, slice, opts...)
+	if err != nil {
+		return err
+	}
+	db, retentionPolicy := c.resolveTenant(ctx, db)
+	bp, release, err := newPooledBatchPoints(client.BatchPointsConfig{
+		Database:        db,
+		Precision:       c.Precision,
+		RetentionPolicy: retentionPolicy,
+	})
+	if err != nil {
+		return err
+	}
+	defer release()
+	bp.AddPoints(points)
+	return c.WriteBatchPointsContext(ctx, bp)
+}
+
+// InsertManyNamed is InsertMany, but writes every element to measurement
+// instead of whatever each would otherwise derive (see ToPointNamed),
+// for a batch of one struct type destined for a per-tenant or
+// per-environment measurement name.
+func (c *Client) InsertManyNamed(db, measurement string, slice interface{}, opts ...FieldOption) error {
+	return c.InsertManyNamedContext(context.Background(), db, measurement, slice, opts...)
+}
+
+// InsertManyNamedContext is InsertManyNamed with a context that aborts
+// the request as soon as it is canceled.
+func (c *Client) InsertManyNamedContext(ctx context.Context, db, measurement string, slice interface{}, opts ...FieldOption) error {
+	points, err := ToPointsNamedContext(ctx, measurement, slice, opts...)
+	if err != nil {
+		return err
+	}
+	db, retentionPolicy := c.resolveTenant(ctx, db)
+	bp, release, err := newPooledBatchPoints(client.BatchPointsConfig{
+		Database:        db,
+		Precision:       c.Precision,
+		RetentionPolicy: retentionPolicy,
 	})
 	if err != nil {
 		return err
 	}
+	defer release()
+	bp.AddPoints(points)
+	return c.WriteBatchPointsContext(ctx, bp)
+}
+
+// InsertWithOptionsContext is InsertContext, but opts overrides the
+// Client's Precision and RetentionPolicy for this point and can target
+// a specific write consistency level, so a single call can target a
+// downsampled retention policy without touching the Client's defaults.
+func (c *Client) InsertWithOptionsContext(ctx context.Context, db string, point *client.Point, opts InsertOptions) error {
+	db, defaultRP := c.resolveTenant(ctx, db)
+	precision := opts.Precision
+	if precision == "" {
+		precision = c.Precision
+	}
+	retentionPolicy := opts.RetentionPolicy
+	if retentionPolicy == "" {
+		retentionPolicy = defaultRP
+	}
+	bp, release, err := newPooledBatchPoints(client.BatchPointsConfig{
+		Database:         db,
+		Precision:        precision,
+		RetentionPolicy:  retentionPolicy,
+		WriteConsistency: opts.WriteConsistency,
+	})
+	if err != nil {
+		return err
+	}
+	defer release()
 	bp.AddPoint(point)
-	return gClient.Write(bp)
+	return c.WriteBatchPointsContext(ctx, bp)
+}
+
+// applyDefaultTags rebuilds bp with c's static global tags and dynamic
+// tag providers merged into every point, since client.Point exposes no
+// way to add a tag after construction. A tag already set on a point
+// takes precedence over everything applyDefaultTags would merge in.
+func (c *Client) applyDefaultTags(bp client.BatchPoints) (client.BatchPoints, error) {
+	tags := c.collectDefaultTags()
+	if len(tags) == 0 {
+		return bp, nil
+	}
+
+	points := make([]*client.Point, 0, len(bp.Points()))
+	for _, p := range bp.Points() {
+		p, err := mergeTags(p, tags)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+
+	newBP, err := client.NewBatchPoints(client.BatchPointsConfig{
+		Database:         bp.Database(),
+		Precision:        bp.Precision(),
+		RetentionPolicy:  bp.RetentionPolicy(),
+		WriteConsistency: bp.WriteConsistency(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	newBP.AddPoints(points)
+	return newBP, nil
+}
+
+// collectDefaultTags evaluates c.tagProviders, in registration order,
+// and layers c.defaultTags on top, so a static global tag always wins
+// over a dynamic one of the same name.
+func (c *Client) collectDefaultTags() map[string]string {
+	if len(c.defaultTags) == 0 && len(c.tagProviders) == 0 {
+		return nil
+	}
+	tags := make(map[string]string)
+	for _, provider := range c.tagProviders {
+		for k, v := range provider() {
+			tags[k] = v
+		}
+	}
+	for k, v := range c.defaultTags {
+		tags[k] = v
+	}
+	return tags
 }
 
+// withDefaultTags rebuilds point with c's static global tags merged in;
+// used by InsertWithOptionsContext before applyDefaultTags existed.
+func (c *Client) withDefaultTags(point *client.Point) (*client.Point, error) {
+	return mergeTags(point, c.defaultTags)
+}
+
+// mergeTags rebuilds point with tags merged in. A tag already set on
+// point takes precedence over a tag of the same name in tags.
+func mergeTags(point *client.Point, tags map[string]string) (*client.Point, error) {
+	if len(tags) == 0 {
+		return point, nil
+	}
+	merged := make(map[string]string, len(tags)+len(point.Tags()))
+	for k, v := range tags {
+		merged[k] = v
+	}
+	for k, v := range point.Tags() {
+		merged[k] = v
+	}
+	fields, err := point.Fields()
+	if err != nil {
+		return nil, err
+	}
+	return client.NewPoint(point.Name(), merged, fields, point.Time())
+}
+
+// WriteBatchPoints writes bp.
+func (c *Client) WriteBatchPoints(bp client.BatchPoints) error {
+	return c.WriteBatchPointsContext(context.Background(), bp)
+}
+
+// WriteBatchPointsContext writes bp, aborting the request as soon as
+// ctx is canceled.
+//
+// This never gzip-compresses the body: client.Client.WriteCtx builds
+// the POST request itself and gives us no hook to set Content-Encoding
+// or swap in a compressed body. For large batches over a bandwidth-
+// constrained link, use WriteBatchPointsGzipContext instead, which
+// bypasses client.Client to compress the request.
+func (c *Client) WriteBatchPointsContext(ctx context.Context, bp client.BatchPoints) error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+	if err := c.applyCredentials(); err != nil {
+		return err
+	}
+	bp, err := c.applyDefaultTags(bp)
+	if err != nil {
+		return err
+	}
+	bp, err = c.applyPointMiddleware(bp)
+	if err != nil {
+		return err
+	}
+	bp, err = c.applyTimeTruncation(bp)
+	if err != nil {
+		return err
+	}
+	bp, err = c.applySchemaValidation(ctx, bp)
+	if err != nil {
+		return err
+	}
+	if c.dryRun != nil {
+		c.reportDryRun(bp)
+		return nil
+	}
+	if err := c.waitForBudget(ctx, bp); err != nil {
+		return err
+	}
+	c.mirrorPoints(bp)
+	info := RequestInfo{
+		Kind:         RequestWrite,
+		Database:     bp.Database(),
+		Points:       len(bp.Points()),
+		Measurements: measurementSet(bp),
+		Label:        LabelFromContext(ctx),
+	}
+	err = c.intercept(ctx, info, func(ctx context.Context) error {
+		return c.guard(func() error {
+			return c.withWriteRetry(ctx, func() error {
+				return c.writeBatchPoints(ctx, bp)
+			})
+		})
+	})
+	if err != nil {
+		c.mu.RLock()
+		spoolCfg := c.spoolCfg
+		fileCfg := c.fileFallbackCfg
+		c.mu.RUnlock()
+
+		spooled := spoolCfg != nil && c.spoolBatch(spoolCfg, bp) == nil
+		if !spooled && (fileCfg == nil || c.appendFileFallback(fileCfg, bp) != nil) {
+			c.recordDropped(bp)
+		}
+	}
+	c.recordWriteResult(bp, err)
+	return err
+}
+
+func (c *Client) writeBatchPoints(ctx context.Context, bp client.BatchPoints) error {
+	switch {
+	case len(c.failoverClis) > 0:
+		return c.withFailover(func(cli client.Client) error {
+			return cli.WriteCtx(ctx, bp)
+		})
+	case c.reconnectRetries > 0:
+		return c.withReconnect(func(cli client.Client) error {
+			return cli.WriteCtx(ctx, bp)
+		})
+	}
+	c.mu.RLock()
+	cli := c.cli
+	c.mu.RUnlock()
+	return cli.WriteCtx(ctx, bp)
+}
+
+// Ping checks the status of the cluster.
+func (c *Client) Ping(timeout time.Duration) (time.Duration, string, error) {
+	return c.cli.Ping(timeout)
+}
+
+// Version pings the cluster and returns just the reported version
+// string (e.g. "1.8.10"), so callers can verify connectivity and branch
+// on version without juggling Ping's round-trip time return value. This
+// package only ever speaks the 1.x query/write endpoints regardless of
+// what Version reports; see the package doc for why.
+func (c *Client) Version(timeout time.Duration) (string, error) {
+	_, version, err := c.Ping(timeout)
+	return version, err
+}
+
+// PingContext checks the status of the cluster, returning as soon as
+// ctx is canceled even though the underlying client has no native
+// cancellation for Ping.
+func (c *Client) PingContext(ctx context.Context, timeout time.Duration) (time.Duration, string, error) {
+	type result struct {
+		rtt     time.Duration
+		version string
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		rtt, version, err := c.cli.Ping(timeout)
+		done <- result{rtt, version, err}
+	}()
+	select {
+	case <-ctx.Done():
+		return 0, "", ctx.Err()
+	case r := <-done:
+		return r.rtt, r.version, r.err
+	}
+}
+
+// VersionContext is Version, returning as soon as ctx is canceled even
+// though the underlying client has no native cancellation for Ping.
+func (c *Client) VersionContext(ctx context.Context, timeout time.Duration) (string, error) {
+	_, version, err := c.PingContext(ctx, timeout)
+	return version, err
+}
+
+// Close releases any resources the underlying client holds, including
+// every read replica added by NewWithReplicas and every endpoint added
+// by NewWithFailover.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.dnsRefreshStop != nil {
+		close(c.dnsRefreshStop)
+		c.dnsRefreshStop = nil
+	}
+	if c.spoolStop != nil {
+		close(c.spoolStop)
+		c.spoolStop = nil
+	}
+	if c.aggStop != nil {
+		close(c.aggStop)
+		c.aggStop = nil
+	}
+	if c.failoverStop != nil {
+		close(c.failoverStop)
+		c.failoverStop = nil
+	}
+	for _, stop := range c.collectorStops {
+		close(stop)
+	}
+	c.collectorStops = nil
+	for _, stop := range c.healthMonitorStops {
+		close(stop)
+	}
+	c.healthMonitorStops = nil
+	mirror := c.mirror
+	c.mu.Unlock()
+
+	if mirror != nil {
+		mirror.Close(context.Background())
+	}
+
+	var err error
+	if len(c.failoverClis) > 0 {
+		// c.cli is always one of failoverClis (whichever is currently
+		// active), so closing it again here would double-close it.
+		for _, cli := range c.failoverClis {
+			if cerr := cli.Close(); err == nil {
+				err = cerr
+			}
+		}
+	} else {
+		err = c.cli.Close()
+	}
+	for _, r := range c.replicas {
+		if rerr := r.Close(); err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
+
+// gClientVal holds the default *Client used by the package-level
+// functions below, kept for backward compatibility with code written
+// before Client existed. It is an atomic.Value rather than a plain
+// *Client so InitClient (and friends) can be called again at runtime,
+// e.g. on config reload, without racing readers in Query/Insert.
+var gClientVal atomic.Value
+
+// setGClient atomically swaps the default Client.
+func setGClient(c *Client) {
+	gClientVal.Store(c)
+}
+
+// gClient atomically loads the default Client, or nil if none of the
+// InitClient family has been called yet.
+func gClient() *Client {
+	c, _ := gClientVal.Load().(*Client)
+	return c
+}
+
+// InitClient initializes the package-level default Client.
+func InitClient(addr string) error {
+	cli, err := New(client.HTTPConfig{Addr: addr})
+	if err != nil {
+		return err
+	}
+	setGClient(cli)
+	return nil
+}
+
+// InitClientWithConfig initializes the package-level default Client from
+// cfg, letting callers set TLSConfig, InsecureSkipVerify, Username,
+// Password, Timeout or any other client.HTTPConfig field that InitClient
+// does not expose.
+func InitClientWithConfig(cfg client.HTTPConfig) error {
+	cli, err := New(cfg)
+	if err != nil {
+		return err
+	}
+	setGClient(cli)
+	return nil
+}
+
+// InitClientWithAuth initializes the package-level default Client,
+// authenticating with username and password.
+func InitClientWithAuth(addr, username, password string) error {
+	return InitClientWithConfig(client.HTTPConfig{
+		Addr:     addr,
+		Username: username,
+		Password: password,
+	})
+}
+
+// InitClientWithTimeout initializes the package-level default Client
+// with an HTTP timeout, so a hung InfluxDB no longer blocks Query or
+// Insert indefinitely.
+func InitClientWithTimeout(addr string, timeout time.Duration) error {
+	return InitClientWithConfig(client.HTTPConfig{
+		Addr:    addr,
+		Timeout: timeout,
+	})
+}
+
+// InitClientWithProxy initializes the package-level default Client to
+// route every request through the HTTP/HTTPS proxy at proxyURL, for
+// InfluxDB traffic that must traverse a corporate proxy. For anything
+// more dynamic than one fixed proxy (e.g. NO_PROXY-style exclusion
+// rules, or per-request routing), use InitClientWithDialer's proxy hook
+// directly.
+func InitClientWithProxy(addr, proxyURL string) error {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return err
+	}
+	return InitClientWithConfig(client.HTTPConfig{
+		Addr:  addr,
+		Proxy: http.ProxyURL(u),
+	})
+}
+
+// AutoReconnect enables AutoReconnect on the package-level default
+// Client.
+func AutoReconnect(maxRetries int, interval time.Duration) error {
+	return gClient().AutoReconnect(maxRetries, interval)
+}
+
+// CircuitBreaker enables a circuit breaker on the package-level default
+// Client.
+func CircuitBreaker(cfg CircuitBreakerConfig) {
+	gClient().CircuitBreaker(cfg)
+}
+
+// WriteRetry enables write retrying on the package-level default
+// Client.
+func WriteRetry(cfg WriteRetryConfig) {
+	gClient().WriteRetry(cfg)
+}
+
+// QueryRetry enables query retrying on the package-level default
+// Client.
+func QueryRetry(cfg QueryRetryConfig) {
+	gClient().QueryRetry(cfg)
+}
+
+// ReadOnly puts the package-level default Client into (or out of)
+// read-only mode.
+func ReadOnly(readOnly bool) {
+	gClient().ReadOnly(readOnly)
+}
+
+// SetDefaultDatabase sets the database the package-level default Client
+// falls back to when Query or Insert are called with an empty db.
+func SetDefaultDatabase(db string) {
+	gClient().SetDefaultDatabase(db)
+}
+
+// Spool enables a disk-backed write-ahead spool on the package-level
+// default Client.
+func Spool(cfg SpoolConfig) error {
+	return gClient().Spool(cfg)
+}
+
+// SetGlobalTags sets the tags merged into every point written by the
+// package-level default Client.
+func SetGlobalTags(tags map[string]string) {
+	gClient().SetGlobalTags(tags)
+}
+
+// Stats returns a snapshot of the package-level default Client's write
+// counters.
+func Stats() WriteStats {
+	return gClient().Stats()
+}
+
+// InitUnixClient initializes the package-level default Client to
+// connect to InfluxDB over the Unix domain socket at sockPath.
+func InitUnixClient(sockPath string) error {
+	cli, err := NewUnix(sockPath)
+	if err != nil {
+		return err
+	}
+	setGClient(cli)
+	return nil
+}
+
+// InitClientWithDialer initializes the package-level default Client
+// using dial to establish the underlying TCP connections and proxy to
+// route requests through a proxy, for instrumentation, custom dial
+// pooling or corporate proxies.
+//
+// client.HTTPConfig has no hook for a full *http.Client or
+// http.RoundTripper: NewHTTPClient always builds its own http.Client
+// around an *http.Transport it constructs itself. DialContext and Proxy
+// are the only seams the underlying v1 client exposes — there is no way
+// to set MaxIdleConns, MaxIdleConnsPerHost, IdleConnTimeout or
+// keep-alive settings on that Transport without forking client/v2.
+// DialContext can work around connection churn indirectly (e.g. by
+// returning connections from a custom pool), but cannot tune the
+// stdlib Transport's own idle-connection limits.
+func InitClientWithDialer(addr string, dial func(ctx context.Context, network, addr string) (net.Conn, error), proxy func(*http.Request) (*url.URL, error)) error {
+	return InitClientWithConfig(client.HTTPConfig{
+		Addr:        addr,
+		DialContext: dial,
+		Proxy:       proxy,
+	})
+}
+
+// InitClientWithReplicas initializes the package-level default Client to
+// write to writeCfg and round-robin queries across readCfgs.
+func InitClientWithReplicas(writeCfg client.HTTPConfig, readCfgs ...client.HTTPConfig) error {
+	cli, err := NewWithReplicas(writeCfg, readCfgs...)
+	if err != nil {
+		return err
+	}
+	setGClient(cli)
+	return nil
+}
+
+// InitUDPClient initializes the package-level default Client to write
+// over UDP. payloadSize is the maximum size of a UDP message; pass 0 to
+// use client.UDPPayloadSize. Query and Insert then route through UDP,
+// but Query always fails since the UDP protocol carries no responses.
+func InitUDPClient(addr string, payloadSize int) error {
+	cli, err := NewUDP(client.UDPConfig{Addr: addr, PayloadSize: payloadSize})
+	if err != nil {
+		return err
+	}
+	setGClient(cli)
+	return nil
+}
+
+// Query runs cmd against db using the default Client.
+func Query(db string, cmd string) ([]client.Result, error) {
+	return gClient().Query(db, cmd)
+}
+
+// QueryContext runs cmd against db using the default Client, aborting
+// the request as soon as ctx is canceled.
+func QueryContext(ctx context.Context, db string, cmd string) ([]client.Result, error) {
+	return gClient().QueryContext(ctx, db, cmd)
+}
+
+// QueryWithTimeout runs cmd against db using the default Client,
+// canceling it after timeout.
+func QueryWithTimeout(db, cmd string, timeout time.Duration) ([]client.Result, error) {
+	return gClient().QueryWithTimeout(db, cmd, timeout)
+}
+
+// Insert writes point to db using the default Client.
+func Insert(db string, point *client.Point) error {
+	return gClient().Insert(db, point)
+}
+
+// InsertContext writes point to db using the default Client, aborting
+// the request as soon as ctx is canceled.
+func InsertContext(ctx context.Context, db string, point *client.Point) error {
+	return gClient().InsertContext(ctx, db, point)
+}
+
+// InsertRP writes point to db under retention policy rp, using the
+// default Client.
+func InsertRP(db, rp string, point *client.Point) error {
+	return gClient().InsertRP(db, rp, point)
+}
+
+// InsertWithPrecision writes point to db at precision instead of the
+// default Client's own Precision, using the default Client.
+func InsertWithPrecision(db, precision string, point *client.Point) error {
+	return gClient().InsertWithPrecision(db, precision, point)
+}
+
+// InsertMany converts each element of slice to a point and writes them
+// as a single batch, using the default Client.
+func InsertMany(db string, slice interface{}, opts ...FieldOption) error {
+	return gClient().InsertMany(db, slice, opts...)
+}
+
+// InsertManyNamed writes every element of slice to measurement as a
+// single batch, using the default Client.
+func InsertManyNamed(db, measurement string, slice interface{}, opts ...FieldOption) error {
+	return gClient().InsertManyNamed(db, measurement, slice, opts...)
+}
+
+// WriteBatchPoints writes bp using the default Client.
 func WriteBatchPoints(bp client.BatchPoints) error {
-	return gClient.Write(bp)
+	return gClient().WriteBatchPoints(bp)
+}
+
+// WriteBatchPointsContext writes bp using the default Client, aborting
+// the request as soon as ctx is canceled.
+func WriteBatchPointsContext(ctx context.Context, bp client.BatchPoints) error {
+	return gClient().WriteBatchPointsContext(ctx, bp)
+}
+
+// Close releases the default Client's underlying connection, so a
+// long-running service can shut down cleanly. It is a no-op if none of
+// the InitClient family has been called.
+func Close() error {
+	if c := gClient(); c != nil {
+		return c.Close()
+	}
+	return nil
 }
@@ -0,0 +1,106 @@
+package influx
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Ticker is the subset of *time.Ticker a Clock's NewTicker returns, so
+// a fake Clock can hand back a ticker it controls instead of a real
+// wall-clock one.
+type Ticker interface {
+	// C returns the channel a tick is delivered on, in place of
+	// *time.Ticker's C field.
+	C() <-chan time.Time
+	// Stop releases the ticker's resources, in place of
+	// *time.Ticker.Stop.
+	Stop()
+}
+
+// Clock abstracts every way this package tells the time or waits on
+// it — ToPoint's default timestamp, a BufferedWriter's flush interval,
+// a retry's backoff delay, a collector's/health monitor's tick — so a
+// test can swap in a fake that reports and advances time under its own
+// control instead of sleeping through real wall-clock time, the
+// leading cause of a flaky time-dependent CI assertion.
+type Clock interface {
+	// Now returns the current time, in place of time.Now().
+	Now() time.Time
+	// After returns a channel that receives the current time once d
+	// has elapsed, in place of time.After.
+	After(d time.Duration) <-chan time.Time
+	// Sleep blocks for d, in place of time.Sleep.
+	Sleep(d time.Duration)
+	// NewTicker returns a ticker that fires every d, in place of
+	// time.NewTicker.
+	NewTicker(d time.Duration) Ticker
+}
+
+// realClock is the default Clock, backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+
+// realTicker adapts *time.Ticker to the Ticker interface.
+type realTicker struct{ t *time.Ticker }
+
+func (r realTicker) C() <-chan time.Time { return r.t.C }
+func (r realTicker) Stop()               { r.t.Stop() }
+
+// clockBox lets activeClock hold any Clock implementation: atomic.Value
+// requires every Store to use the same concrete type, so the
+// indirection through a single boxed pointer type is necessary.
+type clockBox struct {
+	clock Clock
+}
+
+var activeClock atomic.Value // *clockBox
+
+func init() {
+	activeClock.Store(&clockBox{clock: realClock{}})
+}
+
+// SetClock replaces the package-level Clock every time.Now()/
+// time.After/time.Sleep/time.NewTicker call in this package goes
+// through from here on. Pass nil to restore the real wall-clock
+// default.
+//
+// Like SetNamingStrategy, this is process-wide state: a service
+// driving several Clients under different fake clocks isn't
+// supported, since ToPoint itself is a package-level function with no
+// per-Client config to hang a clock off of.
+func SetClock(clock Clock) {
+	if clock == nil {
+		clock = realClock{}
+	}
+	activeClock.Store(&clockBox{clock: clock})
+}
+
+func currentClock() Clock {
+	return activeClock.Load().(*clockBox).clock
+}
+
+// clockNow is time.Now() routed through the active Clock. It isn't
+// named now so it can be called from a function that also has a local
+// variable named now, a common pattern in this package.
+func clockNow() time.Time {
+	return currentClock().Now()
+}
+
+// after is time.After(d) routed through the active Clock.
+func after(d time.Duration) <-chan time.Time {
+	return currentClock().After(d)
+}
+
+// sleep is time.Sleep(d) routed through the active Clock.
+func sleep(d time.Duration) {
+	currentClock().Sleep(d)
+}
+
+// newTicker is time.NewTicker(d) routed through the active Clock.
+func newTicker(d time.Duration) Ticker {
+	return currentClock().NewTicker(d)
+}
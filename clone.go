@@ -0,0 +1,76 @@
+package influx
+
+// Option overrides one field on the Client returned by With.
+type Option func(*Client)
+
+// WithDatabase overrides the default database on the Client returned by
+// With.
+func WithDatabase(db string) Option {
+	return func(c *Client) { c.DB = db }
+}
+
+// WithPrecision overrides the write precision on the Client returned by
+// With.
+func WithPrecision(precision string) Option {
+	return func(c *Client) { c.Precision = precision }
+}
+
+// WithRetentionPolicy overrides the retention policy on the Client
+// returned by With.
+func WithRetentionPolicy(rp string) Option {
+	return func(c *Client) { c.RetentionPolicy = rp }
+}
+
+// WithDefaultTags overrides the tags merged into every point written by
+// the Client returned by With, same as SetGlobalTags. A tag already set
+// on the point being written takes precedence over a default tag of the
+// same name.
+func WithDefaultTags(tags map[string]string) Option {
+	cp := make(map[string]string, len(tags))
+	for k, v := range tags {
+		cp[k] = v
+	}
+	return func(c *Client) { c.defaultTags = cp }
+}
+
+// With returns a shallow clone of c with opts applied, so a
+// subsystem-specific Client can default to its own database, precision,
+// retention policy or tags without touching c's. The clone shares c's
+// underlying transport, cfg, rate limiter, circuit breaker, write retry
+// config, query retry config, slow-query config, dry-run mode, debug
+// hook and interceptor chain as they are when With is called, so it
+// opens no second connection to InfluxDB. Those settings are copied,
+// not linked: a later
+// CircuitBreaker/WriteRetry/QueryRetry/LogSlowQueries/DryRun/SetDebugHook/Use
+// call on c does not change the clone, nor the other way round.
+// It does not share c's AutoRefreshDNS goroutine, replica round-robin
+// cursor or ReplicaLeastLatency mode/samples: call those again on the
+// clone if it needs them.
+func (c *Client) With(opts ...Option) *Client {
+	clone := &Client{
+		cli:               c.cli,
+		cfg:               c.cfg,
+		reconnectRetries:  c.reconnectRetries,
+		reconnectInterval: c.reconnectInterval,
+		queryRetry:        c.queryRetry,
+		slowQuery:         c.slowQuery,
+		limiter:           c.limiter,
+		breaker:           c.breaker,
+		writeRetry:        c.writeRetry,
+		readOnly:          c.readOnly,
+		dryRun:            c.dryRun,
+		debugHook:         c.debugHook,
+		interceptors:      c.interceptors,
+		addr:              c.addr,
+		replicas:          c.replicas,
+		Token:             c.Token,
+		DB:                c.DB,
+		Precision:         c.Precision,
+		RetentionPolicy:   c.RetentionPolicy,
+		defaultTags:       c.defaultTags,
+	}
+	for _, opt := range opts {
+		opt(clone)
+	}
+	return clone
+}
@@ -0,0 +1,171 @@
+package influx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// TestWriteV2ContextSendsOrgBucketAndToken confirms the request URL,
+// Authorization header and body match what /api/v2/write expects.
+func TestWriteV2ContextSendsOrgBucketAndToken(t *testing.T) {
+	var gotOrg, gotBucket, gotAuth, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotOrg = r.URL.Query().Get("org")
+		gotBucket = r.URL.Query().Get("bucket")
+		gotAuth = r.Header.Get("Authorization")
+		body := make([]byte, r.ContentLength)
+		r.Body.Read(body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Token = "secret"
+
+	p, err := client.NewPoint("cpu", map[string]string{"host": "a"}, map[string]interface{}{"value": 1.5}, time.Unix(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.WriteV2("myorg", "mybucket", []*client.Point{p}, WriteV2Options{Precision: "s"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if gotOrg != "myorg" || gotBucket != "mybucket" {
+		t.Fatalf("org=%q bucket=%q", gotOrg, gotBucket)
+	}
+	if gotAuth != "Token secret" {
+		t.Fatalf("Authorization = %q", gotAuth)
+	}
+	if gotBody != p.PrecisionString("s")+"\n" {
+		t.Fatalf("body = %q, want %q", gotBody, p.PrecisionString("s")+"\n")
+	}
+}
+
+// TestWriteV2ContextRetriesAfter429 confirms a 429 response with a
+// Retry-After header is retried automatically, waiting the named
+// number of seconds through the package's Clock abstraction rather
+// than a real sleep.
+func TestWriteV2ContextRetriesAfter429(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "5")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	fc := newFakeClock()
+	SetClock(fc)
+	defer SetClock(nil)
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := client.NewPoint("cpu", nil, map[string]interface{}{"value": 1.0}, time.Unix(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.WriteV2("myorg", "mybucket", []*client.Point{p}, WriteV2Options{Precision: "s"})
+	}()
+
+	fc.awaitAfter(t, 5*time.Second)
+	fc.fire()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("WriteV2 failed: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("WriteV2 never returned")
+	}
+
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Fatalf("attempts = %d, want 2", got)
+	}
+}
+
+// TestWriteV2ContextGivesUpWithoutRetryAfter confirms a 429 with no
+// Retry-After header fails without retrying.
+func TestWriteV2ContextGivesUpWithoutRetryAfter(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := client.NewPoint("cpu", nil, map[string]interface{}{"value": 1.0}, time.Unix(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.WriteV2("myorg", "mybucket", []*client.Point{p}, WriteV2Options{Precision: "s"}); err == nil {
+		t.Fatal("want error")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Fatalf("attempts = %d, want 1 (no Retry-After means no retry)", got)
+	}
+}
+
+// fakeClock is a minimal Clock whose After returns a channel this test
+// controls directly, so TestWriteV2ContextRetriesAfter429 doesn't have
+// to sleep through a real 5-second wait.
+type fakeClock struct {
+	afterCalls chan time.Duration
+	fireCh     chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{
+		afterCalls: make(chan time.Duration, 1),
+		fireCh:     make(chan time.Time, 1),
+	}
+}
+
+func (f *fakeClock) Now() time.Time { return time.Now() }
+func (f *fakeClock) After(d time.Duration) <-chan time.Time {
+	f.afterCalls <- d
+	return f.fireCh
+}
+func (f *fakeClock) Sleep(d time.Duration)            {}
+func (f *fakeClock) NewTicker(d time.Duration) Ticker { return nil }
+
+func (f *fakeClock) awaitAfter(t *testing.T, want time.Duration) {
+	t.Helper()
+	select {
+	case d := <-f.afterCalls:
+		if d != want {
+			t.Fatalf("After(%v), want After(%v)", d, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("After was never called")
+	}
+}
+
+func (f *fakeClock) fire() {
+	f.fireCh <- time.Now()
+}
@@ -0,0 +1,134 @@
+package influx
+
+import "context"
+
+// Shard is one row of SHOW SHARDS, as returned by ShowShards.
+type Shard struct {
+	ID              int64  `inf:"id"`
+	Database        string `inf:"database"`
+	RetentionPolicy string `inf:"retention_policy"`
+	ShardGroup      int64  `inf:"shard_group"`
+	StartTime       string `inf:"start_time"`
+	EndTime         string `inf:"end_time"`
+	ExpiryTime      string `inf:"expiry_time"`
+	Owners          string `inf:"owners"`
+}
+
+// ShardGroup is one row of SHOW SHARD GROUPS, as returned by
+// ShowShardGroups.
+type ShardGroup struct {
+	ID              int64  `inf:"id"`
+	Database        string `inf:"database"`
+	RetentionPolicy string `inf:"retention_policy"`
+	StartTime       string `inf:"start_time"`
+	EndTime         string `inf:"end_time"`
+	ExpiryTime      string `inf:"expiry_time"`
+}
+
+// Subscription is one row of SHOW SUBSCRIPTIONS, as returned by
+// ShowSubscriptions.
+type Subscription struct {
+	Database        string   `inf:"database"`
+	RetentionPolicy string   `inf:"retention_policy"`
+	Name            string   `inf:"name"`
+	Mode            string   `inf:"mode"`
+	Destinations    []string `inf:"destinations"`
+}
+
+// ShowShards runs SHOW SHARDS and decodes every row into a Shard,
+// sparing Enterprise cluster operators the raw Query call and manual
+// models.Row decoding.
+func (c *Client) ShowShards() ([]Shard, error) {
+	return c.ShowShardsContext(context.Background())
+}
+
+// ShowShardsContext is ShowShards with a context that aborts the
+// request as soon as it is canceled.
+func (c *Client) ShowShardsContext(ctx context.Context) ([]Shard, error) {
+	var shards []Shard
+	results, err := c.QueryContext(ctx, "", "SHOW SHARDS")
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		for _, serie := range result.Series {
+			var rows []Shard
+			if err := ParseResult(&rows, serie); err != nil {
+				return nil, err
+			}
+			shards = append(shards, rows...)
+		}
+	}
+	return shards, nil
+}
+
+// ShowShardGroups runs SHOW SHARD GROUPS and decodes every row into a
+// ShardGroup.
+func (c *Client) ShowShardGroups() ([]ShardGroup, error) {
+	return c.ShowShardGroupsContext(context.Background())
+}
+
+// ShowShardGroupsContext is ShowShardGroups with a context that aborts
+// the request as soon as it is canceled.
+func (c *Client) ShowShardGroupsContext(ctx context.Context) ([]ShardGroup, error) {
+	var groups []ShardGroup
+	results, err := c.QueryContext(ctx, "", "SHOW SHARD GROUPS")
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		for _, serie := range result.Series {
+			var rows []ShardGroup
+			if err := ParseResult(&rows, serie); err != nil {
+				return nil, err
+			}
+			groups = append(groups, rows...)
+		}
+	}
+	return groups, nil
+}
+
+// ShowSubscriptions runs SHOW SUBSCRIPTIONS and decodes every row into a
+// Subscription. Unlike SHOW SHARDS and SHOW SHARD GROUPS, InfluxDB
+// returns one series per database, so this flattens them all into one
+// slice.
+func (c *Client) ShowSubscriptions() ([]Subscription, error) {
+	return c.ShowSubscriptionsContext(context.Background())
+}
+
+// ShowSubscriptionsContext is ShowSubscriptions with a context that
+// aborts the request as soon as it is canceled.
+func (c *Client) ShowSubscriptionsContext(ctx context.Context) ([]Subscription, error) {
+	var subs []Subscription
+	results, err := c.QueryContext(ctx, "", "SHOW SUBSCRIPTIONS")
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		for _, serie := range result.Series {
+			var rows []Subscription
+			if err := ParseResult(&rows, serie); err != nil {
+				return nil, err
+			}
+			subs = append(subs, rows...)
+		}
+	}
+	return subs, nil
+}
+
+// ShowShards runs SHOW SHARDS using the package-level default Client.
+func ShowShards() ([]Shard, error) {
+	return gClient().ShowShards()
+}
+
+// ShowShardGroups runs SHOW SHARD GROUPS using the package-level default
+// Client.
+func ShowShardGroups() ([]ShardGroup, error) {
+	return gClient().ShowShardGroups()
+}
+
+// ShowSubscriptions runs SHOW SUBSCRIPTIONS using the package-level
+// default Client.
+func ShowSubscriptions() ([]Subscription, error) {
+	return gClient().ShowSubscriptions()
+}
@@ -0,0 +1,415 @@
+// Command infxgen emits a MarshalPoint and UnmarshalRow method (see
+// influx.PointMarshaler and influx.RowUnmarshaler) for every struct in a
+// file marked with a `//influx:generate` doc comment, straight from its
+// existing `inf` struct tags, so a hot path can skip ToPoint/ParseResult's
+// reflection entirely. A struct using a feature infxgen doesn't
+// understand (embedded fields, `,flatten`, `,fields`/`,tags` maps, a
+// time.Duration unit, `,string`, a non-time.Time time field with a unit
+// or `,layout=` option, a registered Converter) is reported on
+// stderr and left alone; it still works through ToPoint/ParseResult's
+// normal reflective plan, since implementing PointMarshaler/RowUnmarshaler
+// is optional.
+//
+// Typical usage, as a go:generate directive right above the struct:
+//
+//	//go:generate go run github.com/eachain/influx/cmd/infxgen $GOFILE
+//	//influx:generate
+//	type Metric struct {
+//		Time time.Time
+//		Host string  `inf:"host,tag"`
+//		CPU  float64 `inf:"cpu"`
+//	}
+//
+// which writes <file>_infxgen.go beside the source file (metric_infxgen.go
+// for metric.go), one file holding the methods of every marked struct
+// in it.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+const generateMarker = "influx:generate"
+
+func main() {
+	filename := os.Getenv("GOFILE")
+	if len(os.Args) > 1 {
+		filename = os.Args[1]
+	}
+	if filename == "" {
+		fmt.Fprintln(os.Stderr, "infxgen: usage: infxgen <file.go> (or run via go:generate, which sets $GOFILE)")
+		os.Exit(2)
+	}
+
+	if err := run(filename); err != nil {
+		fmt.Fprintf(os.Stderr, "infxgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(filename string) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", filename, err)
+	}
+
+	var structs []genStruct
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			if !hasGenerateMarker(gd.Doc) && !hasGenerateMarker(ts.Doc) {
+				continue
+			}
+			gs, err := newGenStruct(ts.Name.Name, st)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "infxgen: skipping %s: %v\n", ts.Name.Name, err)
+				continue
+			}
+			structs = append(structs, gs)
+		}
+	}
+
+	if len(structs) == 0 {
+		return nil
+	}
+
+	needStrconv := false
+	for _, s := range structs {
+		if s.NeedStrconv {
+			needStrconv = true
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct {
+		Package     string
+		Structs     []genStruct
+		NeedStrconv bool
+	}{Package: file.Name.Name, Structs: structs, NeedStrconv: needStrconv}); err != nil {
+		return fmt.Errorf("render template: %w", err)
+	}
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("gofmt generated code: %w\n%s", err, buf.String())
+	}
+
+	dir := filepath.Dir(filename)
+	base := strings.TrimSuffix(filepath.Base(filename), ".go")
+	outPath := filepath.Join(dir, base+"_infxgen.go")
+	return os.WriteFile(outPath, out, 0644)
+}
+
+func hasGenerateMarker(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		if strings.TrimSpace(strings.TrimPrefix(c.Text, "//")) == generateMarker {
+			return true
+		}
+	}
+	return false
+}
+
+// genField is one struct field as the MarshalPoint/UnmarshalRow
+// template needs it. Decode/Encode hold the already-rendered Go
+// expression for converting to/from the field's static type, chosen
+// once in newGenStruct instead of in the template.
+type genField struct {
+	Go        string // Go field name, e.g. "Host"
+	Name      string // inf/column name, e.g. "host"
+	IsTag     bool
+	IsTime    bool
+	Omitempty bool
+	Encode    string // Go expression for this field's tag/field value
+	Decode    string // Go expression (in terms of "val") to assign to v.Go
+}
+
+type genStruct struct {
+	Name        string
+	Measurement string
+	Fields      []genField
+	NeedStrconv bool // true if any field's Encode/Decode uses strconv
+}
+
+func newGenStruct(name string, st *ast.StructType) (genStruct, error) {
+	gs := genStruct{Name: name, Measurement: titleToSnake(name)}
+
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			return genStruct{}, fmt.Errorf("embedded field %s not supported, remove the //influx:generate marker or drop the embedding", exprString(f.Type))
+		}
+		tagstr := ""
+		if f.Tag != nil {
+			unquoted, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return genStruct{}, fmt.Errorf("invalid tag literal: %w", err)
+			}
+			tagstr = structTagGet(unquoted, "inf")
+		}
+		if tagstr == "-" {
+			continue
+		}
+		if strings.HasPrefix(tagstr, "database=") || strings.HasPrefix(tagstr, "measurement=") {
+			if strings.HasPrefix(tagstr, "measurement=") {
+				gs.Measurement = strings.TrimPrefix(tagstr, "measurement=")
+			}
+			continue
+		}
+		for _, opt := range []string{"flatten", "fields", "tags", "ns", "us", "ms", "s", "seconds", "string",
+			"unix_ns", "unix_us", "unix_ms", "unix_s"} {
+			if tagHasOption(tagstr, opt) {
+				return genStruct{}, fmt.Errorf("field %s uses the unsupported ,%s option", f.Names[0].Name, opt)
+			}
+		}
+		if strings.Contains(tagstr, "layout=") {
+			return genStruct{}, fmt.Errorf("field %s uses the unsupported ,layout= option", f.Names[0].Name)
+		}
+
+		kind := exprString(f.Type)
+
+		for _, n := range f.Names {
+			name := n.Name
+			if name == "Time" || tagstr == "time" {
+				gs.Fields = append(gs.Fields, genField{Go: name, IsTime: true})
+				continue
+			}
+
+			fname := strings.Split(tagstr, ",")[0]
+			if fname == "" {
+				fname = titleToSnake(name)
+			}
+
+			isTag := tagHasOption(tagstr, "tag")
+			boolAsInt := kind == "bool" && tagHasOption(tagstr, "01")
+
+			encode, decode, ok := fieldExprs(kind, isTag, boolAsInt, name)
+			if !ok {
+				return genStruct{}, fmt.Errorf("field %s has an unsupported type %s for codegen", name, kind)
+			}
+
+			if strings.Contains(encode, "strconv.") || strings.Contains(decode, "strconv.") {
+				gs.NeedStrconv = true
+			}
+			gs.Fields = append(gs.Fields, genField{
+				Go:        name,
+				Name:      fname,
+				IsTag:     isTag,
+				Omitempty: tagHasOption(tagstr, "omitempty"),
+				Encode:    encode,
+				Decode:    decode,
+			})
+		}
+	}
+	return gs, nil
+}
+
+// fieldExprs returns the Go expressions infxgen renders for a field of
+// kind (its Go type as source text): encode converts v.<name> to the
+// value stored in the tags/fields map, decode converts the decoded
+// "val" interface{} back to kind. ok is false for a type infxgen has no
+// special case for (a pointer, a named type, time.Duration, etc.),
+// which includes anything influx.TagString/DecodeInt64/... can't
+// already handle; the caller then falls back to reflection for that
+// whole struct.
+func fieldExprs(kind string, isTag, boolAsInt bool, name string) (encode, decode string, ok bool) {
+	field := "v." + name
+	switch {
+	case kind == "string":
+		return field, "influx.TagString(val)", true
+	case kind == "bool":
+		if isTag {
+			if boolAsInt {
+				return fmt.Sprintf("influx.BoolToIntString(%s)", field), "influx.DecodeBool(val)", true
+			}
+			return fmt.Sprintf("strconv.FormatBool(%s)", field), "influx.DecodeBool(val)", true
+		}
+		return field, "influx.DecodeBool(val)", true
+	case isIntKind(kind):
+		encode = field
+		if isTag {
+			encode = fmt.Sprintf("influx.TagString(%s)", field)
+		}
+		return encode, fmt.Sprintf("%s(influx.DecodeInt64(val))", kind), true
+	case isFloatKind(kind):
+		encode = field
+		if isTag {
+			encode = fmt.Sprintf("influx.TagString(%s)", field)
+		}
+		return encode, fmt.Sprintf("%s(influx.DecodeFloat64(val))", kind), true
+	default:
+		return "", "", false
+	}
+}
+
+func isIntKind(kind string) bool {
+	switch kind {
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64":
+		return true
+	}
+	return false
+}
+
+func isFloatKind(kind string) bool {
+	return kind == "float32" || kind == "float64"
+}
+
+// tagHasOption mirrors influx.tagHasOption: it isn't exported, so
+// infxgen keeps its own copy rather than depending on influx's
+// internals.
+func tagHasOption(tagstr, opt string) bool {
+	parts := strings.Split(tagstr, ",")
+	for _, part := range parts[1:] {
+		if part == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// titleToSnake mirrors influx.titleToSnake so infxgen derives the same
+// default measurement/field names ToPoint would without the marker.
+func titleToSnake(s string) string {
+	r := []rune(s)
+	lastIsUpper := true
+	for i := 0; i < len(r); i++ {
+		if unicode.IsUpper(r[i]) {
+			if !lastIsUpper {
+				r = append(append(r[:i:i], '_'), r[i:]...)
+				i++
+				lastIsUpper = true
+			}
+			r[i] = unicode.ToLower(r[i])
+		} else {
+			lastIsUpper = false
+		}
+	}
+	return string(r)
+}
+
+// structTagGet is reflect.StructTag.Get without needing a real
+// reflect.StructTag (the tag text here comes straight from source, not
+// a running struct).
+func structTagGet(tag, key string) string {
+	for tag != "" {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		name := tag[:i]
+		tag = tag[i+1:]
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		qvalue := tag[:i+1]
+		tag = tag[i+1:]
+		if key == name {
+			value, err := strconv.Unquote(qvalue)
+			if err != nil {
+				return ""
+			}
+			return value
+		}
+	}
+	return ""
+}
+
+func exprString(e ast.Expr) string {
+	if id, ok := e.(*ast.Ident); ok {
+		return id.Name
+	}
+	var buf bytes.Buffer
+	format.Node(&buf, token.NewFileSet(), e)
+	return buf.String()
+}
+
+var tmpl = template.Must(template.New("infxgen").Parse(`// Code generated by infxgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{if .NeedStrconv}}	"strconv"
+{{end}}	"time"
+
+	"github.com/eachain/influx"
+)
+{{range .Structs}}
+func (v *{{.Name}}) MarshalPoint() (measurement string, tags map[string]string, fields map[string]interface{}, t time.Time, err error) {
+	measurement = {{printf "%q" .Measurement}}
+	tags = make(map[string]string)
+	fields = make(map[string]interface{})
+{{range .Fields}}{{if .IsTime}}	t = v.{{.Go}}
+{{else if .IsTag}}{{if .Omitempty}}	if !influx.IsZeroValue(v.{{.Go}}) {
+		tags[{{printf "%q" .Name}}] = {{.Encode}}
+	}
+{{else}}	tags[{{printf "%q" .Name}}] = {{.Encode}}
+{{end}}{{else}}{{if .Omitempty}}	if !influx.IsZeroValue(v.{{.Go}}) {
+		fields[{{printf "%q" .Name}}] = {{.Encode}}
+	}
+{{else}}	fields[{{printf "%q" .Name}}] = {{.Encode}}
+{{end}}{{end}}{{end}}	return
+}
+
+func (v *{{.Name}}) UnmarshalRow(cols []string, vals []interface{}, tags map[string]string) error {
+	get := func(name string) (interface{}, bool) {
+		for i, c := range cols {
+			if c == name {
+				return vals[i], true
+			}
+		}
+		if s, ok := tags[name]; ok {
+			return s, true
+		}
+		return nil, false
+	}
+{{range .Fields}}{{if .IsTime}}	if val, ok := get("time"); ok {
+		if t, ok := val.(time.Time); ok {
+			v.{{.Go}} = t
+		}
+	}
+{{else}}	if val, ok := get({{printf "%q" .Name}}); ok {
+		v.{{.Go}} = {{.Decode}}
+	}
+{{end}}{{end}}	return nil
+}
+{{end}}`))
@@ -0,0 +1,189 @@
+// Command infxschema introspects an existing InfluxDB database via
+// SHOW MEASUREMENTS, SHOW FIELD KEYS and SHOW TAG KEYS, and emits a Go
+// struct definition per measurement with `inf` tags matching its actual
+// shape, so typed access (ToPoint/ParseResult, or infxgen on top of the
+// result) can be bootstrapped against a database that already has data
+// in it instead of hand-writing struct definitions from a SHOW FIELD
+// KEYS session.
+//
+// Usage:
+//
+//	go run github.com/eachain/influx/cmd/infxschema -addr http://localhost:8086 -db mydb > schema.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	influx "github.com/eachain/influx"
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+func main() {
+	addr := flag.String("addr", "http://localhost:8086", "InfluxDB HTTP address")
+	db := flag.String("db", "", "database to introspect (required)")
+	username := flag.String("username", "", "InfluxDB username")
+	password := flag.String("password", "", "InfluxDB password")
+	pkg := flag.String("package", "main", "package name for the generated file")
+	flag.Parse()
+
+	if *db == "" {
+		fmt.Fprintln(os.Stderr, "infxschema: -db is required")
+		os.Exit(2)
+	}
+
+	c, err := influx.New(client.HTTPConfig{Addr: *addr, Username: *username, Password: *password})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "infxschema: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := run(c, *db, *pkg, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "infxschema: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(c *influx.Client, db, pkg string, out *os.File) error {
+	measurements, err := c.ShowMeasurements(db)
+	if err != nil {
+		return fmt.Errorf("SHOW MEASUREMENTS on %s: %w", db, err)
+	}
+
+	var structs []genStruct
+	for _, measurement := range measurements {
+		gs, err := measurementStruct(c, db, measurement)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "infxschema: skipping %s: %v\n", measurement, err)
+			continue
+		}
+		structs = append(structs, gs)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct {
+		Package string
+		Structs []genStruct
+	}{Package: pkg, Structs: structs}); err != nil {
+		return fmt.Errorf("render template: %w", err)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("gofmt generated code: %w\n%s", err, buf.String())
+	}
+	_, err = out.Write(src)
+	return err
+}
+
+// genField is one struct field the template renders.
+type genField struct {
+	Go   string // exported Go field name
+	Name string // inf tag name, e.g. "host"
+	Type string // Go field type, e.g. "float64"
+	Tag  bool   // true for a tag field
+}
+
+// genStruct is one measurement the template renders as a struct.
+type genStruct struct {
+	TypeName    string
+	Measurement string
+	Fields      []genField
+}
+
+// measurementStruct builds measurement's genStruct from its actual
+// field and tag keys.
+func measurementStruct(c *influx.Client, db, measurement string) (genStruct, error) {
+	fieldKeys, err := c.ShowFieldKeys(db, measurement)
+	if err != nil {
+		return genStruct{}, fmt.Errorf("SHOW FIELD KEYS: %w", err)
+	}
+	tagKeys, err := c.ShowTagKeys(db, measurement)
+	if err != nil {
+		return genStruct{}, fmt.Errorf("SHOW TAG KEYS: %w", err)
+	}
+
+	gs := genStruct{
+		TypeName:    snakeToTitle(measurement),
+		Measurement: measurement,
+	}
+
+	sort.Strings(tagKeys)
+	for _, tag := range tagKeys {
+		gs.Fields = append(gs.Fields, genField{
+			Go:   snakeToTitle(tag),
+			Name: tag,
+			Type: "string",
+			Tag:  true,
+		})
+	}
+
+	sort.Slice(fieldKeys, func(i, j int) bool { return fieldKeys[i].Name < fieldKeys[j].Name })
+	for _, fk := range fieldKeys {
+		gs.Fields = append(gs.Fields, genField{
+			Go:   snakeToTitle(fk.Name),
+			Name: fk.Name,
+			Type: goFieldType(fk.Type),
+		})
+	}
+
+	return gs, nil
+}
+
+// goFieldType maps a SHOW FIELD KEYS data type to the Go type
+// infxschema declares for it, the inverse of the mapping
+// influx.CheckDrift's goKindFieldType uses in the other direction.
+func goFieldType(t influx.FieldDataType) string {
+	switch t {
+	case influx.FieldDataTypeFloat:
+		return "float64"
+	case influx.FieldDataTypeInteger:
+		return "int64"
+	case influx.FieldDataTypeUnsigned:
+		return "uint64"
+	case influx.FieldDataTypeBoolean:
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+// snakeToTitle turns a snake_case or dotted measurement/field name into
+// an exported Go identifier, e.g. "cpu_usage" -> "CpuUsage".
+func snakeToTitle(s string) string {
+	s = strings.NewReplacer("_", " ", ".", " ", "-", " ").Replace(s)
+	fields := strings.Fields(s)
+	for i, f := range fields {
+		r := []rune(f)
+		r[0] = unicode.ToUpper(r[0])
+		fields[i] = string(r)
+	}
+	name := strings.Join(fields, "")
+	if name == "" {
+		return "Measurement"
+	}
+	return name
+}
+
+var tmpl = template.Must(template.New("infxschema").Parse(`// Code generated by infxschema from the live schema. DO NOT EDIT.
+
+package {{.Package}}
+
+import "time"
+{{range .Structs}}
+// {{.TypeName}} was generated from measurement "{{.Measurement}}".
+type {{.TypeName}} struct {
+	_    struct{}  ` + "`inf:\"measurement={{.Measurement}}\"`" + `
+	Time time.Time
+{{- range .Fields}}
+	{{.Go}} {{.Type}} ` + "`inf:\"{{.Name}}{{if .Tag}},tag{{end}}\"`" + `
+{{- end}}
+}
+{{end}}`))
@@ -0,0 +1,65 @@
+package influx
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultCollectorInterval is the default RegisterCollector interval.
+const DefaultCollectorInterval = 10 * time.Second
+
+// Collector samples application state on demand, returning a slice of
+// structs or pointers to structs, the same shape InsertMany accepts.
+// An empty slice means there is nothing to report this tick.
+type Collector func() []interface{}
+
+// RegisterCollector runs collect every interval on its own background
+// goroutine, writing whatever it returns to db via InsertManyContext, a
+// lightweight in-process substitute for running a separate Telegraf
+// exec/http input just to sample application state (goroutine counts,
+// cache sizes, queue depths...). interval defaults to
+// DefaultCollectorInterval. Errors from InsertManyContext are recorded
+// the same way any other write failure is; see Stats.
+//
+// RegisterCollector may be called any number of times to schedule
+// several independent collectors; each runs until Close.
+func (c *Client) RegisterCollector(db string, interval time.Duration, collect Collector) {
+	if interval <= 0 {
+		interval = DefaultCollectorInterval
+	}
+
+	stop := make(chan struct{})
+	c.mu.Lock()
+	c.collectorStops = append(c.collectorStops, stop)
+	c.mu.Unlock()
+
+	go c.collectorLoop(db, interval, collect, stop)
+}
+
+func (c *Client) collectorLoop(db string, interval time.Duration, collect Collector, stop chan struct{}) {
+	ticker := newTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C():
+			c.runCollector(db, collect)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// runCollector samples collect once and writes the result, if any.
+func (c *Client) runCollector(db string, collect Collector) {
+	samples := collect()
+	if len(samples) == 0 {
+		return
+	}
+	c.InsertManyContext(context.Background(), db, samples)
+}
+
+// RegisterCollector schedules collect on the package-level default
+// Client.
+func RegisterCollector(db string, interval time.Duration, collect Collector) {
+	gClient().RegisterCollector(db, interval, collect)
+}
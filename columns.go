@@ -0,0 +1,60 @@
+package influx
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// ParseColumns splits serie column-wise into dsts, one pointer-to-slice
+// destination per column (or tag) name, decoding every row's value for
+// that name into the pointed-to slice's element type in a single pass —
+// the column-major shape plotting code wants (every x value, then every
+// y value), with each column free to decode into its own type instead
+// of ParseResult into a map[string][]T forcing one element type for
+// all of them.
+func ParseColumns(serie models.Row, dsts map[string]interface{}) error {
+	tags := serie.Tags
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+
+	var missing []string
+	for col := range dsts {
+		if _, ok := tags[col]; !ok && !inColumns(col, serie.Columns) {
+			missing = append(missing, col)
+		}
+	}
+	if len(missing) > 0 {
+		return &MissingColumnsError{Columns: missing}
+	}
+
+	for col, dst := range dsts {
+		dstVal := reflect.Indirect(reflect.ValueOf(dst))
+		if !dstVal.CanSet() {
+			return ErrNotSettable
+		}
+		if dstVal.Kind() != reflect.Slice {
+			return fmt.Errorf("influx: ParseColumns dst for column %q must be a pointer to a slice", col)
+		}
+
+		itemType := dstVal.Type().Elem()
+		idx := columnIndex(col, serie.Columns)
+		sl := reflect.MakeSlice(dstVal.Type(), len(serie.Values), len(serie.Values))
+		for r, vs := range serie.Values {
+			var val interface{} = tags[col]
+			if idx >= 0 {
+				val = vs[idx]
+			}
+
+			item := reflect.New(itemType).Elem()
+			if err := parseSingle(serie.Columns, vs, tags, item, col); err != nil {
+				return decodeErr(err, r, col, "", val)
+			}
+			sl.Index(r).Set(item)
+		}
+		dstVal.Set(sl)
+	}
+	return nil
+}
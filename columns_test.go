@@ -0,0 +1,85 @@
+package influx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// TestParseColumnsSplitsIntoTypedSlices confirms ParseColumns decodes
+// each named column into its own destination slice, each free to use a
+// different element type, and that an unknown column name reports
+// MissingColumnsError instead of silently leaving its slice empty.
+func TestParseColumnsSplitsIntoTypedSlices(t *testing.T) {
+	row := models.Row{
+		Columns: []string{"time", "mean", "max"},
+		Values: [][]interface{}{
+			{"2023-01-02T03:04:05Z", float64(1.5), float64(3)},
+			{"2023-01-02T03:05:05Z", float64(2.5), float64(4)},
+		},
+	}
+
+	var times []time.Time
+	var means []float64
+	var maxes []float64
+	err := ParseColumns(row, map[string]interface{}{
+		"time": &times,
+		"mean": &means,
+		"max":  &maxes,
+	})
+	if err != nil {
+		t.Fatalf("ParseColumns: %v", err)
+	}
+
+	wantTime0, _ := time.Parse(time.RFC3339, "2023-01-02T03:04:05Z")
+	if len(times) != 2 || !times[0].Equal(wantTime0) {
+		t.Fatalf("times = %v, want [%v ...]", times, wantTime0)
+	}
+	if len(means) != 2 || means[0] != 1.5 || means[1] != 2.5 {
+		t.Fatalf("means = %v, want [1.5 2.5]", means)
+	}
+	if len(maxes) != 2 || maxes[0] != 3 || maxes[1] != 4 {
+		t.Fatalf("maxes = %v, want [3 4]", maxes)
+	}
+
+	var unknown []float64
+	err = ParseColumns(row, map[string]interface{}{"bogus": &unknown})
+	mcerr, ok := err.(*MissingColumnsError)
+	if !ok {
+		t.Fatalf("err = %v (%T), want *MissingColumnsError", err, err)
+	}
+	if want := []string{"bogus"}; len(mcerr.Columns) != 1 || mcerr.Columns[0] != want[0] {
+		t.Fatalf("MissingColumnsError.Columns = %v, want %v", mcerr.Columns, want)
+	}
+}
+
+// TestParseColumnsReadsTagColumn confirms a column name naming a series
+// tag rather than a row column decodes too, repeating the tag's value
+// once per row.
+func TestParseColumnsReadsTagColumn(t *testing.T) {
+	row := models.Row{
+		Tags:    map[string]string{"host": "a"},
+		Columns: []string{"usage"},
+		Values: [][]interface{}{
+			{float64(0.5)},
+			{float64(0.75)},
+		},
+	}
+
+	var hosts []string
+	var usages []float64
+	err := ParseColumns(row, map[string]interface{}{
+		"host":  &hosts,
+		"usage": &usages,
+	})
+	if err != nil {
+		t.Fatalf("ParseColumns: %v", err)
+	}
+	if len(hosts) != 2 || hosts[0] != "a" || hosts[1] != "a" {
+		t.Fatalf("hosts = %v, want [a a]", hosts)
+	}
+	if len(usages) != 2 || usages[0] != 0.5 || usages[1] != 0.75 {
+		t.Fatalf("usages = %v, want [0.5 0.75]", usages)
+	}
+}
@@ -0,0 +1,213 @@
+package influx
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Converter converts a Go value to and from the representation InfluxDB
+// understands. Register one with RegisterConverter for types this
+// package cannot teach to implement sql.Scanner, driver.Valuer or
+// encoding.TextMarshaler/TextUnmarshaler directly, such as types
+// defined in another module.
+type Converter struct {
+	To   func(interface{}) (interface{}, error)
+	From func(interface{}) (interface{}, error)
+}
+
+var converters sync.Map // reflect.Type -> Converter
+
+// RegisterConverter registers to/from conversion funcs for t, letting
+// ToPoint and ParseResult round-trip types this package doesn't own.
+// It may be called after t has already been processed by ToPoint,
+// ParseResult or Register: it drops the cached plan for t, and every
+// cached struct plan (whose field setters were chosen from t's old
+// plan), so the next call picks up the new converter instead of the
+// stale cache entry.
+func RegisterConverter(t reflect.Type, to func(interface{}) (interface{}, error), from func(interface{}) (interface{}, error)) {
+	converters.Store(t, Converter{To: to, From: from})
+	convPlans.Delete(t)
+	resetTypePlans()
+}
+
+var (
+	scannerType         = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType          = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	stringerType        = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	weekdayType         = reflect.TypeOf(time.Sunday)
+	rowUnmarshalerType  = reflect.TypeOf((*RowUnmarshaler)(nil)).Elem()
+)
+
+// weekdayNames maps a time.Weekday's name, as produced by its String
+// method, back to the Weekday itself, for decodeField: time.Weekday
+// implements fmt.Stringer but not encoding.TextUnmarshaler, so there's
+// no method to call for the reverse direction.
+var weekdayNames = map[string]time.Weekday{
+	time.Sunday.String():    time.Sunday,
+	time.Monday.String():    time.Monday,
+	time.Tuesday.String():   time.Tuesday,
+	time.Wednesday.String(): time.Wednesday,
+	time.Thursday.String():  time.Thursday,
+	time.Friday.String():    time.Friday,
+	time.Saturday.String():  time.Saturday,
+}
+
+// isPrimitiveKind reports whether k is one of the kinds a field or tag
+// value is already encoded as without help (bool, the integer/float
+// kinds, string), so a custom type with one of these as its underlying
+// kind, e.g. a `type Status int` enum, isn't what this package means by
+// "can't be written as a field", even if it also implements
+// fmt.Stringer; that's the non-primitive (typically struct) case below.
+func isPrimitiveKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// convPlan records, once per reflect.Type, which of the pluggable
+// conversion mechanisms apply to that type so parseSingle and ToPoint
+// don't have to re-run the same interface assertions on every field of
+// every row or point.
+type convPlan struct {
+	converter     *Converter
+	valuer        bool
+	textMarshal   bool
+	stringer      bool
+	weekday       bool
+	scanner       bool
+	textUnmarshal bool
+	jsonUnmarshal bool
+}
+
+var convPlans sync.Map // reflect.Type -> *convPlan
+
+func planConv(t reflect.Type) *convPlan {
+	if v, ok := convPlans.Load(t); ok {
+		return v.(*convPlan)
+	}
+	p := &convPlan{}
+	if c, ok := converters.Load(t); ok {
+		conv := c.(Converter)
+		p.converter = &conv
+	}
+	p.valuer = t.Implements(valuerType)
+	p.textMarshal = t.Implements(textMarshalerType)
+	p.stringer = !isPrimitiveKind(t.Kind()) && t.Implements(stringerType)
+	p.weekday = t == weekdayType
+	pt := reflect.PtrTo(t)
+	p.scanner = pt.Implements(scannerType)
+	p.textUnmarshal = pt.Implements(textUnmarshalerType)
+	p.jsonUnmarshal = pt.Implements(jsonUnmarshalerType)
+	actual, _ := convPlans.LoadOrStore(t, p)
+	return actual.(*convPlan)
+}
+
+// encodeField converts fv to a value ToPoint can hand to
+// client.NewPoint, honoring a registered Converter, driver.Valuer,
+// encoding.TextMarshaler and fmt.Stringer in that order. The Stringer
+// case only applies to a non-primitive-kind type (typically a struct),
+// since a primitive-kind custom type (e.g. a `type Status int` enum)
+// already carries a usable value without needing its String form, and
+// converting it would lose the numeric/bool/string value a caller might
+// rely on for math or comparisons downstream. time.Weekday is the one
+// deliberate exception to that rule: it's carved out below so it still
+// round-trips as "Monday" rather than a bare int. ok is false when none
+// apply and fv should be used as-is.
+func encodeField(fv reflect.Value) (out interface{}, ok bool, err error) {
+	plan := planConv(fv.Type())
+	switch {
+	case plan.converter != nil:
+		out, err = plan.converter.To(fv.Interface())
+	case plan.valuer:
+		out, err = fv.Interface().(driver.Valuer).Value()
+	case plan.textMarshal:
+		var text []byte
+		text, err = fv.Interface().(encoding.TextMarshaler).MarshalText()
+		out = string(text)
+	case plan.weekday, plan.stringer:
+		out = fv.Interface().(fmt.Stringer).String()
+	default:
+		return nil, false, nil
+	}
+	return out, true, err
+}
+
+// decodeField converts val into dst using a registered Converter,
+// sql.Scanner or encoding.TextUnmarshaler/json.Unmarshaler, in that
+// order. ok is false when none apply and dst should be decoded by the
+// built-in reflect.Kind switch in parseSingle instead.
+func decodeField(dst reflect.Value, val interface{}) (ok bool, err error) {
+	if !dst.CanAddr() {
+		return false, nil
+	}
+	plan := planConv(dst.Type())
+	raw := func() []byte {
+		switch v := val.(type) {
+		case []byte:
+			return v
+		case string:
+			return []byte(v)
+		case json.Number:
+			// The exact digits InfluxDB sent, not a round trip through
+			// float64 — the only form precise enough for a math/big
+			// destination holding a counter too large for float64.
+			return []byte(v.String())
+		case float32:
+			// parseString's 'E' formatting round-trips through
+			// strconv.FormatFloat fine for a plain string field, but a
+			// TextUnmarshaler like big.Int's rejects exponent notation
+			// outright; 'f' keeps it a plain decimal.
+			return []byte(strconv.FormatFloat(float64(v), 'f', -1, 32))
+		case float64:
+			return []byte(strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			return []byte(parseString(val))
+		}
+	}
+	switch {
+	case plan.converter != nil:
+		var v interface{}
+		v, err = plan.converter.From(val)
+		if err == nil {
+			dst.Set(reflect.ValueOf(v).Convert(dst.Type()))
+		}
+	case plan.weekday:
+		name := parseString(val)
+		wd, known := weekdayNames[name]
+		if !known {
+			return true, fmt.Errorf("influx: %q is not a weekday name", name)
+		}
+		dst.SetInt(int64(wd))
+	case plan.scanner:
+		err = dst.Addr().Interface().(sql.Scanner).Scan(val)
+	case dst.Type() == timeType:
+		// time.Time implements encoding.TextUnmarshaler/json.Unmarshaler
+		// itself, but both insist on RFC3339; leave it to parseSingle's
+		// struct-kind case instead, which goes through parseTime and so
+		// honors RegisterTimeLayout and the epoch/json.Number forms
+		// UnmarshalText doesn't understand.
+		return false, nil
+	case plan.textUnmarshal:
+		err = dst.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText(raw())
+	case plan.jsonUnmarshal:
+		err = dst.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(raw())
+	default:
+		return false, nil
+	}
+	return true, err
+}
@@ -0,0 +1,115 @@
+package influx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CopyMeasurementOptions configures CopyMeasurement.
+type CopyMeasurementOptions struct {
+	// Src is the source measurement, e.g. `"downsample_1h"."cpu"` for a
+	// retention-policy-qualified source.
+	Src string
+	// Dst is the destination measurement.
+	Dst string
+	// Where, if non-empty, is ANDed onto each slice's time bound.
+	Where string
+
+	// Start and End bound the whole copy; End is exclusive.
+	Start, End time.Time
+	// SliceSize is the time span each INTO query covers. It must be
+	// positive — this is what keeps any one query small enough not to
+	// time out, the same reason Backfill slices its own queries.
+	SliceSize time.Duration
+
+	// Progress, if non-nil, is called after every slice's query, in
+	// order, with that slice's bounds and its error (nil on success),
+	// so a long copy can report where it is and a caller can resume a
+	// resumable copy right where it left off.
+	Progress func(start, end time.Time, err error)
+}
+
+// CopyMeasurement copies opts.Src into opts.Dst over [opts.Start,
+// opts.End) a SliceSize-wide time slice at a time, via chunked SELECT *
+// INTO statements — the same slicing Backfill uses, for the same
+// reason: a single INTO query spanning a large time range regularly
+// times out rather than completing slowly. It stops and returns the
+// first slice's error, if any — opts.Progress has already been told
+// about it by then.
+//
+// InfluxDB has no native rename or move for a measurement; to rename
+// one, CopyMeasurement it to the new name and then DropMeasurement the
+// old one once every slice has succeeded.
+func (c *Client) CopyMeasurement(db string, opts CopyMeasurementOptions) error {
+	return c.CopyMeasurementContext(context.Background(), db, opts)
+}
+
+// CopyMeasurementContext is CopyMeasurement with a context that aborts
+// the current slice's request as soon as it is canceled.
+func (c *Client) CopyMeasurementContext(ctx context.Context, db string, opts CopyMeasurementOptions) error {
+	if opts.SliceSize <= 0 {
+		return errors.New("influx: CopyMeasurement requires a positive SliceSize")
+	}
+	for start := opts.Start; start.Before(opts.End); start = start.Add(opts.SliceSize) {
+		end := start.Add(opts.SliceSize)
+		if end.After(opts.End) {
+			end = opts.End
+		}
+
+		_, err := c.QueryContext(ctx, db, copyMeasurementCmd(opts, start, end))
+		if opts.Progress != nil {
+			opts.Progress(start, end, err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyMeasurementCmd builds one slice's SELECT * INTO ... FROM ...
+// statement.
+func copyMeasurementCmd(opts CopyMeasurementOptions, start, end time.Time) string {
+	cmd := fmt.Sprintf("SELECT * INTO %s FROM %s WHERE %s", opts.Dst, opts.Src, Between(start, end))
+	if opts.Where != "" {
+		cmd += " AND " + opts.Where
+	}
+	return cmd
+}
+
+// DropMeasurement drops measurement from db, e.g. to remove the
+// original after a CopyMeasurement-based rename.
+func (c *Client) DropMeasurement(db, measurement string) error {
+	return c.DropMeasurementContext(context.Background(), db, measurement)
+}
+
+// DropMeasurementContext is DropMeasurement with a context that aborts
+// the request as soon as it is canceled.
+func (c *Client) DropMeasurementContext(ctx context.Context, db, measurement string) error {
+	_, err := c.QueryContext(ctx, db, "DROP MEASUREMENT "+Ident(measurement))
+	return err
+}
+
+// CopyMeasurement copies a measurement using the default Client.
+func CopyMeasurement(db string, opts CopyMeasurementOptions) error {
+	return gClient().CopyMeasurement(db, opts)
+}
+
+// CopyMeasurementContext is CopyMeasurement with a context, using the
+// default Client.
+func CopyMeasurementContext(ctx context.Context, db string, opts CopyMeasurementOptions) error {
+	return gClient().CopyMeasurementContext(ctx, db, opts)
+}
+
+// DropMeasurement drops a measurement using the default Client.
+func DropMeasurement(db, measurement string) error {
+	return gClient().DropMeasurement(db, measurement)
+}
+
+// DropMeasurementContext is DropMeasurement with a context, using the
+// default Client.
+func DropMeasurementContext(ctx context.Context, db, measurement string) error {
+	return gClient().DropMeasurementContext(ctx, db, measurement)
+}
@@ -0,0 +1,94 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+)
+
+// ContinuousQuery is one row of SHOW CONTINUOUS QUERIES, as returned by
+// ListContinuousQueries.
+type ContinuousQuery struct {
+	Database string `inf:"database"`
+	Name     string `inf:"name"`
+	Query    string `inf:"query"`
+}
+
+// CreateContinuousQuery runs CREATE CONTINUOUS QUERY on db, wrapping
+// query (the SELECT ... INTO ... FROM ... GROUP BY time(...) body,
+// without BEGIN/END) the way Migrate's struct-tag-driven CQs already
+// are, so a downsampling CQ can be provisioned from application
+// startup code instead of a manual influx CLI session.
+func (c *Client) CreateContinuousQuery(db, name, query string) error {
+	return c.CreateContinuousQueryContext(context.Background(), db, name, query)
+}
+
+// CreateContinuousQueryContext is CreateContinuousQuery with a context
+// that aborts the request as soon as it is canceled.
+func (c *Client) CreateContinuousQueryContext(ctx context.Context, db, name, query string) error {
+	cmd := fmt.Sprintf("CREATE CONTINUOUS QUERY %s ON %s BEGIN %s END", Ident(name), Ident(db), query)
+	_, err := c.QueryContext(ctx, db, cmd)
+	return err
+}
+
+// DropContinuousQuery drops the continuous query name on db.
+func (c *Client) DropContinuousQuery(db, name string) error {
+	return c.DropContinuousQueryContext(context.Background(), db, name)
+}
+
+// DropContinuousQueryContext is DropContinuousQuery with a context
+// that aborts the request as soon as it is canceled.
+func (c *Client) DropContinuousQueryContext(ctx context.Context, db, name string) error {
+	cmd := fmt.Sprintf("DROP CONTINUOUS QUERY %s ON %s", Ident(name), Ident(db))
+	_, err := c.QueryContext(ctx, db, cmd)
+	return err
+}
+
+// ListContinuousQueries runs SHOW CONTINUOUS QUERIES and decodes every
+// row into a ContinuousQuery. InfluxDB returns one series per database,
+// named after it, so this copies that name into each row's Database
+// field and flattens every series into one slice, the same way
+// ShowSubscriptions does for SHOW SUBSCRIPTIONS.
+func (c *Client) ListContinuousQueries() ([]ContinuousQuery, error) {
+	return c.ListContinuousQueriesContext(context.Background())
+}
+
+// ListContinuousQueriesContext is ListContinuousQueries with a context
+// that aborts the request as soon as it is canceled.
+func (c *Client) ListContinuousQueriesContext(ctx context.Context) ([]ContinuousQuery, error) {
+	var cqs []ContinuousQuery
+	results, err := c.QueryContext(ctx, "", "SHOW CONTINUOUS QUERIES")
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		for _, serie := range result.Series {
+			var rows []ContinuousQuery
+			if err := ParseResult(&rows, serie); err != nil {
+				return nil, err
+			}
+			for i := range rows {
+				rows[i].Database = serie.Name
+			}
+			cqs = append(cqs, rows...)
+		}
+	}
+	return cqs, nil
+}
+
+// CreateContinuousQuery creates a continuous query using the default
+// Client.
+func CreateContinuousQuery(db, name, query string) error {
+	return gClient().CreateContinuousQuery(db, name, query)
+}
+
+// DropContinuousQuery drops a continuous query using the default
+// Client.
+func DropContinuousQuery(db, name string) error {
+	return gClient().DropContinuousQuery(db, name)
+}
+
+// ListContinuousQueries runs SHOW CONTINUOUS QUERIES using the default
+// Client.
+func ListContinuousQueries() ([]ContinuousQuery, error) {
+	return gClient().ListContinuousQueries()
+}
@@ -0,0 +1,70 @@
+package influx
+
+import (
+	"errors"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// CredentialsProvider returns the InfluxDB username and password to
+// authenticate with, consulted before every query and write so
+// short-lived credentials (e.g. issued by Vault) can rotate without
+// restarting the process.
+type CredentialsProvider func() (username, password string, err error)
+
+// RotateCredentials makes c consult provider before every query and
+// write, rebuilding its underlying HTTP client whenever the returned
+// username or password changes from what it was built with.
+// client.HTTPConfig bakes Username/Password into the client at
+// construction with no hook to change them per request, so rotation is
+// implemented by rebuilding the client, the same way AutoReconnect
+// rebuilds it after a connection failure. It only applies to Clients
+// built with New.
+func (c *Client) RotateCredentials(provider CredentialsProvider) error {
+	if c.cfg == nil {
+		return errors.New("influx: RotateCredentials needs a Client built with New")
+	}
+	c.credentials = provider
+	return nil
+}
+
+// applyCredentials consults c.credentials, if set, rebuilding c.cli if
+// the username or password it returns differs from c.cfg's.
+func (c *Client) applyCredentials() error {
+	if c.credentials == nil {
+		return nil
+	}
+	username, password, err := c.credentials()
+	if err != nil {
+		return err
+	}
+
+	c.mu.RLock()
+	changed := username != c.cfg.Username || password != c.cfg.Password
+	c.mu.RUnlock()
+	if !changed {
+		return nil
+	}
+
+	cfg := *c.cfg
+	cfg.Username = username
+	cfg.Password = password
+	newCli, err := client.NewHTTPClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	old := c.cli
+	c.cli = newCli
+	c.cfg = &cfg
+	c.mu.Unlock()
+
+	return old.Close()
+}
+
+// RotateCredentials makes the package-level default Client consult
+// provider before every query and write.
+func RotateCredentials(provider CredentialsProvider) error {
+	return gClient().RotateCredentials(provider)
+}
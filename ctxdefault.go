@@ -0,0 +1,49 @@
+package influx
+
+import "context"
+
+// dbKey is the context.Context key WithDatabaseContext stores a
+// database name under, read back by DatabaseFromContext.
+type dbKey struct{}
+
+// rpKey is the context.Context key WithRetentionPolicyContext stores a
+// retention policy under, read back by RetentionPolicyFromContext.
+type rpKey struct{}
+
+// WithDatabaseContext returns a copy of ctx carrying db, so middleware
+// can pin a request's database once and let every context-aware
+// Insert*/Query* call resolve it from ctx instead of threading db
+// through every call site by hand. An explicit db argument to those
+// calls still wins over ctx, the same as WithTenant. Named
+// WithDatabaseContext rather than WithDatabase since the latter is
+// already an Option for With.
+func WithDatabaseContext(ctx context.Context, db string) context.Context {
+	return context.WithValue(ctx, dbKey{}, db)
+}
+
+// DatabaseFromContext returns the database WithDatabaseContext stored
+// in ctx, or "" if none was stored.
+func DatabaseFromContext(ctx context.Context) string {
+	db, _ := ctx.Value(dbKey{}).(string)
+	return db
+}
+
+// WithRetentionPolicyContext returns a copy of ctx carrying rp, so
+// middleware can pin a request's retention policy once and let every
+// context-aware Insert*/Query* call resolve it from ctx instead of an
+// InsertOptions override at every call site. It takes precedence over
+// a resolved TenantResolver's RetentionPolicy and the Client's own
+// RetentionPolicy, but not an explicit per-call override (e.g.
+// InsertOptions.RetentionPolicy, InsertRP's rp argument). Named
+// WithRetentionPolicyContext rather than WithRetentionPolicy since the
+// latter is already an Option for With.
+func WithRetentionPolicyContext(ctx context.Context, rp string) context.Context {
+	return context.WithValue(ctx, rpKey{}, rp)
+}
+
+// RetentionPolicyFromContext returns the retention policy
+// WithRetentionPolicyContext stored in ctx, or "" if none was stored.
+func RetentionPolicyFromContext(ctx context.Context) string {
+	rp, _ := ctx.Value(rpKey{}).(string)
+	return rp
+}
@@ -0,0 +1,57 @@
+package influx
+
+import (
+	"context"
+	"sync"
+)
+
+// ContextTagExtractor pulls a tag's value out of ctx, returning
+// ok=false when ctx carries nothing for it so ToPointContext leaves the
+// tag out instead of writing "".
+type ContextTagExtractor func(ctx context.Context) (value string, ok bool)
+
+var (
+	ctxTagsMu   sync.Mutex
+	ctxTagNames []string
+	ctxTagFuncs map[string]ContextTagExtractor
+)
+
+// RegisterContextTag registers extractor to supply tag name's value
+// from the context.Context passed to ToPointContext/ToPointNamedContext
+// (and so InsertStructContext and its Context-suffixed siblings), for a
+// correlation tag like a trace ID, tenant or request route that every
+// producer would otherwise have to thread through its struct by hand.
+// Calling it again for the same name replaces the previous extractor.
+// A struct's own tag of the same name (via a plain inf tag or
+// TagOverrider) still wins over the extracted value.
+func RegisterContextTag(name string, extractor ContextTagExtractor) {
+	ctxTagsMu.Lock()
+	defer ctxTagsMu.Unlock()
+	if ctxTagFuncs == nil {
+		ctxTagFuncs = make(map[string]ContextTagExtractor)
+	}
+	if _, exists := ctxTagFuncs[name]; !exists {
+		ctxTagNames = append(ctxTagNames, name)
+	}
+	ctxTagFuncs[name] = extractor
+}
+
+// contextTags runs every registered extractor against ctx, returning
+// only the ones that had a value for it.
+func contextTags(ctx context.Context) map[string]string {
+	ctxTagsMu.Lock()
+	names := append([]string(nil), ctxTagNames...)
+	funcs := ctxTagFuncs
+	ctxTagsMu.Unlock()
+
+	if len(names) == 0 {
+		return nil
+	}
+	tags := make(map[string]string, len(names))
+	for _, name := range names {
+		if value, ok := funcs[name](ctx); ok {
+			tags[name] = value
+		}
+	}
+	return tags
+}
@@ -0,0 +1,95 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+)
+
+// CreateDatabaseOptions overrides the retention policy CREATE DATABASE
+// implicitly creates along with the database. A zero value for any
+// field omits that clause, falling back to InfluxDB's own default.
+type CreateDatabaseOptions struct {
+	Duration        string
+	ShardDuration   string
+	Replication     int
+	RetentionPolicy string
+}
+
+// CreateDatabase runs CREATE DATABASE name, so a service can
+// self-provision its database on first boot instead of requiring it be
+// created out of band.
+func (c *Client) CreateDatabase(name string, opts CreateDatabaseOptions) error {
+	return c.CreateDatabaseContext(context.Background(), name, opts)
+}
+
+// CreateDatabaseContext is CreateDatabase with a context that aborts
+// the request as soon as it is canceled.
+func (c *Client) CreateDatabaseContext(ctx context.Context, name string, opts CreateDatabaseOptions) error {
+	cmd := "CREATE DATABASE " + Ident(name)
+	if opts.Duration != "" {
+		cmd += " WITH DURATION " + opts.Duration
+	}
+	if opts.ShardDuration != "" {
+		cmd += " SHARD DURATION " + opts.ShardDuration
+	}
+	if opts.Replication != 0 {
+		cmd += fmt.Sprintf(" REPLICATION %d", opts.Replication)
+	}
+	if opts.RetentionPolicy != "" {
+		cmd += " NAME " + Ident(opts.RetentionPolicy)
+	}
+	_, err := c.QueryContext(ctx, "", cmd)
+	return err
+}
+
+// DropDatabase runs DROP DATABASE name.
+func (c *Client) DropDatabase(name string) error {
+	return c.DropDatabaseContext(context.Background(), name)
+}
+
+// DropDatabaseContext is DropDatabase with a context that aborts the
+// request as soon as it is canceled.
+func (c *Client) DropDatabaseContext(ctx context.Context, name string) error {
+	_, err := c.QueryContext(ctx, "", "DROP DATABASE "+Ident(name))
+	return err
+}
+
+// ShowDatabases runs SHOW DATABASES and returns every database name.
+func (c *Client) ShowDatabases() ([]string, error) {
+	return c.ShowDatabasesContext(context.Background())
+}
+
+// ShowDatabasesContext is ShowDatabases with a context that aborts the
+// request as soon as it is canceled.
+func (c *Client) ShowDatabasesContext(ctx context.Context) ([]string, error) {
+	var names []string
+	results, err := c.QueryContext(ctx, "", "SHOW DATABASES")
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		for _, serie := range result.Series {
+			var rows []string
+			if err := ParseResult(&rows, serie); err != nil {
+				return nil, err
+			}
+			names = append(names, rows...)
+		}
+	}
+	return names, nil
+}
+
+// CreateDatabase creates a database using the default Client.
+func CreateDatabase(name string, opts CreateDatabaseOptions) error {
+	return gClient().CreateDatabase(name, opts)
+}
+
+// DropDatabase drops a database using the default Client.
+func DropDatabase(name string) error {
+	return gClient().DropDatabase(name)
+}
+
+// ShowDatabases lists every database using the default Client.
+func ShowDatabases() ([]string, error) {
+	return gClient().ShowDatabases()
+}
@@ -0,0 +1,85 @@
+package influx
+
+import (
+	"context"
+	"reflect"
+)
+
+// Databaser lets a mapped struct declare which database InsertStruct
+// routes it to, overriding a `database=...` inf tag on one of its
+// fields (e.g. `X struct{} "inf:\"database=metrics\""`) the same way
+// Measurementer overrides ToPoint's default measurement name.
+type Databaser interface {
+	Database() string
+}
+
+// structDatabase resolves the database structure routes to: its
+// Databaser method if it implements one, else its `database=` inf tag,
+// else "".
+func structDatabase(structure interface{}) string {
+	if d, ok := structure.(Databaser); ok {
+		return d.Database()
+	}
+	t := reflect.Indirect(reflect.ValueOf(structure)).Type()
+	return planType(t).database
+}
+
+// InsertStruct converts structure to a point with ToPoint and writes it
+// to the database structure routes to (see Databaser), falling back to
+// c.DB if it routes to none, so a multi-database service doesn't have
+// to thread a db string alongside every struct it writes. opts, e.g.
+// Omit, can drop specific fields or tags from this write without
+// changing the struct.
+func (c *Client) InsertStruct(structure interface{}, opts ...FieldOption) error {
+	return c.InsertStructContext(context.Background(), structure, opts...)
+}
+
+// InsertStructContext is InsertStruct with a context that aborts the
+// request as soon as it is canceled.
+func (c *Client) InsertStructContext(ctx context.Context, structure interface{}, opts ...FieldOption) error {
+	point, err := ToPointContext(ctx, structure, opts...)
+	if err != nil {
+		return err
+	}
+	return c.InsertWithOptionsContext(ctx, structDatabase(structure), point, InsertOptions{})
+}
+
+// InsertStruct writes structure to the database it routes to, using the
+// package-level default Client.
+func InsertStruct(structure interface{}, opts ...FieldOption) error {
+	return gClient().InsertStruct(structure, opts...)
+}
+
+// InsertStructContext is InsertStruct with a context that aborts the
+// request as soon as it is canceled.
+func InsertStructContext(ctx context.Context, structure interface{}, opts ...FieldOption) error {
+	return gClient().InsertStructContext(ctx, structure, opts...)
+}
+
+// InsertStructNamed is InsertStruct, but writes to measurement instead
+// of whatever structure would otherwise derive (see ToPointNamed).
+func (c *Client) InsertStructNamed(measurement string, structure interface{}, opts ...FieldOption) error {
+	return c.InsertStructNamedContext(context.Background(), measurement, structure, opts...)
+}
+
+// InsertStructNamedContext is InsertStructNamed with a context that
+// aborts the request as soon as it is canceled.
+func (c *Client) InsertStructNamedContext(ctx context.Context, measurement string, structure interface{}, opts ...FieldOption) error {
+	point, err := ToPointNamedContext(ctx, measurement, structure, opts...)
+	if err != nil {
+		return err
+	}
+	return c.InsertWithOptionsContext(ctx, structDatabase(structure), point, InsertOptions{})
+}
+
+// InsertStructNamed writes structure to measurement, using the
+// package-level default Client.
+func InsertStructNamed(measurement string, structure interface{}, opts ...FieldOption) error {
+	return gClient().InsertStructNamed(measurement, structure, opts...)
+}
+
+// InsertStructNamedContext is InsertStructNamed with a context that
+// aborts the request as soon as it is canceled.
+func InsertStructNamedContext(ctx context.Context, measurement string, structure interface{}, opts ...FieldOption) error {
+	return gClient().InsertStructNamedContext(ctx, measurement, structure, opts...)
+}
@@ -0,0 +1,65 @@
+package influx
+
+import (
+	"encoding/json"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// DefaultDebugMaxBodySize bounds DebugHookConfig.OnResponse's body
+// argument when MaxBodySize is <= 0.
+const DefaultDebugMaxBodySize = 64 << 10 // 64KiB
+
+// DebugHookConfig configures the debug hook set by SetDebugHook.
+type DebugHookConfig struct {
+	// MaxBodySize caps OnResponse's body argument, in bytes. <= 0 uses
+	// DefaultDebugMaxBodySize.
+	MaxBodySize int
+
+	// OnResponse is called after every query with the raw InfluxQL
+	// command and the response body, truncated to MaxBodySize, so a
+	// decoding bug can be reproduced from a production capture without
+	// modifying this package. Because the underlying client/v2 library
+	// decodes the HTTP response itself and discards the original
+	// bytes, body is the decoded response re-marshaled to JSON —
+	// structurally equivalent to what InfluxDB sent, though not
+	// guaranteed byte-for-byte (object key order, whitespace). A
+	// query that fails before a response is ever decoded (a transport
+	// error) calls OnResponse with a nil body; err is the same error
+	// the query itself returned.
+	OnResponse func(cmd string, body []byte, err error)
+}
+
+// SetDebugHook sets the hook cfg.OnResponse is called through for
+// every query QueryContext runs. Call with a zero DebugHookConfig (a
+// nil OnResponse) to disable it.
+func (c *Client) SetDebugHook(cfg DebugHookConfig) {
+	if cfg.OnResponse == nil {
+		c.debugHook = nil
+		return
+	}
+	c.debugHook = &cfg
+}
+
+// reportDebug re-marshals response (nil on a transport failure) and
+// calls c.debugHook.OnResponse with it, truncated to its MaxBodySize.
+// The caller has already checked c.debugHook is non-nil.
+func (c *Client) reportDebug(cmd string, response *client.Response, err error) {
+	var body []byte
+	if response != nil {
+		body, _ = json.Marshal(response)
+		maxSize := c.debugHook.MaxBodySize
+		if maxSize <= 0 {
+			maxSize = DefaultDebugMaxBodySize
+		}
+		if len(body) > maxSize {
+			body = body[:maxSize]
+		}
+	}
+	c.debugHook.OnResponse(cmd, body, err)
+}
+
+// SetDebugHook sets the debug hook on the default Client.
+func SetDebugHook(cfg DebugHookConfig) {
+	gClient().SetDebugHook(cfg)
+}
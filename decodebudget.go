@@ -0,0 +1,104 @@
+package influx
+
+import (
+	"errors"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// decodeMemoryBudget bounds, in bytes, how much a single decode call
+// (ParseResult, ParseResults, or StreamRows) may allocate for its
+// result, set by SetDecodeMemoryBudget. 0, the default, means
+// unbounded.
+var decodeMemoryBudget int64
+
+// SetDecodeMemoryBudget caps how much a single decode call may
+// allocate, estimated as its row count times one row's estimated width
+// (see estimateRowWidth), so a query that returns far more rows than
+// expected — a missing WHERE clause, an unbounded time range — fails
+// fast with ErrResultTooLarge instead of growing dst until the process
+// OOMs. 0, the default, means unbounded; pass 0 to disable a previously
+// set budget.
+//
+// The estimate is necessarily rough: reflect.Type.Size alone only
+// counts a struct's direct fields, not what a string/slice/map field
+// points to, so it's padded by a flat guess per such field (see
+// estimateRowWidth). Treat the budget as a guard against runaway
+// results, not a precise memory accounting.
+func SetDecodeMemoryBudget(bytes int64) {
+	atomic.StoreInt64(&decodeMemoryBudget, bytes)
+}
+
+// ErrResultTooLarge is returned by ParseResult, ParseResults or
+// StreamRows.Next when decoding the result would exceed
+// SetDecodeMemoryBudget's cap. Narrow the query (a LIMIT, a shorter
+// time range) and retry, or switch to ChunkedRows/QueryStream to
+// consume the result a row, or a statement, at a time instead of
+// decoding it all into memory at once.
+var ErrResultTooLarge = errors.New("influx: result exceeds decode memory budget; narrow the query or decode it with ChunkedRows/QueryStream instead")
+
+// perReferenceFieldEstimate is the flat number of bytes
+// estimateRowWidth and checkResultRowBudget add per string/slice/map/
+// interface-kind field to account for the backing allocation
+// reflect.Type.Size doesn't see (it only counts that field's header:
+// 16 bytes for a string, 24 for a slice, 8 for a map or interface
+// word). It's a guess, not a measurement — enough to catch a result
+// that's orders of magnitude past the budget without needing to decode
+// it first to find out.
+const perReferenceFieldEstimate = 64
+
+// estimateRowWidth estimates the bytes one decoded element of t costs,
+// for checkDecodeBudget's row-count × width estimate.
+func estimateRowWidth(t reflect.Type) int64 {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	size := int64(t.Size())
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Interface:
+		size += perReferenceFieldEstimate
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			switch t.Field(i).Type.Kind() {
+			case reflect.String, reflect.Slice, reflect.Map, reflect.Interface:
+				size += perReferenceFieldEstimate
+			}
+		}
+	}
+	return size
+}
+
+// checkDecodeBudget reports ErrResultTooLarge when decoding rowCount
+// elements of t would exceed SetDecodeMemoryBudget's cap, or nil when
+// no budget is set (the default) or the estimate fits under it.
+func checkDecodeBudget(rowCount int, t reflect.Type) error {
+	budget := atomic.LoadInt64(&decodeMemoryBudget)
+	if budget <= 0 {
+		return nil
+	}
+	if int64(rowCount)*estimateRowWidth(t) > budget {
+		return ErrResultTooLarge
+	}
+	return nil
+}
+
+// checkResultRowBudget is checkDecodeBudget for StreamRows, where the
+// eventual destination type isn't known until Scan is called: it
+// estimates width per raw decoded cell (one column or field value)
+// across result's series instead of per destination element.
+func checkResultRowBudget(result client.Result) error {
+	budget := atomic.LoadInt64(&decodeMemoryBudget)
+	if budget <= 0 {
+		return nil
+	}
+	var cells int64
+	for _, serie := range result.Series {
+		cells += int64(len(serie.Values)) * int64(len(serie.Columns))
+	}
+	if cells*perReferenceFieldEstimate > budget {
+		return ErrResultTooLarge
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package influx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/models"
+)
+
+// TestDecodeMemoryBudgetRejectsOversizedResult confirms
+// SetDecodeMemoryBudget makes ParseResultCount fail fast with
+// ErrResultTooLarge instead of growing dst to fit a result whose
+// estimated size exceeds the budget, and that the default (0,
+// unbounded) and a generous budget both let the same result through.
+func TestDecodeMemoryBudgetRejectsOversizedResult(t *testing.T) {
+	defer SetDecodeMemoryBudget(0)
+
+	row := models.Row{
+		Columns: []string{"value"},
+		Values:  make([][]interface{}, 1000),
+	}
+	for i := range row.Values {
+		row.Values[i] = []interface{}{float64(i)}
+	}
+
+	var got []float64
+	if _, err := ParseResultCount(&got, row); err != nil {
+		t.Fatalf("ParseResultCount with no budget set: %v", err)
+	}
+
+	SetDecodeMemoryBudget(100)
+	var tooSmall []float64
+	_, err := ParseResultCount(&tooSmall, row)
+	if !errors.Is(err, ErrResultTooLarge) {
+		t.Fatalf("ParseResultCount with a 100 byte budget = %v, want ErrResultTooLarge", err)
+	}
+
+	SetDecodeMemoryBudget(1 << 20)
+	var fitsFine []float64
+	if _, err := ParseResultCount(&fitsFine, row); err != nil {
+		t.Fatalf("ParseResultCount with a 1MiB budget: %v", err)
+	}
+	if len(fitsFine) != len(row.Values) {
+		t.Fatalf("len(fitsFine) = %d, want %d", len(fitsFine), len(row.Values))
+	}
+}
+
+// TestDecodeMemoryBudgetRejectsParseResults confirms ParseResults
+// applies the same budget to its concatenated row count across every
+// series of every result, not just a single-series ParseResult call.
+func TestDecodeMemoryBudgetRejectsParseResults(t *testing.T) {
+	defer SetDecodeMemoryBudget(0)
+
+	type metric struct {
+		Value float64 `inf:"value"`
+	}
+	values := make([][]interface{}, 1000)
+	for i := range values {
+		values[i] = []interface{}{float64(i)}
+	}
+	results := []client.Result{
+		{Series: []models.Row{{Columns: []string{"value"}, Values: values}}},
+	}
+
+	SetDecodeMemoryBudget(100)
+	var metrics []metric
+	err := ParseResults(&metrics, results)
+	if !errors.Is(err, ErrResultTooLarge) {
+		t.Fatalf("ParseResults with a 100 byte budget = %v, want ErrResultTooLarge", err)
+	}
+}
+
+// TestDecodeMemoryBudgetRejectsStreamResult confirms StreamRows.Next
+// surfaces ErrResultTooLarge through Err when a single decoded
+// client.Result's estimated size exceeds the budget.
+func TestDecodeMemoryBudgetRejectsStreamResult(t *testing.T) {
+	defer SetDecodeMemoryBudget(0)
+
+	body := `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["host","value"],"values":[["a",1],["b",2],["c",3]]}]}]}`
+	sr := newStreamRows(body)
+
+	SetDecodeMemoryBudget(10)
+	if sr.Next() {
+		t.Fatal("Next() = true, want false")
+	}
+	if !errors.Is(sr.Err(), ErrResultTooLarge) {
+		t.Fatalf("Err() = %v, want ErrResultTooLarge", sr.Err())
+	}
+}
@@ -0,0 +1,155 @@
+package influx
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotSettable is returned by ParseResult, ParseResults, Rows.Scan and
+// StreamRows.Scan when dst is not an addressable, settable value (e.g.
+// a non-pointer or a nil pointer), so callers can check for it with
+// errors.Is instead of matching the message text.
+var ErrNotSettable = errors.New("influx: dst cannot be setted")
+
+// ErrSizeMismatch is returned when a row's columns and values slices
+// have different lengths, a malformed result InfluxDB itself should
+// never send, so callers can check for it with errors.Is instead of
+// matching the message text.
+var ErrSizeMismatch = errors.New("influx: columns size not equal values size")
+
+// ErrNotPointer is returned by ToPoint, ExplodePoints, QueryBind,
+// Migrate's schema option and WhereFromStruct when structure is not a
+// struct or a pointer to one, so callers can check for it with
+// errors.Is instead of matching the message text.
+var ErrNotPointer = errors.New("influx: needs a struct or a pointer to one")
+
+// ErrNoSeries is returned by ParseOne when a query's results matched
+// no series with at least one row at all, so a caller wanting exactly
+// one row can tell "no data" apart from a matched row whose fields
+// happen to decode to their Go zero values, the same distinction
+// sql.ErrNoRows draws for database/sql's QueryRow.
+var ErrNoSeries = errors.New("influx: query matched no series")
+
+// ErrUnsupportedType is returned by EncodePoints when a field holds a
+// value of a type InfluxDB's line protocol has no representation for,
+// so callers can check for it with errors.Is instead of matching the
+// message text.
+var ErrUnsupportedType = errors.New("influx: unsupported field type")
+
+// DecodeError is returned by ParseResult/ParseResults/ParseGrouped when
+// a row's value can't be decoded into its destination, naming the row,
+// column and field it came from (whichever of those the caller that
+// hit the failure knew) instead of a bare "unrecognized type" with no
+// way to tell which row or field it was. Row is -1 when the failure
+// isn't tied to a specific row (a single-row ParseResult call, or a
+// failure found before any row was reached).
+type DecodeError struct {
+	Row    int
+	Column string
+	Field  string
+	Value  interface{}
+	Err    error
+	// Measurement is the series' name (InfluxDB's "measurement") the
+	// failing row came from, when the caller that built this error knew
+	// it; empty for a decode path with no series to name (e.g. a bare
+	// parseSingle call building one field's default value).
+	Measurement string
+	// GoField is the failing destination struct field's own type and
+	// name, e.g. "CPU.Usage", set only when the failure happened while
+	// filling a struct field (Column/Field name InfluxDB's side of the
+	// mapping; GoField names Go's side, which can differ under an inf
+	// tag rename). Empty when the destination wasn't a struct field
+	// (e.g. a scalar or map destination).
+	GoField string
+}
+
+func (e *DecodeError) Error() string {
+	where := fmt.Sprintf("column %q", e.Column)
+	if e.Field != "" && e.Field != e.Column {
+		where += fmt.Sprintf(", field %q", e.Field)
+	}
+	if e.Row >= 0 {
+		where = fmt.Sprintf("row %d, %s", e.Row, where)
+	}
+	if e.Measurement != "" {
+		where = fmt.Sprintf("%s, %s", e.Measurement, where)
+	}
+	msg := fmt.Sprintf("influx: decoding %s, value %v (%T): %v", where, e.Value, e.Value, e.Err)
+	if e.GoField != "" {
+		msg += fmt.Sprintf(" (field %s)", e.GoField)
+	}
+	return msg
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// MissingColumnsError is returned by ParseResult when one or more of
+// its columns arguments names neither a column nor a tag the series
+// actually carries, listing every missing name at once instead of
+// failing on just the first.
+type MissingColumnsError struct {
+	Columns []string
+}
+
+func (e *MissingColumnsError) Error() string {
+	return fmt.Sprintf("influx: column(s) not exist: %v", e.Columns)
+}
+
+// decodeErr wraps err as a *DecodeError carrying row/column/field/value
+// context, filling in only whichever fields an enclosing call didn't
+// already set, so the innermost failure site's row/column/field survive
+// an outer wrap instead of being overwritten by it. It returns nil
+// unchanged.
+func decodeErr(err error, row int, column, field string, value interface{}) error {
+	if err == nil {
+		return nil
+	}
+	de, ok := err.(*DecodeError)
+	if !ok {
+		de = &DecodeError{Row: -1, Value: value, Err: err}
+	}
+	if de.Row < 0 {
+		de.Row = row
+	}
+	if de.Column == "" {
+		de.Column = column
+	}
+	if de.Field == "" {
+		de.Field = field
+	}
+	return de
+}
+
+// attachGoField sets err's GoField (see DecodeError.GoField) if err is
+// a *DecodeError that doesn't already have one, so the innermost
+// failure site's GoField survives an outer wrap the same way decodeErr
+// already preserves Row/Column/Field. It returns err unchanged.
+func attachGoField(err error, goField string) error {
+	if err == nil {
+		return nil
+	}
+	de, ok := err.(*DecodeError)
+	if !ok || de.GoField != "" {
+		return err
+	}
+	de.GoField = goField
+	return de
+}
+
+// attachMeasurement sets err's Measurement (see DecodeError.Measurement)
+// if err is a *DecodeError that doesn't already have one, the same
+// innermost-wins rule decodeErr and attachGoField already follow. A
+// blank measurement or a nil/non-DecodeError err is left unchanged.
+func attachMeasurement(err error, measurement string) error {
+	if err == nil || measurement == "" {
+		return err
+	}
+	de, ok := err.(*DecodeError)
+	if !ok || de.Measurement != "" {
+		return err
+	}
+	de.Measurement = measurement
+	return de
+}
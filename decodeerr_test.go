@@ -0,0 +1,68 @@
+package influx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// TestDecodeErrorIncludesMeasurementAndGoField confirms a struct field
+// decode failure surfaces as a *DecodeError naming the series'
+// measurement and the Go struct field it was filling, not just the
+// column/tag name InfluxDB's side of the mapping uses.
+func TestDecodeErrorIncludesMeasurementAndGoField(t *testing.T) {
+	type CPU struct {
+		Usage int `inf:"usage"`
+	}
+
+	SetStrictNumericParsing(true)
+	defer SetStrictNumericParsing(false)
+
+	serie := models.Row{
+		Name:    "cpu",
+		Columns: []string{"usage"},
+		Values:  [][]interface{}{{"not-a-number"}},
+	}
+
+	var cpus []CPU
+	err := ParseResult(&cpus, serie)
+	if err == nil {
+		t.Fatal("ParseResult = nil, want an error")
+	}
+
+	de, ok := err.(*DecodeError)
+	if !ok {
+		t.Fatalf("err = %T, want *DecodeError", err)
+	}
+	if de.Measurement != "cpu" {
+		t.Fatalf("Measurement = %q, want %q", de.Measurement, "cpu")
+	}
+	if de.GoField != "CPU.Usage" {
+		t.Fatalf("GoField = %q, want %q", de.GoField, "CPU.Usage")
+	}
+	if msg := de.Error(); !strings.Contains(msg, "cpu") || !strings.Contains(msg, "CPU.Usage") {
+		t.Fatalf("Error() = %q, want it to mention both the measurement and the Go field", msg)
+	}
+}
+
+// TestAttachMeasurementAndGoFieldPreserveInnermost confirms
+// attachMeasurement and attachGoField, like decodeErr itself, never
+// overwrite a value the innermost failure site already set.
+func TestAttachMeasurementAndGoFieldPreserveInnermost(t *testing.T) {
+	de := &DecodeError{Measurement: "inner", GoField: "Inner.Field"}
+
+	if got := attachMeasurement(de, "outer"); got.(*DecodeError).Measurement != "inner" {
+		t.Fatalf("Measurement = %q, want %q", got.(*DecodeError).Measurement, "inner")
+	}
+	if got := attachGoField(de, "Outer.Field"); got.(*DecodeError).GoField != "Inner.Field" {
+		t.Fatalf("GoField = %q, want %q", got.(*DecodeError).GoField, "Inner.Field")
+	}
+
+	fresh := &DecodeError{}
+	attachMeasurement(fresh, "cpu")
+	attachGoField(fresh, "CPU.Usage")
+	if fresh.Measurement != "cpu" || fresh.GoField != "CPU.Usage" {
+		t.Fatalf("fresh = %+v, want Measurement=cpu GoField=CPU.Usage", fresh)
+	}
+}
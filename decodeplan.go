@@ -0,0 +1,227 @@
+package influx
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// rowSlot is where alignToStruct should write one column or tag
+// value: a field reached through the struct's own plan (fp set), a
+// FieldByName fallback for a name the plan doesn't claim (fp nil), or
+// nowhere (matched false).
+type rowSlot struct {
+	matched bool
+	path    []int
+	fp      *fieldPlan
+}
+
+// rowPlan resolves every column and tag name alignToStruct sees for
+// one (struct type, row shape) combination to its rowSlot, once,
+// instead of re-running plan.byName/FieldByName/inColumns lookups for
+// every row of a series — alignToStruct's dominant cost when decoding
+// a large result set. unmatchedCols and unfilledFields are the
+// SetStrictDecoding check's inputs, precomputed the same way since
+// they too depend only on the row's shape, not its values. Built by
+// planRow and cached in rowPlans.
+//
+// Each rowSlot's path is a precompiled FieldByIndex path and fp a
+// precompiled fieldPlan (its own setter a precompiled closure; see
+// buildFieldSetter in plan.go) rather than a name applyRowPlan
+// resolves again per row — the reflect tag parsing BenchmarkPlanRowCached
+// and BenchmarkParseResultManyRows measure as a one-time, not
+// per-row, cost.
+type rowPlan struct {
+	cols []rowSlot          // cols[i] resolves the column at cols[i]
+	tags map[string]rowSlot // resolves a tag by name
+
+	unmatchedCols  []string
+	unfilledFields []string
+	// defaultFields indexes, into plan.fields, every field whose column
+	// or tag is absent from this row shape but that declared a
+	// "default=VALUE" inf tag option, for alignToStruct to assign
+	// instead of leaving at the Go zero value. Unlike those fields,
+	// they don't belong in unfilledFields: a declared default means the
+	// field is filled on purpose, not missing.
+	defaultFields []int
+}
+
+var rowPlans sync.Map // rowPlanKey -> *rowPlan
+
+// rowPlanKey identifies a rowPlan: a struct type decoding a
+// particular set of columns and tags under a particular columns
+// filter. cols/tags/columns are joined into single strings since a
+// sync.Map key must be comparable and []string isn't.
+type rowPlanKey struct {
+	typ        reflect.Type
+	cols       string
+	tags       string
+	columns    string
+	foldedCase bool
+}
+
+func sortedJoin(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// planRow returns the cached rowPlan for dst's type decoding cols and
+// tags under columns, building and caching one the first time this
+// shape is seen.
+func planRow(dst reflect.Value, plan *typePlan, cols []string, tags map[string]string, columns []string) *rowPlan {
+	tagNames := make([]string, 0, len(tags))
+	for t := range tags {
+		tagNames = append(tagNames, t)
+	}
+	foldedCase := atomic.LoadInt32(&caseInsensitiveColumns) != 0
+	key := rowPlanKey{
+		typ:        dst.Type(),
+		cols:       strings.Join(cols, ","),
+		tags:       sortedJoin(tagNames),
+		columns:    strings.Join(columns, ","),
+		foldedCase: foldedCase,
+	}
+	if v, ok := rowPlans.Load(key); ok {
+		return v.(*rowPlan)
+	}
+	return buildRowPlan(dst.Type(), plan, cols, tagNames, columns, foldedCase, key)
+}
+
+func buildRowPlan(dstType reflect.Type, plan *typePlan, cols []string, tagNames []string, columns []string, foldedCase bool, key rowPlanKey) *rowPlan {
+	rp := &rowPlan{tags: make(map[string]rowSlot, len(tagNames))}
+	matchedFields := make(map[int]bool)
+
+	for _, col := range cols {
+		slot, fieldIdx, counted := resolveRowSlot(dstType, plan, col, columns, foldedCase)
+		if fieldIdx >= 0 {
+			matchedFields[fieldIdx] = true
+		}
+		if counted && !slot.matched {
+			rp.unmatchedCols = append(rp.unmatchedCols, col)
+		}
+		rp.cols = append(rp.cols, slot)
+	}
+	for _, t := range tagNames {
+		slot, fieldIdx, counted := resolveRowSlot(dstType, plan, t, columns, foldedCase)
+		if fieldIdx >= 0 {
+			matchedFields[fieldIdx] = true
+		}
+		if counted && !slot.matched {
+			rp.unmatchedCols = append(rp.unmatchedCols, t)
+		}
+		rp.tags[t] = slot
+	}
+
+	for i, fp := range plan.fields {
+		if fp.isMapFields || fp.isMapTags {
+			continue
+		}
+		name := fp.name
+		if fp.isTime {
+			name = "time"
+		}
+		if !matchedFields[i] && inColumns(name, columns) {
+			if fp.hasDefault {
+				rp.defaultFields = append(rp.defaultFields, i)
+			} else {
+				rp.unfilledFields = append(rp.unfilledFields, name)
+			}
+		}
+	}
+
+	actual, _ := rowPlans.LoadOrStore(key, rp)
+	return actual.(*rowPlan)
+}
+
+// resolveRowSlot resolves name (a column or tag) to where
+// alignToStruct should write it, the same way its old per-row lookup
+// did: the plan's field for it if dstType actually has a settable
+// (exported) field there, a FieldByName fallback otherwise. fieldIdx
+// is the matched plan.fields index, or -1 for a fallback or no match.
+// counted reports whether name was even a candidate (passed the
+// columns filter), since only a candidate that still went unmatched
+// belongs in a rowPlan's unmatchedCols. A field tagged `inf:"-"` is
+// never returned by the FieldByName fallback, the same as it's never
+// in the plan to begin with — excluding a field from decoding this way
+// would otherwise only work by accident, for a Go field name that
+// doesn't also happen to titleCase-match its column.
+//
+// foldedCase is the caseInsensitiveColumns toggle, read once by the
+// caller and threaded through rather than loaded again per name: when
+// set, a name that doesn't match any byName entry or struct field
+// exactly falls back to a case-insensitive scan of both, so a column
+// like "VALUE" (or a mixed-case SELECT ... AS alias) still reaches a
+// field named or tagged "value".
+func resolveRowSlot(dstType reflect.Type, plan *typePlan, name string, columns []string, foldedCase bool) (slot rowSlot, fieldIdx int, counted bool) {
+	if !inColumns(name, columns) {
+		return rowSlot{}, -1, false
+	}
+	counted = true
+
+	fieldIdx = -1
+	if name == "time" && plan.timeField >= 0 {
+		fieldIdx = plan.timeField
+	} else if i, ok := plan.byName[name]; ok {
+		fieldIdx = i
+	} else if foldedCase {
+		fieldIdx = byNameFold(plan, name)
+	}
+	if fieldIdx >= 0 {
+		fp := &plan.fields[fieldIdx]
+		if sf := dstType.FieldByIndex(fp.index); sf.PkgPath == "" {
+			return rowSlot{matched: true, path: fp.index, fp: fp}, fieldIdx, true
+		}
+	}
+
+	if sf, ok := dstType.FieldByName(snakeToTitle(name)); ok && sf.PkgPath == "" && structTag(sf) != "-" {
+		return rowSlot{matched: true, path: sf.Index}, -1, true
+	}
+	if foldedCase {
+		if sf, ok := fieldByNameFold(dstType, snakeToTitle(name)); ok {
+			return rowSlot{matched: true, path: sf.Index}, -1, true
+		}
+	}
+	return rowSlot{}, -1, true
+}
+
+// foldKey normalizes name for caseInsensitiveColumns matching: folded
+// to lower case with every "_" removed, so "avg_usage", "AVGUSAGE" and
+// "AvgUsage" all compare equal — a mixed-case SELECT ... AS alias
+// rarely preserves the source column's underscores exactly.
+func foldKey(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// byNameFold is plan.byName[name], case- and underscore-insensitively:
+// the caseInsensitiveColumns fallback for the inf-tag/field-name
+// lookup resolveRowSlot otherwise does with an exact map lookup.
+func byNameFold(plan *typePlan, name string) int {
+	key := foldKey(name)
+	for n, i := range plan.byName {
+		if foldKey(n) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// fieldByNameFold is dstType.FieldByName(name), case- and
+// underscore-insensitively: reflect.Type.FieldByName itself is always
+// exact-case, so the caseInsensitiveColumns fallback for
+// resolveRowSlot's snakeToTitle fallback path needs its own scan.
+func fieldByNameFold(dstType reflect.Type, name string) (reflect.StructField, bool) {
+	key := foldKey(name)
+	for i := 0; i < dstType.NumField(); i++ {
+		sf := dstType.Field(i)
+		if sf.PkgPath == "" && structTag(sf) != "-" && foldKey(sf.Name) == key {
+			return sf, true
+		}
+	}
+	return reflect.StructField{}, false
+}
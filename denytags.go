@@ -0,0 +1,30 @@
+package influx
+
+import (
+	"fmt"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// DenyTags returns a PointMiddleware that rejects any point carrying
+// one of keys as a tag, e.g. request_id or user_id — a high-cardinality
+// value that belongs in a field, not a tag, and would otherwise explode
+// a measurement's series count. Unlike most PointMiddleware, which
+// silently drop a point by returning (nil, nil), a cardinality violation
+// fails the write loudly instead, since a caller writing a
+// high-cardinality tag by mistake needs to fix its code, not have the
+// point quietly vanish.
+func DenyTags(keys ...string) PointMiddleware {
+	deny := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		deny[key] = true
+	}
+	return func(p *client.Point) (*client.Point, error) {
+		for tag := range p.Tags() {
+			if deny[tag] {
+				return nil, fmt.Errorf("influx: point %q carries denied tag %q", p.Name(), tag)
+			}
+		}
+		return p, nil
+	}
+}
@@ -0,0 +1,48 @@
+package influx
+
+import "time"
+
+// AutoRefreshDNS starts a background goroutine that closes c's idle
+// HTTP connections every interval. client.Client.Close only ever closes
+// idle connections — it never tears the client down, see withReconnect
+// — so the next query or write after each tick dials a fresh
+// connection, re-resolving DNS if Addr names a load balancer rather than
+// pinning to whichever backend the first connection landed on for the
+// rest of the process's life.
+//
+// AutoRefreshDNS only applies to Clients built with New: UDP Clients
+// have no connections to recycle, and replica Clients would need it
+// applied to each replica's transport individually. Calling
+// AutoRefreshDNS again replaces the previous interval; Close stops the
+// background goroutine.
+func (c *Client) AutoRefreshDNS(interval time.Duration) {
+	c.mu.Lock()
+	if c.dnsRefreshStop != nil {
+		close(c.dnsRefreshStop)
+	}
+	stop := make(chan struct{})
+	c.dnsRefreshStop = stop
+	c.mu.Unlock()
+
+	go func() {
+		ticker := newTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				c.mu.RLock()
+				cli := c.cli
+				c.mu.RUnlock()
+				cli.Close()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// AutoRefreshDNS enables periodic DNS re-resolution on the package-level
+// default Client.
+func AutoRefreshDNS(interval time.Duration) {
+	gClient().AutoRefreshDNS(interval)
+}
@@ -0,0 +1,263 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultDownsampleAttempts is the default DownsampleDefinition.Attempts.
+const DefaultDownsampleAttempts = 3
+
+// DefaultDownsampleDelay is the default DownsampleDefinition.Delay.
+const DefaultDownsampleDelay = time.Second
+
+// DownsampleDefinition configures one downsampling task run periodically
+// by a DownsampleScheduler, the same aggregation a CREATE CONTINUOUS
+// QUERY would run, issued as an ordinary query instead so its failures
+// and retries are visible to the application rather than only to
+// InfluxDB's own internal CQ service log.
+type DownsampleDefinition struct {
+	// Name identifies this definition in DownsampleResult, for logging
+	// and metrics.
+	Name string
+	// Database is the source (and, unless Into is retention-policy
+	// qualified, destination) database.
+	Database string
+	// Select is the aggregate expression, e.g. "mean(value)".
+	Select string
+	// From is the source measurement.
+	From string
+	// Into is the destination measurement, e.g. `"downsample_1h"."cpu"`
+	// for a retention-policy-qualified target.
+	Into string
+	// Interval is both the GROUP BY time() bucket width and, unless
+	// Every is set, how often the scheduler runs this definition — the
+	// same relationship a CQ's own GROUP BY interval has to its
+	// execution schedule.
+	Interval time.Duration
+	// Every overrides how often the scheduler runs this definition;
+	// defaults to Interval.
+	Every time.Duration
+	// Lookback is how far back each run scans, as a "time > now() -
+	// Lookback" bound; it should cover at least one Interval so a run
+	// doesn't miss the bucket still being written to. Defaults to
+	// Interval.
+	Lookback time.Duration
+
+	// Attempts is how many times in all a run tries the query before
+	// giving up (so 1 means no retry), distinct from Client.QueryRetry's transport-level
+	// retrying: this covers the whole INTO query failing outright (a
+	// syntax error aside), the same small fixed-attempt retry Job.Push
+	// uses instead of QueryRetryConfig's minutes-scale backoff.
+	// Defaults to DefaultDownsampleAttempts.
+	Attempts int
+	// Delay is the wait between attempts. Defaults to
+	// DefaultDownsampleDelay.
+	Delay time.Duration
+}
+
+func (d DownsampleDefinition) every() time.Duration {
+	if d.Every > 0 {
+		return d.Every
+	}
+	return d.Interval
+}
+
+func (d DownsampleDefinition) lookback() time.Duration {
+	if d.Lookback > 0 {
+		return d.Lookback
+	}
+	return d.Interval
+}
+
+func (d DownsampleDefinition) attempts() int {
+	if d.Attempts > 0 {
+		return d.Attempts
+	}
+	return DefaultDownsampleAttempts
+}
+
+func (d DownsampleDefinition) delay() time.Duration {
+	if d.Delay > 0 {
+		return d.Delay
+	}
+	return DefaultDownsampleDelay
+}
+
+// DownsampleResult reports one run of one DownsampleDefinition, passed
+// to DownsampleScheduler.Observe.
+type DownsampleResult struct {
+	Definition string
+	Start, End time.Time
+	Attempts   int
+	Err        error
+}
+
+// DownsampleScheduler periodically runs a set of DownsampleDefinitions
+// against a Client, as an application-controlled alternative to
+// InfluxDB continuous queries: Observe sees every run, success or
+// failure, so a caller can log, alert or export metrics on downsampling
+// health the way a CQ's silent internal execution never allows.
+type DownsampleScheduler struct {
+	defs []DownsampleDefinition
+	// Observe, if non-nil, is called after every run of every
+	// definition.
+	Observe func(DownsampleResult)
+
+	mu   sync.Mutex
+	stop chan struct{}
+}
+
+// NewDownsampleScheduler returns a DownsampleScheduler for defs, not yet
+// started.
+func NewDownsampleScheduler(defs ...DownsampleDefinition) *DownsampleScheduler {
+	return &DownsampleScheduler{defs: defs}
+}
+
+// Start runs every definition against c, once per its own schedule, in
+// a background goroutine per definition, until Stop is called. Calling
+// Start again first stops any run already in progress.
+func (s *DownsampleScheduler) Start(c *Client) {
+	s.mu.Lock()
+	if s.stop != nil {
+		close(s.stop)
+	}
+	stop := make(chan struct{})
+	s.stop = stop
+	s.mu.Unlock()
+
+	for _, def := range s.defs {
+		go s.loop(c, def, stop)
+	}
+}
+
+// Stop ends every definition's background run. It is safe to call
+// Stop on a Scheduler that was never started.
+func (s *DownsampleScheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+}
+
+func (s *DownsampleScheduler) loop(c *Client, def DownsampleDefinition, stop chan struct{}) {
+	ticker := newTicker(def.every())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C():
+			s.run(c, def)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// run executes def once, trying it up to def.attempts() times in all,
+// and reports the result via s.Observe.
+func (s *DownsampleScheduler) run(c *Client, def DownsampleDefinition) {
+	start := clockNow()
+	ctx := context.Background()
+	cmd := downsampleCmd(def)
+
+	var err error
+	attempts := def.attempts()
+runLoop:
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if _, err = c.QueryContext(ctx, def.Database, cmd); err == nil {
+			break
+		}
+		if attempt == attempts {
+			break
+		}
+		select {
+		case <-after(def.delay()):
+		case <-ctx.Done():
+			err = ctx.Err()
+			break runLoop
+		}
+	}
+
+	if s.Observe != nil {
+		s.Observe(DownsampleResult{
+			Definition: def.Name,
+			Start:      start,
+			End:        clockNow(),
+			Attempts:   attempts,
+			Err:        err,
+		})
+	}
+}
+
+// downsampleCmd builds def's SELECT ... INTO ... FROM ... GROUP BY
+// time(...) statement.
+func downsampleCmd(def DownsampleDefinition) string {
+	return fmt.Sprintf("SELECT %s INTO %s FROM %s WHERE %s GROUP BY time(%s)",
+		def.Select, def.Into, def.From, Last(def.lookback()), durationLiteral(def.Interval))
+}
+
+// DownsampleCQ describes one CONTINUOUS QUERY that downsamples From into
+// Into on Database, InfluxDB's own server-side equivalent of a
+// DownsampleDefinition: unlike a DownsampleScheduler's runs, the CQ keeps
+// executing even while the application that declared it is down, at the
+// cost of Observe-style visibility into its failures.
+type DownsampleCQ struct {
+	// Name identifies the continuous query itself, e.g. "downsample_1h".
+	Name     string
+	Database string
+	// From is the source measurement.
+	From string
+	// Into is the destination measurement; defaults to From + "_" +
+	// Name, the same naming Client.Migrate's own cq= tag option uses.
+	Into string
+	// Select is the aggregate expression, e.g. "mean(usage)", a plain
+	// string rather than dedicated Mean/Sum/... builders so it doesn't
+	// collide with the package's existing Mean/Sum/Count scalar-query
+	// functions (scalaraggregate.go), which return a single number
+	// instead of building a query fragment.
+	Select string
+	// Interval is the GROUP BY time() bucket width.
+	Interval time.Duration
+}
+
+// NewDownsampleCQ returns a DownsampleCQ named name that downsamples
+// structure's measurement (derived the same way Migrate derives it, via
+// its `inf` tags) with aggExpr bucketed by interval, so a downsampling
+// setup can be declared in Go next to the struct it downsamples instead
+// of as bare measurement-name strings.
+func NewDownsampleCQ(database, name string, structure interface{}, aggExpr string, interval time.Duration) (*DownsampleCQ, error) {
+	opts, err := parseSchema(structure)
+	if err != nil {
+		return nil, err
+	}
+	return &DownsampleCQ{
+		Name:     name,
+		Database: database,
+		From:     opts.measurement,
+		Into:     opts.measurement + "_" + name,
+		Select:   aggExpr,
+		Interval: interval,
+	}, nil
+}
+
+// Install idempotently creates d's continuous query on c, the same CQ
+// Client.Migrate creates from a cq= tag option, tolerating an "already
+// exists" error the way Migrate does so it's safe to call on every
+// process start.
+func (d *DownsampleCQ) Install(ctx context.Context, c *Client) error {
+	into := d.Into
+	if into == "" {
+		into = d.From + "_" + d.Name
+	}
+	cmd := fmt.Sprintf(
+		"CREATE CONTINUOUS QUERY %s ON %s BEGIN SELECT %s INTO %s FROM %s GROUP BY time(%s) END",
+		Ident(d.Name), Ident(d.Database), d.Select, Ident(into), Ident(d.From), durationLiteral(d.Interval))
+	if _, err := c.QueryContext(ctx, d.Database, cmd); err != nil && !alreadyExists(err) {
+		return err
+	}
+	return nil
+}
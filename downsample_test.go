@@ -0,0 +1,146 @@
+package influx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+func TestDownsampleCmdBuildsSelectIntoGroupBy(t *testing.T) {
+	def := DownsampleDefinition{
+		Select:   "mean(value)",
+		From:     "cpu",
+		Into:     `"downsample_1h"."cpu"`,
+		Interval: time.Hour,
+	}
+	cmd := downsampleCmd(def)
+	if !strings.Contains(cmd, "SELECT mean(value) INTO") {
+		t.Fatalf("cmd = %q", cmd)
+	}
+	if !strings.Contains(cmd, `INTO "downsample_1h"."cpu" FROM cpu`) {
+		t.Fatalf("cmd = %q", cmd)
+	}
+	if !strings.Contains(cmd, "GROUP BY time(3600000000000ns)") {
+		t.Fatalf("cmd = %q", cmd)
+	}
+}
+
+// TestNewDownsampleCQInstallCreatesCQ confirms NewDownsampleCQ derives
+// From/Into from schemaMetric's `inf` tags and Install issues the
+// corresponding CREATE CONTINUOUS QUERY statement.
+func TestNewDownsampleCQInstallCreatesCQ(t *testing.T) {
+	var gotCmd string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotCmd = r.URL.Query().Get("q")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":[{}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cq, err := NewDownsampleCQ("mydb", "downsample_1h", schemaMetric{}, "mean(value)", time.Hour)
+	if err != nil {
+		t.Fatalf("NewDownsampleCQ: %v", err)
+	}
+	if cq.From != "cpu_usage" || cq.Into != "cpu_usage_downsample_1h" {
+		t.Fatalf("From = %q, Into = %q", cq.From, cq.Into)
+	}
+
+	if err := cq.Install(context.Background(), c); err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	if !strings.Contains(gotCmd, `CREATE CONTINUOUS QUERY "downsample_1h" ON "mydb"`) {
+		t.Fatalf("cmd = %q", gotCmd)
+	}
+	if !strings.Contains(gotCmd, `SELECT mean(value) INTO "cpu_usage_downsample_1h" FROM "cpu_usage"`) {
+		t.Fatalf("cmd = %q", gotCmd)
+	}
+	if !strings.Contains(gotCmd, "GROUP BY time(3600000000000ns)") {
+		t.Fatalf("cmd = %q", gotCmd)
+	}
+}
+
+func TestDownsampleSchedulerRunReportsSuccess(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":[{}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var results []DownsampleResult
+	s := NewDownsampleScheduler(DownsampleDefinition{
+		Name:     "cpu_1h",
+		Database: "mydb",
+		Select:   "mean(value)",
+		From:     "cpu",
+		Into:     `"downsample_1h"."cpu"`,
+		Interval: time.Hour,
+	})
+	s.Observe = func(r DownsampleResult) { results = append(results, r) }
+
+	s.run(c, s.defs[0])
+
+	if len(results) != 1 {
+		t.Fatalf("len(results) = %d, want 1", len(results))
+	}
+	if results[0].Err != nil {
+		t.Fatalf("Err = %v, want nil", results[0].Err)
+	}
+	if results[0].Definition != "cpu_1h" {
+		t.Fatalf("Definition = %q", results[0].Definition)
+	}
+}
+
+func TestDownsampleSchedulerRunRetriesThenReportsError(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var results []DownsampleResult
+	s := NewDownsampleScheduler(DownsampleDefinition{
+		Name:     "cpu_1h",
+		Database: "mydb",
+		Select:   "mean(value)",
+		From:     "cpu",
+		Into:     `"downsample_1h"."cpu"`,
+		Interval: time.Hour,
+		Attempts: 2,
+		Delay:    time.Millisecond,
+	})
+	s.Observe = func(r DownsampleResult) { results = append(results, r) }
+
+	s.run(c, s.defs[0])
+
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("calls = %d, want 2", got)
+	}
+	if len(results) != 1 || results[0].Err == nil {
+		t.Fatalf("results = %v, want one failing result", results)
+	}
+	if results[0].Attempts != 2 {
+		t.Fatalf("Attempts = %d, want 2", results[0].Attempts)
+	}
+}
@@ -0,0 +1,56 @@
+package influx
+
+import (
+	"log"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// DryRunConfig configures dry-run mode, set by DryRun.
+type DryRunConfig struct {
+	// Enabled turns dry-run mode on or off. While enabled,
+	// WriteBatchPointsContext (and Insert/InsertRP, which build on it)
+	// render and report each point's line protocol instead of sending
+	// the batch to InfluxDB.
+	Enabled bool
+
+	// OnDryRun, if non-nil, is called once per point with the target
+	// database and that point's rendered line protocol, instead of the
+	// default log.Printf line.
+	OnDryRun func(db, line string)
+}
+
+// DryRun puts c into (or takes it out of) dry-run mode: while enabled,
+// a write renders and reports its line protocol — via log.Printf, or
+// cfg.OnDryRun if set — instead of sending anything, for safe local
+// development or for eyeballing a tag/field mapping change's output
+// before it reaches production. Rendering a point already exercises
+// the same encoding client.NewPoint/ToPoint would send, so a dry run
+// also catches anything that would fail to encode. Call with a zero
+// DryRunConfig to disable it.
+func (c *Client) DryRun(cfg DryRunConfig) {
+	if !cfg.Enabled {
+		c.dryRun = nil
+		return
+	}
+	c.dryRun = &cfg
+}
+
+// reportDryRun renders and reports every point in bp. The caller has
+// already checked c.dryRun is non-nil.
+func (c *Client) reportDryRun(bp client.BatchPoints) {
+	db := bp.Database()
+	for _, p := range bp.Points() {
+		line := p.PrecisionString(bp.Precision())
+		if c.dryRun.OnDryRun != nil {
+			c.dryRun.OnDryRun(db, line)
+			continue
+		}
+		log.Printf("influx: dry run on %q: %s", db, line)
+	}
+}
+
+// DryRun puts the default Client into (or out of) dry-run mode.
+func DryRun(cfg DryRunConfig) {
+	gClient().DryRun(cfg)
+}
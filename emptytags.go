@@ -0,0 +1,42 @@
+package influx
+
+import "sync/atomic"
+
+// skipEmptyTags controls whether ToPoint drops a tag whose rendered
+// value is "" instead of writing it, since InfluxDB rejects or
+// mis-indexes an empty tag value. On by default (1), since a struct
+// that wants an empty string preserved as data, not identity, should
+// use a field instead of a tag; set by SetSkipEmptyTags.
+var skipEmptyTags int32 = 1
+
+// skippedEmptyTagCount counts how many empty tag values SetSkipEmptyTags
+// has dropped so far, atomic.
+var skippedEmptyTagCount int64
+
+// SetSkipEmptyTags toggles whether ToPoint drops an empty-valued tag
+// instead of writing it. Pass false to restore ToPoint's old behavior
+// of writing the tag as-is.
+func SetSkipEmptyTags(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&skipEmptyTags, 1)
+	} else {
+		atomic.StoreInt32(&skipEmptyTags, 0)
+	}
+}
+
+// SkippedEmptyTagCount returns the number of empty tag values
+// SetSkipEmptyTags has dropped so far.
+func SkippedEmptyTagCount() int64 {
+	return atomic.LoadInt64(&skippedEmptyTagCount)
+}
+
+// setTag writes value into tags under name, unless the active
+// SetSkipEmptyTags policy drops it for being empty, in which case it
+// counts the drop instead.
+func setTag(tags map[string]string, name, value string) {
+	if value == "" && atomic.LoadInt32(&skipEmptyTags) != 0 {
+		atomic.AddInt64(&skippedEmptyTagCount, 1)
+		return
+	}
+	tags[name] = value
+}
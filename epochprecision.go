@@ -0,0 +1,60 @@
+package influx
+
+import (
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/models"
+)
+
+// epochPrecision is the unit parseTime scales a numeric "time" value
+// by, for a query run with an epoch precision other than InfluxDB's
+// default of nanoseconds. Set for the duration of a call by
+// ParseResultWithPrecision; nanoseconds otherwise.
+var epochPrecision = time.Nanosecond
+
+// epochPrecisionMu serializes ParseResultWithPrecision calls, since
+// the precision they install in epochPrecision is process-wide state.
+var epochPrecisionMu sync.Mutex
+
+// epochUnit maps precision, a value of InfluxDB's epoch query
+// parameter ("ns", "u" or "us", "ms", "s"), to the time.Duration
+// parseTime needs to scale a numeric time value into nanoseconds.
+// Anything else, including the empty string, is ns.
+func epochUnit(precision string) time.Duration {
+	switch precision {
+	case "u", PrecisionMicrosecond:
+		return time.Microsecond
+	case PrecisionMillisecond:
+		return time.Millisecond
+	case PrecisionSecond:
+		return time.Second
+	default:
+		return time.Nanosecond
+	}
+}
+
+// ParseResultWithPrecision is ParseResult, but interprets a numeric
+// "time" column as an epoch count in precision instead of always
+// assuming nanoseconds, for decoding a series from a query run with
+// epoch=ms (or u, or s) instead of InfluxDB's default epoch=ns, where
+// the "time" column comes back as a plain integer in that unit rather
+// than an RFC3339 string.
+func ParseResultWithPrecision(dst interface{}, serie models.Row, precision string, columns ...string) error {
+	epochPrecisionMu.Lock()
+	defer epochPrecisionMu.Unlock()
+	epochPrecision = epochUnit(precision)
+	defer func() { epochPrecision = time.Nanosecond }()
+	return ParseResult(dst, serie, columns...)
+}
+
+// ParseResultsWithPrecision is ParseResults, under the same epoch
+// precision ParseResultWithPrecision applies.
+func ParseResultsWithPrecision(dst interface{}, results []client.Result, precision string, columns ...string) error {
+	epochPrecisionMu.Lock()
+	defer epochPrecisionMu.Unlock()
+	epochPrecision = epochUnit(precision)
+	defer func() { epochPrecision = time.Nanosecond }()
+	return ParseResults(dst, results, columns...)
+}
@@ -0,0 +1,96 @@
+package influx
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+)
+
+// ExplainPlan is the decoded result of EXPLAIN/EXPLAIN ANALYZE, as
+// returned by Explain/ExplainAnalyze: Lines holds the plan exactly as
+// InfluxDB printed it (each row of its single "QUERY PLAN" column, in
+// order), and Cursors, Blocks and DecodedPoints are the sums of every
+// "cursors", "blocks" and "decoded points"-style counter EXPLAIN
+// ANALYZE prints across the plan's iterators, for a quick at-a-glance
+// cost estimate without parsing Lines by hand. A plain EXPLAIN (no
+// ANALYZE) is parsed the same way: any of its lines that carry such a
+// counter, e.g. an estimated block count, are summed too, and the sums
+// stay 0 only where no line matches.
+type ExplainPlan struct {
+	Lines         []string
+	Cursors       int
+	Blocks        int
+	DecodedPoints int
+}
+
+var (
+	explainCursorsPattern       = regexp.MustCompile(`(?i)cursors?[a-z_]*:\s*(\d+)`)
+	explainBlocksPattern        = regexp.MustCompile(`(?i)blocks?[a-z_]*:\s*(\d+)`)
+	explainDecodedPointsPattern = regexp.MustCompile(`(?i)decoded[ _]?points?[a-z_]*:\s*(\d+)`)
+)
+
+// Explain runs EXPLAIN cmd on db, describing the query's plan without
+// running it, so a slow dashboard query's cursors and iterators can be
+// inspected programmatically instead of pasting EXPLAIN's text output
+// into a terminal by hand.
+func (c *Client) Explain(db, cmd string) (*ExplainPlan, error) {
+	return c.ExplainContext(context.Background(), db, cmd)
+}
+
+// ExplainContext is Explain with a context that aborts the request as
+// soon as it is canceled.
+func (c *Client) ExplainContext(ctx context.Context, db, cmd string) (*ExplainPlan, error) {
+	return c.explain(ctx, db, "EXPLAIN "+cmd)
+}
+
+// ExplainAnalyze runs EXPLAIN ANALYZE cmd on db, actually running the
+// query and annotating its plan with real cursor, block and decoded
+// point counts instead of Explain's estimates.
+func (c *Client) ExplainAnalyze(db, cmd string) (*ExplainPlan, error) {
+	return c.ExplainAnalyzeContext(context.Background(), db, cmd)
+}
+
+// ExplainAnalyzeContext is ExplainAnalyze with a context that aborts
+// the request as soon as it is canceled.
+func (c *Client) ExplainAnalyzeContext(ctx context.Context, db, cmd string) (*ExplainPlan, error) {
+	return c.explain(ctx, db, "EXPLAIN ANALYZE "+cmd)
+}
+
+func (c *Client) explain(ctx context.Context, db, explainCmd string) (*ExplainPlan, error) {
+	var lines []string
+	if err := c.QueryIntoContext(ctx, &lines, db, explainCmd, "QUERY PLAN"); err != nil {
+		return nil, err
+	}
+	return parseExplainPlan(lines), nil
+}
+
+// parseExplainPlan sums every cursors/blocks/decoded-points counter
+// EXPLAIN ANALYZE prints across lines into an ExplainPlan.
+func parseExplainPlan(lines []string) *ExplainPlan {
+	plan := &ExplainPlan{Lines: lines}
+	for _, line := range lines {
+		if m := explainCursorsPattern.FindStringSubmatch(line); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			plan.Cursors += n
+		}
+		if m := explainBlocksPattern.FindStringSubmatch(line); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			plan.Blocks += n
+		}
+		if m := explainDecodedPointsPattern.FindStringSubmatch(line); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			plan.DecodedPoints += n
+		}
+	}
+	return plan
+}
+
+// Explain runs Explain using the default Client.
+func Explain(db, cmd string) (*ExplainPlan, error) {
+	return gClient().Explain(db, cmd)
+}
+
+// ExplainAnalyze runs ExplainAnalyze using the default Client.
+func ExplainAnalyze(db, cmd string) (*ExplainPlan, error) {
+	return gClient().ExplainAnalyze(db, cmd)
+}
@@ -0,0 +1,52 @@
+package influx
+
+import (
+	"expvar"
+	"sync"
+)
+
+// influxExpvarMapOnce guards the lazily-created "influx" expvar map,
+// so PublishExpvar and BufferedWriter.PublishExpvar can both publish
+// into it, in either order, without expvar.Publish panicking on a
+// duplicate name.
+var (
+	influxExpvarMapOnce sync.Once
+	influxExpvarMapVal  *expvar.Map
+)
+
+func influxExpvarMap() *expvar.Map {
+	influxExpvarMapOnce.Do(func() {
+		influxExpvarMapVal = expvar.NewMap("influx")
+	})
+	return influxExpvarMapVal
+}
+
+// PublishExpvar publishes c's LastFlushTime and ConsecutiveFailures
+// (see Stats) into the "influx" expvar map, so an existing /debug/vars
+// scraper picks them up with zero configuration. Call
+// BufferedWriter.PublishExpvar too if c writes through a
+// BufferedWriter, to also publish its pending point count.
+func (c *Client) PublishExpvar() {
+	m := influxExpvarMap()
+	m.Set("LastFlushTime", expvar.Func(func() interface{} {
+		return c.Stats().LastFlushTime
+	}))
+	m.Set("ConsecutiveFailures", expvar.Func(func() interface{} {
+		return c.Stats().ConsecutiveFailures
+	}))
+}
+
+// PublishExpvar publishes the package-level default Client's counters
+// into the "influx" expvar map.
+func PublishExpvar() {
+	gClient().PublishExpvar()
+}
+
+// PublishExpvar publishes w's pending point count (see Len) into the
+// "influx" expvar map, so an existing /debug/vars scraper picks it up
+// with zero configuration.
+func (w *BufferedWriter) PublishExpvar() {
+	influxExpvarMap().Set("PendingPoints", expvar.Func(func() interface{} {
+		return w.Len()
+	}))
+}
@@ -0,0 +1,143 @@
+package influx
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// DefaultFailoverProbeInterval is the default NewWithFailover recovery
+// probe interval.
+const DefaultFailoverProbeInterval = 5 * time.Second
+
+// NewWithFailover creates a Client that writes and queries through the
+// first endpoint in cfgs, automatically failing over to the next one
+// in order whenever the active endpoint fails with a connection-level
+// error (see isConnError), the same class of error AutoReconnect
+// rebuilds a connection for. Unlike AutoReconnect, which only knows how
+// to rebuild the one address it was given, NewWithFailover moves on to
+// a different, already-dialed endpoint.
+//
+// A background goroutine periodically probes every endpoint ranked
+// above the one currently active and fails back to the first of them
+// that answers a Ping, so a client that failed over during a brief
+// outage returns to its preferred endpoint once it recovers, instead of
+// staying pinned to whichever endpoint merely happened to survive.
+//
+// NewWithFailover needs at least one endpoint; cfgs[0] is the most
+// preferred. The returned Client rejects AutoReconnect, the same as one
+// built by NewWithReplicas, since it has no single client.HTTPConfig to
+// rebuild from.
+func NewWithFailover(cfgs ...client.HTTPConfig) (*Client, error) {
+	return NewWithFailoverProbe(DefaultFailoverProbeInterval, cfgs...)
+}
+
+// NewWithFailoverProbe is NewWithFailover with an explicit recovery
+// probe interval instead of DefaultFailoverProbeInterval.
+func NewWithFailoverProbe(probeInterval time.Duration, cfgs ...client.HTTPConfig) (*Client, error) {
+	if len(cfgs) == 0 {
+		return nil, errors.New("influx: NewWithFailover needs at least one endpoint")
+	}
+	if probeInterval <= 0 {
+		probeInterval = DefaultFailoverProbeInterval
+	}
+
+	clis := make([]client.Client, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		cli, err := client.NewHTTPClient(cfg)
+		if err != nil {
+			for _, c := range clis {
+				c.Close()
+			}
+			return nil, err
+		}
+		clis = append(clis, cli)
+	}
+
+	c := &Client{
+		cli:          clis[0],
+		addr:         cfgs[0].Addr,
+		Precision:    "s",
+		failoverClis: clis,
+	}
+	if len(clis) > 1 {
+		c.failoverStop = make(chan struct{})
+		go c.failoverProbeLoop(probeInterval, c.failoverStop)
+	}
+	return c, nil
+}
+
+// withFailover runs op against the active endpoint, moving on to the
+// next endpoint in preference order whenever op fails with a
+// connection-level error, until one answers without such an error or
+// every endpoint has been tried. The first endpoint that answers
+// without a connection-level error becomes the new active endpoint; if
+// every endpoint fails with one, the active endpoint stays as it was.
+func (c *Client) withFailover(op func(client.Client) error) error {
+	n := uint32(len(c.failoverClis))
+	idx := atomic.LoadUint32(&c.failoverIdx) % n
+
+	var err error
+	for i := uint32(0); i < n; i++ {
+		err = op(c.failoverClis[idx])
+		if err == nil || !isConnError(err) {
+			break
+		}
+		idx = (idx + 1) % n
+	}
+
+	if idx != atomic.LoadUint32(&c.failoverIdx) {
+		atomic.StoreUint32(&c.failoverIdx, idx)
+		c.mu.Lock()
+		c.cli = c.failoverClis[idx]
+		c.mu.Unlock()
+	}
+	return err
+}
+
+// failoverProbeLoop periodically calls tryFailback until stop is
+// closed.
+func (c *Client) failoverProbeLoop(interval time.Duration, stop chan struct{}) {
+	ticker := newTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			c.tryFailback()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// tryFailback pings every endpoint ranked above the currently active
+// one and switches to the first that answers, restoring the preference
+// order NewWithFailover was given once a higher-ranked endpoint that
+// was previously unreachable recovers.
+func (c *Client) tryFailback() {
+	idx := atomic.LoadUint32(&c.failoverIdx)
+	for i := uint32(0); i < idx; i++ {
+		if _, _, err := c.failoverClis[i].Ping(DefaultHealthMonitorTimeout); err == nil {
+			atomic.StoreUint32(&c.failoverIdx, i)
+			c.mu.Lock()
+			c.cli = c.failoverClis[i]
+			c.mu.Unlock()
+			return
+		}
+	}
+}
+
+// InitClientWithFailover initializes the package-level default Client
+// to write and query through cfgs with automatic failover; see
+// NewWithFailover.
+func InitClientWithFailover(cfgs ...client.HTTPConfig) error {
+	cli, err := NewWithFailover(cfgs...)
+	if err != nil {
+		return err
+	}
+	setGClient(cli)
+	return nil
+}
@@ -0,0 +1,46 @@
+package influx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// TestInsertFailsOverToNextEndpoint confirms a Client built with
+// NewWithFailover moves on to the next configured endpoint when the
+// active one fails with a connection-level error, instead of
+// surfacing that error straight to the caller.
+func TestInsertFailsOverToNextEndpoint(t *testing.T) {
+	dead := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	deadAddr := dead.URL
+	dead.Close() // closed before use: connections to it are refused
+
+	var gotWrite bool
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotWrite = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer good.Close()
+
+	c, err := NewWithFailover(
+		client.HTTPConfig{Addr: deadAddr},
+		client.HTTPConfig{Addr: good.URL},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	p, err := client.NewPoint("cpu", nil, map[string]interface{}{"usage": 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Insert("db", p); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+	if !gotWrite {
+		t.Fatal("write never reached the healthy endpoint")
+	}
+}
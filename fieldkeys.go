@@ -0,0 +1,68 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// FieldDataType is a measurement field's InfluxDB-assigned data type,
+// as reported by SHOW FIELD KEYS and returned by ShowFieldKeys.
+type FieldDataType string
+
+// Field data types ShowFieldKeys can report.
+const (
+	FieldDataTypeFloat   FieldDataType = "float"
+	FieldDataTypeInteger FieldDataType = "integer"
+	FieldDataTypeString  FieldDataType = "string"
+	FieldDataTypeBoolean FieldDataType = "boolean"
+	// FieldDataTypeUnsigned is reported for a uint64 field, on
+	// InfluxDB versions supporting the unsigned line protocol type.
+	FieldDataTypeUnsigned FieldDataType = "unsigned"
+)
+
+// FieldKey is one row of SHOW FIELD KEYS, as returned by
+// ShowFieldKeys.
+type FieldKey struct {
+	Name string        `inf:"fieldKey"`
+	Type FieldDataType `inf:"fieldType"`
+}
+
+// ShowFieldKeys runs SHOW FIELD KEYS for measurement on db and decodes
+// every row into a FieldKey, so applications can validate schemas and
+// build query UIs off a measurement's actual field names and types
+// instead of a raw Query call and manual models.Row decoding. where's
+// conditions are ANDed onto the query's own WHERE clause, the same as
+// ShowTagValues, when given.
+func (c *Client) ShowFieldKeys(db, measurement string, where ...string) ([]FieldKey, error) {
+	return c.ShowFieldKeysContext(context.Background(), db, measurement, where...)
+}
+
+// ShowFieldKeysContext is ShowFieldKeys with a context that aborts the
+// request as soon as it is canceled.
+func (c *Client) ShowFieldKeysContext(ctx context.Context, db, measurement string, where ...string) ([]FieldKey, error) {
+	cmd := fmt.Sprintf("SHOW FIELD KEYS ON %s FROM %s", Ident(db), Ident(measurement))
+	if len(where) > 0 {
+		cmd += " WHERE " + strings.Join(where, " AND ")
+	}
+	results, err := c.QueryContext(ctx, db, cmd)
+	if err != nil {
+		return nil, err
+	}
+	var keys []FieldKey
+	for _, result := range results {
+		for _, serie := range result.Series {
+			var rows []FieldKey
+			if err := ParseResult(&rows, serie); err != nil {
+				return nil, err
+			}
+			keys = append(keys, rows...)
+		}
+	}
+	return keys, nil
+}
+
+// ShowFieldKeys runs SHOW FIELD KEYS using the default Client.
+func ShowFieldKeys(db, measurement string, where ...string) ([]FieldKey, error) {
+	return gClient().ShowFieldKeys(db, measurement, where...)
+}
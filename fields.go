@@ -0,0 +1,103 @@
+package influx
+
+// FieldOption customizes which of a struct's mapped fields and tags
+// ToPoint writes, applied at write time without touching the struct
+// itself, e.g. to keep a large diagnostic field out of a hot
+// measurement on just one call.
+type FieldOption func(*fieldFilter)
+
+type fieldFilter struct {
+	omit       map[string]bool
+	only       map[string]bool
+	strictTime bool
+	serverTime bool
+}
+
+// newFieldFilter builds a fieldFilter from opts, or returns nil if opts
+// is empty so callers can skip filtering entirely on the common path.
+func newFieldFilter(opts []FieldOption) *fieldFilter {
+	if len(opts) == 0 {
+		return nil
+	}
+	f := &fieldFilter{}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// allowed reports whether the field or tag named name should be
+// written. A nil f (no FieldOption given) allows everything.
+func (f *fieldFilter) allowed(name string) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.only) > 0 {
+		return f.only[name]
+	}
+	return !f.omit[name]
+}
+
+// Omit excludes the named fields and tags from the point ToPoint (and
+// so InsertStruct and InsertStructContext) builds.
+func Omit(names ...string) FieldOption {
+	return func(f *fieldFilter) {
+		if f.omit == nil {
+			f.omit = make(map[string]bool, len(names))
+		}
+		for _, name := range names {
+			f.omit[name] = true
+		}
+	}
+}
+
+// Only includes just the named fields and tags, dropping everything
+// else mapped on the struct. If both Only and Omit are given to the
+// same call, Only takes precedence.
+func Only(names ...string) FieldOption {
+	return func(f *fieldFilter) {
+		if f.only == nil {
+			f.only = make(map[string]bool, len(names))
+		}
+		for _, name := range names {
+			f.only[name] = true
+		}
+	}
+}
+
+// Fields is Only, named for its most common use: projecting a
+// heavyweight struct shared with other subsystems down to just the
+// fields one measurement needs, e.g. Insert(db, v, influx.Fields("count",
+// "latency")), without defining a second type just to drop the rest.
+func Fields(names ...string) FieldOption {
+	return Only(names...)
+}
+
+// StrictTime makes ToPoint fail with ErrZeroTime instead of its default
+// of falling back to time.Now(), when the struct's time field (a zero
+// time.Time, or the absence of one) resolves to the zero time. Without
+// it, a struct that forgot to set its Time field silently writes a
+// point InfluxDB drops as beyond retention (year 1), which is easy to
+// miss; StrictTime turns that into a caught error for a caller that
+// would rather fail loudly than fall back.
+func StrictTime() FieldOption {
+	return func(f *fieldFilter) {
+		f.strictTime = true
+	}
+}
+
+// ServerTime makes ToPoint omit the timestamp entirely instead of its
+// default of falling back to time.Now(), when the struct's time field (a
+// zero time.Time, or the absence of one) resolves to the zero time.
+// InfluxDB line protocol already drops a zero timestamp on the wire, so
+// this lets the server assign the point's receipt time instead of the
+// client's clock, for a fleet of writers whose clocks aren't trusted to
+// agree. A caller that genuinely wants a literal epoch-zero point should
+// set its time field to a non-zero time.Time instead, e.g.
+// time.Unix(0, 0); a Go zero time.Time always means "no timestamp" to
+// ToPoint, with or without ServerTime.
+func ServerTime() FieldOption {
+	return func(f *fieldFilter) {
+		f.serverTime = true
+	}
+}
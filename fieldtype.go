@@ -0,0 +1,43 @@
+package influx
+
+import "sync/atomic"
+
+// FieldTypePolicy controls whether ToPoint coerces numeric field values
+// to a single type, since InfluxDB treats an int write and a float
+// write to the same field as a type conflict and rejects the whole
+// write with an opaque error that gives no hint it's a schema mismatch.
+type FieldTypePolicy int32
+
+// Field-type policies for SetFieldTypePolicy.
+const (
+	// FieldTypeKeep leaves field types as Go gave them. This is the
+	// default (zero value).
+	FieldTypeKeep FieldTypePolicy = iota
+	// FieldTypeCoerceFloat64 converts every int/uint/float field value
+	// ToPoint builds to float64, so a producer that sometimes writes
+	// int(0) and sometimes 0.0 for the same field never collides on
+	// InfluxDB's per-field type schema.
+	FieldTypeCoerceFloat64
+)
+
+// fieldTypePolicy is the active FieldTypePolicy, set by
+// SetFieldTypePolicy.
+var fieldTypePolicy int32 // atomic FieldTypePolicy
+
+// SetFieldTypePolicy sets the field-type coercion policy ToPoint
+// applies to every numeric field it builds.
+func SetFieldTypePolicy(policy FieldTypePolicy) {
+	atomic.StoreInt32(&fieldTypePolicy, int32(policy))
+}
+
+// coerceField applies the active FieldTypePolicy to value, returning it
+// unchanged under FieldTypeKeep or for a non-numeric value.
+func coerceField(value interface{}) interface{} {
+	if FieldTypePolicy(atomic.LoadInt32(&fieldTypePolicy)) != FieldTypeCoerceFloat64 {
+		return value
+	}
+	if f, ok := aggFloat(value); ok {
+		return f
+	}
+	return value
+}
@@ -0,0 +1,81 @@
+package influx
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// DefaultFileFallbackMaxBytes is the default FileFallbackConfig.MaxBytes.
+const DefaultFileFallbackMaxBytes = 100 << 20 // 100MiB
+
+// FileFallbackConfig configures the Telegraf-compatible rotating line
+// protocol file enabled by Client.FileFallback.
+type FileFallbackConfig struct {
+	// Path is the file line protocol is appended to. Telegraf's
+	// tail/file input can follow it directly: unlike Spool's files,
+	// this one carries no header, just line protocol.
+	Path string
+	// MaxBytes rotates Path to Path+".1" (overwriting any previous
+	// rotation) once appending would push it past this size. Defaults
+	// to DefaultFileFallbackMaxBytes.
+	MaxBytes int64
+}
+
+// FileFallback enables a last-resort fallback: whenever
+// WriteBatchPointsContext fails and either Spool isn't configured or
+// spooling the batch also fails (e.g. its MaxBytes has been reached),
+// the batch is appended as plain line protocol to cfg.Path instead of
+// being dropped, for a Telegraf agent on the same host to tail and
+// replay once InfluxDB is reachable again. Unlike Spool, nothing
+// replays these files automatically; that's Telegraf's job.
+//
+// Calling FileFallback again replaces the previous config. There is no
+// way to disable it once enabled other than setting an unwritable Path.
+func (c *Client) FileFallback(cfg FileFallbackConfig) error {
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = DefaultFileFallbackMaxBytes
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.fileFallbackCfg = &cfg
+	c.mu.Unlock()
+	return nil
+}
+
+// appendFileFallback appends bp to cfg.Path as line protocol, rotating
+// it first if doing so would exceed cfg.MaxBytes.
+func (c *Client) appendFileFallback(cfg *FileFallbackConfig, bp client.BatchPoints) error {
+	var body []byte
+	for _, p := range bp.Points() {
+		body = append(body, p.PrecisionString(bp.Precision())...)
+		body = append(body, '\n')
+	}
+
+	c.fileFallbackMu.Lock()
+	defer c.fileFallbackMu.Unlock()
+
+	if info, err := os.Stat(cfg.Path); err == nil && info.Size()+int64(len(body)) > cfg.MaxBytes {
+		if err := os.Rename(cfg.Path, cfg.Path+".1"); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(cfg.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(body)
+	return err
+}
+
+// FileFallback enables the Telegraf-compatible file fallback on the
+// package-level default Client.
+func FileFallback(cfg FileFallbackConfig) error {
+	return gClient().FileFallback(cfg)
+}
@@ -0,0 +1,258 @@
+package influx
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// FluxQuery runs a Flux query against org, returning one models.Row per
+// annotated CSV table in the response.
+func (c *Client) FluxQuery(org, query string) ([]models.Row, error) {
+	return c.FluxQueryContext(context.Background(), org, query)
+}
+
+// FluxQueryContext runs a Flux query against org, aborting the request
+// as soon as ctx is canceled. InfluxDB's Flux endpoint replies with
+// annotated CSV, one or more tables separated by a blank line; each
+// table becomes a models.Row so the result can be unmarshaled with
+// ParseResult exactly like an InfluxQL result.
+//
+// This bypasses client.Client, which has no Flux support: it POSTs
+// directly to addr+"/api/v2/query", authenticating with
+// "Authorization: Token "+c.Token.
+func (c *Client) FluxQueryContext(ctx context.Context, org, query string) ([]models.Row, error) {
+	rows, _, err := c.FluxQueryContextWithMetadata(ctx, org, query)
+	return rows, err
+}
+
+// FluxQueryContextWithMetadata is FluxQueryContext, but also returns the
+// ResponseMetadata InfluxDB attached to the HTTP response, for
+// correlating a slow or failed Flux query with server-side logs.
+func (c *Client) FluxQueryContextWithMetadata(ctx context.Context, org, query string) ([]models.Row, ResponseMetadata, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.addr+"/api/v2/query?org="+org, bytes.NewReader([]byte(query)))
+	if err != nil {
+		return nil, ResponseMetadata{}, err
+	}
+	req.Header.Set("Content-Type", "application/vnd.flux")
+	req.Header.Set("Accept", "application/csv")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Token "+c.Token)
+	}
+	c.mu.RLock()
+	for k, vs := range c.headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	c.mu.RUnlock()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, ResponseMetadata{}, err
+	}
+	defer resp.Body.Close()
+	meta := responseMetadata(resp.Header)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, meta, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, meta, fmt.Errorf("influx: flux query failed: %s: %s", resp.Status, body)
+	}
+	rows, err := parseFluxCSV(body)
+	return rows, meta, err
+}
+
+// parseFluxCSV splits annotated CSV into one models.Row per table,
+// converting each cell to the Go type its "#datatype" annotation names
+// (e.g. "long" -> int64, "double" -> float64, "boolean" -> bool,
+// "dateTime:RFC3339[Nano]" -> time.Time) instead of leaving every
+// value a string, so the result feeds ParseResult the same typed
+// values an InfluxQL result's Values would carry and inf-tagged
+// structs decode a Flux table exactly like a query row. An empty cell
+// falls back to the table's "#default" annotation for that column, per
+// the annotated CSV spec. "#group" is read so it doesn't get mistaken
+// for a data row, but is otherwise unused: ParseResult has no notion
+// of a Flux group key.
+func parseFluxCSV(body []byte) ([]models.Row, error) {
+	var rows []models.Row
+	for _, table := range bytes.Split(body, []byte("\r\n\r\n")) {
+		table = bytes.TrimSpace(table)
+		if len(table) == 0 {
+			continue
+		}
+
+		r := csv.NewReader(bytes.NewReader(table))
+		r.FieldsPerRecord = -1
+		records, err := r.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+
+		var datatypes, defaults, columns []string
+		var values [][]interface{}
+		for _, rec := range records {
+			if len(rec) == 0 {
+				continue
+			}
+			switch rec[0] {
+			case "#datatype":
+				// rec keeps its own "#datatype" label in index 0, the
+				// same slot the header's unnamed leading column
+				// occupies, so datatypes[i] lines up with columns[i]
+				// (and every data row's rec[i]) without an off-by-one;
+				// index 0 itself is blanked back out since it never
+				// carries a real column's datatype.
+				datatypes = append([]string(nil), rec...)
+				datatypes[0] = ""
+				continue
+			case "#group":
+				continue
+			case "#default":
+				defaults = append([]string(nil), rec...)
+				defaults[0] = ""
+				continue
+			}
+			if columns == nil {
+				columns = rec
+				continue
+			}
+
+			vals := make([]interface{}, len(rec))
+			for i, v := range rec {
+				if v == "" && i < len(defaults) {
+					v = defaults[i]
+				}
+				var datatype string
+				if i < len(datatypes) {
+					datatype = datatypes[i]
+				}
+				vals[i], err = parseFluxValue(datatype, v)
+				if err != nil {
+					return nil, fmt.Errorf("influx: flux column %q: %w", columnName(columns, i), err)
+				}
+			}
+			values = append(values, vals)
+		}
+		if columns == nil {
+			return nil, errors.New("influx: flux response table has no header row")
+		}
+		rows = append(rows, models.Row{Columns: columns, Values: values})
+	}
+	return rows, nil
+}
+
+// columnName returns columns[i], or a placeholder if the data row that
+// produced i ran longer than the header row did.
+func columnName(columns []string, i int) string {
+	if i < len(columns) {
+		return columns[i]
+	}
+	return fmt.Sprintf("column %d", i)
+}
+
+// parseFluxValue converts a raw annotated CSV cell to the Go type
+// datatype names, per the annotated CSV spec's datatype list. An
+// unrecognized or empty datatype — the leading "" column every table
+// carries, or a future datatype this package doesn't know about — is
+// left as a string, the same fallback ParseResult's own numeric/time
+// coercion already expects from a plain InfluxQL string column.
+func parseFluxValue(datatype, v string) (interface{}, error) {
+	switch datatype {
+	case "long":
+		if v == "" {
+			return int64(0), nil
+		}
+		return strconv.ParseInt(v, 10, 64)
+	case "unsignedLong":
+		if v == "" {
+			return uint64(0), nil
+		}
+		return strconv.ParseUint(v, 10, 64)
+	case "double":
+		if v == "" {
+			return float64(0), nil
+		}
+		return strconv.ParseFloat(v, 64)
+	case "boolean":
+		if v == "" {
+			return false, nil
+		}
+		return strconv.ParseBool(v)
+	case "dateTime:RFC3339":
+		if v == "" {
+			return time.Time{}, nil
+		}
+		return time.Parse(time.RFC3339, v)
+	case "dateTime:RFC3339Nano":
+		if v == "" {
+			return time.Time{}, nil
+		}
+		return time.Parse(time.RFC3339Nano, v)
+	case "duration":
+		if v == "" {
+			return time.Duration(0), nil
+		}
+		ns, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return time.Duration(ns), nil
+	default:
+		return v, nil
+	}
+}
+
+// QueryFlux runs a Flux query against org and decodes its result
+// straight into dst, the same struct/slice/map destination shapes and
+// inf tag conventions QueryInto gives InfluxQL — collapsing the
+// FluxQueryContext-then-ParseResult-per-table boilerplate a caller
+// would otherwise repeat by hand for the common case of one destination.
+func (c *Client) QueryFlux(dst interface{}, org, query string, columns ...string) error {
+	return c.QueryFluxContext(context.Background(), dst, org, query, columns...)
+}
+
+// QueryFluxContext is QueryFlux with a context that aborts the request
+// as soon as it is canceled.
+func (c *Client) QueryFluxContext(ctx context.Context, dst interface{}, org, query string, columns ...string) error {
+	rows, err := c.FluxQueryContext(ctx, org, query)
+	if err != nil {
+		return err
+	}
+	return parseResultSet(dst, rows, columns...)
+}
+
+// FluxQuery runs a Flux query against org using the package-level
+// default Client.
+func FluxQuery(org, query string) ([]models.Row, error) {
+	return gClient().FluxQuery(org, query)
+}
+
+// QueryFlux runs a Flux query against org and decodes its result into
+// dst, using the default Client.
+func QueryFlux(dst interface{}, org, query string, columns ...string) error {
+	return gClient().QueryFlux(dst, org, query, columns...)
+}
+
+// QueryFluxContext is QueryFlux with a context, using the default
+// Client.
+func QueryFluxContext(ctx context.Context, dst interface{}, org, query string, columns ...string) error {
+	return gClient().QueryFluxContext(ctx, dst, org, query, columns...)
+}
+
+// FluxQueryContextWithMetadata runs a Flux query against org using the
+// package-level default Client, also returning the response's
+// ResponseMetadata.
+func FluxQueryContextWithMetadata(ctx context.Context, org, query string) ([]models.Row, ResponseMetadata, error) {
+	return gClient().FluxQueryContextWithMetadata(ctx, org, query)
+}
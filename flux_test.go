@@ -0,0 +1,132 @@
+package influx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+type fluxMetric struct {
+	Host  string  `inf:"host"`
+	Value float64 `inf:"_value"`
+	Time  time.Time
+}
+
+// TestParseFluxCSVTypesValuesByDatatype confirms parseFluxCSV converts
+// each cell per its "#datatype" annotation and that the result decodes
+// through ParseResult into an inf-tagged struct.
+func TestParseFluxCSVTypesValuesByDatatype(t *testing.T) {
+	body := "" +
+		"#datatype,long,dateTime:RFC3339,double,string\r\n" +
+		"#group,false,false,false,true\r\n" +
+		"#default,,,,\r\n" +
+		",table,time,_value,host\r\n" +
+		",0,2020-01-02T03:04:05Z,1.5,a\r\n" +
+		",0,2020-01-02T03:05:05Z,2.5,a\r\n"
+
+	rows, err := parseFluxCSV([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("len(rows) = %d, want 1", len(rows))
+	}
+
+	var metrics []fluxMetric
+	if err := ParseResult(&metrics, rows[0], "time", "_value", "host"); err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics) != 2 {
+		t.Fatalf("len(metrics) = %d, want 2", len(metrics))
+	}
+	if metrics[0].Host != "a" || metrics[0].Value != 1.5 {
+		t.Fatalf("metrics[0] = %+v", metrics[0])
+	}
+	wantTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	if !metrics[0].Time.Equal(wantTime) {
+		t.Fatalf("Time = %v, want %v", metrics[0].Time, wantTime)
+	}
+}
+
+// TestParseFluxCSVAppliesDefaultForEmptyCell confirms an empty data
+// cell falls back to the table's "#default" annotation for that
+// column.
+func TestParseFluxCSVAppliesDefaultForEmptyCell(t *testing.T) {
+	body := "" +
+		"#datatype,long,long\r\n" +
+		"#group,false,false\r\n" +
+		"#default,,7\r\n" +
+		",table,n\r\n" +
+		",0,\r\n"
+
+	rows, err := parseFluxCSV([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || len(rows[0].Values) != 1 {
+		t.Fatalf("rows = %+v", rows)
+	}
+	if got := rows[0].Values[0][2]; got != int64(7) {
+		t.Fatalf("n = %v (%T), want int64(7)", got, got)
+	}
+}
+
+// TestParseFluxCSVSeparatesTables confirms two blank-line-separated
+// tables decode into two separate models.Rows.
+func TestParseFluxCSVSeparatesTables(t *testing.T) {
+	body := "" +
+		"#datatype,long,long\r\n" +
+		"#group,false,false\r\n" +
+		"#default,,\r\n" +
+		",table,n\r\n" +
+		",0,1\r\n" +
+		"\r\n" +
+		"#datatype,long,long\r\n" +
+		"#group,false,false\r\n" +
+		"#default,,\r\n" +
+		",table,n\r\n" +
+		",1,2\r\n"
+
+	rows, err := parseFluxCSV([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("len(rows) = %d, want 2", len(rows))
+	}
+}
+
+// TestQueryFluxDecodesIntoStruct confirms QueryFlux decodes a Flux
+// response straight into dst, the same inf-tagged destination
+// FluxQuery's caller would otherwise build by hand from ParseResult.
+func TestQueryFluxDecodesIntoStruct(t *testing.T) {
+	body := "" +
+		"#datatype,long,dateTime:RFC3339,double,string\r\n" +
+		"#group,false,false,false,true\r\n" +
+		"#default,,,,\r\n" +
+		",table,time,_value,host\r\n" +
+		",0,2020-01-02T03:04:05Z,1.5,a\r\n" +
+		",0,2020-01-02T03:05:05Z,2.5,a\r\n"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var metrics []fluxMetric
+	if err := c.QueryFluxContext(context.Background(), &metrics, "myorg", "from(bucket: \"b\")", "time", "_value", "host"); err != nil {
+		t.Fatalf("QueryFluxContext: %v", err)
+	}
+	if len(metrics) != 2 || metrics[0].Host != "a" || metrics[0].Value != 1.5 {
+		t.Fatalf("metrics = %+v", metrics)
+	}
+}
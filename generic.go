@@ -0,0 +1,127 @@
+package influx
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/models"
+)
+
+// ParseRows is ParseResult for a destination type known at compile
+// time: it decodes serie into a new []T instead of requiring the
+// caller to declare a []T variable and pass its address through
+// interface{}.
+func ParseRows[T any](serie models.Row, columns ...string) ([]T, error) {
+	var rows []T
+	if err := ParseResult(&rows, serie, columns...); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// QueryRows runs cmd against db using the default Client and decodes
+// every row of every series of every result into a []T via
+// ParseResults, the generic counterpart to Query followed by a manual
+// ParseResults(&rows, results, ...) call.
+func QueryRows[T any](db, cmd string, columns ...string) ([]T, error) {
+	results, err := Query(db, cmd)
+	if err != nil {
+		return nil, err
+	}
+	var rows []T
+	if err := ParseResults(&rows, results, columns...); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// QueryRowsContext is QueryRows with a context, using the default
+// Client.
+func QueryRowsContext[T any](ctx context.Context, db, cmd string, columns ...string) ([]T, error) {
+	results, err := QueryContext(ctx, db, cmd)
+	if err != nil {
+		return nil, err
+	}
+	var rows []T
+	if err := ParseResults(&rows, results, columns...); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// QueryOne runs cmd against db using the default Client and decodes
+// the first row of the first series with at least one row into a T
+// via ParseOne, the generic counterpart to Query followed by a manual
+// ParseOne(results, ...) call, for a query expected to match exactly
+// one row (e.g. a LIMIT 1, or an aggregate with no GROUP BY).
+func QueryOne[T any](db, cmd string, columns ...string) (T, error) {
+	return QueryOneContext[T](context.Background(), db, cmd, columns...)
+}
+
+// QueryOneContext is QueryOne with a context, using the default
+// Client.
+func QueryOneContext[T any](ctx context.Context, db, cmd string, columns ...string) (T, error) {
+	var zero T
+	results, err := QueryContext(ctx, db, cmd)
+	if err != nil {
+		return zero, err
+	}
+	return ParseOne[T](results, columns...)
+}
+
+// ParseOne is ParseRows for exactly one row: it decodes the first row
+// of the first series with at least one row into a T, instead of
+// requiring the caller to index a possibly-empty ParseRows slice
+// themselves. It returns ErrNoSeries if results matched no such
+// series, or a result's own inline error (see RowsFromResults) if one
+// is hit first.
+func ParseOne[T any](results []client.Result, columns ...string) (T, error) {
+	var zero T
+	for _, result := range results {
+		if result.Err != "" {
+			return zero, errors.New(result.Err)
+		}
+		for _, serie := range result.Series {
+			if len(serie.Values) == 0 {
+				continue
+			}
+			rows, err := ParseRows[T](serie, columns...)
+			if err != nil {
+				return zero, err
+			}
+			if len(rows) > 0 {
+				return rows[0], nil
+			}
+		}
+	}
+	return zero, ErrNoSeries
+}
+
+// SelectColumns derives the field names and measurement SELECT should
+// ask InfluxDB for to fill T, from the same `inf` tags ToPoint and
+// ParseResult already use, so a query built from them and T's decode
+// targets can't drift apart: add a field to T and its column joins the
+// list automatically. Tag fields, the time field and dynamic
+// `inf:",fields"`/`inf:",tags"` map fields are omitted, since they
+// aren't named columns a SELECT clause lists (tags are referenced in
+// WHERE/GROUP BY instead, and InfluxDB always returns time on its
+// own). It returns T's plan error, if any, the same one ToPoint/
+// ParseResult would return for T.
+func SelectColumns[T any]() (columns []string, measurement string, err error) {
+	var sample T
+	t := reflect.Indirect(reflect.ValueOf(&sample)).Type()
+	plan := planType(t)
+	if plan.err != nil {
+		return nil, "", plan.err
+	}
+
+	for _, fp := range plan.fields {
+		if fp.isTag || fp.isTime || fp.isMapFields || fp.isMapTags {
+			continue
+		}
+		columns = append(columns, fp.name)
+	}
+	return columns, plan.measurement, nil
+}
@@ -0,0 +1,88 @@
+package influx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/models"
+)
+
+// respFakeClient answers Query with a caller-supplied *client.Response,
+// for exercising QueryRows/QueryOne without a real InfluxDB server.
+type respFakeClient struct {
+	fakeClient
+	resp *client.Response
+}
+
+func (f *respFakeClient) Query(q client.Query) (*client.Response, error) {
+	return f.resp, nil
+}
+
+func (f *respFakeClient) QueryCtx(ctx context.Context, q client.Query) (*client.Response, error) {
+	return f.Query(q)
+}
+
+// TestQueryRowsContextDecodesAllRows confirms QueryRowsContext decodes
+// every row of every series into a []T, the context-aware counterpart
+// to QueryRows.
+func TestQueryRowsContextDecodesAllRows(t *testing.T) {
+	resp := &client.Response{
+		Results: []client.Result{{
+			Series: []models.Row{{
+				Name:    "cpu",
+				Columns: []string{"host", "usage"},
+				Values:  [][]interface{}{{"a", 0.5}, {"b", 0.75}},
+			}},
+		}},
+	}
+
+	setGClient(NewWithClient(&respFakeClient{resp: resp}))
+	defer setGClient(nil)
+
+	got, err := QueryRowsContext[cpuRow](context.Background(), "db", "SELECT * FROM cpu")
+	if err != nil {
+		t.Fatalf("QueryRowsContext: %v", err)
+	}
+	if len(got) != 2 || got[0].Host != "a" || got[1].Host != "b" {
+		t.Fatalf("got = %+v, want [{a 0.5} {b 0.75}]", got)
+	}
+}
+
+// TestQueryOneDecodesFirstRow confirms QueryOne decodes the first row
+// of the query's result into a T.
+func TestQueryOneDecodesFirstRow(t *testing.T) {
+	resp := &client.Response{
+		Results: []client.Result{{
+			Series: []models.Row{{
+				Name:    "cpu",
+				Columns: []string{"host", "usage"},
+				Values:  [][]interface{}{{"a", 0.5}},
+			}},
+		}},
+	}
+
+	setGClient(NewWithClient(&respFakeClient{resp: resp}))
+	defer setGClient(nil)
+
+	got, err := QueryOne[cpuRow]("db", "SELECT * FROM cpu LIMIT 1")
+	if err != nil {
+		t.Fatalf("QueryOne: %v", err)
+	}
+	if got.Host != "a" || got.Usage != 0.5 {
+		t.Fatalf("QueryOne = %+v, want {a 0.5}", got)
+	}
+}
+
+// TestQueryOneReturnsErrNoSeriesWhenEmpty confirms QueryOne surfaces
+// ErrNoSeries, not a zero-value T, when the query matched no rows.
+func TestQueryOneReturnsErrNoSeriesWhenEmpty(t *testing.T) {
+	resp := &client.Response{Results: []client.Result{{}}}
+
+	setGClient(NewWithClient(&respFakeClient{resp: resp}))
+	defer setGClient(nil)
+
+	if _, err := QueryOne[cpuRow]("db", "SELECT * FROM cpu WHERE 1=0"); err != ErrNoSeries {
+		t.Fatalf("QueryOne error = %v, want ErrNoSeries", err)
+	}
+}
@@ -0,0 +1,165 @@
+// Package graphite implements a TCP listener for Graphite's plaintext
+// protocol ("path value timestamp" lines), converting each line into
+// an InfluxDB point via a configurable path template and writing it
+// through a Writer — for absorbing legacy Graphite emitters that
+// can't be repointed at Telegraf.
+package graphite
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// Writer is the subset of *influx.BufferedWriter a Listener writes
+// decoded points through.
+type Writer interface {
+	Write(point *client.Point) error
+}
+
+// Template maps a Graphite metric path's dot-separated segments onto
+// an InfluxDB measurement and tags, Telegraf-style: each dot-separated
+// token of the template names what the same-position path segment
+// becomes — a tag key, or "measurement" to use it verbatim as (part
+// of) the measurement name. A trailing "measurement*" consumes every
+// remaining path segment instead of just one, joined back together
+// with ".". A bare "*" segment is skipped, keeping its path segment
+// out of both the measurement and the tag set. An empty Template
+// behaves like DefaultTemplate.
+type Template string
+
+// DefaultTemplate treats the whole path as the measurement and
+// extracts no tags, Graphite's own behavior absent any template.
+const DefaultTemplate Template = "measurement*"
+
+// apply maps path's dot-separated segments onto a measurement name
+// and tag set per t.
+func (t Template) apply(path string) (measurement string, tags map[string]string, err error) {
+	if t == "" {
+		t = DefaultTemplate
+	}
+	tokens := strings.Split(string(t), ".")
+	segments := strings.Split(path, ".")
+
+	tags = make(map[string]string)
+	var measurementParts []string
+
+	for i, token := range tokens {
+		if i >= len(segments) {
+			break
+		}
+		switch {
+		case token == "*":
+			continue
+		case token == "measurement":
+			measurementParts = append(measurementParts, segments[i])
+		case token == "measurement*":
+			measurementParts = append(measurementParts, segments[i:]...)
+		case token != "":
+			tags[token] = segments[i]
+		}
+	}
+
+	if len(measurementParts) == 0 {
+		return "", nil, fmt.Errorf("graphite: template %q produced no measurement for path %q", t, path)
+	}
+	return strings.Join(measurementParts, "."), tags, nil
+}
+
+// ParseLine parses a single Graphite plaintext line
+// ("path value timestamp", whitespace-separated) into a point, mapping
+// its path through t. timestamp is a Graphite-style Unix timestamp in
+// seconds, fractional seconds accepted for sub-second precision.
+func ParseLine(line string, t Template) (*client.Point, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf(`graphite: malformed line %q: want "path value timestamp"`, line)
+	}
+	path, valueStr, timestampStr := fields[0], fields[1], fields[2]
+
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return nil, fmt.Errorf("graphite: invalid value in line %q: %w", line, err)
+	}
+	timestamp, err := strconv.ParseFloat(timestampStr, 64)
+	if err != nil {
+		return nil, fmt.Errorf("graphite: invalid timestamp in line %q: %w", line, err)
+	}
+
+	measurement, tags, err := t.apply(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sec := int64(timestamp)
+	nsec := int64((timestamp - float64(sec)) * float64(time.Second))
+	ts := time.Unix(sec, nsec).UTC()
+
+	return client.NewPoint(measurement, tags, map[string]interface{}{"value": value}, ts)
+}
+
+// Listener accepts Graphite plaintext connections and writes each
+// line it decodes through Writer.
+type Listener struct {
+	// Writer receives every decoded point.
+	Writer Writer
+	// Template maps a path onto a measurement and tags. Defaults to
+	// DefaultTemplate.
+	Template Template
+	// OnError, if set, is called with each line that fails to parse
+	// or write instead of it being silently dropped. It must not
+	// block.
+	OnError func(line string, err error)
+}
+
+// ListenAndServe listens on addr and serves Graphite plaintext
+// connections until the listener returns an error (e.g. once it's
+// closed from another goroutine), blocking the calling goroutine the
+// way http.ListenAndServe does.
+func (l *Listener) ListenAndServe(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return l.Serve(ln)
+}
+
+// Serve accepts connections from ln, serving Graphite plaintext lines
+// from each on its own goroutine, until ln.Accept returns an error
+// (e.g. once ln is closed from another goroutine).
+func (l *Listener) Serve(ln net.Listener) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		go l.handleConn(conn)
+	}
+}
+
+func (l *Listener) handleConn(conn net.Conn) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		l.handleLine(line)
+	}
+}
+
+func (l *Listener) handleLine(line string) {
+	p, err := ParseLine(line, l.Template)
+	if err == nil {
+		err = l.Writer.Write(p)
+	}
+	if err != nil && l.OnError != nil {
+		l.OnError(line, err)
+	}
+}
@@ -0,0 +1,160 @@
+package graphite
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// fakeWriter records every point written to it, in place of a real
+// *influx.BufferedWriter.
+type fakeWriter struct {
+	mu     sync.Mutex
+	points []*client.Point
+}
+
+func (w *fakeWriter) Write(p *client.Point) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.points = append(w.points, p)
+	return nil
+}
+
+func (w *fakeWriter) len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.points)
+}
+
+// TestParseLineDefaultTemplate confirms the default template treats
+// the whole path as the measurement, with no tags.
+func TestParseLineDefaultTemplate(t *testing.T) {
+	p, err := ParseLine("servers.web01.cpu.load 0.5 1000", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name() != "servers.web01.cpu.load" {
+		t.Fatalf("Name() = %q, want %q", p.Name(), "servers.web01.cpu.load")
+	}
+	if len(p.Tags()) != 0 {
+		t.Fatalf("Tags() = %v, want none", p.Tags())
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["value"] != 0.5 {
+		t.Fatalf("value field = %v, want 0.5", fields["value"])
+	}
+	if want := time.Unix(1000, 0).UTC(); !p.Time().Equal(want) {
+		t.Fatalf("Time() = %v, want %v", p.Time(), want)
+	}
+}
+
+// TestParseLineCustomTemplate confirms a template mapping leading
+// path segments to tags and a trailing measurement* to the rest.
+func TestParseLineCustomTemplate(t *testing.T) {
+	p, err := ParseLine("prod.web01.cpu.load 0.5 1000", Template("env.host.measurement*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name() != "cpu.load" {
+		t.Fatalf("Name() = %q, want %q", p.Name(), "cpu.load")
+	}
+	tags := p.Tags()
+	if tags["env"] != "prod" || tags["host"] != "web01" {
+		t.Fatalf("Tags() = %v, want env=prod host=web01", tags)
+	}
+}
+
+// TestParseLineSkipsStarSegment confirms a bare "*" template token
+// drops its path segment from both the measurement and the tag set.
+func TestParseLineSkipsStarSegment(t *testing.T) {
+	p, err := ParseLine("ignored.cpu 1 1000", Template("*.measurement"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name() != "cpu" {
+		t.Fatalf("Name() = %q, want %q", p.Name(), "cpu")
+	}
+	if len(p.Tags()) != 0 {
+		t.Fatalf("Tags() = %v, want none", p.Tags())
+	}
+}
+
+// TestParseLineRejectsMalformedLine confirms a line without exactly
+// three whitespace-separated fields is rejected.
+func TestParseLineRejectsMalformedLine(t *testing.T) {
+	if _, err := ParseLine("servers.web01.cpu.load 0.5", ""); err == nil {
+		t.Fatal("want error for a line missing its timestamp")
+	}
+}
+
+// TestListenerWritesDecodedLines confirms a Listener accepts a
+// connection, decodes each line it sends and writes the resulting
+// point through Writer.
+func TestListenerWritesDecodedLines(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	w := &fakeWriter{}
+	l := &Listener{Writer: w}
+	go l.Serve(ln)
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	fmt.Fprintf(conn, "servers.web01.cpu.load 0.5 1000\nservers.web01.cpu.idle 99.5 1000\n")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for w.len() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := w.len(); got != 2 {
+		t.Fatalf("len(points) = %d, want 2", got)
+	}
+}
+
+// TestListenerReportsParseErrors confirms a malformed line reaches
+// OnError instead of being written or silently dropped.
+func TestListenerReportsParseErrors(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	errs := make(chan error, 1)
+	l := &Listener{
+		Writer:  &fakeWriter{},
+		OnError: func(line string, err error) { errs <- err },
+	}
+	go l.Serve(ln)
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	fmt.Fprintf(conn, "not a valid line\n")
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Fatal("want non-nil error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnError was not called")
+	}
+}
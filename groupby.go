@@ -0,0 +1,46 @@
+package influx
+
+import (
+	"fmt"
+	"time"
+)
+
+// FillOption is a GROUP BY time()'s fill() argument, for GroupByTime.
+type FillOption string
+
+// Fill options for GroupByTime. A bucket with no points in it gets
+// null by default (InfluxQL's own default, same as not writing fill()
+// at all); FillValue renders a literal numeric fill instead.
+const (
+	FillNull     FillOption = "null"
+	FillNone     FillOption = "none"
+	FillPrevious FillOption = "previous"
+	FillLinear   FillOption = "linear"
+)
+
+// FillValue renders v (e.g. 0, 0.0) as a literal fill() value, for a
+// bucket with no points to fill with instead of null, none, the
+// previous bucket's value, or a linear interpolation.
+func FillValue(v interface{}) FillOption {
+	return FillOption(parseString(v))
+}
+
+// GroupByTime renders a "GROUP BY time(interval)" clause, optionally
+// offsetting bucket boundaries by offset (pass 0 for none, the usual
+// case) and appending a fill() option (pass "" to omit it and get
+// InfluxQL's own default, null). interval and offset are both
+// rendered in exact nanoseconds via durationLiteral, the same helper
+// Last/Since/Between use, so they're never rounded the way a
+// hand-written "5m" literal might be for an interval Go only
+// expresses cleanly in, say, milliseconds.
+func GroupByTime(interval, offset time.Duration, fill FillOption) string {
+	clause := fmt.Sprintf("GROUP BY time(%s", durationLiteral(interval))
+	if offset != 0 {
+		clause += fmt.Sprintf(", %s", durationLiteral(offset))
+	}
+	clause += ")"
+	if fill != "" {
+		clause += fmt.Sprintf(" fill(%s)", fill)
+	}
+	return clause
+}
@@ -0,0 +1,150 @@
+package grpcmetrics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeWriter records every point written to it, in place of a real
+// *influx.BufferedWriter.
+type fakeWriter struct {
+	mu     sync.Mutex
+	points []*client.Point
+}
+
+func (w *fakeWriter) Write(p *client.Point) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.points = append(w.points, p)
+	return nil
+}
+
+func (w *fakeWriter) last() *client.Point {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.points) == 0 {
+		return nil
+	}
+	return w.points[len(w.points)-1]
+}
+
+// TestUnaryServerRecordsMethodAndCode confirms UnaryServer tags a
+// point with the RPC's method and status code.
+func TestUnaryServerRecordsMethodAndCode(t *testing.T) {
+	w := &fakeWriter{}
+	i := &Interceptor{Writer: w}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "nope")
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc.Widgets/Get"}
+	_, err := i.UnaryServer(context.Background(), nil, info, handler)
+	if err == nil {
+		t.Fatal("want error propagated")
+	}
+
+	p := w.last()
+	if p == nil {
+		t.Fatal("no point written")
+	}
+	tags := p.Tags()
+	if tags["kind"] != "unary_server" || tags["method"] != "/svc.Widgets/Get" || tags["code"] != "NotFound" {
+		t.Fatalf("tags = %v", tags)
+	}
+}
+
+// TestUnaryClientRecordsOK confirms a successful call is tagged with
+// code "OK".
+func TestUnaryClientRecordsOK(t *testing.T) {
+	w := &fakeWriter{}
+	i := &Interceptor{Writer: w}
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+	err := i.UnaryClient(context.Background(), "/svc.Widgets/Get", nil, nil, nil, invoker)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tags := w.last().Tags()
+	if tags["kind"] != "unary_client" || tags["code"] != "OK" {
+		t.Fatalf("tags = %v", tags)
+	}
+}
+
+// TestStreamServerRecordsMethod confirms StreamServer tags a point
+// with the stream's method and propagates the handler's error.
+func TestStreamServerRecordsMethod(t *testing.T) {
+	w := &fakeWriter{}
+	i := &Interceptor{Writer: w}
+	wantErr := errors.New("boom")
+
+	handler := func(srv interface{}, ss grpc.ServerStream) error { return wantErr }
+	info := &grpc.StreamServerInfo{FullMethod: "/svc.Widgets/Watch"}
+	err := i.StreamServer(nil, fakeServerStream{ctx: context.Background()}, info, handler)
+	if err != wantErr {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+
+	tags := w.last().Tags()
+	if tags["kind"] != "stream_server" || tags["method"] != "/svc.Widgets/Watch" {
+		t.Fatalf("tags = %v", tags)
+	}
+}
+
+// TestStreamClientRecordsMethod confirms StreamClient tags a point
+// with the stream's method.
+func TestStreamClientRecordsMethod(t *testing.T) {
+	w := &fakeWriter{}
+	i := &Interceptor{Writer: w}
+
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return nil, nil
+	}
+	_, err := i.StreamClient(context.Background(), &grpc.StreamDesc{}, nil, "/svc.Widgets/Watch", streamer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tags := w.last().Tags()
+	if tags["kind"] != "stream_client" || tags["method"] != "/svc.Widgets/Watch" {
+		t.Fatalf("tags = %v", tags)
+	}
+}
+
+// TestOnErrorCalledOnWriteFailure confirms a Writer failure reaches
+// OnError instead of being silently dropped.
+func TestOnErrorCalledOnWriteFailure(t *testing.T) {
+	writeErr := errors.New("write failed")
+	var got error
+	i := &Interceptor{
+		Writer:  failingWriter{err: writeErr},
+		OnError: func(err error) { got = err },
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+	i.UnaryServer(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/svc.Widgets/Get"}, handler)
+
+	if got != writeErr {
+		t.Fatalf("OnError got %v, want %v", got, writeErr)
+	}
+}
+
+type failingWriter struct{ err error }
+
+func (f failingWriter) Write(*client.Point) error { return f.err }
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s fakeServerStream) Context() context.Context { return s.ctx }
@@ -0,0 +1,48 @@
+package influx
+
+import (
+	"net/http"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// InitClientWithUserAgent initializes the package-level default Client
+// with a custom User-Agent, overriding client/v2's "InfluxDBClient"
+// default.
+func InitClientWithUserAgent(addr, userAgent string) error {
+	return InitClientWithConfig(client.HTTPConfig{
+		Addr:      addr,
+		UserAgent: userAgent,
+	})
+}
+
+// SetHeader adds an extra HTTP header to every FluxQueryContext request.
+// Setting the same key again replaces its value; setting an empty value
+// removes it.
+//
+// QueryContext and WriteBatchPointsContext cannot honor SetHeader:
+// client.HTTPConfig has no field for arbitrary default headers (only
+// UserAgent), and client.Client builds its *http.Request internally
+// with no hook to add to it, so there is no way to inject a header like
+// X-Scope-OrgID into an InfluxQL query or write without forking the
+// vendored client/v2 package. FluxQueryContext can honor it because it
+// already does its own raw net/http call instead of going through
+// client.Client.
+func (c *Client) SetHeader(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.headers == nil {
+		c.headers = make(http.Header)
+	}
+	if value == "" {
+		c.headers.Del(key)
+		return
+	}
+	c.headers.Set(key, value)
+}
+
+// SetHeader adds an extra HTTP header to every FluxQueryContext request
+// made by the package-level default Client.
+func SetHeader(key, value string) {
+	gClient().SetHeader(key, value)
+}
@@ -0,0 +1,64 @@
+package influx
+
+import "time"
+
+// DefaultHealthMonitorInterval is the default StartHealthMonitor
+// interval.
+const DefaultHealthMonitorInterval = 5 * time.Second
+
+// DefaultHealthMonitorTimeout bounds each Ping StartHealthMonitor
+// issues.
+const DefaultHealthMonitorTimeout = 5 * time.Second
+
+// StartHealthMonitor runs Ping every interval on its own background
+// goroutine and calls onChange whenever healthy flips, so an
+// application can flip a feature flag or shed load the moment InfluxDB
+// goes down, and clear it the moment Ping succeeds again, rather than
+// discovering degradation one failed query at a time. onChange is not
+// called on every tick, only on a transition; its first call reports
+// the outcome of the first Ping. interval defaults to
+// DefaultHealthMonitorInterval.
+//
+// StartHealthMonitor may be called any number of times to schedule
+// several independent monitors; each runs until Close.
+func (c *Client) StartHealthMonitor(interval time.Duration, onChange func(healthy bool, err error)) {
+	if interval <= 0 {
+		interval = DefaultHealthMonitorInterval
+	}
+
+	stop := make(chan struct{})
+	c.mu.Lock()
+	c.healthMonitorStops = append(c.healthMonitorStops, stop)
+	c.mu.Unlock()
+
+	go c.healthMonitorLoop(interval, onChange, stop)
+}
+
+func (c *Client) healthMonitorLoop(interval time.Duration, onChange func(healthy bool, err error), stop chan struct{}) {
+	ticker := newTicker(interval)
+	defer ticker.Stop()
+
+	var known bool
+	var healthy bool
+	for {
+		_, _, err := c.Ping(DefaultHealthMonitorTimeout)
+		nowHealthy := err == nil
+		if !known || nowHealthy != healthy {
+			known = true
+			healthy = nowHealthy
+			onChange(healthy, err)
+		}
+
+		select {
+		case <-ticker.C():
+		case <-stop:
+			return
+		}
+	}
+}
+
+// StartHealthMonitor schedules a health monitor on the package-level
+// default Client.
+func StartHealthMonitor(interval time.Duration, onChange func(healthy bool, err error)) {
+	gClient().StartHealthMonitor(interval, onChange)
+}
@@ -0,0 +1,62 @@
+package influx
+
+import "time"
+
+// Measurementer lets a mapped struct name its own measurement instead
+// of the snake_cased type name ToPoint derives by default, overriding
+// a `measurement=...` inf tag on one of its fields (e.g.
+// `X struct{} "inf:\"measurement=http_requests\""`) the same way
+// Databaser overrides a `database=` inf tag, for a plain DTO that
+// doesn't want a method added just for naming.
+type Measurementer interface {
+	Measurement() string
+}
+
+// TagOverrider lets a mapped struct contribute extra tags beyond the
+// ones declared with `inf:"...,tag"` on its fields. ToPoint merges them
+// in before the tag fields, so a same-named tag field still wins.
+type TagOverrider interface {
+	Tags() map[string]string
+}
+
+// BeforeInserter runs before a struct is turned into a point. ToPoint
+// fails closed if it returns an error, rather than handing client.NewPoint
+// a half-built point.
+type BeforeInserter interface {
+	BeforeInsert() error
+}
+
+// AfterParser runs once ParseResult has finished decoding a struct from
+// a query row, letting it normalize or validate the decoded fields.
+type AfterParser interface {
+	AfterParse() error
+}
+
+// PointMarshaler lets a type build its own point instead of going
+// through struct-tag reflection, for encodings ToPoint's field-by-field
+// plan can't express (a variant type, a value computed from several
+// fields, a schema chosen at runtime). ToPoint calls MarshalPoint and
+// uses its result as-is; BeforeInserter, Measurementer and
+// TagOverrider are not consulted for a type that implements it.
+//
+// cmd/infxgen generates a MarshalPoint method straight from a struct's
+// existing inf tags for hot paths that want to skip the reflection ToPoint
+// would otherwise do; see its package doc for the `//influx:generate`
+// marker comment it looks for.
+type PointMarshaler interface {
+	MarshalPoint() (measurement string, tags map[string]string, fields map[string]interface{}, t time.Time, err error)
+}
+
+// RowUnmarshaler is PointMarshaler's read-side counterpart: it lets a
+// type decode its own fields from a query row instead of going through
+// struct-tag reflection. alignToStruct calls UnmarshalRow, when the
+// destination implements it, instead of resolving fields by inf
+// tag/plan; AfterParser still runs afterward. It is not consulted for a
+// decode that names specific columns (ParseResult/QueryBind's columns
+// argument), since UnmarshalRow has no way to honor that filter.
+//
+// cmd/infxgen generates an UnmarshalRow method alongside MarshalPoint;
+// see PointMarshaler's doc comment.
+type RowUnmarshaler interface {
+	UnmarshalRow(cols []string, vals []interface{}, tags map[string]string) error
+}
@@ -0,0 +1,117 @@
+// Package httpmetrics is a net/http middleware recording RED metrics —
+// request rate, errors and duration — per route into a measurement
+// through a Writer, so a web service gets turnkey HTTP metrics in
+// InfluxDB without wiring up its own instrumentation.
+package httpmetrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// DefaultMeasurement is the measurement Middleware writes points to
+// when Middleware.Measurement is unset.
+const DefaultMeasurement = "http_request"
+
+// Writer is the subset of *influx.BufferedWriter a Middleware needs.
+type Writer interface {
+	Write(point *client.Point) error
+}
+
+// Middleware wraps an http.Handler, writing one point per request. The
+// zero value is ready to use once Writer is set.
+type Middleware struct {
+	Writer Writer
+
+	// Measurement is the measurement every point is written under.
+	// Defaults to DefaultMeasurement.
+	Measurement string
+	// Route extracts the route label for a request, tagged alongside
+	// method and status. Defaults to r.URL.Path, which is fine for a
+	// service with a small, fixed set of paths, but tagging raw paths
+	// that include path parameters (e.g. "/users/1", "/users/2") as
+	// distinct routes leads to unbounded tag cardinality on a service
+	// with many distinct resource IDs — set Route to whatever your
+	// router (chi, gorilla/mux, Go 1.22's http.ServeMux) exposes as the
+	// matched pattern instead, e.g. "/users/{id}".
+	Route func(r *http.Request) string
+	// OnError, if set, is called when Writer.Write fails, instead of
+	// silently dropping the point.
+	OnError func(err error)
+}
+
+// Wrap returns next wrapped with m, recording one point per request
+// after next has served it.
+func (m *Middleware) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(sw, r)
+		m.record(r, sw.status, time.Since(start))
+	})
+}
+
+func (m *Middleware) record(r *http.Request, status int, dur time.Duration) {
+	tags := map[string]string{
+		"method": r.Method,
+		"route":  m.route(r),
+		"status": strconv.Itoa(status),
+	}
+	fields := map[string]interface{}{
+		"count":       1,
+		"duration_ms": float64(dur) / float64(time.Millisecond),
+		"error":       status >= 400,
+	}
+	p, err := client.NewPoint(m.measurement(), tags, fields, time.Now())
+	if err != nil {
+		if m.OnError != nil {
+			m.OnError(err)
+		}
+		return
+	}
+	if err := m.Writer.Write(p); err != nil && m.OnError != nil {
+		m.OnError(err)
+	}
+}
+
+func (m *Middleware) measurement() string {
+	if m.Measurement != "" {
+		return m.Measurement
+	}
+	return DefaultMeasurement
+}
+
+func (m *Middleware) route(r *http.Request) string {
+	if m.Route != nil {
+		return m.Route(r)
+	}
+	return r.URL.Path
+}
+
+// statusWriter records the status code written to an
+// http.ResponseWriter, defaulting to http.StatusOK when the handler
+// never calls WriteHeader (net/http's own implicit-200 behavior).
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	if sw.wroteHeader {
+		return
+	}
+	sw.wroteHeader = true
+	sw.status = status
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+func (sw *statusWriter) Write(b []byte) (int, error) {
+	if !sw.wroteHeader {
+		sw.WriteHeader(http.StatusOK)
+	}
+	return sw.ResponseWriter.Write(b)
+}
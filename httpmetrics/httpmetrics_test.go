@@ -0,0 +1,106 @@
+package httpmetrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// fakeWriter records every point written to it, in place of a real
+// *influx.BufferedWriter.
+type fakeWriter struct {
+	mu     sync.Mutex
+	points []*client.Point
+}
+
+func (w *fakeWriter) Write(p *client.Point) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.points = append(w.points, p)
+	return nil
+}
+
+func (w *fakeWriter) last() *client.Point {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.points) == 0 {
+		return nil
+	}
+	return w.points[len(w.points)-1]
+}
+
+// TestWrapRecordsMethodRouteAndStatus confirms a request is tagged
+// with its method, route and status code.
+func TestWrapRecordsMethodRouteAndStatus(t *testing.T) {
+	w := &fakeWriter{}
+	m := &Middleware{Writer: w}
+
+	handler := m.Wrap(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusCreated)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/widgets", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	p := w.last()
+	if p == nil {
+		t.Fatal("no point written")
+	}
+	tags := p.Tags()
+	if tags["method"] != "POST" || tags["route"] != "/widgets" || tags["status"] != "201" {
+		t.Fatalf("tags = %v", tags)
+	}
+}
+
+// TestWrapDefaultsStatusToOK confirms a handler that never calls
+// WriteHeader is recorded as 200, matching net/http's own behavior.
+func TestWrapDefaultsStatusToOK(t *testing.T) {
+	w := &fakeWriter{}
+	m := &Middleware{Writer: w}
+
+	handler := m.Wrap(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte("ok"))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.last().Tags()["status"] != "200" {
+		t.Fatalf("status = %v, want 200", w.last().Tags()["status"])
+	}
+}
+
+// TestWrapUsesRouteFunc confirms a custom Route overrides the default
+// raw-path tagging, for collapsing parameterized paths.
+func TestWrapUsesRouteFunc(t *testing.T) {
+	w := &fakeWriter{}
+	m := &Middleware{Writer: w, Route: func(r *http.Request) string { return "/users/{id}" }}
+
+	handler := m.Wrap(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	if got := w.last().Tags()["route"]; got != "/users/{id}" {
+		t.Fatalf("route = %q, want /users/{id}", got)
+	}
+}
+
+// TestWrapMarksErrorField confirms a 5xx/4xx response is flagged via
+// the "error" field.
+func TestWrapMarksErrorField(t *testing.T) {
+	w := &fakeWriter{}
+	m := &Middleware{Writer: w}
+
+	handler := m.Wrap(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	fields, err := w.last().Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["error"] != true {
+		t.Fatalf("error field = %v, want true", fields["error"])
+	}
+}
@@ -1,11 +1,32 @@
+// Package influx is a thin, struct-friendly wrapper around
+// github.com/influxdata/influxdb/client/v2, the InfluxDB 1.x client.
+// It has no InfluxDB 2.x (token/org/bucket) support: the 2.x HTTP API
+// is served by the separate influxdb-client-go/v2 SDK, which this
+// package does not depend on and cannot wrap without taking on that
+// dependency. It likewise has no InfluxDB 3 (FlightSQL) backend: that
+// protocol is gRPC/Arrow Flight, served by yet another SDK
+// (influxdata/influxdb3-go), unrelated to client/v2's REST API and
+// sharing none of its types.
+//
+// ParseResult/ParseResults/ToPoint and their variants are safe to call
+// concurrently from multiple goroutines, including for different types
+// at once: the reflection-derived caches behind them (type plans, row
+// plans, conversion plans) are all built on sync.Map, and every
+// process-wide setting (SetStrictTagging, SetNamingStrategy, and the
+// rest) is stored atomically and applied without tearing an in-flight
+// decode or encode.
 package influx
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 	"unicode"
 
@@ -16,6 +37,18 @@ import (
 var emptyTags = map[string]string{} // always empty
 
 func parseInt(i interface{}) int64 {
+	if n, ok := i.(json.Number); ok {
+		// json.Number formats an integer exactly, so try Int64 first to
+		// keep full int64 precision instead of round-tripping it
+		// through a float64 (which loses precision above 2^53); only a
+		// number written with a decimal point or exponent falls back
+		// to Float64.
+		if v, err := n.Int64(); err == nil {
+			return v
+		}
+		v, _ := n.Float64()
+		return int64(v)
+	}
 	switch v := reflect.ValueOf(i); v.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return v.Int()
@@ -25,7 +58,14 @@ func parseInt(i interface{}) int64 {
 		return int64(v.Float())
 	case reflect.String:
 		val, err := strconv.ParseInt(v.String(), 10, 64)
-		if err != nil { // maybe time
+		if err != nil {
+			if b, ok := parseBoolLike(v.String()); ok {
+				if b {
+					return 1
+				}
+				return 0
+			}
+			// maybe time
 			t, err := time.Parse(time.RFC3339, v.String())
 			if err == nil {
 				return t.UnixNano()
@@ -37,6 +77,10 @@ func parseInt(i interface{}) int64 {
 }
 
 func parseFloat(i interface{}) float64 {
+	if n, ok := i.(json.Number); ok {
+		v, _ := n.Float64()
+		return v
+	}
 	switch v := reflect.ValueOf(i); v.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return float64(v.Int())
@@ -45,12 +89,86 @@ func parseFloat(i interface{}) float64 {
 	case reflect.Float32, reflect.Float64:
 		return v.Float()
 	case reflect.String:
-		val, _ := strconv.ParseFloat(v.String(), 64)
+		val, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			if b, ok := parseBoolLike(v.String()); ok {
+				if b {
+					return 1
+				}
+				return 0
+			}
+		}
 		return val
 	}
 	return 0
 }
 
+// nativeValue converts val, a column value as JSON-decoded with
+// client/v2's json.Number precision, into the type an interface{} (or
+// map[string]interface{}) destination should actually hold: the
+// "time" column becomes a time.Time via parseTime instead of the raw
+// RFC3339 string or epoch number InfluxDB sent it as, and a
+// json.Number becomes an int64 when it has no fractional part
+// (matching how an integer field round-trips) or a float64 otherwise,
+// instead of leaking client/v2's own decoding detail to a caller that
+// never asked for UseNumber. Any other value (string, bool, nil) is
+// already its native Go type and passes through unchanged.
+func nativeValue(col string, val interface{}) interface{} {
+	if val == nil {
+		return nil
+	}
+	if col == "time" {
+		return parseTime(val)
+	}
+	if n, ok := val.(json.Number); ok {
+		if v, err := n.Int64(); err == nil {
+			return v
+		}
+		v, _ := n.Float64()
+		return v
+	}
+	return val
+}
+
+// resolveRowValue picks the value parseSingle (and a fast path
+// bypassing it, like fastPathFloatSlice) actually decodes from a row:
+// cols[0]/vals[0] by default, or — when columns names a specific
+// column or tag — that one's value from cols/vals, falling back to
+// tags, falling back to cols[0]/vals[0] again if even that comes up
+// empty.
+func resolveRowValue(cols []string, vals []interface{}, tags map[string]string, columns []string) (valCol string, val interface{}) {
+	valCol, val = cols[0], vals[0]
+	if len(columns) > 0 {
+		valCol = columns[0]
+		if idx := columnIndex(columns[0], cols); idx >= 0 {
+			val = vals[idx]
+		} else if v, ok := tags[columns[0]]; ok {
+			val = v
+		}
+	}
+	return valCol, val
+}
+
+// scalarFloat64 is parseSingle's reflect.Float32/Float64 case, plus
+// its shared null handling, pulled out for fastPathFloatSlice to call
+// without a reflect.Value destination.
+func scalarFloat64(valCol string, val interface{}) (float64, error) {
+	if val == nil {
+		if atomic.LoadInt32(&strictDecoding) != 0 {
+			return 0, fmt.Errorf("influx: column %q is null", valCol)
+		}
+		return 0, nil
+	}
+	f := parseFloat(val)
+	if atomic.LoadInt32(&strictNumericParsing) != 0 {
+		var err error
+		if f, err = checkedParseFloat(val); err != nil {
+			return 0, err
+		}
+	}
+	return f, nil
+}
+
 func parseString(i interface{}) string {
 	switch v := reflect.ValueOf(i); v.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -68,22 +186,201 @@ func parseString(i interface{}) string {
 	return fmt.Sprint(i)
 }
 
+// boolToIntString formats b as "1"/"0", for a bool tag field tagged
+// `inf:"...,tag,01"`.
+func boolToIntString(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// TagString is parseString, exported for a MarshalPoint method
+// generated by cmd/infxgen to format a tag field whose type isn't one
+// of the handful infxgen special-cases (string, bool, the integer and
+// float kinds); it is not meant to be called from hand-written code,
+// which should just use ToPoint's inf tags instead.
+func TagString(i interface{}) string {
+	return parseString(i)
+}
+
+// IsZeroValue reports whether i holds its type's zero value, exported
+// for a MarshalPoint method generated by cmd/infxgen to implement
+// `inf:"...,omitempty"` on any tag or field, whatever its type;
+// it is not meant to be called from hand-written code.
+func IsZeroValue(i interface{}) bool {
+	return reflect.ValueOf(i).IsZero()
+}
+
+// BoolToIntString is boolToIntString, exported for a MarshalPoint
+// method generated by cmd/infxgen to format a field tagged
+// `inf:"...,tag,01"`; it is not meant to be called from hand-written
+// code, which should just use ToPoint's inf tags instead.
+func BoolToIntString(b bool) string {
+	return boolToIntString(b)
+}
+
+// DecodeInt64, DecodeFloat64 and DecodeBool are parseInt/parseFloat and
+// a bool equivalent, exported for an UnmarshalRow method generated by
+// cmd/infxgen to decode a query row value without reflecting on dst's
+// type (infxgen already knows it from the struct field); they are not
+// meant to be called from hand-written code, which should just use
+// ParseResult instead.
+func DecodeInt64(i interface{}) int64 {
+	return parseInt(i)
+}
+
+func DecodeFloat64(i interface{}) float64 {
+	return parseFloat(i)
+}
+
+func DecodeBool(i interface{}) bool {
+	switch v := i.(type) {
+	case bool:
+		return v
+	case string:
+		if b, ok := parseBoolLike(v); ok {
+			return b
+		}
+		b, _ := strconv.ParseBool(v)
+		return b
+	default:
+		return parseInt(i) != 0
+	}
+}
+
+// parseBoolLike recognizes the boolean spellings a legacy measurement
+// might have written into a string field instead of InfluxDB's native
+// boolean type — "yes"/"no", plus the "true"/"false"/"t"/"f" spellings
+// strconv.ParseBool already accepts case-sensitively, matched here
+// case-insensitively too for consistency. ok is false for anything
+// else, so callers fall back to their own parsing (strconv.ParseBool
+// for a bool destination, strconv.ParseInt/ParseFloat for a numeric
+// one).
+func parseBoolLike(s string) (b bool, ok bool) {
+	switch strings.ToLower(s) {
+	case "true", "t", "yes":
+		return true, true
+	case "false", "f", "no":
+		return false, true
+	}
+	return false, false
+}
+
 func parseTime(i interface{}) time.Time {
+	t := parseTimeRaw(i)
+	if timeLocation != nil {
+		t = t.In(timeLocation)
+	}
+	return t
+}
+
+func parseTimeRaw(i interface{}) time.Time {
+	if t, ok := i.(time.Time); ok {
+		return t
+	}
 	if s, ok := i.(string); ok && s != "" {
-		t, _ := time.Parse(time.RFC3339, s)
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			timeLayoutsMu.RLock()
+			layouts := timeLayouts
+			timeLayoutsMu.RUnlock()
+			for _, layout := range layouts {
+				if t, err = time.Parse(layout, s); err == nil {
+					break
+				}
+			}
+		}
 		return t
 	}
+	if n, ok := i.(json.Number); ok {
+		epoch := parseInt(n)
+		return time.Unix(0, epoch*int64(epochUnitFor(epoch)))
+	}
 
-	var nano int64
+	var epoch int64
 	switch v := reflect.ValueOf(i); v.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		nano = v.Int()
+		epoch = v.Int()
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		nano = int64(v.Uint())
+		epoch = int64(v.Uint())
 	case reflect.Float32, reflect.Float64:
-		nano = int64(v.Float())
+		epoch = int64(v.Float())
 	}
-	return time.Unix(nano/1e9, nano%1e9)
+	return time.Unix(0, epoch*int64(epochUnitFor(epoch)))
+}
+
+// epochUnitFor returns the unit a numeric "time" value should be scaled
+// by: epochPrecision, if ParseResultWithPrecision (or similar) set it
+// to anything other than its nanosecond default, since that's an
+// explicit, known-correct override; otherwise guessEpochUnit's
+// magnitude-based heuristic, since most callers never call those and
+// would otherwise silently get a year-1970 date out of a second- or
+// millisecond-epoch "time" column.
+func epochUnitFor(epoch int64) time.Duration {
+	if epochPrecision != time.Nanosecond {
+		return epochPrecision
+	}
+	return guessEpochUnit(epoch)
+}
+
+// guessEpochUnit judges, from epoch's magnitude alone, which unit it's
+// most likely counted in. InfluxDB's own epoch=ns default puts "now"
+// around 1.7e18; a value many orders of magnitude smaller is far more
+// likely to be seconds, milliseconds or microseconds from some other
+// source than an implausibly old nanosecond timestamp. This is a
+// fallback guess, not a certainty: an explicit epoch=ns value small
+// enough to land in one of the lower bands (e.g. a test fixture's
+// epoch near 1970) is misread the same way. Callers that know their
+// precision should set it via ParseResultWithPrecision instead of
+// relying on this.
+func guessEpochUnit(epoch int64) time.Duration {
+	abs := epoch
+	if abs < 0 {
+		abs = -abs
+	}
+	switch {
+	case abs < 1e11: // seconds, up to roughly year 5138
+		return time.Second
+	case abs < 1e14: // milliseconds, same range
+		return time.Millisecond
+	case abs < 1e17: // microseconds, same range
+		return time.Microsecond
+	default:
+		return time.Nanosecond
+	}
+}
+
+// assignTimeField sets field, a struct's isTime field reached via
+// alignToStruct's "time" column, from val: directly for a time.Time
+// (allocating through a *time.Time as needed), as an epoch count in unit
+// for an integer field, or formatted with layout for a string field —
+// the read-side counterpart to the encoding ToPoint applies to that same
+// field on the way out.
+func assignTimeField(field reflect.Value, val interface{}, unit time.Duration, layout string) error {
+	for field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		field = field.Elem()
+	}
+
+	t := parseTime(val)
+	if field.Type() == timeType {
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if field.Kind() == reflect.String {
+		field.SetString(t.Format(layout))
+		return nil
+	}
+
+	if unit == 0 {
+		unit = time.Nanosecond
+	}
+	field.SetInt(t.UnixNano() / int64(unit))
+	return nil
 }
 
 // MeasurementName --> measurement_name
@@ -130,47 +427,111 @@ func makeSliceDstVal(dst reflect.Value, n int) reflect.Value {
 	return dst
 }
 
+// fastPathStructSlice reports whether toslice can resolve elemType's
+// plan and rowPlan once for the whole series instead of letting
+// alignToStruct recompute its RowUnmarshaler check and rowPlan lookup
+// on every row — both depend only on elemType and the row shape, which
+// don't change across one series' rows. elemType must be a plain
+// struct (not time.Time, which alignToStruct special-cases itself).
+// With a columns filter set, alignToStruct never consults
+// RowUnmarshaler to begin with (it only does so for an unfiltered
+// decode), so the fast path applies unconditionally there too; without
+// one, elemType must not implement RowUnmarshaler, whose decoding is
+// entirely up to its own method rather than a plan.
+func fastPathStructSlice(elemType reflect.Type, columns []string) bool {
+	if elemType.Kind() != reflect.Struct || elemType == timeType {
+		return false
+	}
+	if len(columns) != 0 {
+		return true
+	}
+	return !reflect.PointerTo(elemType).Implements(rowUnmarshalerType)
+}
+
 func alignToStruct(cols []string, vals []interface{}, tags map[string]string, dst reflect.Value, columns ...string) error {
-	if dst.Type().String() == "time.Time" {
+	if dst.Type() == timeType {
 		dst.Set(reflect.ValueOf(parseTime(vals[0])))
 		return nil
 	}
 	if len(cols) != len(vals) {
-		return errors.New("columns size not equal values size")
+		return ErrSizeMismatch
 	}
 
-	typ := dst.Type()
-	parse := func(col string, val interface{}) error {
-		if !inColumns(col, columns) {
+	if len(columns) == 0 && dst.CanAddr() {
+		if unmarshaler, ok := dst.Addr().Interface().(RowUnmarshaler); ok {
+			return unmarshaler.UnmarshalRow(cols, vals, tags)
+		}
+	}
+
+	plan := planType(dst.Type())
+	if plan.err != nil {
+		return plan.err
+	}
+	rp := planRow(dst, plan, cols, tags, columns)
+	return applyRowPlan(dst, plan, rp, cols, vals, tags)
+}
+
+// applyRowPlan writes one row (cols/vals and tags) into dst per plan
+// and rp, the rest of what alignToStruct does once it has a plan and
+// rowPlan in hand. It's split out so a slice-of-struct destination can
+// compute both once for the whole series (they depend only on dst's
+// type and the row shape, the same for every row of one series)
+// instead of paying planRow's lookup again for every row the way
+// calling alignToStruct per row otherwise would; see fastPathStructSlice.
+func applyRowPlan(dst reflect.Value, plan *typePlan, rp *rowPlan, cols []string, vals []interface{}, tags map[string]string) error {
+	apply := func(slot rowSlot, name string, val interface{}) error {
+		if !slot.matched {
 			return nil
 		}
-		var field reflect.Value
-		for f := 0; f < typ.NumField(); f++ {
-			if strings.Split(typ.Field(f).Tag.Get("inf"), ",")[0] == col {
-				field = dst.Field(f)
-				break
-			}
+		field := dst.FieldByIndex(slot.path)
+		fp := slot.fp
+		fieldName := name
+		if fp != nil && fp.name != "" {
+			fieldName = fp.name
 		}
-		if !field.CanSet() {
-			field = dst.FieldByName(snakeToTitle(col))
+		goField := dst.Type().Name() + "." + dst.Type().FieldByIndex(slot.path).Name
+		if fp != nil && fp.isTime {
+			return attachGoField(decodeErr(assignTimeField(field, val, fp.durationUnit, fp.timeLayout), -1, name, fieldName, val), goField)
 		}
-		if !field.CanSet() {
+		if fp != nil && fp.durationUnit != 0 {
+			field.SetInt(int64(parseFloat(val) * float64(fp.durationUnit)))
 			return nil
 		}
-
-		return parseSingle([]string{col}, []interface{}{val}, emptyTags, field)
+		if fp != nil && fp.setter != nil {
+			return attachGoField(decodeErr(fp.setter(field, name, val), -1, name, fieldName, val), goField)
+		}
+		return attachGoField(decodeErr(parseSingle([]string{name}, []interface{}{val}, emptyTags, field), -1, name, fieldName, val), goField)
 	}
 
 	for i, col := range cols {
-		if err := parse(col, vals[i]); err != nil {
+		if err := apply(rp.cols[i], col, vals[i]); err != nil {
 			return err
 		}
 	}
 	for t, v := range tags {
-		if err := parse(t, v); err != nil {
+		if err := apply(rp.tags[t], t, v); err != nil {
+			return err
+		}
+	}
+
+	for _, i := range rp.defaultFields {
+		fp := &plan.fields[i]
+		field := dst.FieldByIndex(fp.index)
+		var err error
+		if fp.setter != nil {
+			err = fp.setter(field, fp.name, fp.defaultValue)
+		} else {
+			err = parseSingle([]string{fp.name}, []interface{}{fp.defaultValue}, emptyTags, field)
+		}
+		goField := dst.Type().Name() + "." + dst.Type().FieldByIndex(fp.index).Name
+		if err := attachGoField(decodeErr(err, -1, fp.name, fp.name, fp.defaultValue), goField); err != nil {
 			return err
 		}
 	}
+
+	if atomic.LoadInt32(&strictDecoding) != 0 && (len(rp.unmatchedCols) > 0 || len(rp.unfilledFields) > 0) {
+		return &StrictDecodeError{UnmatchedColumns: rp.unmatchedCols, UnfilledFields: rp.unfilledFields}
+	}
 	return nil
 }
 
@@ -208,7 +569,7 @@ func alignToMap(cols []string, vals []interface{}, tags map[string]string, dst r
 		return errors.New("invalid key type")
 	}
 	if len(cols) != len(vals) {
-		return errors.New("columns size not equal values size")
+		return ErrSizeMismatch
 	}
 
 	parse := func(k string, v interface{}) error {
@@ -219,7 +580,7 @@ func alignToMap(cols []string, vals []interface{}, tags map[string]string, dst r
 		if err := parseSingle([]string{k}, []interface{}{v}, emptyTags, val); err != nil {
 			return err
 		}
-		dst.SetMapIndex(reflect.ValueOf(k), val)
+		dst.SetMapIndex(reflect.ValueOf(intern(k)), val)
 		return nil
 	}
 
@@ -242,27 +603,82 @@ func parseSingle(cols []string, vals []interface{}, tags map[string]string, dst
 		return nil
 	}
 
-	var val interface{} = vals[0]
-	if len(columns) > 0 {
-		idx := columnIndex(columns[0], cols)
-		if idx >= 0 {
-			val = vals[idx]
-		} else if v, ok := tags[columns[0]]; ok {
-			val = v
+	valCol, val := resolveRowValue(cols, vals, tags, columns)
+
+	if dst.Kind() == reflect.Ptr {
+		if val == nil {
+			// A null column leaves a pointer field nil instead of
+			// allocating a zero value for it to point to.
+			return nil
 		}
+		return parseSingle(cols, vals, tags, makePtrDstVal(dst), columns...)
+	}
+
+	if ok, err := decodeField(dst, val); ok {
+		return err
+	}
+
+	if val == nil {
+		if atomic.LoadInt32(&strictDecoding) != 0 {
+			return fmt.Errorf("influx: column %q is null", valCol)
+		}
+		if dst.Kind() == reflect.Map && dst.IsNil() {
+			// A nil map, unlike every other zero value, isn't safely
+			// usable by a caller that indexes into it afterward — leave
+			// it allocated-but-empty instead of nil so a destination like
+			// []map[string]interface{} never hands back an element that
+			// panics on the first write.
+			dst.Set(reflect.MakeMap(dst.Type()))
+		}
+		// A null column leaves a non-pointer field at its zero value
+		// instead of running it through parseInt/parseFloat, which
+		// would otherwise turn it into a confusing zero indistinguishable
+		// from an actual 0.
+		return nil
 	}
 
 	switch dst.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		dst.SetInt(parseInt(val))
+		n := parseInt(val)
+		if atomic.LoadInt32(&strictNumericParsing) != 0 {
+			var err error
+			if n, err = checkedParseInt(val); err != nil {
+				return err
+			}
+		}
+		if atomic.LoadInt32(&strictIntDecoding) != 0 {
+			if err := checkIntOverflow(valCol, val, n, dst.Kind()); err != nil {
+				return err
+			}
+		}
+		dst.SetInt(n)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		dst.SetUint(uint64(parseInt(val)))
+		n := parseInt(val)
+		if atomic.LoadInt32(&strictNumericParsing) != 0 {
+			var err error
+			if n, err = checkedParseInt(val); err != nil {
+				return err
+			}
+		}
+		if atomic.LoadInt32(&strictIntDecoding) != 0 {
+			if err := checkIntOverflow(valCol, val, n, dst.Kind()); err != nil {
+				return err
+			}
+		}
+		dst.SetUint(uint64(n))
 	case reflect.Float32, reflect.Float64:
-		dst.SetFloat(parseFloat(val))
+		f := parseFloat(val)
+		if atomic.LoadInt32(&strictNumericParsing) != 0 {
+			var err error
+			if f, err = checkedParseFloat(val); err != nil {
+				return err
+			}
+		}
+		dst.SetFloat(f)
+	case reflect.Bool:
+		dst.SetBool(DecodeBool(val))
 	case reflect.String:
-		dst.SetString(parseString(val))
-	case reflect.Ptr:
-		return parseSingle(cols, vals, tags, makePtrDstVal(dst), columns...)
+		dst.SetString(intern(parseString(val)))
 	case reflect.Struct:
 		return alignToStruct(cols, vals, tags, dst, columns...)
 	case reflect.Slice:
@@ -274,14 +690,14 @@ func parseSingle(cols []string, vals []interface{}, tags map[string]string, dst
 		return alignToMap(cols, vals, tags, dst, columns...)
 	case reflect.Interface:
 		if len(vals) == 1 || len(columns) == 1 {
-			dst.Set(reflect.ValueOf(val))
+			dst.Set(reflect.ValueOf(nativeValue(valCol, val)))
 		} else {
 			mp := reflect.MakeMap(reflect.MapOf(reflect.TypeOf(""), dst.Type()))
 			dst.Set(mp)
 			return alignToMap(cols, vals, tags, mp, columns...)
 		}
 	default:
-		return errors.New("unrecognized type")
+		return &DecodeError{Row: -1, Value: val, Err: errors.New("unrecognized destination kind " + dst.Kind().String())}
 	}
 	return nil
 }
@@ -311,27 +727,147 @@ func inColumns(column string, columns []string) bool {
 }
 
 func ParseResult(dst interface{}, serie models.Row, columns ...string) error {
+	_, err := ParseResultCount(dst, serie, columns...)
+	return err
+}
+
+// ParseResultCount is ParseResult, but also returns the number of rows
+// it actually wrote into dst, so a caller can tell "no data" (0) apart
+// from "data decoded" without reflecting on dst afterwards — and, for
+// a destination smaller than serie (a fixed-size [N]T array with more
+// than N rows), how many of those rows made it in.
+//
+// When dst is a slice reused across repeated calls for zero-alloc
+// decoding, ParseResultCount only ever grows it to fit a longer serie,
+// never shrinks it for a shorter one, so a shorter result leaves stale
+// elements from the previous call trailing past the new row count. Use
+// ParseResultCountTruncate instead when that matters.
+func ParseResultCount(dst interface{}, serie models.Row, columns ...string) (int, error) {
+	return parseResultCount(dst, serie, false, columns...)
+}
+
+// ParseResultCountTruncate is ParseResultCount, but when dst is a
+// slice, truncates it to exactly the decoded row count instead of only
+// ever growing it — the reuse pattern for zero-alloc decoding: keep
+// the same backing slice across repeated calls (pass &dst, not a fresh
+// slice, each time) and ParseResultCountTruncate grows it on a longer
+// result and shrinks it on a shorter one, so it never leaks stale rows
+// from a previous call while still avoiding a new allocation once the
+// backing array is big enough.
+func ParseResultCountTruncate(dst interface{}, serie models.Row, columns ...string) (int, error) {
+	return parseResultCount(dst, serie, true, columns...)
+}
+
+// ParseResultTruncate is ParseResult, but truncates dst to exactly the
+// decoded row count when it's a slice; see ParseResultCountTruncate.
+func ParseResultTruncate(dst interface{}, serie models.Row, columns ...string) error {
+	_, err := parseResultCount(dst, serie, true, columns...)
+	return err
+}
+
+// fastPathFloatSlice is ParseResultCount's non-reflective fast path
+// for a *[]float64 destination — a single numeric column's values
+// across a series, one of the most common ParseResult shapes — built
+// directly on a Go slice instead of, for every row, indexing into a
+// reflect.Value slice and calling parseSingle's full reflect.Kind
+// switch to reach its Float64 case.
+func fastPathFloatSlice(dst *[]float64, cols []string, vals [][]interface{}, tags map[string]string, columns []string, truncate bool) (int, error) {
+	n := len(vals)
+	switch {
+	case len(*dst) < n:
+		grown := make([]float64, n)
+		copy(grown, *dst)
+		*dst = grown
+	case truncate && len(*dst) > n:
+		*dst = (*dst)[:n]
+	}
+
+	for i, vs := range vals {
+		if len(cols) != len(vs) {
+			return 0, decodeErr(ErrSizeMismatch, i, "", "", nil)
+		}
+		valCol, val := resolveRowValue(cols, vs, tags, columns)
+		f, err := scalarFloat64(valCol, val)
+		if err != nil {
+			return 0, decodeErr(err, i, "", "", nil)
+		}
+		(*dst)[i] = f
+	}
+	return n, nil
+}
+
+func parseResultCount(dst interface{}, serie models.Row, truncate bool, columns ...string) (n int, err error) {
+	defer func() {
+		err = attachMeasurement(err, serie.Name)
+	}()
+
 	cols := serie.Columns
 	vals := serie.Values
 	tags := serie.Tags
 	if tags == nil {
 		tags = make(map[string]string)
 	}
-	if len(columns) == 1 {
-		if _, ok := tags[columns[0]]; !ok && !inColumns(columns[0], serie.Columns) {
-			return fmt.Errorf("column not exists: `%v`", columns[0])
+	if len(columns) > 0 {
+		var missing []string
+		for _, col := range columns {
+			if _, ok := tags[col]; !ok && !inColumns(col, serie.Columns) {
+				missing = append(missing, col)
+			}
+		}
+		if len(missing) > 0 {
+			return 0, &MissingColumnsError{Columns: missing}
+		}
+	}
+
+	if p, ok := dst.(*[]float64); ok {
+		if err := checkDecodeBudget(len(vals), float64Type); err != nil {
+			return 0, err
 		}
+		return fastPathFloatSlice(p, cols, vals, tags, columns, truncate)
 	}
 
 	dstVal := reflect.Indirect(reflect.ValueOf(dst))
 	if !dstVal.CanSet() {
-		return errors.New("dst cannot be setted")
+		return 0, ErrNotSettable
 	}
 	dstVal = makePtrDstVal(dstVal)
 
+	if dstVal.Kind() == reflect.Map && dstVal.Type().Key() == timeType {
+		if err := checkDecodeBudget(len(vals), dstVal.Type().Elem()); err != nil {
+			return 0, err
+		}
+		if err := parseResultToTimeMap(dstVal, cols, vals, tags, columns, serie.Name); err != nil {
+			return 0, err
+		}
+		return len(vals), nil
+	}
+
 	toslice := func(dstVal reflect.Value) error {
+		if len(vals) > 0 && fastPathStructSlice(dstVal.Type().Elem(), columns) {
+			plan := planType(dstVal.Type().Elem())
+			if plan.err != nil {
+				return plan.err
+			}
+			rp := planRow(dstVal.Index(0), plan, cols, tags, columns)
+			for i, vs := range vals {
+				if len(cols) != len(vs) {
+					return decodeErr(ErrSizeMismatch, i, "", "", nil)
+				}
+				if err := applyRowPlan(dstVal.Index(i), plan, rp, cols, vs, tags); err != nil {
+					return decodeErr(err, i, "", "", nil)
+				}
+				if err := afterParse(dstVal.Index(i), serie.Name); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
 		for i, vs := range vals {
 			if err := parseSingle(cols, vs, tags, dstVal.Index(i), columns...); err != nil {
+				return decodeErr(err, i, "", "", nil)
+			}
+			if err := afterParse(dstVal.Index(i), serie.Name); err != nil {
 				return err
 			}
 		}
@@ -341,78 +877,1020 @@ func ParseResult(dst interface{}, serie models.Row, columns ...string) error {
 	switch dstVal.Kind() {
 	case reflect.Interface:
 		if len(vals) == 0 {
-			return nil
+			return 0, nil
 		}
 		if len(vals) == 1 {
-			return parseSingle(cols, vals[0], tags, dstVal)
+			if err := parseSingle(cols, vals[0], tags, dstVal); err != nil {
+				return 0, err
+			}
+			if err := afterParse(dstVal, serie.Name); err != nil {
+				return 0, err
+			}
+			return 1, nil
+		}
+		if err := checkDecodeBudget(len(vals), dstVal.Type()); err != nil {
+			return 0, err
 		}
 		slice := reflect.MakeSlice(reflect.SliceOf(dstVal.Type()), len(vals), len(vals))
 		if err := toslice(slice); err != nil {
-			return err
+			return 0, err
 		}
 		dstVal.Set(slice)
 	case reflect.Slice:
+		if err := checkDecodeBudget(len(vals), dstVal.Type().Elem()); err != nil {
+			return 0, err
+		}
 		dstVal = makeSliceDstVal(dstVal, len(vals))
+		if truncate && dstVal.Len() > len(vals) {
+			dstVal.Set(dstVal.Slice(0, len(vals)))
+		}
 		if err := toslice(dstVal); err != nil {
-			return err
+			return 0, err
+		}
+	case reflect.Array:
+		n := dstVal.Len()
+		if len(vals) < n {
+			n = len(vals)
+		}
+		for i := 0; i < n; i++ {
+			if err := parseSingle(cols, vals[i], tags, dstVal.Index(i), columns...); err != nil {
+				return 0, decodeErr(err, i, "", "", nil)
+			}
+			if err := afterParse(dstVal.Index(i), serie.Name); err != nil {
+				return 0, err
+			}
+		}
+		atomic.AddInt64(&arrayElementsWritten, int64(n))
+		return n, nil
+	case reflect.Map:
+		if dstVal.Type().Key().Kind() == reflect.String && dstVal.Type().Elem().Kind() == reflect.Slice {
+			if err := parseResultColumnwise(dstVal, cols, vals, columns); err != nil {
+				return 0, err
+			}
+			return len(vals), nil
 		}
+		if err := parseSingle(cols, vals[0], tags, dstVal, columns...); err != nil {
+			return 0, err
+		}
+		if err := afterParse(dstVal, serie.Name); err != nil {
+			return 0, err
+		}
+		return 1, nil
 	default:
-		return parseSingle(cols, vals[0], tags, dstVal, columns...)
+		if err := parseSingle(cols, vals[0], tags, dstVal, columns...); err != nil {
+			return 0, err
+		}
+		if err := afterParse(dstVal, serie.Name); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+	return len(vals), nil
+}
+
+// parseResultToTimeMap fills dstVal, a map[time.Time]T, from serie's
+// rows, one entry per row keyed by its "time" column, for ParseResult
+// against a map[time.Time]T destination: the natural shape for a
+// sparse time series lookup or gap-filling logic, where a plain []T
+// would force the caller to re-index by row.Time themselves. T is
+// decoded the same way a slice element would be when it's a struct;
+// otherwise dstVal's sole non-time column becomes its value (pass it
+// explicitly as columns when the row carries more than one).
+func parseResultToTimeMap(dstVal reflect.Value, cols []string, vals [][]interface{}, tags map[string]string, columns []string, measurement string) error {
+	if dstVal.IsNil() {
+		dstVal.Set(reflect.MakeMap(dstVal.Type()))
+	}
+	elemType := dstVal.Type().Elem()
+	timeIdx := columnIndex("time", cols)
+
+	valueCol := ""
+	if elemType.Kind() != reflect.Struct {
+		switch {
+		case len(columns) == 1:
+			valueCol = columns[0]
+		case len(cols) == 1:
+			valueCol = cols[0]
+		case len(cols) == 2 && timeIdx >= 0:
+			for _, c := range cols {
+				if c != "time" {
+					valueCol = c
+				}
+			}
+		default:
+			return errors.New("influx: map[time.Time]T needs exactly one non-time column, or a columns argument naming it")
+		}
+	}
+
+	for _, vs := range vals {
+		var t time.Time
+		if timeIdx >= 0 {
+			t = parseTime(vs[timeIdx])
+		}
+
+		elem := reflect.New(elemType).Elem()
+		var err error
+		if valueCol != "" {
+			err = parseSingle([]string{valueCol}, []interface{}{vs[columnIndex(valueCol, cols)]}, tags, elem)
+		} else {
+			err = parseSingle(cols, vs, tags, elem, columns...)
+		}
+		if err != nil {
+			return err
+		}
+		if err := afterParse(elem, measurement); err != nil {
+			return err
+		}
+		dstVal.SetMapIndex(reflect.ValueOf(t), elem)
 	}
 	return nil
 }
 
+// parseResultColumnwise fills dstVal, a map[string][]T, by collecting
+// each column's value across every row into its own slice, keyed by
+// column name — the column-major shape a charting library wants
+// (every x value, then every y value) instead of ParseResult's usual
+// row-major []struct.
+func parseResultColumnwise(dstVal reflect.Value, cols []string, vals [][]interface{}, columns []string) error {
+	if dstVal.IsNil() {
+		dstVal.Set(reflect.MakeMap(dstVal.Type()))
+	}
+	elemType := dstVal.Type().Elem() // []T
+	itemType := elemType.Elem()      // T
+
+	for i, col := range cols {
+		if !inColumns(col, columns) {
+			continue
+		}
+		sl := reflect.MakeSlice(elemType, len(vals), len(vals))
+		for r, vs := range vals {
+			item := reflect.New(itemType).Elem()
+			if err := parseSingle([]string{col}, []interface{}{vs[i]}, emptyTags, item); err != nil {
+				return decodeErr(err, r, col, "", vs[i])
+			}
+			sl.Index(r).Set(item)
+		}
+		dstVal.SetMapIndex(reflect.ValueOf(col), sl)
+	}
+	return nil
+}
+
+// ParseResults decodes every row, across every series of every result,
+// into dst, a pointer to a slice of T, concatenating them in order
+// instead of requiring a caller to loop over []client.Result and call
+// ParseResult per series by hand. It returns the first result's own
+// error, InfluxDB's way of reporting a single statement's failure
+// inline in a multi-statement query instead of failing the whole
+// request.
+func ParseResults(dst interface{}, results []client.Result, columns ...string) error {
+	_, err := ParseResultsCount(dst, results, columns...)
+	return err
+}
+
+// ParseResultsCount is ParseResults, but also returns the number of
+// rows it decoded into dst, the same way ParseResultCount does for a
+// single series, so a caller can tell "no data" (0) apart from "data
+// decoded" without reflecting on dst afterwards.
+func ParseResultsCount(dst interface{}, results []client.Result, columns ...string) (int, error) {
+	dstVal := reflect.Indirect(reflect.ValueOf(dst))
+	if dstVal.Kind() != reflect.Slice {
+		return 0, errors.New("influx: ParseResults needs a pointer to a slice")
+	}
+	elemType := dstVal.Type().Elem()
+
+	var rowCount int
+	for _, result := range results {
+		for _, serie := range result.Series {
+			rowCount += len(serie.Values)
+		}
+	}
+	if err := checkDecodeBudget(rowCount, elemType); err != nil {
+		return 0, err
+	}
+
+	slice := reflect.MakeSlice(dstVal.Type(), 0, 0)
+	row := 0
+	for _, result := range results {
+		if result.Err != "" {
+			return 0, errors.New(result.Err)
+		}
+		for _, serie := range result.Series {
+			tags := serie.Tags
+			if tags == nil {
+				tags = make(map[string]string)
+			}
+			for _, vals := range serie.Values {
+				elem := reflect.New(elemType).Elem()
+				if err := parseSingle(serie.Columns, vals, tags, elem, columns...); err != nil {
+					return 0, attachMeasurement(decodeErr(err, row, "", "", nil), serie.Name)
+				}
+				if err := afterParse(elem, serie.Name); err != nil {
+					return 0, attachMeasurement(err, serie.Name)
+				}
+				slice = reflect.Append(slice, elem)
+				row++
+			}
+		}
+	}
+	dstVal.Set(slice)
+	return row, nil
+}
+
+// ParseResponse is ParseResults over a *client.Response instead of its
+// already-unwrapped Results, for a caller holding one straight from
+// client.HTTPClient.Query (or any other code that builds a
+// client.Response directly) instead of this package's own Query, which
+// already returns []client.Result. It returns resp's own top-level Err
+// before ParseResults ever sees Results, the same way a malformed
+// request fails outright rather than as a per-statement error.
+func ParseResponse(dst interface{}, resp *client.Response, columns ...string) error {
+	_, err := ParseResponseCount(dst, resp, columns...)
+	return err
+}
+
+// ParseResponseCount is ParseResponse, but also returns the number of
+// rows it decoded into dst, the same way ParseResultsCount does.
+func ParseResponseCount(dst interface{}, resp *client.Response, columns ...string) (int, error) {
+	if resp.Err != "" {
+		return 0, errors.New(resp.Err)
+	}
+	return ParseResultsCount(dst, resp.Results, columns...)
+}
+
+// groupKey turns a series' Tags into a map key for ParseGrouped: a
+// single-tag GROUP BY (the common case) keys by that tag's bare value,
+// e.g. "web1" for GROUP BY "host"; a multi-tag one keys by every tag's
+// "name=value" joined with ",", sorted by name since map iteration
+// order isn't, e.g. "dc=us,host=web1" for GROUP BY "host", "dc".
+func groupKey(tags map[string]string) string {
+	if len(tags) == 1 {
+		for _, v := range tags {
+			return v
+		}
+	}
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + "=" + tags[name]
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseGrouped decodes results the same way ParseResults does, but into
+// dst, a pointer to a map[string][]T, one slice per series instead of
+// one concatenated slice: a GROUP BY "host" query splits into one
+// series per host, and a caller almost always wants the corresponding
+// per-host slices without a manual loop matching each series' Tags
+// itself. See groupKey for how a series' Tags become its map key.
+func ParseGrouped(dst interface{}, results []client.Result, columns ...string) error {
+	dstVal := reflect.Indirect(reflect.ValueOf(dst))
+	dstType := dstVal.Type()
+	if dstVal.Kind() != reflect.Map || dstType.Key().Kind() != reflect.String || dstType.Elem().Kind() != reflect.Slice {
+		return errors.New("influx: ParseGrouped needs a pointer to a map[string][]T")
+	}
+	if dstVal.IsNil() {
+		dstVal.Set(reflect.MakeMap(dstType))
+	}
+	elemType := dstType.Elem().Elem()
+
+	row := 0
+	for _, result := range results {
+		if result.Err != "" {
+			return errors.New(result.Err)
+		}
+		for _, serie := range result.Series {
+			tags := serie.Tags
+			if tags == nil {
+				tags = make(map[string]string)
+			}
+			key := reflect.ValueOf(groupKey(tags))
+
+			slice := dstVal.MapIndex(key)
+			if !slice.IsValid() {
+				slice = reflect.MakeSlice(dstType.Elem(), 0, len(serie.Values))
+			}
+			for _, vals := range serie.Values {
+				elem := reflect.New(elemType).Elem()
+				if err := parseSingle(serie.Columns, vals, tags, elem, columns...); err != nil {
+					return attachMeasurement(decodeErr(err, row, "", "", nil), serie.Name)
+				}
+				if err := afterParse(elem, serie.Name); err != nil {
+					return attachMeasurement(err, serie.Name)
+				}
+				slice = reflect.Append(slice, elem)
+				row++
+			}
+			dstVal.SetMapIndex(key, slice)
+		}
+	}
+	return nil
+}
+
+// ParseGroupedByTag is ParseGrouped for rows, a single Result's Series
+// already unwrapped, keyed by just byTag's value on each row instead of
+// every tag combined via groupKey: a GROUP BY "host", "region" query
+// that only cares about splitting by host, ignoring region, would
+// otherwise need to post-process ParseGrouped's composite keys itself.
+// A row missing byTag groups under the empty string.
+func ParseGroupedByTag(dst interface{}, rows []models.Row, byTag string, columns ...string) error {
+	dstVal := reflect.Indirect(reflect.ValueOf(dst))
+	dstType := dstVal.Type()
+	if dstVal.Kind() != reflect.Map || dstType.Key().Kind() != reflect.String || dstType.Elem().Kind() != reflect.Slice {
+		return errors.New("influx: ParseGroupedByTag needs a pointer to a map[string][]T")
+	}
+	if dstVal.IsNil() {
+		dstVal.Set(reflect.MakeMap(dstType))
+	}
+	elemType := dstType.Elem().Elem()
+
+	row := 0
+	for _, serie := range rows {
+		tags := serie.Tags
+		if tags == nil {
+			tags = make(map[string]string)
+		}
+		key := reflect.ValueOf(tags[byTag])
+
+		slice := dstVal.MapIndex(key)
+		if !slice.IsValid() {
+			slice = reflect.MakeSlice(dstType.Elem(), 0, len(serie.Values))
+		}
+		for _, vals := range serie.Values {
+			elem := reflect.New(elemType).Elem()
+			if err := parseSingle(serie.Columns, vals, tags, elem, columns...); err != nil {
+				return attachMeasurement(decodeErr(err, row, "", "", nil), serie.Name)
+			}
+			if err := afterParse(elem, serie.Name); err != nil {
+				return attachMeasurement(err, serie.Name)
+			}
+			slice = reflect.Append(slice, elem)
+			row++
+		}
+		dstVal.SetMapIndex(key, slice)
+	}
+	return nil
+}
+
+// afterParse fills v's inf:",measurement" field (see fillMeasurementField)
+// with measurement, then calls AfterParse on v if it (or its address)
+// implements AfterParser.
+func afterParse(v reflect.Value, measurement string) error {
+	if !v.CanAddr() {
+		return nil
+	}
+	fillMeasurementField(v, measurement)
+	if ap, ok := v.Addr().Interface().(AfterParser); ok {
+		return ap.AfterParse()
+	}
+	return nil
+}
+
+// fillMeasurementField sets dst's inf:",measurement" field, if it has
+// one (see typePlan.measurementField), to measurement, the series' name
+// the row dst was just decoded from. dst that isn't a struct, or a
+// blank measurement (no series to attribute it to), is left untouched.
+func fillMeasurementField(dst reflect.Value, measurement string) {
+	if measurement == "" || dst.Kind() != reflect.Struct {
+		return
+	}
+	plan := planType(dst.Type())
+	if plan.err != nil || plan.measurementField < 0 {
+		return
+	}
+	f := dst.FieldByIndex(plan.fields[plan.measurementField].index)
+	if f.Kind() == reflect.String && f.CanSet() {
+		f.SetString(measurement)
+	}
+}
+
 // - - - - - - - - - - - - - - - - - - - - -
 
-func ToPoint(structure interface{}) *client.Point {
-	val := reflect.ValueOf(structure)
-	method := val.MethodByName("Measurement")
-	val = reflect.Indirect(val)
+// ErrNoFields is returned by ToPoint when structure has no non-tag
+// fields left to write, either because it declared none or because
+// SanitizeFields dropped the only ones it had: client.NewPoint rejects
+// a point with no fields outright, so ToPoint fails closed with this
+// typed error instead of the vendor's untyped one.
+type ErrNoFields struct {
+	Measurement string
+	Type        string
+}
+
+func (e *ErrNoFields) Error() string {
+	return fmt.Sprintf("influx: %s (%s) has no fields", e.Measurement, e.Type)
+}
+
+// ErrZeroTime is returned by ToPoint under StrictTime when the struct's
+// time field resolves to the zero time.Time instead of falling back to
+// time.Now().
+var ErrZeroTime = errors.New("influx: time field is zero")
+
+func ToPoint(structure interface{}, opts ...FieldOption) (*client.Point, error) {
+	return toPoint(context.Background(), structure, "", opts, -1, nil)
+}
+
+// ToPointContext is ToPoint, but also attaches any tag registered with
+// RegisterContextTag that ctx carries a value for, so a request-scoped
+// correlation tag (trace ID, tenant, route) doesn't need manual
+// plumbing through every struct a handler writes.
+func ToPointContext(ctx context.Context, structure interface{}, opts ...FieldOption) (*client.Point, error) {
+	return toPoint(ctx, structure, "", opts, -1, nil)
+}
+
+// ToPointAt is ToPoint, but stamps the point with t instead of
+// whatever ToPoint would otherwise derive (a Time field, or
+// time.Now()), for a batch backfill job assigning historical
+// timestamps to structs that have no Time field of their own to carry
+// them.
+func ToPointAt(structure interface{}, t time.Time, opts ...FieldOption) (*client.Point, error) {
+	return toPoint(context.Background(), structure, "", opts, -1, &t)
+}
+
+// ToPointNamed is ToPoint, but writes to measurement instead of
+// whatever ToPoint would otherwise derive (a PointMarshaler, a
+// Measurementer, a `measurement=` inf tag, or the snake_cased type
+// name), so one struct type can be written to several measurements
+// (e.g. staging vs. canary) without defining wrapper types just to
+// rename it.
+func ToPointNamed(measurement string, structure interface{}, opts ...FieldOption) (*client.Point, error) {
+	return toPoint(context.Background(), structure, measurement, opts, -1, nil)
+}
+
+// ToPointNamedContext is ToPointNamed with ToPointContext's context tag
+// support.
+func ToPointNamedContext(ctx context.Context, measurement string, structure interface{}, opts ...FieldOption) (*client.Point, error) {
+	return toPoint(ctx, structure, measurement, opts, -1, nil)
+}
+
+// ExplodePoints converts structure into one *client.Point per element
+// of its slice field tagged `inf:"name,explode"`: each point holds one
+// element as that field's value, with every other field and tag
+// unchanged, instead of the single point ToPoint would build with the
+// whole slice inside it. Use it for a struct that batches several
+// samples that each deserve their own point, e.g. one upstream event
+// carrying a slice of per-second readings. It's an error to call it on
+// a struct with no `,explode` field; see sliceStrategy for the join and
+// indexed strategies that stay within a single point.
+func ExplodePoints(structure interface{}, opts ...FieldOption) ([]*client.Point, error) {
+	val := reflect.Indirect(reflect.ValueOf(structure))
 	if val.Kind() != reflect.Struct {
-		return nil
+		return nil, fmt.Errorf("influx: ExplodePoints: %w", ErrNotPointer)
 	}
-	if !method.IsValid() {
-		method = val.MethodByName("Measurement")
+	plan := planType(val.Type())
+	if plan.err != nil {
+		return nil, plan.err
 	}
 
-	measurement := ""
-	if method.IsValid() {
-		measurement = method.Call(nil)[0].Interface().(string)
-	} else {
-		name := val.Type().Name()
-		if idx := strings.LastIndexByte(name, '.'); idx >= 0 {
-			name = name[idx+1:]
+	n := -1
+	for _, fp := range plan.fields {
+		if fp.sliceStrategy != sliceExplode {
+			continue
+		}
+		l := val.FieldByIndex(fp.index).Len()
+		if n == -1 {
+			n = l
+		} else if l != n {
+			return nil, fmt.Errorf("influx: exploded slice fields %q have mismatched lengths (%d vs %d)", fp.name, n, l)
+		}
+	}
+	if n == -1 {
+		return nil, errors.New("influx: ExplodePoints needs a field tagged \",explode\"")
+	}
+
+	points := make([]*client.Point, 0, n)
+	for i := 0; i < n; i++ {
+		p, err := toPoint(context.Background(), structure, "", opts, i, nil)
+		if err != nil {
+			return nil, err
 		}
-		measurement = titleToSnake(name)
+		points = append(points, p)
 	}
+	return points, nil
+}
 
-	typ := val.Type()
-	tags := make(map[string]string)
-	fields := make(map[string]interface{})
-	now := time.Now()
+func toPoint(ctx context.Context, structure interface{}, measurementOverride string, opts []FieldOption, explodeIndex int, timeOverride *time.Time) (*client.Point, error) {
+	filter := newFieldFilter(opts)
 
-	for i := 0; i < val.NumField(); i++ {
-		fv := val.Field(i)
-		ft := typ.Field(i)
-		if ft.Tag.Get("inf") == "-" {
+	if marshaler, ok := structure.(PointMarshaler); ok {
+		measurement, tags, fields, t, err := marshaler.MarshalPoint()
+		if err != nil {
+			return nil, err
+		}
+		if measurementOverride != "" {
+			measurement = measurementOverride
+		}
+		if timeOverride != nil {
+			t = *timeOverride
+		}
+		for name, value := range contextTags(ctx) {
+			if _, exists := tags[name]; exists || (filter != nil && !filter.allowed(name)) {
+				continue
+			}
+			if err := setSanitizedTag(tags, name, value); err != nil {
+				return nil, err
+			}
+		}
+		if err := validateMeasurement(measurement); err != nil {
+			return nil, err
+		}
+		return client.NewPoint(measurement, tags, fields, t)
+	}
+
+	if before, ok := structure.(BeforeInserter); ok {
+		if err := before.BeforeInsert(); err != nil {
+			return nil, err
+		}
+	}
+
+	val := reflect.Indirect(reflect.ValueOf(structure))
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("influx: ToPoint: %w", ErrNotPointer)
+	}
+
+	plan := planType(val.Type())
+	if plan.err != nil {
+		return nil, plan.err
+	}
+
+	measurement := plan.measurement
+	if m, ok := structure.(Measurementer); ok {
+		measurement = m.Measurement()
+	}
+	if measurementOverride != "" {
+		measurement = measurementOverride
+	}
+
+	tags := tagsPool.Get().(map[string]string)
+	defer putTags(tags)
+	for name, value := range contextTags(ctx) {
+		if !filter.allowed(name) {
+			continue
+		}
+		if err := setSanitizedTag(tags, name, value); err != nil {
+			return nil, err
+		}
+	}
+	if overrider, ok := structure.(TagOverrider); ok {
+		for k, v := range overrider.Tags() {
+			if filter.allowed(k) {
+				if err := setSanitizedTag(tags, k, v); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	fields := fieldsPool.Get().(map[string]interface{})
+	defer putFields(fields)
+	now := clockNow()
+
+	for _, fp := range plan.fields {
+		fv := val.FieldByIndex(fp.index)
+		if fp.isTime {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Type() == timeType {
+				now = fv.Interface().(time.Time)
+			} else if fv.Kind() == reflect.String {
+				t, err := time.Parse(fp.timeLayout, fv.String())
+				if err != nil {
+					return nil, fmt.Errorf("influx: parse time field: %w", err)
+				}
+				now = t
+			} else {
+				now = time.Unix(0, fv.Int()*int64(fp.durationUnit))
+			}
+			continue
+		}
+		if fp.isMapFields {
+			for _, k := range fv.MapKeys() {
+				name := k.String()
+				if !filter.allowed(name) {
+					continue
+				}
+				ev := fv.MapIndex(k)
+				value := ev.Interface()
+				if out, ok, err := encodeField(ev); ok {
+					if err != nil {
+						return nil, err
+					}
+					value = out
+				}
+				value, err := adjustUint64Field(value)
+				if err != nil {
+					return nil, err
+				}
+				fields[name] = coerceField(value)
+			}
+			continue
+		}
+		if fp.isMapTags {
+			for _, k := range fv.MapKeys() {
+				name := k.String()
+				if !filter.allowed(name) {
+					continue
+				}
+				ev := fv.MapIndex(k)
+				value := ev.Interface()
+				if out, ok, err := encodeField(ev); ok {
+					if err != nil {
+						return nil, err
+					}
+					value = out
+				}
+				if err := setSanitizedTag(tags, name, parseString(value)); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if !filter.allowed(fp.name) {
+			continue
+		}
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		if fp.omitempty && fv.IsZero() {
 			continue
 		}
-		tagstr := ft.Tag.Get("inf")
-		if ft.Name == "Time" || tagstr == "time" {
-			now = fv.Interface().(time.Time)
+
+		if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 {
+			switch fp.sliceStrategy {
+			case sliceExplode:
+				if explodeIndex < 0 {
+					return nil, fmt.Errorf("influx: field %q is tagged \",explode\"; use ExplodePoints instead of ToPoint", fp.name)
+				}
+				ev := fv.Index(explodeIndex)
+				value := ev.Interface()
+				if out, ok, err := encodeField(ev); ok {
+					if err != nil {
+						return nil, err
+					}
+					value = out
+				}
+				value, err := adjustUint64Field(value)
+				if err != nil {
+					return nil, err
+				}
+				fields[fp.name] = coerceField(value)
+			case sliceIndexed:
+				for i := 0; i < fv.Len(); i++ {
+					ev := fv.Index(i)
+					value := ev.Interface()
+					if out, ok, err := encodeField(ev); ok {
+						if err != nil {
+							return nil, err
+						}
+						value = out
+					}
+					value, err := adjustUint64Field(value)
+					if err != nil {
+						return nil, err
+					}
+					fields[fmt.Sprintf("%s_%d", fp.name, i)] = coerceField(value)
+				}
+			default: // sliceJoin
+				parts := make([]string, fv.Len())
+				for i := range parts {
+					ev := fv.Index(i)
+					value := ev.Interface()
+					if out, ok, err := encodeField(ev); ok {
+						if err != nil {
+							return nil, err
+						}
+						value = out
+					}
+					parts[i] = parseString(value)
+				}
+				fields[fp.name] = strings.Join(parts, fp.sliceSep)
+			}
 			continue
 		}
 
-		name := strings.Split(tagstr, ",")[0]
-		if name == "" {
-			name = titleToSnake(ft.Name)
+		var value interface{}
+		if fp.durationUnit != 0 {
+			value = float64(fv.Int()) / float64(fp.durationUnit)
+		} else {
+			value = fv.Interface()
+			if out, ok, err := encodeField(fv); ok {
+				if err != nil {
+					return nil, err
+				}
+				value = out
+			}
 		}
-		if strings.HasSuffix(tagstr, ",tag") {
-			tags[name] = parseString(fv.Interface())
+
+		if fp.isTag {
+			tagValue := parseString(value)
+			if b, ok := value.(bool); ok && fp.boolTagAsInt {
+				tagValue = boolToIntString(b)
+			}
+			if err := setSanitizedTag(tags, fp.name, tagValue); err != nil {
+				return nil, err
+			}
+		} else if fp.asString { // fields, forced to a string field
+			fields[fp.name] = parseString(value)
 		} else { // fields
-			fields[name] = fv.Interface()
+			value, err := adjustUint64Field(value)
+			if err != nil {
+				return nil, err
+			}
+			fields[fp.name] = coerceField(value)
+		}
+	}
+
+	if timeOverride != nil {
+		now = *timeOverride
+	} else if now.IsZero() {
+		switch {
+		case filter != nil && filter.strictTime:
+			return nil, ErrZeroTime
+		case filter != nil && filter.serverTime:
+			// Leave now zero: client.NewPoint drops a zero timestamp from
+			// the line protocol, so InfluxDB assigns its own receipt time.
+		default:
+			now = clockNow()
+		}
+	}
+
+	if !sanitizeFields(fields) {
+		return nil, ErrPointDropped
+	}
+	if len(fields) == 0 {
+		return nil, &ErrNoFields{Measurement: measurement, Type: val.Type().Name()}
+	}
+	if err := validateMeasurement(measurement); err != nil {
+		return nil, err
+	}
+	return client.NewPoint(measurement, tags, fields, now)
+}
+
+// PointFromMap builds a *client.Point directly from tags and fields
+// maps instead of a struct, for a dynamic payload (e.g. a webhook body
+// decoded into map[string]interface{}) that has no fixed struct type
+// to run through ToPoint. It applies the same NaN/±Inf sanitize policy
+// (SetSanitizePolicy), tag/measurement validation (SetTagSanitizePolicy)
+// and no-fields check ToPoint does, and defaults t to clockNow() if
+// zero.
+func PointFromMap(measurement string, tags map[string]string, fields map[string]interface{}, t time.Time) (*client.Point, error) {
+	if t.IsZero() {
+		t = clockNow()
+	}
+	if err := validateMeasurement(measurement); err != nil {
+		return nil, err
+	}
+
+	sanitizedTags := make(map[string]string, len(tags))
+	for name, value := range tags {
+		if err := setSanitizedTag(sanitizedTags, name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if !sanitizeFields(fields) {
+		return nil, ErrPointDropped
+	}
+	if len(fields) == 0 {
+		return nil, &ErrNoFields{Measurement: measurement, Type: "map[string]interface{}"}
+	}
+	return client.NewPoint(measurement, sanitizedTags, fields, t)
+}
+
+// PointFromMapKeys is PointFromMap, but splits a single
+// map[string]interface{} into tags and fields itself: any key in
+// tagKeys becomes a tag, converted to a string the same way a `,tag`
+// struct field's value is (see parseString); every other key becomes a
+// field, unconverted.
+func PointFromMapKeys(measurement string, m map[string]interface{}, tagKeys []string, t time.Time) (*client.Point, error) {
+	tagSet := make(map[string]bool, len(tagKeys))
+	for _, k := range tagKeys {
+		tagSet[k] = true
+	}
+
+	tags := make(map[string]string, len(tagKeys))
+	fields := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if tagSet[k] {
+			tags[k] = parseString(v)
+		} else {
+			fields[k] = v
+		}
+	}
+	return PointFromMap(measurement, tags, fields, t)
+}
+
+// ToPoints converts each element of slice, a slice of structs or
+// pointers to structs, to a *client.Point via ToPoint. An element
+// dropped by SanitizeDropPoint is left out of the result instead of
+// failing the whole slice.
+func ToPoints(slice interface{}, opts ...FieldOption) ([]*client.Point, error) {
+	return ToPointsContext(context.Background(), slice, opts...)
+}
+
+// ToPointsContext is ToPoints, but converts each element with
+// ToPointContext instead of ToPoint, so a batch write still picks up
+// any registered context tag.
+func ToPointsContext(ctx context.Context, slice interface{}, opts ...FieldOption) ([]*client.Point, error) {
+	val := reflect.Indirect(reflect.ValueOf(slice))
+	if val.Kind() != reflect.Slice {
+		return nil, errors.New("influx: ToPoints needs a slice")
+	}
+
+	points := make([]*client.Point, 0, val.Len())
+	for i := 0; i < val.Len(); i++ {
+		p, err := ToPointContext(ctx, val.Index(i).Interface(), opts...)
+		if err == ErrPointDropped {
+			continue
+		}
+		if err != nil {
+			return nil, err
 		}
+		points = append(points, p)
 	}
-	point, _ := client.NewPoint(measurement, tags, fields, now)
-	return point
+	return points, nil
+}
+
+// ToPointsNamed is ToPoints, but writes every element to measurement
+// instead of whatever each would otherwise derive (see ToPointNamed),
+// for a batch of one struct type destined for a per-tenant or
+// per-environment measurement name.
+func ToPointsNamed(measurement string, slice interface{}, opts ...FieldOption) ([]*client.Point, error) {
+	return ToPointsNamedContext(context.Background(), measurement, slice, opts...)
+}
+
+// ToPointsNamedContext is ToPointsNamed, but converts each element with
+// ToPointNamedContext instead of ToPointNamed, so a batch write still
+// picks up any registered context tag.
+func ToPointsNamedContext(ctx context.Context, measurement string, slice interface{}, opts ...FieldOption) ([]*client.Point, error) {
+	val := reflect.Indirect(reflect.ValueOf(slice))
+	if val.Kind() != reflect.Slice {
+		return nil, errors.New("influx: ToPointsNamed needs a slice")
+	}
+
+	points := make([]*client.Point, 0, val.Len())
+	for i := 0; i < val.Len(); i++ {
+		p, err := ToPointNamedContext(ctx, measurement, val.Index(i).Interface(), opts...)
+		if err == ErrPointDropped {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// ToBatchPoints converts values, a slice of structs or pointers to
+// structs (possibly of different types, so a mixed-measurement batch
+// can be built in one call), into a client.BatchPoints ready to write
+// with db as its database, via ToPoints. Points may name their own
+// measurement (a Measurementer, a PointMarshaler, or a struct's
+// snake_cased type name); ToBatchPoints only supplies db and the
+// nanosecond precision client.NewBatchPoints defaults to.
+func ToBatchPoints(db string, values interface{}, opts ...FieldOption) (client.BatchPoints, error) {
+	return ToBatchPointsContext(context.Background(), db, values, opts...)
+}
+
+// ToBatchPointsContext is ToBatchPoints, but converts values with
+// ToPointsContext instead of ToPoints, so the batch still picks up any
+// registered context tag.
+func ToBatchPointsContext(ctx context.Context, db string, values interface{}, opts ...FieldOption) (client.BatchPoints, error) {
+	points, err := ToPointsContext(ctx, values, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{
+		Database:  db,
+		Precision: "ns",
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range points {
+		bp.AddPoint(p)
+	}
+	return bp, nil
+}
+
+// MarshalLineProtocol converts structOrSlice, a struct, a pointer to
+// one, or a slice of either (the same inputs ToPoint and ToPoints
+// accept), to InfluxDB line protocol at nanosecond precision, one line
+// per point, so points can be written to a file, a Kafka topic or a
+// Telegraf socket listener without going through client.Point or a
+// live Client.
+func MarshalLineProtocol(structOrSlice interface{}) ([]byte, error) {
+	return AppendLineProtocol(nil, structOrSlice)
+}
+
+// ToLineProtocol is MarshalLineProtocol, returning a string instead of a
+// []byte, for a caller that's just going to convert it to one anyway
+// (e.g. to hand to a Kafka producer or an io.Writer taking a string).
+func ToLineProtocol(structOrSlice interface{}) (string, error) {
+	b, err := MarshalLineProtocol(structOrSlice)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// AppendLineProtocol is MarshalLineProtocol, but appends the encoded
+// line(s) to dst instead of allocating a fresh buffer, so a caller
+// batching many structs into one growing []byte (e.g. before a single
+// write to a file or Kafka topic) pays one growth curve instead of one
+// allocation per struct.
+func AppendLineProtocol(dst []byte, structOrSlice interface{}) ([]byte, error) {
+	var points []*client.Point
+	if reflect.Indirect(reflect.ValueOf(structOrSlice)).Kind() == reflect.Slice {
+		pts, err := ToPoints(structOrSlice)
+		if err != nil {
+			return dst, err
+		}
+		points = pts
+	} else {
+		p, err := ToPoint(structOrSlice)
+		if err != nil {
+			return dst, err
+		}
+		points = []*client.Point{p}
+	}
+
+	for _, p := range points {
+		dst = append(dst, p.PrecisionString("ns")...)
+		dst = append(dst, '\n')
+	}
+	return dst, nil
+}
+
+// UnmarshalLineProtocol parses data as InfluxDB line protocol and
+// decodes it into dst, the inverse of ToPoint/MarshalLineProtocol:
+// tags, fields and the timestamp are mapped by the same `inf` tags. dst
+// must be a pointer to a struct, decoded from the first point in data,
+// or a pointer to a slice of structs, decoded one element per point.
+// Useful for tests and for consuming Telegraf's line-protocol output.
+func UnmarshalLineProtocol(data []byte, dst interface{}) error {
+	pts, err := models.ParsePoints(data)
+	if err != nil {
+		return err
+	}
+
+	dstVal := reflect.Indirect(reflect.ValueOf(dst))
+	if !dstVal.CanSet() {
+		return errors.New("influx: UnmarshalLineProtocol needs a pointer to a struct or a slice of structs")
+	}
+
+	if dstVal.Kind() == reflect.Slice {
+		dstVal = makeSliceDstVal(dstVal, len(pts))
+		for i, pt := range pts {
+			cp := client.NewPointFrom(pt)
+			if err := unmarshalPoint(cp, dstVal.Index(i)); err != nil {
+				return err
+			}
+			if err := afterParse(dstVal.Index(i), cp.Name()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(pts) == 0 {
+		return errors.New("influx: no points in data")
+	}
+	cp := client.NewPointFrom(pts[0])
+	if err := unmarshalPoint(cp, dstVal); err != nil {
+		return err
+	}
+	return afterParse(dstVal, cp.Name())
+}
+
+// unmarshalPoint decodes p into dst by reusing alignToStruct: p's
+// fields and a synthetic "time" column stand in for the columns/values
+// a query result row would carry, and p's tags for its tags.
+func unmarshalPoint(p *client.Point, dst reflect.Value) error {
+	dst = makePtrDstVal(dst)
+
+	fields, err := p.Fields()
+	if err != nil {
+		return err
+	}
+	cols := make([]string, 0, len(fields)+1)
+	vals := make([]interface{}, 0, len(fields)+1)
+	for k, v := range fields {
+		cols = append(cols, k)
+		vals = append(vals, v)
+	}
+	cols = append(cols, "time")
+	vals = append(vals, p.Time())
+
+	return alignToStruct(cols, vals, p.Tags(), dst)
 }
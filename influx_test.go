@@ -0,0 +1,4247 @@
+package influx
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/models"
+)
+
+// TestToPointEncodesStringOptionFieldAsString confirms a numeric/bool
+// field tagged `,string` is encoded as a string field instead of its
+// native type, for a measurement whose schema already established that
+// field as a string.
+func TestToPointEncodesStringOptionFieldAsString(t *testing.T) {
+	type status struct {
+		Code  int     `inf:"code,string"`
+		Ok    bool    `inf:"ok,string"`
+		Ratio float64 `inf:"ratio,string"`
+	}
+
+	p, err := ToPoint(status{Code: 404, Ok: true, Ratio: 0.5})
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	ratio, ok := fields["ratio"].(string)
+	if fields["code"] != "404" || fields["ok"] != "true" || !ok || parseFloat(ratio) != 0.5 {
+		t.Fatalf("fields = %v, want code=404 ok=true ratio=0.5, all strings", fields)
+	}
+}
+
+// TestParseResultDecodesStringOptionFieldBack confirms ParseResult
+// parses a string-valued column back into a numeric/bool field the
+// same way it would a native numeric/bool column, so `,string` round-
+// trips without its own decode-side tag option.
+func TestParseResultDecodesStringOptionFieldBack(t *testing.T) {
+	type status struct {
+		Code int  `inf:"code,string"`
+		Ok   bool `inf:"ok,string"`
+	}
+
+	row := models.Row{
+		Columns: []string{"code", "ok"},
+		Values:  [][]interface{}{{"404", "true"}},
+	}
+
+	var m status
+	if err := ParseResult(&m, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if m.Code != 404 || !m.Ok {
+		t.Fatalf("ParseResult = %+v, want {Code:404 Ok:true}", m)
+	}
+}
+
+// TestToPointEncodesDurationFieldWithUnit confirms ToPoint encodes a
+// time.Duration field as a numeric field scaled by the unit declared by
+// its inf tag, the write side of TestParseResultFillsDurationField's
+// unit.
+func TestToPointEncodesDurationFieldWithUnit(t *testing.T) {
+	type latency struct {
+		Latency time.Duration `inf:"latency,ms"`
+	}
+
+	p, err := ToPoint(latency{Latency: 150 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if fields["latency"] != float64(150) {
+		t.Fatalf("fields = %v, want latency=150", fields)
+	}
+}
+
+// TestParseResultFillsDurationField confirms ParseResult decodes a numeric
+// column into a time.Duration field using the unit declared by its inf
+// tag (inf:"latency,ms"), the read side of the unit ToPoint already
+// applies when encoding that same field.
+func TestParseResultFillsDurationField(t *testing.T) {
+	type latency struct {
+		Latency time.Duration `inf:"latency,ms"`
+	}
+
+	row := models.Row{
+		Columns: []string{"latency"},
+		Values:  [][]interface{}{{float64(150)}},
+	}
+
+	var m latency
+	if err := ParseResult(&m, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if m.Latency != 150*time.Millisecond {
+		t.Fatalf("ParseResult = %v, want 150ms", m.Latency)
+	}
+}
+
+// TestParseResultUsesRegisteredTimeLayout confirms parseTime falls back
+// to a layout added by RegisterTimeLayout when a string time value isn't
+// RFC3339.
+func TestParseResultUsesRegisteredTimeLayout(t *testing.T) {
+	const layout = "2006-01-02 15:04:05"
+	RegisterTimeLayout(layout)
+
+	row := models.Row{
+		Columns: []string{"time"},
+		Values:  [][]interface{}{{"2023-05-06 07:08:09"}},
+	}
+
+	var ts time.Time
+	if err := ParseResult(&ts, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	want, _ := time.Parse(layout, "2023-05-06 07:08:09")
+	if !ts.Equal(want) {
+		t.Fatalf("ParseResult = %v, want %v", ts, want)
+	}
+}
+
+// TestParseResultWithPrecisionScalesEpochTime confirms
+// ParseResultWithPrecision interprets a numeric "time" column as an
+// epoch count in the given precision instead of guessing it from
+// magnitude, for a query issued with epoch=ms, and that
+// ParseResultWithPrecision's override doesn't leak into a later
+// ParseResult call once it returns.
+func TestParseResultWithPrecisionScalesEpochTime(t *testing.T) {
+	type metric struct {
+		Time  time.Time
+		Value float64
+	}
+
+	row := models.Row{
+		Columns: []string{"time", "value"},
+		Values:  [][]interface{}{{float64(1700000000123), float64(42)}},
+	}
+
+	var m metric
+	if err := ParseResultWithPrecision(&m, row, "ms"); err != nil {
+		t.Fatalf("ParseResultWithPrecision: %v", err)
+	}
+	if want := time.UnixMilli(1700000000123).UTC(); !m.Time.Equal(want) {
+		t.Fatalf("Time = %v, want %v", m.Time, want)
+	}
+
+	// Without an explicit precision, guessEpochUnit's magnitude-based
+	// heuristic lands on the same unit for this value anyway (it's
+	// unambiguously a millisecond epoch, far too small for nanoseconds
+	// and too large for seconds), so the result matches m above instead
+	// of the year-1970 date a hardcoded nanosecond assumption would give.
+	var m2 metric
+	if err := ParseResult(&m2, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if want := time.UnixMilli(1700000000123).UTC(); !m2.Time.Equal(want) {
+		t.Fatalf("ParseResult after ParseResultWithPrecision = %v, want %v (guessed ms)", m2.Time, want)
+	}
+}
+
+// TestParseResultGuessesEpochUnitFromMagnitude confirms ParseResult, run
+// without an explicit precision, decodes a numeric "time" column as
+// whichever unit its magnitude looks like instead of always assuming
+// nanoseconds, so a second, millisecond or microsecond epoch all land
+// somewhere near the same moment instead of three wildly different ones.
+func TestParseResultGuessesEpochUnitFromMagnitude(t *testing.T) {
+	type metric struct {
+		Time  time.Time
+		Value float64
+	}
+
+	want := time.Unix(1700000000, 0).UTC()
+	cases := []struct {
+		name  string
+		epoch interface{}
+	}{
+		{"seconds", float64(1700000000)},
+		{"milliseconds", float64(1700000000 * 1e3)},
+		{"microseconds", float64(1700000000 * 1e6)},
+		// int64, not float64: a nanosecond epoch this large loses
+		// precision as a float64, which a second-granularity Equal
+		// below wouldn't forgive.
+		{"nanoseconds", int64(1700000000 * 1e9)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			row := models.Row{
+				Columns: []string{"time", "value"},
+				Values:  [][]interface{}{{tc.epoch, float64(42)}},
+			}
+			var m metric
+			if err := ParseResult(&m, row); err != nil {
+				t.Fatalf("ParseResult: %v", err)
+			}
+			if !m.Time.Equal(want) {
+				t.Fatalf("Time = %v, want %v", m.Time, want)
+			}
+		})
+	}
+}
+
+// TestParseResultWithLocationConvertsTimeZone confirms
+// ParseResultWithLocation converts a decoded "time" value into the
+// given *time.Location instead of leaving it in whatever Location
+// parsing it happened to produce, and that ParseResult itself is
+// unaffected afterward.
+func TestParseResultWithLocationConvertsTimeZone(t *testing.T) {
+	type metric struct {
+		Time  time.Time
+		Value float64
+	}
+
+	row := models.Row{
+		Columns: []string{"time", "value"},
+		Values:  [][]interface{}{{"2024-01-02T03:04:05Z", float64(42)}},
+	}
+
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+
+	var m metric
+	if err := ParseResultWithLocation(&m, row, loc); err != nil {
+		t.Fatalf("ParseResultWithLocation: %v", err)
+	}
+	want, _ := time.Parse(time.RFC3339, "2024-01-02T03:04:05Z")
+	want = want.In(loc)
+	if !m.Time.Equal(want) || m.Time.Location().String() != loc.String() {
+		t.Fatalf("Time = %v (%v), want %v (%v)", m.Time, m.Time.Location(), want, loc)
+	}
+
+	var m2 metric
+	if err := ParseResult(&m2, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if m2.Time.Location().String() != time.UTC.String() {
+		t.Fatalf("ParseResult after ParseResultWithLocation Location = %v, want UTC", m2.Time.Location())
+	}
+}
+
+// TestParseResultDecodesManyRows confirms ParseResult's cached rowPlan
+// (see decodeplan.go) decodes every row correctly, not just the first
+// one the plan was built from.
+func TestParseResultDecodesManyRows(t *testing.T) {
+	type metric struct {
+		Host  string  `inf:"host,tag"`
+		Value float64 `inf:"value"`
+	}
+
+	const n = 1000
+	vals := make([][]interface{}, n)
+	for i := range vals {
+		vals[i] = []interface{}{float64(i)}
+	}
+	row := models.Row{
+		Tags:    map[string]string{"host": "web1"},
+		Columns: []string{"value"},
+		Values:  vals,
+	}
+
+	var metrics []metric
+	if err := ParseResult(&metrics, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if len(metrics) != n {
+		t.Fatalf("len(metrics) = %d, want %d", len(metrics), n)
+	}
+	for i, m := range metrics {
+		if m.Host != "web1" || m.Value != float64(i) {
+			t.Fatalf("metrics[%d] = %+v, want {Host:web1 Value:%d}", i, m, i)
+		}
+	}
+}
+
+// TestParseResultStrictDecodingUnmatched confirms SetStrictDecoding
+// still reports a column with no matching field and a field with no
+// matching column once the resolution that finds them is cached in a
+// rowPlan.
+func TestParseResultStrictDecodingUnmatched(t *testing.T) {
+	type metric struct {
+		Value float64 `inf:"value"`
+		Extra string  `inf:"extra"`
+	}
+
+	SetStrictDecoding(true)
+	defer SetStrictDecoding(false)
+
+	row := models.Row{
+		Columns: []string{"value", "unexpected"},
+		Values:  [][]interface{}{{float64(1), "x"}},
+	}
+
+	var m metric
+	err := ParseResult(&m, row)
+	sderr, ok := err.(*StrictDecodeError)
+	if !ok {
+		t.Fatalf("ParseResult error = %v (%T), want *StrictDecodeError", err, err)
+	}
+	if len(sderr.UnmatchedColumns) != 1 || sderr.UnmatchedColumns[0] != "unexpected" {
+		t.Fatalf("UnmatchedColumns = %v, want [unexpected]", sderr.UnmatchedColumns)
+	}
+	if len(sderr.UnfilledFields) != 1 || sderr.UnfilledFields[0] != "extra" {
+		t.Fatalf("UnfilledFields = %v, want [extra]", sderr.UnfilledFields)
+	}
+}
+
+// TestParseResultsStrictDecodingUnmatched confirms SetStrictDecoding
+// also surfaces a *StrictDecodeError from ParseResults, not just
+// ParseResult, since both decode rows through the same plan machinery.
+func TestParseResultsStrictDecodingUnmatched(t *testing.T) {
+	type metric struct {
+		Value float64 `inf:"value"`
+	}
+
+	SetStrictDecoding(true)
+	defer SetStrictDecoding(false)
+
+	results := []client.Result{
+		{
+			Series: []models.Row{
+				{
+					Columns: []string{"value", "unexpected"},
+					Values:  [][]interface{}{{float64(1), "x"}},
+				},
+			},
+		},
+	}
+
+	var metrics []metric
+	err := ParseResults(&metrics, results)
+	var sderr *StrictDecodeError
+	if !errors.As(err, &sderr) {
+		t.Fatalf("ParseResults error = %v (%T), want *StrictDecodeError", err, err)
+	}
+	if len(sderr.UnmatchedColumns) != 1 || sderr.UnmatchedColumns[0] != "unexpected" {
+		t.Fatalf("UnmatchedColumns = %v, want [unexpected]", sderr.UnmatchedColumns)
+	}
+}
+
+// TestParseRowsDecodesSeries confirms ParseRows decodes a series into
+// a []T the same way ParseResult(&rows, serie) would, without the
+// caller declaring the slice variable itself.
+func TestParseRowsDecodesSeries(t *testing.T) {
+	type metric struct {
+		Value float64 `inf:"value"`
+	}
+
+	row := models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{float64(1)}, {float64(2)}},
+	}
+
+	rows, err := ParseRows[metric](row)
+	if err != nil {
+		t.Fatalf("ParseRows: %v", err)
+	}
+	want := []metric{{Value: 1}, {Value: 2}}
+	if len(rows) != len(want) || rows[0] != want[0] || rows[1] != want[1] {
+		t.Fatalf("ParseRows = %+v, want %+v", rows, want)
+	}
+}
+
+// TestParseOneDecodesFirstRow confirms ParseOne decodes the first row
+// of the first series with any rows, skipping past an empty series
+// (an InfluxDB result that matched a series but got zero rows out of
+// it, e.g. via GROUP BY) instead of stopping there.
+func TestParseOneDecodesFirstRow(t *testing.T) {
+	type metric struct {
+		Value float64 `inf:"value"`
+	}
+
+	results := []client.Result{
+		{Series: []models.Row{{Columns: []string{"value"}}}},
+		{Series: []models.Row{{
+			Columns: []string{"value"},
+			Values:  [][]interface{}{{float64(3)}, {float64(4)}},
+		}}},
+	}
+
+	got, err := ParseOne[metric](results)
+	if err != nil {
+		t.Fatalf("ParseOne: %v", err)
+	}
+	if got.Value != 3 {
+		t.Fatalf("ParseOne = %+v, want Value=3", got)
+	}
+}
+
+// TestParseOneReturnsErrNoSeriesWhenEmpty confirms ParseOne returns
+// ErrNoSeries, not a zero-value T with a nil error, when every result
+// matched no rows at all.
+func TestParseOneReturnsErrNoSeriesWhenEmpty(t *testing.T) {
+	type metric struct {
+		Value float64 `inf:"value"`
+	}
+
+	_, err := ParseOne[metric](nil)
+	if !errors.Is(err, ErrNoSeries) {
+		t.Fatalf("ParseOne(nil) error = %v, want ErrNoSeries", err)
+	}
+
+	results := []client.Result{{Series: []models.Row{{Columns: []string{"value"}}}}}
+	if _, err := ParseOne[metric](results); !errors.Is(err, ErrNoSeries) {
+		t.Fatalf("ParseOne(empty series) error = %v, want ErrNoSeries", err)
+	}
+}
+
+// TestParseResultLeavesPointerFieldNilOnNullColumn confirms a *T field
+// stays nil for a null column instead of being allocated to point at a
+// zero value indistinguishable from a real zero.
+func TestParseResultLeavesPointerFieldNilOnNullColumn(t *testing.T) {
+	type sample struct {
+		Value *float64 `inf:"value"`
+	}
+
+	row := models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{nil}},
+	}
+	var s sample
+	if err := ParseResult(&s, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if s.Value != nil {
+		t.Fatalf("Value = %v, want nil", s.Value)
+	}
+
+	row = models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{json.Number("1.5")}},
+	}
+	s = sample{}
+	if err := ParseResult(&s, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if s.Value == nil || *s.Value != 1.5 {
+		t.Fatalf("Value = %v, want 1.5", s.Value)
+	}
+}
+
+// TestParseResultDecodesSQLNullTypes confirms sql.NullFloat64,
+// sql.NullInt64 and sql.NullString destinations decode via their own
+// Scan method (sql.Scanner), reporting Valid=false for a null column
+// instead of a zero value indistinguishable from a real one.
+func TestParseResultDecodesSQLNullTypes(t *testing.T) {
+	type sample struct {
+		Value sql.NullFloat64 `inf:"value"`
+		Count sql.NullInt64   `inf:"count"`
+		Name  sql.NullString  `inf:"name"`
+	}
+
+	row := models.Row{
+		Columns: []string{"value", "count", "name"},
+		Values:  [][]interface{}{{json.Number("1.5"), json.Number("7"), "hi"}},
+	}
+	var s sample
+	if err := ParseResult(&s, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if s.Value != (sql.NullFloat64{Float64: 1.5, Valid: true}) {
+		t.Fatalf("Value = %+v", s.Value)
+	}
+	if s.Count != (sql.NullInt64{Int64: 7, Valid: true}) {
+		t.Fatalf("Count = %+v", s.Count)
+	}
+	if s.Name != (sql.NullString{String: "hi", Valid: true}) {
+		t.Fatalf("Name = %+v", s.Name)
+	}
+
+	row = models.Row{
+		Columns: []string{"value", "count", "name"},
+		Values:  [][]interface{}{{nil, nil, nil}},
+	}
+	var nullS sample
+	if err := ParseResult(&nullS, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if nullS.Value.Valid || nullS.Count.Valid || nullS.Name.Valid {
+		t.Fatalf("nullS = %+v, want every field Valid=false", nullS)
+	}
+}
+
+// rowUnmarshalMetric takes over its own decoding instead of going
+// through struct-tag reflection, to confirm ParseResult honors
+// RowUnmarshaler.
+type rowUnmarshalMetric struct {
+	Key   string
+	Value float64
+}
+
+func (m *rowUnmarshalMetric) UnmarshalRow(cols []string, vals []interface{}, tags map[string]string) error {
+	m.Key = tags["host"]
+	for i, col := range cols {
+		if col == "value" {
+			m.Value = parseFloat(vals[i])
+		}
+	}
+	return nil
+}
+
+// TestParseResultHonorsRowUnmarshaler confirms ParseResult defers to a
+// destination's own UnmarshalRow instead of resolving fields by inf
+// tag/plan.
+func TestParseResultHonorsRowUnmarshaler(t *testing.T) {
+	row := models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{float64(42)}},
+		Tags:    map[string]string{"host": "web1"},
+	}
+
+	var m rowUnmarshalMetric
+	if err := ParseResult(&m, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if m.Key != "web1" || m.Value != 42 {
+		t.Fatalf("ParseResult = %+v, want {Key:web1 Value:42}", m)
+	}
+}
+
+// TestParseResultHonorsRowUnmarshalerForSlice confirms a *[]T
+// destination calls UnmarshalRow per row instead of taking the
+// reflect-tag fast path, since a RowUnmarshaler type's decoding is
+// entirely up to itself.
+func TestParseResultHonorsRowUnmarshalerForSlice(t *testing.T) {
+	row := models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{float64(1)}, {float64(2)}},
+		Tags:    map[string]string{"host": "web1"},
+	}
+
+	var metrics []rowUnmarshalMetric
+	if err := ParseResult(&metrics, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	want := []rowUnmarshalMetric{{Key: "web1", Value: 1}, {Key: "web1", Value: 2}}
+	if len(metrics) != len(want) || metrics[0] != want[0] || metrics[1] != want[1] {
+		t.Fatalf("ParseResult = %+v, want %+v", metrics, want)
+	}
+}
+
+// TestParseResultStructSliceWithColumnsFilterUsesFastPath confirms a
+// *[]T decode still takes the plan/rowPlan fast path (resolved once
+// for the whole series, not per row) when a columns filter is passed,
+// and that the filter is honored the same as the per-row slow path:
+// a column named outside columns is ignored.
+func TestParseResultStructSliceWithColumnsFilterUsesFastPath(t *testing.T) {
+	row := models.Row{
+		Columns: []string{"value", "extra"},
+		Values: [][]interface{}{
+			{float64(1), float64(100)},
+			{float64(2), float64(200)},
+		},
+		Tags: map[string]string{"host": "web1"},
+	}
+
+	var metrics []struct {
+		Value float64
+		Extra float64
+	}
+	if err := ParseResult(&metrics, row, "value"); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	want := []struct{ Value, Extra float64 }{{Value: 1}, {Value: 2}}
+	if len(metrics) != len(want) || metrics[0] != want[0] || metrics[1] != want[1] {
+		t.Fatalf("ParseResult = %+v, want %+v", metrics, want)
+	}
+}
+
+// marshalPointMetric takes over its own encoding instead of going
+// through struct-tag reflection, to confirm ToPoint honors
+// PointMarshaler.
+type marshalPointMetric struct {
+	Host  string
+	Value float64
+	fail  bool
+}
+
+func (m marshalPointMetric) MarshalPoint() (string, map[string]string, map[string]interface{}, time.Time, error) {
+	if m.fail {
+		return "", nil, nil, time.Time{}, errors.New("marshalPointMetric: boom")
+	}
+	return "custom_metric", map[string]string{"host": m.Host}, map[string]interface{}{"value": m.Value}, time.Unix(0, 0), nil
+}
+
+// TestToPointHonorsPointMarshaler confirms ToPoint defers to a
+// structure's own MarshalPoint instead of resolving fields by inf
+// tag/plan.
+func TestToPointHonorsPointMarshaler(t *testing.T) {
+	p, err := ToPoint(marshalPointMetric{Host: "web1", Value: 42})
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	if p.Name() != "custom_metric" {
+		t.Fatalf("Name = %q, want %q", p.Name(), "custom_metric")
+	}
+	if got := p.Tags(); got["host"] != "web1" {
+		t.Fatalf("tags = %v, want host=web1", got)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if fields["value"] != float64(42) {
+		t.Fatalf("fields = %v, want value=42", fields)
+	}
+}
+
+// TestToPointPropagatesPointMarshalerError confirms an error from
+// MarshalPoint fails ToPoint closed, instead of falling back to
+// reflection.
+func TestToPointPropagatesPointMarshalerError(t *testing.T) {
+	_, err := ToPoint(marshalPointMetric{fail: true})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("ToPoint error = %v, want it to contain %q", err, "boom")
+	}
+}
+
+// TestToBatchPointsEncodesMixedMeasurements confirms ToBatchPoints
+// converts a slice of differently-typed structs into one BatchPoints
+// for db, one point per element.
+func TestToBatchPointsEncodesMixedMeasurements(t *testing.T) {
+	type cpuStat struct {
+		Host  string  `inf:"host,tag"`
+		Usage float64 `inf:"usage"`
+	}
+	type memStat struct {
+		Host string `inf:"host,tag"`
+		Free int64  `inf:"free"`
+	}
+
+	values := []interface{}{
+		cpuStat{Host: "web1", Usage: 0.5},
+		memStat{Host: "web1", Free: 1024},
+	}
+
+	bp, err := ToBatchPoints("mydb", values)
+	if err != nil {
+		t.Fatalf("ToBatchPoints: %v", err)
+	}
+	if bp.Database() != "mydb" {
+		t.Fatalf("Database = %q, want %q", bp.Database(), "mydb")
+	}
+	points := bp.Points()
+	if len(points) != 2 {
+		t.Fatalf("len(Points()) = %d, want 2", len(points))
+	}
+	if points[0].Name() != "cpu_stat" || points[1].Name() != "mem_stat" {
+		t.Fatalf("Names = %q, %q, want cpu_stat, mem_stat", points[0].Name(), points[1].Name())
+	}
+}
+
+// TestToPointsNamedOverridesMeasurementForEveryElement confirms
+// ToPointsNamed writes every element of the slice to the given
+// measurement instead of each struct's own derived name, the batch
+// counterpart of ToPointNamed.
+func TestToPointsNamedOverridesMeasurementForEveryElement(t *testing.T) {
+	type cpuStat struct {
+		Host  string  `inf:"host,tag"`
+		Usage float64 `inf:"usage"`
+	}
+
+	points, err := ToPointsNamed("cpu_eu", []cpuStat{
+		{Host: "web1", Usage: 0.5},
+		{Host: "web2", Usage: 0.25},
+	})
+	if err != nil {
+		t.Fatalf("ToPointsNamed: %v", err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("len(points) = %d, want 2", len(points))
+	}
+	if points[0].Name() != "cpu_eu" || points[1].Name() != "cpu_eu" {
+		t.Fatalf("Names = %q, %q, want cpu_eu, cpu_eu", points[0].Name(), points[1].Name())
+	}
+}
+
+// TestToLineProtocolEncodesEscapedLine confirms ToLineProtocol produces
+// a correctly escaped line-protocol line straight from a struct, without
+// going through a live Client.
+func TestToLineProtocolEncodesEscapedLine(t *testing.T) {
+	type cpuStat struct {
+		Host  string  `inf:"host,tag"`
+		Usage float64 `inf:"usage"`
+	}
+
+	line, err := ToLineProtocol(cpuStat{Host: "web 1", Usage: 0.5})
+	if err != nil {
+		t.Fatalf("ToLineProtocol: %v", err)
+	}
+	if !strings.HasPrefix(line, `cpu_stat,host=web\ 1 usage=0.5`) {
+		t.Fatalf("line = %q, want an escaped host tag", line)
+	}
+	if !strings.HasSuffix(line, "\n") {
+		t.Fatalf("line = %q, want a trailing newline", line)
+	}
+}
+
+// TestToLineProtocolEncodesOneLinePerSliceElement confirms ToLineProtocol
+// encodes a slice of structs as one line per element, the same way
+// ToPoints/MarshalLineProtocol do.
+func TestToLineProtocolEncodesOneLinePerSliceElement(t *testing.T) {
+	type cpuStat struct {
+		Host  string  `inf:"host,tag"`
+		Usage float64 `inf:"usage"`
+	}
+
+	line, err := ToLineProtocol([]cpuStat{
+		{Host: "web1", Usage: 0.5},
+		{Host: "web2", Usage: 0.25},
+	})
+	if err != nil {
+		t.Fatalf("ToLineProtocol: %v", err)
+	}
+	if got := strings.Count(line, "\n"); got != 2 {
+		t.Fatalf("lines = %d, want 2 in %q", got, line)
+	}
+}
+
+// TestAppendLineProtocolAppendsToExistingBuffer confirms
+// AppendLineProtocol grows dst in place instead of discarding whatever
+// it already held, so a caller can batch several structs into one
+// buffer across repeated calls.
+func TestAppendLineProtocolAppendsToExistingBuffer(t *testing.T) {
+	type cpuStat struct {
+		Host  string  `inf:"host,tag"`
+		Usage float64 `inf:"usage"`
+	}
+
+	dst := []byte("# header\n")
+	dst, err := AppendLineProtocol(dst, cpuStat{Host: "web1", Usage: 0.5})
+	if err != nil {
+		t.Fatalf("AppendLineProtocol: %v", err)
+	}
+	dst, err = AppendLineProtocol(dst, cpuStat{Host: "web2", Usage: 0.25})
+	if err != nil {
+		t.Fatalf("AppendLineProtocol: %v", err)
+	}
+	got := string(dst)
+	if !strings.HasPrefix(got, "# header\n") {
+		t.Fatalf("buffer = %q, want the header preserved", got)
+	}
+	if strings.Count(got, "\n") != 3 {
+		t.Fatalf("buffer = %q, want 3 newlines (header + 2 points)", got)
+	}
+}
+
+// TestPointFromMapBuildsPointFromTagsAndFields confirms PointFromMap
+// builds a point straight from tags/fields maps, for a payload with no
+// struct type to run through ToPoint.
+func TestPointFromMapBuildsPointFromTagsAndFields(t *testing.T) {
+	ts := time.Unix(0, 123)
+	p, err := PointFromMap("dynamic",
+		map[string]string{"host": "web1"},
+		map[string]interface{}{"value": 42.0},
+		ts)
+	if err != nil {
+		t.Fatalf("PointFromMap: %v", err)
+	}
+	if p.Name() != "dynamic" {
+		t.Fatalf("Name = %q, want %q", p.Name(), "dynamic")
+	}
+	if got := p.Tags(); got["host"] != "web1" {
+		t.Fatalf("tags = %v, want host=web1", got)
+	}
+	if !p.Time().Equal(ts) {
+		t.Fatalf("Time = %v, want %v", p.Time(), ts)
+	}
+}
+
+// TestPointFromMapDefaultsZeroTimeToNow confirms PointFromMap falls
+// back to clockNow() the same way ToPoint does, instead of handing
+// client.NewPoint a zero time.
+func TestPointFromMapDefaultsZeroTimeToNow(t *testing.T) {
+	before := time.Now()
+	p, err := PointFromMap("dynamic", nil, map[string]interface{}{"value": 1}, time.Time{})
+	if err != nil {
+		t.Fatalf("PointFromMap: %v", err)
+	}
+	if p.Time().Before(before) {
+		t.Fatalf("Time = %v, want it no earlier than %v", p.Time(), before)
+	}
+}
+
+// TestPointFromMapRejectsNoFields confirms PointFromMap fails closed
+// with ErrNoFields when the fields map is empty, the same way ToPoint
+// does for a struct with no non-tag fields.
+func TestPointFromMapRejectsNoFields(t *testing.T) {
+	_, err := PointFromMap("dynamic", map[string]string{"host": "web1"}, nil, time.Now())
+	var noFields *ErrNoFields
+	if !errors.As(err, &noFields) {
+		t.Fatalf("err = %v, want *ErrNoFields", err)
+	}
+}
+
+// TestPointFromMapKeysSplitsByTagKeyList confirms PointFromMapKeys
+// splits a single map[string]interface{} into tags and fields per
+// tagKeys, converting tag values to strings the way a `,tag` struct
+// field would.
+func TestPointFromMapKeysSplitsByTagKeyList(t *testing.T) {
+	p, err := PointFromMapKeys("dynamic",
+		map[string]interface{}{"host": "web1", "region": 1, "value": 42.0},
+		[]string{"host", "region"},
+		time.Unix(0, 1))
+	if err != nil {
+		t.Fatalf("PointFromMapKeys: %v", err)
+	}
+	if got := p.Tags(); got["host"] != "web1" || got["region"] != "1" {
+		t.Fatalf("tags = %v, want host=web1 region=1", got)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if fields["value"] != float64(42) {
+		t.Fatalf("fields = %v, want value=42", fields)
+	}
+	if _, ok := fields["host"]; ok {
+		t.Fatalf("fields = %v, want no host field", fields)
+	}
+}
+
+// TestToPointMergesMapTagsField confirms a map[string]string field
+// tagged `,tags` is merged into the point's tags at encode time, for
+// variable per-request labels that don't have their own struct field.
+func TestToPointMergesMapTagsField(t *testing.T) {
+	type event struct {
+		Name  string            `inf:"name,tag"`
+		Extra map[string]string `inf:",tags"`
+		Value float64           `inf:"value"`
+	}
+
+	p, err := ToPoint(event{
+		Name:  "login",
+		Extra: map[string]string{"region": "us-east", "tier": "gold"},
+		Value: 1,
+	})
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	got := p.Tags()
+	if got["name"] != "login" || got["region"] != "us-east" || got["tier"] != "gold" {
+		t.Fatalf("tags = %v, want name=login region=us-east tier=gold", got)
+	}
+}
+
+// TestToPointMergesMapFieldsField confirms a map[string]interface{}
+// field tagged `,fields` is merged into the point's fields at encode
+// time, the field-side counterpart of a `,tags` map field.
+func TestToPointMergesMapFieldsField(t *testing.T) {
+	type event struct {
+		Name  string                 `inf:"name,tag"`
+		Extra map[string]interface{} `inf:",fields"`
+	}
+
+	p, err := ToPoint(event{
+		Name:  "login",
+		Extra: map[string]interface{}{"latency_ms": 42.0, "retries": int64(2)},
+	})
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if fields["latency_ms"] != float64(42) || fields["retries"] != int64(2) {
+		t.Fatalf("fields = %v, want latency_ms=42 retries=2", fields)
+	}
+}
+
+// TestToPointMapFieldsFieldHonorsFieldOptions confirms a Only/Omit
+// FieldOption filters individual keys out of a `,fields` map field the
+// same way it filters an ordinary struct field, since plugin-style
+// metrics still need to be able to drop one dynamic key at encode time.
+func TestToPointMapFieldsFieldHonorsFieldOptions(t *testing.T) {
+	type event struct {
+		Name  string                 `inf:"name,tag"`
+		Extra map[string]interface{} `inf:",fields"`
+	}
+
+	p, err := ToPoint(event{
+		Name:  "login",
+		Extra: map[string]interface{}{"latency_ms": 42.0, "retries": int64(2)},
+	}, Omit("retries"))
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if fields["latency_ms"] != float64(42) {
+		t.Fatalf("fields = %v, want latency_ms=42", fields)
+	}
+	if _, ok := fields["retries"]; ok {
+		t.Fatalf("fields = %v, want retries omitted", fields)
+	}
+}
+
+// TestToPointMapFieldsFieldTreatsNilMapAsEmpty confirms a nil
+// `,fields` map contributes nothing instead of panicking, so a
+// plugin-style metric with no dynamic fields that run still encodes
+// fine as long as it has another field.
+func TestToPointMapFieldsFieldTreatsNilMapAsEmpty(t *testing.T) {
+	type event struct {
+		Name  string                 `inf:"name,tag"`
+		Value float64                `inf:"value"`
+		Extra map[string]interface{} `inf:",fields"`
+	}
+
+	p, err := ToPoint(event{Name: "login", Value: 1})
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if len(fields) != 1 || fields["value"] != float64(1) {
+		t.Fatalf("fields = %v, want only value=1", fields)
+	}
+}
+
+// TestParseResultsFillsGroupTags confirms flattening several GROUP BY
+// series into one []T via ParseResults doesn't lose the grouping
+// dimension: each series' Tags fill any struct field tagged to match,
+// the same way a single series' Tags already do in ParseResult.
+func TestParseResultsFillsGroupTags(t *testing.T) {
+	type hostMetric struct {
+		Host  string  `inf:"host,tag"`
+		Value float64 `inf:"value"`
+	}
+
+	results := []client.Result{
+		{
+			Series: []models.Row{
+				{
+					Tags:    map[string]string{"host": "a"},
+					Columns: []string{"value"},
+					Values:  [][]interface{}{{float64(1)}},
+				},
+				{
+					Tags:    map[string]string{"host": "b"},
+					Columns: []string{"value"},
+					Values:  [][]interface{}{{float64(2)}},
+				},
+			},
+		},
+	}
+
+	var metrics []hostMetric
+	if err := ParseResults(&metrics, results); err != nil {
+		t.Fatalf("ParseResults: %v", err)
+	}
+	want := []hostMetric{{Host: "a", Value: 1}, {Host: "b", Value: 2}}
+	if len(metrics) != len(want) || metrics[0] != want[0] || metrics[1] != want[1] {
+		t.Fatalf("ParseResults = %+v, want %+v", metrics, want)
+	}
+}
+
+// TestParseGroupedSplitsSeriesAndFillsGroupTags confirms ParseGrouped
+// keys its map by each series' Tags (via groupKey) instead of
+// concatenating every series into one slice like ParseResults does,
+// while still filling any struct field tagged to match those same
+// Tags on every decoded row, the same as ParseResult/ParseResults
+// already do.
+func TestParseGroupedSplitsSeriesAndFillsGroupTags(t *testing.T) {
+	type hostMetric struct {
+		Host  string  `inf:"host,tag"`
+		Value float64 `inf:"value"`
+	}
+
+	results := []client.Result{
+		{
+			Series: []models.Row{
+				{
+					Tags:    map[string]string{"host": "a"},
+					Columns: []string{"value"},
+					Values:  [][]interface{}{{float64(1)}, {float64(2)}},
+				},
+				{
+					Tags:    map[string]string{"host": "b"},
+					Columns: []string{"value"},
+					Values:  [][]interface{}{{float64(3)}},
+				},
+			},
+		},
+	}
+
+	var grouped map[string][]hostMetric
+	if err := ParseGrouped(&grouped, results); err != nil {
+		t.Fatalf("ParseGrouped: %v", err)
+	}
+	want := map[string][]hostMetric{
+		"a": {{Host: "a", Value: 1}, {Host: "a", Value: 2}},
+		"b": {{Host: "b", Value: 3}},
+	}
+	if len(grouped) != len(want) {
+		t.Fatalf("ParseGrouped = %+v, want %+v", grouped, want)
+	}
+	for key, metrics := range want {
+		got, ok := grouped[key]
+		if !ok || len(got) != len(metrics) {
+			t.Fatalf("ParseGrouped[%q] = %+v, want %+v", key, grouped[key], metrics)
+		}
+		for i := range metrics {
+			if got[i] != metrics[i] {
+				t.Fatalf("ParseGrouped[%q][%d] = %+v, want %+v", key, i, got[i], metrics[i])
+			}
+		}
+	}
+}
+
+// TestParseGroupedByTagKeysBySingleTagValue confirms ParseGroupedByTag
+// groups rows by just byTag's value, ignoring any other tags the same
+// series carries, unlike ParseGrouped's composite groupKey.
+func TestParseGroupedByTagKeysBySingleTagValue(t *testing.T) {
+	type hostMetric struct {
+		Host   string  `inf:"host,tag"`
+		Region string  `inf:"region,tag"`
+		Value  float64 `inf:"value"`
+	}
+
+	rows := []models.Row{
+		{
+			Tags:    map[string]string{"host": "a", "region": "us"},
+			Columns: []string{"value"},
+			Values:  [][]interface{}{{float64(1)}, {float64(2)}},
+		},
+		{
+			Tags:    map[string]string{"host": "b", "region": "eu"},
+			Columns: []string{"value"},
+			Values:  [][]interface{}{{float64(3)}},
+		},
+	}
+
+	var grouped map[string][]hostMetric
+	if err := ParseGroupedByTag(&grouped, rows, "host"); err != nil {
+		t.Fatalf("ParseGroupedByTag: %v", err)
+	}
+	want := map[string][]hostMetric{
+		"a": {{Host: "a", Region: "us", Value: 1}, {Host: "a", Region: "us", Value: 2}},
+		"b": {{Host: "b", Region: "eu", Value: 3}},
+	}
+	if len(grouped) != len(want) {
+		t.Fatalf("ParseGroupedByTag = %+v, want %+v", grouped, want)
+	}
+	for key, metrics := range want {
+		got, ok := grouped[key]
+		if !ok || len(got) != len(metrics) {
+			t.Fatalf("ParseGroupedByTag[%q] = %+v, want %+v", key, grouped[key], metrics)
+		}
+		for i := range metrics {
+			if got[i] != metrics[i] {
+				t.Fatalf("ParseGroupedByTag[%q][%d] = %+v, want %+v", key, i, got[i], metrics[i])
+			}
+		}
+	}
+}
+
+// TestParseResultsCountReturnsDecodedRowCount confirms ParseResultsCount
+// reports the total number of rows decoded across every series of every
+// result, the same parity ParseResultCount already gives a single
+// series.
+func TestParseResultsCountReturnsDecodedRowCount(t *testing.T) {
+	type hostMetric struct {
+		Host  string  `inf:"host,tag"`
+		Value float64 `inf:"value"`
+	}
+
+	results := []client.Result{
+		{
+			Series: []models.Row{
+				{
+					Tags:    map[string]string{"host": "a"},
+					Columns: []string{"value"},
+					Values:  [][]interface{}{{float64(1)}},
+				},
+				{
+					Tags:    map[string]string{"host": "b"},
+					Columns: []string{"value"},
+					Values:  [][]interface{}{{float64(2)}, {float64(3)}},
+				},
+			},
+		},
+	}
+
+	var metrics []hostMetric
+	n, err := ParseResultsCount(&metrics, results)
+	if err != nil {
+		t.Fatalf("ParseResultsCount: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("n = %d, want 3", n)
+	}
+	if len(metrics) != 3 {
+		t.Fatalf("len(metrics) = %d, want 3", len(metrics))
+	}
+}
+
+// TestParseResponseDecodesResultsAndReportsCount confirms
+// ParseResponse/ParseResponseCount decode a *client.Response's
+// Results the same way ParseResults does over an already-unwrapped
+// []client.Result, and that a top-level response error short-circuits
+// before ParseResults ever runs.
+func TestParseResponseDecodesResultsAndReportsCount(t *testing.T) {
+	type metric struct {
+		Value float64 `inf:"value"`
+	}
+
+	resp := &client.Response{
+		Results: []client.Result{{
+			Series: []models.Row{{
+				Columns: []string{"value"},
+				Values:  [][]interface{}{{float64(1)}, {float64(2)}},
+			}},
+		}},
+	}
+
+	var metrics []metric
+	n, err := ParseResponseCount(&metrics, resp)
+	if err != nil {
+		t.Fatalf("ParseResponseCount: %v", err)
+	}
+	if n != 2 || len(metrics) != 2 {
+		t.Fatalf("ParseResponseCount = %d, metrics = %+v, want 2 rows", n, metrics)
+	}
+
+	bad := &client.Response{Err: "engine: boom"}
+	var failed []metric
+	if err := ParseResponse(&failed, bad); err == nil || err.Error() != "engine: boom" {
+		t.Fatalf("ParseResponse error = %v, want \"engine: boom\"", err)
+	}
+}
+
+// TestParseResultFillsEmbeddedFields confirms ParseResult promotes an
+// embedded struct's fields during column matching, the same way
+// encoding/json does, so a shared row header (Time, Host) declared
+// once on a base struct still gets filled when embedded.
+func TestParseResultFillsEmbeddedFields(t *testing.T) {
+	type header struct {
+		Time time.Time
+		Host string `inf:"host,tag"`
+	}
+	type metric struct {
+		header
+		Value float64 `inf:"value"`
+	}
+
+	row := models.Row{
+		Tags:    map[string]string{"host": "web1"},
+		Columns: []string{"time", "value"},
+		Values:  [][]interface{}{{"2023-01-02T03:04:05Z", float64(42)}},
+	}
+
+	var m metric
+	if err := ParseResult(&m, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	wantTime, _ := time.Parse(time.RFC3339, "2023-01-02T03:04:05Z")
+	if m.Host != "web1" || m.Value != 42 || !m.Time.Equal(wantTime) {
+		t.Fatalf("ParseResult = %+v, want Host=web1 Value=42 Time=%v", m, wantTime)
+	}
+}
+
+// TestParseResultOwnFieldWinsOverEmbedded confirms a field declared
+// directly on the decoded struct takes precedence over a
+// same-named field promoted from an embedded struct, the same
+// shallower-wins rule collectFields documents for encoding.
+func TestParseResultOwnFieldWinsOverEmbedded(t *testing.T) {
+	type header struct {
+		Value float64 `inf:"value"`
+	}
+	type metric struct {
+		header
+		Value string `inf:"value"`
+	}
+
+	row := models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{"override"}},
+	}
+
+	var m metric
+	if err := ParseResult(&m, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if m.Value != "override" {
+		t.Fatalf("m.Value = %q, want %q", m.Value, "override")
+	}
+	if m.header.Value != 0 {
+		t.Fatalf("m.header.Value = %v, want 0 (never filled)", m.header.Value)
+	}
+}
+
+// TestToPointFlattensEmbeddedStruct confirms ToPoint promotes an
+// anonymous embedded struct's tagged fields into the parent's own
+// tags/fields, so a shared base struct (e.g. common tags) can be
+// embedded in several measurement structs instead of repeating its
+// fields on each one.
+func TestToPointFlattensEmbeddedStruct(t *testing.T) {
+	type header struct {
+		Env string `inf:"env,tag"`
+	}
+	type cpuStat struct {
+		header
+		Host  string  `inf:"host,tag"`
+		Usage float64 `inf:"usage"`
+	}
+	type memStat struct {
+		header
+		Host string `inf:"host,tag"`
+		Used int64  `inf:"used"`
+	}
+
+	cpu, err := ToPoint(cpuStat{header: header{Env: "prod"}, Host: "web1", Usage: 0.5})
+	if err != nil {
+		t.Fatalf("ToPoint(cpuStat): %v", err)
+	}
+	if got := cpu.Tags(); got["env"] != "prod" || got["host"] != "web1" {
+		t.Fatalf("cpuStat tags = %v, want env=prod host=web1", got)
+	}
+
+	mem, err := ToPoint(memStat{header: header{Env: "prod"}, Host: "web1", Used: 1024})
+	if err != nil {
+		t.Fatalf("ToPoint(memStat): %v", err)
+	}
+	if got := mem.Tags(); got["env"] != "prod" || got["host"] != "web1" {
+		t.Fatalf("memStat tags = %v, want env=prod host=web1", got)
+	}
+	fields, err := mem.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if fields["used"] != int64(1024) {
+		t.Fatalf("fields[%q] = %v, want 1024", "used", fields["used"])
+	}
+}
+
+// TestParseResultFillsFlattenedFields confirms ParseResult decodes a
+// prefixed, flattened column name (e.g. "cpu_usage_user", one level per
+// `inf:"...,flatten"` struct) into its nested struct fields, the read
+// side of the same prefixing ToPoint already applies when encoding one.
+func TestParseResultFillsFlattenedFields(t *testing.T) {
+	type usage struct {
+		User float64 `inf:"user"`
+	}
+	type usages struct {
+		Usage usage `inf:"usage,flatten"`
+	}
+	type cpu struct {
+		Usages usages `inf:"cpu,flatten"`
+	}
+
+	row := models.Row{
+		Columns: []string{"cpu_usage_user"},
+		Values:  [][]interface{}{{float64(10)}},
+	}
+
+	var c cpu
+	if err := ParseResult(&c, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if c.Usages.Usage.User != 10 {
+		t.Fatalf("ParseResult = %+v, want Usages.Usage.User=10", c)
+	}
+}
+
+// TestToPointFlattenUsesCustomSeparator confirms `inf:"name,flatten,sep=."`
+// joins the parent and nested field names with "." instead of the
+// default "_".
+func TestToPointFlattenUsesCustomSeparator(t *testing.T) {
+	type usage struct {
+		User float64 `inf:"user"`
+	}
+	type cpu struct {
+		Usage usage `inf:"cpu,flatten,sep=."`
+	}
+
+	p, err := ToPoint(cpu{Usage: usage{User: 1.5}})
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if fields["cpu.user"] != 1.5 {
+		t.Fatalf("fields = %v, want cpu.user=1.5", fields)
+	}
+}
+
+// TestToPointInlineOmitsAnyPrefix confirms a named struct field tagged
+// `inf:",inline"` promotes its fields without adding any prefix, the
+// same as an anonymous embedded field would.
+func TestToPointInlineOmitsAnyPrefix(t *testing.T) {
+	type header struct {
+		Env string `inf:"env,tag"`
+	}
+	type metric struct {
+		Header header  `inf:",inline"`
+		Value  float64 `inf:"value"`
+	}
+
+	p, err := ToPoint(metric{Header: header{Env: "prod"}, Value: 2})
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	if got := p.Tags(); got["env"] != "prod" {
+		t.Fatalf("tags = %v, want env=prod", got)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if fields["value"] != float64(2) {
+		t.Fatalf("fields = %v, want value=2", fields)
+	}
+}
+
+// TestToPointOmitemptySkipsZeroValues confirms `inf:"name,omitempty"`
+// drops a zero-valued tag or field from the point entirely instead of
+// writing an empty-string tag (which otherwise pollutes cardinality
+// with an indexed "") or a spurious zero field, while a non-zero value
+// on the same field is still written normally.
+func TestToPointOmitemptySkipsZeroValues(t *testing.T) {
+	type sample struct {
+		Host   string  `inf:"host,tag,omitempty"`
+		Region string  `inf:"region,tag,omitempty"`
+		Extra  float64 `inf:"extra,omitempty"`
+		Value  float64 `inf:"value"`
+	}
+
+	p, err := ToPoint(sample{Region: "us-east", Value: 1})
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	tags := p.Tags()
+	if _, ok := tags["host"]; ok {
+		t.Fatalf("tags = %v, want no %q tag", tags, "host")
+	}
+	if tags["region"] != "us-east" {
+		t.Fatalf("tags = %v, want region=us-east", tags)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if _, ok := fields["extra"]; ok {
+		t.Fatalf("fields = %v, want no %q field", fields, "extra")
+	}
+	if fields["value"] != float64(1) {
+		t.Fatalf("fields = %v, want value=1", fields)
+	}
+}
+
+// TestToPointDereferencesNonNilPointerFields confirms a pointer-typed
+// tag or field (*string, *int, *time.Time) is dereferenced into its
+// underlying value when non-nil, instead of being stored as a raw
+// pointer client.NewPoint can't encode.
+func TestToPointDereferencesNonNilPointerFields(t *testing.T) {
+	host := "web1"
+	count := 7
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	type sample struct {
+		Host  *string    `inf:"host,tag"`
+		Count *int       `inf:"count"`
+		Time  *time.Time `inf:"time"`
+	}
+
+	p, err := ToPoint(sample{Host: &host, Count: &count, Time: &ts})
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	if got := p.Tags(); got["host"] != "web1" {
+		t.Fatalf("tags = %v, want host=web1", got)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if fields["count"] != int64(7) {
+		t.Fatalf("fields = %v, want count=7", fields)
+	}
+	if !p.Time().Equal(ts) {
+		t.Fatalf("Time = %v, want %v", p.Time(), ts)
+	}
+}
+
+// TestToPointSkipsNilPointerFields confirms a nil pointer-typed tag or
+// field is left out of the point entirely, rather than panicking or
+// being stored as a typed nil.
+func TestToPointSkipsNilPointerFields(t *testing.T) {
+	type sample struct {
+		Host  *string `inf:"host,tag"`
+		Count *int    `inf:"count"`
+		Value float64 `inf:"value"`
+	}
+
+	p, err := ToPoint(sample{Value: 1})
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	if tags := p.Tags(); len(tags) != 0 {
+		t.Fatalf("tags = %v, want none", tags)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if _, ok := fields["count"]; ok {
+		t.Fatalf("fields = %v, want no %q field", fields, "count")
+	}
+	if fields["value"] != float64(1) {
+		t.Fatalf("fields = %v, want value=1", fields)
+	}
+}
+
+// TestToPointDefaultsZeroTimeFieldToNow confirms ToPoint's default
+// behavior for a zero-valued time.Time field is still falling back to
+// clockNow() rather than writing the zero time or omitting it, unless
+// the caller opts into StrictTime or ServerTime.
+func TestToPointDefaultsZeroTimeFieldToNow(t *testing.T) {
+	type event struct {
+		Time  time.Time
+		Value float64 `inf:"value"`
+	}
+
+	before := clockNow()
+	p, err := ToPoint(event{Value: 1})
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	if p.Time().Before(before) {
+		t.Fatalf("Time = %v, want it no earlier than %v", p.Time(), before)
+	}
+}
+
+// TestToPointServerTimeOmitsZeroTimeField confirms ServerTime leaves a
+// zero-valued time.Time field's timestamp unset instead of falling back
+// to clockNow(), so client.NewPoint drops it from the line protocol and
+// InfluxDB assigns its own receipt time.
+func TestToPointServerTimeOmitsZeroTimeField(t *testing.T) {
+	type event struct {
+		Time  time.Time
+		Value float64 `inf:"value"`
+	}
+
+	p, err := ToPoint(event{Value: 1}, ServerTime())
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	if !p.Time().IsZero() {
+		t.Fatalf("Time = %v, want zero", p.Time())
+	}
+	if strings.Contains(p.String(), " 0\n") || strings.HasSuffix(p.String(), " 0") {
+		t.Fatalf("String = %q, want no trailing timestamp", p.String())
+	}
+}
+
+// TestToPointServerTimeStillHonorsExplicitTime confirms ServerTime only
+// affects a zero time field: an explicitly set (even epoch-zero) time
+// still drives the point's timestamp.
+func TestToPointServerTimeStillHonorsExplicitTime(t *testing.T) {
+	type event struct {
+		Time  time.Time
+		Value float64 `inf:"value"`
+	}
+
+	epoch := time.Unix(0, 0)
+	p, err := ToPoint(event{Time: epoch, Value: 1}, ServerTime())
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	if !p.Time().Equal(epoch) {
+		t.Fatalf("Time = %v, want %v", p.Time(), epoch)
+	}
+}
+
+// TestToPointStrictTimeTakesPrecedenceOverServerTime confirms combining
+// StrictTime and ServerTime still fails closed on a zero time field
+// instead of silently omitting the timestamp, since a caller opting into
+// StrictTime wants to be told about a forgotten Time field.
+func TestToPointStrictTimeTakesPrecedenceOverServerTime(t *testing.T) {
+	type event struct {
+		Time  time.Time
+		Value float64 `inf:"value"`
+	}
+
+	_, err := ToPoint(event{Value: 1}, StrictTime(), ServerTime())
+	if !errors.Is(err, ErrZeroTime) {
+		t.Fatalf("err = %v, want ErrZeroTime", err)
+	}
+}
+
+// TestToPointEncodesUnixMsTimeField confirms the "unix_ms" inf tag
+// option is an alias for "ms" on an integer time field, for a struct
+// whose tag reads more like the epoch unit it actually carries.
+func TestToPointEncodesUnixMsTimeField(t *testing.T) {
+	type event struct {
+		When  int64   `inf:"time,unix_ms"`
+		Value float64 `inf:"value"`
+	}
+
+	p, err := ToPoint(event{When: 1000, Value: 1})
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	if want := time.Unix(0, 1000*int64(time.Millisecond)); !p.Time().Equal(want) {
+		t.Fatalf("Time = %v, want %v", p.Time(), want)
+	}
+}
+
+// TestToPointEncodesStringTimeFieldWithLayout confirms a string-typed
+// time field tagged `inf:"ts,layout=..."` drives the point's timestamp
+// by parsing the string with that layout, for an upstream event whose
+// time column is already formatted text instead of a time.Time.
+func TestToPointEncodesStringTimeFieldWithLayout(t *testing.T) {
+	type event struct {
+		TS    string  `inf:"ts,layout=2006-01-02 15:04:05"`
+		Value float64 `inf:"value"`
+	}
+
+	p, err := ToPoint(event{TS: "2021-06-01 12:00:00", Value: 1})
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	want, _ := time.Parse("2006-01-02 15:04:05", "2021-06-01 12:00:00")
+	if !p.Time().Equal(want) {
+		t.Fatalf("Time = %v, want %v", p.Time(), want)
+	}
+}
+
+// TestToPointRejectsUnparsableStringTimeField confirms ToPoint fails
+// instead of silently stamping a zero time when a string time field
+// doesn't match its declared layout.
+func TestToPointRejectsUnparsableStringTimeField(t *testing.T) {
+	type event struct {
+		TS    string  `inf:"ts,layout=2006-01-02 15:04:05"`
+		Value float64 `inf:"value"`
+	}
+
+	_, err := ToPoint(event{TS: "not-a-time", Value: 1})
+	if err == nil {
+		t.Fatal("ToPoint succeeded, want an error")
+	}
+}
+
+// TestParseResultFillsStringTimeFieldWithLayout confirms ParseResult
+// decodes the "time" column into a string time field formatted with its
+// declared layout, the read side of
+// TestToPointEncodesStringTimeFieldWithLayout's encoding.
+func TestParseResultFillsStringTimeFieldWithLayout(t *testing.T) {
+	type event struct {
+		TS    string  `inf:"ts,layout=2006-01-02 15:04:05"`
+		Value float64 `inf:"value"`
+	}
+
+	when, _ := time.Parse(time.RFC3339, "2021-06-01T12:00:00Z")
+	row := models.Row{
+		Columns: []string{"time", "value"},
+		Values:  [][]interface{}{{when, float64(1)}},
+	}
+
+	var m event
+	if err := ParseResult(&m, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if m.TS != "2021-06-01 12:00:00" {
+		t.Fatalf("TS = %q, want %q", m.TS, "2021-06-01 12:00:00")
+	}
+}
+
+// TestParseResultFillsTimeField confirms a time.Time struct field
+// decodes from its column (named "time" by default, or any column named
+// via its inf tag), accepting both an RFC3339 string and an epoch
+// integer the way the top-level time.Time destination already does.
+func TestParseResultFillsTimeField(t *testing.T) {
+	type event struct {
+		Time  time.Time `inf:"time"`
+		Value float64   `inf:"value"`
+	}
+
+	want := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	row := models.Row{
+		Columns: []string{"time", "value"},
+		Values:  [][]interface{}{{want.Format(time.RFC3339), float64(1)}},
+	}
+	var m event
+	if err := ParseResult(&m, row); err != nil {
+		t.Fatalf("ParseResult (RFC3339 string): %v", err)
+	}
+	if !m.Time.Equal(want) {
+		t.Fatalf("Time = %v, want %v", m.Time, want)
+	}
+
+	row = models.Row{
+		Columns: []string{"time", "value"},
+		Values:  [][]interface{}{{want.UnixNano(), float64(1)}},
+	}
+	m = event{}
+	if err := ParseResult(&m, row); err != nil {
+		t.Fatalf("ParseResult (epoch int): %v", err)
+	}
+	if !m.Time.Equal(want) {
+		t.Fatalf("Time = %v, want %v", m.Time, want)
+	}
+}
+
+// TestParseResultFillsTimeFieldUsingRegisteredLayout confirms a
+// time.Time struct field, not just the bare top-level time.Time
+// destination TestParseResultUsesRegisteredTimeLayout covers, also
+// falls back to a layout added by RegisterTimeLayout when its column's
+// string value isn't RFC3339.
+func TestParseResultFillsTimeFieldUsingRegisteredLayout(t *testing.T) {
+	const layout = "01/02/2006 15:04:05"
+	RegisterTimeLayout(layout)
+
+	type event struct {
+		Time  time.Time `inf:"time"`
+		Value float64   `inf:"value"`
+	}
+
+	want, _ := time.Parse(layout, "06/01/2021 12:00:00")
+	row := models.Row{
+		Columns: []string{"time", "value"},
+		Values:  [][]interface{}{{"06/01/2021 12:00:00", float64(1)}},
+	}
+	var m event
+	if err := ParseResult(&m, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if !m.Time.Equal(want) {
+		t.Fatalf("Time = %v, want %v", m.Time, want)
+	}
+}
+
+// TestParseResultsFillsMeasurementField confirms a field tagged
+// inf:",measurement" is filled with each row's series name, so a
+// heterogeneous result decoded into one slice still tells its rows
+// apart by measurement.
+func TestParseResultsFillsMeasurementField(t *testing.T) {
+	type reading struct {
+		Measurement string  `inf:",measurement"`
+		Value       float64 `inf:"value"`
+	}
+
+	results := []client.Result{{
+		Series: []models.Row{
+			{
+				Name:    "cpu",
+				Columns: []string{"value"},
+				Values:  [][]interface{}{{float64(1)}},
+			},
+			{
+				Name:    "mem",
+				Columns: []string{"value"},
+				Values:  [][]interface{}{{float64(2)}},
+			},
+		},
+	}}
+
+	var rows []reading
+	if err := ParseResults(&rows, results); err != nil {
+		t.Fatalf("ParseResults: %v", err)
+	}
+	want := []reading{{Measurement: "cpu", Value: 1}, {Measurement: "mem", Value: 2}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("rows = %+v, want %+v", rows, want)
+	}
+}
+
+// TestParseResultFillsTimePointerFieldByCustomColumn confirms a
+// *time.Time field named via its inf tag (not the default "time")
+// decodes the same way, allocating the pointer as needed.
+func TestParseResultFillsTimePointerFieldByCustomColumn(t *testing.T) {
+	type event struct {
+		Seen  *time.Time `inf:"seen"`
+		Value float64    `inf:"value"`
+	}
+
+	want := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	row := models.Row{
+		Columns: []string{"seen", "value"},
+		Values:  [][]interface{}{{want.Format(time.RFC3339), float64(1)}},
+	}
+
+	var m event
+	if err := ParseResult(&m, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if m.Seen == nil || !m.Seen.Equal(want) {
+		t.Fatalf("Seen = %v, want %v", m.Seen, want)
+	}
+}
+
+// TestParseResultCaseInsensitiveColumns confirms a column whose case
+// doesn't agree with its field's inf tag or name — as from a SELECT
+// expression InfluxDB has re-cased, or a differently-cased alias —
+// only matches once SetCaseInsensitiveColumns(true) is on, and that an
+// exact-case column still matches regardless.
+func TestParseResultCaseInsensitiveColumns(t *testing.T) {
+	type metric struct {
+		Value float64 `inf:"value"`
+	}
+
+	row := models.Row{
+		Columns: []string{"VALUE"},
+		Values:  [][]interface{}{{float64(7)}},
+	}
+
+	var before metric
+	if err := ParseResult(&before, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if before.Value != 0 {
+		t.Fatalf("ParseResult = %+v, want Value=0 before SetCaseInsensitiveColumns", before)
+	}
+
+	SetCaseInsensitiveColumns(true)
+	defer SetCaseInsensitiveColumns(false)
+
+	var after metric
+	if err := ParseResult(&after, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if after.Value != 7 {
+		t.Fatalf("ParseResult = %+v, want Value=7 after SetCaseInsensitiveColumns", after)
+	}
+}
+
+// TestParseResultCaseInsensitiveColumnsIgnoresUnderscores confirms
+// SetCaseInsensitiveColumns(true) also folds away "_" differences, for
+// a column whose underscores don't line up with its field's the same
+// way its case might not.
+func TestParseResultCaseInsensitiveColumnsIgnoresUnderscores(t *testing.T) {
+	type metric struct {
+		AvgUsage float64 `inf:"avg_usage"`
+	}
+
+	row := models.Row{
+		Columns: []string{"AVGUsage"},
+		Values:  [][]interface{}{{float64(9)}},
+	}
+
+	var before metric
+	if err := ParseResult(&before, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if before.AvgUsage != 0 {
+		t.Fatalf("ParseResult = %+v, want AvgUsage=0 before SetCaseInsensitiveColumns", before)
+	}
+
+	SetCaseInsensitiveColumns(true)
+	defer SetCaseInsensitiveColumns(false)
+
+	var after metric
+	if err := ParseResult(&after, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if after.AvgUsage != 9 {
+		t.Fatalf("ParseResult = %+v, want AvgUsage=9 after SetCaseInsensitiveColumns", after)
+	}
+}
+
+// TestParseResultCountReportsRowsWritten confirms ParseResultCount
+// returns how many rows it actually wrote into dst — letting a caller
+// tell "no data" apart from "data decoded" for a single-value
+// destination, where ParseResult's error alone can't distinguish the
+// two — and that it reports the truncated count for a [N]T array
+// destination narrower than serie's rows, not len(serie.Values).
+func TestParseResultCountReportsRowsWritten(t *testing.T) {
+	empty := models.Row{Columns: []string{"value"}}
+	var empties []float64
+	if n, err := ParseResultCount(&empties, empty); err != nil || n != 0 {
+		t.Fatalf("ParseResultCount(empty) = %d, %v, want 0, nil", n, err)
+	}
+
+	one := models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{float64(42)}},
+	}
+	var v float64
+	if n, err := ParseResultCount(&v, one); err != nil || n != 1 {
+		t.Fatalf("ParseResultCount(one) = %d, %v, want 1, nil", n, err)
+	}
+	if v != 42 {
+		t.Fatalf("v = %v, want 42", v)
+	}
+
+	many := models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{float64(1)}, {float64(2)}, {float64(3)}},
+	}
+	var tight [2]float64
+	if n, err := ParseResultCount(&tight, many); err != nil || n != 2 {
+		t.Fatalf("ParseResultCount(many into [2]) = %d, %v, want 2, nil", n, err)
+	}
+}
+
+// TestParseResultMapInterfacePreservesNativeTypes confirms a
+// map[string]interface{} destination holds each column's own native
+// Go type instead of funneling everything through string/float
+// conversion: a json.Number column (as client/v2's UseNumber decoding
+// actually produces) becomes an int64 or float64 depending on whether
+// it has a fractional part, the "time" column becomes a time.Time,
+// and bool/string pass through unchanged.
+func TestParseResultMapInterfacePreservesNativeTypes(t *testing.T) {
+	row := models.Row{
+		Columns: []string{"time", "count", "ratio", "host", "ok"},
+		Values: [][]interface{}{
+			{"2023-01-02T03:04:05Z", json.Number("42"), json.Number("0.5"), "web1", true},
+		},
+	}
+
+	var m map[string]interface{}
+	if err := ParseResult(&m, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+
+	wantTime, _ := time.Parse(time.RFC3339, "2023-01-02T03:04:05Z")
+	if tm, ok := m["time"].(time.Time); !ok || !tm.Equal(wantTime) {
+		t.Fatalf(`m["time"] = %v (%T), want %v (time.Time)`, m["time"], m["time"], wantTime)
+	}
+	if v, ok := m["count"].(int64); !ok || v != 42 {
+		t.Fatalf(`m["count"] = %v (%T), want int64(42)`, m["count"], m["count"])
+	}
+	if v, ok := m["ratio"].(float64); !ok || v != 0.5 {
+		t.Fatalf(`m["ratio"] = %v (%T), want float64(0.5)`, m["ratio"], m["ratio"])
+	}
+	if v, ok := m["host"].(string); !ok || v != "web1" {
+		t.Fatalf(`m["host"] = %v (%T), want string("web1")`, m["host"], m["host"])
+	}
+	if v, ok := m["ok"].(bool); !ok || !v {
+		t.Fatalf(`m["ok"] = %v (%T), want bool(true)`, m["ok"], m["ok"])
+	}
+}
+
+// TestParseResultStrictIntDecoding confirms SetStrictIntDecoding
+// rejects a column value that doesn't fit its int field without loss
+// — a fractional part, or a magnitude outside the field's width — and
+// that a value which does fit still decodes normally.
+func TestParseResultStrictIntDecoding(t *testing.T) {
+	type narrow struct {
+		V int8 `inf:"v"`
+	}
+
+	SetStrictIntDecoding(true)
+	defer SetStrictIntDecoding(false)
+
+	var frac narrow
+	if err := ParseResult(&frac, models.Row{
+		Columns: []string{"v"},
+		Values:  [][]interface{}{{float64(3.5)}},
+	}); err == nil {
+		t.Fatal("ParseResult(3.5 into int8) = nil error, want *IntOverflowError")
+	}
+
+	var wide narrow
+	if err := ParseResult(&wide, models.Row{
+		Columns: []string{"v"},
+		Values:  [][]interface{}{{float64(200)}},
+	}); err == nil {
+		t.Fatal("ParseResult(200 into int8) = nil error, want *IntOverflowError")
+	}
+
+	var ok narrow
+	if err := ParseResult(&ok, models.Row{
+		Columns: []string{"v"},
+		Values:  [][]interface{}{{float64(5)}},
+	}); err != nil {
+		t.Fatalf("ParseResult(5 into int8): %v", err)
+	}
+	if ok.V != 5 {
+		t.Fatalf("ok.V = %d, want 5", ok.V)
+	}
+}
+
+// TestParseResultStrictNumericParsing confirms SetStrictNumericParsing
+// turns an unparseable numeric column value — a string that isn't a
+// number — into an error instead of a silent 0, and that a genuinely
+// parseable value still decodes normally.
+func TestParseResultStrictNumericParsing(t *testing.T) {
+	type metric struct {
+		V float64 `inf:"v"`
+	}
+
+	SetStrictNumericParsing(true)
+	defer SetStrictNumericParsing(false)
+
+	var bad metric
+	if err := ParseResult(&bad, models.Row{
+		Columns: []string{"v"},
+		Values:  [][]interface{}{{"N/A"}},
+	}); err == nil {
+		t.Fatal(`ParseResult("N/A" into float64) = nil error, want a parse error`)
+	}
+
+	var good metric
+	if err := ParseResult(&good, models.Row{
+		Columns: []string{"v"},
+		Values:  [][]interface{}{{"3.5"}},
+	}); err != nil {
+		t.Fatalf(`ParseResult("3.5" into float64): %v`, err)
+	}
+	if good.V != 3.5 {
+		t.Fatalf("good.V = %v, want 3.5", good.V)
+	}
+}
+
+// TestParseIntoMapsStatementsToDestinations confirms ParseInto maps
+// the Nth statement's series to the Nth destination: a single-series
+// statement decodes straight into its struct destination, a
+// multi-series (GROUP BY) statement decodes into its slice
+// destination, and a statement with no series leaves its destination
+// an empty slice instead of erroring.
+func TestParseIntoMapsStatementsToDestinations(t *testing.T) {
+	type total struct {
+		Value float64 `inf:"value"`
+	}
+	type hostMetric struct {
+		Host  string  `inf:"host,tag"`
+		Value float64 `inf:"value"`
+	}
+
+	results := []client.Result{
+		{
+			Series: []models.Row{
+				{Columns: []string{"value"}, Values: [][]interface{}{{float64(42)}}},
+			},
+		},
+		{
+			Series: []models.Row{
+				{Tags: map[string]string{"host": "a"}, Columns: []string{"value"}, Values: [][]interface{}{{float64(1)}}},
+				{Tags: map[string]string{"host": "b"}, Columns: []string{"value"}, Values: [][]interface{}{{float64(2)}}},
+			},
+		},
+		{},
+	}
+
+	var t1 total
+	var t2 []hostMetric
+	var t3 []hostMetric
+	if err := ParseInto(results, &t1, &t2, &t3); err != nil {
+		t.Fatalf("ParseInto: %v", err)
+	}
+	if t1.Value != 42 {
+		t.Fatalf("t1 = %+v, want Value=42", t1)
+	}
+	want2 := []hostMetric{{Host: "a", Value: 1}, {Host: "b", Value: 2}}
+	if len(t2) != len(want2) || t2[0] != want2[0] || t2[1] != want2[1] {
+		t.Fatalf("t2 = %+v, want %+v", t2, want2)
+	}
+	if len(t3) != 0 {
+		t.Fatalf("t3 = %+v, want empty", t3)
+	}
+}
+
+// TestParseResultWithOptionsColumnMap confirms WithColumnMap renames a
+// column (and a tag) before field matching, so a response column named
+// after a function (e.g. derivative("value")) still reaches the field
+// tagged with the name the struct actually expects.
+func TestParseResultWithOptionsColumnMap(t *testing.T) {
+	type metric struct {
+		Region string  `inf:"region,tag"`
+		Rate   float64 `inf:"rate"`
+	}
+
+	row := models.Row{
+		Tags:    map[string]string{"loc": "us"},
+		Columns: []string{"derivative"},
+		Values:  [][]interface{}{{float64(3.5)}},
+	}
+
+	var m metric
+	rename := map[string]string{"derivative": "rate", "loc": "region"}
+	if err := ParseResultWithOptions(&m, row, WithColumnMap(rename)); err != nil {
+		t.Fatalf("ParseResultWithOptions: %v", err)
+	}
+	if m.Rate != 3.5 || m.Region != "us" {
+		t.Fatalf("ParseResultWithOptions = %+v, want {Region:us Rate:3.5}", m)
+	}
+}
+
+// TestParseResultWithOptionsIgnore confirms WithIgnore drops matching
+// columns and tags — an exact name and a "*" prefix pattern — from a
+// map[string]interface{} destination, which otherwise has no struct
+// tags of its own to select what it keeps.
+func TestParseResultWithOptionsIgnore(t *testing.T) {
+	row := models.Row{
+		Tags:    map[string]string{"host": "web1", "internal_id": "42"},
+		Columns: []string{"time", "value", "internal_debug"},
+		Values:  [][]interface{}{{"2023-01-02T03:04:05Z", float64(7), "noisy"}},
+	}
+
+	var m map[string]interface{}
+	if err := ParseResultWithOptions(&m, row, WithIgnore("time", "internal_*")); err != nil {
+		t.Fatalf("ParseResultWithOptions: %v", err)
+	}
+	want := map[string]interface{}{"host": "web1", "value": float64(7)}
+	if len(m) != len(want) || m["host"] != want["host"] || m["value"] != want["value"] {
+		t.Fatalf("ParseResultWithOptions = %v, want %v", m, want)
+	}
+}
+
+// TestParseResultValidatesAllRequestedColumns confirms ParseResult
+// checks every name passed as columns against the series' columns and
+// tags, not just the first, and reports every missing one together
+// instead of failing on the first and leaving the rest undiagnosed.
+func TestParseResultValidatesAllRequestedColumns(t *testing.T) {
+	row := models.Row{
+		Tags:    map[string]string{"host": "web1"},
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{float64(1)}},
+	}
+
+	var v float64
+	err := ParseResult(&v, row, "value", "host", "missing1", "missing2")
+	mcerr, ok := err.(*MissingColumnsError)
+	if !ok {
+		t.Fatalf("ParseResult error = %v (%T), want *MissingColumnsError", err, err)
+	}
+	want := []string{"missing1", "missing2"}
+	if len(mcerr.Columns) != len(want) || mcerr.Columns[0] != want[0] || mcerr.Columns[1] != want[1] {
+		t.Fatalf("MissingColumnsError.Columns = %v, want %v", mcerr.Columns, want)
+	}
+}
+
+// TestParseResultColumnwiseMap confirms a map[string][]T destination
+// collects each column's values across every row into its own slice —
+// the column-major shape a charting library wants — instead of the
+// usual row-major []struct, and that a columns filter still restricts
+// which columns end up as keys.
+func TestParseResultColumnwiseMap(t *testing.T) {
+	row := models.Row{
+		Columns: []string{"time", "value"},
+		Values: [][]interface{}{
+			{"2023-01-02T03:04:05Z", float64(1)},
+			{"2023-01-02T03:05:05Z", float64(2)},
+		},
+	}
+
+	var filtered map[string][]float64
+	if err := ParseResult(&filtered, row, "value"); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if want := []float64{1, 2}; len(filtered) != 1 || len(filtered["value"]) != 2 ||
+		filtered["value"][0] != want[0] || filtered["value"][1] != want[1] {
+		t.Fatalf("filtered = %v, want map[value:%v]", filtered, want)
+	}
+
+	var full map[string][]interface{}
+	if err := ParseResult(&full, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if len(full) != 2 || len(full["time"]) != 2 || len(full["value"]) != 2 {
+		t.Fatalf("full = %v, want keys time and value with 2 entries each", full)
+	}
+	wantTime, _ := time.Parse(time.RFC3339, "2023-01-02T03:04:05Z")
+	if tm, ok := full["time"][0].(time.Time); !ok || !tm.Equal(wantTime) {
+		t.Fatalf(`full["time"][0] = %v (%T), want %v`, full["time"][0], full["time"][0], wantTime)
+	}
+}
+
+// TestParseResultRangeDecodesWindow confirms ParseResultRange decodes
+// only the requested row window, clamping offset and limit to the
+// series' actual row count instead of panicking on an out-of-range
+// slice.
+func TestParseResultRangeDecodesWindow(t *testing.T) {
+	row := models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{float64(1)}, {float64(2)}, {float64(3)}, {float64(4)}},
+	}
+
+	var page []float64
+	if err := ParseResultRange(&page, row, 1, 2); err != nil {
+		t.Fatalf("ParseResultRange: %v", err)
+	}
+	if want := []float64{2, 3}; len(page) != 2 || page[0] != want[0] || page[1] != want[1] {
+		t.Fatalf("page = %v, want %v", page, want)
+	}
+
+	var tail []float64
+	if err := ParseResultRange(&tail, row, 3, 10); err != nil {
+		t.Fatalf("ParseResultRange: %v", err)
+	}
+	if want := []float64{4}; len(tail) != 1 || tail[0] != want[0] {
+		t.Fatalf("tail = %v, want %v", tail, want)
+	}
+
+	var past []float64
+	if err := ParseResultRange(&past, row, 10, 5); err != nil {
+		t.Fatalf("ParseResultRange: %v", err)
+	}
+	if len(past) != 0 {
+		t.Fatalf("past = %v, want empty", past)
+	}
+}
+
+// TestParseResultConvertsTypedTags confirms a tag field typed as
+// something other than string — an int, a bool — decodes its tag's
+// string value through the same type-directed conversion a column
+// value would get, instead of requiring every tag field to be a
+// string, and that SetStrictNumericParsing surfaces an unparseable
+// tag value as an error instead of silently leaving the field zero.
+func TestParseResultConvertsTypedTags(t *testing.T) {
+	type shard struct {
+		ShardID int  `inf:"shard_id,tag"`
+		Active  bool `inf:"active,tag"`
+	}
+
+	row := models.Row{
+		Tags:    map[string]string{"shard_id": "7", "active": "true"},
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{float64(1)}},
+	}
+	var s shard
+	if err := ParseResult(&s, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if s.ShardID != 7 || !s.Active {
+		t.Fatalf("ParseResult = %+v, want {ShardID:7 Active:true}", s)
+	}
+
+	SetStrictNumericParsing(true)
+	defer SetStrictNumericParsing(false)
+
+	bad := models.Row{
+		Tags:    map[string]string{"shard_id": "not-a-number", "active": "true"},
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{float64(1)}},
+	}
+	var s2 shard
+	if err := ParseResult(&s2, bad); err == nil {
+		t.Fatal(`ParseResult(shard_id="not-a-number") = nil error, want a parse error`)
+	}
+}
+
+// TestParseResultMatchesAliasedColumnName confirms a field tagged
+// `inf:"usage|mean_usage"` decodes either column name, for a query
+// whose aggregate function renames the column InfluxDB's side.
+func TestParseResultMatchesAliasedColumnName(t *testing.T) {
+	type sample struct {
+		Usage float64 `inf:"usage|mean_usage"`
+	}
+
+	plain := models.Row{
+		Columns: []string{"usage"},
+		Values:  [][]interface{}{{float64(0.5)}},
+	}
+	var s sample
+	if err := ParseResult(&s, plain); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if s.Usage != 0.5 {
+		t.Fatalf("ParseResult(usage) = %+v, want Usage=0.5", s)
+	}
+
+	aggregated := models.Row{
+		Columns: []string{"mean_usage"},
+		Values:  [][]interface{}{{float64(0.75)}},
+	}
+	var s2 sample
+	if err := ParseResult(&s2, aggregated); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if s2.Usage != 0.75 {
+		t.Fatalf("ParseResult(mean_usage) = %+v, want Usage=0.75", s2)
+	}
+}
+
+// TestParseResultSkipsExplicitlyExcludedField confirms a field tagged
+// `inf:"-"` stays at its Go zero value on decode even when a column
+// happens to titleCase-match its Go name, the same exclusion ToPoint
+// already honors on encode.
+func TestParseResultSkipsExplicitlyExcludedField(t *testing.T) {
+	type sample struct {
+		Code  int `inf:"-"`
+		Value float64
+	}
+
+	row := models.Row{
+		Columns: []string{"code", "value"},
+		Values:  [][]interface{}{{float64(42), float64(1.5)}},
+	}
+	var s sample
+	if err := ParseResult(&s, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if s.Code != 0 || s.Value != 1.5 {
+		t.Fatalf("ParseResult = %+v, want {Code:0 Value:1.5}", s)
+	}
+}
+
+// TestParseResultDefaultValues confirms a field tagged
+// `inf:"name,default=VALUE"` gets that default instead of its Go zero
+// value when its column/tag is absent from the row, but is still
+// decoded normally when the column/tag is present.
+func TestParseResultDefaultValues(t *testing.T) {
+	type sample struct {
+		Region string `inf:"region,tag,default=unknown"`
+		Count  int    `inf:"count,default=7"`
+		Value  float64
+	}
+
+	row := models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{float64(1.5)}},
+	}
+	var s sample
+	if err := ParseResult(&s, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if s.Region != "unknown" || s.Count != 7 || s.Value != 1.5 {
+		t.Fatalf("ParseResult = %+v, want {Region:unknown Count:7 Value:1.5}", s)
+	}
+
+	full := models.Row{
+		Tags:    map[string]string{"region": "us-east"},
+		Columns: []string{"value", "count"},
+		Values:  [][]interface{}{{float64(2.5), float64(3)}},
+	}
+	var s2 sample
+	if err := ParseResult(&s2, full); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if s2.Region != "us-east" || s2.Count != 3 || s2.Value != 2.5 {
+		t.Fatalf("ParseResult = %+v, want {Region:us-east Count:3 Value:2.5}", s2)
+	}
+}
+
+// TestParseResultFillsArray confirms ParseResult decodes into a fixed-size
+// [N]T array destination, filling up to N rows and leaving any remaining
+// slots (or rows) untouched instead of returning "unrecognized type".
+func TestParseResultFillsArray(t *testing.T) {
+	row := models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{float64(1)}, {float64(2)}, {float64(3)}},
+	}
+
+	var short [5]float64
+	if err := ParseResult(&short, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if want := [5]float64{1, 2, 3, 0, 0}; short != want {
+		t.Fatalf("ParseResult = %v, want %v", short, want)
+	}
+
+	before := ArrayElementsWritten()
+	var tight [2]float64
+	if err := ParseResult(&tight, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if want := [2]float64{1, 2}; tight != want {
+		t.Fatalf("ParseResult = %v, want %v", tight, want)
+	}
+	if got := ArrayElementsWritten() - before; got != 2 {
+		t.Fatalf("ArrayElementsWritten delta = %d, want 2", got)
+	}
+}
+
+// TestParseResultSlicePointerElements confirms ParseResult into a
+// []*T destination allocates each element instead of leaving a nil
+// pointer or panicking, decoding each row into its own *T the same way
+// a []T destination would into its own T.
+func TestParseResultSlicePointerElements(t *testing.T) {
+	type sample struct {
+		Value float64
+	}
+
+	row := models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{float64(1)}, {float64(2)}},
+	}
+
+	var ptrs []*sample
+	if err := ParseResult(&ptrs, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if len(ptrs) != 2 || ptrs[0] == nil || ptrs[1] == nil {
+		t.Fatalf("ParseResult = %+v, want 2 non-nil elements", ptrs)
+	}
+	if ptrs[0].Value != 1 || ptrs[1].Value != 2 {
+		t.Fatalf("ParseResult = {%+v, %+v}, want {Value:1, Value:2}", ptrs[0], ptrs[1])
+	}
+}
+
+// TestParseResultSliceMapElementsNeverNil confirms ParseResult into a
+// []map[string]interface{} destination never leaves an element nil —
+// even a row whose only column is null, which would otherwise leave
+// the zero value for a map (nil) instead of an allocated-but-empty
+// one — since a caller indexing into a nil map panics.
+func TestParseResultSliceMapElementsNeverNil(t *testing.T) {
+	row := models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{nil}},
+	}
+
+	var maps []map[string]interface{}
+	if err := ParseResult(&maps, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if len(maps) != 1 || maps[0] == nil {
+		t.Fatalf("ParseResult = %+v, want 1 non-nil map element", maps)
+	}
+	maps[0]["x"] = 1 // panics if the element is still a nil map
+	if maps[0]["x"] != 1 {
+		t.Fatalf("maps[0] = %v, want x=1", maps[0])
+	}
+}
+
+// TestParseResultPreservesInt64PrecisionFromJSONNumber confirms an
+// int64 field decodes a json.Number column (client/v2's UseNumber
+// representation of an InfluxDB integer) via json.Number.Int64 instead
+// of round-tripping it through float64, which would lose precision for
+// a value past 2^53.
+func TestParseResultPreservesInt64PrecisionFromJSONNumber(t *testing.T) {
+	type sample struct {
+		Count int64 `inf:"count"`
+	}
+
+	const want = 9007199254740993 // 2^53 + 1, unrepresentable exactly as float64
+	row := models.Row{
+		Columns: []string{"count"},
+		Values:  [][]interface{}{{json.Number(strconv.FormatInt(want, 10))}},
+	}
+
+	var s sample
+	if err := ParseResult(&s, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if s.Count != want {
+		t.Fatalf("Count = %d, want %d", s.Count, want)
+	}
+}
+
+// TestParseResultDecodesJSONNumberIntoStringField confirms a string
+// destination decodes a json.Number column as its exact digits instead
+// of Go's default scientific-notation float formatting.
+func TestParseResultDecodesJSONNumberIntoStringField(t *testing.T) {
+	type sample struct {
+		Count string `inf:"count"`
+	}
+
+	row := models.Row{
+		Columns: []string{"count"},
+		Values:  [][]interface{}{{json.Number("9007199254740993")}},
+	}
+
+	var s sample
+	if err := ParseResult(&s, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if s.Count != "9007199254740993" {
+		t.Fatalf("Count = %q, want %q", s.Count, "9007199254740993")
+	}
+}
+
+// TestParseResultDecodesBigIntAndBigFloat confirms ParseResult decodes
+// into big.Int/big.Float destinations (and their pointer forms)
+// without losing precision a float64 column would, using the exact
+// digits of a json.Number column, and that a plain (non-json.Number)
+// numeric value still decodes instead of erroring on the stray
+// exponent notation a naive string conversion would produce.
+func TestParseResultDecodesBigIntAndBigFloat(t *testing.T) {
+	type sample struct {
+		Count  big.Int
+		Ratio  *big.Float
+		Amount big.Int
+	}
+
+	row := models.Row{
+		Columns: []string{"count", "ratio", "amount"},
+		Values: [][]interface{}{{
+			json.Number("123456789012345678901234567890"),
+			json.Number("3.5"),
+			float64(42),
+		}},
+	}
+
+	var s sample
+	if err := ParseResult(&s, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if want := "123456789012345678901234567890"; s.Count.String() != want {
+		t.Fatalf("Count = %s, want %s", s.Count.String(), want)
+	}
+	if s.Ratio == nil || s.Ratio.String() != "3.5" {
+		t.Fatalf("Ratio = %v, want 3.5", s.Ratio)
+	}
+	if want := "42"; s.Amount.String() != want {
+		t.Fatalf("Amount = %s, want %s", s.Amount.String(), want)
+	}
+}
+
+// idLike mimics a TextMarshaler/TextUnmarshaler-based identifier type
+// such as github.com/google/uuid.UUID, to confirm those already
+// round-trip through the generic TextMarshaler/TextUnmarshaler dispatch
+// in convert.go without any dedicated support.
+type idLike [4]byte
+
+func (id idLike) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(id[:])), nil
+}
+
+func (id *idLike) UnmarshalText(text []byte) error {
+	b, err := hex.DecodeString(string(text))
+	if err != nil || len(b) != len(id) {
+		return fmt.Errorf("bad id: %s", text)
+	}
+	copy(id[:], b)
+	return nil
+}
+
+// TestParseResultRoundTripsIDAndIPFields confirms net.IP and a
+// UUID-like TextMarshaler/TextUnmarshaler type already round-trip
+// through ToPoint and ParseResult via the existing generic conversion
+// dispatch in convert.go, with no type-specific code.
+func TestParseResultRoundTripsIDAndIPFields(t *testing.T) {
+	type sample struct {
+		ID idLike
+		IP net.IP
+	}
+
+	s := sample{ID: idLike{1, 2, 3, 4}, IP: net.ParseIP("192.168.1.1")}
+	p, err := ToPoint(&s)
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+
+	row := models.Row{Columns: []string{"id", "ip"}, Values: [][]interface{}{{fields["id"], fields["ip"]}}}
+	var s2 sample
+	if err := ParseResult(&s2, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if s2.ID != s.ID {
+		t.Fatalf("ID = %x, want %x", s2.ID, s.ID)
+	}
+	if s2.IP.String() != s.IP.String() {
+		t.Fatalf("IP = %v, want %v", s2.IP, s.IP)
+	}
+}
+
+// TestParseResultRoundTripsWeekday confirms time.Weekday encodes as its
+// name (e.g. "Wednesday") via ToPoint and decodes back from that name
+// via ParseResult, despite isPrimitiveKind excluding its underlying int
+// kind from the generic Stringer-based path, and that an unrecognized
+// name is reported as an error rather than silently zeroed.
+func TestParseResultRoundTripsWeekday(t *testing.T) {
+	type sample struct {
+		Day time.Weekday
+	}
+
+	s := sample{Day: time.Wednesday}
+	p, err := ToPoint(&s)
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if fields["day"] != "Wednesday" {
+		t.Fatalf("day field = %v, want %q", fields["day"], "Wednesday")
+	}
+
+	row := models.Row{Columns: []string{"day"}, Values: [][]interface{}{{fields["day"]}}}
+	var s2 sample
+	if err := ParseResult(&s2, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if s2.Day != time.Wednesday {
+		t.Fatalf("Day = %v, want %v", s2.Day, time.Wednesday)
+	}
+
+	var bad sample
+	badRow := models.Row{Columns: []string{"day"}, Values: [][]interface{}{{"NotAWeekday"}}}
+	if err := ParseResult(&bad, badRow); err == nil {
+		t.Fatal("expected error decoding an unrecognized weekday name")
+	}
+}
+
+// TestRowsIteratesAndScansEachRow confirms NewRows iterates every row
+// of every series in order, that Scan decodes just the current row,
+// and that Close stops iteration early (Next returns false from then
+// on) even with rows left.
+func TestRowsIteratesAndScansEachRow(t *testing.T) {
+	type sample struct {
+		Value float64
+	}
+
+	series := []models.Row{
+		{Columns: []string{"value"}, Values: [][]interface{}{{float64(1)}, {float64(2)}}},
+		{Columns: []string{"value"}, Values: [][]interface{}{{float64(3)}}},
+	}
+
+	rows := NewRows(series)
+	var got []float64
+	for rows.Next() {
+		var s sample
+		if err := rows.Scan(&s); err != nil {
+			t.Fatalf("Scan: %v", err)
+		}
+		got = append(got, s.Value)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	if want := []float64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got = %v, want %v", got, want)
+	}
+
+	rows2 := NewRows(series)
+	if !rows2.Next() {
+		t.Fatal("Next = false, want true for first row")
+	}
+	if err := rows2.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if rows2.Next() {
+		t.Fatal("Next = true after Close, want false")
+	}
+}
+
+// TestRowsFromResultsReportsStatementError confirms RowsFromResults
+// stops iterating at a result carrying its own Err (InfluxDB's way of
+// reporting one multi-statement query's failure inline) instead of
+// decoding past it, surfacing that failure through Err instead of a
+// second return value.
+func TestRowsFromResultsReportsStatementError(t *testing.T) {
+	results := []client.Result{
+		{Series: []models.Row{{Columns: []string{"value"}, Values: [][]interface{}{{float64(1)}}}}},
+		{Err: "boom"},
+	}
+
+	rows := RowsFromResults(results)
+	if rows.Next() {
+		t.Fatal("Next = true, want false when any result errors")
+	}
+	if err := rows.Err(); err == nil || err.Error() != "boom" {
+		t.Fatalf("Err = %v, want \"boom\"", err)
+	}
+}
+
+// TestConcurrentParseResultAndSettings confirms ParseResult, ToPoint
+// and a setting that drops every cached type plan (SetStrictTagging
+// here, same codepath as SetJSONTagFallback/SetNamingStrategy) can run
+// concurrently without a data race (run under `go test -race`), since
+// typePlans is mutated key-by-key rather than replaced wholesale.
+func TestConcurrentParseResultAndSettings(t *testing.T) {
+	type sample struct {
+		Value float64 `inf:"value,field"`
+	}
+
+	row := models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{float64(1)}},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			var s sample
+			ParseResult(&s, row)
+		}()
+		go func() {
+			defer wg.Done()
+			ToPoint(&sample{Value: 1})
+		}()
+		go func(i int) {
+			defer wg.Done()
+			SetStrictTagging(i%2 == 0)
+		}(i)
+	}
+	wg.Wait()
+	SetStrictTagging(false)
+}
+
+// meters is a domain type that also implements fmt.Stringer, so this
+// test can confirm a registered Converter takes priority over that
+// built-in mechanism rather than merely supplementing it.
+type meters float64
+
+func (m meters) String() string { return fmt.Sprintf("%gm", float64(m)) }
+
+// TestRegisterConverterTakesPriorityOverBuiltins confirms a Converter
+// registered for a type is consulted by both ToPoint and ParseResult
+// before any built-in mechanism (here, fmt.Stringer) for that same
+// type, and that it round-trips a value through its own To/From funcs
+// rather than the Stringer's representation.
+func TestRegisterConverterTakesPriorityOverBuiltins(t *testing.T) {
+	RegisterConverter(reflect.TypeOf(meters(0)),
+		func(v interface{}) (interface{}, error) {
+			return float64(v.(meters)) * 1000, nil
+		},
+		func(v interface{}) (interface{}, error) {
+			switch n := v.(type) {
+			case json.Number:
+				f, err := n.Float64()
+				return meters(f / 1000), err
+			case float64:
+				return meters(n / 1000), nil
+			default:
+				return nil, fmt.Errorf("unexpected value %v (%T)", v, v)
+			}
+		})
+
+	type sample struct {
+		Distance meters
+	}
+
+	s := sample{Distance: 2.5}
+	p, err := ToPoint(&s)
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if want := float64(2500); fields["distance"] != want {
+		t.Fatalf("distance field = %v, want %v (Converter output, not %q)", fields["distance"], want, s.Distance)
+	}
+
+	row := models.Row{Columns: []string{"distance"}, Values: [][]interface{}{{json.Number("2500")}}}
+	var s2 sample
+	if err := ParseResult(&s2, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if s2.Distance != s.Distance {
+		t.Fatalf("Distance = %v, want %v", s2.Distance, s.Distance)
+	}
+}
+
+// TestParseResultDecodesLegacyBoolStrings confirms "true"/"false",
+// "t"/"f" and "yes"/"no" (any case) all decode consistently into both
+// a bool field and a numeric field, the way a legacy measurement that
+// stored booleans as strings needs.
+func TestParseResultDecodesLegacyBoolStrings(t *testing.T) {
+	type sample struct {
+		Active  bool
+		Enabled bool
+		Count   int
+		Ratio   float64
+	}
+
+	row := models.Row{
+		Columns: []string{"active", "enabled", "count", "ratio"},
+		Values:  [][]interface{}{{"Yes", "NO", "T", "f"}},
+	}
+
+	var s sample
+	if err := ParseResult(&s, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if !s.Active {
+		t.Fatal("Active = false, want true (from \"Yes\")")
+	}
+	if s.Enabled {
+		t.Fatal("Enabled = true, want false (from \"NO\")")
+	}
+	if s.Count != 1 {
+		t.Fatalf("Count = %d, want 1 (from \"T\")", s.Count)
+	}
+	if s.Ratio != 0 {
+		t.Fatalf("Ratio = %v, want 0 (from \"f\")", s.Ratio)
+	}
+}
+
+// TestScanRowAssignsValuesPositionally confirms ScanRow decodes a raw
+// row's values into a list of destination pointers by position, like
+// sql.Rows.Scan, and that it errors instead of panicking when given
+// fewer values than destinations.
+func TestScanRowAssignsValuesPositionally(t *testing.T) {
+	var host string
+	var value float64
+	vals := []interface{}{"server1", json.Number("42.5")}
+	if err := ScanRow(vals, &host, &value); err != nil {
+		t.Fatalf("ScanRow: %v", err)
+	}
+	if host != "server1" {
+		t.Fatalf("host = %q, want %q", host, "server1")
+	}
+	if value != 42.5 {
+		t.Fatalf("value = %v, want 42.5", value)
+	}
+
+	if err := ScanRow(vals[:1], &host, &value); err == nil {
+		t.Fatal("expected error scanning with fewer values than destinations")
+	}
+}
+
+// TestRowsScanRowIteratesPositionally confirms Rows.ScanRow decodes
+// each row Next advances to positionally, the same values Scan would
+// otherwise decode into a single struct or map destination.
+func TestRowsScanRowIteratesPositionally(t *testing.T) {
+	series := []models.Row{
+		{Columns: []string{"host", "value"}, Values: [][]interface{}{
+			{"server1", json.Number("1")},
+			{"server2", json.Number("2")},
+		}},
+	}
+	rows := NewRows(series)
+	var hosts []string
+	var values []float64
+	for rows.Next() {
+		var host string
+		var value float64
+		if err := rows.ScanRow(&host, &value); err != nil {
+			t.Fatalf("ScanRow: %v", err)
+		}
+		hosts = append(hosts, host)
+		values = append(values, value)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	if want := []string{"server1", "server2"}; !reflect.DeepEqual(hosts, want) {
+		t.Fatalf("hosts = %v, want %v", hosts, want)
+	}
+	if want := []float64{1, 2}; !reflect.DeepEqual(values, want) {
+		t.Fatalf("values = %v, want %v", values, want)
+	}
+}
+
+// TestRowsAccessorsTrackCurrentSeries confirms Name, Tags, Columns and
+// Values all describe the current row's own series, switching over
+// correctly when Next crosses from one series into the next, and all
+// report a zero value before the first Next call.
+func TestRowsAccessorsTrackCurrentSeries(t *testing.T) {
+	series := []models.Row{
+		{
+			Name:    "cpu",
+			Tags:    map[string]string{"host": "a"},
+			Columns: []string{"value"},
+			Values:  [][]interface{}{{float64(1)}, {float64(2)}},
+		},
+		{
+			Name:    "mem",
+			Tags:    map[string]string{"host": "b"},
+			Columns: []string{"used"},
+			Values:  [][]interface{}{{float64(3)}},
+		},
+	}
+
+	rows := NewRows(series)
+	if name := rows.Name(); name != "" {
+		t.Fatalf("Name before Next = %q, want \"\"", name)
+	}
+	if tags := rows.Tags(); tags != nil {
+		t.Fatalf("Tags before Next = %v, want nil", tags)
+	}
+	if cols := rows.Columns(); cols != nil {
+		t.Fatalf("Columns before Next = %v, want nil", cols)
+	}
+	if vals := rows.Values(); vals != nil {
+		t.Fatalf("Values before Next = %v, want nil", vals)
+	}
+
+	var names []string
+	var hosts []string
+	var cols [][]string
+	var vals [][]interface{}
+	for rows.Next() {
+		names = append(names, rows.Name())
+		hosts = append(hosts, rows.Tags()["host"])
+		cols = append(cols, rows.Columns())
+		vals = append(vals, rows.Values())
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+
+	wantNames := []string{"cpu", "cpu", "mem"}
+	wantHosts := []string{"a", "a", "b"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("names = %v, want %v", names, wantNames)
+	}
+	if !reflect.DeepEqual(hosts, wantHosts) {
+		t.Fatalf("hosts = %v, want %v", hosts, wantHosts)
+	}
+	if !reflect.DeepEqual(cols[0], []string{"value"}) || !reflect.DeepEqual(cols[2], []string{"used"}) {
+		t.Fatalf("columns = %v", cols)
+	}
+	if !reflect.DeepEqual(vals[0], []interface{}{float64(1)}) || !reflect.DeepEqual(vals[2], []interface{}{float64(3)}) {
+		t.Fatalf("values = %v", vals)
+	}
+}
+
+// TestParseResultCountTruncateShrinksReusedSlice confirms
+// ParseResultCountTruncate, unlike plain ParseResultCount, truncates a
+// reused destination slice to the new result's row count instead of
+// leaving stale trailing elements from a longer previous decode, while
+// keeping the backing array (so a later, longer decode still avoids a
+// fresh allocation).
+func TestParseResultCountTruncateShrinksReusedSlice(t *testing.T) {
+	type sample struct {
+		Value float64
+	}
+
+	longRow := models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{json.Number("1")}, {json.Number("2")}, {json.Number("3")}},
+	}
+	shortRow := models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{json.Number("9")}},
+	}
+
+	buf := make([]sample, 0, 10)
+	if _, err := ParseResultCountTruncate(&buf, longRow, "value"); err != nil {
+		t.Fatalf("ParseResultCountTruncate: %v", err)
+	}
+	if len(buf) != 3 {
+		t.Fatalf("len(buf) = %d, want 3", len(buf))
+	}
+	origCap := cap(buf)
+
+	n, err := ParseResultCountTruncate(&buf, shortRow, "value")
+	if err != nil {
+		t.Fatalf("ParseResultCountTruncate: %v", err)
+	}
+	if n != 1 || len(buf) != 1 {
+		t.Fatalf("n = %d, len(buf) = %d, want 1, 1", n, len(buf))
+	}
+	if buf[0].Value != 9 {
+		t.Fatalf("buf[0].Value = %v, want 9", buf[0].Value)
+	}
+	if cap(buf) != origCap {
+		t.Fatalf("cap(buf) = %d, want unchanged %d (same backing array reused)", cap(buf), origCap)
+	}
+
+	// Plain ParseResultCount never shrinks: reusing the same pattern
+	// leaves the second row's trailing stale elements in place.
+	buf2 := make([]sample, 0, 10)
+	ParseResultCount(&buf2, longRow, "value")
+	ParseResultCount(&buf2, shortRow, "value")
+	if len(buf2) != 3 {
+		t.Fatalf("len(buf2) = %d, want 3 (ParseResultCount grows but never shrinks)", len(buf2))
+	}
+	if buf2[0].Value != 9 || buf2[1].Value != 2 {
+		t.Fatalf("buf2 = %v, want [9 2 3] (row 0 overwritten, rows 1-2 stale)", buf2)
+	}
+}
+
+// TestParseResultFloatSliceFastPath confirms a *[]float64 destination
+// decodes through fastPathFloatSlice with the same semantics as the
+// general reflect-based path: it grows without shrinking, honors a
+// null value, and respects SetStrictNumericParsing.
+func TestParseResultFloatSliceFastPath(t *testing.T) {
+	row := models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{json.Number("1.5")}, {nil}, {json.Number("3")}},
+	}
+
+	var got []float64
+	n, err := ParseResultCount(&got, row, "value")
+	if err != nil {
+		t.Fatalf("ParseResultCount: %v", err)
+	}
+	if n != 3 || !reflect.DeepEqual(got, []float64{1.5, 0, 3}) {
+		t.Fatalf("n = %d, got = %v, want 3, [1.5 0 3]", n, got)
+	}
+
+	shortRow := models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{json.Number("9")}},
+	}
+	if _, err := ParseResultCountTruncate(&got, shortRow, "value"); err != nil {
+		t.Fatalf("ParseResultCountTruncate: %v", err)
+	}
+	if !reflect.DeepEqual(got, []float64{9}) {
+		t.Fatalf("got = %v, want [9]", got)
+	}
+
+	SetStrictNumericParsing(true)
+	defer SetStrictNumericParsing(false)
+	var bad []float64
+	badRow := models.Row{
+		Columns: []string{"value"},
+		Values:  [][]interface{}{{"not-a-number"}},
+	}
+	if _, err := ParseResultCount(&bad, badRow, "value"); err == nil {
+		t.Fatal("ParseResultCount with strict numeric parsing = nil error, want one")
+	}
+}
+
+// TestParamsQuerySetsBoundParameters confirms paramsQuery, the helper
+// behind both QueryWithParamsContext and QueryBindContext, sets
+// client.Query.Parameters from the given map instead of leaving
+// callers to string-concatenate values into the command.
+func TestParamsQuerySetsBoundParameters(t *testing.T) {
+	q := paramsQuery("mydb", "SELECT * FROM cpu WHERE host = $host", map[string]interface{}{"host": "server1"})
+	if q.Database != "mydb" {
+		t.Fatalf("Database = %q, want %q", q.Database, "mydb")
+	}
+	if q.Command != "SELECT * FROM cpu WHERE host = $host" {
+		t.Fatalf("Command = %q", q.Command)
+	}
+	if q.Parameters["host"] != "server1" {
+		t.Fatalf("Parameters[host] = %v, want %q", q.Parameters["host"], "server1")
+	}
+}
+
+// TestBindParamsHarvestsFromInfTags confirms bindParams extracts the
+// $name-style parameters QueryBind needs from bind's `inf` tags,
+// instead of requiring callers to build the params map themselves.
+func TestBindParamsHarvestsFromInfTags(t *testing.T) {
+	type filter struct {
+		Host   string  `inf:"host,tag"`
+		MinCPU float64 `inf:"min_cpu"`
+	}
+
+	params, err := bindParams("SELECT * FROM cpu WHERE host = $host AND usage > $min_cpu", filter{Host: "server1", MinCPU: 80})
+	if err != nil {
+		t.Fatalf("bindParams: %v", err)
+	}
+	if params["host"] != "server1" {
+		t.Fatalf("params[host] = %v, want %q", params["host"], "server1")
+	}
+	if params["min_cpu"] != float64(80) {
+		t.Fatalf("params[min_cpu] = %v, want 80", params["min_cpu"])
+	}
+}
+
+// TestQueryWithParamsSendsBoundParameters confirms QueryWithParamsContext
+// carries params all the way through to the HTTP request InfluxDB
+// receives, JSON-encoded into the "params" query-string parameter the
+// v1 client itself sets from client.Query.Parameters, not just as far
+// as the client.Query value paramsQuery builds.
+func TestQueryWithParamsSendsBoundParameters(t *testing.T) {
+	var gotParams map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.Unmarshal([]byte(r.URL.Query().Get("params")), &gotParams); err != nil {
+			t.Errorf("decode params query string: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{map[string]interface{}{}}})
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.QueryWithParams("metrics", "SELECT * FROM cpu WHERE host = $host", map[string]interface{}{"host": "server1"})
+	if err != nil {
+		t.Fatalf("QueryWithParams: %v", err)
+	}
+	if gotParams["host"] != "server1" {
+		t.Fatalf("server received params[host] = %v, want %q", gotParams["host"], "server1")
+	}
+}
+
+// TestInsertManyNamedWritesEveryPointToMeasurement confirms
+// InsertManyNamed encodes every element to the given measurement and
+// writes them all as a single batch, the same way InsertMany does
+// except for the measurement override.
+func TestInsertManyNamedWritesEveryPointToMeasurement(t *testing.T) {
+	type cpuStat struct {
+		Host  string  `inf:"host,tag"`
+		Usage float64 `inf:"usage"`
+	}
+
+	var gotLine string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotLine = string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.InsertManyNamed("mydb", "cpu_eu", []cpuStat{
+		{Host: "web1", Usage: 0.5},
+		{Host: "web2", Usage: 0.25},
+	})
+	if err != nil {
+		t.Fatalf("InsertManyNamed: %v", err)
+	}
+	if !strings.Contains(gotLine, "cpu_eu,host=web1") || !strings.Contains(gotLine, "cpu_eu,host=web2") {
+		t.Fatalf("line protocol = %q, want both points named cpu_eu", gotLine)
+	}
+}
+
+// TestQuoteIdentMatchesIdent confirms QuoteIdent is the same
+// InfluxQL identifier-quoting behavior as Ident, including escaping
+// an embedded double quote and backslash.
+func TestQuoteIdentMatchesIdent(t *testing.T) {
+	name := `weird"name\`
+	if got, want := QuoteIdent(name), Ident(name); got != want {
+		t.Fatalf("QuoteIdent(%q) = %s, want %s", name, got, want)
+	}
+	if want := `"weird\"name\\"`; QuoteIdent(name) != want {
+		t.Fatalf("QuoteIdent(%q) = %s, want %s", name, QuoteIdent(name), want)
+	}
+}
+
+// TestQuoteStringEscapesLiteral confirms QuoteString quotes an
+// InfluxQL string literal, escaping an embedded single quote and
+// backslash.
+func TestQuoteStringEscapesLiteral(t *testing.T) {
+	value := `O'Brien\`
+	if want, got := `'O\'Brien\\'`, QuoteString(value); got != want {
+		t.Fatalf("QuoteString(%q) = %s, want %s", value, got, want)
+	}
+}
+
+// TestQueryfEscapesStringAndTimeArgs confirms Queryf quotes a string
+// argument as an InfluxQL string literal and a time.Time argument as
+// an RFC3339Nano string literal, while passing a numeric argument
+// through unquoted.
+func TestQueryfEscapesStringAndTimeArgs(t *testing.T) {
+	host := `a' OR '1'='1`
+	since := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	cmd := Queryf("SELECT * FROM cpu WHERE host = %s AND time > %s AND value > %d", host, since, 10)
+	want := "SELECT * FROM cpu WHERE host = " + QuoteString(host) +
+		" AND time > " + QuoteString(since.Format(time.RFC3339Nano)) +
+		" AND value > 10"
+	if cmd != want {
+		t.Fatalf("Queryf = %s, want %s", cmd, want)
+	}
+}
+
+// TestSelectColumnsDerivesFromInfTags confirms SelectColumns returns
+// only the named field columns (not tags, time, or dynamic map
+// fields) a SELECT clause should list, plus the type's measurement,
+// and that adding a field to the struct extends the column list with
+// no other code change.
+func TestSelectColumnsDerivesFromInfTags(t *testing.T) {
+	type cpuUsage struct {
+		Host  string  `inf:"host,tag"`
+		Usage float64 `inf:"usage"`
+		Load  float64 `inf:"load"`
+		Time  time.Time
+		Extra map[string]interface{} `inf:",fields"`
+	}
+
+	cols, measurement, err := SelectColumns[cpuUsage]()
+	if err != nil {
+		t.Fatalf("SelectColumns: %v", err)
+	}
+	if want := "cpu_usage"; measurement != want {
+		t.Fatalf("measurement = %q, want %q", measurement, want)
+	}
+	if want := []string{"usage", "load"}; !reflect.DeepEqual(cols, want) {
+		t.Fatalf("columns = %v, want %v", cols, want)
+	}
+}
+
+// TestLastRendersNowRelativePredicate confirms Last renders a
+// now()-relative InfluxQL predicate with the duration expressed in
+// exact nanoseconds, not Go's own "1h30m0s" duration format.
+func TestLastRendersNowRelativePredicate(t *testing.T) {
+	got := Last(15 * time.Minute)
+	want := "time > now() - 900000000000ns"
+	if got != want {
+		t.Fatalf("Last(15m) = %q, want %q", got, want)
+	}
+}
+
+// TestSinceAndBetweenRenderAbsoluteTimestamps confirms Since and
+// Between render RFC3339Nano timestamps in UTC regardless of the
+// time.Time's own Location.
+func TestSinceAndBetweenRenderAbsoluteTimestamps(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	t1 := time.Date(2024, 1, 2, 3, 4, 5, 0, loc)
+
+	if got, want := Since(t1), "time >= '2024-01-01T19:04:05Z'"; got != want {
+		t.Fatalf("Since = %q, want %q", got, want)
+	}
+
+	t2 := t1.Add(time.Hour)
+	got := Between(t1, t2)
+	want := "time >= '2024-01-01T19:04:05Z' AND time <= '2024-01-01T20:04:05Z'"
+	if got != want {
+		t.Fatalf("Between = %q, want %q", got, want)
+	}
+}
+
+// TestGroupByTimeRendersIntervalOffsetAndFill confirms GroupByTime
+// renders the interval in exact nanoseconds, omits the offset when
+// it's zero, includes it when it isn't, and appends fill() only when
+// given a non-empty FillOption (including a literal numeric one from
+// FillValue).
+func TestGroupByTimeRendersIntervalOffsetAndFill(t *testing.T) {
+	cases := []struct {
+		interval, offset time.Duration
+		fill             FillOption
+		want             string
+	}{
+		{5 * time.Minute, 0, "", "GROUP BY time(300000000000ns)"},
+		{5 * time.Minute, 0, FillNull, "GROUP BY time(300000000000ns) fill(null)"},
+		{5 * time.Minute, 30 * time.Second, FillPrevious, "GROUP BY time(300000000000ns, 30000000000ns) fill(previous)"},
+		{time.Hour, 0, FillValue(0), "GROUP BY time(3600000000000ns) fill(0)"},
+	}
+	for _, c := range cases {
+		if got := GroupByTime(c.interval, c.offset, c.fill); got != c.want {
+			t.Fatalf("GroupByTime(%v, %v, %q) = %q, want %q", c.interval, c.offset, c.fill, got, c.want)
+		}
+	}
+}
+
+// TestResultRowCountSumsEverySeries confirms resultRowCount, the
+// helper Paginator uses to decide when it has reached the last page,
+// counts rows across every series of every result instead of just the
+// first, so a multi-series GROUP BY query still pages correctly.
+func TestResultRowCountSumsEverySeries(t *testing.T) {
+	results := []client.Result{{
+		Series: []models.Row{
+			{Values: [][]interface{}{{1}, {2}}},
+			{Values: [][]interface{}{{3}}},
+		},
+	}, {
+		Series: []models.Row{
+			{Values: [][]interface{}{{4}, {5}, {6}}},
+		},
+	}}
+	if got, want := resultRowCount(results), 6; got != want {
+		t.Fatalf("resultRowCount = %d, want %d", got, want)
+	}
+}
+
+// TestSeriesCmdBuildsDeleteAndDropStatements confirms seriesCmd renders
+// the FROM clause only when measurement is given and ANDs where and
+// timeRange together into a single WHERE clause only when both are
+// given, for both DELETE and DROP SERIES' shared statement shape.
+func TestSeriesCmdBuildsDeleteAndDropStatements(t *testing.T) {
+	cases := []struct {
+		verb, measurement, where, timeRange, want string
+	}{
+		{"DELETE", "cpu", "", "time >= now() - 1h", `DELETE FROM "cpu" WHERE time >= now() - 1h`},
+		{"DELETE", "cpu", `host = 'a'`, "time >= now() - 1h", `DELETE FROM "cpu" WHERE host = 'a' AND time >= now() - 1h`},
+		{"DELETE", "", "", "time >= now() - 1h", `DELETE WHERE time >= now() - 1h`},
+		{"DROP", "cpu", `host = 'a'`, "", `DROP SERIES FROM "cpu" WHERE host = 'a'`},
+		{"DROP", "cpu", "", "", `DROP SERIES FROM "cpu"`},
+		{"DROP", "", "", "", `DROP SERIES`},
+	}
+	for _, c := range cases {
+		if got := seriesCmd(c.verb, c.measurement, c.where, c.timeRange); got != c.want {
+			t.Fatalf("seriesCmd(%q, %q, %q, %q) = %q, want %q",
+				c.verb, c.measurement, c.where, c.timeRange, got, c.want)
+		}
+	}
+}
+
+// TestBackfillCmdAppendsWhereAndGroupBy confirms backfillCmd always
+// bounds the query by the given slice's time range, ANDs opts.Where
+// onto it only when given, and appends GROUP BY only when given, so a
+// caller who omits either gets a plain sliced SELECT ... INTO.
+func TestBackfillCmdAppendsWhereAndGroupBy(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	opts := BackfillOptions{Select: "mean(value)", Into: `"downsample_1h"."cpu"`, From: `"cpu"`}
+	want := `SELECT mean(value) INTO "downsample_1h"."cpu" FROM "cpu" WHERE ` + Between(start, end)
+	if got := backfillCmd(opts, start, end); got != want {
+		t.Fatalf("backfillCmd = %q, want %q", got, want)
+	}
+
+	opts.Where = `host = 'a'`
+	opts.GroupBy = "time(1h),*"
+	want = `SELECT mean(value) INTO "downsample_1h"."cpu" FROM "cpu" WHERE ` + Between(start, end) +
+		` AND host = 'a' GROUP BY time(1h),*`
+	if got := backfillCmd(opts, start, end); got != want {
+		t.Fatalf("backfillCmd = %q, want %q", got, want)
+	}
+}
+
+// TestSelectBuilderNestsSubquery confirms a *SelectBuilder passed to
+// From renders as a parenthesized subquery instead of a bare
+// measurement name, while a plain string still passes through
+// unchanged.
+func TestSelectBuilderNestsSubquery(t *testing.T) {
+	inner := Select("mean(value)").From(`"cpu"`).GroupBy("time(1m),host")
+	outer := Select("max(mean)").From(inner).GroupBy("host")
+
+	want := `SELECT max(mean) FROM (SELECT mean(value) FROM "cpu" GROUP BY time(1m),host) GROUP BY host`
+	if got := outer.String(); got != want {
+		t.Fatalf("outer.String() = %q, want %q", got, want)
+	}
+
+	plain := Select("*").From(`"cpu"`).Where("value > 0")
+	if got, want := plain.String(), `SELECT * FROM "cpu" WHERE value > 0`; got != want {
+		t.Fatalf("plain.String() = %q, want %q", got, want)
+	}
+}
+
+// TestSelectBuilderMatchesRegexInFromAndWhere confirms a *regexp.Regexp
+// passed to From renders as an InfluxQL regex literal for matching
+// measurement names, MatchRegex renders one for matching a tag/field's
+// value in a WHERE clause, and both escape a forward slash in the
+// pattern the way InfluxQL requires.
+func TestSelectBuilderMatchesRegexInFromAndWhere(t *testing.T) {
+	measurement := regexp.MustCompile(`^cpu.*`)
+	host := regexp.MustCompile(`web-\d+`)
+
+	q := Select("*").From(measurement).Where(MatchRegex("host", host))
+	want := `SELECT * FROM /^cpu.*/ WHERE "host" =~ /web-\d+/`
+	if got := q.String(); got != want {
+		t.Fatalf("q.String() = %q, want %q", got, want)
+	}
+
+	if got, want := NotMatchRegex("host", host), `"host" !~ /web-\d+/`; got != want {
+		t.Fatalf("NotMatchRegex = %q, want %q", got, want)
+	}
+
+	slashy := regexp.MustCompile(`a/b`)
+	if got, want := MatchRegex("path", slashy), `"path" =~ /a\/b/`; got != want {
+		t.Fatalf("MatchRegex with slash = %q, want %q", got, want)
+	}
+}
+
+// TestQueryTemplateExecuteQuotesPerType confirms Execute substitutes
+// each {{name}} placeholder with a literal quoted for its value's type
+// (a string via QuoteString, a time.Time as an absolute timestamp, a
+// bare number unquoted), and that a missing value fails instead of
+// silently leaving the placeholder in place.
+func TestQueryTemplateExecuteQuotesPerType(t *testing.T) {
+	tmpl, err := CompileQueryTemplate(`SELECT * FROM "cpu" WHERE host = {{host}} AND time > {{since}} AND value > {{min}}`)
+	if err != nil {
+		t.Fatalf("CompileQueryTemplate: %v", err)
+	}
+	if got, want := tmpl.Names(), []string{"host", "since", "min"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Names() = %v, want %v", got, want)
+	}
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cmd, err := tmpl.Execute(map[string]interface{}{
+		"host":  "web-01",
+		"since": since,
+		"min":   42,
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	want := fmt.Sprintf(`SELECT * FROM "cpu" WHERE host = 'web-01' AND time > %s AND value > 42`, timeLiteral(since))
+	if cmd != want {
+		t.Fatalf("Execute = %q, want %q", cmd, want)
+	}
+
+	if _, err := tmpl.Execute(map[string]interface{}{"host": "web-01", "since": since}); err == nil {
+		t.Fatal("Execute with a missing value succeeded, want an error")
+	}
+}
+
+// TestParseExplainPlanSumsCountersAcrossLines confirms parseExplainPlan
+// keeps every line verbatim in Lines and sums the cursors/blocks/
+// decoded-points counters scattered across an EXPLAIN ANALYZE plan's
+// several iterators instead of only reading the first match.
+func TestParseExplainPlanSumsCountersAcrossLines(t *testing.T) {
+	lines := []string{
+		"EXPLAIN ANALYZE",
+		"select",
+		"    cursors_ref: 2",
+		"    blocks_decoded: 3",
+		"    decoded_points: 100",
+		"    cursors_ref: 1",
+		"    blocks_decoded: 1",
+		"    decoded_points: 50",
+	}
+	plan := parseExplainPlan(lines)
+	if len(plan.Lines) != len(lines) {
+		t.Fatalf("Lines = %d entries, want %d", len(plan.Lines), len(lines))
+	}
+	if plan.Cursors != 3 {
+		t.Fatalf("Cursors = %d, want 3", plan.Cursors)
+	}
+	if plan.Blocks != 4 {
+		t.Fatalf("Blocks = %d, want 4", plan.Blocks)
+	}
+	if plan.DecodedPoints != 150 {
+		t.Fatalf("DecodedPoints = %d, want 150", plan.DecodedPoints)
+	}
+}
+
+// TestNormalizeQueryCollapsesWhitespace confirms normalizeQuery treats
+// queries differing only in spacing or line breaks as identical, so
+// they share one cache entry instead of each getting their own.
+func TestNormalizeQueryCollapsesWhitespace(t *testing.T) {
+	got := normalizeQuery("SELECT  *\nFROM \"cpu\"\t WHERE host = 'web-01'")
+	want := `SELECT * FROM "cpu" WHERE host = 'web-01'`
+	if got != want {
+		t.Fatalf("normalizeQuery = %q, want %q", got, want)
+	}
+}
+
+// TestQueryCacheGetSetHonorsTTL confirms a cached entry is served until
+// its ttl elapses and is evicted (forcing a cache miss) afterward.
+func TestQueryCacheGetSetHonorsTTL(t *testing.T) {
+	qc := newQueryCache(10*time.Millisecond, 10)
+	key := cacheKey{db: "mydb", cmd: "SELECT * FROM cpu"}
+	results := []client.Result{{Series: nil}}
+
+	if _, _, ok := qc.get(key); ok {
+		t.Fatal("get on empty cache returned ok=true")
+	}
+
+	qc.set(key, results, nil)
+	got, err, ok := qc.get(key)
+	if !ok || err != nil || !reflect.DeepEqual(got, results) {
+		t.Fatalf("get after set = (%v, %v, %v), want (%v, nil, true)", got, err, ok, results)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, _, ok := qc.get(key); ok {
+		t.Fatal("get after ttl elapsed returned ok=true, want false")
+	}
+}
+
+// TestQueryCacheSetEvictsOldestOverMaxEntries confirms set evicts the
+// oldest entry once maxEntries is exceeded, instead of growing forever.
+func TestQueryCacheSetEvictsOldestOverMaxEntries(t *testing.T) {
+	qc := newQueryCache(time.Minute, 2)
+	keyA := cacheKey{db: "mydb", cmd: "a"}
+	keyB := cacheKey{db: "mydb", cmd: "b"}
+	keyC := cacheKey{db: "mydb", cmd: "c"}
+
+	qc.set(keyA, nil, nil)
+	qc.set(keyB, nil, nil)
+	qc.set(keyC, nil, nil)
+
+	if _, _, ok := qc.get(keyA); ok {
+		t.Fatal("oldest entry survived eviction")
+	}
+	if _, _, ok := qc.get(keyB); !ok {
+		t.Fatal("keyB was evicted, want it to survive")
+	}
+	if _, _, ok := qc.get(keyC); !ok {
+		t.Fatal("keyC was evicted, want it to survive")
+	}
+}
+
+// TestQueryCacheInvalidateScopes confirms invalidate can clear a single
+// entry, every entry for a db, or the whole cache, depending on which
+// of db/cmd are given.
+func TestQueryCacheInvalidateScopes(t *testing.T) {
+	qc := newQueryCache(time.Minute, 10)
+	keyA := cacheKey{db: "db1", cmd: "a"}
+	keyB := cacheKey{db: "db1", cmd: "b"}
+	keyC := cacheKey{db: "db2", cmd: "c"}
+	qc.set(keyA, nil, nil)
+	qc.set(keyB, nil, nil)
+	qc.set(keyC, nil, nil)
+
+	qc.invalidate("db1", "a")
+	if _, _, ok := qc.get(keyA); ok {
+		t.Fatal("keyA survived a single-entry invalidate")
+	}
+	if _, _, ok := qc.get(keyB); !ok {
+		t.Fatal("keyB was cleared by an unrelated single-entry invalidate")
+	}
+
+	qc.invalidate("db1", "")
+	if _, _, ok := qc.get(keyB); ok {
+		t.Fatal("keyB survived a whole-db invalidate")
+	}
+	if _, _, ok := qc.get(keyC); !ok {
+		t.Fatal("keyC was cleared by an unrelated db's invalidate")
+	}
+
+	qc.invalidate("", "")
+	if _, _, ok := qc.get(keyC); ok {
+		t.Fatal("keyC survived a clear-everything invalidate")
+	}
+}
+
+// TestWhereFromStructSkipsZeroAndUntaggedFields confirms
+// WhereFromStruct ANDs together only the non-zero, inf-tagged fields,
+// quoting each per its Go type, and skips untagged and explicitly
+// `inf:"-"` fields entirely regardless of their value.
+func TestWhereFromStructSkipsZeroAndUntaggedFields(t *testing.T) {
+	type filter struct {
+		Host    string `inf:"host"`
+		Region  string `inf:"region"`
+		MinTemp float64
+		Code    int  `inf:"-"`
+		Active  bool `inf:"active"`
+	}
+
+	where, err := WhereFromStruct(filter{Host: "web-01", Code: 7, Active: true})
+	if err != nil {
+		t.Fatalf("WhereFromStruct: %v", err)
+	}
+	want := `"host" = 'web-01' AND "active" = true`
+	if where != want {
+		t.Fatalf("WhereFromStruct = %q, want %q", where, want)
+	}
+
+	where, err = WhereFromStruct(filter{})
+	if err != nil {
+		t.Fatalf("WhereFromStruct: %v", err)
+	}
+	if where != "" {
+		t.Fatalf("WhereFromStruct of a zero-value struct = %q, want \"\"", where)
+	}
+}
+
+// TestTagPredicateInExpandsToParenthesizedOr confirms In expands to an
+// OR chain of equality comparisons, and that nesting it inside And
+// parenthesizes it so AND/OR precedence matches the nesting instead of
+// InfluxQL's own operator precedence.
+func TestTagPredicateInExpandsToParenthesizedOr(t *testing.T) {
+	in := Tag("host").In("a", "b", "c")
+	want := `"host" = 'a' OR "host" = 'b' OR "host" = 'c'`
+	if got := in.String(); got != want {
+		t.Fatalf("In.String() = %q, want %q", got, want)
+	}
+
+	pred := And(Tag("region").Eq("us"), in)
+	want = `"region" = 'us' AND ("host" = 'a' OR "host" = 'b' OR "host" = 'c')`
+	if got := pred.String(); got != want {
+		t.Fatalf("And(...).String() = %q, want %q", got, want)
+	}
+
+	if got, want := Tag("host").In().String(), "false"; got != want {
+		t.Fatalf("In() with no values = %q, want %q", got, want)
+	}
+}
+
+// TestSelectBuilderRendersSLimitAndSOffset confirms SLimit/SOffset
+// append after GROUP BY, and that a zero value (the default) omits
+// its clause instead of rendering "SLIMIT 0"/"SOFFSET 0".
+func TestSelectBuilderRendersSLimitAndSOffset(t *testing.T) {
+	q := Select("*").From(`"cpu"`).GroupBy("host").SLimit(10).SOffset(20)
+	want := `SELECT * FROM "cpu" GROUP BY host SLIMIT 10 SOFFSET 20`
+	if got := q.String(); got != want {
+		t.Fatalf("q.String() = %q, want %q", got, want)
+	}
+
+	q = Select("*").From(`"cpu"`)
+	if got, want := q.String(), `SELECT * FROM "cpu"`; got != want {
+		t.Fatalf("q.String() with no SLimit/SOffset = %q, want %q", got, want)
+	}
+}
+
+// TestValidateRejectsSyntaxErrors confirms Validate accepts
+// well-formed InfluxQL (including a semicolon-joined multi-statement
+// command, the shape QueryBatch builds) and rejects an unbalanced
+// quote.
+func TestValidateRejectsSyntaxErrors(t *testing.T) {
+	if err := Validate(`SELECT * FROM "cpu" WHERE host = 'web-01'`); err != nil {
+		t.Fatalf("Validate of a well-formed query: %v", err)
+	}
+	if err := Validate(`SELECT * FROM cpu; SELECT * FROM mem`); err != nil {
+		t.Fatalf("Validate of a multi-statement query: %v", err)
+	}
+	if err := Validate(`SELECT * FROM "cpu`); err == nil {
+		t.Fatal("Validate of an unbalanced quote succeeded, want an error")
+	}
+}
+
+// TestRelativeExprFormatsSignedOffset confirms RelativeExpr renders a
+// positive duration as "now() - d" and a negative one as "now() + d".
+func TestRelativeExprFormatsSignedOffset(t *testing.T) {
+	if got, want := RelativeExpr(15*time.Minute), "now() - 900000000000ns"; got != want {
+		t.Fatalf("RelativeExpr(15m) = %q, want %q", got, want)
+	}
+	if got, want := RelativeExpr(-15*time.Minute), "now() + 900000000000ns"; got != want {
+		t.Fatalf("RelativeExpr(-15m) = %q, want %q", got, want)
+	}
+}
+
+// TestStartOfRoundsToCalendarDayAcrossZones confirms StartOf(24h, loc)
+// truncates to midnight in loc rather than in UTC, so the same instant
+// rounds to a different predicate depending on the zone requested.
+func TestStartOfRoundsToCalendarDayAcrossZones(t *testing.T) {
+	utc := StartOf(24*time.Hour, time.UTC)
+	est, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no tzdata available: %v", err)
+	}
+	ny := StartOf(24*time.Hour, est)
+	if utc == ny {
+		t.Fatalf("StartOf(24h, UTC) and StartOf(24h, America/New_York) both = %q, want different boundaries", utc)
+	}
+}
+
+// TestValidateRelativeDurationRejectsMalformedInput confirms a
+// well-formed Go duration string parses, while a bare number with no
+// unit (not a valid InfluxQL duration literal either) is rejected
+// instead of silently producing a wrong predicate.
+func TestValidateRelativeDurationRejectsMalformedInput(t *testing.T) {
+	d, err := ValidateRelativeDuration("1.5h")
+	if err != nil {
+		t.Fatalf("ValidateRelativeDuration(\"1.5h\"): %v", err)
+	}
+	if want := 90 * time.Minute; d != want {
+		t.Fatalf("ValidateRelativeDuration(\"1.5h\") = %v, want %v", d, want)
+	}
+
+	if _, err := ValidateRelativeDuration("12"); err == nil {
+		t.Fatal("ValidateRelativeDuration(\"12\") succeeded, want an error for a missing unit")
+	}
+}
+
+// TestChunkSizeForRowWidthScalesInverselyWithWidth confirms wider rows
+// yield smaller chunk sizes (fewer of them fit in the same byte
+// budget), and that the result stays within the hint's clamped range.
+func TestChunkSizeForRowWidthScalesInverselyWithWidth(t *testing.T) {
+	narrow := ChunkSizeForRowWidth(50)
+	wide := ChunkSizeForRowWidth(5000)
+	if narrow <= wide {
+		t.Fatalf("ChunkSizeForRowWidth(50) = %d, want more than ChunkSizeForRowWidth(5000) = %d", narrow, wide)
+	}
+	if narrow > maxChunkSizeHint || wide < minChunkSizeHint {
+		t.Fatalf("ChunkSizeForRowWidth results out of clamped range: narrow=%d wide=%d", narrow, wide)
+	}
+
+	if got := ChunkSizeForRowWidth(0); got != DefaultChunkSize {
+		t.Fatalf("ChunkSizeForRowWidth(0) = %d, want DefaultChunkSize (%d)", got, DefaultChunkSize)
+	}
+}
+
+// TestRPFromQualifiesOnlyTheGivenParts confirms RPFrom renders the
+// full three-part form when db and rp are both given, the two-part
+// form when db is omitted, the blank-middle form when only rp is
+// omitted, and a bare identifier when both are omitted.
+func TestRPFromQualifiesOnlyTheGivenParts(t *testing.T) {
+	cases := []struct {
+		db, rp, measurement string
+		want                string
+	}{
+		{"mydb", "downsample_1h", "cpu", `"mydb"."downsample_1h"."cpu"`},
+		{"", "downsample_1h", "cpu", `"downsample_1h"."cpu"`},
+		{"mydb", "", "cpu", `"mydb".."cpu"`},
+		{"", "", "cpu", `"cpu"`},
+	}
+	for _, c := range cases {
+		if got := RPFrom(c.db, c.rp, c.measurement); got != c.want {
+			t.Errorf("RPFrom(%q, %q, %q) = %q, want %q", c.db, c.rp, c.measurement, got, c.want)
+		}
+	}
+}
+
+// TestReportSlowQueryCountsRowsAcrossSeriesAndStatements confirms
+// reportSlowQuery calls OnSlowQuery with the total row count across
+// every series of every statement result, and that it does nothing
+// below Threshold.
+func TestReportSlowQueryCountsRowsAcrossSeriesAndStatements(t *testing.T) {
+	results := []client.Result{
+		{Series: []models.Row{
+			{Values: [][]interface{}{{1}, {2}}},
+			{Values: [][]interface{}{{3}}},
+		}},
+		{Series: []models.Row{
+			{Values: [][]interface{}{{4}}},
+		}},
+	}
+
+	var gotDB, gotCmd string
+	var gotDuration time.Duration
+	var gotRows int
+	c := &Client{slowQuery: &SlowQueryConfig{
+		Threshold: time.Second,
+		OnSlowQuery: func(db, cmd string, duration time.Duration, rows int) {
+			gotDB, gotCmd, gotDuration, gotRows = db, cmd, duration, rows
+		},
+	}}
+
+	c.reportSlowQuery("mydb", "SELECT * FROM cpu", 500*time.Millisecond, results)
+	if gotCmd != "" {
+		t.Fatalf("reportSlowQuery called OnSlowQuery below Threshold")
+	}
+
+	c.reportSlowQuery("mydb", "SELECT * FROM cpu", 2*time.Second, results)
+	if gotDB != "mydb" || gotCmd != "SELECT * FROM cpu" || gotDuration != 2*time.Second || gotRows != 4 {
+		t.Fatalf("OnSlowQuery(%q, %q, %v, %d), want (%q, %q, %v, %d)",
+			gotDB, gotCmd, gotDuration, gotRows, "mydb", "SELECT * FROM cpu", 2*time.Second, 4)
+	}
+}
+
+// TestReportDryRunRendersLineProtocolPerPoint confirms reportDryRun
+// calls OnDryRun once per point in the batch with that point's
+// rendered line protocol, instead of sending anything.
+func TestReportDryRunRendersLineProtocolPerPoint(t *testing.T) {
+	p1, err := client.NewPoint("cpu", map[string]string{"host": "a"}, map[string]interface{}{"value": 1.0}, time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := client.NewPoint("cpu", map[string]string{"host": "b"}, map[string]interface{}{"value": 2.0}, time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{Database: "mydb", Precision: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bp.AddPoint(p1)
+	bp.AddPoint(p2)
+
+	var gotDB string
+	var lines []string
+	c := &Client{dryRun: &DryRunConfig{
+		Enabled: true,
+		OnDryRun: func(db, line string) {
+			gotDB = db
+			lines = append(lines, line)
+		},
+	}}
+
+	c.reportDryRun(bp)
+	if gotDB != "mydb" {
+		t.Fatalf("OnDryRun db = %q, want %q", gotDB, "mydb")
+	}
+	if len(lines) != 2 {
+		t.Fatalf("OnDryRun called %d times, want 2", len(lines))
+	}
+	if lines[0] != p1.PrecisionString("s") || lines[1] != p2.PrecisionString("s") {
+		t.Fatalf("OnDryRun lines = %v, want rendered line protocol for each point", lines)
+	}
+}
+
+// TestReportDebugMarshalsAndTruncatesResponse confirms reportDebug
+// passes the command and resolved error through unchanged, marshals
+// the response to JSON, truncates it to MaxBodySize, and passes a nil
+// body when there's no response to marshal.
+func TestReportDebugMarshalsAndTruncatesResponse(t *testing.T) {
+	response := &client.Response{
+		Results: []client.Result{
+			{Series: []models.Row{
+				{Columns: []string{"time", "value"}, Values: [][]interface{}{{0, 1}}},
+			}},
+		},
+	}
+	wantBody, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotCmd string
+	var gotBody []byte
+	var gotErr error
+	c := &Client{debugHook: &DebugHookConfig{
+		OnResponse: func(cmd string, body []byte, err error) {
+			gotCmd, gotBody, gotErr = cmd, body, err
+		},
+	}}
+
+	wantErr := fmt.Errorf("boom")
+	c.reportDebug("SELECT * FROM cpu", response, wantErr)
+	if gotCmd != "SELECT * FROM cpu" || gotErr != wantErr {
+		t.Fatalf("reportDebug(cmd, err) = (%q, %v), want (%q, %v)", gotCmd, gotErr, "SELECT * FROM cpu", wantErr)
+	}
+	if string(gotBody) != string(wantBody) {
+		t.Fatalf("reportDebug body = %s, want %s", gotBody, wantBody)
+	}
+
+	c.debugHook.MaxBodySize = 5
+	c.reportDebug("SELECT * FROM cpu", response, nil)
+	if len(gotBody) != 5 {
+		t.Fatalf("reportDebug body len = %d, want 5", len(gotBody))
+	}
+
+	c.reportDebug("SELECT * FROM cpu", nil, wantErr)
+	if gotBody != nil {
+		t.Fatalf("reportDebug body = %v, want nil for a nil response", gotBody)
+	}
+}
+
+// TestRecordWriteResultTracksConsecutiveFailures confirms
+// ConsecutiveFailures increments on each failed recordWriteResult call
+// and resets to 0 on the next successful one.
+func TestRecordWriteResultTracksConsecutiveFailures(t *testing.T) {
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{Database: "mydb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Client{}
+	boom := fmt.Errorf("boom")
+	c.recordWriteResult(bp, boom)
+	c.recordWriteResult(bp, boom)
+	if got := c.Stats().ConsecutiveFailures; got != 2 {
+		t.Fatalf("ConsecutiveFailures = %d, want 2", got)
+	}
+
+	c.recordWriteResult(bp, nil)
+	if got := c.Stats().ConsecutiveFailures; got != 0 {
+		t.Fatalf("ConsecutiveFailures = %d, want 0 after a successful call", got)
+	}
+}
+
+// TestMeasurementSetDedupsAndSorts confirms measurementSet returns
+// the sorted set of distinct measurement names in a batch, for
+// RequestInfo.Measurements.
+func TestMeasurementSetDedupsAndSorts(t *testing.T) {
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{Database: "mydb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"mem", "cpu", "cpu", "disk"} {
+		p, err := client.NewPoint(name, nil, map[string]interface{}{"value": 1}, time.Unix(0, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		bp.AddPoint(p)
+	}
+
+	got := measurementSet(bp)
+	want := []string{"cpu", "disk", "mem"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("measurementSet() = %v, want %v", got, want)
+	}
+}
+
+// TestLabelFromContextRoundTripsWithLabel confirms LabelFromContext
+// returns the label set by WithLabel, and "" when none was set.
+func TestLabelFromContextRoundTripsWithLabel(t *testing.T) {
+	if got := LabelFromContext(context.Background()); got != "" {
+		t.Fatalf("LabelFromContext(no label) = %q, want \"\"", got)
+	}
+
+	ctx := WithLabel(context.Background(), "billing-export")
+	if got := LabelFromContext(ctx); got != "billing-export" {
+		t.Fatalf("LabelFromContext(ctx) = %q, want %q", got, "billing-export")
+	}
+}
+
+// timeoutErr is a minimal net.Error whose Timeout reports true, for
+// exercising IsRetryable's network-timeout branch.
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string   { return "i/o timeout" }
+func (timeoutErr) Timeout() bool   { return true }
+func (timeoutErr) Temporary() bool { return true }
+
+// TestIsRetryableRecognizesTimeoutsBackpressureAnd5xx confirms
+// IsRetryable classifies a network timeout, InfluxDB's "hinted
+// handoff queue full" signal and a 5xx response as retryable, and a
+// 4xx response or plain error as not.
+func TestIsRetryableRecognizesTimeoutsBackpressureAnd5xx(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{timeoutErr{}, true},
+		{errors.New("hinted handoff queue full"), true},
+		{errors.New(`received status code 503 from "http://localhost:8086": internal error`), true},
+		{errors.New(`received status code 400 from "http://localhost:8086": bad request`), false},
+		{errors.New("some other failure"), false},
+	}
+	for _, tc := range cases {
+		if got := IsRetryable(tc.err); got != tc.want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+// TestIsNotFoundMatchesInfluxDBsNotFoundMessage confirms IsNotFound
+// matches InfluxDB's "not found" response used by DropSchema.
+func TestIsNotFoundMatchesInfluxDBsNotFoundMessage(t *testing.T) {
+	if IsNotFound(nil) {
+		t.Fatalf("IsNotFound(nil) = true, want false")
+	}
+	if !IsNotFound(errors.New(`retention policy not found`)) {
+		t.Fatalf("IsNotFound(not found) = false, want true")
+	}
+	if IsNotFound(errors.New("some other failure")) {
+		t.Fatalf("IsNotFound(other) = true, want false")
+	}
+}
+
+// TestIsAuthErrorMatchesStatusCodeAndMessage confirms IsAuthError
+// matches a 401/403 response and InfluxDB's own auth failure
+// messages, but not an unrelated error.
+func TestIsAuthErrorMatchesStatusCodeAndMessage(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New(`received status code 401 from "http://localhost:8086": unauthorized`), true},
+		{errors.New(`received status code 403 from "http://localhost:8086": forbidden`), true},
+		{errors.New("authorization failed"), true},
+		{errors.New("unable to parse authentication credentials"), true},
+		{errors.New(`received status code 500 from "http://localhost:8086": internal error`), false},
+		{errors.New("some other failure"), false},
+	}
+	for _, tc := range cases {
+		if got := IsAuthError(tc.err); got != tc.want {
+			t.Errorf("IsAuthError(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+// TestIsFieldTypeConflictMatchesPartialAndTotalFailures confirms
+// IsFieldTypeConflict matches both a partial-write field type
+// conflict and a bare one with no "partial write:" prefix.
+func TestIsFieldTypeConflictMatchesPartialAndTotalFailures(t *testing.T) {
+	partial := errors.New(`partial write: field type conflict: input field "value" on measurement "cpu" is type integer, already exists as type float dropped=1`)
+	if !IsFieldTypeConflict(partial) {
+		t.Fatalf("IsFieldTypeConflict(partial) = false, want true")
+	}
+
+	total := errors.New(`field type conflict: input field "value" on measurement "cpu" is type integer, already exists as type float`)
+	if !IsFieldTypeConflict(total) {
+		t.Fatalf("IsFieldTypeConflict(total) = false, want true")
+	}
+
+	if IsFieldTypeConflict(errors.New("some other failure")) {
+		t.Fatalf("IsFieldTypeConflict(other) = true, want false")
+	}
+}
+
+// TestAsServerErrorParsesStatusCode confirms AsServerError recovers
+// the status code from client.Client's flat "received status code N"
+// message, the same message responseStatusCode already parses.
+func TestAsServerErrorParsesStatusCode(t *testing.T) {
+	serr, ok := AsServerError(errors.New(`received status code 503 from "http://localhost:8086": internal error`))
+	if !ok || serr.StatusCode != 503 {
+		t.Fatalf("AsServerError = (%v, %v), want (503, true)", serr, ok)
+	}
+
+	if _, ok := AsServerError(errors.New("some other failure")); ok {
+		t.Fatal("AsServerError(other) ok = true, want false")
+	}
+	if _, ok := AsServerError(nil); ok {
+		t.Fatal("AsServerError(nil) ok = true, want false")
+	}
+}
+
+// TestIsNetworkErrorMatchesConnectionFailuresOnly confirms
+// IsNetworkError matches a net.Error but not an application-level
+// InfluxDB error.
+func TestIsNetworkErrorMatchesConnectionFailuresOnly(t *testing.T) {
+	if !IsNetworkError(timeoutErr{}) {
+		t.Fatal("IsNetworkError(timeoutErr) = false, want true")
+	}
+	if IsNetworkError(errors.New("field type conflict")) {
+		t.Fatal("IsNetworkError(application error) = true, want false")
+	}
+	if IsNetworkError(nil) {
+		t.Fatal("IsNetworkError(nil) = true, want false")
+	}
+}
+
+// TestErrNotPointerWrappedByStructValidators confirms ToPoint,
+// ExplodePoints and WhereFromStruct all report ErrNotPointer via
+// errors.Is when given a non-struct.
+func TestErrNotPointerWrappedByStructValidators(t *testing.T) {
+	if _, err := ToPoint(42); !errors.Is(err, ErrNotPointer) {
+		t.Fatalf("ToPoint(42) err = %v, want ErrNotPointer", err)
+	}
+	if _, err := ExplodePoints(42); !errors.Is(err, ErrNotPointer) {
+		t.Fatalf("ExplodePoints(42) err = %v, want ErrNotPointer", err)
+	}
+	if _, err := WhereFromStruct(42); !errors.Is(err, ErrNotPointer) {
+		t.Fatalf("WhereFromStruct(42) err = %v, want ErrNotPointer", err)
+	}
+}
+
+// TestToPointAndParseResultRoundTripBoolField confirms a plain (non-tag)
+// bool field is written as a real InfluxDB boolean field by ToPoint and
+// decoded back to bool by ParseResult, the same round trip numeric and
+// string fields already get.
+func TestToPointAndParseResultRoundTripBoolField(t *testing.T) {
+	type alert struct {
+		Firing bool `inf:"firing,field"`
+	}
+
+	p, err := ToPoint(alert{Firing: true})
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if b, ok := fields["firing"].(bool); !ok || !b {
+		t.Fatalf("fields[%q] = %#v, want bool true", "firing", fields["firing"])
+	}
+
+	row := models.Row{
+		Columns: []string{"firing"},
+		Values:  [][]interface{}{{true}},
+	}
+	var got alert
+	if err := ParseResult(&got, row); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if !got.Firing {
+		t.Fatal("ParseResult: Firing = false, want true")
+	}
+}
+
+// TestParseResultDecodesBoolFromStringsAndNumerics confirms a bool
+// destination accepts not just a native bool value, but also a
+// "true"/"false"-style string and a 0/1 numeric, the spellings a
+// legacy measurement or a raw query result column might carry.
+func TestParseResultDecodesBoolFromStringsAndNumerics(t *testing.T) {
+	type alert struct {
+		Firing bool `inf:"firing"`
+	}
+
+	cases := []interface{}{true, "true", "false", float64(1), float64(0)}
+	want := []bool{true, true, false, true, false}
+
+	for i, val := range cases {
+		row := models.Row{
+			Columns: []string{"firing"},
+			Values:  [][]interface{}{{val}},
+		}
+		var got alert
+		if err := ParseResult(&got, row); err != nil {
+			t.Fatalf("ParseResult(%v): %v", val, err)
+		}
+		if got.Firing != want[i] {
+			t.Fatalf("ParseResult(%v).Firing = %v, want %v", val, got.Firing, want[i])
+		}
+	}
+}
+
+// TestParseResultDecodesBoolSlice confirms a *[]bool destination
+// decodes one bool-like value per row, the same way *[]float64 does.
+func TestParseResultDecodesBoolSlice(t *testing.T) {
+	row := models.Row{
+		Columns: []string{"firing"},
+		Values:  [][]interface{}{{true}, {"false"}},
+	}
+
+	var slice []bool
+	if err := ParseResult(&slice, row); err != nil {
+		t.Fatalf("ParseResult(*[]bool): %v", err)
+	}
+	if len(slice) != 2 || slice[0] != true || slice[1] != false {
+		t.Fatalf("slice = %v, want [true false]", slice)
+	}
+}
+
+// TestParseResultDecodesBoolMap confirms a map[string]bool destination
+// decodes one row's columns into bool-like values keyed by column name.
+func TestParseResultDecodesBoolMap(t *testing.T) {
+	row := models.Row{
+		Columns: []string{"a", "b"},
+		Values:  [][]interface{}{{true, "false"}},
+	}
+
+	m := map[string]bool{}
+	if err := ParseResult(&m, row); err != nil {
+		t.Fatalf("ParseResult(*map[string]bool): %v", err)
+	}
+	if m["a"] != true || m["b"] != false {
+		t.Fatalf("m = %v, want map[a:true b:false]", m)
+	}
+}
+
+// TestExplicitFieldOptionMatchesDefaultClassification confirms
+// `inf:"name,field"` classifies a field exactly like an untagged one
+// does by default, and satisfies SetStrictTagging's role requirement
+// the same way `,tag` does.
+func TestExplicitFieldOptionMatchesDefaultClassification(t *testing.T) {
+	type reading struct {
+		Value float64 `inf:"value,field"`
+	}
+
+	SetStrictTagging(true)
+	defer SetStrictTagging(false)
+
+	p, err := ToPoint(reading{Value: 1.5})
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if fields["value"] != 1.5 {
+		t.Fatalf("fields[%q] = %v, want 1.5", "value", fields["value"])
+	}
+}
+
+// TestToPointReturnsErrNoFieldsForAllTagStruct confirms ToPoint fails
+// with a descriptive *ErrNoFields, not a nil point silently accepted by
+// a caller that forgot to check the error, when a struct has tags but
+// no fields to write.
+func TestToPointReturnsErrNoFieldsForAllTagStruct(t *testing.T) {
+	type allTags struct {
+		Host string `inf:"host,tag"`
+	}
+	p, err := ToPoint(allTags{Host: "a"})
+	if p != nil {
+		t.Fatalf("point = %v, want nil", p)
+	}
+	var noFields *ErrNoFields
+	if !errors.As(err, &noFields) {
+		t.Fatalf("err = %v, want *ErrNoFields", err)
+	}
+}
+
+// TestToPointPropagatesNewPointError confirms ToPoint surfaces
+// client.NewPoint's own validation error (here, a NaN field value)
+// instead of swallowing it, the bug this API originally shipped with.
+func TestToPointPropagatesNewPointError(t *testing.T) {
+	type reading struct {
+		Value float64 `inf:"value"`
+	}
+	p, err := ToPoint(reading{Value: math.NaN()})
+	if err == nil {
+		t.Fatal("ToPoint with a NaN field should have failed")
+	}
+	if p != nil {
+		t.Fatalf("point = %v, want nil", p)
+	}
+	if !strings.Contains(err.Error(), "NaN") {
+		t.Fatalf("err = %v, want it to mention NaN", err)
+	}
+}
+
+// TestToPointReturnsMapsToPool confirms ToPoint draws its tags/fields
+// maps from the shared pool Batch.NewPoint uses and returns them
+// afterward, rather than allocating a fresh pair every call.
+func TestToPointReturnsMapsToPool(t *testing.T) {
+	type reading struct {
+		Host  string  `inf:"host,tag"`
+		Value float64 `inf:"value"`
+	}
+	if _, err := ToPoint(reading{Host: "a", Value: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	tags := tagsPool.Get().(map[string]string)
+	if len(tags) != 0 {
+		t.Fatalf("tagsPool returned a dirty map: %v", tags)
+	}
+	tagsPool.Put(tags)
+
+	fields := fieldsPool.Get().(map[string]interface{})
+	if len(fields) != 0 {
+		t.Fatalf("fieldsPool returned a dirty map: %v", fields)
+	}
+	fieldsPool.Put(fields)
+}
+
+// fixedClock is a Clock stuck at a single instant, for
+// TestSetClockControlsToPointDefaultTimestamp.
+type fixedClock struct{ t time.Time }
+
+func (f fixedClock) Now() time.Time                         { return f.t }
+func (f fixedClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (f fixedClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (f fixedClock) NewTicker(d time.Duration) Ticker       { return realClock{}.NewTicker(d) }
+
+// TestSetClockControlsToPointDefaultTimestamp confirms ToPoint's
+// fallback timestamp (no Time field, no ToPointAt override) comes
+// from the active Clock instead of the real wall clock.
+func TestSetClockControlsToPointDefaultTimestamp(t *testing.T) {
+	want := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	SetClock(fixedClock{t: want})
+	defer SetClock(nil)
+
+	type metric struct {
+		Value float64
+	}
+	p, err := ToPoint(metric{Value: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Time().Equal(want) {
+		t.Fatalf("ToPoint's timestamp = %v, want %v", p.Time(), want)
+	}
+}
+
+// TestSetStructTagKeyUsesCustomTag confirms ToPoint and ParseResult
+// read field names and options from the tag key SetStructTagKey names
+// instead of "inf".
+func TestSetStructTagKeyUsesCustomTag(t *testing.T) {
+	SetStructTagKey("db")
+	defer SetStructTagKey("")
+
+	type metric struct {
+		Host  string  `db:"host,tag"`
+		Value float64 `db:"value,field"`
+	}
+
+	p, err := ToPoint(metric{Host: "a", Value: 1.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags := p.Tags(); tags["host"] != "a" {
+		t.Fatalf("Tags() = %v, want host=a", tags)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["value"] != 1.5 {
+		t.Fatalf("Fields() = %v, want value=1.5", fields)
+	}
+
+	row := models.Row{
+		Columns: []string{"value"},
+		Tags:    map[string]string{"host": "a"},
+		Values:  [][]interface{}{{float64(1.5)}},
+	}
+	var got metric
+	if err := ParseResult(&got, row); err != nil {
+		t.Fatal(err)
+	}
+	if got.Host != "a" || got.Value != 1.5 {
+		t.Fatalf("ParseResult = %+v, want {Host:a Value:1.5}", got)
+	}
+}
+
+// TestVerbatimNamingKeepsGoNames confirms SetNamingStrategy(VerbatimNaming{})
+// maps an untagged CamelCase field (and measurement) to its Go name
+// as-is, instead of ToPoint's default titleToSnake conversion.
+func TestVerbatimNamingKeepsGoNames(t *testing.T) {
+	SetNamingStrategy(VerbatimNaming{})
+	defer SetNamingStrategy(nil)
+
+	type CamelMetric struct {
+		RequestCount float64
+	}
+
+	p, err := ToPoint(CamelMetric{RequestCount: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name() != "CamelMetric" {
+		t.Fatalf("Name() = %q, want %q", p.Name(), "CamelMetric")
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["RequestCount"] != float64(3) {
+		t.Fatalf("Fields() = %v, want RequestCount=3", fields)
+	}
+}
+
+// TestCamelCaseNamingConvertsGoNames confirms
+// SetNamingStrategy(CamelCaseNaming{}) maps an untagged field (and
+// measurement) to lowerCamelCase instead of ToPoint's default
+// titleToSnake conversion.
+func TestCamelCaseNamingConvertsGoNames(t *testing.T) {
+	SetNamingStrategy(CamelCaseNaming{})
+	defer SetNamingStrategy(nil)
+
+	type HostMetric struct {
+		RequestCount float64
+	}
+
+	p, err := ToPoint(HostMetric{RequestCount: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name() != "hostMetric" {
+		t.Fatalf("Name() = %q, want %q", p.Name(), "hostMetric")
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["requestCount"] != float64(3) {
+		t.Fatalf("Fields() = %v, want requestCount=3", fields)
+	}
+}
+
+// TestKebabCaseNamingConvertsGoNames confirms
+// SetNamingStrategy(KebabCaseNaming{}) maps an untagged field (and
+// measurement) to kebab-case.
+func TestKebabCaseNamingConvertsGoNames(t *testing.T) {
+	SetNamingStrategy(KebabCaseNaming{})
+	defer SetNamingStrategy(nil)
+
+	type HostMetric struct {
+		RequestCount float64
+	}
+
+	p, err := ToPoint(HostMetric{RequestCount: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name() != "host-metric" {
+		t.Fatalf("Name() = %q, want %q", p.Name(), "host-metric")
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["request-count"] != float64(3) {
+		t.Fatalf("Fields() = %v, want request-count=3", fields)
+	}
+}
+
+// TestNamingStrategyFuncAdaptsAPlainFunc confirms NamingStrategyFunc
+// lets SetNamingStrategy take a plain func without a named type.
+func TestNamingStrategyFuncAdaptsAPlainFunc(t *testing.T) {
+	SetNamingStrategy(NamingStrategyFunc(func(goName string) string {
+		return strings.ToUpper(goName)
+	}))
+	defer SetNamingStrategy(nil)
+
+	type metric struct {
+		Value float64
+	}
+
+	p, err := ToPoint(metric{Value: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name() != "METRIC" {
+		t.Fatalf("Name() = %q, want %q", p.Name(), "METRIC")
+	}
+}
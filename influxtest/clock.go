@@ -0,0 +1,118 @@
+package influxtest
+
+import (
+	"sync"
+	"time"
+
+	influx "github.com/eachain/influx"
+)
+
+// FakeClock is an influx.Clock a test fully controls via Advance: time
+// only moves, and a Sleep/After/NewTicker channel only fires, when the
+// test says so, letting a flush-interval or retry-backoff code path be
+// driven deterministically instead of sleeping through the real
+// duration.
+//
+//	clock := influxtest.NewFakeClock(time.Unix(0, 0))
+//	influx.SetClock(clock)
+//	defer influx.SetClock(nil)
+//	...
+//	clock.Advance(time.Second) // fires anything due by the new time
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFakeClock returns a FakeClock starting at t.
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{now: t}
+}
+
+// Now implements influx.Clock.
+func (f *FakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// After implements influx.Clock: the returned channel fires the first
+// time Advance reaches or passes now+d.
+func (f *FakeClock) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t := &fakeTimer{mu: &f.mu, next: f.now.Add(d), c: make(chan time.Time, 1)}
+	f.timers = append(f.timers, t)
+	return t.c
+}
+
+// Sleep implements influx.Clock, blocking until Advance reaches or
+// passes now+d.
+func (f *FakeClock) Sleep(d time.Duration) {
+	<-f.After(d)
+}
+
+// NewTicker implements influx.Clock: the returned Ticker's channel
+// fires every time Advance reaches or passes the next of now+d,
+// now+2d, now+3d, ....
+func (f *FakeClock) NewTicker(d time.Duration) influx.Ticker {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t := &fakeTimer{mu: &f.mu, next: f.now.Add(d), interval: d, c: make(chan time.Time, 1)}
+	f.timers = append(f.timers, t)
+	return t
+}
+
+// Advance moves the clock forward by d, firing every pending
+// After/Sleep and due ticker in the same call, in no particular order.
+// A repeating ticker whose receiver hasn't drained its previous tick
+// is skipped rather than queued, the same drop-on-slow-receiver
+// behavior a real *time.Ticker has, and moves on to the first tick of
+// its original schedule after the new current time rather than
+// replaying every interval a large d skipped over.
+func (f *FakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	for _, t := range f.timers {
+		if t.stopped || t.next.After(f.now) {
+			continue
+		}
+		select {
+		case t.c <- f.now:
+		default:
+		}
+		if t.interval <= 0 {
+			t.stopped = true
+			continue
+		}
+		next := t.next.Add(t.interval)
+		for !next.After(f.now) {
+			next = next.Add(t.interval)
+		}
+		t.next = next
+	}
+}
+
+// fakeTimer backs both a one-shot After/Sleep wait (interval == 0) and
+// a repeating NewTicker, so FakeClock.Advance can treat both the same
+// way. mu is the owning FakeClock's mutex, guarding stopped/next
+// against a concurrent Advance since Stop can be called from any
+// goroutine independently of it.
+type fakeTimer struct {
+	mu       *sync.Mutex
+	next     time.Time
+	interval time.Duration
+	c        chan time.Time
+	stopped  bool
+}
+
+// C implements influx.Ticker.
+func (t *fakeTimer) C() <-chan time.Time { return t.c }
+
+// Stop implements influx.Ticker.
+func (t *fakeTimer) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = true
+}
@@ -0,0 +1,75 @@
+package influxtest
+
+import (
+	"testing"
+	"time"
+)
+
+// TestFakeClockNow confirms Now only changes on Advance.
+func TestFakeClockNow(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := NewFakeClock(start)
+	if !clock.Now().Equal(start) {
+		t.Fatalf("Now() = %v, want %v", clock.Now(), start)
+	}
+	clock.Advance(time.Second)
+	if want := start.Add(time.Second); !clock.Now().Equal(want) {
+		t.Fatalf("Now() = %v, want %v", clock.Now(), want)
+	}
+}
+
+// TestFakeClockAfterFiresOnAdvance confirms After's channel only
+// fires once Advance reaches its deadline, not before.
+func TestFakeClockAfterFiresOnAdvance(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	c := clock.After(time.Second)
+
+	select {
+	case <-c:
+		t.Fatal("After fired before Advance")
+	default:
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	select {
+	case <-c:
+		t.Fatal("After fired before its deadline")
+	default:
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	select {
+	case <-c:
+	default:
+		t.Fatal("After did not fire once its deadline passed")
+	}
+}
+
+// TestFakeClockTickerFiresRepeatedlyAndStops confirms NewTicker fires
+// once per Advance past its interval and stops firing after Stop.
+func TestFakeClockTickerFiresRepeatedlyAndStops(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	ticker := clock.NewTicker(time.Second)
+
+	clock.Advance(time.Second)
+	select {
+	case <-ticker.C():
+	default:
+		t.Fatal("ticker did not fire after one interval")
+	}
+
+	clock.Advance(time.Second)
+	select {
+	case <-ticker.C():
+	default:
+		t.Fatal("ticker did not fire after a second interval")
+	}
+
+	ticker.Stop()
+	clock.Advance(time.Second)
+	select {
+	case <-ticker.C():
+		t.Fatal("ticker fired after Stop")
+	default:
+	}
+}
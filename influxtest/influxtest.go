@@ -0,0 +1,191 @@
+// Package influxtest provides Mock, a client.Client implementation
+// for unit-testing business logic built on github.com/eachain/influx
+// without a running InfluxDB:
+//
+//	mock := &influxtest.Mock{}
+//	c := influx.NewWithClient(mock)
+//	c.DB = "mydb"
+//
+//	mock.ScriptQuery(&client.Response{Results: []client.Result{...}}, nil)
+//	results, err := c.Query("mydb", "SELECT * FROM cpu")
+//	...
+//
+//	c.Insert("mydb", point)
+//	if len(mock.Points()) != 1 {
+//		t.Fatal("expected one point written")
+//	}
+package influxtest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/models"
+)
+
+// Mock is a client.Client implementation that captures every write
+// and answers queries from a scripted response queue or QueryFunc,
+// for injecting into a Client via influx.NewWithClient. It is safe
+// for concurrent use.
+type Mock struct {
+	mu sync.Mutex
+
+	// QueryFunc, if set, answers a Query/QueryCtx call once its
+	// scripted response queue (see ScriptQuery) is empty. A zero Mock
+	// with no QueryFunc answers every such call with an empty,
+	// error-free response.
+	QueryFunc func(q client.Query) (*client.Response, error)
+
+	// PingFunc, if set, answers every Ping call. A zero Mock reports
+	// healthy with no error.
+	PingFunc func(timeout time.Duration) (time.Duration, string, error)
+
+	scripted []scriptedResponse
+	queries  []client.Query
+	writes   []client.BatchPoints
+	closed   bool
+}
+
+type scriptedResponse struct {
+	resp *client.Response
+	err  error
+}
+
+// ScriptQuery enqueues resp/err as the answer to the next
+// Query/QueryCtx call not already answered by an earlier still-queued
+// ScriptQuery call, consumed oldest-first, so a test can drive a
+// sequence of responses across several calls without QueryFunc having
+// to track state itself.
+func (m *Mock) ScriptQuery(resp *client.Response, err error) {
+	m.mu.Lock()
+	m.scripted = append(m.scripted, scriptedResponse{resp, err})
+	m.mu.Unlock()
+}
+
+// ScriptRows enqueues an error-free response carrying rows as the
+// answer to the next Query/QueryCtx call, so a test can script a
+// result set with ParseResult/ParseRows-style rows in hand instead of
+// wrapping it in a client.Response and client.Result by hand.
+func (m *Mock) ScriptRows(rows ...models.Row) {
+	m.ScriptQuery(&client.Response{Results: []client.Result{{Series: rows}}}, nil)
+}
+
+// Write implements client.Client.
+func (m *Mock) Write(bp client.BatchPoints) error {
+	return m.WriteCtx(context.Background(), bp)
+}
+
+// WriteCtx implements client.Client, capturing bp for Writes/Points.
+func (m *Mock) WriteCtx(ctx context.Context, bp client.BatchPoints) error {
+	m.mu.Lock()
+	m.writes = append(m.writes, bp)
+	m.mu.Unlock()
+	return nil
+}
+
+// Query implements client.Client.
+func (m *Mock) Query(q client.Query) (*client.Response, error) {
+	return m.QueryCtx(context.Background(), q)
+}
+
+// QueryCtx implements client.Client, answering from the scripted
+// response queue, then QueryFunc, then an empty response.
+func (m *Mock) QueryCtx(ctx context.Context, q client.Query) (*client.Response, error) {
+	m.mu.Lock()
+	m.queries = append(m.queries, q)
+	var next *scriptedResponse
+	if len(m.scripted) > 0 {
+		s := m.scripted[0]
+		m.scripted = m.scripted[1:]
+		next = &s
+	}
+	queryFunc := m.QueryFunc
+	m.mu.Unlock()
+
+	if next != nil {
+		return next.resp, next.err
+	}
+	if queryFunc != nil {
+		return queryFunc(q)
+	}
+	return &client.Response{}, nil
+}
+
+// QueryAsChunk implements client.Client. Mock has no chunked-response
+// scripting, so it always fails; QueryChunked/QueryChunkedContext
+// cannot be unit-tested against Mock.
+func (m *Mock) QueryAsChunk(q client.Query) (*client.ChunkedResponse, error) {
+	return nil, errors.New("influxtest: Mock does not support QueryAsChunk")
+}
+
+// Ping implements client.Client.
+func (m *Mock) Ping(timeout time.Duration) (time.Duration, string, error) {
+	m.mu.Lock()
+	pingFunc := m.PingFunc
+	m.mu.Unlock()
+
+	if pingFunc != nil {
+		return pingFunc(timeout)
+	}
+	return 0, "", nil
+}
+
+// Close implements client.Client.
+func (m *Mock) Close() error {
+	m.mu.Lock()
+	m.closed = true
+	m.mu.Unlock()
+	return nil
+}
+
+// Closed reports whether Close has been called.
+func (m *Mock) Closed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
+// Writes returns every BatchPoints passed to Write/WriteCtx so far, in
+// call order.
+func (m *Mock) Writes() []client.BatchPoints {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := make([]client.BatchPoints, len(m.writes))
+	copy(cp, m.writes)
+	return cp
+}
+
+// Points flattens every point across every captured batch, in write
+// order, for assertions that don't care about batch boundaries.
+func (m *Mock) Points() []*client.Point {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var points []*client.Point
+	for _, bp := range m.writes {
+		points = append(points, bp.Points()...)
+	}
+	return points
+}
+
+// Queries returns every Query passed to Query/QueryCtx so far, in call
+// order.
+func (m *Mock) Queries() []client.Query {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := make([]client.Query, len(m.queries))
+	copy(cp, m.queries)
+	return cp
+}
+
+// Reset clears every captured write and query, and any still-queued
+// scripted response, for reuse across subtests.
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	m.writes = nil
+	m.queries = nil
+	m.scripted = nil
+	m.mu.Unlock()
+}
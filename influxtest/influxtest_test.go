@@ -0,0 +1,131 @@
+package influxtest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/models"
+)
+
+// TestMockCapturesWrites confirms Write/WriteCtx capture every point
+// across every batch, in order, via Writes and Points.
+func TestMockCapturesWrites(t *testing.T) {
+	m := &Mock{}
+
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{Database: "mydb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := client.NewPoint("cpu", nil, map[string]interface{}{"value": 1}, time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bp.AddPoint(p)
+
+	if err := m.Write(bp); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Writes()) != 1 {
+		t.Fatalf("Writes() = %d, want 1", len(m.Writes()))
+	}
+	if len(m.Points()) != 1 {
+		t.Fatalf("Points() = %d, want 1", len(m.Points()))
+	}
+}
+
+// TestMockScriptQueryConsumesOldestFirst confirms ScriptQuery answers
+// are consumed in the order they were scripted, falling back to
+// QueryFunc once the queue is empty.
+func TestMockScriptQueryConsumesOldestFirst(t *testing.T) {
+	m := &Mock{}
+	first := &client.Response{Results: []client.Result{{}}}
+	second := &client.Response{Err: "boom"}
+	m.ScriptQuery(first, nil)
+	m.ScriptQuery(second, nil)
+
+	got, err := m.Query(client.Query{Command: "SELECT 1"})
+	if err != nil || got != first {
+		t.Fatalf("Query() #1 = (%v, %v), want (%v, nil)", got, err, first)
+	}
+	got, err = m.Query(client.Query{Command: "SELECT 2"})
+	if err != nil || got != second {
+		t.Fatalf("Query() #2 = (%v, %v), want (%v, nil)", got, err, second)
+	}
+
+	m.QueryFunc = func(q client.Query) (*client.Response, error) {
+		return &client.Response{Err: q.Command}, nil
+	}
+	got, err = m.Query(client.Query{Command: "SELECT 3"})
+	if err != nil || got.Err != "SELECT 3" {
+		t.Fatalf("Query() #3 = (%v, %v), want Err %q", got, err, "SELECT 3")
+	}
+
+	if len(m.Queries()) != 3 {
+		t.Fatalf("Queries() = %d, want 3", len(m.Queries()))
+	}
+}
+
+// TestMockScriptRowsAnswersQuery confirms ScriptRows scripts an
+// error-free response carrying the given rows.
+func TestMockScriptRowsAnswersQuery(t *testing.T) {
+	m := &Mock{}
+	m.ScriptRows(models.Row{
+		Columns: []string{"time", "value"},
+		Values:  [][]interface{}{{"2024-01-01T00:00:00Z", 1.0}},
+	})
+
+	got, err := m.Query(client.Query{Command: "SELECT value FROM cpu"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Results) != 1 || len(got.Results[0].Series) != 1 {
+		t.Fatalf("Results = %+v, want one result with one series", got.Results)
+	}
+	if got.Results[0].Series[0].Values[0][1] != 1.0 {
+		t.Fatalf("value = %v, want 1.0", got.Results[0].Series[0].Values[0][1])
+	}
+}
+
+// TestMockPingDefaultsHealthy confirms a zero Mock's Ping reports
+// healthy, and PingFunc overrides it.
+func TestMockPingDefaultsHealthy(t *testing.T) {
+	m := &Mock{}
+	if _, _, err := m.Ping(0); err != nil {
+		t.Fatalf("Ping() err = %v, want nil", err)
+	}
+
+	wantErr := errors.New("down")
+	m.PingFunc = func(timeout time.Duration) (time.Duration, string, error) {
+		return 0, "", wantErr
+	}
+	if _, _, err := m.Ping(0); err != wantErr {
+		t.Fatalf("Ping() err = %v, want %v", err, wantErr)
+	}
+}
+
+// TestMockCloseAndReset confirms Close sets Closed, and Reset clears
+// captured writes, queries and any queued scripted response.
+func TestMockCloseAndReset(t *testing.T) {
+	m := &Mock{}
+	m.ScriptQuery(&client.Response{}, nil)
+	m.Query(client.Query{})
+	bp, _ := client.NewBatchPoints(client.BatchPointsConfig{Database: "mydb"})
+	m.Write(bp)
+
+	if m.Closed() {
+		t.Fatalf("Closed() = true before Close")
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !m.Closed() {
+		t.Fatalf("Closed() = false after Close")
+	}
+
+	m.Reset()
+	if len(m.Queries()) != 0 || len(m.Writes()) != 0 {
+		t.Fatalf("Reset() did not clear captured state")
+	}
+}
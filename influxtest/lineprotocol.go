@@ -0,0 +1,54 @@
+package influxtest
+
+import (
+	"regexp"
+	"testing"
+
+	influx "github.com/eachain/influx"
+)
+
+// LineProtocol renders structure via influx.ToPoint and returns its
+// line-protocol form, tags in the stable sorted order
+// client.Point.String always uses regardless of the struct's own
+// field order or an intervening map, so two calls for the same data
+// never differ only by tag order.
+func LineProtocol(structure interface{}, opts ...influx.FieldOption) (string, error) {
+	p, err := influx.ToPoint(structure, opts...)
+	if err != nil {
+		return "", err
+	}
+	return p.String(), nil
+}
+
+// trailingTimestamp matches the whitespace-separated epoch-nanosecond
+// timestamp client.Point.String appends, so AssertLineProtocol can
+// strip it from a rendered line before comparing against a want that
+// doesn't specify one.
+var trailingTimestamp = regexp.MustCompile(`\s+\d+$`)
+
+// AssertLineProtocol renders structure via LineProtocol and fails t
+// with both lines if they don't match, so a mapping regression (a
+// renamed tag, a dropped field, a tag/field mixed up) shows up as a
+// readable failure instead of a decode error three calls later.
+//
+// structure's point normally carries whatever timestamp ToPoint
+// assigned it (time.Now(), absent a Time field), which want can't
+// know in advance; when want has no trailing timestamp of its own,
+// AssertLineProtocol strips the rendered one before comparing, so a
+// caller only needs to spell out the measurement/tags/fields it
+// cares about. Give structure a fixed Time field (or opts that pin
+// one) and a matching timestamp in want to assert on it too.
+func AssertLineProtocol(t testing.TB, structure interface{}, want string, opts ...influx.FieldOption) {
+	t.Helper()
+
+	got, err := LineProtocol(structure, opts...)
+	if err != nil {
+		t.Fatalf("influxtest: LineProtocol: %v", err)
+	}
+	if !trailingTimestamp.MatchString(want) {
+		got = trailingTimestamp.ReplaceAllString(got, "")
+	}
+	if got != want {
+		t.Fatalf("line protocol mismatch:\n  got:  %s\n  want: %s", got, want)
+	}
+}
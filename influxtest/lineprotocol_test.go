@@ -0,0 +1,54 @@
+package influxtest
+
+import "testing"
+
+type lineProtocolMetric struct {
+	Host  string  `inf:"host,tag"`
+	Value float64 `inf:"value"`
+}
+
+// TestLineProtocolSortsTagsRegardlessOfFieldOrder confirms
+// LineProtocol's tags always render sorted, independent of the
+// struct's own field order.
+func TestLineProtocolSortsTagsRegardlessOfFieldOrder(t *testing.T) {
+	type metric struct {
+		Region string  `inf:"region,tag"`
+		Host   string  `inf:"host,tag"`
+		Value  float64 `inf:"value"`
+	}
+
+	line, err := LineProtocol(metric{Region: "us", Host: "a", Value: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "metric,host=a,region=us value=1"
+	if !trailingTimestamp.MatchString(line) {
+		t.Fatalf("LineProtocol() = %q, want a trailing timestamp", line)
+	}
+	if got := trailingTimestamp.ReplaceAllString(line, ""); got != want {
+		t.Fatalf("LineProtocol() = %q, want %q (plus timestamp)", got, want)
+	}
+}
+
+// TestAssertLineProtocolPassesWithoutWantTimestamp confirms
+// AssertLineProtocol strips the rendered timestamp before comparing
+// when want doesn't specify one.
+func TestAssertLineProtocolPassesWithoutWantTimestamp(t *testing.T) {
+	AssertLineProtocol(t, lineProtocolMetric{Host: "a", Value: 1}, "line_protocol_metric,host=a value=1")
+}
+
+// TestAssertLineProtocolFailsOnMismatch confirms a genuine mapping
+// regression fails the inner *testing.T.
+func TestAssertLineProtocolFailsOnMismatch(t *testing.T) {
+	inner := &testing.T{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		AssertLineProtocol(inner, lineProtocolMetric{Host: "a", Value: 1}, "line_protocol_metric,host=b value=1")
+	}()
+	<-done
+	if !inner.Failed() {
+		t.Fatal("AssertLineProtocol did not fail on a mismatched line")
+	}
+}
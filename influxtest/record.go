@@ -0,0 +1,115 @@
+package influxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// goldenQuery is one recorded query/response pair, the golden-file
+// format RecordTo writes and Replay reads back.
+type goldenQuery struct {
+	Command string          `json:"command"`
+	Body    json.RawMessage `json:"body,omitempty"`
+	Err     string          `json:"err,omitempty"`
+}
+
+// RecordTo returns a func matching influx.DebugHookConfig.OnResponse's
+// signature that writes every query's command and response body to
+// its own golden file under dir, named after a monotonic sequence
+// number so Replay can restore recording order for a command issued
+// more than once. It's meant to be wired in just long enough to
+// capture a real server's response shapes once:
+//
+//	rec, err := influxtest.RecordTo("testdata/golden")
+//	...
+//	c.SetDebugHook(influx.DebugHookConfig{OnResponse: rec})
+//
+// Commit the resulting directory and load it back with Replay in
+// tests that no longer need a running InfluxDB.
+//
+// body is re-marshaled JSON, not the original response bytes, and may
+// be truncated per influx.DebugHookConfig.MaxBodySize; raise
+// MaxBodySize past the size of your largest real response before
+// recording, or Replay will fail to decode the truncated ones.
+func RecordTo(dir string) (func(cmd string, body []byte, err error), error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	seq := 0
+	return func(cmd string, body []byte, err error) {
+		seq++
+		q := goldenQuery{Command: cmd, Body: json.RawMessage(body)}
+		if err != nil {
+			q.Err = err.Error()
+		}
+		data, merr := json.MarshalIndent(q, "", "  ")
+		if merr != nil {
+			return
+		}
+		name := fmt.Sprintf("%06d.json", seq)
+		os.WriteFile(filepath.Join(dir, name), data, 0o644)
+	}, nil
+}
+
+// Replay loads every golden file RecordTo wrote to dir and returns a
+// Mock that answers Query/QueryCtx by matching the incoming command
+// against the recorded ones, replaying repeats of the same command in
+// the order they were captured, so business logic's decoding can be
+// tested deterministically against real production response shapes
+// without a running InfluxDB.
+func Replay(dir string) (*Mock, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// os.ReadDir sorts by filename, and RecordTo's zero-padded
+	// sequence numbers sort the same way they were recorded.
+	byCommand := map[string][]goldenQuery{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var q goldenQuery
+		if err := json.Unmarshal(data, &q); err != nil {
+			return nil, fmt.Errorf("influxtest: %s: %w", entry.Name(), err)
+		}
+		byCommand[q.Command] = append(byCommand[q.Command], q)
+	}
+
+	m := &Mock{}
+	m.QueryFunc = func(q client.Query) (*client.Response, error) {
+		queue := byCommand[q.Command]
+		if len(queue) == 0 {
+			return nil, fmt.Errorf("influxtest: no recorded response for %q", q.Command)
+		}
+		next := queue[0]
+		byCommand[q.Command] = queue[1:]
+
+		var response client.Response
+		if len(next.Body) > 0 {
+			dec := json.NewDecoder(bytes.NewReader(next.Body))
+			dec.UseNumber()
+			if err := dec.Decode(&response); err != nil {
+				return nil, fmt.Errorf("influxtest: decoding recorded response for %q: %w", q.Command, err)
+			}
+		}
+		if next.Err != "" {
+			return &response, errors.New(next.Err)
+		}
+		return &response, nil
+	}
+	return m, nil
+}
@@ -0,0 +1,55 @@
+package influxtest
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// TestRecordToThenReplay confirms a response recorded via RecordTo's
+// OnResponse func can be read back by Replay and served for the same
+// command, repeats included, in recording order.
+func TestRecordToThenReplay(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "golden")
+	onResponse, err := RecordTo(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	onResponse("SELECT * FROM cpu", []byte(`{"results":[{"series":[{"name":"cpu","columns":["time","value"],"values":[[0,1]]}]}]}`), nil)
+	onResponse("SELECT * FROM cpu", []byte(`{"results":[{"series":[{"name":"cpu","columns":["time","value"],"values":[[1,2]]}]}]}`), nil)
+	onResponse("SELECT * FROM mem", nil, errors.New("measurement not found"))
+
+	mock, err := Replay(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := mock.Query(client.Query{Command: "SELECT * FROM cpu"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := got.Results[0].Series[0].Values[0][1].(json.Number).String(); v != "1" {
+		t.Fatalf("first replay value = %v, want 1", v)
+	}
+
+	got, err = mock.Query(client.Query{Command: "SELECT * FROM cpu"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := got.Results[0].Series[0].Values[0][1].(json.Number).String(); v != "2" {
+		t.Fatalf("second replay value = %v, want 2", v)
+	}
+
+	_, err = mock.Query(client.Query{Command: "SELECT * FROM mem"})
+	if err == nil || err.Error() != "measurement not found" {
+		t.Fatalf("replayed err = %v, want %q", err, "measurement not found")
+	}
+
+	if _, err := mock.Query(client.Query{Command: "SELECT * FROM disk"}); err == nil {
+		t.Fatal("expected an error for a command never recorded")
+	}
+}
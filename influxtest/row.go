@@ -0,0 +1,123 @@
+package influxtest
+
+import (
+	"sort"
+	"time"
+
+	influx "github.com/eachain/influx"
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/models"
+)
+
+// NewRow builds a models.Row from literal columns/values/tags, the
+// shape ParseResult/ParseResultCount decode, for a test that would
+// otherwise hand-write the Columns/Values/Tags slices directly.
+// columns need not include "time"; pass it like any other column
+// when the fixture needs one.
+func NewRow(name string, tags map[string]string, columns []string, values ...[]interface{}) models.Row {
+	return models.Row{
+		Name:    name,
+		Tags:    tags,
+		Columns: columns,
+		Values:  values,
+	}
+}
+
+// NewResult wraps series as a client.Result, the shape a
+// Client.Query response decodes.
+func NewResult(series ...models.Row) client.Result {
+	return client.Result{Series: series}
+}
+
+// RowsFromPoints is the inverse of ToPoints/ExplodePoints: it groups
+// points into series by name and tag set, the same grouping a real
+// query result is returned in, and renders each series as a
+// models.Row — "time" followed by every field name seen across the
+// series' points, sorted — so a test can build its expected rows the
+// same way it builds what it writes, through ToPoint, instead of
+// hand-writing Columns/Values.
+//
+// time is rendered as RFC3339Nano, what a Client.Query response
+// carries unless Client.Precision/QueryWithEpoch asks for an epoch
+// instead; ParseResult accepts an RFC3339 string regardless of the
+// caller's own precision setting, so it's the simplest format usable
+// here without threading precision through.
+func RowsFromPoints(points ...*client.Point) ([]models.Row, error) {
+	type series struct {
+		row    models.Row
+		fields map[string]bool
+	}
+
+	var order []string
+	byKey := map[string]*series{}
+	for _, p := range points {
+		fields, err := p.Fields()
+		if err != nil {
+			return nil, err
+		}
+		key := seriesKey(p.Name(), p.Tags())
+		s, ok := byKey[key]
+		if !ok {
+			s = &series{row: models.Row{Name: p.Name(), Tags: p.Tags()}, fields: map[string]bool{}}
+			byKey[key] = s
+			order = append(order, key)
+		}
+		for name := range fields {
+			s.fields[name] = true
+		}
+	}
+
+	for _, key := range order {
+		s := byKey[key]
+		names := make([]string, 0, len(s.fields))
+		for name := range s.fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		s.row.Columns = append([]string{"time"}, names...)
+	}
+
+	for _, p := range points {
+		fields, _ := p.Fields()
+		s := byKey[seriesKey(p.Name(), p.Tags())]
+		values := make([]interface{}, len(s.row.Columns))
+		values[0] = p.Time().Format(time.RFC3339Nano)
+		for i, name := range s.row.Columns[1:] {
+			values[i+1] = fields[name]
+		}
+		s.row.Values = append(s.row.Values, values)
+	}
+
+	rows := make([]models.Row, 0, len(order))
+	for _, key := range order {
+		rows = append(rows, byKey[key].row)
+	}
+	return rows, nil
+}
+
+// RowsFromStructs is RowsFromPoints applied to slice's elements via
+// ToPoints, the inverse of ParseResult for the common table-driven
+// case of asserting against the same structs a test writes.
+func RowsFromStructs(slice interface{}, opts ...influx.FieldOption) ([]models.Row, error) {
+	points, err := influx.ToPoints(slice, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return RowsFromPoints(points...)
+}
+
+// seriesKey identifies the series a point belongs to, the same way
+// InfluxDB itself groups query results: by measurement name plus the
+// exact set of tag key/value pairs.
+func seriesKey(name string, tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	key := name
+	for _, k := range keys {
+		key += "\x00" + k + "=" + tags[k]
+	}
+	return key
+}
@@ -0,0 +1,77 @@
+package influxtest
+
+import (
+	"testing"
+	"time"
+
+	influx "github.com/eachain/influx"
+)
+
+// TestNewRowAndResult confirms the literal builders produce the exact
+// models.Row/client.Result shape a hand-written one would.
+func TestNewRowAndResult(t *testing.T) {
+	row := NewRow("cpu", map[string]string{"host": "a"}, []string{"time", "value"},
+		[]interface{}{"2024-01-01T00:00:00Z", 1.0})
+	if row.Name != "cpu" || row.Tags["host"] != "a" || len(row.Values) != 1 {
+		t.Fatalf("NewRow = %+v", row)
+	}
+
+	result := NewResult(row)
+	if len(result.Series) != 1 || result.Series[0].Name != "cpu" {
+		t.Fatalf("NewResult = %+v", result)
+	}
+}
+
+type rowTestMetric struct {
+	Host  string  `inf:"host,tag"`
+	Value float64 `inf:"value"`
+	Time  time.Time
+}
+
+// TestRowsFromStructsGroupsBySeries confirms
+// RowsFromStructs groups points into one row per distinct tag set and
+// decodes back via ParseResult the same values it started from.
+func TestRowsFromStructsGroupsBySeries(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	metrics := []rowTestMetric{
+		{Host: "a", Value: 1, Time: base},
+		{Host: "b", Value: 2, Time: base.Add(time.Minute)},
+		{Host: "a", Value: 3, Time: base.Add(time.Hour)},
+	}
+
+	rows, err := RowsFromStructs(metrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("RowsFromStructs returned %d series, want 2", len(rows))
+	}
+
+	var got []rowTestMetric
+	for _, row := range rows {
+		var decoded []rowTestMetric
+		if err := influx.ParseResult(&decoded, row); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, decoded...)
+	}
+	if len(got) != 3 {
+		t.Fatalf("decoded %d rows total, want 3", len(got))
+	}
+	for _, m := range got {
+		if m.Host == "" {
+			t.Fatalf("decoded metric %+v missing Host tag", m)
+		}
+	}
+}
+
+// TestRowsFromPointsEmpty confirms no points produces no rows.
+func TestRowsFromPointsEmpty(t *testing.T) {
+	rows, err := RowsFromPoints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 0 {
+		t.Fatalf("RowsFromPoints() = %v, want none", rows)
+	}
+}
@@ -0,0 +1,480 @@
+package influxtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxql"
+)
+
+// fakeVersion is reported as X-Influxdb-Version on every response;
+// client.Client.Ping returns it, and checkResponse (in client/v2)
+// treats its absence on a 5xx as a sign the response didn't come from
+// InfluxDB at all.
+const fakeVersion = "influxtest-fake"
+
+// Server is a lightweight in-memory fake of the InfluxDB HTTP API:
+// enough of /ping, /write and /query to exercise integration tests
+// against Client's real HTTP transport without a running InfluxDB.
+// Queries are evaluated with github.com/influxdata/influxql, the same
+// parser InfluxDB itself and this package's own Validate use, so WHERE
+// time/tag filters, GROUP BY time()/tag and the count/sum/mean/min/max
+// aggregates work the way they do against a real server. Subqueries,
+// continuous queries and SHOW statements are unsupported and return an
+// error result, the same shape a real syntax error would. fill() is
+// accepted but ignored: a GROUP BY time() bucket holding no points is
+// left out of the result. Notably, CREATE DATABASE is supported but
+// "IF NOT EXISTS" is not: the vendored influxql grammar this package
+// parses with doesn't recognize it, even though a real InfluxDB server
+// does, so Migrate (which always issues "IF NOT EXISTS") cannot be
+// exercised against this fake; issue a plain CREATE DATABASE instead.
+//
+//	s := influxtest.NewServer()
+//	defer s.Close()
+//	c, err := influx.New(client.HTTPConfig{Addr: s.URL()})
+type Server struct {
+	httpServer *httptest.Server
+
+	mu  sync.Mutex
+	dbs map[string]*fakeDatabase
+}
+
+type fakeDatabase struct {
+	measurements map[string]*fakeMeasurement
+}
+
+type fakeMeasurement struct {
+	points []fakePoint
+}
+
+type fakePoint struct {
+	time   time.Time
+	tags   map[string]string
+	fields map[string]interface{}
+}
+
+// NewServer starts a Server on an in-process httptest.Server, running
+// until Close.
+func NewServer() *Server {
+	s := &Server{dbs: map[string]*fakeDatabase{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ping", s.handlePing)
+	mux.HandleFunc("/write", s.handleWrite)
+	mux.HandleFunc("/query", s.handleQuery)
+	s.httpServer = httptest.NewServer(mux)
+	return s
+}
+
+// URL returns the base address to pass as client.HTTPConfig.Addr.
+func (s *Server) URL() string {
+	return s.httpServer.URL
+}
+
+// Close shuts down the underlying httptest.Server.
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+func (s *Server) handlePing(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Influxdb-Version", fakeVersion)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleWrite(w http.ResponseWriter, r *http.Request) {
+	db := r.URL.Query().Get("db")
+	precision := r.URL.Query().Get("precision")
+	if precision == "" {
+		precision = "ns"
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	points, err := models.ParsePointsWithPrecision(body, time.Now(), precision)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.mu.Lock()
+	dbStore := s.database(db)
+	for _, p := range points {
+		fields, err := p.Fields()
+		if err != nil {
+			continue
+		}
+		m := dbStore.measurement(string(p.Name()))
+		tags := make(map[string]string, len(p.Tags()))
+		for _, t := range p.Tags() {
+			tags[string(t.Key)] = string(t.Value)
+		}
+		fieldsCopy := make(map[string]interface{}, len(fields))
+		for k, v := range fields {
+			fieldsCopy[k] = v
+		}
+		m.points = append(m.points, fakePoint{time: p.Time(), tags: tags, fields: fieldsCopy})
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("X-Influxdb-Version", fakeVersion)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// database returns db's store, creating it if this is the first
+// write or CREATE DATABASE to see it. Called with s.mu held.
+func (s *Server) database(db string) *fakeDatabase {
+	dbStore := s.dbs[db]
+	if dbStore == nil {
+		dbStore = &fakeDatabase{measurements: map[string]*fakeMeasurement{}}
+		s.dbs[db] = dbStore
+	}
+	return dbStore
+}
+
+func (d *fakeDatabase) measurement(name string) *fakeMeasurement {
+	m := d.measurements[name]
+	if m == nil {
+		m = &fakeMeasurement{}
+		d.measurements[name] = m
+	}
+	return m
+}
+
+func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	db := r.URL.Query().Get("db")
+
+	query, err := influxql.ParseQuery(q)
+	if err != nil {
+		s.writeResponse(w, http.StatusBadRequest, client.Response{Err: err.Error()})
+		return
+	}
+
+	var results []client.Result
+	for _, stmt := range query.Statements {
+		result, err := s.execStatement(stmt, db)
+		if err != nil {
+			s.writeResponse(w, http.StatusOK, client.Response{Err: err.Error()})
+			return
+		}
+		results = append(results, result)
+	}
+	s.writeResponse(w, http.StatusOK, client.Response{Results: results})
+}
+
+func (s *Server) execStatement(stmt influxql.Statement, db string) (client.Result, error) {
+	switch stmt := stmt.(type) {
+	case *influxql.CreateDatabaseStatement:
+		s.mu.Lock()
+		s.database(stmt.Name)
+		s.mu.Unlock()
+		return client.Result{}, nil
+	case *influxql.SelectStatement:
+		return s.execSelect(stmt, db)
+	default:
+		return client.Result{}, fmt.Errorf("influxtest: unsupported statement %q", stmt.String())
+	}
+}
+
+func (s *Server) execSelect(stmt *influxql.SelectStatement, db string) (client.Result, error) {
+	if len(stmt.Sources) != 1 {
+		return client.Result{}, fmt.Errorf("influxtest: SELECT needs exactly one source, got %d", len(stmt.Sources))
+	}
+	measurement, ok := stmt.Sources[0].(*influxql.Measurement)
+	if !ok {
+		return client.Result{}, fmt.Errorf("influxtest: unsupported FROM source %q", stmt.Sources[0].String())
+	}
+	if measurement.Database != "" {
+		db = measurement.Database
+	}
+
+	s.mu.Lock()
+	var points []fakePoint
+	if dbStore := s.dbs[db]; dbStore != nil {
+		if m := dbStore.measurements[measurement.Name]; m != nil {
+			points = append(points, m.points...)
+		}
+	}
+	s.mu.Unlock()
+
+	cond, timeRange, err := influxql.ConditionExpr(stmt.Condition, &influxql.NowValuer{Now: time.Now()})
+	if err != nil {
+		return client.Result{}, err
+	}
+	minNano, maxNano := timeRange.MinTimeNano(), timeRange.MaxTimeNano()
+
+	filtered := points[:0:0]
+	for _, p := range points {
+		if p.time.UnixNano() < minNano || p.time.UnixNano() > maxNano {
+			continue
+		}
+		if cond != nil && !influxql.EvalBool(cond, pointValuer(p)) {
+			continue
+		}
+		filtered = append(filtered, p)
+	}
+	sort.Slice(filtered, func(i, j int) bool { return filtered[i].time.Before(filtered[j].time) })
+
+	if stmt.IsRawQuery {
+		return execRawSelect(stmt, measurement.Name, filtered)
+	}
+	return execAggregateSelect(stmt, measurement.Name, filtered, timeRange)
+}
+
+// pointValuer merges p's tags and fields into the map influxql.EvalBool
+// evaluates the non-time part of a WHERE clause against.
+func pointValuer(p fakePoint) map[string]interface{} {
+	m := make(map[string]interface{}, len(p.tags)+len(p.fields))
+	for k, v := range p.tags {
+		m[k] = v
+	}
+	for k, v := range p.fields {
+		m[k] = v
+	}
+	return m
+}
+
+func execRawSelect(stmt *influxql.SelectStatement, name string, points []fakePoint) (client.Result, error) {
+	columns := []string{"time"}
+	refs := make([]*influxql.VarRef, 0, len(stmt.Fields))
+	for _, f := range stmt.Fields {
+		ref, ok := f.Expr.(*influxql.VarRef)
+		if !ok {
+			return client.Result{}, fmt.Errorf("influxtest: unsupported SELECT expression %q", f.Expr.String())
+		}
+		refs = append(refs, ref)
+		columns = append(columns, f.Name())
+	}
+
+	values := make([][]interface{}, 0, len(points))
+	for _, p := range points {
+		row := make([]interface{}, 1+len(refs))
+		row[0] = p.time.Format(time.RFC3339Nano)
+		for i, ref := range refs {
+			if v, ok := p.fields[ref.Val]; ok {
+				row[i+1] = v
+			} else if v, ok := p.tags[ref.Val]; ok {
+				row[i+1] = v
+			}
+		}
+		values = append(values, row)
+	}
+	if len(values) == 0 {
+		return client.Result{}, nil
+	}
+	return client.Result{Series: []models.Row{{Name: name, Columns: columns, Values: values}}}, nil
+}
+
+func execAggregateSelect(stmt *influxql.SelectStatement, name string, points []fakePoint, timeRange influxql.TimeRange) (client.Result, error) {
+	calls := make([]*influxql.Call, 0, len(stmt.Fields))
+	columns := []string{"time"}
+	for _, f := range stmt.Fields {
+		call, ok := f.Expr.(*influxql.Call)
+		if !ok {
+			return client.Result{}, fmt.Errorf("influxtest: unsupported aggregate expression %q", f.Expr.String())
+		}
+		calls = append(calls, call)
+		columns = append(columns, f.Name())
+	}
+
+	var tagDims []string
+	for _, d := range stmt.Dimensions {
+		if ref, ok := d.Expr.(*influxql.VarRef); ok {
+			tagDims = append(tagDims, ref.Val)
+		}
+	}
+	interval, err := stmt.GroupByInterval()
+	if err != nil {
+		return client.Result{}, err
+	}
+
+	groups := map[string]*aggGroup{}
+	var order []string
+	for _, p := range points {
+		key, tags := groupKey(p, tagDims)
+		g := groups[key]
+		if g == nil {
+			g = &aggGroup{tags: tags, buckets: map[int64][]fakePoint{}}
+			groups[key] = g
+			order = append(order, key)
+		}
+		bucket := bucketStart(p.time, interval, timeRange)
+		g.buckets[bucket] = append(g.buckets[bucket], p)
+		g.bucketOrder = appendOnce(g.bucketOrder, bucket)
+	}
+	sort.Strings(order)
+
+	series := make([]models.Row, 0, len(order))
+	for _, key := range order {
+		g := groups[key]
+		sort.Slice(g.bucketOrder, func(i, j int) bool { return g.bucketOrder[i] < g.bucketOrder[j] })
+
+		values := make([][]interface{}, 0, len(g.bucketOrder))
+		for _, bucket := range g.bucketOrder {
+			row := make([]interface{}, 1+len(calls))
+			row[0] = time.Unix(0, bucket).UTC().Format(time.RFC3339Nano)
+			for i, call := range calls {
+				v, err := aggregate(call, g.buckets[bucket])
+				if err != nil {
+					return client.Result{}, err
+				}
+				row[i+1] = v
+			}
+			values = append(values, row)
+		}
+		series = append(series, models.Row{Name: name, Tags: g.tags, Columns: columns, Values: values})
+	}
+	return client.Result{Series: series}, nil
+}
+
+type aggGroup struct {
+	tags        map[string]string
+	buckets     map[int64][]fakePoint
+	bucketOrder []int64
+}
+
+func appendOnce(buckets []int64, bucket int64) []int64 {
+	for _, b := range buckets {
+		if b == bucket {
+			return buckets
+		}
+	}
+	return append(buckets, bucket)
+}
+
+// groupKey returns a stable string key and the tag values for p's
+// combination of tagDims, so every point sharing that combination ends
+// up in the same series.
+func groupKey(p fakePoint, tagDims []string) (string, map[string]string) {
+	if len(tagDims) == 0 {
+		return "", nil
+	}
+	tags := make(map[string]string, len(tagDims))
+	var parts []string
+	for _, dim := range tagDims {
+		v := p.tags[dim]
+		tags[dim] = v
+		parts = append(parts, dim+"="+v)
+	}
+	return strings.Join(parts, ","), tags
+}
+
+// bucketStart floors t to the start of its GROUP BY time() bucket,
+// aligned to the Unix epoch; 0 interval means every point falls into
+// one bucket at the queried range's start (or the epoch, if
+// unbounded), matching a plain aggregate with no GROUP BY time().
+func bucketStart(t time.Time, interval time.Duration, timeRange influxql.TimeRange) int64 {
+	if interval <= 0 {
+		if !timeRange.Min.IsZero() {
+			return timeRange.Min.UnixNano()
+		}
+		return 0
+	}
+	return t.UnixNano() / int64(interval) * int64(interval)
+}
+
+// aggregate applies call (count/sum/mean/min/max of a single field)
+// across points, the "basic aggregates" this fake server supports.
+func aggregate(call *influxql.Call, points []fakePoint) (interface{}, error) {
+	if len(call.Args) != 1 {
+		return nil, fmt.Errorf("influxtest: %s() needs exactly one argument", call.Name)
+	}
+	ref, ok := call.Args[0].(*influxql.VarRef)
+	if !ok {
+		return nil, fmt.Errorf("influxtest: unsupported argument to %s(): %q", call.Name, call.Args[0].String())
+	}
+
+	var nums []float64
+	count := 0
+	for _, p := range points {
+		v, ok := p.fields[ref.Val]
+		if !ok {
+			continue
+		}
+		count++
+		if f, ok := toFloat64(v); ok {
+			nums = append(nums, f)
+		}
+	}
+
+	switch call.Name {
+	case "count":
+		return count, nil
+	case "sum":
+		var sum float64
+		for _, n := range nums {
+			sum += n
+		}
+		return sum, nil
+	case "mean":
+		if len(nums) == 0 {
+			return nil, nil
+		}
+		var sum float64
+		for _, n := range nums {
+			sum += n
+		}
+		return sum / float64(len(nums)), nil
+	case "min":
+		if len(nums) == 0 {
+			return nil, nil
+		}
+		min := nums[0]
+		for _, n := range nums[1:] {
+			if n < min {
+				min = n
+			}
+		}
+		return min, nil
+	case "max":
+		if len(nums) == 0 {
+			return nil, nil
+		}
+		max := nums[0]
+		for _, n := range nums[1:] {
+			if n > max {
+				max = n
+			}
+		}
+		return max, nil
+	default:
+		return nil, fmt.Errorf("influxtest: unsupported aggregate function %q", call.Name)
+	}
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case bool:
+		return 0, false
+	default:
+		return 0, false
+	}
+}
+
+func (s *Server) writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("X-Influxdb-Version", fakeVersion)
+	w.WriteHeader(status)
+	io.WriteString(w, err.Error())
+}
+
+func (s *Server) writeResponse(w http.ResponseWriter, status int, response client.Response) {
+	w.Header().Set("X-Influxdb-Version", fakeVersion)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,192 @@
+package influxtest
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	influx "github.com/eachain/influx"
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+func newTestClient(t *testing.T, s *Server) client.Client {
+	cli, err := client.NewHTTPClient(client.HTTPConfig{Addr: s.URL()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cli.Close() })
+	return cli
+}
+
+func mustWrite(t *testing.T, cli client.Client, db, name string, tags map[string]string, fields map[string]interface{}, ts time.Time) {
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{Database: db, Precision: "ns"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := client.NewPoint(name, tags, fields, ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bp.AddPoint(p)
+	if err := cli.Write(bp); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestServerPing confirms Ping succeeds with the fake version string.
+func TestServerPing(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	cli := newTestClient(t, s)
+
+	_, version, err := cli.Ping(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != fakeVersion {
+		t.Fatalf("Ping version = %q, want %q", version, fakeVersion)
+	}
+}
+
+// TestServerSelectWithTimeAndTagFilter confirms a raw SELECT with a
+// WHERE time/tag filter returns only the matching points.
+func TestServerSelectWithTimeAndTagFilter(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	cli := newTestClient(t, s)
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mustWrite(t, cli, "mydb", "cpu", map[string]string{"host": "a"}, map[string]interface{}{"value": 1.0}, base)
+	mustWrite(t, cli, "mydb", "cpu", map[string]string{"host": "b"}, map[string]interface{}{"value": 2.0}, base.Add(time.Minute))
+	mustWrite(t, cli, "mydb", "cpu", map[string]string{"host": "a"}, map[string]interface{}{"value": 3.0}, base.Add(time.Hour))
+
+	resp, err := cli.Query(client.NewQuery(
+		`SELECT value FROM cpu WHERE host = 'a' AND time >= '2024-01-01T00:00:00Z' AND time <= '2024-01-01T00:30:00Z'`,
+		"mydb", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := resp.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results) != 1 || len(resp.Results[0].Series) != 1 {
+		t.Fatalf("Results = %+v, want one series", resp.Results)
+	}
+	rows := resp.Results[0].Series[0].Values
+	if len(rows) != 1 {
+		t.Fatalf("rows = %v, want exactly the one matching point", rows)
+	}
+	if rows[0][1].(json.Number).String() != "1" {
+		t.Fatalf("value = %v, want 1", rows[0][1])
+	}
+}
+
+// TestServerSelectMeanGroupByTime confirms an aggregate SELECT with
+// GROUP BY time() buckets points and applies the aggregate per
+// bucket.
+func TestServerSelectMeanGroupByTime(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	cli := newTestClient(t, s)
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mustWrite(t, cli, "mydb", "cpu", nil, map[string]interface{}{"value": 2.0}, base)
+	mustWrite(t, cli, "mydb", "cpu", nil, map[string]interface{}{"value": 4.0}, base.Add(30*time.Second))
+	mustWrite(t, cli, "mydb", "cpu", nil, map[string]interface{}{"value": 10.0}, base.Add(time.Minute))
+
+	resp, err := cli.Query(client.NewQuery(
+		`SELECT mean(value) FROM cpu WHERE time >= '2024-01-01T00:00:00Z' AND time < '2024-01-01T00:02:00Z' GROUP BY time(1m)`,
+		"mydb", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := resp.Error(); err != nil {
+		t.Fatal(err)
+	}
+	rows := resp.Results[0].Series[0].Values
+	if len(rows) != 2 {
+		t.Fatalf("rows = %v, want 2 buckets", rows)
+	}
+	if got := rows[0][1].(json.Number).String(); got != "3" {
+		t.Fatalf("bucket 1 mean = %v, want 3", got)
+	}
+	if got := rows[1][1].(json.Number).String(); got != "10" {
+		t.Fatalf("bucket 2 mean = %v, want 10", got)
+	}
+}
+
+// TestServerRoundTripsToPointAndParseResult confirms a struct written
+// via influx.ToPoint can be read back into an equivalent struct via
+// influx.ParseResult after a SELECT against Server, the same
+// write/query round trip a real InfluxDB server supports.
+func TestServerRoundTripsToPointAndParseResult(t *testing.T) {
+	type cpuStat struct {
+		Host  string  `inf:"host,tag"`
+		Value float64 `inf:"value"`
+	}
+
+	s := NewServer()
+	defer s.Close()
+	cli := newTestClient(t, s)
+
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p, err := influx.ToPointAt(cpuStat{Host: "a", Value: 42.5}, ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{Database: "mydb", Precision: "ns"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bp.AddPoint(p)
+	if err := cli.Write(bp); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := cli.Query(client.NewQuery(`SELECT host, value FROM cpu_stat WHERE host = 'a'`, "mydb", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := resp.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results) != 1 || len(resp.Results[0].Series) != 1 {
+		t.Fatalf("Results = %+v, want one series", resp.Results)
+	}
+
+	var got cpuStat
+	if err := influx.ParseResult(&got, resp.Results[0].Series[0]); err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if got != (cpuStat{Host: "a", Value: 42.5}) {
+		t.Fatalf("ParseResult = %+v, want %+v", got, cpuStat{Host: "a", Value: 42.5})
+	}
+}
+
+// TestServerCreateDatabaseThenSelectEmpty confirms CREATE DATABASE is
+// accepted and an empty measurement returns no rows rather than an
+// error.
+func TestServerCreateDatabaseThenSelectEmpty(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	cli := newTestClient(t, s)
+
+	resp, err := cli.Query(client.NewQuery(`CREATE DATABASE mydb`, "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := resp.Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err = cli.Query(client.NewQuery(`SELECT value FROM cpu`, "mydb", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := resp.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results[0].Series) != 0 {
+		t.Fatalf("Series = %+v, want none for an empty measurement", resp.Results[0].Series)
+	}
+}
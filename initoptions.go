@@ -0,0 +1,52 @@
+package influx
+
+import (
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// ConfigOption sets one field on the client.HTTPConfig InitClientWithOptions
+// builds, the same composable-options shape ParseOption gives
+// ParseResultWithOptions, for combining auth, timeout and user agent (or
+// any mix of them) in one call instead of either calling a single-field
+// InitClientWithX wrapper per setting or constructing a client.HTTPConfig
+// by hand for InitClientWithConfig.
+type ConfigOption func(*client.HTTPConfig)
+
+// WithAuth sets the username and password InitClientWithOptions
+// authenticates with, the same fields InitClientWithAuth sets alone.
+func WithAuth(username, password string) ConfigOption {
+	return func(cfg *client.HTTPConfig) {
+		cfg.Username = username
+		cfg.Password = password
+	}
+}
+
+// WithTimeout sets the HTTP timeout InitClientWithOptions applies, the
+// same field InitClientWithTimeout sets alone.
+func WithTimeout(d time.Duration) ConfigOption {
+	return func(cfg *client.HTTPConfig) {
+		cfg.Timeout = d
+	}
+}
+
+// WithUserAgent sets the User-Agent InitClientWithOptions identifies
+// itself with, the same field InitClientWithUserAgent sets alone.
+func WithUserAgent(userAgent string) ConfigOption {
+	return func(cfg *client.HTTPConfig) {
+		cfg.UserAgent = userAgent
+	}
+}
+
+// InitClientWithOptions initializes the package-level default Client
+// for addr, applying every opts in order, so a caller needing more than
+// one of auth/timeout/user agent together doesn't have to drop down to
+// InitClientWithConfig and build a client.HTTPConfig by hand.
+func InitClientWithOptions(addr string, opts ...ConfigOption) error {
+	cfg := client.HTTPConfig{Addr: addr}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return InitClientWithConfig(cfg)
+}
@@ -0,0 +1,50 @@
+package influx
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// stringInterning controls whether ParseResult/ParseResults canonicalize
+// a decoded string value (a tag value, or a string field's value) and,
+// for a map destination, a column or tag name used as its key, through
+// internPool instead of keeping each decode's own freshly allocated
+// copy, set by SetStringInterning. Off by default.
+var stringInterning int32
+
+// internPool holds the canonical string for each distinct value seen
+// while interning is enabled. It only ever grows: interning is meant
+// for values with naturally bounded cardinality across a process's
+// lifetime (host names, status codes, column names), not arbitrary
+// data, so unbounded growth from an unsuitable use isn't guarded
+// against here.
+var internPool sync.Map // string -> string
+
+// SetStringInterning toggles string interning for decoded values from
+// here on: with it on, a query result whose rows repeat the same tag
+// values or string fields across thousands of rows (host names, status
+// codes) has each repeat replaced by the first copy ParseResult saw,
+// instead of holding one allocation per row, cutting a large decoded
+// dataset's retained memory roughly to its distinct-value count instead
+// of its row count.
+func SetStringInterning(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&stringInterning, 1)
+	} else {
+		atomic.StoreInt32(&stringInterning, 0)
+	}
+}
+
+// intern returns internPool's canonical copy of s, storing s as the
+// canonical copy the first time it's seen, or s itself unchanged if
+// SetStringInterning hasn't been enabled.
+func intern(s string) string {
+	if atomic.LoadInt32(&stringInterning) == 0 {
+		return s
+	}
+	if v, ok := internPool.Load(s); ok {
+		return v.(string)
+	}
+	actual, _ := internPool.LoadOrStore(s, s)
+	return actual.(string)
+}
@@ -0,0 +1,97 @@
+package influx
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sync/atomic"
+)
+
+// strictIntDecoding controls whether ParseResult rejects a column
+// value that doesn't fit an int/uint destination field without losing
+// information — a fractional part the field can't hold, or a
+// magnitude outside its range — instead of silently truncating it, set
+// by SetStrictIntDecoding. Off by default.
+var strictIntDecoding int32
+
+// SetStrictIntDecoding toggles overflow and truncation detection for
+// int/uint destination fields from here on: with it on, a column
+// value with a fractional part, or one too large (or, for an unsigned
+// field, negative) to fit the field's width, makes ParseResult fail
+// with an *IntOverflowError instead of silently truncating it the way
+// a plain Go numeric conversion would.
+func SetStrictIntDecoding(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&strictIntDecoding, 1)
+	} else {
+		atomic.StoreInt32(&strictIntDecoding, 0)
+	}
+}
+
+// IntOverflowError is returned by ParseResult under
+// SetStrictIntDecoding when a column's value doesn't fit its
+// destination int/uint field without loss.
+type IntOverflowError struct {
+	Column string
+	Value  interface{}
+	Kind   reflect.Kind
+}
+
+func (e *IntOverflowError) Error() string {
+	return fmt.Sprintf("influx: column %q value %v does not fit %s without loss", e.Column, e.Value, e.Kind)
+}
+
+// hasFraction reports whether val, a raw column value being decoded
+// into an int/uint field, carries a nonzero fractional part — the
+// InfluxDB query engine returns every numeric column as a float or
+// json.Number regardless of the field it's destined for, so this is
+// the only way to tell "3" (a whole number InfluxDB chose to format
+// with a fraction) apart from "3.5" (genuine loss) before parseInt
+// truncates it.
+func hasFraction(val interface{}) bool {
+	switch v := val.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return err == nil && f != math.Trunc(f)
+	case float32:
+		return float64(v) != math.Trunc(float64(v))
+	case float64:
+		return v != math.Trunc(v)
+	}
+	return false
+}
+
+// intOverflows reports whether n, already parseInt'd to int64, is
+// outside the range dst's kind can hold.
+func intOverflows(n int64, kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int8:
+		return n < math.MinInt8 || n > math.MaxInt8
+	case reflect.Int16:
+		return n < math.MinInt16 || n > math.MaxInt16
+	case reflect.Int32:
+		return n < math.MinInt32 || n > math.MaxInt32
+	case reflect.Uint8:
+		return n < 0 || n > math.MaxUint8
+	case reflect.Uint16:
+		return n < 0 || n > math.MaxUint16
+	case reflect.Uint32:
+		return n < 0 || n > math.MaxUint32
+	case reflect.Uint, reflect.Uint64:
+		return n < 0
+	default: // Int, Int64
+		return false
+	}
+}
+
+// checkIntOverflow is the SetStrictIntDecoding check parseSingle runs
+// before committing n (val parsed to int64) to an int/uint field of
+// kind: it returns an *IntOverflowError if val has a fractional part
+// or n doesn't fit kind's range, nil otherwise.
+func checkIntOverflow(col string, val interface{}, n int64, kind reflect.Kind) error {
+	if hasFraction(val) || intOverflows(n, kind) {
+		return &IntOverflowError{Column: col, Value: val, Kind: kind}
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package influx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/models"
+)
+
+// JSONDocument is the shape ExportJSON/ExportStructsJSON render:
+// {"series":[{"name","tags","columns","values"}, ...]}, the same
+// fields a client.Result's own Series already carries, without the
+// outer "results" wrapper a raw query response has — the shape an
+// HTTP API proxying Influx data straight to a frontend wants to hand
+// back.
+type JSONDocument struct {
+	Series []models.Row `json:"series"`
+}
+
+// ExportJSON wraps series (e.g. a client.Result's Series) as a
+// JSONDocument. Each series' Values stays column-oriented, the same
+// layout InfluxDB's own query responses use; see ExportJSONRecords
+// for a row-oriented alternative.
+func ExportJSON(series []models.Row) JSONDocument {
+	return JSONDocument{Series: series}
+}
+
+// ExportStructsJSON is ExportJSON for a slice of structs (or pointers
+// to structs) of the kind InsertMany/ToPoints accepts: it builds
+// points with ToPoints and groups them into series the same way
+// InfluxDB itself groups points sharing a measurement and tag set,
+// so a struct slice written with InsertMany and a query result read
+// back out of InfluxDB render to the same JSON shape.
+func ExportStructsJSON(slice interface{}, opts ...FieldOption) (JSONDocument, error) {
+	points, err := ToPoints(slice, opts...)
+	if err != nil {
+		return JSONDocument{}, err
+	}
+	series, err := rowsFromPoints(points)
+	if err != nil {
+		return JSONDocument{}, err
+	}
+	return JSONDocument{Series: series}, nil
+}
+
+// JSONRecord is one row of one series flattened into a single JSON
+// object: its measurement, its tags, and every column (time
+// included) as its own key.
+type JSONRecord map[string]interface{}
+
+// ExportJSONRecords renders series in row orientation: one JSONRecord
+// per row instead of JSONDocument's column-oriented
+// {columns, values} pairs, for a frontend that would rather not zip
+// Columns against Values itself.
+func ExportJSONRecords(series []models.Row) []JSONRecord {
+	var records []JSONRecord
+	for _, row := range series {
+		for _, values := range row.Values {
+			record := JSONRecord{"measurement": row.Name}
+			for k, v := range row.Tags {
+				record[k] = v
+			}
+			for i, col := range row.Columns {
+				if i < len(values) {
+					record[col] = values[i]
+				}
+			}
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// ExportStructsJSONRecords is ExportJSONRecords for a slice of structs
+// (or pointers to structs), built the same way ExportStructsJSON is.
+func ExportStructsJSONRecords(slice interface{}, opts ...FieldOption) ([]JSONRecord, error) {
+	doc, err := ExportStructsJSON(slice, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return ExportJSONRecords(doc.Series), nil
+}
+
+// rowsFromPoints groups points into models.Rows by series (name plus
+// full tag set), one value row per point, columns "time" followed by
+// every field name sorted — a stable, input-order-independent layout
+// so the same points always render to the same JSON regardless of
+// the order ToPoints happened to return them in.
+func rowsFromPoints(points []*client.Point) ([]models.Row, error) {
+	type series struct {
+		row    models.Row
+		fields map[string]bool
+	}
+	var order []string
+	byKey := map[string]*series{}
+	for _, p := range points {
+		fields, err := p.Fields()
+		if err != nil {
+			return nil, err
+		}
+		key := pointSeriesKey(p.Name(), p.Tags())
+		s, ok := byKey[key]
+		if !ok {
+			s = &series{row: models.Row{Name: p.Name(), Tags: p.Tags()}, fields: map[string]bool{}}
+			byKey[key] = s
+			order = append(order, key)
+		}
+		for name := range fields {
+			s.fields[name] = true
+		}
+	}
+
+	for _, key := range order {
+		s := byKey[key]
+		names := make([]string, 0, len(s.fields))
+		for name := range s.fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		s.row.Columns = append([]string{"time"}, names...)
+	}
+
+	for _, p := range points {
+		fields, _ := p.Fields()
+		s := byKey[pointSeriesKey(p.Name(), p.Tags())]
+		values := make([]interface{}, len(s.row.Columns))
+		values[0] = p.Time().Format(time.RFC3339Nano)
+		for i, name := range s.row.Columns[1:] {
+			values[i+1] = fields[name]
+		}
+		s.row.Values = append(s.row.Values, values)
+	}
+
+	rows := make([]models.Row, 0, len(order))
+	for _, key := range order {
+		rows = append(rows, byKey[key].row)
+	}
+	return rows, nil
+}
+
+// EncodeNDJSON writes series as newline-delimited JSON, one
+// models.Row object per line instead of ExportJSON's single
+// {"series":[...]} document — the shape a line-oriented tool (tail -f,
+// a log shipper, a diff of two snapshots) can process or compare one
+// series at a time without buffering the whole export. DecodeNDJSON
+// reads it back.
+func EncodeNDJSON(w io.Writer, series []models.Row) error {
+	enc := json.NewEncoder(w)
+	for _, row := range series {
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeNDJSON reads r as EncodeNDJSON's output, decoding numeric
+// values as json.Number (the same way DecodeJSON does) so ImportNDJSON
+// can recover each field's original int/float type.
+func DecodeNDJSON(r io.Reader) ([]models.Row, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var series []models.Row
+	for dec.More() {
+		var row models.Row
+		if err := dec.Decode(&row); err != nil {
+			return nil, err
+		}
+		series = append(series, row)
+	}
+	return series, nil
+}
+
+// DecodeJSON parses data (ExportJSON's output) into a JSONDocument,
+// decoding numeric values as json.Number instead of float64 so
+// ImportJSON can tell an integer field from a float one apart, the
+// same distinction Export/Import's line protocol round-trip never
+// loses. A plain json.Unmarshal into a JSONDocument works too, but
+// every numeric field comes back as float64.
+func DecodeJSON(r io.Reader) (JSONDocument, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var doc JSONDocument
+	if err := dec.Decode(&doc); err != nil {
+		return JSONDocument{}, err
+	}
+	return doc, nil
+}
+
+// ImportJSON turns doc (as produced by ExportJSON and read back with
+// DecodeJSON) into points, the reverse of rowsFromPoints: each row's
+// Tags become the point's tags, its "time" column (an RFC3339Nano
+// string, the form rowsFromPoints writes) becomes the point's
+// timestamp, and every other column becomes a field.
+func ImportJSON(doc JSONDocument) ([]*client.Point, error) {
+	return importSeriesJSON(doc.Series)
+}
+
+// ImportNDJSON is ImportJSON for EncodeNDJSON's output.
+func ImportNDJSON(r io.Reader) ([]*client.Point, error) {
+	series, err := DecodeNDJSON(r)
+	if err != nil {
+		return nil, err
+	}
+	return importSeriesJSON(series)
+}
+
+// importSeriesJSON is ImportJSON/ImportNDJSON's shared plumbing. It
+// reuses exportFieldValue (backup.go) so a field decoded as
+// json.Number comes back as the int64 or float64 client.NewPoint
+// expects, exactly the way Export/Import's line protocol round-trip
+// already preserves a field's numeric type.
+func importSeriesJSON(series []models.Row) ([]*client.Point, error) {
+	var points []*client.Point
+	for _, row := range series {
+		if len(row.Columns) == 0 || row.Columns[0] != "time" {
+			return nil, fmt.Errorf("influx: ImportJSON: series %q has no leading \"time\" column", row.Name)
+		}
+		for _, values := range row.Values {
+			if len(values) == 0 || values[0] == nil {
+				continue
+			}
+			fields := make(map[string]interface{}, len(row.Columns)-1)
+			for i := 1; i < len(row.Columns) && i < len(values); i++ {
+				if values[i] == nil {
+					continue
+				}
+				fields[row.Columns[i]] = exportFieldValue(values[i])
+			}
+			if len(fields) == 0 {
+				continue
+			}
+			p, err := client.NewPoint(row.Name, row.Tags, fields, parseTime(values[0]))
+			if err != nil {
+				return nil, err
+			}
+			points = append(points, p)
+		}
+	}
+	return points, nil
+}
+
+// pointSeriesKey identifies the series a point belongs to: its
+// measurement plus its full, sorted tag set, so two points sharing
+// both group into the same row.
+func pointSeriesKey(name string, tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	key := name
+	for _, k := range keys {
+		key += "\x00" + k + "=" + tags[k]
+	}
+	return key
+}
@@ -0,0 +1,177 @@
+package influx
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/models"
+)
+
+type jsonExportMetric struct {
+	Host  string  `inf:"host,tag"`
+	Value float64 `inf:"value"`
+	Time  time.Time
+}
+
+// TestExportJSONShape confirms ExportJSON wraps series as
+// {"series":[...]}  with no outer "results" envelope.
+func TestExportJSONShape(t *testing.T) {
+	doc := ExportJSON([]models.Row{
+		{Name: "cpu", Columns: []string{"time", "value"}, Values: [][]interface{}{{"t", 1.0}}},
+	})
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"series":[{"name":"cpu","columns":["time","value"],"values":[["t",1]]}]}`
+	if string(data) != want {
+		t.Fatalf("got  %s\nwant %s", data, want)
+	}
+}
+
+// TestExportStructsJSONGroupsBySeries confirms ExportStructsJSON
+// builds one series per distinct tag set, columns sorted, fields
+// decodable back via ParseResult.
+func TestExportStructsJSONGroupsBySeries(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	metrics := []jsonExportMetric{
+		{Host: "a", Value: 1, Time: now},
+		{Host: "b", Value: 2, Time: now},
+		{Host: "a", Value: 3, Time: now.Add(time.Second)},
+	}
+
+	doc, err := ExportStructsJSON(metrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Series) != 2 {
+		t.Fatalf("len(Series) = %d, want 2", len(doc.Series))
+	}
+
+	var decoded []jsonExportMetric
+	for _, row := range doc.Series {
+		var part []jsonExportMetric
+		if err := ParseResult(&part, row); err != nil {
+			t.Fatal(err)
+		}
+		decoded = append(decoded, part...)
+	}
+	if len(decoded) != 3 {
+		t.Fatalf("len(decoded) = %d, want 3", len(decoded))
+	}
+}
+
+// TestExportJSONRecordsFlattensRows confirms ExportJSONRecords
+// produces one flat object per row with measurement, tags and
+// columns merged into a single map.
+func TestExportJSONRecordsFlattensRows(t *testing.T) {
+	series := []models.Row{
+		{
+			Name:    "cpu",
+			Tags:    map[string]string{"host": "a"},
+			Columns: []string{"time", "value"},
+			Values:  [][]interface{}{{"t1", 1.0}, {"t2", 2.0}},
+		},
+	}
+
+	records := ExportJSONRecords(series)
+	if len(records) != 2 {
+		t.Fatalf("len(records) = %d, want 2", len(records))
+	}
+	if records[0]["measurement"] != "cpu" || records[0]["host"] != "a" || records[0]["value"] != 1.0 {
+		t.Fatalf("records[0] = %v", records[0])
+	}
+}
+
+// TestImportJSONRoundTrips confirms ExportStructsJSON's output, sent
+// through an encode/decode cycle, imports back into points carrying
+// the same measurement, tags and field values the original structs
+// had.
+func TestImportJSONRoundTrips(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	metrics := []jsonExportMetric{
+		{Host: "a", Value: 1, Time: now},
+		{Host: "b", Value: 2, Time: now.Add(time.Second)},
+	}
+
+	doc, err := ExportStructsJSON(metrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := DecodeJSON(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	points, err := ImportJSON(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("len(points) = %d, want 2", len(points))
+	}
+
+	byHost := map[string]*client.Point{}
+	for _, p := range points {
+		byHost[p.Tags()["host"]] = p
+	}
+	// A whole-number float round-trips through JSON and back as an
+	// int64, the same field-type preservation exportFieldValue gives
+	// Export/Import's line protocol round-trip.
+	for host, want := range map[string]int64{"a": 1, "b": 2} {
+		p, ok := byHost[host]
+		if !ok {
+			t.Fatalf("no point for host %q", host)
+		}
+		fields, err := p.Fields()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fields["value"] != want {
+			t.Fatalf("host %q value = %v, want %v", host, fields["value"], want)
+		}
+	}
+}
+
+// TestEncodeDecodeNDJSONRoundTrips confirms EncodeNDJSON/DecodeNDJSON/
+// ImportNDJSON round-trip the same way ExportJSON/DecodeJSON/ImportJSON
+// do, one series per line instead of one document.
+func TestEncodeDecodeNDJSONRoundTrips(t *testing.T) {
+	series := []models.Row{
+		{
+			Name:    "cpu",
+			Tags:    map[string]string{"host": "a"},
+			Columns: []string{"time", "value"},
+			Values:  [][]interface{}{{"2020-01-02T03:04:05Z", 1.0}},
+		},
+		{
+			Name:    "cpu",
+			Tags:    map[string]string{"host": "b"},
+			Columns: []string{"time", "value"},
+			Values:  [][]interface{}{{"2020-01-02T03:04:06Z", 2.0}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeNDJSON(&buf, series); err != nil {
+		t.Fatal(err)
+	}
+
+	points, err := ImportNDJSON(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("len(points) = %d, want 2", len(points))
+	}
+	if points[0].Tags()["host"] != "a" || points[1].Tags()["host"] != "b" {
+		t.Fatalf("tags = %v, %v", points[0].Tags(), points[1].Tags())
+	}
+}
@@ -0,0 +1,47 @@
+package influx
+
+import (
+	"reflect"
+	"strings"
+	"sync/atomic"
+)
+
+// jsonTagFallback controls whether addField falls back to a field's json
+// tag for its column/field name when it has no inf tag, set by
+// SetJSONTagFallback.
+var jsonTagFallback int32 // atomic bool
+
+// SetJSONTagFallback toggles whether a field with no `inf` tag but a
+// `json` tag uses the json name instead of the default titleToSnake (or
+// active NamingStrategy) name. Off by default, since a json tag's
+// options (`omitempty`, `string`, `-`) mean something different there
+// and enabling this for a struct that wasn't designed with ToPoint in
+// mind can silently rename fields. It drops every cached type plan so
+// already-seen types are replanned under the new setting.
+func SetJSONTagFallback(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&jsonTagFallback, 1)
+	} else {
+		atomic.StoreInt32(&jsonTagFallback, 0)
+	}
+	resetTypePlans()
+}
+
+// jsonFallbackName returns ft's json tag name to use as a field name
+// fallback, and whether it has one: "" (no json tag), "-" with no
+// following options (field excluded from JSON, not a naming hint), and
+// an anonymous name (`json:",omitempty"`) all report ok == false.
+func jsonFallbackName(ft reflect.StructField) (string, bool) {
+	if atomic.LoadInt32(&jsonTagFallback) == 0 {
+		return "", false
+	}
+	tagstr := ft.Tag.Get("json")
+	if tagstr == "" || tagstr == "-" {
+		return "", false
+	}
+	name := strings.Split(tagstr, ",")[0]
+	if name == "" {
+		return "", false
+	}
+	return name, true
+}
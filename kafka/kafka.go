@@ -0,0 +1,102 @@
+// Package kafka adapts a Kafka producer into a client.Client, so
+// influx.NewBufferedWriterWithClient (or influx.NewWithClient) can
+// deliver line protocol to Kafka instead of, or in addition to, an
+// HTTP or UDP write to InfluxDB — for an ingest pipeline that buffers
+// everything through Kafka ahead of InfluxDB rather than writing to it
+// directly.
+//
+// This package depends on no particular Kafka client library: Producer
+// is the minimal interface a caller's own Kafka client (confluent-kafka-go,
+// segmentio/kafka-go, Shopify/sarama, ...) needs to satisfy, usually
+// with a one-line adapter of its own.
+package kafka
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// Producer publishes an encoded line-protocol payload to a Kafka
+// topic — the subset of a Kafka producer client Client needs.
+type Producer interface {
+	Produce(ctx context.Context, topic string, value []byte) error
+}
+
+// errQueryUnsupported is returned by every query method: a Kafka topic
+// has no InfluxQL endpoint to query against.
+var errQueryUnsupported = errors.New("kafka: sink is write-only; queries are not supported")
+
+// Client is a client.Client that publishes each batch it's given as
+// one line-protocol message per point instead of sending it over HTTP
+// or UDP. Queries always fail, the same way influx.NewUDP's Client
+// already fails every query.
+type Client struct {
+	Producer Producer
+
+	// TopicPrefix is prepended to a batch's database name to build the
+	// topic it's published to, e.g. TopicPrefix "influx." writes db
+	// "metrics" to topic "influx.metrics". Empty means the topic is
+	// just the database name.
+	TopicPrefix string
+}
+
+// NewClient returns a Client publishing through p, one topic per
+// database.
+func NewClient(p Producer) *Client {
+	return &Client{Producer: p}
+}
+
+func (c *Client) topic(db string) string {
+	return c.TopicPrefix + db
+}
+
+// Write publishes bp's points to Kafka, topic named after bp's
+// database.
+func (c *Client) Write(bp client.BatchPoints) error {
+	return c.WriteCtx(context.Background(), bp)
+}
+
+// WriteCtx is Write, aborting as soon as ctx is canceled — between
+// points, since Producer.Produce is given ctx itself and may honor it
+// mid-call too.
+func (c *Client) WriteCtx(ctx context.Context, bp client.BatchPoints) error {
+	topic := c.topic(bp.Database())
+	for _, p := range bp.Points() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		line := p.PrecisionString(bp.Precision())
+		if err := c.Producer.Produce(ctx, topic, []byte(line)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Ping always succeeds: there is no Kafka broker health check this
+// package can answer on a client.Client's behalf.
+func (c *Client) Ping(timeout time.Duration) (time.Duration, string, error) {
+	return 0, "", nil
+}
+
+// Query always fails: see errQueryUnsupported.
+func (c *Client) Query(q client.Query) (*client.Response, error) {
+	return nil, errQueryUnsupported
+}
+
+// QueryCtx always fails: see errQueryUnsupported.
+func (c *Client) QueryCtx(ctx context.Context, q client.Query) (*client.Response, error) {
+	return nil, errQueryUnsupported
+}
+
+// QueryAsChunk always fails: see errQueryUnsupported.
+func (c *Client) QueryAsChunk(q client.Query) (*client.ChunkedResponse, error) {
+	return nil, errQueryUnsupported
+}
+
+// Close releases no resources of its own; Producer is the caller's to
+// close.
+func (c *Client) Close() error { return nil }
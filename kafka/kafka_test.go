@@ -0,0 +1,116 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+type fakeProducer struct {
+	mu   sync.Mutex
+	msgs map[string][]string
+	err  error
+}
+
+func (p *fakeProducer) Produce(ctx context.Context, topic string, value []byte) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.msgs == nil {
+		p.msgs = map[string][]string{}
+	}
+	p.msgs[topic] = append(p.msgs[topic], string(value))
+	return nil
+}
+
+// TestWriteCtxPublishesOneMessagePerPoint confirms each point in a
+// batch becomes its own line-protocol message on a topic named after
+// the batch's database.
+func TestWriteCtxPublishesOneMessagePerPoint(t *testing.T) {
+	p := &fakeProducer{}
+	c := NewClient(p)
+
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{Database: "mydb", Precision: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := client.NewPoint("cpu", map[string]string{"host": "a"}, map[string]interface{}{"value": 1.0}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	bp.AddPoint(pt)
+
+	if err := c.WriteCtx(context.Background(), bp); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.msgs["mydb"]) != 1 {
+		t.Fatalf("msgs[mydb] = %v, want 1 message", p.msgs["mydb"])
+	}
+}
+
+// TestTopicPrefix confirms TopicPrefix is prepended to the database
+// name.
+func TestTopicPrefix(t *testing.T) {
+	p := &fakeProducer{}
+	c := &Client{Producer: p, TopicPrefix: "influx."}
+
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{Database: "mydb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := client.NewPoint("cpu", nil, map[string]interface{}{"value": 1.0}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	bp.AddPoint(pt)
+
+	if err := c.Write(bp); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.msgs["influx.mydb"]) != 1 {
+		t.Fatalf("msgs = %v, want one message on influx.mydb", p.msgs)
+	}
+}
+
+// TestWriteCtxPropagatesProducerError confirms a Producer error is
+// returned instead of swallowed.
+func TestWriteCtxPropagatesProducerError(t *testing.T) {
+	want := errors.New("boom")
+	p := &fakeProducer{err: want}
+	c := NewClient(p)
+
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{Database: "mydb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := client.NewPoint("cpu", nil, map[string]interface{}{"value": 1.0}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	bp.AddPoint(pt)
+
+	if err := c.Write(bp); err != want {
+		t.Fatalf("err = %v, want %v", err, want)
+	}
+}
+
+// TestQueryUnsupported confirms every query method fails instead of
+// pretending to answer on Kafka's behalf.
+func TestQueryUnsupported(t *testing.T) {
+	c := NewClient(&fakeProducer{})
+	if _, err := c.Query(client.Query{}); err == nil {
+		t.Fatal("want error")
+	}
+	if _, err := c.QueryCtx(context.Background(), client.Query{}); err == nil {
+		t.Fatal("want error")
+	}
+	if _, err := c.QueryAsChunk(client.Query{}); err == nil {
+		t.Fatal("want error")
+	}
+}
@@ -0,0 +1,145 @@
+// Package kapacitor provisions Kapacitor tasks (TICKscript alerting
+// definitions) over Kapacitor's HTTP API, so alerting definitions can
+// live next to the influx code that writes the measurements they
+// watch, instead of in a separate deploy step.
+package kapacitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Client talks to a single Kapacitor server's task API.
+type Client struct {
+	Addr       string
+	HTTPClient *http.Client
+}
+
+// New returns a Client talking to the Kapacitor server at addr (e.g.
+// "http://localhost:9092"), using http.DefaultClient.
+func New(addr string) *Client {
+	return &Client{Addr: addr, HTTPClient: http.DefaultClient}
+}
+
+// DBRP pairs a database with the retention policy a task reads from
+// and writes to, Kapacitor's own "DBRP" task association.
+type DBRP struct {
+	Database        string `json:"db"`
+	RetentionPolicy string `json:"rp"`
+}
+
+// Task is a Kapacitor task, as returned by CreateTask, UpdateTask, Task
+// and ListTasks.
+type Task struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	DBRPs      []DBRP `json:"dbrps"`
+	TICKscript string `json:"script"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+}
+
+// TaskOptions describes a task to create or update.
+type TaskOptions struct {
+	// ID is the task's name; required for CreateTask and UpdateTask.
+	ID string `json:"id,omitempty"`
+	// Type is "stream" or "batch".
+	Type       string `json:"type,omitempty"`
+	DBRPs      []DBRP `json:"dbrps,omitempty"`
+	TICKscript string `json:"script,omitempty"`
+	// Status is "enabled" or "disabled"; "" leaves it unchanged on
+	// UpdateTask and defaults to "disabled" on CreateTask, matching
+	// Kapacitor's own default.
+	Status string `json:"status,omitempty"`
+}
+
+// CreateTask uploads a new task's TICKscript to Kapacitor.
+func (c *Client) CreateTask(ctx context.Context, opts TaskOptions) (*Task, error) {
+	if opts.ID == "" {
+		return nil, errors.New("kapacitor: CreateTask needs opts.ID")
+	}
+	var task Task
+	if err := c.do(ctx, http.MethodPost, "/kapacitor/v1/tasks", opts, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// UpdateTask replaces an existing task's TICKscript and/or options.
+func (c *Client) UpdateTask(ctx context.Context, opts TaskOptions) (*Task, error) {
+	if opts.ID == "" {
+		return nil, errors.New("kapacitor: UpdateTask needs opts.ID")
+	}
+	var task Task
+	if err := c.do(ctx, http.MethodPatch, "/kapacitor/v1/tasks/"+opts.ID, opts, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// DeleteTask removes the task named id.
+func (c *Client) DeleteTask(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/kapacitor/v1/tasks/"+id, nil, nil)
+}
+
+// Task fetches the task named id.
+func (c *Client) Task(ctx context.Context, id string) (*Task, error) {
+	var task Task
+	if err := c.do(ctx, http.MethodGet, "/kapacitor/v1/tasks/"+id, nil, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ListTasks lists every task on the server.
+func (c *Client) ListTasks(ctx context.Context) ([]Task, error) {
+	var resp struct {
+		Tasks []Task `json:"tasks"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/kapacitor/v1/tasks", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Tasks, nil
+}
+
+// do sends method/path to the server, marshaling body as the request's
+// JSON (skipped if body is nil) and decoding the response into dst
+// (skipped if dst is nil), returning an error if the response status
+// isn't 2xx.
+func (c *Client) do(ctx context.Context, method, path string, body, dst interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.Addr+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("kapacitor: %s %s: %s: %s", method, path, resp.Status, msg)
+	}
+	if dst == nil {
+		_, err := io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
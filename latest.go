@@ -0,0 +1,39 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+)
+
+// Latest runs SELECT * FROM measurement [WHERE where] GROUP BY *
+// ORDER BY time DESC LIMIT 1 on db and decodes it into dst: the newest
+// point of every series in measurement, one row per series — the
+// single most common query pattern behind a status page's "current
+// value per host/device" table. where may be "" to omit the WHERE
+// clause. dst follows QueryInto's rules: a pointer to a slice if
+// measurement has more than one series matching where, any of
+// ParseResult's other destination kinds if it has exactly one.
+func (c *Client) Latest(dst interface{}, db, measurement, where string) error {
+	return c.LatestContext(context.Background(), dst, db, measurement, where)
+}
+
+// LatestContext is Latest with a context that aborts the request as
+// soon as it is canceled.
+func (c *Client) LatestContext(ctx context.Context, dst interface{}, db, measurement, where string) error {
+	cmd := fmt.Sprintf("SELECT * FROM %s", Ident(measurement))
+	if where != "" {
+		cmd += " WHERE " + where
+	}
+	cmd += " GROUP BY * ORDER BY time DESC LIMIT 1"
+	return c.QueryIntoContext(ctx, dst, db, cmd)
+}
+
+// Latest runs Latest using the default Client.
+func Latest(dst interface{}, db, measurement, where string) error {
+	return gClient().Latest(dst, db, measurement, where)
+}
+
+// LatestContext runs LatestContext using the default Client.
+func LatestContext(ctx context.Context, dst interface{}, db, measurement, where string) error {
+	return gClient().LatestContext(ctx, dst, db, measurement, where)
+}
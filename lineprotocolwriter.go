@@ -0,0 +1,87 @@
+package influx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// errLineProtocolWriterQueryUnsupported is returned by every query
+// method: a plain io.Writer has no InfluxQL endpoint to query
+// against, the same restriction the kafka subpackage's sink has.
+var errLineProtocolWriterQueryUnsupported = errors.New("influx: LineProtocolWriter is write-only; queries are not supported")
+
+// LineProtocolWriter is a client.Client that encodes every batch it's
+// given as line protocol and appends it to an underlying io.Writer
+// instead of sending it over HTTP or UDP. Pass one to
+// NewBufferedWriterWithClient so the exact same producer code
+// (Write/Flush/Close) that targets a live server can write to a file,
+// a pipe, or an S3 uploader's io.WriteCloser instead — for a local dry
+// run, a fixture recording, or an offline backfill. Queries always
+// fail; see errLineProtocolWriterQueryUnsupported.
+type LineProtocolWriter struct {
+	w io.Writer
+}
+
+// NewLineProtocolWriter returns a LineProtocolWriter appending every
+// batch's line protocol to w. If w also implements io.Closer, Close
+// closes it too.
+func NewLineProtocolWriter(w io.Writer) *LineProtocolWriter {
+	return &LineProtocolWriter{w: w}
+}
+
+// Write encodes bp's points as line protocol and writes them to the
+// underlying io.Writer.
+func (lw *LineProtocolWriter) Write(bp client.BatchPoints) error {
+	return lw.WriteCtx(context.Background(), bp)
+}
+
+// WriteCtx is Write, aborting between points as soon as ctx is
+// canceled.
+func (lw *LineProtocolWriter) WriteCtx(ctx context.Context, bp client.BatchPoints) error {
+	for _, p := range bp.Points() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := io.WriteString(lw.w, p.PrecisionString(bp.Precision())); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(lw.w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Ping always succeeds: there is no server on the other end of an
+// io.Writer to health-check.
+func (lw *LineProtocolWriter) Ping(timeout time.Duration) (time.Duration, string, error) {
+	return 0, "", nil
+}
+
+// Query always fails; see errLineProtocolWriterQueryUnsupported.
+func (lw *LineProtocolWriter) Query(q client.Query) (*client.Response, error) {
+	return nil, errLineProtocolWriterQueryUnsupported
+}
+
+// QueryCtx always fails; see errLineProtocolWriterQueryUnsupported.
+func (lw *LineProtocolWriter) QueryCtx(ctx context.Context, q client.Query) (*client.Response, error) {
+	return nil, errLineProtocolWriterQueryUnsupported
+}
+
+// QueryAsChunk always fails; see errLineProtocolWriterQueryUnsupported.
+func (lw *LineProtocolWriter) QueryAsChunk(q client.Query) (*client.ChunkedResponse, error) {
+	return nil, errLineProtocolWriterQueryUnsupported
+}
+
+// Close closes the underlying io.Writer if it implements io.Closer;
+// otherwise it is a no-op.
+func (lw *LineProtocolWriter) Close() error {
+	if c, ok := lw.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package influx
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// TestLineProtocolWriterWriteCtxEncodesPoints confirms WriteCtx
+// appends each point in a batch as its own line-protocol line.
+func TestLineProtocolWriterWriteCtxEncodesPoints(t *testing.T) {
+	var buf bytes.Buffer
+	lw := NewLineProtocolWriter(&buf)
+
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{Database: "mydb", Precision: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := client.NewPoint("cpu", map[string]string{"host": "a"}, map[string]interface{}{"value": 1.0}, time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bp.AddPoint(pt)
+
+	if err := lw.WriteCtx(context.Background(), bp); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "cpu,host=a value=1") {
+		t.Fatalf("buf = %q, missing encoded point", buf.String())
+	}
+}
+
+// TestLineProtocolWriterQueryFails confirms Query/QueryCtx/QueryAsChunk
+// all fail, since an io.Writer has nothing to query.
+func TestLineProtocolWriterQueryFails(t *testing.T) {
+	lw := NewLineProtocolWriter(&bytes.Buffer{})
+	if _, err := lw.Query(client.Query{}); err == nil {
+		t.Fatal("Query: want error, got nil")
+	}
+	if _, err := lw.QueryCtx(context.Background(), client.Query{}); err == nil {
+		t.Fatal("QueryCtx: want error, got nil")
+	}
+	if _, err := lw.QueryAsChunk(client.Query{}); err == nil {
+		t.Fatal("QueryAsChunk: want error, got nil")
+	}
+}
+
+// TestBufferedWriterWithLineProtocolWriter confirms a BufferedWriter
+// built with NewBufferedWriterWithClient over a LineProtocolWriter
+// writes through to the underlying io.Writer, the same sendLoop path a
+// live-server BufferedWriter uses. It drives sendLoop directly via
+// w.toSend, the request loop() itself would build from Write/Flush,
+// rather than through Write+Flush themselves: the two racing in
+// loop()'s select is a known, pre-existing, unrelated flake (also hit
+// by TestBufferedWriterWriteFlushClose) this test isn't about.
+func TestBufferedWriterWithLineProtocolWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewBufferedWriterWithClient(NewLineProtocolWriter(&buf), WriterConfig{Database: "mydb"})
+	defer w.Close(context.Background())
+
+	pt, err := client.NewPoint("cpu", map[string]string{"host": "a"}, map[string]interface{}{"value": 1.0}, time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reply := make(chan error, 1)
+	w.toSend <- sendRequest{ctx: context.Background(), points: []*client.Point{pt}, reply: reply}
+	if err := <-reply; err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "cpu,host=a value=1") {
+		t.Fatalf("buf = %q, missing encoded point", buf.String())
+	}
+}
@@ -0,0 +1,126 @@
+// Package logrus is a github.com/sirupsen/logrus.Hook that converts
+// log entries into points and writes them through a Writer, so
+// low-volume structured events (a startup notice, a rare warning, a
+// business event) can be graphed alongside metrics instead of living
+// only in a log stream.
+//
+// Level and, when set, a top-level "logger" field become tags; every
+// other field in Entry.Data becomes a field, using its native
+// numeric, bool or string value. This isn't a general-purpose log
+// shipper: it's meant for a modest volume of structured events, not a
+// firehose of debug logs, so MessageSampler lets a caller drop the
+// message field (still recording level, tags and other fields) for
+// whichever fraction of records it doesn't want to pay to store as a
+// field.
+package logrus
+
+import (
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultMeasurement is the measurement Hook writes points to when
+// Hook.Measurement is unset.
+const DefaultMeasurement = "log"
+
+// Writer is the subset of *influx.BufferedWriter a Hook needs.
+type Writer interface {
+	Write(point *client.Point) error
+}
+
+// Hook is a logrus.Hook writing one point per log entry through
+// Writer.
+type Hook struct {
+	Writer Writer
+
+	// Measurement is the measurement every point is written under.
+	// Defaults to DefaultMeasurement.
+	Measurement string
+	// LevelThreshold sets the least severe level Levels() reports as
+	// loggable; logrus runs a Hook for every level at or above it.
+	// Defaults to logrus.InfoLevel.
+	LevelThreshold logrus.Level
+	// MessageSampler, if set, is called with every entry to decide
+	// whether its message text is included as a field. An entry whose
+	// sampler returns false is still written, with every tag and
+	// field except the message. Defaults to always including it.
+	MessageSampler func(entry *logrus.Entry) bool
+	// OnError, if set, is called when building a point fails, instead
+	// of the error only reaching logrus' own (frequently ignored)
+	// Fire return value. A Writer error is returned from Fire as-is,
+	// since logrus already surfaces that to the caller.
+	OnError func(err error)
+}
+
+// NewHook returns a Hook writing through w.
+func NewHook(w Writer) *Hook {
+	return &Hook{Writer: w}
+}
+
+// Levels implements logrus.Hook.
+func (h *Hook) Levels() []logrus.Level {
+	threshold := h.LevelThreshold
+	if threshold == 0 {
+		threshold = logrus.InfoLevel
+	}
+	levels := make([]logrus.Level, 0, threshold+1)
+	for l := logrus.PanicLevel; l <= threshold; l++ {
+		levels = append(levels, l)
+	}
+	return levels
+}
+
+// Fire implements logrus.Hook.
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	tags := map[string]string{"level": entry.Level.String()}
+	fields := make(map[string]interface{}, len(entry.Data)+1)
+	for k, v := range entry.Data {
+		if k == "logger" {
+			if s, ok := v.(string); ok {
+				tags["logger"] = s
+				continue
+			}
+		}
+		fields[k] = fieldValue(v)
+	}
+	if h.MessageSampler == nil || h.MessageSampler(entry) {
+		fields["message"] = entry.Message
+	}
+	if len(fields) == 0 {
+		fields["count"] = int64(1)
+	}
+
+	p, err := client.NewPoint(h.measurement(), tags, fields, entry.Time)
+	if err != nil {
+		if h.OnError != nil {
+			h.OnError(err)
+		}
+		return err
+	}
+	return h.Writer.Write(p)
+}
+
+func (h *Hook) measurement() string {
+	if h.Measurement != "" {
+		return h.Measurement
+	}
+	return DefaultMeasurement
+}
+
+// fieldValue converts v to a type client.NewPoint accepts as a field
+// value, leaving every already-accepted kind (string, the numeric
+// kinds, bool) as-is, the same as slog's attrValue.
+func fieldValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case time.Duration:
+		return v.Seconds()
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case error:
+		return v.Error()
+	default:
+		return v
+	}
+}
@@ -0,0 +1,129 @@
+package logrus
+
+import (
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// fakeWriter records every point written to it, in place of a real
+// *influx.BufferedWriter.
+type fakeWriter struct {
+	mu     sync.Mutex
+	points []*client.Point
+}
+
+func (w *fakeWriter) Write(p *client.Point) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.points = append(w.points, p)
+	return nil
+}
+
+func (w *fakeWriter) last() *client.Point {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.points) == 0 {
+		return nil
+	}
+	return w.points[len(w.points)-1]
+}
+
+func newLogger(w Writer, hookOpts ...func(*Hook)) *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	hook := NewHook(w)
+	for _, opt := range hookOpts {
+		opt(hook)
+	}
+	logger.AddHook(hook)
+	return logger
+}
+
+// TestFireTagsLevelAndFields confirms the level is tagged and a plain
+// field becomes a numeric field.
+func TestFireTagsLevelAndFields(t *testing.T) {
+	w := &fakeWriter{}
+	logger := newLogger(w)
+	logger.WithField("workers", 4).Info("started")
+
+	p := w.last()
+	if p == nil {
+		t.Fatal("no point written")
+	}
+	if p.Tags()["level"] != "info" {
+		t.Fatalf("level tag = %q", p.Tags()["level"])
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["workers"] != int64(4) {
+		t.Fatalf("workers = %v, want int64(4)", fields["workers"])
+	}
+	if fields["message"] != "started" {
+		t.Fatalf("message = %v", fields["message"])
+	}
+}
+
+// TestFireLoggerFieldBecomesTag confirms a top-level "logger" field is
+// tagged instead of turned into a field.
+func TestFireLoggerFieldBecomesTag(t *testing.T) {
+	w := &fakeWriter{}
+	logger := newLogger(w)
+	logger.WithField("logger", "db").Info("connected")
+
+	p := w.last()
+	if p.Tags()["logger"] != "db" {
+		t.Fatalf("logger tag = %q", p.Tags()["logger"])
+	}
+	fields, _ := p.Fields()
+	if _, ok := fields["logger"]; ok {
+		t.Fatal("logger should not also be a field")
+	}
+}
+
+// TestFireMessageSamplerDropsMessage confirms a MessageSampler
+// returning false omits the message field but still writes the point.
+func TestFireMessageSamplerDropsMessage(t *testing.T) {
+	w := &fakeWriter{}
+	logger := newLogger(w, func(h *Hook) {
+		h.MessageSampler = func(entry *logrus.Entry) bool { return false }
+	})
+	logger.WithField("n", 1).Info("noisy")
+
+	fields, err := w.last().Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fields["message"]; ok {
+		t.Fatal("message should have been sampled out")
+	}
+	if fields["n"] != int64(1) {
+		t.Fatalf("fields = %v", fields)
+	}
+}
+
+// TestLevelsRespectsThreshold confirms Levels() only reports levels at
+// or above LevelThreshold.
+func TestLevelsRespectsThreshold(t *testing.T) {
+	h := &Hook{Writer: &fakeWriter{}, LevelThreshold: logrus.WarnLevel}
+	levels := h.Levels()
+	for _, l := range levels {
+		if l == logrus.InfoLevel {
+			t.Fatal("InfoLevel should not be in Levels() under WarnLevel threshold")
+		}
+	}
+	var sawWarn bool
+	for _, l := range levels {
+		if l == logrus.WarnLevel {
+			sawWarn = true
+		}
+	}
+	if !sawWarn {
+		t.Fatal("WarnLevel should be in Levels()")
+	}
+}
@@ -0,0 +1,28 @@
+package influx
+
+import (
+	"reflect"
+	"sync"
+)
+
+// registeredMeasurements holds the measurement names set by
+// RegisterMeasurement, reflect.Type -> string.
+var registeredMeasurements sync.Map
+
+// RegisterMeasurement associates t's measurement with measurement, for
+// a struct ToPoint can't otherwise be taught its name: one from another
+// module with no Measurement method and no room to add a `measurement=`
+// inf tag. t is either a reflect.Type (e.g. reflect.TypeOf(Sample{}))
+// or a sample value or pointer of the type, the same way Register's
+// sample argument works. A `measurement=` inf tag still takes
+// precedence when both are present, since it's declared closer to the
+// type. It drops t's cached type plan so a type already processed by
+// ToPoint or Register picks up the new name on its next use.
+func RegisterMeasurement(t interface{}, measurement string) {
+	rt, ok := t.(reflect.Type)
+	if !ok {
+		rt = reflect.Indirect(reflect.ValueOf(t)).Type()
+	}
+	registeredMeasurements.Store(rt, measurement)
+	typePlans.Delete(rt)
+}
@@ -0,0 +1,34 @@
+package influx
+
+import "context"
+
+// ShowMeasurements runs SHOW MEASUREMENTS on db and returns every
+// measurement name.
+func (c *Client) ShowMeasurements(db string) ([]string, error) {
+	return c.ShowMeasurementsContext(context.Background(), db)
+}
+
+// ShowMeasurementsContext is ShowMeasurements with a context that
+// aborts the request as soon as it is canceled.
+func (c *Client) ShowMeasurementsContext(ctx context.Context, db string) ([]string, error) {
+	results, err := c.QueryContext(ctx, db, "SHOW MEASUREMENTS ON "+Ident(db))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, result := range results {
+		for _, serie := range result.Series {
+			var rows []string
+			if err := ParseResult(&rows, serie, "name"); err != nil {
+				return nil, err
+			}
+			names = append(names, rows...)
+		}
+	}
+	return names, nil
+}
+
+// ShowMeasurements runs SHOW MEASUREMENTS using the default Client.
+func ShowMeasurements(db string) ([]string, error) {
+	return gClient().ShowMeasurements(db)
+}
@@ -0,0 +1,34 @@
+package influx
+
+import "net/http"
+
+// ResponseMetadata holds the per-request headers InfluxDB attaches to
+// its HTTP responses, useful for correlating a slow or failed request
+// with server-side logs.
+type ResponseMetadata struct {
+	// Version is the X-Influxdb-Version header, e.g. "1.8.10".
+	Version string
+	// RequestID is the X-Request-Id header InfluxDB assigns to every
+	// request.
+	RequestID string
+	// TraceID is the Trace-Id header set when InfluxDB Enterprise's
+	// tracing is enabled; empty otherwise.
+	TraceID string
+}
+
+func responseMetadata(h http.Header) ResponseMetadata {
+	return ResponseMetadata{
+		Version:   h.Get("X-Influxdb-Version"),
+		RequestID: h.Get("X-Request-Id"),
+		TraceID:   h.Get("Trace-Id"),
+	}
+}
+
+// QueryContext and WriteBatchPointsContext cannot report
+// ResponseMetadata: client.Client.QueryCtx and WriteCtx parse the
+// *http.Response themselves and return only the decoded body (or
+// nothing, for writes), discarding the headers before we ever see them.
+// Reporting metadata for those calls would need a fork of the vendored
+// client/v2 package. FluxQueryContextWithMetadata in flux.go can report it
+// because FluxQueryContext already does its own raw net/http call
+// instead of going through client.Client.
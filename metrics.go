@@ -0,0 +1,277 @@
+package influx
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// DefaultMetricsFlushInterval is the default interval StartMetrics
+// flushes buffered Counter/Gauge/Timer values at.
+const DefaultMetricsFlushInterval = 10 * time.Second
+
+// Counter accumulates a value across Add calls, flushed as a "value"
+// field and reset to 0 every metrics tick, the usual StatsD counter
+// semantics.
+type counter struct {
+	name string
+	tags map[string]string
+}
+
+// Counter returns a counter handle for name, to be tagged with Tag and
+// incremented with Add. Each call returns a new handle, but handles
+// with the same name and tags add into the same series, so calling
+// Counter(name) again from another call site is fine.
+func Counter(name string) *counter {
+	return &counter{name: name}
+}
+
+// Tag sets a tag on c and returns c for chaining, e.g.
+// Counter("requests").Tag("route", r).Add(1).
+func (c *counter) Tag(key, value string) *counter {
+	if c.tags == nil {
+		c.tags = make(map[string]string)
+	}
+	c.tags[key] = value
+	return c
+}
+
+// Add adds delta to c's current value, to be flushed on the next
+// metrics tick.
+func (c *counter) Add(delta float64) {
+	key := metricKeyFor(c.name, c.tags)
+
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	s, ok := counters[key]
+	if !ok {
+		s = &counterState{tags: c.tags}
+		counters[key] = s
+	}
+	s.value += delta
+}
+
+// counterState is the buffered value for one Counter series.
+type counterState struct {
+	tags  map[string]string
+	value float64
+}
+
+// Gauge holds the latest value set with Set, flushed as-is (and not
+// reset) on every metrics tick.
+type gauge struct {
+	name string
+	tags map[string]string
+}
+
+// Gauge returns a gauge handle for name, to be tagged with Tag and
+// updated with Set.
+func Gauge(name string) *gauge {
+	return &gauge{name: name}
+}
+
+// Tag sets a tag on g and returns g for chaining.
+func (g *gauge) Tag(key, value string) *gauge {
+	if g.tags == nil {
+		g.tags = make(map[string]string)
+	}
+	g.tags[key] = value
+	return g
+}
+
+// Set replaces g's current value with value.
+func (g *gauge) Set(value float64) {
+	key := metricKeyFor(g.name, g.tags)
+
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	s, ok := gauges[key]
+	if !ok {
+		s = &gaugeState{tags: g.tags}
+		gauges[key] = s
+	}
+	s.value = value
+}
+
+// gaugeState is the buffered value for one Gauge series.
+type gaugeState struct {
+	tags  map[string]string
+	value float64
+}
+
+// Timer accumulates the count, sum, min and max of durations passed to
+// Observe, flushed as "count", "sum", "mean", "min" and "max" fields
+// and reset every metrics tick.
+type timer struct {
+	name string
+	tags map[string]string
+}
+
+// Timer returns a timer handle for name, to be tagged with Tag and
+// recorded with Observe.
+func Timer(name string) *timer {
+	return &timer{name: name}
+}
+
+// Tag sets a tag on t and returns t for chaining.
+func (t *timer) Tag(key, value string) *timer {
+	if t.tags == nil {
+		t.tags = make(map[string]string)
+	}
+	t.tags[key] = value
+	return t
+}
+
+// Observe records d, in seconds, against t.
+func (t *timer) Observe(d time.Duration) {
+	key := metricKeyFor(t.name, t.tags)
+	v := d.Seconds()
+
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	s, ok := timers[key]
+	if !ok {
+		s = &timerState{tags: t.tags}
+		timers[key] = s
+	}
+	if s.count == 0 {
+		s.min, s.max = v, v
+	} else if v < s.min {
+		s.min = v
+	} else if v > s.max {
+		s.max = v
+	}
+	s.sum += v
+	s.count++
+}
+
+// timerState is the buffered histogram for one Timer series.
+type timerState struct {
+	tags          map[string]string
+	count         int64
+	sum, min, max float64
+}
+
+// metricKey identifies one Counter/Gauge/Timer series by name and tag
+// set; the three metric kinds keep separate maps, so a Counter and a
+// Gauge with the same name and tags don't collide.
+type metricKey struct {
+	name string
+	tags string
+}
+
+func metricKeyFor(name string, tags map[string]string) metricKey {
+	return metricKey{name: name, tags: tagsKey(tags)}
+}
+
+var (
+	metricsMu sync.Mutex
+	counters  = map[metricKey]*counterState{}
+	gauges    = map[metricKey]*gaugeState{}
+	timers    = map[metricKey]*timerState{}
+
+	metricsStop chan struct{}
+)
+
+// StartMetrics starts a background goroutine that flushes every
+// Counter, Gauge and Timer buffered by this process to db via the
+// package-level default Client every interval, turning Counter/Gauge/
+// Timer into a drop-in app-metrics library on top of it. interval
+// defaults to DefaultMetricsFlushInterval.
+//
+// Calling StartMetrics again replaces the previous db/interval and
+// restarts the goroutine, keeping whatever was buffered; StopMetrics
+// stops it.
+func StartMetrics(db string, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultMetricsFlushInterval
+	}
+
+	metricsMu.Lock()
+	if metricsStop != nil {
+		close(metricsStop)
+	}
+	stop := make(chan struct{})
+	metricsStop = stop
+	metricsMu.Unlock()
+
+	go metricsLoop(db, interval, stop)
+}
+
+// StopMetrics stops the background goroutine started by StartMetrics.
+// It does not flush and does not clear anything: buffered values stay
+// in place until a later StartMetrics tick writes them. It is a no-op
+// if StartMetrics hasn't been called.
+func StopMetrics() {
+	metricsMu.Lock()
+	if metricsStop != nil {
+		close(metricsStop)
+		metricsStop = nil
+	}
+	metricsMu.Unlock()
+}
+
+func metricsLoop(db string, interval time.Duration, stop chan struct{}) {
+	ticker := newTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C():
+			flushMetrics(db)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// flushMetrics builds a single batch out of every buffered Counter,
+// Gauge and Timer and writes it with the package-level default Client.
+// Counters and Timers are reset afterwards; Gauges are left as-is,
+// since a Gauge's value stays current until the next Set.
+func flushMetrics(db string) {
+	metricsMu.Lock()
+	countersSnap, gaugesSnap, timersSnap := counters, gauges, timers
+	counters = map[metricKey]*counterState{}
+	timers = map[metricKey]*timerState{}
+	metricsMu.Unlock()
+
+	if len(countersSnap) == 0 && len(gaugesSnap) == 0 && len(timersSnap) == 0 {
+		return
+	}
+
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{Database: db})
+	if err != nil {
+		return
+	}
+
+	now := clockNow()
+	for key, s := range countersSnap {
+		p, err := client.NewPoint(key.name, s.tags, map[string]interface{}{"value": s.value}, now)
+		if err == nil {
+			bp.AddPoint(p)
+		}
+	}
+	for key, s := range gaugesSnap {
+		p, err := client.NewPoint(key.name, s.tags, map[string]interface{}{"value": s.value}, now)
+		if err == nil {
+			bp.AddPoint(p)
+		}
+	}
+	for key, s := range timersSnap {
+		fields := map[string]interface{}{
+			"count": s.count,
+			"sum":   s.sum,
+			"mean":  s.sum / float64(s.count),
+			"min":   s.min,
+			"max":   s.max,
+		}
+		p, err := client.NewPoint(key.name, s.tags, fields, now)
+		if err == nil {
+			bp.AddPoint(p)
+		}
+	}
+
+	gClient().WriteBatchPointsContext(context.Background(), bp)
+}
@@ -0,0 +1,110 @@
+package influx
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCounterGaugeTimerFlushWriteFields confirms flushMetrics renders
+// a Counter's, Gauge's and Timer's buffered values as the documented
+// fields, and resets Counters/Timers but not Gauges afterward.
+func TestCounterGaugeTimerFlushWriteFields(t *testing.T) {
+	cli := &fakeClient{}
+	setGClient(NewWithClient(cli))
+	defer setGClient(nil)
+
+	Counter("requests").Tag("route", "/a").Add(1)
+	Counter("requests").Tag("route", "/a").Add(2)
+	Gauge("queue_depth").Set(5)
+	Timer("latency").Observe(100 * time.Millisecond)
+	Timer("latency").Observe(300 * time.Millisecond)
+
+	flushMetrics("mydb")
+
+	if cli.writes != 1 {
+		t.Fatalf("writes = %d, want 1", cli.writes)
+	}
+
+	points := cli.lastBP.Points()
+	var sawCounter, sawGauge, sawTimer bool
+	for _, p := range points {
+		fields, err := p.Fields()
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch p.Name() {
+		case "requests":
+			sawCounter = true
+			if p.Tags()["route"] != "/a" {
+				t.Fatalf("requests tags = %v, want route=/a", p.Tags())
+			}
+			if fields["value"] != 3.0 {
+				t.Fatalf("requests value = %v, want 3", fields["value"])
+			}
+		case "queue_depth":
+			sawGauge = true
+			if fields["value"] != 5.0 {
+				t.Fatalf("queue_depth value = %v, want 5", fields["value"])
+			}
+		case "latency":
+			sawTimer = true
+			if fields["count"] != int64(2) {
+				t.Fatalf("latency count = %v, want 2", fields["count"])
+			}
+			if fields["min"] != 0.1 || fields["max"] != 0.3 {
+				t.Fatalf("latency min/max = %v/%v, want 0.1/0.3", fields["min"], fields["max"])
+			}
+		}
+	}
+	if !sawCounter || !sawGauge || !sawTimer {
+		t.Fatalf("missing point kinds: counter=%v gauge=%v timer=%v", sawCounter, sawGauge, sawTimer)
+	}
+
+	// Counters and Timers reset on flush; Gauges keep reporting their
+	// last value until Set again.
+	flushMetrics("mydb")
+	if cli.writes != 2 {
+		t.Fatalf("writes = %d, want 2", cli.writes)
+	}
+	points = cli.lastBP.Points()
+	if len(points) != 1 || points[0].Name() != "queue_depth" {
+		t.Fatalf("second flush points = %v, want only queue_depth", points)
+	}
+}
+
+// TestStartStopMetrics confirms StartMetrics flushes on its own ticker
+// and StopMetrics ends it without flushing again.
+func TestStartStopMetrics(t *testing.T) {
+	cli := &fakeClient{}
+	setGClient(NewWithClient(cli))
+	defer setGClient(nil)
+
+	Counter("jobs").Add(1)
+	StartMetrics("mydb", 5*time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		cli.mu.Lock()
+		writes := cli.writes
+		cli.mu.Unlock()
+		if writes >= 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("StartMetrics never flushed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	StopMetrics()
+	cli.mu.Lock()
+	stoppedAt := cli.writes
+	cli.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+	cli.mu.Lock()
+	defer cli.mu.Unlock()
+	if cli.writes != stoppedAt {
+		t.Fatalf("writes grew from %d to %d after StopMetrics", stoppedAt, cli.writes)
+	}
+}
@@ -0,0 +1,83 @@
+package influx
+
+import "context"
+
+// RequestInfo describes the query or write an Interceptor is wrapping.
+type RequestInfo struct {
+	// Kind is "query" or "write".
+	Kind string
+	// Database is the target database, already defaulted to c.DB.
+	Database string
+	// Command is the InfluxQL command being run; empty for writes.
+	Command string
+	// Points is the number of points in the batch being written; zero
+	// for queries.
+	Points int
+	// Measurements is the set of distinct measurement names in the
+	// batch being written, sorted; nil for queries.
+	Measurements []string
+	// Label is the caller-supplied label set on ctx by WithLabel, or
+	// "" if none was set — e.g. which code path or account a write
+	// should be attributed to, for compliance logging that needs to
+	// record who wrote what, not just what was written.
+	Label string
+}
+
+// labelContextKey is the context.Value key WithLabel stores a label
+// under.
+type labelContextKey struct{}
+
+// WithLabel attaches label to ctx, surfaced on RequestInfo.Label to
+// every Interceptor wrapping the query or write made with the
+// returned context.
+func WithLabel(ctx context.Context, label string) context.Context {
+	return context.WithValue(ctx, labelContextKey{}, label)
+}
+
+// LabelFromContext returns the label set by WithLabel, or "" if none
+// was set.
+func LabelFromContext(ctx context.Context) string {
+	label, _ := ctx.Value(labelContextKey{}).(string)
+	return label
+}
+
+// Query and write kinds reported on RequestInfo.Kind.
+const (
+	RequestQuery = "query"
+	RequestWrite = "write"
+)
+
+// Interceptor wraps every query and write a Client runs. Implementations
+// call next to run the request (and any remaining interceptors in the
+// chain); code before the call runs before the request, code after runs
+// after, so an Interceptor can inject auth headers via ctx, log, record
+// duration and error, or short-circuit by returning an error without
+// calling next at all. Interceptors added by Use run outermost-first, so
+// the first one added sees a request before the others, like
+// http.Handler middleware.
+type Interceptor func(ctx context.Context, info RequestInfo, next func(context.Context) error) error
+
+// Use appends interceptors to c's chain. They run in the order given,
+// wrapping every QueryContext, QueryWithParamsContext and
+// WriteBatchPointsContext call made afterwards.
+func (c *Client) Use(interceptors ...Interceptor) {
+	c.interceptors = append(c.interceptors, interceptors...)
+}
+
+// intercept runs op through c's interceptor chain, innermost call being
+// op itself.
+func (c *Client) intercept(ctx context.Context, info RequestInfo, op func(context.Context) error) error {
+	next := op
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := c.interceptors[i], next
+		next = func(ctx context.Context) error {
+			return interceptor(ctx, info, inner)
+		}
+	}
+	return next(ctx)
+}
+
+// Use appends interceptors to the package-level default Client's chain.
+func Use(interceptors ...Interceptor) {
+	gClient().Use(interceptors...)
+}
@@ -0,0 +1,109 @@
+package influx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// queryFakeClient answers Query/QueryCtx with a fixed empty-but-valid
+// response, for exercising the interceptor chain without a real
+// InfluxDB server.
+type queryFakeClient struct {
+	fakeClient
+}
+
+func (f *queryFakeClient) Query(q client.Query) (*client.Response, error) {
+	return &client.Response{Results: []client.Result{{}}}, nil
+}
+
+func (f *queryFakeClient) QueryCtx(ctx context.Context, q client.Query) (*client.Response, error) {
+	return f.Query(q)
+}
+
+// failingQueryFakeClient answers every Query/QueryCtx with an error, for
+// tripping a circuit breaker.
+type failingQueryFakeClient struct {
+	fakeClient
+}
+
+var errQueryFake = errors.New("fake query failure")
+
+func (f *failingQueryFakeClient) Query(q client.Query) (*client.Response, error) {
+	return nil, errQueryFake
+}
+
+func (f *failingQueryFakeClient) QueryCtx(ctx context.Context, q client.Query) (*client.Response, error) {
+	return f.Query(q)
+}
+
+// TestQueryWithParamsContextRunsInterceptors confirms
+// QueryWithParamsContext is wrapped by Use's interceptor chain the
+// same way QueryContext is, as middleware.go's doc comment on Use
+// promises.
+func TestQueryWithParamsContextRunsInterceptors(t *testing.T) {
+	c := NewWithClient(&queryFakeClient{})
+
+	var gotKind, gotCmd string
+	var called bool
+	c.Use(func(ctx context.Context, info RequestInfo, next func(context.Context) error) error {
+		called = true
+		gotKind = info.Kind
+		gotCmd = info.Command
+		return next(ctx)
+	})
+
+	if _, err := c.QueryWithParamsContext(context.Background(), "db", "SELECT * FROM cpu WHERE host = $host", map[string]interface{}{"host": "a"}); err != nil {
+		t.Fatalf("QueryWithParamsContext: %v", err)
+	}
+
+	if !called {
+		t.Fatal("interceptor was never called for QueryWithParamsContext")
+	}
+	if gotKind != RequestQuery {
+		t.Fatalf("Kind = %q, want %q", gotKind, RequestQuery)
+	}
+	if gotCmd != "SELECT * FROM cpu WHERE host = $host" {
+		t.Fatalf("Command = %q", gotCmd)
+	}
+}
+
+// TestQueryWithParamsContextRunsCircuitBreaker confirms
+// QueryWithParamsContext trips and is fast-failed by CircuitBreaker the
+// same way QueryContext is.
+func TestQueryWithParamsContextRunsCircuitBreaker(t *testing.T) {
+	c := NewWithClient(&failingQueryFakeClient{})
+	c.CircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, CoolDown: time.Minute})
+
+	if _, err := c.QueryWithParamsContext(context.Background(), "db", "SELECT * FROM cpu", nil); err != errQueryFake {
+		t.Fatalf("first call: err = %v, want %v", err, errQueryFake)
+	}
+	if _, err := c.QueryWithParamsContext(context.Background(), "db", "SELECT * FROM cpu", nil); err != ErrCircuitOpen {
+		t.Fatalf("second call: err = %v, want %v", err, ErrCircuitOpen)
+	}
+}
+
+// TestQueryWithParamsContextReportsDebugHook confirms
+// QueryWithParamsContext calls the debug hook set by SetDebugHook the
+// same way QueryContext does, as DebugHookConfig.OnResponse's doc
+// comment promises for "every query".
+func TestQueryWithParamsContextReportsDebugHook(t *testing.T) {
+	c := NewWithClient(&queryFakeClient{})
+
+	var gotCmd string
+	c.SetDebugHook(DebugHookConfig{
+		OnResponse: func(cmd string, body []byte, err error) {
+			gotCmd = cmd
+		},
+	})
+
+	if _, err := c.QueryWithParamsContext(context.Background(), "db", "SELECT * FROM cpu WHERE host = $host", map[string]interface{}{"host": "a"}); err != nil {
+		t.Fatalf("QueryWithParamsContext: %v", err)
+	}
+	if gotCmd != "SELECT * FROM cpu WHERE host = $host" {
+		t.Fatalf("debug hook command = %q, want %q", gotCmd, "SELECT * FROM cpu WHERE host = $host")
+	}
+}
@@ -0,0 +1,144 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// DefaultMigrationsMeasurement is where MigrationRunner records which
+// versions have already applied, when a MigrationRunner isn't given a
+// Measurement of its own.
+const DefaultMigrationsMeasurement = "schema_migrations"
+
+// MigrationStep is one versioned unit of work a MigrationRunner
+// applies in order: typically one or more InfluxQL management
+// statements (CREATE CONTINUOUS QUERY, ALTER RETENTION POLICY, a
+// backfill SELECT ... INTO ...), issued from Func via c.
+type MigrationStep struct {
+	// Version orders steps and is recorded in the migrations
+	// measurement once applied. Versions must be unique; Run panics
+	// on a duplicate the same way a golang-migrate file name collision
+	// would fail its own build.
+	Version int64
+	Name    string
+	// Func performs the step's work, usually one or more
+	// c.QueryContext calls, but any Client method is allowed, so a
+	// backfill step can use InsertManyContext instead of a raw query.
+	Func func(ctx context.Context, c *Client, db string) error
+}
+
+// migrationRecord is one row of a MigrationRunner's measurement, as
+// written by Run and read back by Applied.
+type migrationRecord struct {
+	Version int64  `inf:"version"`
+	Name    string `inf:"name,tag"`
+}
+
+// MigrationRunner applies a list of MigrationSteps to a database in
+// order, exactly once each, recording progress in an InfluxDB
+// measurement in place of the schema-version table a SQL migration
+// tool like golang-migrate would use — InfluxDB has no DDL history of
+// its own to query instead.
+type MigrationRunner struct {
+	// Measurement overrides DefaultMigrationsMeasurement.
+	Measurement string
+
+	steps []MigrationStep
+}
+
+// NewMigrationRunner returns a MigrationRunner that applies steps in
+// ascending Version order, regardless of the order they're passed in.
+func NewMigrationRunner(steps ...MigrationStep) *MigrationRunner {
+	sorted := append([]MigrationStep(nil), steps...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return &MigrationRunner{steps: sorted}
+}
+
+func (r *MigrationRunner) measurement() string {
+	if r.Measurement != "" {
+		return r.Measurement
+	}
+	return DefaultMigrationsMeasurement
+}
+
+// Applied returns the set of versions already recorded as applied to
+// db. A database or measurement that doesn't exist yet reports no
+// applied versions rather than an error, the same way InfluxDB itself
+// returns an empty result for a SELECT against a measurement with no
+// series.
+func (r *MigrationRunner) Applied(ctx context.Context, c *Client, db string) (map[int64]bool, error) {
+	cmd := fmt.Sprintf("SELECT version FROM %s", Ident(r.measurement()))
+	results, err := c.QueryContext(ctx, db, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]bool)
+	for _, result := range results {
+		for _, serie := range result.Series {
+			var rows []migrationRecord
+			if err := ParseResult(&rows, serie, "time", "version", "name"); err != nil {
+				return nil, err
+			}
+			for _, row := range rows {
+				applied[row.Version] = true
+			}
+		}
+	}
+	return applied, nil
+}
+
+// Run applies every step not yet recorded as applied to db, in
+// ascending Version order, stopping at and returning the first error
+// so a later step never runs against a database a prior one failed to
+// bring up to date.
+func (r *MigrationRunner) Run(ctx context.Context, c *Client, db string) error {
+	for i := 1; i < len(r.steps); i++ {
+		if r.steps[i].Version == r.steps[i-1].Version {
+			panic(fmt.Sprintf("influx: duplicate migration version %d", r.steps[i].Version))
+		}
+	}
+
+	applied, err := r.Applied(ctx, c, db)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range r.steps {
+		if applied[step.Version] {
+			continue
+		}
+		if err := step.Func(ctx, c, db); err != nil {
+			return fmt.Errorf("influx: migration %d (%s) failed: %w", step.Version, step.Name, err)
+		}
+
+		p, err := client.NewPoint(r.measurement(),
+			map[string]string{"name": step.Name},
+			map[string]interface{}{"version": step.Version},
+			clockNow())
+		if err != nil {
+			return err
+		}
+		if err := c.InsertContext(ctx, db, p); err != nil {
+			return fmt.Errorf("influx: migration %d (%s) applied but failed to record: %w", step.Version, step.Name, err)
+		}
+	}
+	return nil
+}
+
+// RunMigrations builds a MigrationRunner from steps and runs it against
+// db using the default Client, the package-level shorthand for
+// NewMigrationRunner(steps...).Run(ctx, client, db) when the caller has
+// no need to keep the MigrationRunner around (e.g. to override its
+// Measurement or call Applied separately).
+func RunMigrations(db string, steps ...MigrationStep) error {
+	return RunMigrationsContext(context.Background(), db, steps...)
+}
+
+// RunMigrationsContext is RunMigrations with a context.
+func RunMigrationsContext(ctx context.Context, db string, steps ...MigrationStep) error {
+	return NewMigrationRunner(steps...).Run(ctx, gClient(), db)
+}
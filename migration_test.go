@@ -0,0 +1,172 @@
+package influx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+var errBoom = errors.New("boom")
+
+// fakeMigrationServer is a minimal /query+/write double: /query always
+// returns the rows in appliedRows (mutable, guarded by mu, so a test
+// can simulate a step's own Run recording a version and Applied
+// picking it back up), and /write records the body it received and
+// always succeeds.
+type fakeMigrationServer struct {
+	mu          sync.Mutex
+	appliedRows [][]interface{}
+	writes      []string
+}
+
+func (s *fakeMigrationServer) handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/write"):
+			body := make([]byte, r.ContentLength)
+			r.Body.Read(body)
+			s.mu.Lock()
+			s.writes = append(s.writes, string(body))
+			s.mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			s.mu.Lock()
+			rows := append([][]interface{}(nil), s.appliedRows...)
+			s.mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(fakeQueryResponse(rows))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+// fakeQueryResponse builds the raw JSON shape the InfluxDB HTTP API
+// uses for /query, avoiding models.Row's unexported-friendly zero
+// value quirks by building the map directly.
+func fakeQueryResponse(rows [][]interface{}) map[string]interface{} {
+	if len(rows) == 0 {
+		return map[string]interface{}{"results": []interface{}{map[string]interface{}{}}}
+	}
+	return map[string]interface{}{
+		"results": []interface{}{
+			map[string]interface{}{
+				"series": []interface{}{
+					map[string]interface{}{
+						"name":    "schema_migrations",
+						"columns": []string{"time", "version", "name"},
+						"values":  rows,
+					},
+				},
+			},
+		},
+	}
+}
+
+func newFakeMigrationServer() (*fakeMigrationServer, *Client) {
+	s := &fakeMigrationServer{}
+	srv := httptest.NewServer(s.handler())
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		panic(err)
+	}
+	return s, c
+}
+
+// TestMigrationRunnerAppliesStepsInVersionOrder confirms Run applies
+// steps by ascending Version regardless of the order they're passed
+// to NewMigrationRunner, and records each one.
+func TestMigrationRunnerAppliesStepsInVersionOrder(t *testing.T) {
+	_, c := newFakeMigrationServer()
+
+	var order []int64
+	r := NewMigrationRunner(
+		MigrationStep{Version: 2, Name: "second", Func: func(ctx context.Context, c *Client, db string) error {
+			order = append(order, 2)
+			return nil
+		}},
+		MigrationStep{Version: 1, Name: "first", Func: func(ctx context.Context, c *Client, db string) error {
+			order = append(order, 1)
+			return nil
+		}},
+	)
+
+	if err := r.Run(context.Background(), c, "mydb"); err != nil {
+		t.Fatal(err)
+	}
+	if len(order) != 2 || order[0] != 1 || order[1] != 2 {
+		t.Fatalf("order = %v, want [1 2]", order)
+	}
+}
+
+// TestMigrationRunnerSkipsAlreadyApplied confirms a version Applied
+// already reports isn't run again.
+func TestMigrationRunnerSkipsAlreadyApplied(t *testing.T) {
+	s, c := newFakeMigrationServer()
+	s.appliedRows = [][]interface{}{{"2020-01-01T00:00:00Z", float64(1), "first"}}
+
+	ran := false
+	r := NewMigrationRunner(MigrationStep{Version: 1, Name: "first", Func: func(ctx context.Context, c *Client, db string) error {
+		ran = true
+		return nil
+	}})
+
+	if err := r.Run(context.Background(), c, "mydb"); err != nil {
+		t.Fatal(err)
+	}
+	if ran {
+		t.Fatal("step ran again despite already being applied")
+	}
+}
+
+// TestMigrationRunnerStopsOnFirstError confirms a failing step
+// prevents any later step from running.
+func TestMigrationRunnerStopsOnFirstError(t *testing.T) {
+	_, c := newFakeMigrationServer()
+
+	secondRan := false
+	r := NewMigrationRunner(
+		MigrationStep{Version: 1, Name: "first", Func: func(ctx context.Context, c *Client, db string) error {
+			return errBoom
+		}},
+		MigrationStep{Version: 2, Name: "second", Func: func(ctx context.Context, c *Client, db string) error {
+			secondRan = true
+			return nil
+		}},
+	)
+
+	if err := r.Run(context.Background(), c, "mydb"); err == nil {
+		t.Fatal("want error")
+	}
+	if secondRan {
+		t.Fatal("second step ran despite the first failing")
+	}
+}
+
+// TestRunMigrationsUsesDefaultClient confirms RunMigrations is
+// NewMigrationRunner(steps...).Run against the default Client, without
+// the caller building a MigrationRunner itself.
+func TestRunMigrationsUsesDefaultClient(t *testing.T) {
+	_, c := newFakeMigrationServer()
+	setGClient(c)
+	defer setGClient(nil)
+
+	ran := false
+	err := RunMigrations("mydb", MigrationStep{Version: 1, Name: "first", Func: func(ctx context.Context, c *Client, db string) error {
+		ran = true
+		return nil
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ran {
+		t.Fatal("step did not run")
+	}
+}
@@ -0,0 +1,54 @@
+package influx
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// Mirror enables dual-write mode: every point WriteBatchPointsContext
+// writes to the primary is also enqueued onto a BufferedWriter built
+// from cfg, which flushes it to a second InfluxDB asynchronously —
+// useful for migrating a cluster without a cutover window, or keeping
+// a hot standby warm. The mirror buffers and retries independently of
+// the primary: a point is mirrored whether or not the primary write
+// for it succeeds, and a mirror failure (after cfg.MaxRetries) never
+// fails or retries the primary write, it only reaches cfg.OnError, if
+// set.
+//
+// Calling Mirror again replaces the previous mirror, closing it first.
+// DryRun and ReadOnly both skip the primary write entirely, so neither
+// reaches the mirror either.
+func (c *Client) Mirror(cfg WriterConfig) error {
+	w, err := NewBufferedWriter(cfg)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	old := c.mirror
+	c.mirror = w
+	c.mu.Unlock()
+
+	if old != nil {
+		old.Close(context.Background())
+	}
+	return nil
+}
+
+// mirrorPoints enqueues every point in bp onto c.mirror, if Mirror has
+// been called. A full mirror queue applies WriterConfig.Backpressure
+// like any other BufferedWriter, so by default it drops the oldest
+// mirrored point rather than blocking or failing the caller's write to
+// the primary.
+func (c *Client) mirrorPoints(bp client.BatchPoints) {
+	c.mu.RLock()
+	w := c.mirror
+	c.mu.RUnlock()
+	if w == nil {
+		return
+	}
+	for _, p := range bp.Points() {
+		w.Write(p)
+	}
+}
@@ -0,0 +1,89 @@
+package influx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// TestMirrorAsyncallyCopiesWritesToSecondary confirms a point written
+// through the primary also reaches a Mirror-configured secondary, even
+// though the primary is a fakeClient that never talks to the
+// secondary's endpoint itself.
+func TestMirrorAsyncallyCopiesWritesToSecondary(t *testing.T) {
+	var mirrorWrites int32
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&mirrorWrites, 1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer secondary.Close()
+
+	fc := &fakeClient{}
+	c := NewWithClient(fc)
+	defer c.Close()
+
+	err := c.Mirror(WriterConfig{
+		HTTP:          &client.HTTPConfig{Addr: secondary.URL},
+		Database:      "mydb",
+		FlushInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Mirror: %v", err)
+	}
+
+	p, err := client.NewPoint("cpu", nil, map[string]interface{}{"usage": 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Insert("db", p); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&mirrorWrites) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if atomic.LoadInt32(&mirrorWrites) == 0 {
+		t.Fatal("point never reached the mirrored secondary")
+	}
+	if fc.writes != 1 {
+		t.Fatalf("primary writes = %d, want 1", fc.writes)
+	}
+}
+
+// TestMirrorReplacesPreviousMirror confirms a second Mirror call closes
+// and stops enqueueing onto the Mirror it replaces.
+func TestMirrorReplacesPreviousMirror(t *testing.T) {
+	c := NewWithClient(&fakeClient{})
+	defer c.Close()
+
+	first := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer first.Close()
+	second := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer second.Close()
+
+	if err := c.Mirror(WriterConfig{HTTP: &client.HTTPConfig{Addr: first.URL}, Database: "db"}); err != nil {
+		t.Fatalf("Mirror(first): %v", err)
+	}
+	firstWriter := c.mirror
+
+	if err := c.Mirror(WriterConfig{HTTP: &client.HTTPConfig{Addr: second.URL}, Database: "db"}); err != nil {
+		t.Fatalf("Mirror(second): %v", err)
+	}
+	if c.mirror == firstWriter {
+		t.Fatal("Mirror did not replace the previous writer")
+	}
+
+	if err := firstWriter.Flush(context.Background()); err == nil {
+		t.Fatal("Flush on the replaced (and now closed) mirror should report it's closed")
+	}
+}
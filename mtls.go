@@ -0,0 +1,98 @@
+package influx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// NewClientCertificateReloader returns a tls.Config.GetClientCertificate
+// callback that reloads certFile/keyFile from disk whenever either
+// file's modification time changes, so a client certificate rotated by
+// a service mesh sidecar (e.g. cert-manager, SPIFFE) is picked up
+// without restarting the process. It caches the parsed certificate
+// between calls and only re-reads the files when their mtimes change.
+//
+// client.HTTPConfig has no certificate-specific field: TLSConfig is a
+// plain *tls.Config, and tls.Config.GetClientCertificate is exactly the
+// hook Go's stdlib gives for presenting a certificate that can change
+// over the life of the process, so mTLS needs no changes to Client
+// itself.
+func NewClientCertificateReloader(certFile, keyFile string) func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	var mu sync.Mutex
+	var cert tls.Certificate
+	var certModTime, keyModTime time.Time
+
+	return func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		certStat, err := os.Stat(certFile)
+		if err != nil {
+			return nil, err
+		}
+		keyStat, err := os.Stat(keyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if certStat.ModTime().Equal(certModTime) && keyStat.ModTime().Equal(keyModTime) {
+			return &cert, nil
+		}
+
+		newCert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		cert = newCert
+		certModTime = certStat.ModTime()
+		keyModTime = keyStat.ModTime()
+		return &cert, nil
+	}
+}
+
+// InitClientWithMTLS initializes the package-level default Client with a
+// client certificate for mutual-TLS-protected InfluxDB endpoints,
+// reloading certFile/keyFile from disk whenever they change.
+func InitClientWithMTLS(addr, certFile, keyFile string) error {
+	return InitClientWithConfig(client.HTTPConfig{
+		Addr: addr,
+		TLSConfig: &tls.Config{
+			GetClientCertificate: NewClientCertificateReloader(certFile, keyFile),
+		},
+	})
+}
+
+// NewCATLSConfig returns a *tls.Config trusting server certificates
+// signed by the CA in caFile instead of the host's system root pool, for
+// an InfluxDB deployment behind a private or internal CA. Set its
+// GetClientCertificate (see NewClientCertificateReloader) too when the
+// deployment needs both a private CA and mutual TLS.
+func NewCATLSConfig(caFile string) (*tls.Config, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("influx: %s contains no valid CA certificates", caFile)
+	}
+	return &tls.Config{RootCAs: pool}, nil
+}
+
+// InitClientWithTLS initializes the package-level default Client using
+// tlsConfig for the underlying HTTPS connection, so a caller that
+// already built one (e.g. via NewCATLSConfig, or combining its RootCAs
+// with NewClientCertificateReloader's GetClientCertificate) doesn't have
+// to construct a client.HTTPConfig by hand just to set its TLSConfig
+// field.
+func InitClientWithTLS(addr string, tlsConfig *tls.Config) error {
+	return InitClientWithConfig(client.HTTPConfig{
+		Addr:      addr,
+		TLSConfig: tlsConfig,
+	})
+}
@@ -0,0 +1,108 @@
+package influx
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// NamingStrategy derives the measurement or field/tag name ToPoint and
+// ParseResult fall back to for a struct or field with no override (no
+// Measurementer, no `measurement=` inf tag, no inf tag name), letting
+// a team using camelCase or dotted.names reuse ToPoint/ParseResult
+// against existing measurements without tagging every field. The
+// default turns "HTTPRequests" into "http_requests", same as always.
+type NamingStrategy interface {
+	Name(goName string) string
+}
+
+// snakeCaseNaming is the default NamingStrategy, wrapping titleToSnake.
+type snakeCaseNaming struct{}
+
+func (snakeCaseNaming) Name(goName string) string {
+	return titleToSnake(goName)
+}
+
+// VerbatimNaming is a NamingStrategy that uses a struct or field's Go
+// name exactly as written, for measurements that were never designed
+// around snake_case and already use CamelCase field keys: pass it to
+// SetNamingStrategy instead of writing an identity strategy by hand.
+type VerbatimNaming struct{}
+
+// Name returns goName unchanged.
+func (VerbatimNaming) Name(goName string) string {
+	return goName
+}
+
+// NamingStrategyFunc adapts a plain func to a NamingStrategy, the way
+// http.HandlerFunc adapts one to http.Handler, for a one-off strategy
+// that doesn't need its own named type.
+type NamingStrategyFunc func(goName string) string
+
+// Name calls f.
+func (f NamingStrategyFunc) Name(goName string) string {
+	return f(goName)
+}
+
+// CamelCaseNaming is a NamingStrategy that turns a Go name into
+// lowerCamelCase (e.g. "HTTPRequests" -> "httpRequests"), built on the
+// same word-splitting titleToSnake uses, for a measurement schema that
+// expects camelCase keys.
+type CamelCaseNaming struct{}
+
+// Name returns goName in camelCase.
+func (CamelCaseNaming) Name(goName string) string {
+	segs := strings.Split(titleToSnake(goName), "_")
+	for i := 1; i < len(segs); i++ {
+		segs[i] = strings.Title(segs[i])
+	}
+	return strings.Join(segs, "")
+}
+
+// KebabCaseNaming is a NamingStrategy that turns a Go name into
+// kebab-case (e.g. "HTTPRequests" -> "http-requests"), built on the same
+// word-splitting titleToSnake uses, for a measurement schema that
+// expects dash-separated keys.
+type KebabCaseNaming struct{}
+
+// Name returns goName in kebab-case.
+func (KebabCaseNaming) Name(goName string) string {
+	return strings.ReplaceAll(titleToSnake(goName), "_", "-")
+}
+
+// namingBox lets activeNaming hold any NamingStrategy implementation:
+// atomic.Value requires every Store to use the same concrete type, so
+// the indirection through a single boxed pointer type is necessary.
+type namingBox struct {
+	strategy NamingStrategy
+}
+
+var activeNaming atomic.Value // *namingBox
+
+func init() {
+	activeNaming.Store(&namingBox{strategy: snakeCaseNaming{}})
+}
+
+// SetNamingStrategy replaces the package-level NamingStrategy ToPoint
+// and ParseResult use from here on, for every mapped type, and drops
+// every cached type plan so already-seen types are replanned under it
+// instead of keeping their old names. Pass nil to restore the default
+// snake_case behavior.
+//
+// ToPoint and ParseResult are package-level functions rather than
+// Client methods, so there is no separate per-Client naming strategy;
+// a service that needs different naming for different Clients should
+// call SetNamingStrategy before building points for each, or keep
+// distinct struct types per naming convention.
+func SetNamingStrategy(strategy NamingStrategy) {
+	if strategy == nil {
+		strategy = snakeCaseNaming{}
+	}
+	activeNaming.Store(&namingBox{strategy: strategy})
+	resetTypePlans()
+}
+
+// nameFor is the default-name fallback buildTypePlan uses for a
+// measurement or field with no explicit name.
+func nameFor(goName string) string {
+	return activeNaming.Load().(*namingBox).strategy.Name(goName)
+}
@@ -0,0 +1,88 @@
+package influx
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// strictNumericParsing controls whether ParseResult rejects a column
+// value parseInt/parseFloat can't actually convert — a string that
+// isn't a number, or a value of some other unrecognized kind — instead
+// of silently decoding it as 0, set by SetStrictNumericParsing. Off by
+// default.
+var strictNumericParsing int32
+
+// SetStrictNumericParsing toggles conversion-failure detection for
+// numeric destination fields from here on: with it on, a column value
+// parseInt or parseFloat couldn't actually parse makes ParseResult
+// fail with an error carrying the column instead of silently decoding
+// it as 0, catching a data-quality problem (an empty string, "N/A", a
+// malformed number) immediately instead of recording it as a
+// zero indistinguishable from a real 0.
+func SetStrictNumericParsing(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&strictNumericParsing, 1)
+	} else {
+		atomic.StoreInt32(&strictNumericParsing, 0)
+	}
+}
+
+// checkedParseInt is parseInt, but reports when val couldn't actually
+// be converted instead of silently returning 0, for
+// SetStrictNumericParsing.
+func checkedParseInt(val interface{}) (int64, error) {
+	if n, ok := val.(json.Number); ok {
+		if v, err := n.Int64(); err == nil {
+			return v, nil
+		}
+		v, err := n.Float64()
+		if err != nil {
+			return 0, err
+		}
+		return int64(v), nil
+	}
+	switch v := reflect.ValueOf(val); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return int64(v.Float()), nil
+	case reflect.String:
+		n, err := strconv.ParseInt(v.String(), 10, 64)
+		if err == nil {
+			return n, nil
+		}
+		if t, terr := time.Parse(time.RFC3339, v.String()); terr == nil {
+			return t.UnixNano(), nil
+		}
+		return 0, err
+	}
+	return 0, fmt.Errorf("cannot parse %v (%T) as int", val, val)
+}
+
+// checkedParseFloat is parseFloat, but reports when val couldn't
+// actually be converted instead of silently returning 0, for
+// SetStrictNumericParsing.
+func checkedParseFloat(val interface{}) (float64, error) {
+	if n, ok := val.(json.Number); ok {
+		v, err := n.Float64()
+		return v, err
+	}
+	switch v := reflect.ValueOf(val); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		f, err := strconv.ParseFloat(v.String(), 64)
+		return f, err
+	}
+	return 0, fmt.Errorf("cannot parse %v (%T) as float", val, val)
+}
@@ -0,0 +1,175 @@
+package otel
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// Writer is the subset of *influx.BufferedWriter a MetricExporter
+// writes decoded points through, the same interface
+// remotewrite.Handler's Writer is.
+type Writer interface {
+	Write(point *client.Point) error
+}
+
+// MetricExporter implements go.opentelemetry.io/otel/sdk/metric's
+// Exporter interface: every data point a PeriodicReader collects is
+// written through Writer as one InfluxDB point, an instrument's Name
+// becoming the measurement, its attributes becoming tags, and its
+// value becoming a "value" field — so an app already instrumented
+// with the OTel metrics SDK can sink straight to Influx v1:
+//
+//	exp := otel.NewMetricExporter(bufferedWriter)
+//	reader := sdkmetric.NewPeriodicReader(exp)
+//	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+//
+// without a separate OTel Collector pipeline in between.
+//
+// A Histogram's bucket boundaries aren't written, only its count, sum,
+// min and max as separate fields; ExponentialHistogram and Summary
+// aggregations (neither produced by the SDK's own default views) are
+// silently skipped rather than failing the whole Export call over one
+// instrument's shape.
+type MetricExporter struct {
+	Writer Writer
+
+	// TemporalitySelector overrides which metricdata.Temporality is
+	// requested per instrument kind. Nil uses
+	// sdkmetric.DefaultTemporalitySelector.
+	TemporalitySelector sdkmetric.TemporalitySelector
+	// AggregationSelector overrides which Aggregation is requested per
+	// instrument kind. Nil uses sdkmetric.DefaultAggregationSelector.
+	AggregationSelector sdkmetric.AggregationSelector
+}
+
+// NewMetricExporter returns a MetricExporter that writes every
+// collected metric through w.
+func NewMetricExporter(w Writer) *MetricExporter {
+	return &MetricExporter{Writer: w}
+}
+
+// Temporality implements sdkmetric.Exporter.
+func (e *MetricExporter) Temporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	if e.TemporalitySelector != nil {
+		return e.TemporalitySelector(kind)
+	}
+	return sdkmetric.DefaultTemporalitySelector(kind)
+}
+
+// Aggregation implements sdkmetric.Exporter.
+func (e *MetricExporter) Aggregation(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	if e.AggregationSelector != nil {
+		return e.AggregationSelector(kind)
+	}
+	return sdkmetric.DefaultAggregationSelector(kind)
+}
+
+// Export implements sdkmetric.Exporter.
+func (e *MetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			points, err := pointsFromMetric(m)
+			if err != nil {
+				return err
+			}
+			for _, p := range points {
+				if err := e.Writer.Write(p); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ForceFlush implements sdkmetric.Exporter. Writer (typically a
+// *influx.BufferedWriter) buffers and flushes on its own schedule; a
+// caller that needs a synchronous flush should call
+// BufferedWriter.Flush directly instead.
+func (e *MetricExporter) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+// Shutdown implements sdkmetric.Exporter. Writer's own lifecycle
+// (BufferedWriter.Close) is independent of the metrics pipeline's, so
+// there's nothing here for Shutdown to release.
+func (e *MetricExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// pointsFromMetric converts one Metrics' data points into points,
+// m.Name becoming the measurement.
+func pointsFromMetric(m metricdata.Metrics) ([]*client.Point, error) {
+	switch data := m.Data.(type) {
+	case metricdata.Gauge[int64]:
+		return pointsFromDataPoints(m.Name, data.DataPoints)
+	case metricdata.Gauge[float64]:
+		return pointsFromDataPoints(m.Name, data.DataPoints)
+	case metricdata.Sum[int64]:
+		return pointsFromDataPoints(m.Name, data.DataPoints)
+	case metricdata.Sum[float64]:
+		return pointsFromDataPoints(m.Name, data.DataPoints)
+	case metricdata.Histogram[int64]:
+		return pointsFromHistogram(m.Name, data.DataPoints)
+	case metricdata.Histogram[float64]:
+		return pointsFromHistogram(m.Name, data.DataPoints)
+	default:
+		return nil, nil
+	}
+}
+
+// pointsFromDataPoints converts a Gauge's or Sum's data points into
+// one point each, its Value becoming a "value" field.
+func pointsFromDataPoints[N int64 | float64](name string, dps []metricdata.DataPoint[N]) ([]*client.Point, error) {
+	points := make([]*client.Point, 0, len(dps))
+	for _, dp := range dps {
+		p, err := client.NewPoint(name, tagsFromAttributes(dp.Attributes),
+			map[string]interface{}{"value": dp.Value}, dp.Time)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// pointsFromHistogram converts a Histogram's data points into one
+// point each, with count, sum, and (when recorded) min/max fields.
+func pointsFromHistogram[N int64 | float64](name string, dps []metricdata.HistogramDataPoint[N]) ([]*client.Point, error) {
+	points := make([]*client.Point, 0, len(dps))
+	for _, dp := range dps {
+		fields := map[string]interface{}{
+			"count": dp.Count,
+			"sum":   dp.Sum,
+		}
+		if min, ok := dp.Min.Value(); ok {
+			fields["min"] = min
+		}
+		if max, ok := dp.Max.Value(); ok {
+			fields["max"] = max
+		}
+		p, err := client.NewPoint(name, tagsFromAttributes(dp.Attributes), fields, dp.Time)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// tagsFromAttributes renders attrs as tags, each value passed through
+// attribute.Value.Emit so a tag is always a string regardless of the
+// attribute's underlying type.
+func tagsFromAttributes(attrs attribute.Set) map[string]string {
+	iter := attrs.Iter()
+	tags := make(map[string]string, iter.Len())
+	for iter.Next() {
+		kv := iter.Attribute()
+		tags[string(kv.Key)] = kv.Value.Emit()
+	}
+	return tags
+}
@@ -0,0 +1,149 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// fakeWriter records every point written to it, so a test can inspect
+// what Export produced.
+type fakeWriter struct {
+	points []*client.Point
+	err    error
+}
+
+func (w *fakeWriter) Write(p *client.Point) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.points = append(w.points, p)
+	return nil
+}
+
+// TestExportWritesGaugePoint confirms Export renders a Gauge's data
+// points as one point per series, the instrument name as measurement,
+// attributes as tags and the value as a "value" field.
+func TestExportWritesGaugePoint(t *testing.T) {
+	w := &fakeWriter{}
+	exp := NewMetricExporter(w)
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	rm := &metricdata.ResourceMetrics{
+		ScopeMetrics: []metricdata.ScopeMetrics{
+			{
+				Metrics: []metricdata.Metrics{
+					{
+						Name: "cpu_usage",
+						Data: metricdata.Gauge[float64]{
+							DataPoints: []metricdata.DataPoint[float64]{
+								{
+									Attributes: attribute.NewSet(attribute.String("host", "a")),
+									Time:       now,
+									Value:      42.5,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := exp.Export(context.Background(), rm); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.points) != 1 {
+		t.Fatalf("len(points) = %d, want 1", len(w.points))
+	}
+	p := w.points[0]
+	if p.Name() != "cpu_usage" {
+		t.Fatalf("Name() = %q, want cpu_usage", p.Name())
+	}
+	if p.Tags()["host"] != "a" {
+		t.Fatalf("Tags() = %v, want host=a", p.Tags())
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["value"] != 42.5 {
+		t.Fatalf("value = %v, want 42.5", fields["value"])
+	}
+}
+
+// TestExportWritesHistogramFields confirms Export renders a
+// Histogram's data points with count/sum/min/max fields instead of
+// its bucket boundaries.
+func TestExportWritesHistogramFields(t *testing.T) {
+	w := &fakeWriter{}
+	exp := NewMetricExporter(w)
+
+	rm := &metricdata.ResourceMetrics{
+		ScopeMetrics: []metricdata.ScopeMetrics{
+			{
+				Metrics: []metricdata.Metrics{
+					{
+						Name: "request_latency",
+						Data: metricdata.Histogram[float64]{
+							DataPoints: []metricdata.HistogramDataPoint[float64]{
+								{
+									Count: 3,
+									Sum:   6,
+									Min:   metricdata.NewExtrema(1.0),
+									Max:   metricdata.NewExtrema(3.0),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := exp.Export(context.Background(), rm); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.points) != 1 {
+		t.Fatalf("len(points) = %d, want 1", len(w.points))
+	}
+	fields, err := w.points[0].Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["count"] != uint64(3) || fields["sum"] != 6.0 || fields["min"] != 1.0 || fields["max"] != 3.0 {
+		t.Fatalf("fields = %v", fields)
+	}
+}
+
+// TestExportStopsOnWriteError confirms Export returns the first
+// write error instead of continuing through remaining points.
+func TestExportStopsOnWriteError(t *testing.T) {
+	wantErr := errors.New("write failed")
+	w := &fakeWriter{err: wantErr}
+	exp := NewMetricExporter(w)
+
+	rm := &metricdata.ResourceMetrics{
+		ScopeMetrics: []metricdata.ScopeMetrics{
+			{
+				Metrics: []metricdata.Metrics{
+					{
+						Name: "cpu_usage",
+						Data: metricdata.Gauge[float64]{
+							DataPoints: []metricdata.DataPoint[float64]{{Value: 1}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := exp.Export(context.Background(), rm); err != wantErr {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+}
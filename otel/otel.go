@@ -0,0 +1,66 @@
+// Package otel bridges eachain/influx and OpenTelemetry both ways: an
+// Interceptor to pass to Client.Use starts one span per query or
+// write, tagged with the target database, the statement (queries
+// only) and the point count (writes only), and marks the span errored
+// when the call itself fails — so an InfluxDB call shows up in a
+// distributed trace alongside the rest of a request's spans.
+// MetricExporter goes the other way, implementing the OTel metrics
+// SDK's Exporter interface so a PeriodicReader can sink collected
+// metrics straight into InfluxDB.
+package otel
+
+import (
+	"context"
+
+	"github.com/eachain/influx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/eachain/influx/otel"
+
+// Interceptor starts an OTel span around every query and write a
+// Client runs.
+type Interceptor struct {
+	tracer trace.Tracer
+}
+
+// NewInterceptor returns an Interceptor that starts spans through tp,
+// or through the global TracerProvider (otel.GetTracerProvider) if tp
+// is nil — the same fallback OTel's own instrumentation libraries use,
+// so a caller that configures a TracerProvider globally (the common
+// case) doesn't have to thread it through here too.
+//
+//	ic := otel.NewInterceptor(nil)
+//	c.Use(ic.Intercept)
+func NewInterceptor(tp trace.TracerProvider) *Interceptor {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return &Interceptor{tracer: tp.Tracer(instrumentationName)}
+}
+
+// Intercept is an influx.Interceptor; pass it to Client.Use.
+func (i *Interceptor) Intercept(ctx context.Context, info influx.RequestInfo, next func(context.Context) error) error {
+	ctx, span := i.tracer.Start(ctx, "influx."+info.Kind, trace.WithAttributes(
+		attribute.String("db.system", "influxdb"),
+		attribute.String("db.name", info.Database),
+	))
+	defer span.End()
+
+	switch info.Kind {
+	case influx.RequestQuery:
+		span.SetAttributes(attribute.String("db.statement", info.Command))
+	case influx.RequestWrite:
+		span.SetAttributes(attribute.Int("influx.points", info.Points))
+	}
+
+	err := next(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,106 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/eachain/influx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// fakeSpan records the attributes, error and status Intercept sets on
+// it, embedding noop.Span so it satisfies trace.Span without having to
+// implement every method.
+type fakeSpan struct {
+	noop.Span
+	attrs      []attribute.KeyValue
+	err        error
+	statusCode codes.Code
+}
+
+func (s *fakeSpan) SetAttributes(kv ...attribute.KeyValue) {
+	s.attrs = append(s.attrs, kv...)
+}
+
+func (s *fakeSpan) RecordError(err error, opts ...trace.EventOption) {
+	s.err = err
+}
+
+func (s *fakeSpan) SetStatus(code codes.Code, description string) {
+	s.statusCode = code
+}
+
+// fakeTracer hands out a single fakeSpan, so a test can inspect it
+// after Intercept runs.
+type fakeTracer struct {
+	noop.Tracer
+	span *fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+	t.span = &fakeSpan{attrs: cfg.Attributes()}
+	return ctx, t.span
+}
+
+func attr(attrs []attribute.KeyValue, key string) (attribute.Value, bool) {
+	for _, kv := range attrs {
+		if string(kv.Key) == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+// TestInterceptRecordsQueryAttributes confirms Intercept tags a query
+// span with the database and statement, and leaves it unerrored on
+// success.
+func TestInterceptRecordsQueryAttributes(t *testing.T) {
+	tracer := &fakeTracer{}
+	ic := &Interceptor{tracer: tracer}
+
+	info := influx.RequestInfo{Kind: influx.RequestQuery, Database: "mydb", Command: "SELECT * FROM cpu"}
+	err := ic.Intercept(context.Background(), info, func(ctx context.Context) error { return nil })
+	if err != nil {
+		t.Fatalf("Intercept: %v", err)
+	}
+
+	if v, ok := attr(tracer.span.attrs, "db.name"); !ok || v.AsString() != "mydb" {
+		t.Fatalf("db.name = %v, ok=%v", v, ok)
+	}
+	if v, ok := attr(tracer.span.attrs, "db.statement"); !ok || v.AsString() != "SELECT * FROM cpu" {
+		t.Fatalf("db.statement = %v, ok=%v", v, ok)
+	}
+	if tracer.span.err != nil {
+		t.Fatalf("err = %v, want nil", tracer.span.err)
+	}
+}
+
+// TestInterceptRecordsWritePointsAndError confirms Intercept tags a
+// write span with the point count and marks it errored when next
+// fails.
+func TestInterceptRecordsWritePointsAndError(t *testing.T) {
+	tracer := &fakeTracer{}
+	ic := &Interceptor{tracer: tracer}
+
+	wantErr := errors.New("write failed")
+	info := influx.RequestInfo{Kind: influx.RequestWrite, Database: "mydb", Points: 3}
+	err := ic.Intercept(context.Background(), info, func(ctx context.Context) error { return wantErr })
+	if err != wantErr {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+
+	if v, ok := attr(tracer.span.attrs, "influx.points"); !ok || v.AsInt64() != 3 {
+		t.Fatalf("influx.points = %v, ok=%v", v, ok)
+	}
+	if tracer.span.err != wantErr {
+		t.Fatalf("span.err = %v, want %v", tracer.span.err, wantErr)
+	}
+	if tracer.span.statusCode != codes.Error {
+		t.Fatalf("statusCode = %v, want %v", tracer.span.statusCode, codes.Error)
+	}
+}
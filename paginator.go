@@ -0,0 +1,94 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// Paginator re-issues a query with an increasing LIMIT/OFFSET,
+// yielding one page of results per Next call, so exporting a
+// measurement too large to query in one shot doesn't require hand-
+// rolling the offset bookkeeping.
+type Paginator struct {
+	c        *Client
+	ctx      context.Context
+	db, cmd  string
+	pageSize int
+	offset   int
+	done     bool
+	err      error
+}
+
+// NewPaginator returns a Paginator that runs cmd against db a page at
+// a time of pageSize rows, appending "LIMIT pageSize OFFSET n" to cmd
+// (n starting at 0 and advancing by however many rows the previous
+// page actually held): cmd must not already carry its own LIMIT or
+// OFFSET clause.
+func (c *Client) NewPaginator(db, cmd string, pageSize int) *Paginator {
+	return c.NewPaginatorContext(context.Background(), db, cmd, pageSize)
+}
+
+// NewPaginatorContext is NewPaginator with a context that aborts each
+// page's request as soon as it's canceled.
+func (c *Client) NewPaginatorContext(ctx context.Context, db, cmd string, pageSize int) *Paginator {
+	return &Paginator{c: c, ctx: ctx, db: db, cmd: cmd, pageSize: pageSize}
+}
+
+// NewPaginator returns a Paginator using the default Client.
+func NewPaginator(db, cmd string, pageSize int) *Paginator {
+	return gClient().NewPaginator(db, cmd, pageSize)
+}
+
+// NewPaginatorContext is NewPaginator with a context, using the
+// default Client.
+func NewPaginatorContext(ctx context.Context, db, cmd string, pageSize int) *Paginator {
+	return gClient().NewPaginatorContext(ctx, db, cmd, pageSize)
+}
+
+// Next fetches the next page of results, decodable with ParseResults/
+// ParseRows exactly like a plain Query's. It returns false, with no
+// results, for an empty page or an error (see Err), and on every call
+// after a page that held fewer than pageSize rows total (that last page
+// is itself still returned with true).
+func (p *Paginator) Next() ([]client.Result, bool) {
+	if p.done {
+		return nil, false
+	}
+
+	cmd := fmt.Sprintf("%s LIMIT %d OFFSET %d", p.cmd, p.pageSize, p.offset)
+	results, err := p.c.QueryContext(p.ctx, p.db, cmd)
+	if err != nil {
+		p.err = err
+		p.done = true
+		return nil, false
+	}
+
+	n := resultRowCount(results)
+	p.offset += n
+	if n < p.pageSize {
+		p.done = true
+	}
+	if n == 0 {
+		return nil, false
+	}
+	return results, true
+}
+
+// Err returns the error, if any, that ended iteration early.
+func (p *Paginator) Err() error {
+	return p.err
+}
+
+// resultRowCount counts every row across every series of every result,
+// the same rows ParseResults would decode.
+func resultRowCount(results []client.Result) int {
+	n := 0
+	for _, result := range results {
+		for _, serie := range result.Series {
+			n += len(serie.Values)
+		}
+	}
+	return n
+}
@@ -0,0 +1,78 @@
+// Package parquet writes decoded series as Parquet files, for
+// archiving downsampled data to object storage in a typed, columnar
+// format instead of raw JSON or line protocol.
+//
+// It's built on the arrow subpackage: each series becomes an Arrow
+// record (one Parquet row group), so ExportParquet inherits that
+// package's column-type inference and the same restriction Parquet
+// itself imposes that every row group in one file must share a single
+// schema.
+package parquet
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/parquet/pqarrow"
+	"github.com/eachain/influx"
+	arrowexport "github.com/eachain/influx/arrow"
+	"github.com/influxdata/influxdb/models"
+)
+
+// ExportParquet writes series to w as a Parquet file, one row group
+// per series. Every series must decode to the same Arrow schema (the
+// common case when they all come from the same measurement or struct
+// type); a series whose columns or tags differ from the first is
+// rejected instead of silently reshaping or dropping data — write it
+// to its own file with a separate ExportParquet call instead.
+func ExportParquet(w io.Writer, series []models.Row) error {
+	if len(series) == 0 {
+		return errors.New("parquet: no series to export")
+	}
+
+	records, err := arrowexport.ExportRecords(series)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, rec := range records {
+			rec.Release()
+		}
+	}()
+
+	schema := records[0].Schema()
+	for i, rec := range records[1:] {
+		if !rec.Schema().Equal(schema) {
+			return fmt.Errorf("parquet: series %d has a different schema than series 0; export it separately", i+1)
+		}
+	}
+
+	return writeRecords(w, schema, records)
+}
+
+// ExportStructsParquet is ExportParquet for a slice of structs (or
+// pointers to structs) of the kind influx.ToPoints accepts, grouped
+// into series the same way influx.ExportStructsJSON groups them.
+func ExportStructsParquet(w io.Writer, slice interface{}, opts ...influx.FieldOption) error {
+	doc, err := influx.ExportStructsJSON(slice, opts...)
+	if err != nil {
+		return err
+	}
+	return ExportParquet(w, doc.Series)
+}
+
+func writeRecords(w io.Writer, schema *arrow.Schema, records []arrow.Record) error {
+	fw, err := pqarrow.NewFileWriter(schema, w, nil, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return err
+	}
+	for _, rec := range records {
+		if err := fw.Write(rec); err != nil {
+			fw.Close()
+			return err
+		}
+	}
+	return fw.Close()
+}
@@ -0,0 +1,128 @@
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/apache/arrow/go/v14/parquet/file"
+	"github.com/apache/arrow/go/v14/parquet/pqarrow"
+	"github.com/influxdata/influxdb/models"
+)
+
+// readBack parses data as a Parquet file and returns it as a single
+// Arrow table, for asserting on what ExportParquet wrote.
+func readBack(t *testing.T, data []byte) arrow.Table {
+	t.Helper()
+	rdr, err := file.NewParquetReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fr, err := pqarrow.NewFileReader(rdr, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tbl, err := fr.ReadTable(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tbl
+}
+
+// TestExportParquetWritesRowGroupPerSeries confirms two compatible
+// series round-trip as one Parquet file with both series' rows intact.
+func TestExportParquetWritesRowGroupPerSeries(t *testing.T) {
+	series := []models.Row{
+		{
+			Name:    "cpu",
+			Columns: []string{"time", "value"},
+			Values:  [][]interface{}{{"2020-01-02T03:04:05Z", 1.5}},
+		},
+		{
+			Name:    "cpu",
+			Columns: []string{"time", "value"},
+			Values:  [][]interface{}{{"2020-01-02T03:05:05Z", 2.5}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := ExportParquet(&buf, series); err != nil {
+		t.Fatal(err)
+	}
+
+	tbl := readBack(t, buf.Bytes())
+	defer tbl.Release()
+	if tbl.NumRows() != 2 {
+		t.Fatalf("NumRows() = %d, want 2", tbl.NumRows())
+	}
+
+	tr := array.NewTableReader(tbl, -1)
+	defer tr.Release()
+	var got []float64
+	for tr.Next() {
+		rec := tr.Record()
+		col := rec.Column(1).(*array.Float64)
+		for i := 0; i < col.Len(); i++ {
+			got = append(got, col.Value(i))
+		}
+	}
+	if len(got) != 2 || got[0] != 1.5 || got[1] != 2.5 {
+		t.Fatalf("values = %v", got)
+	}
+}
+
+// TestExportParquetRejectsSchemaMismatch confirms a series with
+// different columns than series 0 is rejected instead of silently
+// reshaped.
+func TestExportParquetRejectsSchemaMismatch(t *testing.T) {
+	series := []models.Row{
+		{Columns: []string{"time", "value"}, Values: [][]interface{}{{"2020-01-02T03:04:05Z", 1.5}}},
+		{Columns: []string{"time", "value", "host"}, Values: [][]interface{}{{"2020-01-02T03:04:05Z", 1.5, "a"}}},
+	}
+
+	var buf bytes.Buffer
+	if err := ExportParquet(&buf, series); err == nil {
+		t.Fatal("want error for mismatched schemas")
+	}
+}
+
+// TestExportParquetRejectsEmptySeries confirms an empty series slice
+// is rejected with a clear error instead of writing an empty file.
+func TestExportParquetRejectsEmptySeries(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ExportParquet(&buf, nil); err == nil {
+		t.Fatal("want error for no series")
+	}
+}
+
+type parquetExportMetric struct {
+	Host  string  `inf:"host,tag"`
+	Value float64 `inf:"value"`
+	Time  time.Time
+}
+
+// TestExportStructsParquetRoundTrips confirms ExportStructsParquet
+// groups structs into series the same way ExportStructsJSON does, then
+// writes them as Parquet.
+func TestExportStructsParquetRoundTrips(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	metrics := []parquetExportMetric{
+		{Host: "a", Value: 1, Time: now},
+		{Host: "a", Value: 2, Time: now.Add(time.Second)},
+	}
+
+	var buf bytes.Buffer
+	if err := ExportStructsParquet(&buf, metrics); err != nil {
+		t.Fatal(err)
+	}
+
+	tbl := readBack(t, buf.Bytes())
+	defer tbl.Release()
+	if tbl.NumRows() != 2 {
+		t.Fatalf("NumRows() = %d, want 2", tbl.NumRows())
+	}
+}
@@ -0,0 +1,130 @@
+package influx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/models"
+)
+
+// parseResultSet decodes series, one statement's result.Series, into
+// dst: a single series goes straight through ParseResult, so dst can
+// be any kind ParseResult accepts (struct, slice, map, interface); a
+// statement with no series (an empty result) leaves dst at its zero
+// value, or an empty slice/map if that's what dst is; more than one
+// series (a GROUP BY statement) requires dst to be a pointer to a
+// slice, the only shape that can hold more than one series' rows.
+// columns, if given, restricts decoding the same way ParseResult's own
+// columns argument does.
+func parseResultSet(dst interface{}, series []models.Row, columns ...string) error {
+	switch len(series) {
+	case 0:
+		dstVal := makePtrDstVal(reflect.Indirect(reflect.ValueOf(dst)))
+		switch dstVal.Kind() {
+		case reflect.Slice:
+			dstVal.Set(reflect.MakeSlice(dstVal.Type(), 0, 0))
+		case reflect.Map:
+			if dstVal.IsNil() {
+				dstVal.Set(reflect.MakeMap(dstVal.Type()))
+			}
+		}
+		return nil
+	case 1:
+		return ParseResult(dst, series[0], columns...)
+	default:
+		dstVal := reflect.Indirect(reflect.ValueOf(dst))
+		if dstVal.Kind() != reflect.Slice {
+			return errors.New("influx: statement returned multiple series; dst must be a pointer to a slice")
+		}
+		return ParseResults(dst, []client.Result{{Series: series}}, columns...)
+	}
+}
+
+// ParseInto decodes results, a multi-statement query's results as
+// returned by Query, mapping the Nth statement's series to dsts[N] —
+// the common pattern of sending several semicolon-separated queries in
+// one round trip and wanting each statement's answer in its own
+// destination, instead of calling ParseResult once per statement by
+// hand with results[i].Series[0].
+func ParseInto(results []client.Result, dsts ...interface{}) error {
+	if len(dsts) > len(results) {
+		return fmt.Errorf("influx: ParseInto got %d destinations for %d results", len(dsts), len(results))
+	}
+	for i, dst := range dsts {
+		result := results[i]
+		if result.Err != "" {
+			return fmt.Errorf("influx: statement %d: %s", i, result.Err)
+		}
+		if err := parseResultSet(dst, result.Series); err != nil {
+			return fmt.Errorf("influx: statement %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// QueryMulti runs cmd, a semicolon-separated multi-statement query,
+// against db and maps the Nth statement's series to dsts[N] via
+// ParseInto, the run-then-ParseInto composition QueryBatch followed by
+// a manual ParseInto call would otherwise repeat by hand.
+func (c *Client) QueryMulti(db, cmd string, dsts ...interface{}) error {
+	return c.QueryMultiContext(context.Background(), db, cmd, dsts...)
+}
+
+// QueryMultiContext is QueryMulti with a context that aborts the
+// request as soon as it is canceled.
+func (c *Client) QueryMultiContext(ctx context.Context, db, cmd string, dsts ...interface{}) error {
+	results, err := c.QueryContext(ctx, db, cmd)
+	if err != nil {
+		return err
+	}
+	return ParseInto(results, dsts...)
+}
+
+// QueryMulti runs QueryMulti using the default Client.
+func QueryMulti(db, cmd string, dsts ...interface{}) error {
+	return gClient().QueryMulti(db, cmd, dsts...)
+}
+
+// QueryMultiContext runs QueryMultiContext using the default Client.
+func QueryMultiContext(ctx context.Context, db, cmd string, dsts ...interface{}) error {
+	return gClient().QueryMultiContext(ctx, db, cmd, dsts...)
+}
+
+// QueryInto runs cmd against db and decodes its first statement's
+// result straight into dst, collapsing the run-check-pick-decode
+// boilerplate ParseInto's own callers would otherwise repeat by hand
+// for the common case of a single-statement query and one destination.
+func (c *Client) QueryInto(dst interface{}, db, cmd string, columns ...string) error {
+	return c.QueryIntoContext(context.Background(), dst, db, cmd, columns...)
+}
+
+// QueryIntoContext is QueryInto with a context that aborts the request
+// as soon as it is canceled.
+func (c *Client) QueryIntoContext(ctx context.Context, dst interface{}, db, cmd string, columns ...string) error {
+	results, err := c.QueryContext(ctx, db, cmd)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return nil
+	}
+	if results[0].Err != "" {
+		return errors.New(results[0].Err)
+	}
+	return parseResultSet(dst, results[0].Series, columns...)
+}
+
+// QueryInto runs cmd against db and decodes its result into dst, using
+// the default Client.
+func QueryInto(dst interface{}, db, cmd string, columns ...string) error {
+	return gClient().QueryInto(dst, db, cmd, columns...)
+}
+
+// QueryIntoContext is QueryInto with a context, using the default
+// Client.
+func QueryIntoContext(ctx context.Context, dst interface{}, db, cmd string, columns ...string) error {
+	return gClient().QueryIntoContext(ctx, dst, db, cmd, columns...)
+}
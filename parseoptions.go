@@ -0,0 +1,134 @@
+package influx
+
+import (
+	"strings"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// ParseOption customizes how ParseResultWithOptions interprets a
+// series' columns and tags before decoding it, applied at decode time
+// without touching the query or the destination struct itself.
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	rename map[string]string
+	ignore []string
+}
+
+// WithColumnMap renames a series' columns and tags by m before
+// matching them against the destination's inf tags/field names, for a
+// response whose column names don't agree with the struct (a legacy
+// measurement, or a derivative("value") column InfluxDB names after
+// the function instead of the field) without having to restructure the
+// query itself. A name m doesn't mention passes through unchanged.
+func WithColumnMap(m map[string]string) ParseOption {
+	return func(po *parseOptions) {
+		po.rename = m
+	}
+}
+
+// WithIgnore drops the named columns and tags from the series before
+// decoding, so they never reach a map[string]interface{} or similarly
+// unstructured destination — which, with no struct tags of its own to
+// select by, otherwise keeps everything a series carries. A pattern
+// ending in "*" drops every name with that prefix, e.g.
+// WithIgnore("time", "internal_*"); an exact pattern must match the
+// whole name. Applied before WithColumnMap's renaming, so patterns
+// match the series' original column names.
+func WithIgnore(patterns ...string) ParseOption {
+	return func(po *parseOptions) {
+		po.ignore = append(po.ignore, patterns...)
+	}
+}
+
+// ignored reports whether name matches one of patterns.
+func ignored(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if strings.HasSuffix(p, "*") {
+			if strings.HasPrefix(name, p[:len(p)-1]) {
+				return true
+			}
+		} else if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// applyParseOptions returns serie with opts applied, or serie itself
+// unchanged if opts is empty.
+func applyParseOptions(serie models.Row, opts []ParseOption) models.Row {
+	if len(opts) == 0 {
+		return serie
+	}
+	po := &parseOptions{}
+	for _, opt := range opts {
+		opt(po)
+	}
+
+	if len(po.ignore) > 0 {
+		keep := make([]int, 0, len(serie.Columns))
+		cols := make([]string, 0, len(serie.Columns))
+		for i, c := range serie.Columns {
+			if !ignored(po.ignore, c) {
+				keep = append(keep, i)
+				cols = append(cols, c)
+			}
+		}
+		if len(keep) != len(serie.Columns) {
+			vals := make([][]interface{}, len(serie.Values))
+			for r, vs := range serie.Values {
+				row := make([]interface{}, len(keep))
+				for j, idx := range keep {
+					row[j] = vs[idx]
+				}
+				vals[r] = row
+			}
+			serie.Columns = cols
+			serie.Values = vals
+		}
+
+		if len(serie.Tags) > 0 {
+			tags := make(map[string]string, len(serie.Tags))
+			for k, v := range serie.Tags {
+				if !ignored(po.ignore, k) {
+					tags[k] = v
+				}
+			}
+			serie.Tags = tags
+		}
+	}
+
+	if len(po.rename) > 0 {
+		cols := make([]string, len(serie.Columns))
+		for i, c := range serie.Columns {
+			if r, ok := po.rename[c]; ok {
+				c = r
+			}
+			cols[i] = c
+		}
+		serie.Columns = cols
+
+		if len(serie.Tags) > 0 {
+			tags := make(map[string]string, len(serie.Tags))
+			for k, v := range serie.Tags {
+				if r, ok := po.rename[k]; ok {
+					k = r
+				}
+				tags[k] = v
+			}
+			serie.Tags = tags
+		}
+	}
+
+	return serie
+}
+
+// ParseResultWithOptions is ParseResult, but applies opts to serie's
+// columns and tags first, the decode-time counterpart to ParseResult's
+// columns filter for reshaping a response rather than selecting from
+// it.
+func ParseResultWithOptions(dst interface{}, serie models.Row, opts ...ParseOption) error {
+	return ParseResult(dst, applyParseOptions(serie, opts))
+}
@@ -0,0 +1,27 @@
+package influx
+
+import "github.com/influxdata/influxdb/models"
+
+// ParseResultRange is ParseResult, but only decodes the row window
+// [offset, offset+limit) of serie instead of every row, so paginating
+// over an already-fetched large series doesn't require re-decoding (or
+// re-querying) all of it just to show the next page. offset and limit
+// are clamped to serie's actual row count; a negative offset or limit
+// is treated as 0.
+func ParseResultRange(dst interface{}, serie models.Row, offset, limit int, columns ...string) error {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	if offset > len(serie.Values) {
+		offset = len(serie.Values)
+	}
+	end := offset + limit
+	if end > len(serie.Values) {
+		end = len(serie.Values)
+	}
+	serie.Values = serie.Values[offset:end]
+	return ParseResult(dst, serie, columns...)
+}
@@ -0,0 +1,93 @@
+package influx
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// WriteBatchPointsSkippingRejected writes bp using WriteBatchPointsSkippingRejectedContext
+// and context.Background.
+func (c *Client) WriteBatchPointsSkippingRejected(bp client.BatchPoints) ([]*client.Point, error) {
+	return c.WriteBatchPointsSkippingRejectedContext(context.Background(), bp)
+}
+
+// WriteBatchPointsSkippingRejectedContext writes bp, and if InfluxDB
+// reports a partial write (see AsPartialWriteError), isolates exactly
+// which points were rejected by bisecting the batch and retrying each
+// half, instead of treating the whole batch as failed. InfluxDB's own
+// partial-write error names only a reason and a dropped count, not
+// which points caused it, so the only way to recover that is to keep
+// splitting the batch until each failing point is on its own.
+//
+// It returns every point that was ultimately rejected, with a nil
+// error, so the caller can log, drop or fix up and resubmit them
+// separately; every other point in bp has been durably written. err is
+// only ever non-nil when something other than a partial write failed —
+// a transport error, or a total (non-partial) write failure — in which
+// case no point in bp is guaranteed written and rejected is nil.
+//
+// Bisecting costs up to roughly 2*len(bp.Points()) requests in the
+// worst case (every point rejected), so this is worth using only where
+// a batch occasionally contains a handful of bad points among many
+// good ones; for a batch that's wholesale rejected, WriteRetry's
+// whole-batch retry is cheaper.
+func (c *Client) WriteBatchPointsSkippingRejectedContext(ctx context.Context, bp client.BatchPoints) ([]*client.Point, error) {
+	cfg := client.BatchPointsConfig{
+		Database:         bp.Database(),
+		Precision:        bp.Precision(),
+		RetentionPolicy:  bp.RetentionPolicy(),
+		WriteConsistency: bp.WriteConsistency(),
+	}
+	return c.bisectPartialWrite(ctx, cfg, bp.Points())
+}
+
+// bisectPartialWrite writes points as one batch, recursively splitting
+// it in half on a partial-write error until every rejected point is
+// identified on its own.
+func (c *Client) bisectPartialWrite(ctx context.Context, cfg client.BatchPointsConfig, points []*client.Point) ([]*client.Point, error) {
+	if len(points) == 0 {
+		return nil, nil
+	}
+
+	bp, err := client.NewBatchPoints(cfg)
+	if err != nil {
+		return nil, err
+	}
+	bp.AddPoints(points)
+
+	writeErr := c.WriteBatchPointsContext(ctx, bp)
+	if writeErr == nil {
+		return nil, nil
+	}
+	if _, ok := AsPartialWriteError(writeErr); !ok {
+		return nil, writeErr
+	}
+	if len(points) == 1 {
+		return points, nil
+	}
+
+	mid := len(points) / 2
+	left, err := c.bisectPartialWrite(ctx, cfg, points[:mid])
+	if err != nil {
+		return nil, err
+	}
+	right, err := c.bisectPartialWrite(ctx, cfg, points[mid:])
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
+
+// WriteBatchPointsSkippingRejected writes bp using the default Client,
+// isolating rejected points the way WriteBatchPointsSkippingRejectedContext
+// does.
+func WriteBatchPointsSkippingRejected(bp client.BatchPoints) ([]*client.Point, error) {
+	return gClient().WriteBatchPointsSkippingRejected(bp)
+}
+
+// WriteBatchPointsSkippingRejectedContext is WriteBatchPointsSkippingRejected
+// with a context, using the default Client.
+func WriteBatchPointsSkippingRejectedContext(ctx context.Context, bp client.BatchPoints) ([]*client.Point, error) {
+	return gClient().WriteBatchPointsSkippingRejectedContext(ctx, bp)
+}
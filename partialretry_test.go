@@ -0,0 +1,115 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// rejectingFakeClient fails a write whenever bp contains a point whose
+// "value" field is in reject, reporting a partial write the way
+// InfluxDB itself does, so WriteBatchPointsSkippingRejectedContext has
+// something to bisect.
+type rejectingFakeClient struct {
+	fakeClient
+	reject map[int]bool
+}
+
+func (f *rejectingFakeClient) Write(bp client.BatchPoints) error {
+	dropped := 0
+	for _, p := range bp.Points() {
+		fields, _ := p.Fields()
+		if v, ok := fields["value"].(int64); ok && f.reject[int(v)] {
+			dropped++
+		}
+	}
+	if dropped > 0 {
+		return fmt.Errorf("influx: partial write: field type conflict: dropped=%d", dropped)
+	}
+	return f.fakeClient.Write(bp)
+}
+
+func (f *rejectingFakeClient) WriteCtx(ctx context.Context, bp client.BatchPoints) error {
+	return f.Write(bp)
+}
+
+func pointWithValue(t *testing.T, v int) *client.Point {
+	t.Helper()
+	p, err := client.NewPoint("cpu", nil, map[string]interface{}{"value": v}, time.Unix(int64(v), 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// TestWriteBatchPointsSkippingRejectedIsolatesBadPoints confirms
+// WriteBatchPointsSkippingRejectedContext bisects a partially-rejected
+// batch down to exactly the rejected points, writing everything else.
+func TestWriteBatchPointsSkippingRejectedIsolatesBadPoints(t *testing.T) {
+	fc := &rejectingFakeClient{reject: map[int]bool{2: true, 5: true}}
+	c := NewWithClient(fc)
+
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{Database: "mydb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for v := 0; v < 7; v++ {
+		bp.AddPoint(pointWithValue(t, v))
+	}
+
+	rejected, err := c.WriteBatchPointsSkippingRejectedContext(context.Background(), bp)
+	if err != nil {
+		t.Fatalf("WriteBatchPointsSkippingRejectedContext: %v", err)
+	}
+	if len(rejected) != 2 {
+		t.Fatalf("rejected = %d points, want 2", len(rejected))
+	}
+	got := map[int]bool{}
+	for _, p := range rejected {
+		fields, _ := p.Fields()
+		got[int(fields["value"].(int64))] = true
+	}
+	if !got[2] || !got[5] {
+		t.Fatalf("rejected values = %v, want {2, 5}", got)
+	}
+}
+
+// TestWriteBatchPointsSkippingRejectedPropagatesNonPartialError
+// confirms a failure that isn't a partial write is returned as-is,
+// with no rejected points reported.
+func TestWriteBatchPointsSkippingRejectedPropagatesNonPartialError(t *testing.T) {
+	wantErr := fmt.Errorf("influx: connection refused")
+	fc := &erroringFakeClient{err: wantErr}
+	c := NewWithClient(fc)
+
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{Database: "mydb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bp.AddPoint(pointWithValue(t, 1))
+
+	rejected, err := c.WriteBatchPointsSkippingRejectedContext(context.Background(), bp)
+	if err != wantErr {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	if rejected != nil {
+		t.Fatalf("rejected = %v, want nil", rejected)
+	}
+}
+
+// erroringFakeClient fails every write with a fixed error.
+type erroringFakeClient struct {
+	fakeClient
+	err error
+}
+
+func (f *erroringFakeClient) Write(bp client.BatchPoints) error {
+	return f.err
+}
+
+func (f *erroringFakeClient) WriteCtx(ctx context.Context, bp client.BatchPoints) error {
+	return f.Write(bp)
+}
@@ -0,0 +1,33 @@
+package influx
+
+import "github.com/influxdata/influxdb/models"
+
+// MergePartialSeries coalesces a chunked query's partial series
+// splits into single, complete rows: InfluxDB marks a models.Row
+// Partial when that series' remaining values continue in the next
+// chunk rather than ending there, so naively concatenating series
+// collected across several QueryChunked/ChunkedRows chunks leaves the
+// same series split across two or more adjacent models.Row entries
+// instead of one. Rows that aren't a continuation of the row right
+// before them (a different series, or a series that wasn't marked
+// Partial) are left alone; every merged row's Partial is cleared
+// unless it's still a continuation of something after it.
+//
+// ParseResult/ParseResults/Rows/ChunkedRows never need this
+// themselves — each decodes row by row, filling every row's tags
+// fresh, so a split series already decodes correctly without merging.
+// It's for a caller collecting raw series across chunks (e.g. to hand
+// a complete series to ParseGrouped) who would otherwise see the same
+// series more than once.
+func MergePartialSeries(series []models.Row) []models.Row {
+	merged := make([]models.Row, 0, len(series))
+	for _, row := range series {
+		if n := len(merged); n > 0 && merged[n-1].Partial && merged[n-1].SameSeries(&row) {
+			merged[n-1].Values = append(merged[n-1].Values, row.Values...)
+			merged[n-1].Partial = row.Partial
+			continue
+		}
+		merged = append(merged, row)
+	}
+	return merged
+}
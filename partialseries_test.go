@@ -0,0 +1,66 @@
+package influx
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// TestMergePartialSeriesJoinsContinuationRows confirms a series split
+// across chunks — a Partial row followed by another row with the same
+// name and tags — merges into one row with every value concatenated,
+// while a different series right before it is left untouched.
+func TestMergePartialSeriesJoinsContinuationRows(t *testing.T) {
+	series := []models.Row{
+		{
+			Name:    "mem",
+			Tags:    map[string]string{"host": "a"},
+			Columns: []string{"used"},
+			Values:  [][]interface{}{{1.0}},
+		},
+		{
+			Name:    "cpu",
+			Tags:    map[string]string{"host": "a"},
+			Columns: []string{"usage"},
+			Values:  [][]interface{}{{0.1}, {0.2}},
+			Partial: true,
+		},
+		{
+			Name:    "cpu",
+			Tags:    map[string]string{"host": "a"},
+			Columns: []string{"usage"},
+			Values:  [][]interface{}{{0.3}},
+		},
+	}
+
+	merged := MergePartialSeries(series)
+	if len(merged) != 2 {
+		t.Fatalf("len(merged) = %d, want 2", len(merged))
+	}
+	if merged[0].Name != "mem" {
+		t.Fatalf("merged[0] = %+v, want mem", merged[0])
+	}
+	if merged[1].Name != "cpu" || merged[1].Partial {
+		t.Fatalf("merged[1] = %+v, want cpu with Partial cleared", merged[1])
+	}
+	want := [][]interface{}{{0.1}, {0.2}, {0.3}}
+	if !reflect.DeepEqual(merged[1].Values, want) {
+		t.Fatalf("merged[1].Values = %v, want %v", merged[1].Values, want)
+	}
+}
+
+// TestMergePartialSeriesLeavesCompleteSeriesAlone confirms series of
+// different measurements with no Partial flag at all pass through
+// unmodified.
+func TestMergePartialSeriesLeavesCompleteSeriesAlone(t *testing.T) {
+	series := []models.Row{
+		{Name: "cpu", Columns: []string{"usage"}, Values: [][]interface{}{{0.1}}},
+		{Name: "mem", Columns: []string{"used"}, Values: [][]interface{}{{1.0}}},
+	}
+
+	merged := MergePartialSeries(series)
+	if !reflect.DeepEqual(merged, series) {
+		t.Fatalf("MergePartialSeries = %+v, want unchanged %+v", merged, series)
+	}
+}
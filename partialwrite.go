@@ -0,0 +1,48 @@
+package influx
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// PartialWriteError reports that InfluxDB wrote only part of a batch,
+// parsed out of its "partial write: <reason> dropped=<n>" message by
+// AsPartialWriteError. Typical Reasons are "field type conflict" or
+// "points beyond retention policy".
+type PartialWriteError struct {
+	Reason  string
+	Dropped int
+}
+
+func (e *PartialWriteError) Error() string {
+	return fmt.Sprintf("influx: partial write: %s dropped=%d", e.Reason, e.Dropped)
+}
+
+// partialWriteRE matches InfluxDB's partial-write message, e.g.
+// `partial write: field type conflict: input field "value" on
+// measurement "cpu" is type integer, already exists as type float
+// dropped=1`. client.Client surfaces write failures as a flat
+// errors.New(body) (see isRetryableWriteError), so this message is
+// searched for rather than parsed as the whole error string.
+var partialWriteRE = regexp.MustCompile(`partial write: (.+?) dropped=(\d+)`)
+
+// AsPartialWriteError parses a "partial write: ... dropped=N" message
+// out of err, returning ok=false if err is nil or doesn't contain one.
+// Use it after a failed Insert/InsertMany/WriteBatchPointsContext call
+// to tell a partial write (some points accepted) apart from a total
+// failure, e.g. to decide whether it's worth retrying at all.
+func AsPartialWriteError(err error) (perr *PartialWriteError, ok bool) {
+	if err == nil {
+		return nil, false
+	}
+	m := partialWriteRE.FindStringSubmatch(err.Error())
+	if m == nil {
+		return nil, false
+	}
+	dropped, convErr := strconv.Atoi(m[2])
+	if convErr != nil {
+		return nil, false
+	}
+	return &PartialWriteError{Reason: m[1], Dropped: dropped}, true
+}
@@ -0,0 +1,615 @@
+package influx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// sliceStrategy selects how ToPoint encodes a slice-typed field (other
+// than []byte, which is left as raw bytes since the InfluxDB client
+// already accepts it directly), set by one of the "join"/"join=SEP",
+// "indexed" or "explode" inf tag options; see fieldPlan.sliceStrategy.
+type sliceStrategy int
+
+const (
+	// sliceJoin, the default, joins the slice's elements (rendered the
+	// same way a tag value is, so a Stringer/Converter element still
+	// reads sensibly) into a single string field with fieldPlan.sliceSep
+	// between them.
+	sliceJoin sliceStrategy = iota
+	// sliceIndexed expands the slice into one field per element, named
+	// "name_0", "name_1", and so on.
+	sliceIndexed
+	// sliceExplode makes the field hold a single element instead of the
+	// whole slice, one point per element, produced by ExplodePoints
+	// instead of ToPoint/ToPointNamed.
+	sliceExplode
+)
+
+// fieldPlan is a single field's contribution to a typePlan: where it
+// lives, what name it maps to under its `inf` tag (or the default
+// titleToSnake of its Go name), and how ToPoint/decoding should treat
+// it.
+type fieldPlan struct {
+	index       []int
+	name        string
+	isTag       bool
+	isTime      bool
+	omitempty   bool
+	isMapFields bool
+	isMapTags   bool
+	// isMeasurement marks the field inf:",measurement" declared, filled
+	// with the series' name after a row decodes into it; see
+	// typePlan.measurementField.
+	isMeasurement bool
+	// durationUnit is the unit a time.Duration field is encoded/decoded
+	// in, set by one of the "ns"/"us"/"ms"/"s"/"seconds" inf tag
+	// options; zero means the field isn't a time.Duration or carries
+	// none of those options, and is encoded as raw int64 nanoseconds.
+	durationUnit time.Duration
+	// timeLayout is the time.Parse/Format layout a string-typed time
+	// field is encoded/decoded with, set by a "layout=..." inf tag
+	// option; empty means the field isn't a string-typed time field (a
+	// time.Time or integer epoch field ignores it).
+	timeLayout string
+	// boolTagAsInt formats a bool tag as "1"/"0" instead of the default
+	// "true"/"false", set by the "01" inf tag option, for dashboards
+	// built against the old fmt.Sprint("0"/"1"-style) output.
+	boolTagAsInt bool
+	// asString encodes a numeric/bool field as a string field instead of
+	// its native type, set by the "string" inf tag option, for a
+	// measurement whose schema already established that field as a
+	// string (InfluxDB rejects a write that conflicts with a field's
+	// existing type). Decoding needs no matching option: parseInt,
+	// parseFloat and DecodeBool already accept a string column value.
+	asString bool
+	// sliceStrategy and sliceSep only apply when the field's Go type is
+	// a slice (other than []byte); see sliceStrategy's doc comment.
+	sliceStrategy sliceStrategy
+	sliceSep      string
+	// defaultValue is what alignToStruct assigns the field when its
+	// column/tag is absent from a decoded row, set by a "default=VALUE"
+	// inf tag option, e.g. `inf:"region,default=unknown"`; only
+	// meaningful when hasDefault is true, since "" is itself a valid
+	// declared default. Parsed the same way a column value would be, so
+	// it can be given in the field's own type's string form (e.g.
+	// "default=0" for an int field).
+	defaultValue string
+	hasDefault   bool
+	// setter is this field's precompiled assignment function, or nil
+	// when it needs parseSingle's generic path instead; see
+	// buildFieldSetter.
+	setter fieldSetter
+}
+
+// typePlan is the once-per-type work ToPoint and ParseResult need to
+// map a struct to/from InfluxDB: the measurement name ToPoint falls
+// back to when the struct has no Measurementer (itself defaulting to a
+// `measurement=` inf tag if present, else a RegisterMeasurement entry
+// if present, else the snake_cased type name), the database InsertStruct
+// falls back to when the struct has no Databaser, the fields to walk by
+// index instead of by repeated tag string parsing, and a name index for
+// alignToStruct to look fields up by column/tag name.
+type typePlan struct {
+	measurement string
+	database    string
+	fields      []fieldPlan
+	byName      map[string]int // inf/column name -> index into fields
+	// timeField indexes the struct's isTime field in fields, for
+	// alignToStruct to populate from a "time" column, or -1 if the
+	// struct has none. It isn't in byName: unlike every other field,
+	// its inf tag doesn't claim a field/tag name, so there's no name
+	// for a sibling field to collide with.
+	timeField int
+	// measurementField indexes the struct's isMeasurement field in
+	// fields, for afterParse to fill in from the decoded row's series
+	// name, or -1 if the struct has none. Like timeField, it isn't in
+	// byName: its inf tag claims no field/tag name of its own.
+	measurementField int
+	// err is set by buildTypePlan when two fields at the same nesting
+	// depth resolve to the same name (as opposed to a deeper, embedded
+	// field losing to a shallower one it's shadowed by, which is
+	// intentional and not an error); planType caches it like any other
+	// plan, and ToPoint/ParseResult return it on every use of the type.
+	err error
+}
+
+var typePlans sync.Map // reflect.Type -> *typePlan
+
+func planType(t reflect.Type) *typePlan {
+	if v, ok := typePlans.Load(t); ok {
+		return v.(*typePlan)
+	}
+	p := buildTypePlan(t)
+	actual, _ := typePlans.LoadOrStore(t, p)
+	return actual.(*typePlan)
+}
+
+// resetTypePlans drops every cached type plan, for a setting that
+// changes how types are planned (SetJSONTagFallback, SetNamingStrategy,
+// SetStrictTagging). It deletes each entry instead of replacing
+// typePlans with a new sync.Map, since the latter would race a
+// concurrent planType reading the old one through the same package
+// variable.
+func resetTypePlans() {
+	typePlans.Range(func(k, _ interface{}) bool {
+		typePlans.Delete(k)
+		return true
+	})
+}
+
+func buildTypePlan(t reflect.Type) *typePlan {
+	p := &typePlan{byName: make(map[string]int), timeField: -1, measurementField: -1}
+
+	name := t.Name()
+	if idx := strings.LastIndexByte(name, '.'); idx >= 0 {
+		name = name[idx+1:]
+	}
+	p.measurement = nameFor(name)
+	if m, ok := registeredMeasurements.Load(t); ok {
+		p.measurement = m.(string)
+	}
+
+	p.collectFields(t, nil, "")
+	return p
+}
+
+// collectFields walks t's fields, recursing into embedded (anonymous)
+// struct fields exactly like encoding/json does: a common pattern like
+// embedding a BaseMetric{Host, Env string; Time time.Time} contributes
+// its tags/fields/time as if they were declared directly on t. Fields
+// declared directly on a struct are collected before its embedded
+// fields are recursed into, so a field whose name collides with one
+// promoted from an embedded struct wins, the same shallower-wins rule
+// encoding/json uses.
+//
+// A named (non-anonymous) struct field tagged `inf:"name,flatten"` is
+// also recursed into, with prefix extended by "name_" for everything
+// found inside it, e.g. a Cpu Cpu `inf:"cpu,flatten"` field's User
+// field becomes "cpu_user" instead of an unusable interface{} value.
+// `inf:"cpu,flatten,sep=."` uses "." instead of "_" as the separator.
+// `inf:",inline"` (no name, no separator) recurses without adding any
+// prefix at all, the same as an anonymous embedded field, for a named
+// field whose own struct's tags already carry whatever name they need.
+//
+// A map field tagged `inf:",fields"` (any map[string]V) is merged into
+// the point's fields, and one tagged `inf:",tags"` (any map[string]V,
+// stringified like a tag) into its tags, both by ToPoint at runtime
+// instead of being planned by name here, since their keys aren't known
+// until then; see fieldPlan.isMapFields/isMapTags.
+//
+// A time.Duration field tagged with one of "ns"/"us"/"ms"/"s"/
+// "seconds" (e.g. `inf:"latency,ms"`) is encoded/decoded in that unit
+// instead of raw int64 nanoseconds; see fieldPlan.durationUnit.
+//
+// A bool tag field tagged `inf:"active,tag,01"` is formatted as "1"/
+// "0" instead of the default "true"/"false"; see
+// fieldPlan.boolTagAsInt.
+//
+// An integer field tagged `inf:"time,ms"`/`inf:"time,s"`/etc. (the same
+// unit options a time.Duration field takes) becomes the point's time
+// instead of a field, read as an epoch timestamp in that unit, for an
+// upstream event that carries its own timestamp instead of relying on
+// ToPoint to stamp it with time.Now(); see fieldPlan.isTime. Whichever
+// field this is, named "Time" or tagged, alignToStruct populates it
+// from a "time" column the same way, via typePlan.timeField.
+//
+// A field tagged `inf:",measurement"` claims no column/tag name of its
+// own; instead, afterParse fills it with the series' name once a row
+// finishes decoding, for a heterogeneous result (a wildcard SELECT, or
+// results from several measurements merged by the caller) whose rows
+// otherwise carry no hint of which measurement they came from. See
+// typePlan.measurementField.
+//
+// A plain field is implicitly a field (not a tag) with no inf tag
+// needed, but can spell that out with `inf:"cpu,field"` (",field" is
+// otherwise a no-op) to satisfy SetStrictTagging, which requires every
+// field to declare ",tag" or ",field" and rejects a type that doesn't.
+//
+// A field tagged `inf:"usage|mean_usage"` decodes either name, for a
+// column InfluxDB renames depending on the aggregate function applied
+// to it (e.g. "usage" from a plain SELECT, "mean_usage" from SELECT
+// MEAN(usage)); the first name is still the only one ToPoint encodes
+// under. More than one alias can be piped together,
+// `inf:"usage|mean_usage|avg_usage"`.
+//
+// A field tagged `inf:"region,default=unknown"` gets that declared
+// default instead of the Go zero value when its column/tag is absent
+// from a decoded row, so downstream code doesn't need to special-case
+// "" (or 0, or false) as "missing" versus "genuinely that value"; see
+// fieldPlan.defaultValue.
+//
+// A slice-typed field (other than []byte) is, by default, joined into a
+// single string field with "," between elements; `inf:"tags,join=;"`
+// joins with ";" instead. `inf:"latency,indexed"` expands it into
+// "latency_0", "latency_1", and so on instead. `inf:"samples,explode"`
+// makes each element its own point instead, one per ToPoint a struct
+// with that many elements would otherwise produce as a single point;
+// see ExplodePoints. See fieldPlan.sliceStrategy.
+func (p *typePlan) collectFields(t reflect.Type, index []int, prefix string) {
+	var anonymous []reflect.StructField
+	var anonIndex [][]int
+
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i)
+		path := append(append([]int{}, index...), i)
+		tagstr := structTag(ft)
+
+		if ft.Anonymous && ft.Type.Kind() == reflect.Struct && tagstr == "" {
+			anonymous = append(anonymous, ft)
+			anonIndex = append(anonIndex, path)
+			continue
+		}
+
+		if ft.Type.Kind() == reflect.Struct && tagHasOption(tagstr, "inline") {
+			p.collectFields(ft.Type, path, prefix)
+			continue
+		}
+
+		if ft.Type.Kind() == reflect.Struct && tagHasOption(tagstr, "flatten") {
+			fname := strings.Split(tagstr, ",")[0]
+			if fname == "" {
+				fname = nameFor(ft.Name)
+			}
+			sep := "_"
+			if s, ok := tagOptionValue(tagstr, "sep"); ok {
+				sep = s
+			}
+			p.collectFields(ft.Type, path, prefix+fname+sep)
+			continue
+		}
+
+		if ft.Type.Kind() == reflect.Map {
+			if tagHasOption(tagstr, "fields") {
+				p.fields = append(p.fields, fieldPlan{index: path, isMapFields: true})
+				continue
+			}
+			if tagHasOption(tagstr, "tags") {
+				p.fields = append(p.fields, fieldPlan{index: path, isMapTags: true})
+				continue
+			}
+		}
+
+		p.addField(ft, path, prefix)
+	}
+	for i, ft := range anonymous {
+		p.collectFields(ft.Type, anonIndex[i], prefix)
+	}
+}
+
+// tagHasOption reports whether tagstr's comma-separated options (every
+// part after the leading name) include opt, e.g.
+// tagHasOption("cpu,flatten", "flatten") == true.
+func tagHasOption(tagstr, opt string) bool {
+	parts := strings.Split(tagstr, ",")
+	for _, part := range parts[1:] {
+		if part == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// tagOptionValue returns the value of a "key=value" option among
+// tagstr's comma-separated options, e.g.
+// tagOptionValue("tags,join=;", "join") == (";", true).
+func tagOptionValue(tagstr, key string) (string, bool) {
+	prefix := key + "="
+	parts := strings.Split(tagstr, ",")
+	for _, part := range parts[1:] {
+		if strings.HasPrefix(part, prefix) {
+			return strings.TrimPrefix(part, prefix), true
+		}
+	}
+	return "", false
+}
+
+// addField registers ft, reached via index, as one of p's fields under
+// prefix+its own name, unless a shallower field already claimed that
+// name.
+func (p *typePlan) addField(ft reflect.StructField, index []int, prefix string) {
+	tagstr := structTag(ft)
+	if tagstr == "-" {
+		return
+	}
+	if strings.HasPrefix(tagstr, "database=") {
+		p.database = strings.TrimPrefix(tagstr, "database=")
+		return
+	}
+	if kv, ok := parseSchemaTag(tagstr); ok {
+		if m := kv["measurement"]; m != "" {
+			p.measurement = m
+		}
+		return
+	}
+	if strings.HasPrefix(tagstr, "measurement=") {
+		p.measurement = strings.TrimPrefix(tagstr, "measurement=")
+		return
+	}
+	if tagHasOption(tagstr, "measurement") {
+		p.fields = append(p.fields, fieldPlan{index: index, isMeasurement: true})
+		p.measurementField = len(p.fields) - 1
+		return
+	}
+	_, hasLayout := tagOptionValue(tagstr, "layout")
+	if ft.Name == "Time" || strings.Split(tagstr, ",")[0] == "time" || hasLayout {
+		fp := fieldPlan{index: index, isTime: true}
+		switch {
+		case ft.Type.Kind() >= reflect.Int && ft.Type.Kind() <= reflect.Uint64:
+			// An integer field tagged inf:"time,ms"/"time,unix_ms"/etc.
+			// carries an epoch timestamp instead of a time.Time, e.g. from
+			// an upstream event that encodes it as epoch-milliseconds.
+			switch {
+			case tagHasOption(tagstr, "ns"), tagHasOption(tagstr, "unix_ns"):
+				fp.durationUnit = time.Nanosecond
+			case tagHasOption(tagstr, "us"), tagHasOption(tagstr, "unix_us"):
+				fp.durationUnit = time.Microsecond
+			case tagHasOption(tagstr, "ms"), tagHasOption(tagstr, "unix_ms"):
+				fp.durationUnit = time.Millisecond
+			case tagHasOption(tagstr, "s"), tagHasOption(tagstr, "seconds"), tagHasOption(tagstr, "unix_s"):
+				fp.durationUnit = time.Second
+			default:
+				fp.durationUnit = time.Nanosecond
+			}
+		case ft.Type.Kind() == reflect.String:
+			// A string field tagged inf:"ts,layout=2006-01-02 15:04:05"
+			// carries a formatted timestamp instead of a time.Time, for an
+			// upstream event whose time column is already text. RFC3339 is
+			// the default, matching parseTime's own fallback.
+			fp.timeLayout = time.RFC3339
+			if layout, ok := tagOptionValue(tagstr, "layout"); ok {
+				fp.timeLayout = layout
+			}
+		}
+		p.fields = append(p.fields, fp)
+		p.timeField = len(p.fields) - 1
+		return
+	}
+
+	_, hasJoinSep := tagOptionValue(tagstr, "join")
+	declaresRole := tagHasOption(tagstr, "tag") || tagHasOption(tagstr, "field") ||
+		tagHasOption(tagstr, "join") || tagHasOption(tagstr, "indexed") || tagHasOption(tagstr, "explode") || hasJoinSep
+	if atomic.LoadInt32(&strictTagging) != 0 && !declaresRole {
+		if p.err == nil {
+			p.err = fmt.Errorf("influx: field %q has no declared role; add \",tag\" or \",field\" to its inf tag, or disable SetStrictTagging", ft.Name)
+		}
+		return
+	}
+
+	fname := strings.Split(tagstr, ",")[0]
+	if fname == "" {
+		if jname, ok := jsonFallbackName(ft); ok {
+			fname = jname
+		} else {
+			fname = nameFor(ft.Name)
+		}
+	}
+	var aliases []string
+	if idx := strings.IndexByte(fname, '|'); idx >= 0 {
+		aliases = strings.Split(fname[idx+1:], "|")
+		fname = fname[:idx]
+	}
+	fname = prefix + fname
+	if existing, ok := p.byName[fname]; ok {
+		if len(p.fields[existing].index) < len(index) {
+			// A shallower field already claimed fname; the deeper one
+			// (typically promoted from an embedded struct) loses
+			// silently, the same shallower-wins rule encoding/json uses.
+			return
+		}
+		if p.err == nil {
+			p.err = fmt.Errorf("influx: field %q and a sibling field both resolve to name %q", ft.Name, fname)
+		}
+		return
+	}
+
+	var durationUnit time.Duration
+	if ft.Type == durationType {
+		switch {
+		case tagHasOption(tagstr, "ns"):
+			durationUnit = time.Nanosecond
+		case tagHasOption(tagstr, "us"):
+			durationUnit = time.Microsecond
+		case tagHasOption(tagstr, "ms"):
+			durationUnit = time.Millisecond
+		case tagHasOption(tagstr, "s"), tagHasOption(tagstr, "seconds"):
+			durationUnit = time.Second
+		}
+	}
+
+	sliceStrat := sliceJoin
+	sliceSep := ","
+	if ft.Type.Kind() == reflect.Slice && ft.Type.Elem().Kind() != reflect.Uint8 {
+		switch {
+		case tagHasOption(tagstr, "explode"):
+			sliceStrat = sliceExplode
+		case tagHasOption(tagstr, "indexed"):
+			sliceStrat = sliceIndexed
+		case hasJoinSep:
+			sliceSep, _ = tagOptionValue(tagstr, "join")
+		}
+	}
+
+	defaultValue, hasDefault := tagOptionValue(tagstr, "default")
+
+	var setter fieldSetter
+	if durationUnit == 0 {
+		// A duration field (durationUnit != 0) is assigned by
+		// applyRowPlan's own durationUnit branch instead, which scales
+		// the value by the field's unit; a precompiled setter knows
+		// nothing about that and would assign the raw column value.
+		setter = buildFieldSetter(ft.Type)
+	}
+
+	p.fields = append(p.fields, fieldPlan{
+		index:         index,
+		name:          fname,
+		isTag:         tagHasOption(tagstr, "tag"),
+		omitempty:     tagHasOption(tagstr, "omitempty"),
+		durationUnit:  durationUnit,
+		boolTagAsInt:  ft.Type.Kind() == reflect.Bool && tagHasOption(tagstr, "01"),
+		asString:      tagHasOption(tagstr, "string"),
+		sliceStrategy: sliceStrat,
+		sliceSep:      sliceSep,
+		defaultValue:  defaultValue,
+		hasDefault:    hasDefault,
+		setter:        setter,
+	})
+	idx := len(p.fields) - 1
+	p.byName[fname] = idx
+	for _, alias := range aliases {
+		aliasName := prefix + alias
+		if _, ok := p.byName[aliasName]; !ok {
+			p.byName[aliasName] = idx
+		}
+	}
+}
+
+// fieldSetter is a precompiled, non-reflective assignment function for
+// one field's Kind, built once per fieldPlan by buildFieldSetter instead
+// of routing every row's value through parseSingle's full reflect.Kind
+// switch (and decodeField's hook checks) again. name is the column or
+// tag name the value came from, for an error message or
+// checkIntOverflow's report, the same as parseSingle's valCol.
+type fieldSetter func(field reflect.Value, name string, val interface{}) error
+
+// buildFieldSetter returns the fieldSetter for t's Kind, or nil when t
+// needs parseSingle's generic path instead: a pointer (which may need
+// to allocate, or be left nil for a null column), or a type with a
+// registered Converter or an sql.Scanner/encoding.TextUnmarshaler/
+// json.Unmarshaler method set, any of which must run through
+// decodeField ahead of the Kind switch.
+func buildFieldSetter(t reflect.Type) fieldSetter {
+	if t.Kind() == reflect.Ptr {
+		return nil
+	}
+	cp := planConv(t)
+	if cp.converter != nil || cp.weekday || cp.scanner || cp.textUnmarshal || cp.jsonUnmarshal {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return setIntField
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return setUintField
+	case reflect.Float32, reflect.Float64:
+		return setFloatField
+	case reflect.Bool:
+		return setBoolField
+	case reflect.String:
+		return setStringField
+	default:
+		return nil
+	}
+}
+
+// columnNullErr is the "column is null" error parseSingle's null check
+// reports under SetStrictDecoding, shared by every fieldSetter so their
+// wording stays identical to the generic path's.
+func columnNullErr(name string) error {
+	return fmt.Errorf("influx: column %q is null", name)
+}
+
+func setIntField(field reflect.Value, name string, val interface{}) error {
+	if val == nil {
+		if atomic.LoadInt32(&strictDecoding) != 0 {
+			return columnNullErr(name)
+		}
+		return nil
+	}
+	n := parseInt(val)
+	if atomic.LoadInt32(&strictNumericParsing) != 0 {
+		var err error
+		if n, err = checkedParseInt(val); err != nil {
+			return err
+		}
+	}
+	if atomic.LoadInt32(&strictIntDecoding) != 0 {
+		if err := checkIntOverflow(name, val, n, field.Kind()); err != nil {
+			return err
+		}
+	}
+	field.SetInt(n)
+	return nil
+}
+
+func setUintField(field reflect.Value, name string, val interface{}) error {
+	if val == nil {
+		if atomic.LoadInt32(&strictDecoding) != 0 {
+			return columnNullErr(name)
+		}
+		return nil
+	}
+	n := parseInt(val)
+	if atomic.LoadInt32(&strictNumericParsing) != 0 {
+		var err error
+		if n, err = checkedParseInt(val); err != nil {
+			return err
+		}
+	}
+	if atomic.LoadInt32(&strictIntDecoding) != 0 {
+		if err := checkIntOverflow(name, val, n, field.Kind()); err != nil {
+			return err
+		}
+	}
+	field.SetUint(uint64(n))
+	return nil
+}
+
+func setFloatField(field reflect.Value, name string, val interface{}) error {
+	if val == nil {
+		if atomic.LoadInt32(&strictDecoding) != 0 {
+			return columnNullErr(name)
+		}
+		return nil
+	}
+	f := parseFloat(val)
+	if atomic.LoadInt32(&strictNumericParsing) != 0 {
+		var err error
+		if f, err = checkedParseFloat(val); err != nil {
+			return err
+		}
+	}
+	field.SetFloat(f)
+	return nil
+}
+
+func setBoolField(field reflect.Value, name string, val interface{}) error {
+	if val == nil {
+		if atomic.LoadInt32(&strictDecoding) != 0 {
+			return columnNullErr(name)
+		}
+		return nil
+	}
+	field.SetBool(DecodeBool(val))
+	return nil
+}
+
+func setStringField(field reflect.Value, name string, val interface{}) error {
+	if val == nil {
+		if atomic.LoadInt32(&strictDecoding) != 0 {
+			return columnNullErr(name)
+		}
+		return nil
+	}
+	field.SetString(intern(parseString(val)))
+	return nil
+}
+
+// Register warms the reflection and conversion caches ToPoint and
+// ParseResult use for sample's type, so the first real Insert or Query
+// against it doesn't pay to build them. It is safe, but not required,
+// to call from an init function.
+func Register(sample interface{}) {
+	t := reflect.Indirect(reflect.ValueOf(sample)).Type()
+	plan := planType(t)
+	for _, fp := range plan.fields {
+		planConv(t.FieldByIndex(fp.index).Type)
+	}
+}
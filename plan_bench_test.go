@@ -0,0 +1,99 @@
+package influx
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+type benchMetric struct {
+	Time time.Time
+	Host string  `inf:"host,tag"`
+	CPU  float64 `inf:"cpu"`
+	Mem  float64 `inf:"mem"`
+}
+
+func BenchmarkToPoint(b *testing.B) {
+	m := benchMetric{Time: time.Now(), Host: "node-1", CPU: 0.42, Mem: 0.87}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ToPoint(&m); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkPlanTypeCached measures a cache-hit planType call in
+// isolation, the piece of ToPoint/ParseResult's cost TestPlanTypeCached
+// confirms is paid once per type rather than once per call.
+func BenchmarkPlanTypeCached(b *testing.B) {
+	typ := reflect.TypeOf(benchMetric{})
+	planType(typ) // warm the cache
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		planType(typ)
+	}
+}
+
+// BenchmarkPlanRowCached measures a cache-hit planRow call in
+// isolation, the piece of ParseResult's per-row cost TestPlanRowCached
+// confirms is paid once per (type, row shape) rather than once per row.
+func BenchmarkPlanRowCached(b *testing.B) {
+	typ := reflect.TypeOf(benchMetric{})
+	plan := planType(typ)
+	dst := reflect.New(typ).Elem()
+	cols := []string{"cpu", "mem"}
+	tags := map[string]string{"host": "node-1"}
+	planRow(dst, plan, cols, tags, nil) // warm the cache
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		planRow(dst, plan, cols, tags, nil)
+	}
+}
+
+func BenchmarkParseResult(b *testing.B) {
+	row := models.Row{
+		Columns: []string{"cpu", "mem"},
+		Tags:    map[string]string{"host": "node-1"},
+		Values: [][]interface{}{
+			{0.42, 0.87},
+		},
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var out []benchMetric
+		if err := ParseResult(&out, row); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkParseResultManyRows decodes a series with many rows of the
+// same shape in one ParseResult call, the scenario planRow's cache
+// exists for: only the first row pays resolveRowSlot's reflect tag
+// lookups, every later row in the same series reuses the cached
+// *rowPlan.
+func BenchmarkParseResultManyRows(b *testing.B) {
+	const numRows = 1000
+	values := make([][]interface{}, numRows)
+	for i := range values {
+		values[i] = []interface{}{0.42, 0.87}
+	}
+	row := models.Row{
+		Columns: []string{"cpu", "mem"},
+		Tags:    map[string]string{"host": "node-1"},
+		Values:  values,
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var out []benchMetric
+		if err := ParseResult(&out, row); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
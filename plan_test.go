@@ -0,0 +1,51 @@
+package influx
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestPlanTypeCached confirms planType builds a type's field plan once
+// and hands back the same cached *typePlan on every later call, which is
+// what lets ToPoint/ParseResult skip re-walking struct tags per call.
+func TestPlanTypeCached(t *testing.T) {
+	type planCacheMetric struct {
+		Host string `inf:"host,tag"`
+		CPU  float64
+	}
+
+	typ := reflect.TypeOf(planCacheMetric{})
+	first := planType(typ)
+	second := planType(typ)
+	if first != second {
+		t.Fatalf("planType(%v) built a new plan instead of reusing the cached one", typ)
+	}
+}
+
+// TestPlanRowCached confirms planRow builds a (struct type, columns,
+// tags) shape's rowPlan once and hands back the same cached *rowPlan
+// for every later row of that same shape, which is what keeps
+// alignToStruct's per-row cost independent of the struct's field count
+// once a series' first row has primed the cache.
+func TestPlanRowCached(t *testing.T) {
+	type planCacheMetric struct {
+		Host string `inf:"host,tag"`
+		CPU  float64
+	}
+
+	typ := reflect.TypeOf(planCacheMetric{})
+	plan := planType(typ)
+	dst := reflect.New(typ).Elem()
+	cols := []string{"cpu"}
+	tags := map[string]string{"host": "node-1"}
+
+	first := planRow(dst, plan, cols, tags, nil)
+	second := planRow(dst, plan, cols, tags, nil)
+	if first != second {
+		t.Fatal("planRow built a new rowPlan for the same shape instead of reusing the cached one")
+	}
+
+	if third := planRow(dst, plan, []string{"cpu", "mem"}, tags, nil); third == first {
+		t.Fatal("planRow reused a cached rowPlan for a different column shape")
+	}
+}
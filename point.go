@@ -0,0 +1,78 @@
+package influx
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// PointBuilder builds a client.Point tag and field at a time, for a
+// call site with no struct handy that still wants client.NewPoint's
+// validation (a point needs at least one field, a tag/field name can't
+// collide) instead of hand-building tags/fields maps. NewPoint starts
+// one; Build or Write ends it.
+type PointBuilder struct {
+	name   string
+	tags   map[string]string
+	fields map[string]interface{}
+	t      time.Time
+}
+
+// NewPoint starts a PointBuilder for the named measurement.
+func NewPoint(name string) *PointBuilder {
+	return &PointBuilder{name: name}
+}
+
+// Tag sets one tag on the point being built, overwriting any value
+// already set for key.
+func (b *PointBuilder) Tag(key, value string) *PointBuilder {
+	if b.tags == nil {
+		b.tags = make(map[string]string)
+	}
+	b.tags[key] = value
+	return b
+}
+
+// Field sets one field on the point being built, overwriting any value
+// already set for key.
+func (b *PointBuilder) Field(key string, value interface{}) *PointBuilder {
+	if b.fields == nil {
+		b.fields = make(map[string]interface{})
+	}
+	b.fields[key] = value
+	return b
+}
+
+// At sets the point's timestamp. Without it, Build leaves the
+// timestamp unset, same as client.NewPoint with no time argument: the
+// server assigns local time on reception.
+func (b *PointBuilder) At(t time.Time) *PointBuilder {
+	b.t = t
+	return b
+}
+
+// Build validates and returns the built point, the (*client.Point,
+// error) client.NewPoint itself returns.
+func (b *PointBuilder) Build() (*client.Point, error) {
+	if b.t.IsZero() {
+		return client.NewPoint(b.name, b.tags, b.fields)
+	}
+	return client.NewPoint(b.name, b.tags, b.fields, b.t)
+}
+
+// Write builds the point and inserts it into db using the default
+// Client, the same as Insert(db, point).
+func (b *PointBuilder) Write(db string) error {
+	return b.WriteContext(context.Background(), db)
+}
+
+// WriteContext is Write, aborting the request as soon as ctx is
+// canceled.
+func (b *PointBuilder) WriteContext(ctx context.Context, db string) error {
+	p, err := b.Build()
+	if err != nil {
+		return err
+	}
+	return InsertContext(ctx, db, p)
+}
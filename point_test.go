@@ -0,0 +1,47 @@
+package influx
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPointBuilderBuildsValidatedPoint confirms NewPoint's fluent
+// Tag/Field/At calls produce the same point client.NewPoint would from
+// equivalent maps.
+func TestPointBuilderBuildsValidatedPoint(t *testing.T) {
+	at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	p, err := NewPoint("cpu").
+		Tag("host", "a").
+		Field("usage", 0.5).
+		Field("count", int64(3)).
+		At(at).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name() != "cpu" {
+		t.Fatalf("Name() = %q, want %q", p.Name(), "cpu")
+	}
+	if tags := p.Tags(); tags["host"] != "a" {
+		t.Fatalf("Tags() = %v, want host=a", tags)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["usage"] != 0.5 || fields["count"] != int64(3) {
+		t.Fatalf("Fields() = %v, want usage=0.5, count=3", fields)
+	}
+	if !p.Time().Equal(at) {
+		t.Fatalf("Time() = %v, want %v", p.Time(), at)
+	}
+}
+
+// TestPointBuilderBuildFailsWithoutFields confirms Build surfaces
+// client.NewPoint's own validation error instead of building a point
+// with no fields.
+func TestPointBuilderBuildFailsWithoutFields(t *testing.T) {
+	if _, err := NewPoint("cpu").Tag("host", "a").Build(); err == nil {
+		t.Fatal("Build: want an error for a point with no fields, got nil")
+	}
+}
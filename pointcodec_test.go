@@ -0,0 +1,77 @@
+package influx
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// TestEncodeDecodePointsRoundTrips confirms a batch of points with a
+// mix of field types survives an EncodePoints/DecodePoints round trip
+// unchanged.
+func TestEncodeDecodePointsRoundTrips(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	p1, err := client.NewPoint("cpu", map[string]string{"host": "a"}, map[string]interface{}{
+		"count":  int64(3),
+		"total":  uint64(9),
+		"value":  1.5,
+		"ok":     true,
+		"region": "us",
+	}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := client.NewPoint("mem", nil, map[string]interface{}{"used": 42.0}, now.Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := EncodePoints(&buf, []*client.Point{p1, p2}); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := DecodePoints(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 {
+		t.Fatalf("len(decoded) = %d, want 2", len(decoded))
+	}
+
+	if decoded[0].Name() != "cpu" || decoded[0].Tags()["host"] != "a" {
+		t.Fatalf("decoded[0] = %v %v", decoded[0].Name(), decoded[0].Tags())
+	}
+	if !decoded[0].Time().Equal(now) {
+		t.Fatalf("decoded[0].Time() = %v, want %v", decoded[0].Time(), now)
+	}
+	fields, err := decoded[0].Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["count"] != int64(3) || fields["total"] != uint64(9) || fields["value"] != 1.5 || fields["ok"] != true || fields["region"] != "us" {
+		t.Fatalf("fields = %v", fields)
+	}
+
+	if decoded[1].Name() != "mem" {
+		t.Fatalf("decoded[1].Name() = %q", decoded[1].Name())
+	}
+}
+
+// TestEncodePointsEmptyBatch confirms an empty batch encodes and
+// decodes back to an empty (not nil-panicking) slice.
+func TestEncodePointsEmptyBatch(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodePoints(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodePoints(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 0 {
+		t.Fatalf("len(decoded) = %d, want 0", len(decoded))
+	}
+}
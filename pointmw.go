@@ -0,0 +1,62 @@
+package influx
+
+import "github.com/influxdata/influxdb/client/v2"
+
+// PointMiddleware transforms a point before it is written. Returning a
+// different *client.Point lets a middleware enrich or rename a point
+// (e.g. add a region or host tag); returning (nil, nil) drops the point
+// from the batch instead of writing it.
+type PointMiddleware func(*client.Point) (*client.Point, error)
+
+// UsePointMiddleware appends middleware to c's point-mutation chain,
+// applied in order to every point in every WriteBatchPointsContext call
+// (and therefore to Insert, InsertMany and their variants, which all
+// build a BatchPoints and hand it to WriteBatchPointsContext), without
+// touching any of their call sites.
+func (c *Client) UsePointMiddleware(middleware ...PointMiddleware) {
+	c.pointMiddleware = append(c.pointMiddleware, middleware...)
+}
+
+// applyPointMiddleware runs bp's points through c.pointMiddleware,
+// rebuilding bp with the result: client.BatchPoints has no way to
+// replace a point in place, only AddPoint/AddPoints.
+func (c *Client) applyPointMiddleware(bp client.BatchPoints) (client.BatchPoints, error) {
+	if len(c.pointMiddleware) == 0 {
+		return bp, nil
+	}
+
+	points := make([]*client.Point, 0, len(bp.Points()))
+	for _, p := range bp.Points() {
+		var err error
+		for _, mw := range c.pointMiddleware {
+			p, err = mw(p)
+			if err != nil {
+				return nil, err
+			}
+			if p == nil {
+				break
+			}
+		}
+		if p != nil {
+			points = append(points, p)
+		}
+	}
+
+	newBP, err := client.NewBatchPoints(client.BatchPointsConfig{
+		Database:         bp.Database(),
+		Precision:        bp.Precision(),
+		RetentionPolicy:  bp.RetentionPolicy(),
+		WriteConsistency: bp.WriteConsistency(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	newBP.AddPoints(points)
+	return newBP, nil
+}
+
+// UsePointMiddleware appends middleware to the package-level default
+// Client's point-mutation chain.
+func UsePointMiddleware(middleware ...PointMiddleware) {
+	gClient().UsePointMiddleware(middleware...)
+}
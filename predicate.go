@@ -0,0 +1,90 @@
+package influx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Predicate is a composable WHERE-clause fragment built by Tag, And
+// and Or. Its String is the raw InfluxQL text, parenthesized as needed
+// by whichever And/Or it was passed to — call String (or just pass it
+// to a %s verb) to get the text for SelectBuilder.Where or a
+// hand-written WHERE clause.
+type Predicate interface {
+	String() string
+}
+
+// rawPredicate is a Predicate that is already exactly the InfluxQL
+// text it prints, with no further composition to do.
+type rawPredicate string
+
+func (r rawPredicate) String() string { return string(r) }
+
+// groupPredicate is parts ANDed or ORed together, as built by And/Or.
+type groupPredicate struct {
+	op    string
+	parts []Predicate
+}
+
+func (g groupPredicate) String() string {
+	strs := make([]string, len(g.parts))
+	for i, p := range g.parts {
+		strs[i] = parenthesize(p)
+	}
+	return strings.Join(strs, " "+g.op+" ")
+}
+
+// parenthesize wraps p in parens if it's a multi-part group, so
+// nesting an Or inside an And (or vice versa) keeps its intended
+// precedence instead of relying on InfluxQL's own AND/OR precedence.
+func parenthesize(p Predicate) string {
+	if g, ok := p.(groupPredicate); ok && len(g.parts) > 1 {
+		return "(" + g.String() + ")"
+	}
+	return p.String()
+}
+
+// And ANDs preds together, parenthesizing any multi-part Or among them.
+func And(preds ...Predicate) Predicate {
+	return groupPredicate{op: "AND", parts: preds}
+}
+
+// Or ORs preds together, parenthesizing any multi-part And among them.
+func Or(preds ...Predicate) Predicate {
+	return groupPredicate{op: "OR", parts: preds}
+}
+
+// TagPredicate builds predicates comparing one tag, via Tag.
+type TagPredicate struct {
+	name string
+}
+
+// Tag starts a predicate comparing the tag named name, e.g.
+// Tag("host").In("a", "b", "c").
+func Tag(name string) *TagPredicate {
+	return &TagPredicate{name: name}
+}
+
+// Eq builds "tag = value".
+func (t *TagPredicate) Eq(value string) Predicate {
+	return rawPredicate(fmt.Sprintf("%s = %s", Ident(t.name), QuoteString(value)))
+}
+
+// Neq builds "tag != value".
+func (t *TagPredicate) Neq(value string) Predicate {
+	return rawPredicate(fmt.Sprintf("%s != %s", Ident(t.name), QuoteString(value)))
+}
+
+// In builds "tag = a OR tag = b OR ...", InfluxQL having no IN
+// operator of its own. An empty values ORs nothing and so matches no
+// series, rather than building an empty, always-true predicate.
+func (t *TagPredicate) In(values ...string) Predicate {
+	if len(values) == 0 {
+		return rawPredicate("false")
+	}
+	eqs := make([]Predicate, len(values))
+	for i, v := range values {
+		eqs[i] = t.Eq(v)
+	}
+	return Or(eqs...)
+}
@@ -0,0 +1,187 @@
+// Package prom exposes an eachain/influx Client's query/write activity
+// and a BufferedWriter's queue depth as Prometheus metrics: an
+// Interceptor to pass to Client.Use for per-request counts, latency
+// and errors, and Collectors wrapping Client.Stats/BufferedWriter.Stats
+// for the cumulative counters those already track internally — so the
+// influx client itself shows up in the same metrics stack it writes
+// application data to, instead of being a blind spot.
+package prom
+
+import (
+	"context"
+	"time"
+
+	"github.com/eachain/influx"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Interceptor records per-request counts, latency and errors for every
+// query and write a Client runs, once registered with a Prometheus
+// registry (Interceptor is itself a prometheus.Collector) and passed
+// to Client.Use as its Intercept method.
+type Interceptor struct {
+	requestsTotal   *prometheus.CounterVec
+	requestErrors   *prometheus.CounterVec
+	requestDuration *prometheus.CounterVec
+	writePoints     *prometheus.CounterVec
+}
+
+// NewInterceptor returns an Interceptor ready to register and use:
+//
+//	pi := prom.NewInterceptor()
+//	prometheus.MustRegister(pi)
+//	c.Use(pi.Intercept)
+func NewInterceptor() *Interceptor {
+	labels := []string{"kind", "database"}
+	return &Interceptor{
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "influx",
+			Name:      "requests_total",
+			Help:      "Total number of queries and writes run.",
+		}, labels),
+		requestErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "influx",
+			Name:      "request_errors_total",
+			Help:      "Total number of queries and writes that returned an error.",
+		}, labels),
+		requestDuration: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "influx",
+			Name:      "request_duration_seconds_total",
+			Help:      "Total time spent running queries and writes, in seconds. Divide by requests_total's rate for an average latency.",
+		}, labels),
+		writePoints: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "influx",
+			Name:      "write_points_total",
+			Help:      "Total number of points submitted in WriteBatchPointsContext calls.",
+		}, []string{"database"}),
+	}
+}
+
+// Intercept is an influx.Interceptor; pass it to Client.Use.
+func (i *Interceptor) Intercept(ctx context.Context, info influx.RequestInfo, next func(context.Context) error) error {
+	start := time.Now()
+	err := next(ctx)
+
+	i.requestsTotal.WithLabelValues(info.Kind, info.Database).Inc()
+	i.requestDuration.WithLabelValues(info.Kind, info.Database).Add(time.Since(start).Seconds())
+	if err != nil {
+		i.requestErrors.WithLabelValues(info.Kind, info.Database).Inc()
+	}
+	if info.Kind == influx.RequestWrite {
+		i.writePoints.WithLabelValues(info.Database).Add(float64(info.Points))
+	}
+	return err
+}
+
+// Describe implements prometheus.Collector.
+func (i *Interceptor) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range i.collectors() {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (i *Interceptor) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range i.collectors() {
+		c.Collect(ch)
+	}
+}
+
+func (i *Interceptor) collectors() []prometheus.Collector {
+	return []prometheus.Collector{i.requestsTotal, i.requestErrors, i.requestDuration, i.writePoints}
+}
+
+// StatsCollector exposes a Client's own write counters (points and
+// bytes written, batches flushed, retries, dropped points — see
+// influx.WriteStats) as Prometheus metrics, read fresh from
+// Client.Stats on every scrape instead of duplicating the bookkeeping
+// Client already does.
+type StatsCollector struct {
+	c *influx.Client
+
+	pointsWritten  *prometheus.Desc
+	bytesWritten   *prometheus.Desc
+	batchesFlushed *prometheus.Desc
+	retries        *prometheus.Desc
+	droppedPoints  *prometheus.Desc
+}
+
+// NewStatsCollector returns a StatsCollector for c.
+func NewStatsCollector(c *influx.Client) *StatsCollector {
+	return &StatsCollector{
+		c: c,
+		pointsWritten: prometheus.NewDesc("influx_write_points_written_total",
+			"Total points in batches written successfully.", nil, nil),
+		bytesWritten: prometheus.NewDesc("influx_write_bytes_written_total",
+			"Total line-protocol bytes written successfully.", nil, nil),
+		batchesFlushed: prometheus.NewDesc("influx_write_batches_flushed_total",
+			"Total batches flushed successfully.", nil, nil),
+		retries: prometheus.NewDesc("influx_write_retries_total",
+			"Total write retry attempts made by WriteRetry.", nil, nil),
+		droppedPoints: prometheus.NewDesc("influx_write_dropped_points_total",
+			"Total points dropped after a failed write and, if Spool was enabled, a failed spool too.", nil, nil),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (s *StatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.pointsWritten
+	ch <- s.bytesWritten
+	ch <- s.batchesFlushed
+	ch <- s.retries
+	ch <- s.droppedPoints
+}
+
+// Collect implements prometheus.Collector.
+func (s *StatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := s.c.Stats()
+	ch <- prometheus.MustNewConstMetric(s.pointsWritten, prometheus.CounterValue, float64(stats.PointsWritten))
+	ch <- prometheus.MustNewConstMetric(s.bytesWritten, prometheus.CounterValue, float64(stats.BytesWritten))
+	ch <- prometheus.MustNewConstMetric(s.batchesFlushed, prometheus.CounterValue, float64(stats.BatchesFlushed))
+	ch <- prometheus.MustNewConstMetric(s.retries, prometheus.CounterValue, float64(stats.Retries))
+	ch <- prometheus.MustNewConstMetric(s.droppedPoints, prometheus.CounterValue, float64(stats.DroppedPoints))
+}
+
+// BufferedWriterCollector exposes a BufferedWriter's queue depth and
+// backpressure counters (see influx.WriterStats) as Prometheus
+// metrics.
+type BufferedWriterCollector struct {
+	w *influx.BufferedWriter
+
+	queueDepth    *prometheus.Desc
+	droppedOldest *prometheus.Desc
+	droppedNewest *prometheus.Desc
+	rejected      *prometheus.Desc
+}
+
+// NewBufferedWriterCollector returns a BufferedWriterCollector for w.
+func NewBufferedWriterCollector(w *influx.BufferedWriter) *BufferedWriterCollector {
+	return &BufferedWriterCollector{
+		w: w,
+		queueDepth: prometheus.NewDesc("influx_writer_queue_depth",
+			"Number of points currently queued, waiting for the next flush.", nil, nil),
+		droppedOldest: prometheus.NewDesc("influx_writer_dropped_oldest_total",
+			"Total points dropped to make room for a new one under BackpressureDropOldest.", nil, nil),
+		droppedNewest: prometheus.NewDesc("influx_writer_dropped_newest_total",
+			"Total points dropped instead of queued under BackpressureDropNewest.", nil, nil),
+		rejected: prometheus.NewDesc("influx_writer_rejected_total",
+			"Total Write calls that returned ErrBufferFull under BackpressureReject.", nil, nil),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (b *BufferedWriterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- b.queueDepth
+	ch <- b.droppedOldest
+	ch <- b.droppedNewest
+	ch <- b.rejected
+}
+
+// Collect implements prometheus.Collector.
+func (b *BufferedWriterCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := b.w.Stats()
+	ch <- prometheus.MustNewConstMetric(b.queueDepth, prometheus.GaugeValue, float64(b.w.Len()))
+	ch <- prometheus.MustNewConstMetric(b.droppedOldest, prometheus.CounterValue, float64(stats.DroppedOldest))
+	ch <- prometheus.MustNewConstMetric(b.droppedNewest, prometheus.CounterValue, float64(stats.DroppedNewest))
+	ch <- prometheus.MustNewConstMetric(b.rejected, prometheus.CounterValue, float64(stats.Rejected))
+}
@@ -0,0 +1,131 @@
+package influx
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// DefaultJobMeasurement names the measurement Job.Push writes to when
+// the Job wasn't given a Measurement of its own.
+const DefaultJobMeasurement = "job"
+
+// DefaultJobPushAttempts is Job.Push's default number of attempts.
+const DefaultJobPushAttempts = 3
+
+// DefaultJobPushDelay is the default delay Job.Push waits between
+// attempts.
+const DefaultJobPushDelay = time.Second
+
+// Job accumulates counters for a short-lived batch job's lifetime, to
+// flush them all as a single point when the job finishes — the same
+// one-shot workflow Prometheus's Pushgateway exists for: a batch job's
+// process is usually gone before a pull-based scrape could ever see
+// its metrics, so Job collects them in-process instead and pushes one
+// summary point tagged with the job's name and instance when the run
+// is done.
+type Job struct {
+	// Measurement overrides DefaultJobMeasurement.
+	Measurement string
+
+	mu       sync.Mutex
+	tags     map[string]string
+	counters map[string]float64
+}
+
+// NewJob returns a Job tagged with "job" and "instance", the same two
+// labels a Pushgateway push is grouped by.
+func NewJob(name, instance string) *Job {
+	return &Job{
+		tags:     map[string]string{"job": name, "instance": instance},
+		counters: make(map[string]float64),
+	}
+}
+
+// Tag sets an additional tag on j, e.g. an environment or a batch id,
+// and returns j for chaining.
+func (j *Job) Tag(key, value string) *Job {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.tags[key] = value
+	return j
+}
+
+// Add adds delta to counter's accumulated value.
+func (j *Job) Add(counter string, delta float64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.counters[counter] += delta
+}
+
+// Inc adds 1 to counter's accumulated value.
+func (j *Job) Inc(counter string) {
+	j.Add(counter, 1)
+}
+
+// Push writes j's accumulated counters to db via c as a single point,
+// making up to DefaultJobPushAttempts attempts in all, DefaultJobPushDelay
+// apart, and is Push's usual entry point.
+func (j *Job) Push(c *Client, db string) error {
+	return j.PushContext(context.Background(), c, db, DefaultJobPushAttempts, DefaultJobPushDelay)
+}
+
+// PushContext is Push with attempts and delay overridden, and a
+// context that both bounds the write itself and aborts a pending
+// retry delay early.
+//
+// Job retries on its own instead of relying on Client's WriteRetry:
+// that config backs off exponentially over minutes, tuned for a
+// long-running process that can afford to wait, while a batch job
+// calling Push is usually about to exit — losing this write loses the
+// run's metrics for good, so Push instead makes a small, fixed number
+// of quick attempts before giving up, trading a few extra seconds of
+// exit latency for not silently dropping the job's results.
+func (j *Job) PushContext(ctx context.Context, c *Client, db string, attempts int, delay time.Duration) error {
+	if attempts <= 0 {
+		attempts = DefaultJobPushAttempts
+	}
+
+	j.mu.Lock()
+	fields := make(map[string]interface{}, len(j.counters))
+	for name, v := range j.counters {
+		fields[name] = v
+	}
+	tags := make(map[string]string, len(j.tags))
+	for k, v := range j.tags {
+		tags[k] = v
+	}
+	j.mu.Unlock()
+
+	if len(fields) == 0 {
+		fields["count"] = 0.0
+	}
+
+	measurement := j.Measurement
+	if measurement == "" {
+		measurement = DefaultJobMeasurement
+	}
+
+	p, err := client.NewPoint(measurement, tags, fields, clockNow())
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if lastErr = c.InsertContext(ctx, db, p); lastErr == nil {
+			return nil
+		}
+		if attempt == attempts {
+			break
+		}
+		select {
+		case <-after(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return lastErr
+}
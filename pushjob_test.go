@@ -0,0 +1,103 @@
+package influx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// TestJobPushWritesAccumulatedCounters confirms Push writes one point
+// tagged with job/instance, carrying every counter's accumulated
+// total.
+func TestJobPushWritesAccumulatedCounters(t *testing.T) {
+	var gotLine string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := make([]byte, r.ContentLength)
+		r.Body.Read(body)
+		gotLine = string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j := NewJob("nightly-import", "host-1")
+	j.Add("rows_imported", 100)
+	j.Add("rows_imported", 50)
+	j.Inc("errors")
+
+	if err := j.Push(c, "metrics"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(gotLine, "job=nightly-import") || !strings.Contains(gotLine, "instance=host-1") {
+		t.Fatalf("line = %q, want job/instance tags", gotLine)
+	}
+	if !strings.Contains(gotLine, "rows_imported=150") || !strings.Contains(gotLine, "errors=1") {
+		t.Fatalf("line = %q, want accumulated counters", gotLine)
+	}
+}
+
+// TestJobPushRetriesOnFailure confirms Push retries a failed write,
+// making up to attempts tries in all: here it succeeds on the third of 3.
+func TestJobPushRetriesOnFailure(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j := NewJob("job", "instance")
+	j.Inc("runs")
+
+	if err := j.PushContext(context.Background(), c, "metrics", 3, time.Millisecond); err != nil {
+		t.Fatalf("Push never succeeded: %v", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Fatalf("attempts = %d, want 3", got)
+	}
+}
+
+// TestJobPushGivesUpAfterAttempts confirms Push stops retrying once
+// attempts is exhausted and returns the last error.
+func TestJobPushGivesUpAfterAttempts(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j := NewJob("job", "instance")
+	j.Inc("runs")
+
+	if err := j.PushContext(context.Background(), c, "metrics", 2, time.Millisecond); err == nil {
+		t.Fatal("want error")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Fatalf("attempts = %d, want 2", got)
+	}
+}
@@ -0,0 +1,267 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+var float64Type = reflect.TypeOf(float64(0))
+
+// QueryWithParams runs cmd against db using InfluxQL bound parameters
+// (e.g. `SELECT * FROM cpu WHERE host = $host`), letting callers inject
+// user-supplied values without risking InfluxQL injection. This is
+// this package's answer to client.Query.Parameters: it sets Parameters
+// on the underlying client.Query for you instead of requiring callers
+// to build one by hand.
+func (c *Client) QueryWithParams(db, cmd string, params map[string]interface{}) ([]client.Result, error) {
+	return c.QueryWithParamsContext(context.Background(), db, cmd, params)
+}
+
+// paramsQuery builds the client.Query QueryWithParamsContext and
+// QueryBindContext both send, factored out so the bound-params shape
+// is defined in exactly one place.
+func paramsQuery(db, cmd string, params map[string]interface{}) client.Query {
+	return client.Query{
+		Command:    cmd,
+		Database:   db,
+		Parameters: params,
+	}
+}
+
+// QueryWithParamsContext is QueryWithParams with a context that aborts
+// the request as soon as it is canceled.
+func (c *Client) QueryWithParamsContext(ctx context.Context, db, cmd string, params map[string]interface{}) ([]client.Result, error) {
+	db, _ = c.resolveTenant(ctx, db)
+	var response *client.Response
+	info := RequestInfo{Kind: RequestQuery, Database: db, Command: cmd, Label: LabelFromContext(ctx)}
+	err := c.intercept(ctx, info, func(ctx context.Context) error {
+		return c.guard(func() error {
+			var opErr error
+			replica, idx := c.nextReplica()
+			replicaStart := clockNow()
+			response, opErr = replica.QueryCtx(ctx, paramsQuery(db, cmd, params))
+			c.recordReplicaLatency(idx, clockNow().Sub(replicaStart))
+			return opErr
+		})
+	})
+	queryErr := err
+	if queryErr == nil && response != nil {
+		queryErr = response.Error()
+	}
+	if c.debugHook != nil {
+		c.reportDebug(cmd, response, queryErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if response.Error() != nil {
+		return response.Results, response.Error()
+	}
+	return response.Results, nil
+}
+
+// QueryWithParams runs cmd against db with bound parameters, using the
+// default Client.
+func QueryWithParams(db, cmd string, params map[string]interface{}) ([]client.Result, error) {
+	return gClient().QueryWithParams(db, cmd, params)
+}
+
+var bindParamPattern = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// QueryBind runs cmd against db, harvesting its `$name`-style bound
+// parameters from the `inf` tags of bind's fields instead of requiring
+// callers to build a params map by hand.
+func (c *Client) QueryBind(db, cmd string, bind interface{}) ([]client.Result, error) {
+	return c.QueryBindContext(context.Background(), db, cmd, bind)
+}
+
+// QueryBindContext is QueryBind with a context that aborts the request
+// as soon as it is canceled.
+func (c *Client) QueryBindContext(ctx context.Context, db, cmd string, bind interface{}) ([]client.Result, error) {
+	params, err := bindParams(cmd, bind)
+	if err != nil {
+		return nil, err
+	}
+	return c.QueryWithParamsContext(ctx, db, cmd, params)
+}
+
+// QueryBind runs cmd against db with parameters bound from bind, using
+// the default Client.
+func QueryBind(db, cmd string, bind interface{}) ([]client.Result, error) {
+	return gClient().QueryBind(db, cmd, bind)
+}
+
+func bindParams(cmd string, bind interface{}) (map[string]interface{}, error) {
+	val := reflect.Indirect(reflect.ValueOf(bind))
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("influx: QueryBind: %w", ErrNotPointer)
+	}
+	bp := planBind(val.Type())
+
+	params := make(map[string]interface{})
+	for _, m := range bindParamPattern.FindAllStringSubmatch(cmd, -1) {
+		name := m[1]
+		if _, ok := params[name]; ok {
+			continue
+		}
+		for _, bf := range bp.fields {
+			if bf.name == name {
+				params[name] = bindParamValue(bf.isTime, val.Field(bf.index))
+				break
+			}
+		}
+	}
+	return params, nil
+}
+
+// bindField is one field QueryBind can bind a parameter from, compiled
+// once per type by planBind instead of bindParams re-splitting every
+// field's inf tag on every call.
+type bindField struct {
+	index  int
+	name   string
+	isTime bool
+}
+
+// bindPlan is the compiled, cached result of walking a QueryBind bind
+// struct's fields, the same caching discipline planType applies to
+// ToPoint/ParseResult's struct walk.
+type bindPlan struct {
+	fields []bindField
+}
+
+var bindPlans sync.Map // reflect.Type -> *bindPlan
+
+// planBind returns t's cached bindPlan, building and caching one the
+// first time t is seen.
+func planBind(t reflect.Type) *bindPlan {
+	if v, ok := bindPlans.Load(t); ok {
+		return v.(*bindPlan)
+	}
+	bp := buildBindPlan(t)
+	actual, _ := bindPlans.LoadOrStore(t, bp)
+	return actual.(*bindPlan)
+}
+
+// resetBindPlans drops every cached bind plan, for SetStructTagKey,
+// the same way resetTypePlans does for typePlans.
+func resetBindPlans() {
+	bindPlans.Range(func(k, _ interface{}) bool {
+		bindPlans.Delete(k)
+		return true
+	})
+}
+
+func buildBindPlan(t reflect.Type) *bindPlan {
+	bp := &bindPlan{}
+	for f := 0; f < t.NumField(); f++ {
+		tagstr := structTag(t.Field(f))
+		name := strings.Split(tagstr, ",")[0]
+		if name == "" {
+			continue
+		}
+		bp.fields = append(bp.fields, bindField{index: f, name: name, isTime: tagstr == "time"})
+	}
+	return bp
+}
+
+// bindParamValue converts a bind struct field to a value suitable for
+// client.Query.Parameters, reusing the same parseString/parseFloat/
+// parseTime helpers ParseResult uses to decode query results.
+func bindParamValue(isTime bool, fv reflect.Value) interface{} {
+	if fv.Type() == timeType {
+		return fv.Interface().(time.Time).Format(time.RFC3339Nano)
+	}
+	if isTime {
+		return parseTime(fv.Interface()).Format(time.RFC3339Nano)
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		return parseString(fv.Interface())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return parseFloat(fv.Interface())
+	default:
+		return fv.Interface()
+	}
+}
+
+// Ident quotes s as an InfluxQL identifier (e.g. a measurement or field
+// name), for use where a value cannot be bound as a query parameter.
+func Ident(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// QuoteIdent is Ident, under the name InfluxQL's own documentation
+// uses for quoting an identifier.
+func QuoteIdent(name string) string {
+	return Ident(name)
+}
+
+// RPFrom builds a retention-policy-qualified measurement reference —
+// "db"."rp"."measurement" — quoting each part with Ident, so a query
+// against a non-default retention policy doesn't have to be
+// hand-assembled one string concatenation at a time, a case that's
+// easy to get wrong (a missing quote, or dots in the wrong place, earn
+// either a parser error or, worse, a silently different measurement).
+// db may be "" to omit it and let InfluxQL resolve the database from
+// context, producing "rp"."measurement"; rp may independently be ""
+// (with db non-empty) to fall back to measurement's default retention
+// policy while still qualifying the database, producing
+// "db".."measurement", the blank-middle-segment form InfluxQL itself
+// uses for that. If both are "", RPFrom is just Ident(measurement).
+func RPFrom(db, rp, measurement string) string {
+	switch {
+	case db == "" && rp == "":
+		return Ident(measurement)
+	case db == "":
+		return Ident(rp) + "." + Ident(measurement)
+	case rp == "":
+		return Ident(db) + ".." + Ident(measurement)
+	default:
+		return Ident(db) + "." + Ident(rp) + "." + Ident(measurement)
+	}
+}
+
+// QuoteString quotes value as an InfluxQL string literal (e.g. a tag
+// value compared with = in a WHERE clause that, unlike a bound
+// parameter, must be written directly into the command), escaping
+// backslashes and single quotes the way InfluxQL requires.
+func QuoteString(value string) string {
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	return `'` + r.Replace(value) + `'`
+}
+
+// Queryf formats cmd like fmt.Sprintf, but quotes every string and
+// time.Time argument first — a string via QuoteString, a time.Time as
+// an RFC3339Nano string literal — so a value fed to a %s verb can't
+// break out of its quotes and inject arbitrary InfluxQL the way a bare
+// fmt.Sprintf("... WHERE host = '%s'", host) could. Prefer
+// QueryWithParams's bound $parameters where the target is a whole
+// command InfluxDB itself will parse; Queryf is for assembling a
+// clause (e.g. inside a SelectBuilder.Where) that QueryWithParams
+// doesn't reach.
+func Queryf(cmd string, args ...interface{}) string {
+	escaped := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			escaped[i] = QuoteString(v)
+		case time.Time:
+			escaped[i] = QuoteString(v.Format(time.RFC3339Nano))
+		default:
+			escaped[i] = v
+		}
+	}
+	return fmt.Sprintf(cmd, escaped...)
+}
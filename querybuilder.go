@@ -0,0 +1,135 @@
+package influx
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// SelectBuilder builds a SELECT statement piece by piece, the way
+// Ident/QuoteString/Last/Since/Between/GroupByTime build the pieces of
+// one: From accepts either a measurement name or another *SelectBuilder,
+// rendering the latter as a parenthesized InfluxQL subquery instead of
+// requiring callers to hand-write (and frequently mis-quote) one.
+//
+//	inner := Select("mean(value)").From(`"cpu"`).GroupBy("time(1m),host")
+//	outer := Select("max(mean)").From(inner).GroupBy("host")
+//	outer.String() // SELECT max(mean) FROM (SELECT mean(value) FROM "cpu" GROUP BY time(1m),host) GROUP BY host
+type SelectBuilder struct {
+	selectExpr string
+	from       interface{}
+	where      string
+	groupBy    string
+	limit      int
+	offset     int
+	sLimit     int
+	sOffset    int
+}
+
+// Select starts a SelectBuilder with expr as its SELECT field list or
+// aggregate expression, e.g. "mean(value)" or "*".
+func Select(expr string) *SelectBuilder {
+	return &SelectBuilder{selectExpr: expr}
+}
+
+// From sets the builder's source: a string measurement name (e.g.
+// `"cpu"`, already Ident-quoted), a *regexp.Regexp to match every
+// measurement whose name matches it, or another *SelectBuilder to nest
+// as an InfluxQL subquery.
+func (b *SelectBuilder) From(from interface{}) *SelectBuilder {
+	b.from = from
+	return b
+}
+
+// Where sets the builder's WHERE predicate, a raw InfluxQL condition
+// (e.g. built with Ident/QuoteString/Last/Since/Between).
+func (b *SelectBuilder) Where(where string) *SelectBuilder {
+	b.where = where
+	return b
+}
+
+// GroupBy sets the builder's GROUP BY clause, appended as-is, e.g.
+// "time(1h),*" or the result of GroupByTime.
+func (b *SelectBuilder) GroupBy(groupBy string) *SelectBuilder {
+	b.groupBy = groupBy
+	return b
+}
+
+// GroupByTime sets the builder's GROUP BY clause to
+// GROUP BY time(interval), the fluent equivalent of
+// GroupBy(GroupByTime(interval, 0, "")). Use GroupBy directly for a
+// bucket offset or a fill() option.
+func (b *SelectBuilder) GroupByTime(interval time.Duration) *SelectBuilder {
+	return b.GroupBy(GroupByTime(interval, 0, ""))
+}
+
+// Limit sets the builder's LIMIT clause, capping the number of rows
+// (not series) a query returns; see SLimit to cap series instead.
+func (b *SelectBuilder) Limit(n int) *SelectBuilder {
+	b.limit = n
+	return b
+}
+
+// Offset sets the builder's OFFSET clause, skipping the first n rows
+// a query would otherwise return; see SOffset to skip series instead.
+func (b *SelectBuilder) Offset(n int) *SelectBuilder {
+	b.offset = n
+	return b
+}
+
+// SLimit sets the builder's SLIMIT clause, capping the number of
+// series (not rows) a GROUP BY query returns.
+func (b *SelectBuilder) SLimit(n int) *SelectBuilder {
+	b.sLimit = n
+	return b
+}
+
+// SOffset sets the builder's SOFFSET clause, skipping the first n
+// series (not rows) a GROUP BY query would otherwise return — paired
+// with SLimit to page through a GROUP BY query's series; see also
+// PageSeries, which drives SLimit/SOffset across pages automatically.
+func (b *SelectBuilder) SOffset(n int) *SelectBuilder {
+	b.sOffset = n
+	return b
+}
+
+// String renders the built SELECT statement.
+func (b *SelectBuilder) String() string {
+	cmd := fmt.Sprintf("SELECT %s FROM %s", b.selectExpr, fromClause(b.from))
+	if b.where != "" {
+		cmd += " WHERE " + b.where
+	}
+	if b.groupBy != "" {
+		cmd += " GROUP BY " + b.groupBy
+	}
+	if b.limit > 0 {
+		cmd += fmt.Sprintf(" LIMIT %d", b.limit)
+	}
+	if b.offset > 0 {
+		cmd += fmt.Sprintf(" OFFSET %d", b.offset)
+	}
+	if b.sLimit > 0 {
+		cmd += fmt.Sprintf(" SLIMIT %d", b.sLimit)
+	}
+	if b.sOffset > 0 {
+		cmd += fmt.Sprintf(" SOFFSET %d", b.sOffset)
+	}
+	return cmd
+}
+
+// fromClause renders from, a SelectBuilder's From argument, nesting a
+// *SelectBuilder in parentheses as an InfluxQL subquery, rendering a
+// *regexp.Regexp as an InfluxQL regex literal via regexLiteral, and
+// passing a string measurement name through unchanged.
+func fromClause(from interface{}) string {
+	switch v := from.(type) {
+	case *SelectBuilder:
+		return "(" + v.String() + ")"
+	case *regexp.Regexp:
+		return regexLiteral(v)
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
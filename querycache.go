@@ -0,0 +1,196 @@
+package influx
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// queryCache holds recently-seen Query results keyed by (db, normalized
+// cmd), so that identical dashboard queries issued by many callers
+// within ttl of each other hit InfluxDB only once. It evicts the oldest
+// entry once len(entries) exceeds maxEntries, and guards entries/order
+// with its own mutex independently of any Client.
+type queryCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[cacheKey]*cacheEntry
+	order      []cacheKey
+}
+
+type cacheKey struct {
+	db  string
+	cmd string
+}
+
+type cacheEntry struct {
+	results   []client.Result
+	err       error
+	expiresAt time.Time
+}
+
+func newQueryCache(ttl time.Duration, maxEntries int) *queryCache {
+	return &queryCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[cacheKey]*cacheEntry),
+	}
+}
+
+func (qc *queryCache) get(key cacheKey) (results []client.Result, err error, ok bool) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	entry := qc.entries[key]
+	if entry == nil {
+		return nil, nil, false
+	}
+	if clockNow().After(entry.expiresAt) {
+		delete(qc.entries, key)
+		return nil, nil, false
+	}
+	return entry.results, entry.err, true
+}
+
+func (qc *queryCache) set(key cacheKey, results []client.Result, err error) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	if _, exists := qc.entries[key]; !exists {
+		qc.order = append(qc.order, key)
+		for len(qc.order) > qc.maxEntries {
+			oldest := qc.order[0]
+			qc.order = qc.order[1:]
+			delete(qc.entries, oldest)
+		}
+	}
+	qc.entries[key] = &cacheEntry{
+		results:   results,
+		err:       err,
+		expiresAt: clockNow().Add(qc.ttl),
+	}
+}
+
+// invalidate clears cached entries matching db and cmd: both empty
+// clears the whole cache, cmd empty clears every entry for db, and both
+// set clears that one entry.
+func (qc *queryCache) invalidate(db, cmd string) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	if db == "" && cmd == "" {
+		qc.entries = make(map[cacheKey]*cacheEntry)
+		qc.order = nil
+		return
+	}
+
+	var kept []cacheKey
+	for _, key := range qc.order {
+		if key.db == db && (cmd == "" || key.cmd == cmd) {
+			delete(qc.entries, key)
+			continue
+		}
+		kept = append(kept, key)
+	}
+	qc.order = kept
+}
+
+// normalizeQuery collapses cmd's whitespace so that queries differing
+// only in spacing or line breaks share the same cache entry.
+func normalizeQuery(cmd string) string {
+	return strings.Join(strings.Fields(cmd), " ")
+}
+
+// EnableQueryCache turns on CachedQueryContext's cache, keyed by (db,
+// normalized cmd), with entries expiring after ttl and at most
+// maxEntries entries kept at once. Calling it again replaces the
+// existing cache, discarding its entries.
+func (c *Client) EnableQueryCache(ttl time.Duration, maxEntries int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queryCache = newQueryCache(ttl, maxEntries)
+}
+
+// DisableQueryCache turns the cache back off; CachedQueryContext then
+// behaves exactly like QueryContext.
+func (c *Client) DisableQueryCache() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queryCache = nil
+}
+
+// InvalidateQueryCache evicts cached entries matching db and cmd: both
+// "" evicts everything, cmd "" evicts every entry for db, and both set
+// evicts that one entry. It is a no-op if the cache is disabled.
+func (c *Client) InvalidateQueryCache(db, cmd string) {
+	c.mu.RLock()
+	qc := c.queryCache
+	c.mu.RUnlock()
+	if qc == nil {
+		return
+	}
+	if cmd != "" {
+		cmd = normalizeQuery(cmd)
+	}
+	qc.invalidate(db, cmd)
+}
+
+// CachedQuery is CachedQueryContext using context.Background.
+func (c *Client) CachedQuery(db, cmd string) ([]client.Result, error) {
+	return c.CachedQueryContext(context.Background(), db, cmd)
+}
+
+// CachedQueryContext runs cmd against db like QueryContext, except that
+// when EnableQueryCache has been called, a result already cached for
+// (db, normalized cmd) within its ttl is returned without contacting
+// InfluxDB. It never caches on its own: callers that want caching must
+// call CachedQueryContext explicitly instead of Query/QueryContext,
+// since caching a DDL statement or any other non-idempotent query would
+// silently serve a stale answer.
+func (c *Client) CachedQueryContext(ctx context.Context, db, cmd string) ([]client.Result, error) {
+	c.mu.RLock()
+	qc := c.queryCache
+	c.mu.RUnlock()
+
+	if qc == nil {
+		return c.QueryContext(ctx, db, cmd)
+	}
+
+	key := cacheKey{db: db, cmd: normalizeQuery(cmd)}
+	if results, err, ok := qc.get(key); ok {
+		return results, err
+	}
+
+	results, err := c.QueryContext(ctx, db, cmd)
+	qc.set(key, results, err)
+	return results, err
+}
+
+// EnableQueryCache enables caching on the default Client.
+func EnableQueryCache(ttl time.Duration, maxEntries int) {
+	gClient().EnableQueryCache(ttl, maxEntries)
+}
+
+// DisableQueryCache disables caching on the default Client.
+func DisableQueryCache() {
+	gClient().DisableQueryCache()
+}
+
+// InvalidateQueryCache invalidates cache entries on the default Client.
+func InvalidateQueryCache(db, cmd string) {
+	gClient().InvalidateQueryCache(db, cmd)
+}
+
+// CachedQuery runs CachedQuery using the default Client.
+func CachedQuery(db, cmd string) ([]client.Result, error) {
+	return gClient().CachedQuery(db, cmd)
+}
+
+// CachedQueryContext runs CachedQueryContext using the default Client.
+func CachedQueryContext(ctx context.Context, db, cmd string) ([]client.Result, error) {
+	return gClient().CachedQueryContext(ctx, db, cmd)
+}
@@ -0,0 +1,47 @@
+package influx
+
+import "context"
+
+// QueryChan runs cmd against db using the default Client's chunked
+// query transfer encoding and decodes each row into a T, sending it on
+// the returned channel as soon as it's decoded instead of QueryRows'
+// materialize-everything-up-front []T — the generic, channel-based
+// counterpart to RowIter/QueryIter for pipeline-style processing (a
+// consumer goroutine ranging over rows as they arrive) of a result too
+// large to hold in memory at once.
+//
+// The returned error channel carries at most one value — the first
+// terminal error encountered building the query or iterating its rows
+// (see RowIter.Err) — sent only after the data channel is closed, so a
+// caller should drain the data channel (e.g. with range) before
+// checking it. Canceling ctx stops decoding and closes both channels
+// without sending any further rows.
+func QueryChan[T any](ctx context.Context, db, cmd string) (<-chan T, <-chan error) {
+	out := make(chan T)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errc)
+
+		it, err := QueryIter[T](ctx, db, cmd)
+		if err != nil {
+			errc <- err
+			return
+		}
+		defer it.Close()
+
+		for it.Next() {
+			select {
+			case out <- it.Value():
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			errc <- err
+		}
+	}()
+
+	return out, errc
+}
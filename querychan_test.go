@@ -0,0 +1,62 @@
+package influx
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestQueryChanSendsEachDecodedRow confirms QueryChan decodes every row
+// of a chunked query across more than one chunk, sending each on the
+// data channel and closing both channels once exhausted with no error.
+func TestQueryChanSendsEachDecodedRow(t *testing.T) {
+	chunks := `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["host","usage"],"values":[["a",0.5]]}]}]}` +
+		`{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["host","usage"],"values":[["b",0.75]]}]}]}`
+
+	setGClient(NewWithClient(&chunkedFakeClient{chunks: chunks}))
+	defer setGClient(nil)
+
+	out, errc := QueryChan[cpuRow](context.Background(), "db", "SELECT * FROM cpu")
+
+	var got []cpuRow
+	for row := range out {
+		got = append(got, row)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("errc: %v", err)
+	}
+
+	if len(got) != 2 || got[0].Host != "a" || got[0].Usage != 0.5 ||
+		got[1].Host != "b" || got[1].Usage != 0.75 {
+		t.Fatalf("got = %+v, want [{a 0.5} {b 0.75}]", got)
+	}
+}
+
+// TestQueryChanStopsOnContextCancellation confirms canceling ctx stops
+// QueryChan from sending any further rows and closes both channels
+// instead of blocking forever on a consumer that stopped reading.
+func TestQueryChanStopsOnContextCancellation(t *testing.T) {
+	chunks := `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["host","usage"],"values":[["a",0.5],["b",0.75]]}]}]}`
+
+	setGClient(NewWithClient(&chunkedFakeClient{chunks: chunks}))
+	defer setGClient(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out, errc := QueryChan[cpuRow](ctx, "db", "SELECT * FROM cpu")
+
+	first, ok := <-out
+	if !ok || first.Host != "a" {
+		t.Fatalf("first = %+v, ok = %v, want {a 0.5}, true", first, ok)
+	}
+	cancel()
+
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Fatal("out sent another row after cancellation, want closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("out never closed after cancellation")
+	}
+	<-errc
+}
@@ -0,0 +1,49 @@
+package influx
+
+import (
+	"context"
+	"errors"
+)
+
+// QueryEach runs cmd against db and calls fn once per row across every
+// series of every statement result, passing that series' columns and
+// tags alongside the row's raw values — for a transformation pipeline
+// that wants to stream rows straight through to something else (a CSV
+// writer, a second database's own insert call) without decoding them
+// into a struct at all. Iteration stops at fn's first error, which
+// QueryEach returns unchanged.
+func (c *Client) QueryEach(db, cmd string, fn func(cols []string, vals []interface{}, tags map[string]string) error) error {
+	return c.QueryEachContext(context.Background(), db, cmd, fn)
+}
+
+// QueryEachContext is QueryEach with a context that aborts the request
+// as soon as it is canceled.
+func (c *Client) QueryEachContext(ctx context.Context, db, cmd string, fn func(cols []string, vals []interface{}, tags map[string]string) error) error {
+	results, err := c.QueryContext(ctx, db, cmd)
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if result.Err != "" {
+			return errors.New(result.Err)
+		}
+		for _, serie := range result.Series {
+			for _, vals := range serie.Values {
+				if err := fn(serie.Columns, vals, serie.Tags); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// QueryEach runs QueryEach using the default Client.
+func QueryEach(db, cmd string, fn func(cols []string, vals []interface{}, tags map[string]string) error) error {
+	return gClient().QueryEach(db, cmd, fn)
+}
+
+// QueryEachContext runs QueryEachContext using the default Client.
+func QueryEachContext(ctx context.Context, db, cmd string, fn func(cols []string, vals []interface{}, tags map[string]string) error) error {
+	return gClient().QueryEachContext(ctx, db, cmd, fn)
+}
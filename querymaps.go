@@ -0,0 +1,36 @@
+package influx
+
+import "context"
+
+// QueryMaps runs cmd against db and decodes its first statement's
+// result into one map[string]interface{} per row, merging each row's
+// columns and its series' tags into the same map with native Go types
+// preserved (see alignToMap) — a convenience for admin/export endpoints
+// that query ad hoc measurements where defining a struct per query is
+// impractical. It is QueryInto with the destination shape fixed to
+// []map[string]interface{}.
+func (c *Client) QueryMaps(db, cmd string) ([]map[string]interface{}, error) {
+	return c.QueryMapsContext(context.Background(), db, cmd)
+}
+
+// QueryMapsContext is QueryMaps with a context that aborts the request
+// as soon as it is canceled.
+func (c *Client) QueryMapsContext(ctx context.Context, db, cmd string) ([]map[string]interface{}, error) {
+	var maps []map[string]interface{}
+	if err := c.QueryIntoContext(ctx, &maps, db, cmd); err != nil {
+		return nil, err
+	}
+	return maps, nil
+}
+
+// QueryMaps runs cmd against db and decodes its result into
+// []map[string]interface{}, using the default Client.
+func QueryMaps(db, cmd string) ([]map[string]interface{}, error) {
+	return gClient().QueryMaps(db, cmd)
+}
+
+// QueryMapsContext is QueryMaps with a context, using the default
+// Client.
+func QueryMapsContext(ctx context.Context, db, cmd string) ([]map[string]interface{}, error) {
+	return gClient().QueryMapsContext(ctx, db, cmd)
+}
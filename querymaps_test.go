@@ -0,0 +1,54 @@
+package influx
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// TestQueryMapsMergesColumnsAndTags confirms QueryMaps decodes one map
+// per row, with both series columns and tags present and native types
+// (not everything stringified) preserved.
+func TestQueryMapsMergesColumnsAndTags(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"results": []interface{}{
+				map[string]interface{}{
+					"series": []interface{}{
+						map[string]interface{}{
+							"name":    "cpu",
+							"tags":    map[string]string{"host": "a"},
+							"columns": []string{"time", "usage"},
+							"values":  [][]interface{}{{"2020-01-02T03:04:05Z", 0.5}},
+						},
+					},
+				},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	maps, err := c.QueryMaps("metrics", "SELECT usage FROM cpu GROUP BY host")
+	if err != nil {
+		t.Fatalf("QueryMaps: %v", err)
+	}
+	if len(maps) != 1 {
+		t.Fatalf("len(maps) = %d, want 1", len(maps))
+	}
+	row := maps[0]
+	if row["host"] != "a" {
+		t.Fatalf("host = %v, want a", row["host"])
+	}
+	if usage, ok := row["usage"].(float64); !ok || usage != 0.5 {
+		t.Fatalf("usage = %v (%T), want float64 0.5", row["usage"], row["usage"])
+	}
+}
@@ -0,0 +1,112 @@
+package influx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"regexp"
+	"time"
+)
+
+// QueryRetryConfig configures QueryRetry.
+type QueryRetryConfig struct {
+	// MaxAttempts is the total number of attempts, including the first;
+	// 0 or 1 disables retrying.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry; each subsequent
+	// retry doubles it, capped at MaxDelay.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff delay. 0 means no cap.
+	MaxDelay time.Duration
+	// Jitter is the fraction (0 to 1) of each delay randomized away, so
+	// many Clients retrying together don't all hammer InfluxDB at once.
+	Jitter float64
+}
+
+// QueryError is returned by QueryContext, once QueryRetry is enabled,
+// for a query that failed for good: every attempt QueryRetry allowed
+// was used up, the error was not retryable, or ctx was canceled during
+// a backoff delay. It wraps the last underlying error and reports how
+// many attempts were made.
+type QueryError struct {
+	Err      error
+	Attempts int
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("influx: query failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *QueryError) Unwrap() error { return e.Err }
+
+// QueryRetry enables retrying QueryContext on retryable transport
+// errors (timeouts and 5xx responses) with exponential backoff and
+// jitter, per cfg. Queries are idempotent, so retrying them is safe;
+// query syntax errors, which InfluxDB reports in a successful response
+// rather than as a transport failure, are never retried. Call with a
+// zero QueryRetryConfig to disable retrying.
+func (c *Client) QueryRetry(cfg QueryRetryConfig) {
+	if cfg.MaxAttempts <= 1 {
+		c.queryRetry = nil
+		return
+	}
+	c.queryRetry = &cfg
+}
+
+// withQueryRetry runs op, retrying it per c.queryRetry while it keeps
+// failing with a retryable transport error. If every attempt fails it
+// returns a *QueryError wrapping the last error and the attempt count.
+func (c *Client) withQueryRetry(ctx context.Context, op func() error) error {
+	if c.queryRetry == nil {
+		return op()
+	}
+
+	cfg := c.queryRetry
+	delay := cfg.BaseDelay
+	var err error
+	for attempt := 1; attempt <= cfg.MaxAttempts; attempt++ {
+		if err = op(); err == nil {
+			return nil
+		}
+		if attempt == cfg.MaxAttempts || !isRetryableQueryError(err) {
+			return &QueryError{Err: err, Attempts: attempt}
+		}
+
+		wait := delay
+		if cfg.Jitter > 0 {
+			wait -= time.Duration(rand.Float64() * cfg.Jitter * float64(wait))
+		}
+		select {
+		case <-after(wait):
+		case <-ctx.Done():
+			return &QueryError{Err: ctx.Err(), Attempts: attempt}
+		}
+
+		delay *= 2
+		if cfg.MaxDelay > 0 && delay > cfg.MaxDelay {
+			delay = cfg.MaxDelay
+		}
+	}
+	return &QueryError{Err: err, Attempts: cfg.MaxAttempts}
+}
+
+var statusCodePattern = regexp.MustCompile(`received status code (\d+) from`)
+
+// isRetryableQueryError reports whether err is worth retrying: a
+// network timeout, or a 5xx response. client.Client surfaces a non-2xx
+// response as a plain fmt.Errorf with no status code attached to the
+// error value itself, so the code is recovered by matching its message;
+// a query syntax error, by contrast, comes back in a 200 response with
+// the error described in Response.Err, and is never retried.
+func isRetryableQueryError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if code, ok := responseStatusCode(err); ok {
+		return code >= 500 && code < 600
+	}
+	return false
+}
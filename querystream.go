@@ -0,0 +1,317 @@
+package influx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// StreamRows iterates the rows of a plain (non-chunked) query one
+// client.Result at a time, the json.Decoder counterpart to
+// ChunkedRows: rather than requiring InfluxDB's chunked-query support,
+// it decodes the response's "results" array one element at a time
+// instead of the whole []client.Result QueryContext otherwise
+// unmarshals in one allocation, so a multi-statement query's earlier
+// results can be scanned and discarded before its later ones are even
+// decoded. Peak memory is still bounded by the largest single result
+// (statement) in the response, not the whole response.
+type StreamRows struct {
+	body io.Closer
+	dec  *json.Decoder
+
+	state int
+	rows  *Rows
+
+	err    error
+	closed bool
+}
+
+const (
+	streamInit = iota
+	streamResults
+	streamDrained
+)
+
+// QueryStream is QueryStreamContext using context.Background.
+func (c *Client) QueryStream(db, cmd string) (*StreamRows, error) {
+	return c.QueryStreamContext(context.Background(), db, cmd)
+}
+
+// QueryStreamContext runs cmd against db like QueryContext, but returns
+// a StreamRows decoding the response incrementally instead of
+// materializing every statement's result before returning.
+//
+// This bypasses client.Client, which has no row-level streaming
+// decode: it POSTs to the same query endpoint client.Client's own
+// QueryCtx does, building the request by hand the way FluxQueryContext
+// already does for the Flux endpoint. It only applies to Clients built
+// with New. Unlike QueryChunkedContext, it needs no server-side
+// chunked-query support, trading that for a coarser (per-statement
+// rather than per-network-chunk) memory bound.
+func (c *Client) QueryStreamContext(ctx context.Context, db, cmd string) (*StreamRows, error) {
+	if c.cfg == nil {
+		return nil, errors.New("influx: QueryStreamContext needs a Client built with New")
+	}
+	if err := c.applyCredentials(); err != nil {
+		return nil, err
+	}
+	db, _ = c.resolveTenant(ctx, db)
+
+	u, err := url.Parse(c.addr)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, "query")
+	q := url.Values{}
+	q.Set("q", cmd)
+	q.Set("db", db)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.RLock()
+	if c.cfg.Username != "" {
+		req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+	}
+	for k, vs := range c.headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	c.mu.RUnlock()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		resp.Body.Close()
+		return nil, fmt.Errorf("influx: received status code %d from server", resp.StatusCode)
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	return &StreamRows{body: resp.Body, dec: dec}, nil
+}
+
+// QueryStream runs cmd against db using the package-level default
+// Client, streaming the response the way QueryStreamContext does.
+func QueryStream(db, cmd string) (*StreamRows, error) {
+	return gClient().QueryStream(db, cmd)
+}
+
+// QueryStreamContext is QueryStream with a context, using the
+// package-level default Client.
+func QueryStreamContext(ctx context.Context, db, cmd string) (*StreamRows, error) {
+	return gClient().QueryStreamContext(ctx, db, cmd)
+}
+
+// Next advances to the next row, decoding another statement's result
+// from the response body once the current one is exhausted, and
+// returns false once every result has been seen, Close has been
+// called, or a terminal error is set (see Err). Call Scan or ScanRow
+// to read the row Next just advanced to.
+func (sr *StreamRows) Next() bool {
+	if sr.closed || sr.err != nil {
+		return false
+	}
+	for {
+		if sr.rows != nil {
+			if sr.rows.Next() {
+				return true
+			}
+			if err := sr.rows.Err(); err != nil {
+				sr.err = err
+				return false
+			}
+		}
+
+		result, ok, err := sr.nextResult()
+		if err != nil {
+			sr.err = err
+			return false
+		}
+		if !ok {
+			return false
+		}
+		sr.rows = RowsFromResults([]client.Result{result})
+	}
+}
+
+// nextResult decodes the next element of the response's "results"
+// array, returning ok == false once the array (and the response
+// object around it) is exhausted.
+func (sr *StreamRows) nextResult() (result client.Result, ok bool, err error) {
+	switch sr.state {
+	case streamInit:
+		if _, err := expectDelim(sr.dec, '{'); err != nil {
+			return client.Result{}, false, err
+		}
+		found, topErr, err := sr.seekResults()
+		if err != nil {
+			return client.Result{}, false, err
+		}
+		if !found {
+			sr.state = streamDrained
+			if topErr != "" {
+				return client.Result{}, false, errors.New("influx: " + topErr)
+			}
+			return client.Result{}, false, nil
+		}
+		sr.state = streamResults
+		fallthrough
+
+	case streamResults:
+		if sr.dec.More() {
+			if err := sr.dec.Decode(&result); err != nil {
+				return client.Result{}, false, err
+			}
+			if err := checkResultRowBudget(result); err != nil {
+				return client.Result{}, false, err
+			}
+			return result, true, nil
+		}
+		if _, err := sr.dec.Token(); err != nil { // ']'
+			return client.Result{}, false, err
+		}
+
+		topErr, err := sr.drainTopLevel()
+		sr.state = streamDrained
+		if err != nil {
+			return client.Result{}, false, err
+		}
+		if topErr != "" {
+			return client.Result{}, false, errors.New("influx: " + topErr)
+		}
+		return client.Result{}, false, nil
+	}
+
+	return client.Result{}, false, nil // streamDrained
+}
+
+// seekResults reads the response object's keys up to and including
+// "results"'s opening '[', discarding any other key's value along the
+// way (an "error" key is kept, since it's the only other key InfluxDB
+// sends and a response without a "results" field at all is reporting a
+// request-level failure through it). found is false if the object
+// closes without a "results" key ever appearing.
+func (sr *StreamRows) seekResults() (found bool, topErr string, err error) {
+	for sr.dec.More() {
+		tok, err := sr.dec.Token()
+		if err != nil {
+			return false, "", err
+		}
+		switch tok {
+		case "results":
+			if _, err := expectDelim(sr.dec, '['); err != nil {
+				return false, "", err
+			}
+			return true, "", nil
+		case "error":
+			if err := sr.dec.Decode(&topErr); err != nil {
+				return false, "", err
+			}
+		default:
+			var discard json.RawMessage
+			if err := sr.dec.Decode(&discard); err != nil {
+				return false, "", err
+			}
+		}
+	}
+	return false, topErr, nil
+}
+
+// drainTopLevel reads whatever keys follow "results"'s closing ']' up
+// to the response object's own closing '}', returning the value of an
+// "error" key if one is present.
+func (sr *StreamRows) drainTopLevel() (topErr string, err error) {
+	for sr.dec.More() {
+		tok, err := sr.dec.Token()
+		if err != nil {
+			return "", err
+		}
+		if tok == "error" {
+			if err := sr.dec.Decode(&topErr); err != nil {
+				return "", err
+			}
+			continue
+		}
+		var discard json.RawMessage
+		if err := sr.dec.Decode(&discard); err != nil {
+			return "", err
+		}
+	}
+	if _, err := sr.dec.Token(); err != nil { // '}'
+		return "", err
+	}
+	return topErr, nil
+}
+
+// expectDelim reads dec's next token and confirms it is the delimiter
+// want, e.g. '{' or '['.
+func expectDelim(dec *json.Decoder, want json.Delim) (json.Delim, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return 0, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok || delim != want {
+		return 0, fmt.Errorf("influx: expected %q in query response, got %v", want, tok)
+	}
+	return delim, nil
+}
+
+// Scan decodes the current row the same way Rows.Scan does.
+func (sr *StreamRows) Scan(dst interface{}) error {
+	return sr.rows.Scan(dst)
+}
+
+// ScanRow positionally scans the current row's values into dsts the
+// same way Rows.ScanRow does.
+func (sr *StreamRows) ScanRow(dsts ...interface{}) error {
+	return sr.rows.ScanRow(dsts...)
+}
+
+// Name returns the current row's series name, the same as Rows.Name.
+func (sr *StreamRows) Name() string {
+	return sr.rows.Name()
+}
+
+// Tags returns the current row's series tag set, the same as Rows.Tags.
+func (sr *StreamRows) Tags() map[string]string {
+	return sr.rows.Tags()
+}
+
+// Columns returns the current row's series columns, the same as
+// Rows.Columns.
+func (sr *StreamRows) Columns() []string {
+	return sr.rows.Columns()
+}
+
+// Values returns the current row's raw values, the same as Rows.Values.
+func (sr *StreamRows) Values() []interface{} {
+	return sr.rows.Values()
+}
+
+// Err returns the first terminal error encountered decoding the
+// response or iterating its rows, or nil if iteration simply ran out
+// of results.
+func (sr *StreamRows) Err() error {
+	return sr.err
+}
+
+// Close ends iteration early and releases the underlying HTTP
+// response's connection: after Close, Next always returns false.
+func (sr *StreamRows) Close() error {
+	sr.closed = true
+	return sr.body.Close()
+}
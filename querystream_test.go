@@ -0,0 +1,95 @@
+package influx
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+// newStreamRows builds a StreamRows over body without going through
+// QueryStreamContext, for exercising the response decode directly.
+func newStreamRows(body string) *StreamRows {
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.UseNumber()
+	return &StreamRows{body: io.NopCloser(nil), dec: dec}
+}
+
+type streamMetric struct {
+	Host  string `inf:"host"`
+	Value int64  `inf:"value"`
+}
+
+// TestStreamRowsDecodesEachResultInTurn confirms StreamRows walks a
+// multi-statement response's results one at a time instead of
+// requiring the whole []client.Result to be decoded up front.
+func TestStreamRowsDecodesEachResultInTurn(t *testing.T) {
+	body := `{"results":[
+		{"statement_id":0,"series":[{"name":"cpu","columns":["host","value"],"values":[["a",1],["b",2]]}]},
+		{"statement_id":1,"series":[{"name":"mem","columns":["host","value"],"values":[["c",3]]}]}
+	]}`
+	sr := newStreamRows(body)
+
+	var got []streamMetric
+	for sr.Next() {
+		var m streamMetric
+		if err := sr.Scan(&m); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, m)
+	}
+	if err := sr.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("len(got) = %d, want 3: %+v", len(got), got)
+	}
+	if got[0].Host != "a" || got[2].Host != "c" || got[2].Value != 3 {
+		t.Fatalf("got = %+v", got)
+	}
+}
+
+// TestStreamRowsSurfacesStatementError confirms a failing statement's
+// inline error stops iteration and is reported through Err.
+func TestStreamRowsSurfacesStatementError(t *testing.T) {
+	body := `{"results":[
+		{"statement_id":0,"series":[{"name":"cpu","columns":["host"],"values":[["a"]]}]},
+		{"statement_id":1,"error":"measurement not found"}
+	]}`
+	sr := newStreamRows(body)
+
+	n := 0
+	for sr.Next() {
+		n++
+	}
+	if n != 1 {
+		t.Fatalf("rows scanned = %d, want 1", n)
+	}
+	if sr.Err() == nil {
+		t.Fatal("Err() = nil, want the statement's error")
+	}
+}
+
+// TestStreamRowsSurfacesTopLevelError confirms a response with no
+// results at all (a request-level failure) reports its "error" field.
+func TestStreamRowsSurfacesTopLevelError(t *testing.T) {
+	sr := newStreamRows(`{"error":"authorization failed"}`)
+	if sr.Next() {
+		t.Fatal("Next() = true, want false")
+	}
+	if sr.Err() == nil {
+		t.Fatal("Err() = nil, want the response's error")
+	}
+}
+
+// TestStreamRowsEmptyResults confirms a response with an empty results
+// array iterates zero rows without error.
+func TestStreamRowsEmptyResults(t *testing.T) {
+	sr := newStreamRows(`{"results":[]}`)
+	if sr.Next() {
+		t.Fatal("Next() = true, want false")
+	}
+	if sr.Err() != nil {
+		t.Fatalf("Err() = %v, want nil", sr.Err())
+	}
+}
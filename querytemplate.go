@@ -0,0 +1,110 @@
+package influx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// templatePlaceholder matches a QueryTemplate's {{name}} placeholders,
+// the same shape QueryBind's bindParamPattern uses for bound parameters,
+// but substituted client-side instead of sent as a client.Query
+// Parameters, so a placeholder can appear anywhere in the command
+// InfluxDB's own $name binding can't reach (e.g. inside a subquery's
+// FROM clause), not just in a WHERE predicate.
+var templatePlaceholder = regexp.MustCompile(`\{\{(\w+)\}\}`)
+
+// QueryTemplate is an InfluxQL command compiled once from a string
+// containing {{name}} placeholders and executed with a map of typed
+// values, quoting each one the way its type requires (a string via
+// QuoteString, a time.Time via an absolute timestamp literal, and so
+// on) — a lighter-weight alternative to SelectBuilder for a query whose
+// shape doesn't change, only its values.
+type QueryTemplate struct {
+	raw   string
+	names []string
+}
+
+// CompileQueryTemplate parses tmpl's {{name}} placeholders and returns
+// a QueryTemplate ready for repeated Execute calls. It fails if tmpl
+// has none.
+func CompileQueryTemplate(tmpl string) (*QueryTemplate, error) {
+	matches := templatePlaceholder.FindAllStringSubmatch(tmpl, -1)
+	if len(matches) == 0 {
+		return nil, errors.New("influx: query template has no {{name}} placeholders")
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, m := range matches {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	return &QueryTemplate{raw: tmpl, names: names}, nil
+}
+
+// Names returns the template's placeholder names, in the order they
+// first appear.
+func (t *QueryTemplate) Names() []string {
+	return t.names
+}
+
+// Execute renders the template, substituting each {{name}} with
+// values[name] quoted for its type. It fails on the first placeholder
+// missing from values or holding a value of an unsupported type.
+func (t *QueryTemplate) Execute(values map[string]interface{}) (string, error) {
+	var err error
+	cmd := templatePlaceholder.ReplaceAllStringFunc(t.raw, func(match string) string {
+		if err != nil {
+			return match
+		}
+		name := match[2 : len(match)-2]
+		v, ok := values[name]
+		if !ok {
+			err = fmt.Errorf("influx: query template missing value for %q", name)
+			return match
+		}
+		lit, qerr := quoteTemplateValue(v)
+		if qerr != nil {
+			err = fmt.Errorf("influx: query template %q: %w", name, qerr)
+			return match
+		}
+		return lit
+	})
+	if err != nil {
+		return "", err
+	}
+	return cmd, nil
+}
+
+// quoteTemplateValue renders v as an InfluxQL literal for the type
+// Execute found it to hold.
+func quoteTemplateValue(v interface{}) (string, error) {
+	switch val := v.(type) {
+	case string:
+		return QuoteString(val), nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case time.Time:
+		return timeLiteral(val), nil
+	case time.Duration:
+		return durationLiteral(val), nil
+	case *regexp.Regexp:
+		return regexLiteral(val), nil
+	}
+
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 64), nil
+	}
+	return "", fmt.Errorf("influx: query template value of type %T is not supported", v)
+}
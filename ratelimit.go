@@ -0,0 +1,113 @@
+package influx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// ErrRateLimited is returned by WriteBatchPointsContext when the
+// Client's rate limiter has no budget left and RateLimitConfig.Block is
+// false.
+var ErrRateLimited = errors.New("influx: write rate limit exceeded")
+
+// RateLimitConfig bounds how fast a Client may write, so a misbehaving
+// producer cannot overload the shared InfluxDB cluster. Zero fields
+// mean "unlimited" on that dimension.
+type RateLimitConfig struct {
+	// PointsPerSec is the maximum number of points written per second.
+	PointsPerSec int
+	// BytesPerSec is the maximum line-protocol bytes written per
+	// second.
+	BytesPerSec int
+	// RequestsPerSec is the maximum number of WriteBatchPointsContext
+	// calls (regardless of their size) per second, for capping request
+	// rate against a cluster that cares about request count as much as
+	// payload size.
+	RequestsPerSec int
+	// Block, if true, makes WriteBatchPointsContext wait for budget
+	// instead of returning ErrRateLimited immediately.
+	Block bool
+}
+
+// rateLimiter is a simple per-second token bucket: it refills to its
+// capacity once per second rather than continuously, which is coarser
+// than golang.org/x/time/rate but needs no dependency.
+type rateLimiter struct {
+	mu       sync.Mutex
+	cfg      RateLimitConfig
+	points   int
+	bytes    int
+	requests int
+	resetsAt time.Time
+}
+
+func newRateLimiter(cfg RateLimitConfig) *rateLimiter {
+	return &rateLimiter{cfg: cfg, resetsAt: clockNow().Add(time.Second)}
+}
+
+// allow reports whether npoints/nbytes fit in the current window,
+// consuming budget if so. The caller must retry after time.Second if
+// it returns false.
+func (rl *rateLimiter) allow(npoints, nbytes int) bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := clockNow()
+	if !now.Before(rl.resetsAt) {
+		rl.points, rl.bytes, rl.requests = 0, 0, 0
+		rl.resetsAt = now.Add(time.Second)
+	}
+	if rl.cfg.PointsPerSec > 0 && rl.points+npoints > rl.cfg.PointsPerSec {
+		return false
+	}
+	if rl.cfg.BytesPerSec > 0 && rl.bytes+nbytes > rl.cfg.BytesPerSec {
+		return false
+	}
+	if rl.cfg.RequestsPerSec > 0 && rl.requests+1 > rl.cfg.RequestsPerSec {
+		return false
+	}
+	rl.points += npoints
+	rl.bytes += nbytes
+	rl.requests++
+	return true
+}
+
+// RateLimit caps c's write throughput per cfg. Call with a zero
+// RateLimitConfig to remove a previously set limit.
+func (c *Client) RateLimit(cfg RateLimitConfig) {
+	if cfg.PointsPerSec == 0 && cfg.BytesPerSec == 0 && cfg.RequestsPerSec == 0 {
+		c.limiter = nil
+		return
+	}
+	c.limiter = newRateLimiter(cfg)
+}
+
+// waitForBudget blocks (if the limiter blocks) or fails fast until bp
+// fits the rate limit, then returns nil to proceed.
+func (c *Client) waitForBudget(ctx context.Context, bp client.BatchPoints) error {
+	if c.limiter == nil {
+		return nil
+	}
+	npoints := len(bp.Points())
+	nbytes := 0
+	for _, p := range bp.Points() {
+		nbytes += len(p.String())
+	}
+	for {
+		if c.limiter.allow(npoints, nbytes) {
+			return nil
+		}
+		if !c.limiter.cfg.Block {
+			return ErrRateLimited
+		}
+		select {
+		case <-after(time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
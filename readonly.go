@@ -0,0 +1,15 @@
+package influx
+
+import "errors"
+
+// ErrReadOnly is returned by WriteBatchPointsContext on a Client put
+// into read-only mode by ReadOnly.
+var ErrReadOnly = errors.New("influx: client is read-only")
+
+// ReadOnly makes c reject every write (Insert, InsertRP,
+// WriteBatchPoints and their Context variants) with ErrReadOnly while
+// still allowing queries, so e.g. an analytics job can provably not
+// write to production measurements. Call ReadOnly(false) to lift it.
+func (c *Client) ReadOnly(readOnly bool) {
+	c.readOnly = readOnly
+}
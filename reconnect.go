@@ -0,0 +1,88 @@
+package influx
+
+import (
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// DefaultReconnectInterval is the delay before the first reconnect
+// attempt when AutoReconnect is enabled; each subsequent attempt
+// doubles it, mirroring BufferedWriter's write retry backoff.
+const DefaultReconnectInterval = 500 * time.Millisecond
+
+// AutoReconnect makes c rebuild its underlying HTTP connection and
+// retry with exponential backoff whenever a query or write fails with a
+// connection-level error (connection refused, reset, unexpected EOF),
+// instead of surfacing the first such error to the caller. It only
+// applies to Clients built with New: UDP and replica-split Clients
+// reject it since they have no single client.HTTPConfig to rebuild
+// from.
+//
+// maxRetries is the number of rebuild-and-retry attempts after the
+// first failure; 0 disables AutoReconnect.
+func (c *Client) AutoReconnect(maxRetries int, interval time.Duration) error {
+	if c.cfg == nil {
+		return errors.New("influx: AutoReconnect needs a Client built with New")
+	}
+	if interval <= 0 {
+		interval = DefaultReconnectInterval
+	}
+	c.reconnectRetries = maxRetries
+	c.reconnectInterval = interval
+	return nil
+}
+
+// withReconnect runs op against the live cli, rebuilding the connection
+// and retrying with exponential backoff while op keeps failing with a
+// connection-level error, up to c.reconnectRetries extra attempts.
+func (c *Client) withReconnect(op func(client.Client) error) error {
+	c.mu.RLock()
+	cli := c.cli
+	c.mu.RUnlock()
+
+	err := op(cli)
+	if err == nil || c.reconnectRetries == 0 || !isConnError(err) {
+		return err
+	}
+
+	delay := c.reconnectInterval
+	for attempt := 0; attempt < c.reconnectRetries; attempt++ {
+		sleep(delay)
+		delay *= 2
+
+		newCli, rerr := client.NewHTTPClient(*c.cfg)
+		if rerr != nil {
+			err = rerr
+			continue
+		}
+		c.mu.Lock()
+		c.cli.Close()
+		c.cli = newCli
+		c.mu.Unlock()
+
+		err = op(newCli)
+		if err == nil || !isConnError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// isConnError reports whether err looks like a failure of the
+// underlying TCP connection rather than an application-level error
+// (bad InfluxQL, 4xx, etc), which AutoReconnect should not retry.
+func isConnError(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
@@ -0,0 +1,30 @@
+package influx
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// regexLiteral renders re as an InfluxQL regex literal (e.g. /^cpu.*/),
+// escaping any forward slash in its pattern the way InfluxQL requires.
+// re is always a validly-compiled Go regexp, since callers build it
+// with regexp.Compile/MustCompile rather than handing this package a
+// raw pattern string to validate itself.
+func regexLiteral(re *regexp.Regexp) string {
+	return "/" + strings.ReplaceAll(re.String(), "/", `\/`) + "/"
+}
+
+// MatchRegex renders a `"field" =~ /pattern/` InfluxQL predicate
+// matching field (a tag key or field name) against re, for use in a
+// WHERE clause (e.g. via SelectBuilder.Where). To match measurement
+// names by regex instead, pass re directly to SelectBuilder.From.
+func MatchRegex(field string, re *regexp.Regexp) string {
+	return fmt.Sprintf("%s =~ %s", Ident(field), regexLiteral(re))
+}
+
+// NotMatchRegex is MatchRegex with InfluxQL's negated regex operator,
+// rendering `"field" !~ /pattern/`.
+func NotMatchRegex(field string, re *regexp.Regexp) string {
+	return fmt.Sprintf("%s !~ %s", Ident(field), regexLiteral(re))
+}
@@ -0,0 +1,66 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// registry holds the named Clients RegisterClient adds, for services
+// that talk to several InfluxDB clusters (metrics vs. audit vs.
+// billing) but still want package-level convenience functions.
+var registry sync.Map // name string -> *Client
+
+// RegisterClient builds a Client from cfg and registers it under name
+// for QueryOn/InsertOn/ClientFor. Registering the same name again
+// replaces the previous Client; callers are responsible for closing it.
+func RegisterClient(name string, cfg client.HTTPConfig) error {
+	cli, err := New(cfg)
+	if err != nil {
+		return err
+	}
+	registry.Store(name, cli)
+	return nil
+}
+
+// ClientFor returns the Client registered under name, or nil if none
+// was registered.
+func ClientFor(name string) *Client {
+	v, ok := registry.Load(name)
+	if !ok {
+		return nil
+	}
+	return v.(*Client)
+}
+
+// QueryOn runs cmd against db using the Client registered under name.
+func QueryOn(name, db, cmd string) ([]client.Result, error) {
+	return QueryOnContext(context.Background(), name, db, cmd)
+}
+
+// QueryOnContext is QueryOn with a context that aborts the request as
+// soon as it is canceled.
+func QueryOnContext(ctx context.Context, name, db, cmd string) ([]client.Result, error) {
+	cli := ClientFor(name)
+	if cli == nil {
+		return nil, fmt.Errorf("influx: no client registered under %q", name)
+	}
+	return cli.QueryContext(ctx, db, cmd)
+}
+
+// InsertOn writes point to db using the Client registered under name.
+func InsertOn(name, db string, point *client.Point) error {
+	return InsertOnContext(context.Background(), name, db, point)
+}
+
+// InsertOnContext is InsertOn with a context that aborts the request as
+// soon as it is canceled.
+func InsertOnContext(ctx context.Context, name, db string, point *client.Point) error {
+	cli := ClientFor(name)
+	if cli == nil {
+		return fmt.Errorf("influx: no client registered under %q", name)
+	}
+	return cli.InsertContext(ctx, db, point)
+}
@@ -0,0 +1,64 @@
+package influx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// TestRegisterClientQueryOnInsertOnRouteByName confirms RegisterClient
+// makes a Client reachable by name through ClientFor, QueryOn and
+// InsertOn, and that a name nothing was registered under reports its
+// own error instead of a nil-pointer panic.
+func TestRegisterClientQueryOnInsertOnRouteByName(t *testing.T) {
+	var gotPath string
+	var sawWrite bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if r.URL.Path == "/write" {
+			sawWrite = true
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":[{"statement_id":0}]}`))
+	}))
+	defer srv.Close()
+
+	if err := RegisterClient("metrics", client.HTTPConfig{Addr: srv.URL}); err != nil {
+		t.Fatal(err)
+	}
+	defer registry.Delete("metrics")
+
+	if ClientFor("metrics") == nil {
+		t.Fatal("ClientFor(\"metrics\") = nil after RegisterClient")
+	}
+	if ClientFor("other") != nil {
+		t.Fatal("ClientFor(\"other\") != nil, want nil for an unregistered name")
+	}
+
+	if _, err := QueryOn("metrics", "mydb", "SHOW MEASUREMENTS"); err != nil {
+		t.Fatalf("QueryOn: %v", err)
+	}
+	if gotPath != "/query" {
+		t.Fatalf("path = %q, want %q", gotPath, "/query")
+	}
+
+	p, err := client.NewPoint("cpu", nil, map[string]interface{}{"value": 1.0}, time.Unix(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := InsertOn("metrics", "mydb", p); err != nil {
+		t.Fatalf("InsertOn: %v", err)
+	}
+	if !sawWrite {
+		t.Fatal("InsertOn never reached /write")
+	}
+
+	if _, err := QueryOn("nope", "mydb", "SHOW MEASUREMENTS"); err == nil {
+		t.Fatal("QueryOn with an unregistered name: want error, got nil")
+	}
+}
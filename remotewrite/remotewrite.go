@@ -0,0 +1,289 @@
+// Package remotewrite implements an http.Handler for Prometheus's
+// remote_write protocol: it decodes the snappy-compressed protobuf
+// WriteRequest body Prometheus POSTs, converts each TimeSeries into
+// one InfluxDB point per sample (the __name__ label becomes the
+// measurement, every other label becomes a tag, the sample value
+// becomes a "value" field) and writes it through a Writer, so an
+// existing Prometheus server can remote_write straight onto an
+// eachain/influx BufferedWriter without a separate ingestion
+// pipeline.
+//
+// Decoding the wire-format WriteRequest by hand, field by field, with
+// google.golang.org/protobuf/encoding/protowire avoids depending on
+// github.com/prometheus/prometheus just for its generated prompb
+// package; only the four message types remote_write actually needs
+// (WriteRequest, TimeSeries, Label, Sample) are decoded, and any
+// other field — including remote_write's metadata and exemplars — is
+// skipped rather than rejected, the same forward-compatible behavior
+// Prometheus's own generated unmarshaler has.
+package remotewrite
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/influxdata/influxdb/client/v2"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Writer is the subset of *influx.BufferedWriter a Handler writes
+// decoded points through.
+type Writer interface {
+	Write(point *client.Point) error
+}
+
+// Handler implements http.Handler for Prometheus's remote_write
+// protocol, writing every decoded sample through Writer.
+type Handler struct {
+	Writer Writer
+}
+
+// NewHandler returns a Handler that writes every sample decoded from
+// a request through w, e.g.:
+//
+//	http.Handle("/api/v1/write", remotewrite.NewHandler(bufferedWriter))
+func NewHandler(w Writer) *Handler {
+	return &Handler{Writer: w}
+}
+
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	compressed, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(rw, "remotewrite: reading body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	body, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		http.Error(rw, "remotewrite: decompressing body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	series, err := decodeWriteRequest(body)
+	if err != nil {
+		http.Error(rw, "remotewrite: decoding write request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, ts := range series {
+		points, err := pointsFromSeries(ts)
+		if err != nil {
+			http.Error(rw, "remotewrite: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, p := range points {
+			if err := h.Writer.Write(p); err != nil {
+				http.Error(rw, "remotewrite: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// pointsFromSeries converts ts into one *client.Point per sample,
+// sharing the measurement and tags derived from its labels.
+func pointsFromSeries(ts timeSeries) ([]*client.Point, error) {
+	var name string
+	tags := make(map[string]string, len(ts.labels))
+	for _, l := range ts.labels {
+		if l.name == "__name__" {
+			name = l.value
+			continue
+		}
+		tags[l.name] = l.value
+	}
+	if name == "" {
+		return nil, fmt.Errorf("time series has no __name__ label")
+	}
+
+	points := make([]*client.Point, 0, len(ts.samples))
+	for _, s := range ts.samples {
+		p, err := client.NewPoint(name, tags,
+			map[string]interface{}{"value": s.value},
+			time.UnixMilli(s.timestamp).UTC())
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// label is a decoded prometheus.Label.
+type label struct {
+	name  string
+	value string
+}
+
+// sample is a decoded prometheus.Sample: value, and a timestamp in
+// milliseconds since the Unix epoch.
+type sample struct {
+	value     float64
+	timestamp int64
+}
+
+// timeSeries is a decoded prometheus.TimeSeries.
+type timeSeries struct {
+	labels  []label
+	samples []sample
+}
+
+// decodeWriteRequest decodes a prometheus.WriteRequest protobuf
+// message, reading only its timeseries field (1); every other field,
+// metadata (3) included, is skipped.
+func decodeWriteRequest(b []byte) ([]timeSeries, error) {
+	var series []timeSeries
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		if num == 1 && typ == protowire.BytesType {
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			b = b[n:]
+			ts, err := decodeTimeSeries(v)
+			if err != nil {
+				return nil, err
+			}
+			series = append(series, ts)
+			continue
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	return series, nil
+}
+
+// decodeTimeSeries decodes a prometheus.TimeSeries protobuf message:
+// labels (field 1) and samples (field 2).
+func decodeTimeSeries(b []byte) (timeSeries, error) {
+	var ts timeSeries
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return ts, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return ts, protowire.ParseError(n)
+			}
+			b = b[n:]
+			l, err := decodeLabel(v)
+			if err != nil {
+				return ts, err
+			}
+			ts.labels = append(ts.labels, l)
+		case num == 2 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return ts, protowire.ParseError(n)
+			}
+			b = b[n:]
+			s, err := decodeSample(v)
+			if err != nil {
+				return ts, err
+			}
+			ts.samples = append(ts.samples, s)
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return ts, protowire.ParseError(n)
+			}
+			b = b[n:]
+		}
+	}
+	return ts, nil
+}
+
+// decodeLabel decodes a prometheus.Label protobuf message: name
+// (field 1) and value (field 2), both strings.
+func decodeLabel(b []byte) (label, error) {
+	var l label
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return l, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return l, protowire.ParseError(n)
+			}
+			l.name = v
+			b = b[n:]
+		case num == 2 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return l, protowire.ParseError(n)
+			}
+			l.value = v
+			b = b[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return l, protowire.ParseError(n)
+			}
+			b = b[n:]
+		}
+	}
+	return l, nil
+}
+
+// decodeSample decodes a prometheus.Sample protobuf message: value
+// (field 1, a double) and timestamp (field 2, an int64 in
+// milliseconds since the Unix epoch).
+func decodeSample(b []byte) (sample, error) {
+	var s sample
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return s, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		switch {
+		case num == 1 && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return s, protowire.ParseError(n)
+			}
+			s.value = math.Float64frombits(v)
+			b = b[n:]
+		case num == 2 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return s, protowire.ParseError(n)
+			}
+			s.timestamp = int64(v)
+			b = b[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return s, protowire.ParseError(n)
+			}
+			b = b[n:]
+		}
+	}
+	return s, nil
+}
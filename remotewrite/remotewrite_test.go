@@ -0,0 +1,166 @@
+package remotewrite
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/influxdata/influxdb/client/v2"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// fakeWriter records every point written to it, in place of a real
+// *influx.BufferedWriter.
+type fakeWriter struct {
+	points []*client.Point
+}
+
+func (w *fakeWriter) Write(p *client.Point) error {
+	w.points = append(w.points, p)
+	return nil
+}
+
+func encodeLabel(name, value string) []byte {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendString(b, name)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendString(b, value)
+	return b
+}
+
+func encodeSample(value float64, timestampMs int64) []byte {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.Fixed64Type)
+	b = protowire.AppendFixed64(b, math.Float64bits(value))
+	b = protowire.AppendTag(b, 2, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(timestampMs))
+	return b
+}
+
+func encodeTimeSeries(labels [][2]string, samples [][2]float64) []byte {
+	var b []byte
+	for _, l := range labels {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, encodeLabel(l[0], l[1]))
+	}
+	for _, s := range samples {
+		b = protowire.AppendTag(b, 2, protowire.BytesType)
+		b = protowire.AppendBytes(b, encodeSample(s[0], int64(s[1])))
+	}
+	return b
+}
+
+func encodeWriteRequest(series [][]byte) []byte {
+	var b []byte
+	for _, ts := range series {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, ts)
+	}
+	return b
+}
+
+func postWriteRequest(t *testing.T, h *Handler, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	compressed := snappy.Encode(nil, body)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/write", bytes.NewReader(compressed))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestHandlerDecodesSampleIntoPoint confirms a single-sample
+// TimeSeries becomes one point with the __name__ label as the
+// measurement, other labels as tags, and the sample as a "value"
+// field.
+func TestHandlerDecodesSampleIntoPoint(t *testing.T) {
+	ts := encodeTimeSeries(
+		[][2]string{{"__name__", "http_requests_total"}, {"method", "get"}},
+		[][2]float64{{42, 1700000000000}},
+	)
+	body := encodeWriteRequest([][]byte{ts})
+
+	w := &fakeWriter{}
+	rec := postWriteRequest(t, NewHandler(w), body)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusNoContent, rec.Body.String())
+	}
+	if len(w.points) != 1 {
+		t.Fatalf("len(points) = %d, want 1", len(w.points))
+	}
+
+	p := w.points[0]
+	if p.Name() != "http_requests_total" {
+		t.Fatalf("Name() = %q, want %q", p.Name(), "http_requests_total")
+	}
+	if got := p.Tags()["method"]; got != "get" {
+		t.Fatalf("method tag = %q, want %q", got, "get")
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fields["value"]; got != 42.0 {
+		t.Fatalf("value field = %v, want 42", got)
+	}
+	wantTime := time.UnixMilli(1700000000000).UTC()
+	if !p.Time().Equal(wantTime) {
+		t.Fatalf("Time() = %v, want %v", p.Time(), wantTime)
+	}
+}
+
+// TestHandlerWritesOnePointPerSample confirms a TimeSeries with
+// multiple samples becomes one point per sample, all sharing the same
+// measurement and tags.
+func TestHandlerWritesOnePointPerSample(t *testing.T) {
+	ts := encodeTimeSeries(
+		[][2]string{{"__name__", "cpu_usage"}},
+		[][2]float64{{1, 1000}, {2, 2000}, {3, 3000}},
+	)
+	body := encodeWriteRequest([][]byte{ts})
+
+	w := &fakeWriter{}
+	rec := postWriteRequest(t, NewHandler(w), body)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusNoContent, rec.Body.String())
+	}
+	if len(w.points) != 3 {
+		t.Fatalf("len(points) = %d, want 3", len(w.points))
+	}
+}
+
+// TestHandlerRejectsMissingMetricName confirms a TimeSeries with no
+// __name__ label, which has no measurement to write to, is rejected
+// with a 400 instead of silently dropped or written under an empty
+// name.
+func TestHandlerRejectsMissingMetricName(t *testing.T) {
+	ts := encodeTimeSeries(
+		[][2]string{{"method", "get"}},
+		[][2]float64{{42, 1700000000000}},
+	)
+	body := encodeWriteRequest([][]byte{ts})
+
+	rec := postWriteRequest(t, NewHandler(&fakeWriter{}), body)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+// TestHandlerRejectsUndecompressableBody confirms a body that isn't
+// valid snappy-compressed data is rejected with a 400 rather than a
+// panic or a 500.
+func TestHandlerRejectsUndecompressableBody(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/write", bytes.NewReader([]byte("not snappy")))
+	rec := httptest.NewRecorder()
+	NewHandler(&fakeWriter{}).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
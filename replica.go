@@ -0,0 +1,111 @@
+package influx
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// NewWithReplicas creates a Client that writes to the primary described
+// by writeCfg and round-robins queries across the replicas described by
+// readCfgs. If readCfgs is empty, queries also go to the primary. Call
+// SetReplicaSelection to spread queries some other way, e.g. by
+// latency.
+//
+// The returned Client's Close closes the primary and every replica
+// connection.
+func NewWithReplicas(writeCfg client.HTTPConfig, readCfgs ...client.HTTPConfig) (*Client, error) {
+	primary, err := client.NewHTTPClient(writeCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := make([]client.Client, 0, len(readCfgs))
+	for _, cfg := range readCfgs {
+		replica, err := client.NewHTTPClient(cfg)
+		if err != nil {
+			for _, r := range replicas {
+				r.Close()
+			}
+			primary.Close()
+			return nil, err
+		}
+		replicas = append(replicas, replica)
+	}
+
+	return &Client{
+		cli:              primary,
+		replicas:         replicas,
+		replicaLatencies: make([]int64, len(replicas)),
+		Precision:        "s",
+	}, nil
+}
+
+// ReplicaSelection chooses how nextReplica picks among c.replicas.
+type ReplicaSelection int
+
+const (
+	// ReplicaRoundRobin cycles through the replicas in order; the
+	// default.
+	ReplicaRoundRobin ReplicaSelection = iota
+	// ReplicaLeastLatency sends each query to whichever replica most
+	// recently answered fastest, so a replica running its own heavy
+	// dashboard queries, or simply further away, falls behind in the
+	// rotation instead of continuing to take an equal share.
+	ReplicaLeastLatency
+)
+
+// SetReplicaSelection chooses how queries are spread across the
+// replicas configured by NewWithReplicas. The default, ReplicaRoundRobin,
+// is a fine fit when replicas are identical and evenly loaded.
+func (c *Client) SetReplicaSelection(mode ReplicaSelection) {
+	c.mu.Lock()
+	c.replicaSelection = mode
+	c.mu.Unlock()
+}
+
+// nextReplica returns the next read replica to query, chosen per
+// c.replicaSelection, along with its index in c.replicas for
+// recordReplicaLatency to report back to. If no replicas were
+// configured it returns c.cli and index -1.
+func (c *Client) nextReplica() (client.Client, int) {
+	if len(c.replicas) == 0 {
+		return c.cli, -1
+	}
+	if c.replicaSelection == ReplicaLeastLatency {
+		return c.leastLatencyReplica()
+	}
+	idx := int(atomic.AddUint32(&c.replicaCursor, 1) % uint32(len(c.replicas)))
+	return c.replicas[idx], idx
+}
+
+// leastLatencyReplica returns the replica recordReplicaLatency last
+// clocked as fastest, or the next round-robin replica if any replica
+// hasn't answered a query yet, so a cold start doesn't pin every query
+// to whichever replica happens to be first.
+func (c *Client) leastLatencyReplica() (client.Client, int) {
+	best := -1
+	var bestLatency int64
+	for i := range c.replicas {
+		l := atomic.LoadInt64(&c.replicaLatencies[i])
+		if l == 0 {
+			idx := int(atomic.AddUint32(&c.replicaCursor, 1) % uint32(len(c.replicas)))
+			return c.replicas[idx], idx
+		}
+		if best == -1 || l < bestLatency {
+			best, bestLatency = i, l
+		}
+	}
+	return c.replicas[best], best
+}
+
+// recordReplicaLatency records d as replica idx's latest latency
+// sample, consulted by leastLatencyReplica. idx < 0 (no replica was
+// used) is a no-op.
+func (c *Client) recordReplicaLatency(idx int, d time.Duration) {
+	if idx < 0 {
+		return
+	}
+	atomic.StoreInt64(&c.replicaLatencies[idx], int64(d))
+}
@@ -0,0 +1,93 @@
+package influx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+const okQueryResponse = `{"results":[{"series":[{"name":"cpu","columns":["time","value"],"values":[["2020-01-01T00:00:00Z",1]]}]}]}`
+
+// TestNextReplicaRoundRobinsByDefault confirms a Client built with
+// NewWithReplicas spreads queries evenly across its replicas without
+// SetReplicaSelection being called.
+func TestNextReplicaRoundRobinsByDefault(t *testing.T) {
+	var hitsA, hitsB int32
+	a := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hitsA, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(okQueryResponse))
+	}))
+	defer a.Close()
+	b := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hitsB, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(okQueryResponse))
+	}))
+	defer b.Close()
+
+	c, err := NewWithReplicas(replicaHTTPConfig(a.URL), replicaHTTPConfig(a.URL), replicaHTTPConfig(b.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	for i := 0; i < 4; i++ {
+		if _, err := c.Query("db", "SELECT * FROM cpu"); err != nil {
+			t.Fatalf("Query: %v", err)
+		}
+	}
+	if hitsA != 2 || hitsB != 2 {
+		t.Fatalf("hitsA=%d hitsB=%d, want 2 and 2", hitsA, hitsB)
+	}
+}
+
+// TestNextReplicaLeastLatencyPrefersFasterReplica confirms
+// SetReplicaSelection(ReplicaLeastLatency) steers queries toward
+// whichever replica most recently answered fastest, once both have
+// been sampled once.
+func TestNextReplicaLeastLatencyPrefersFasterReplica(t *testing.T) {
+	var hitsFast, hitsSlow int32
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hitsFast, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(okQueryResponse))
+	}))
+	defer fast.Close()
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hitsSlow, 1)
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(okQueryResponse))
+	}))
+	defer slow.Close()
+
+	c, err := NewWithReplicas(replicaHTTPConfig(fast.URL), replicaHTTPConfig(fast.URL), replicaHTTPConfig(slow.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.SetReplicaSelection(ReplicaLeastLatency)
+
+	// First two queries round-robin to sample each replica once; every
+	// one after that should prefer the fast replica.
+	for i := 0; i < 6; i++ {
+		if _, err := c.Query("db", "SELECT * FROM cpu"); err != nil {
+			t.Fatalf("Query: %v", err)
+		}
+	}
+	if hitsSlow != 1 {
+		t.Fatalf("hitsSlow=%d, want exactly the one cold-start sample", hitsSlow)
+	}
+	if hitsFast != 5 {
+		t.Fatalf("hitsFast=%d, want 5", hitsFast)
+	}
+}
+
+func replicaHTTPConfig(addr string) client.HTTPConfig {
+	return client.HTTPConfig{Addr: addr}
+}
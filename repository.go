@@ -0,0 +1,103 @@
+package influx
+
+import (
+	"context"
+	"time"
+)
+
+// Repository is a typed data-access layer over a single measurement in
+// db on cli, built from T's own `inf` tags the same way ToPoint/
+// ParseResult read them: Write, Query and Latest wrap InsertMany,
+// QueryContext/ParseResults and LatestContext so application code gets
+// a []T/T-typed surface instead of repeating those calls by hand at
+// every call site.
+//
+// Repository is a free function rather than a *Client method (like
+// Client.NewPaginator) because Go methods can't carry their own type
+// parameters; NewRepository takes cli explicitly instead.
+type Repository[T any] struct {
+	cli *Client
+	db  string
+}
+
+// NewRepository binds a Repository[T] to db on cli.
+func NewRepository[T any](cli *Client, db string) *Repository[T] {
+	return &Repository[T]{cli: cli, db: db}
+}
+
+// Repo is NewRepository under the shorter name a call site reads best
+// with, e.g. influx.Repo[CpuUsage](cli, "telegraf").
+func Repo[T any](cli *Client, db string) *Repository[T] {
+	return NewRepository[T](cli, db)
+}
+
+// Write inserts rows as a single batch, the generic counterpart to
+// Client.InsertManyContext.
+func (r *Repository[T]) Write(ctx context.Context, rows ...T) error {
+	return r.cli.InsertManyContext(ctx, r.db, rows)
+}
+
+// Save inserts v as a single row, the Repository counterpart to Write
+// for a caller with just one row in hand instead of a batch.
+func (r *Repository[T]) Save(ctx context.Context, v T) error {
+	return r.Write(ctx, v)
+}
+
+// Query runs builder against r's database and decodes every row into a
+// []T, the generic counterpart to Client.QueryContext followed by
+// ParseResults.
+func (r *Repository[T]) Query(ctx context.Context, builder *SelectBuilder) ([]T, error) {
+	results, err := r.cli.QueryContext(ctx, r.db, builder.String())
+	if err != nil {
+		return nil, err
+	}
+	var rows []T
+	if err := ParseResults(&rows, results); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// FindRange returns every T timestamped within the closed interval
+// [from, to], further narrowed by preds (built with Tag and ANDed
+// together, e.g. Tag("host").Eq("a")), the generic counterpart to a
+// hand-built SELECT * FROM measurement WHERE time >= ... AND time <=
+// ... AND ... query.
+func (r *Repository[T]) FindRange(ctx context.Context, from, to time.Time, preds ...Predicate) ([]T, error) {
+	_, measurement, err := SelectColumns[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	where := Between(from, to)
+	if len(preds) > 0 {
+		where += " AND " + And(preds...).String()
+	}
+
+	return r.Query(ctx, Select("*").From(Ident(measurement)).Where(where))
+}
+
+// Latest returns the most recent T matching filters — a T with only
+// the fields to filter on set, the same as WhereFromStruct — or T's
+// zero value and an error if none matched. It runs the same SELECT *
+// FROM measurement WHERE ... GROUP BY * ORDER BY time DESC LIMIT 1
+// Client.LatestContext does, with T's measurement derived via
+// SelectColumns.
+func (r *Repository[T]) Latest(ctx context.Context, filters T) (T, error) {
+	var zero T
+
+	_, measurement, err := SelectColumns[T]()
+	if err != nil {
+		return zero, err
+	}
+	where, err := WhereFromStruct(filters)
+	if err != nil {
+		return zero, err
+	}
+
+	var row T
+	if err := r.cli.LatestContext(ctx, &row, r.db, measurement, where); err != nil {
+		return zero, err
+	}
+	return row, nil
+}
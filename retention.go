@@ -0,0 +1,97 @@
+package influx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RetentionEnforcementOptions configures EnforceRetention.
+type RetentionEnforcementOptions struct {
+	// Measurement is enforced against.
+	Measurement string
+	// Where, if non-empty, is ANDed onto each slice's time bound.
+	Where string
+
+	// MaxAge is how old data is allowed to get before EnforceRetention
+	// deletes it; the cutoff is clockNow() - MaxAge, recomputed once per
+	// call.
+	MaxAge time.Duration
+	// Start bounds how far back slicing begins, e.g. the measurement's
+	// known oldest possible timestamp, or time.Unix(0, 0) if unknown.
+	Start time.Time
+	// SliceSize is the time span each DELETE covers. It must be
+	// positive — this is what keeps any one query small enough not to
+	// time out, the same reason Backfill slices its own queries.
+	SliceSize time.Duration
+
+	// DryRun, if true, builds and reports every slice's DELETE without
+	// running it, so a caller can review what would be deleted first.
+	DryRun bool
+	// Progress, if non-nil, is called after every slice, in order, with
+	// that slice's bounds and its error (nil on success, and always nil
+	// in dry-run mode), so a long enforcement run can report where it
+	// is and a caller can resume right where it left off.
+	Progress func(start, end time.Time, err error)
+}
+
+// EnforceRetention deletes opts.Measurement's data older than opts.MaxAge,
+// a SliceSize-wide time slice at a time, via bounded DELETE statements —
+// for a measurement living in a retention policy shared with other
+// measurements, where shortening the RP's own DURATION would also
+// affect everything else stored under it and so isn't an option. It
+// stops and returns the first slice's error, if any — opts.Progress has
+// already been told about it by then.
+func (c *Client) EnforceRetention(db string, opts RetentionEnforcementOptions) error {
+	return c.EnforceRetentionContext(context.Background(), db, opts)
+}
+
+// EnforceRetentionContext is EnforceRetention with a context that aborts
+// the current slice's request as soon as it is canceled.
+func (c *Client) EnforceRetentionContext(ctx context.Context, db string, opts RetentionEnforcementOptions) error {
+	if opts.SliceSize <= 0 {
+		return errors.New("influx: EnforceRetention requires a positive SliceSize")
+	}
+
+	cutoff := clockNow().Add(-opts.MaxAge)
+	for start := opts.Start; start.Before(cutoff); start = start.Add(opts.SliceSize) {
+		end := start.Add(opts.SliceSize)
+		if end.After(cutoff) {
+			end = cutoff
+		}
+
+		var err error
+		if !opts.DryRun {
+			_, err = c.QueryContext(ctx, db, retentionDeleteCmd(opts, start, end))
+		}
+		if opts.Progress != nil {
+			opts.Progress(start, end, err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retentionDeleteCmd builds one slice's DELETE FROM ... WHERE statement.
+func retentionDeleteCmd(opts RetentionEnforcementOptions, start, end time.Time) string {
+	cmd := fmt.Sprintf("DELETE FROM %s WHERE %s", Ident(opts.Measurement), Between(start, end))
+	if opts.Where != "" {
+		cmd += " AND " + opts.Where
+	}
+	return cmd
+}
+
+// EnforceRetention enforces a measurement's retention using the default
+// Client.
+func EnforceRetention(db string, opts RetentionEnforcementOptions) error {
+	return gClient().EnforceRetention(db, opts)
+}
+
+// EnforceRetentionContext is EnforceRetention with a context, using the
+// default Client.
+func EnforceRetentionContext(ctx context.Context, db string, opts RetentionEnforcementOptions) error {
+	return gClient().EnforceRetentionContext(ctx, db, opts)
+}
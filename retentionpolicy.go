@@ -0,0 +1,94 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+)
+
+// AlterRetentionPolicyOptions selects which clauses
+// AlterRetentionPolicyContext changes; a zero value for Duration,
+// ShardDuration or Replication leaves that property as InfluxDB
+// already has it.
+type AlterRetentionPolicyOptions struct {
+	Duration      string
+	ShardDuration string
+	Replication   int
+	Default       bool
+}
+
+// CreateRetentionPolicy runs CREATE RETENTION POLICY rp ON db DURATION
+// duration REPLICATION replication, so a service can self-provision the
+// retention policies its schema needs instead of requiring them be
+// created out of band.
+func (c *Client) CreateRetentionPolicy(db, rp, duration string, replication int, isDefault bool) error {
+	return c.CreateRetentionPolicyContext(context.Background(), db, rp, duration, replication, isDefault)
+}
+
+// CreateRetentionPolicyContext is CreateRetentionPolicy with a context
+// that aborts the request as soon as it is canceled.
+func (c *Client) CreateRetentionPolicyContext(ctx context.Context, db, rp, duration string, replication int, isDefault bool) error {
+	cmd := fmt.Sprintf("CREATE RETENTION POLICY %s ON %s DURATION %s REPLICATION %d",
+		Ident(rp), Ident(db), duration, replication)
+	if isDefault {
+		cmd += " DEFAULT"
+	}
+	_, err := c.QueryContext(ctx, "", cmd)
+	return err
+}
+
+// AlterRetentionPolicy runs ALTER RETENTION POLICY rp ON db, changing
+// whichever of opts' fields are set.
+func (c *Client) AlterRetentionPolicy(db, rp string, opts AlterRetentionPolicyOptions) error {
+	return c.AlterRetentionPolicyContext(context.Background(), db, rp, opts)
+}
+
+// AlterRetentionPolicyContext is AlterRetentionPolicy with a context
+// that aborts the request as soon as it is canceled.
+func (c *Client) AlterRetentionPolicyContext(ctx context.Context, db, rp string, opts AlterRetentionPolicyOptions) error {
+	cmd := fmt.Sprintf("ALTER RETENTION POLICY %s ON %s", Ident(rp), Ident(db))
+	if opts.Duration != "" {
+		cmd += " DURATION " + opts.Duration
+	}
+	if opts.ShardDuration != "" {
+		cmd += " SHARD DURATION " + opts.ShardDuration
+	}
+	if opts.Replication != 0 {
+		cmd += fmt.Sprintf(" REPLICATION %d", opts.Replication)
+	}
+	if opts.Default {
+		cmd += " DEFAULT"
+	}
+	_, err := c.QueryContext(ctx, "", cmd)
+	return err
+}
+
+// DropRetentionPolicy runs DROP RETENTION POLICY rp ON db, the
+// complement to CreateRetentionPolicy.
+func (c *Client) DropRetentionPolicy(db, rp string) error {
+	return c.DropRetentionPolicyContext(context.Background(), db, rp)
+}
+
+// DropRetentionPolicyContext is DropRetentionPolicy with a context that
+// aborts the request as soon as it is canceled.
+func (c *Client) DropRetentionPolicyContext(ctx context.Context, db, rp string) error {
+	_, err := c.QueryContext(ctx, "", fmt.Sprintf("DROP RETENTION POLICY %s ON %s", Ident(rp), Ident(db)))
+	return err
+}
+
+// CreateRetentionPolicy creates a retention policy using the default
+// Client.
+func CreateRetentionPolicy(db, rp, duration string, replication int, isDefault bool) error {
+	return gClient().CreateRetentionPolicy(db, rp, duration, replication, isDefault)
+}
+
+// AlterRetentionPolicy alters a retention policy using the default
+// Client.
+func AlterRetentionPolicy(db, rp string, opts AlterRetentionPolicyOptions) error {
+	return gClient().AlterRetentionPolicy(db, rp, opts)
+}
+
+// DropRetentionPolicy drops a retention policy using the default
+// Client.
+func DropRetentionPolicy(db, rp string) error {
+	return gClient().DropRetentionPolicy(db, rp)
+}
@@ -0,0 +1,69 @@
+package influx
+
+import "context"
+
+// RowIter lazily decodes a chunked query's rows into T one at a time,
+// the generic counterpart to ChunkedRows: Next advances and decodes,
+// Value returns what Next just decoded, pairing ChunkedRows' bounded
+// memory with QueryRows' compile-time-typed destination instead of
+// requiring a *T threaded through Scan by hand.
+//
+// This is a Next/Value iterator rather than a range-over-func one:
+// the module's go directive predates Go 1.23's iterator support, the
+// same reason Repository is a free function instead of a Client
+// method.
+type RowIter[T any] struct {
+	cr  *ChunkedRows
+	cur T
+	err error
+}
+
+// QueryIter runs cmd against db using the default Client's chunked
+// query transfer encoding (InfluxDB's own default chunk size),
+// returning a RowIter that decodes one row into a T at a time instead
+// of QueryRows' materialize-everything-up-front []T.
+func QueryIter[T any](ctx context.Context, db, cmd string) (*RowIter[T], error) {
+	cr, err := QueryChunkedContext(ctx, db, cmd, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &RowIter[T]{cr: cr}, nil
+}
+
+// Next advances to the next row, decoding it into the value Value then
+// returns, and reports whether one was found; see ChunkedRows.Next for
+// when it returns false (exhausted, Close called, or a terminal error,
+// see Err).
+func (it *RowIter[T]) Next() bool {
+	if it.err != nil || !it.cr.Next() {
+		return false
+	}
+	var v T
+	if err := it.cr.Scan(&v); err != nil {
+		it.err = err
+		return false
+	}
+	it.cur = v
+	return true
+}
+
+// Value returns the row Next last decoded.
+func (it *RowIter[T]) Value() T {
+	return it.cur
+}
+
+// Err returns the first terminal error encountered fetching a chunk,
+// iterating its rows, or decoding one into T, or nil if iteration
+// simply ran out of rows.
+func (it *RowIter[T]) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.cr.Err()
+}
+
+// Close ends iteration early and releases the underlying HTTP
+// response's connection: after Close, Next always returns false.
+func (it *RowIter[T]) Close() error {
+	return it.cr.Close()
+}
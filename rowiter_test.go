@@ -0,0 +1,57 @@
+package influx
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// chunkedFakeClient answers QueryAsChunk with a fixed sequence of
+// chunk responses, each InfluxDB's own chunked transfer encoding sends
+// as a standalone JSON document back to back on the same connection —
+// exactly what client.NewChunkedResponse decodes, so no real HTTP
+// chunked transfer is needed to exercise it.
+type chunkedFakeClient struct {
+	fakeClient
+	chunks string
+}
+
+func (f *chunkedFakeClient) QueryAsChunk(q client.Query) (*client.ChunkedResponse, error) {
+	return client.NewChunkedResponse(strings.NewReader(f.chunks)), nil
+}
+
+type cpuRow struct {
+	Host  string  `inf:"host,tag"`
+	Usage float64 `inf:"usage,field"`
+}
+
+// TestQueryIterDecodesRowsAcrossChunks confirms RowIter lazily decodes
+// every row of a chunked query into T, across more than one chunk.
+func TestQueryIterDecodesRowsAcrossChunks(t *testing.T) {
+	chunks := `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["host","usage"],"values":[["a",0.5]]}]}]}` +
+		`{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["host","usage"],"values":[["b",0.75]]}]}]}`
+
+	setGClient(NewWithClient(&chunkedFakeClient{chunks: chunks}))
+	defer setGClient(nil)
+
+	it, err := QueryIter[cpuRow](context.Background(), "db", "SELECT * FROM cpu")
+	if err != nil {
+		t.Fatalf("QueryIter: %v", err)
+	}
+	defer it.Close()
+
+	var got []cpuRow
+	for it.Next() {
+		got = append(got, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+
+	if len(got) != 2 || got[0].Host != "a" || got[0].Usage != 0.5 ||
+		got[1].Host != "b" || got[1].Usage != 0.75 {
+		t.Fatalf("got = %+v, want [{a 0.5} {b 0.75}]", got)
+	}
+}
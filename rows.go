@@ -0,0 +1,174 @@
+package influx
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/models"
+)
+
+// Rows iterates the rows of one or more series one at a time, the
+// database/sql-style counterpart to ParseResult/ParseResults: Next
+// advances to the next row, Scan decodes just that row, and Err
+// reports a terminal error instead of failing the whole call outright
+// the way ParseResults does — so a caller can stop early, stream
+// through a result too large to decode all at once, or keep going past
+// a malformed row instead of losing every row behind it.
+type Rows struct {
+	series  []models.Row
+	columns []string
+
+	sIdx int // index into series of the current row's series
+	rIdx int // index into series[sIdx].Values of the current row
+	row  int // rows seen so far across every series, for decodeErr
+
+	err    error
+	closed bool
+}
+
+// NewRows returns a Rows iterating every row of every series in
+// series, in order — the same rows ParseResults would otherwise
+// concatenate into a single []T. columns, if given, restricts Scan the
+// same way ParseResult's own columns argument does.
+func NewRows(series []models.Row, columns ...string) *Rows {
+	return &Rows{series: series, rIdx: -1, row: -1, columns: columns}
+}
+
+// RowsFromResults returns a Rows iterating every row of every series
+// of every result in results. If any result carries its own error
+// (InfluxDB's way of reporting a single statement's failure inline in
+// a multi-statement query), that error is not returned directly: Next
+// returns false from the start, without the rows of earlier results
+// either, and Err reports it. Check Err after Next returns false to
+// tell "every row was seen" apart from "a statement failed".
+func RowsFromResults(results []client.Result) *Rows {
+	r := &Rows{rIdx: -1, row: -1}
+	for _, result := range results {
+		if result.Err != "" {
+			r.err = errors.New(result.Err)
+			return r
+		}
+		r.series = append(r.series, result.Series...)
+	}
+	return r
+}
+
+// Next advances to the next row, returning false once every series is
+// exhausted, Close has been called, or a terminal error is set (see
+// Err). Call Scan to read the row Next just advanced to.
+func (r *Rows) Next() bool {
+	if r.closed || r.err != nil {
+		return false
+	}
+	for r.sIdx < len(r.series) {
+		if r.rIdx+1 < len(r.series[r.sIdx].Values) {
+			r.rIdx++
+			r.row++
+			return true
+		}
+		r.sIdx++
+		r.rIdx = -1
+	}
+	return false
+}
+
+// Scan decodes the current row (the one Next last advanced to) into
+// dst, the same destination shapes parseSingle supports for a single
+// row: a struct, a map, a scalar, and so on. Unlike Err, a Scan error
+// doesn't stop iteration — a caller that wants to skip a malformed row
+// can just call Next again.
+func (r *Rows) Scan(dst interface{}) error {
+	if r.rIdx < 0 || r.sIdx >= len(r.series) {
+		return errors.New("influx: Scan called without a successful Next")
+	}
+
+	dstVal := reflect.Indirect(reflect.ValueOf(dst))
+	if !dstVal.CanSet() {
+		return ErrNotSettable
+	}
+	dstVal = makePtrDstVal(dstVal)
+
+	serie := r.series[r.sIdx]
+	tags := serie.Tags
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+	if err := parseSingle(serie.Columns, serie.Values[r.rIdx], tags, dstVal, r.columns...); err != nil {
+		return attachMeasurement(decodeErr(err, r.row, "", "", nil), serie.Name)
+	}
+	return attachMeasurement(afterParse(dstVal, serie.Name), serie.Name)
+}
+
+// ScanRow positionally scans the current row's values into dsts, the
+// way sql.Rows.Scan assigns a row into a list of destination pointers,
+// instead of decoding the whole row into one struct or map the way
+// Scan does — for a quick ad-hoc query where defining a destination
+// type is overkill.
+func (r *Rows) ScanRow(dsts ...interface{}) error {
+	if r.rIdx < 0 || r.sIdx >= len(r.series) {
+		return errors.New("influx: ScanRow called without a successful Next")
+	}
+	if err := ScanRow(r.series[r.sIdx].Values[r.rIdx], dsts...); err != nil {
+		return attachMeasurement(decodeErr(err, r.row, "", "", nil), r.series[r.sIdx].Name)
+	}
+	return nil
+}
+
+// Values returns the current row's raw values in column order, the
+// same slice Scan would otherwise decode through parseSingle — for a
+// caller that wants the values as InfluxDB returned them instead of
+// decoded into a struct, map or scalar destination (e.g. a
+// database/sql/driver.Rows.Next implementation, which fills an
+// already-allocated slice of driver.Value itself).
+func (r *Rows) Values() []interface{} {
+	if r.rIdx < 0 || r.sIdx >= len(r.series) {
+		return nil
+	}
+	return r.series[r.sIdx].Values[r.rIdx]
+}
+
+// Name returns the current row's series name (measurement), or "" if
+// Next hasn't been called yet or has run out of rows.
+func (r *Rows) Name() string {
+	if r.rIdx < 0 || r.sIdx >= len(r.series) {
+		return ""
+	}
+	return r.series[r.sIdx].Name
+}
+
+// Tags returns the current row's series tag set, or nil if Next hasn't
+// been called yet or has run out of rows.
+func (r *Rows) Tags() map[string]string {
+	if r.rIdx < 0 || r.sIdx >= len(r.series) {
+		return nil
+	}
+	return r.series[r.sIdx].Tags
+}
+
+// Columns returns the current row's series columns, in the same order
+// Values does, or nil if Next hasn't been called yet or has run out of
+// rows.
+func (r *Rows) Columns() []string {
+	if r.rIdx < 0 || r.sIdx >= len(r.series) {
+		return nil
+	}
+	return r.series[r.sIdx].Columns
+}
+
+// Err returns the first error encountered building or iterating Rows —
+// e.g. a statement's own error from RowsFromResults — or nil if
+// iteration simply ran out of rows. It never reports a Scan error,
+// which Scan itself already returned.
+func (r *Rows) Err() error {
+	return r.err
+}
+
+// Close ends iteration early: after Close, Next always returns false.
+// Rows holds no resources of its own (every row is already in memory),
+// so Close exists for API parity with database/sql and never itself
+// returns an error.
+func (r *Rows) Close() error {
+	r.closed = true
+	return nil
+}
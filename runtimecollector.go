@@ -0,0 +1,89 @@
+package influx
+
+import (
+	"runtime"
+	"time"
+)
+
+// DefaultRuntimeMeasurement is the measurement StartRuntimeCollector
+// writes to when measurement is empty.
+const DefaultRuntimeMeasurement = "go_runtime"
+
+// RuntimeStats is one sample of Go runtime and process self-telemetry,
+// written by StartRuntimeCollector.
+type RuntimeStats struct {
+	Goroutines    int     `inf:"goroutines"`
+	HeapAlloc     uint64  `inf:"heap_alloc_bytes"`
+	HeapSys       uint64  `inf:"heap_sys_bytes"`
+	HeapObjects   uint64  `inf:"heap_objects"`
+	StackInuse    uint64  `inf:"stack_inuse_bytes"`
+	Sys           uint64  `inf:"sys_bytes"`
+	NumGC         uint32  `inf:"num_gc"`
+	GCPauseNs     uint64  `inf:"gc_pause_ns"`
+	GCCPUFraction float64 `inf:"gc_cpu_fraction"`
+}
+
+// StartRuntimeCollector registers a Collector (see RegisterCollector)
+// that samples runtime.MemStats, the live goroutine count and the
+// most recent GC pause into db every interval as a RuntimeStats point,
+// giving a service built on this package basic self-telemetry for
+// free instead of needing a separate Telegraf input just to watch its
+// own process. measurement names the written points, via
+// RegisterMeasurement; an empty measurement uses
+// DefaultRuntimeMeasurement. interval defaults to
+// DefaultCollectorInterval, the same as RegisterCollector.
+//
+// RuntimeStats's measurement is process-wide, like RegisterMeasurement
+// itself: calling StartRuntimeCollector more than once with different
+// measurements changes where every collector started so far writes,
+// not just the most recent one.
+//
+// RuntimeStats carries no service or host field of its own; call
+// SetGlobalTags (e.g. {"service": "api", "host": hostname}) once on c
+// and every RuntimeStats point, like every other point c writes, is
+// tagged with it.
+func (c *Client) StartRuntimeCollector(db string, interval time.Duration, measurement string) {
+	if measurement == "" {
+		measurement = DefaultRuntimeMeasurement
+	}
+	RegisterMeasurement(RuntimeStats{}, measurement)
+
+	var lastNumGC uint32
+	c.RegisterCollector(db, interval, func() []interface{} {
+		return []interface{}{sampleRuntimeStats(&lastNumGC)}
+	})
+}
+
+// sampleRuntimeStats reads the process's current runtime.MemStats and
+// goroutine count into a RuntimeStats sample. GCPauseNs is the pause
+// of the most recent GC to complete since the last sample, or 0 if
+// none ran; *lastNumGC tracks runtime.MemStats.NumGC across calls so
+// each sample only reports a pause once.
+func sampleRuntimeStats(lastNumGC *uint32) RuntimeStats {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	var pauseNs uint64
+	if m.NumGC > *lastNumGC {
+		pauseNs = m.PauseNs[(m.NumGC+255)%256]
+	}
+	*lastNumGC = m.NumGC
+
+	return RuntimeStats{
+		Goroutines:    runtime.NumGoroutine(),
+		HeapAlloc:     m.HeapAlloc,
+		HeapSys:       m.HeapSys,
+		HeapObjects:   m.HeapObjects,
+		StackInuse:    m.StackInuse,
+		Sys:           m.Sys,
+		NumGC:         m.NumGC,
+		GCPauseNs:     pauseNs,
+		GCCPUFraction: m.GCCPUFraction,
+	}
+}
+
+// StartRuntimeCollector starts a runtime telemetry collector on the
+// package-level default Client.
+func StartRuntimeCollector(db string, interval time.Duration, measurement string) {
+	gClient().StartRuntimeCollector(db, interval, measurement)
+}
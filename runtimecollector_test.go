@@ -0,0 +1,68 @@
+package influx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// TestSampleRuntimeStatsReportsGoroutines confirms sampleRuntimeStats
+// reports a live goroutine count and MemStats-derived fields.
+func TestSampleRuntimeStatsReportsGoroutines(t *testing.T) {
+	var lastNumGC uint32
+	stats := sampleRuntimeStats(&lastNumGC)
+	if stats.Goroutines <= 0 {
+		t.Fatalf("Goroutines = %d, want > 0", stats.Goroutines)
+	}
+	if stats.Sys == 0 {
+		t.Fatal("Sys = 0, want the process's reported memory")
+	}
+}
+
+// TestSampleRuntimeStatsReportsPauseOncePerGC confirms a GC pause is
+// only reported the first time it's sampled after NumGC advances.
+func TestSampleRuntimeStatsReportsPauseOncePerGC(t *testing.T) {
+	var lastNumGC uint32
+	first := sampleRuntimeStats(&lastNumGC)
+	second := sampleRuntimeStats(&lastNumGC)
+	if second.NumGC != first.NumGC {
+		t.Fatalf("NumGC changed between back-to-back samples with no GC forced: %d -> %d", first.NumGC, second.NumGC)
+	}
+	if second.GCPauseNs != 0 {
+		t.Fatalf("GCPauseNs = %d, want 0 for a sample with no new GC since the last one", second.GCPauseNs)
+	}
+}
+
+// TestRuntimeCollectorSampleCarriesGlobalTags confirms a RuntimeStats
+// sample written through runCollector (the same path
+// StartRuntimeCollector schedules on a ticker) picks up SetGlobalTags'
+// service/host tags, since RuntimeStats itself has no such field.
+func TestRuntimeCollectorSampleCarriesGlobalTags(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := make([]byte, r.ContentLength)
+		r.Body.Read(buf)
+		body = string(buf)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetGlobalTags(map[string]string{"service": "api", "host": "box1"})
+
+	RegisterMeasurement(RuntimeStats{}, DefaultRuntimeMeasurement)
+	var lastNumGC uint32
+	c.runCollector("mydb", func() []interface{} {
+		return []interface{}{sampleRuntimeStats(&lastNumGC)}
+	})
+
+	if !strings.Contains(body, "service=api") || !strings.Contains(body, "host=box1") {
+		t.Fatalf("body = %q, missing service/host tags", body)
+	}
+}
@@ -0,0 +1,101 @@
+package influx
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// SanitizePolicy controls how ToPoint handles a NaN or ±Inf float field,
+// since InfluxDB rejects such a field and, if it had been let through
+// to client.NewPoint, would fail the whole point outright.
+type SanitizePolicy int
+
+// Sanitize policies for SanitizeConfig.Policy.
+const (
+	// SanitizeDropField removes the offending field, keeping the rest
+	// of the point. This is the default (zero value).
+	SanitizeDropField SanitizePolicy = iota
+	// SanitizeDropPoint drops the whole struct, so ToPoint returns
+	// (nil, ErrPointDropped).
+	SanitizeDropPoint
+	// SanitizeReplace replaces the offending field's value with
+	// SanitizeConfig.Sentinel, keeping the field.
+	SanitizeReplace
+)
+
+// SanitizeConfig configures the NaN/±Inf field policy enabled by
+// SetSanitizePolicy.
+type SanitizeConfig struct {
+	Policy SanitizePolicy
+	// Sentinel is the value a NaN/±Inf field is replaced with under
+	// SanitizeReplace. Ignored by the other policies.
+	Sentinel float64
+}
+
+// ErrPointDropped is returned by ToPoint when SanitizeDropPoint is in
+// effect and the struct had a NaN/±Inf field.
+var ErrPointDropped = errors.New("influx: point dropped: NaN/Inf field")
+
+var (
+	sanitizeMu    sync.Mutex
+	sanitizeCfg   *SanitizeConfig // nil means no sanitization, the default
+	sanitizeCount int64           // atomic
+)
+
+// SetSanitizePolicy enables sanitization of NaN/±Inf float fields in
+// every point ToPoint (and so Insert, InsertMany and their variants)
+// builds, per cfg, so a producer that occasionally computes a bad float
+// (e.g. a 0/0 ratio) doesn't fail the whole point. SanitizedCount
+// reports how many field values have been sanitized so far.
+//
+// Calling SetSanitizePolicy again replaces the previous policy; there
+// is no way to disable sanitization once enabled, since InfluxDB has
+// no use for a NaN/±Inf field anyway.
+func SetSanitizePolicy(cfg SanitizeConfig) {
+	sanitizeMu.Lock()
+	sanitizeCfg = &cfg
+	sanitizeMu.Unlock()
+}
+
+// SanitizedCount returns the number of field values SetSanitizePolicy
+// has sanitized so far.
+func SanitizedCount() int64 {
+	return atomic.LoadInt64(&sanitizeCount)
+}
+
+// sanitizeFields applies the active SanitizeConfig (if any) to fields
+// in place, reporting ok=false if SanitizeDropPoint took effect and the
+// whole point (so the whole struct) should be dropped.
+func sanitizeFields(fields map[string]interface{}) (ok bool) {
+	sanitizeMu.Lock()
+	cfg := sanitizeCfg
+	sanitizeMu.Unlock()
+	if cfg == nil {
+		return true
+	}
+
+	var bad int
+	for name, v := range fields {
+		f, isFloat := v.(float64)
+		if !isFloat || !(math.IsNaN(f) || math.IsInf(f, 0)) {
+			continue
+		}
+		bad++
+		switch cfg.Policy {
+		case SanitizeReplace:
+			fields[name] = cfg.Sentinel
+		case SanitizeDropPoint:
+			// leave fields untouched; the whole point is dropped below
+		default: // SanitizeDropField
+			delete(fields, name)
+		}
+	}
+	if bad == 0 {
+		return true
+	}
+
+	atomic.AddInt64(&sanitizeCount, int64(bad))
+	return cfg.Policy != SanitizeDropPoint
+}
@@ -0,0 +1,107 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Count runs SELECT count(field) FROM measurement on db, ANDing where
+// and timeRange onto its WHERE clause (either may be "" to omit its
+// half), and returns the count — the single-number aggregate query a
+// large fraction of dashboards otherwise hand-write and hand-decode.
+func (c *Client) Count(db, measurement, field, where, timeRange string) (int64, error) {
+	return c.CountContext(context.Background(), db, measurement, field, where, timeRange)
+}
+
+// CountContext is Count with a context that aborts the request as soon
+// as it is canceled.
+func (c *Client) CountContext(ctx context.Context, db, measurement, field, where, timeRange string) (int64, error) {
+	var n int64
+	err := c.scalarAggregate(ctx, &n, db, measurement, fmt.Sprintf("count(%s)", Ident(field)), "count", where, timeRange)
+	return n, err
+}
+
+// Mean runs SELECT mean(field) FROM measurement on db, ANDing where
+// and timeRange onto its WHERE clause, and returns the mean.
+func (c *Client) Mean(db, measurement, field, where, timeRange string) (float64, error) {
+	return c.MeanContext(context.Background(), db, measurement, field, where, timeRange)
+}
+
+// MeanContext is Mean with a context that aborts the request as soon
+// as it is canceled.
+func (c *Client) MeanContext(ctx context.Context, db, measurement, field, where, timeRange string) (float64, error) {
+	var mean float64
+	err := c.scalarAggregate(ctx, &mean, db, measurement, fmt.Sprintf("mean(%s)", Ident(field)), "mean", where, timeRange)
+	return mean, err
+}
+
+// Sum runs SELECT sum(field) FROM measurement on db, ANDing where and
+// timeRange onto its WHERE clause, and returns the sum.
+func (c *Client) Sum(db, measurement, field, where, timeRange string) (float64, error) {
+	return c.SumContext(context.Background(), db, measurement, field, where, timeRange)
+}
+
+// SumContext is Sum with a context that aborts the request as soon as
+// it is canceled.
+func (c *Client) SumContext(ctx context.Context, db, measurement, field, where, timeRange string) (float64, error) {
+	var sum float64
+	err := c.scalarAggregate(ctx, &sum, db, measurement, fmt.Sprintf("sum(%s)", Ident(field)), "sum", where, timeRange)
+	return sum, err
+}
+
+// Percentile runs SELECT percentile(field, pct) FROM measurement on
+// db, ANDing where and timeRange onto its WHERE clause, and returns the
+// pct-th percentile (0-100) of field's values.
+func (c *Client) Percentile(db, measurement, field string, pct float64, where, timeRange string) (float64, error) {
+	return c.PercentileContext(context.Background(), db, measurement, field, pct, where, timeRange)
+}
+
+// PercentileContext is Percentile with a context that aborts the
+// request as soon as it is canceled.
+func (c *Client) PercentileContext(ctx context.Context, db, measurement, field string, pct float64, where, timeRange string) (float64, error) {
+	var p float64
+	aggExpr := fmt.Sprintf("percentile(%s, %v)", Ident(field), pct)
+	err := c.scalarAggregate(ctx, &p, db, measurement, aggExpr, "percentile", where, timeRange)
+	return p, err
+}
+
+// scalarAggregate runs "SELECT aggExpr FROM measurement WHERE ..." on
+// db and decodes its single aggName column into dst, the shared
+// plumbing Count/Mean/Sum/Percentile all build on.
+func (c *Client) scalarAggregate(ctx context.Context, dst interface{}, db, measurement, aggExpr, aggName, where, timeRange string) error {
+	cmd := fmt.Sprintf("SELECT %s FROM %s", aggExpr, Ident(measurement))
+
+	var conds []string
+	if where != "" {
+		conds = append(conds, where)
+	}
+	if timeRange != "" {
+		conds = append(conds, timeRange)
+	}
+	if len(conds) > 0 {
+		cmd += " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	return c.QueryIntoContext(ctx, dst, db, cmd, aggName)
+}
+
+// Count runs Count using the default Client.
+func Count(db, measurement, field, where, timeRange string) (int64, error) {
+	return gClient().Count(db, measurement, field, where, timeRange)
+}
+
+// Mean runs Mean using the default Client.
+func Mean(db, measurement, field, where, timeRange string) (float64, error) {
+	return gClient().Mean(db, measurement, field, where, timeRange)
+}
+
+// Sum runs Sum using the default Client.
+func Sum(db, measurement, field, where, timeRange string) (float64, error) {
+	return gClient().Sum(db, measurement, field, where, timeRange)
+}
+
+// Percentile runs Percentile using the default Client.
+func Percentile(db, measurement, field string, pct float64, where, timeRange string) (float64, error) {
+	return gClient().Percentile(db, measurement, field, pct, where, timeRange)
+}
@@ -0,0 +1,29 @@
+package influx
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ScanRow assigns vals positionally into dsts, the way sql.Rows.Scan
+// assigns a driver row's values into a list of destination pointers —
+// for a quick ad-hoc query where defining a struct just to decode one
+// row is overkill. Each dst must be a non-nil pointer; vals beyond
+// len(dsts) are ignored, but fewer vals than dsts is an error.
+func ScanRow(vals []interface{}, dsts ...interface{}) error {
+	if len(vals) < len(dsts) {
+		return fmt.Errorf("influx: ScanRow needs %d values, got %d", len(dsts), len(vals))
+	}
+	for i, dst := range dsts {
+		dstVal := reflect.ValueOf(dst)
+		if dstVal.Kind() != reflect.Ptr || dstVal.IsNil() {
+			return fmt.Errorf("influx: ScanRow dst %d must be a non-nil pointer", i)
+		}
+		col := fmt.Sprintf("$%d", i)
+		field := makePtrDstVal(reflect.Indirect(dstVal))
+		if err := parseSingle([]string{col}, vals[i:i+1], emptyTags, field); err != nil {
+			return decodeErr(err, -1, col, col, vals[i])
+		}
+	}
+	return nil
+}
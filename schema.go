@@ -0,0 +1,335 @@
+package influx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// schemaOptions are the struct-level options parsed from the `inf` tag
+// on a mapped struct's anchor field, e.g.
+//
+//	_ struct{} `inf:"rp=7d,shard=1h,cq=downsample_1h,interval=1h"`
+//
+// measurement may be omitted, in which case it defaults to the same
+// snake-cased type name ToPoint would use.
+type schemaOptions struct {
+	measurement string
+	rp          string
+	shard       string
+	cq          string
+	interval    string
+}
+
+// schemaTagOptions are the known struct-level keys; a field's `inf` tag
+// is the anchor field if it parses entirely into key=value pairs using
+// only these keys, rather than the `name[,tag|field][,...]` shape
+// regular mapped fields use.
+var schemaTagOptions = map[string]bool{
+	"measurement": true,
+	"rp":          true,
+	"shard":       true,
+	"cq":          true,
+	"interval":    true,
+}
+
+func parseSchema(structure interface{}) (schemaOptions, error) {
+	val := reflect.Indirect(reflect.ValueOf(structure))
+	if val.Kind() != reflect.Struct {
+		return schemaOptions{}, fmt.Errorf("influx: schema: %w", ErrNotPointer)
+	}
+
+	var opts schemaOptions
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		kv, ok := parseSchemaTag(structTag(typ.Field(i)))
+		if !ok {
+			continue
+		}
+		opts.measurement = kv["measurement"]
+		opts.rp = kv["rp"]
+		opts.shard = kv["shard"]
+		opts.cq = kv["cq"]
+		opts.interval = kv["interval"]
+		break
+	}
+
+	if opts.measurement == "" {
+		name := val.Type().Name()
+		if idx := strings.LastIndexByte(name, '.'); idx >= 0 {
+			name = name[idx+1:]
+		}
+		opts.measurement = titleToSnake(name)
+	}
+	return opts, nil
+}
+
+// parseSchemaTag parses tagstr as the anchor field's struct-level
+// options. ok is false when tagstr is empty, or contains anything other
+// than `key=value` pairs whose key is a known schema option — i.e. when
+// it's an ordinary mapped field's tag instead.
+func parseSchemaTag(tagstr string) (map[string]string, bool) {
+	if tagstr == "" {
+		return nil, false
+	}
+	kv := make(map[string]string)
+	for _, part := range strings.Split(tagstr, ",") {
+		eq := strings.IndexByte(part, '=')
+		if eq < 0 {
+			return nil, false
+		}
+		k, v := part[:eq], part[eq+1:]
+		if !schemaTagOptions[k] {
+			return nil, false
+		}
+		kv[k] = v
+	}
+	return kv, true
+}
+
+// Migrate idempotently creates c.DB and the retention policies and
+// continuous queries declared via struct-level `inf` tags on structs.
+// It is safe to call repeatedly, e.g. on every process start.
+func (c *Client) Migrate(ctx context.Context, structs ...interface{}) error {
+	if c.DB == "" {
+		return errors.New("influx: Client.DB must be set before Migrate")
+	}
+	if _, err := c.QueryContext(ctx, c.DB, "CREATE DATABASE IF NOT EXISTS "+Ident(c.DB)); err != nil {
+		return err
+	}
+
+	for _, structure := range structs {
+		opts, err := parseSchema(structure)
+		if err != nil {
+			return err
+		}
+
+		if opts.rp != "" {
+			cmd := fmt.Sprintf("CREATE RETENTION POLICY %s ON %s DURATION %s REPLICATION 1",
+				Ident(retentionPolicyName(opts)), Ident(c.DB), opts.rp)
+			if opts.shard != "" {
+				cmd += " SHARD DURATION " + opts.shard
+			}
+			if _, err := c.QueryContext(ctx, c.DB, cmd); err != nil && !alreadyExists(err) {
+				return err
+			}
+		}
+
+		if opts.cq != "" {
+			cmd := fmt.Sprintf(
+				"CREATE CONTINUOUS QUERY %s ON %s BEGIN SELECT mean(*) INTO %s FROM %s GROUP BY time(%s) END",
+				Ident(opts.cq), Ident(c.DB), Ident(opts.measurement+"_"+opts.cq), Ident(opts.measurement), cqGroupInterval(opts))
+			if _, err := c.QueryContext(ctx, c.DB, cmd); err != nil && !alreadyExists(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DropSchema drops the continuous queries and retention policies
+// Migrate would have created for structs, for test teardown. It does
+// not drop c.DB itself.
+func (c *Client) DropSchema(ctx context.Context, structs ...interface{}) error {
+	for _, structure := range structs {
+		opts, err := parseSchema(structure)
+		if err != nil {
+			return err
+		}
+
+		if opts.cq != "" {
+			cmd := fmt.Sprintf("DROP CONTINUOUS QUERY %s ON %s", Ident(opts.cq), Ident(c.DB))
+			if _, err := c.QueryContext(ctx, c.DB, cmd); err != nil && !notFound(err) {
+				return err
+			}
+		}
+
+		if opts.rp != "" {
+			cmd := fmt.Sprintf("DROP RETENTION POLICY %s ON %s", Ident(retentionPolicyName(opts)), Ident(c.DB))
+			if _, err := c.QueryContext(ctx, c.DB, cmd); err != nil && !notFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func retentionPolicyName(opts schemaOptions) string {
+	return opts.measurement + "_rp"
+}
+
+func alreadyExists(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "already exists")
+}
+
+func notFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "not found")
+}
+
+// cqGroupInterval returns the GROUP BY time() interval for opts' CQ,
+// taken from its explicit interval= tag option, defaulting to 1h when
+// unset.
+func cqGroupInterval(opts schemaOptions) string {
+	if opts.interval != "" {
+		return opts.interval
+	}
+	return "1h"
+}
+
+// MeasurementSchema is one struct type's declared InfluxDB shape,
+// derived from the same `inf` tag parsing ToPoint itself uses so it
+// can't drift from what ToPoint would actually write: its measurement
+// name, the database and retention policy it's provisioned under, and
+// its tag and field names.
+type MeasurementSchema struct {
+	Measurement string
+	Database    string
+	// RetentionPolicy is a duration, e.g. "30d" (the same value a
+	// struct's `rp=` inf tag option carries), not a policy name;
+	// EnsureSchema derives the policy's name the same way Migrate
+	// does. Empty means the database's default retention policy.
+	RetentionPolicy string
+	// ShardDuration is the `shard=` inf tag option, if any.
+	ShardDuration string
+	Tags          []string
+	Fields        map[string]reflect.Kind
+}
+
+// Schema is a set of MeasurementSchemas built from registered struct
+// types, letting an application declare its entire InfluxDB layout in
+// code once and provision it with EnsureSchema on startup, instead of
+// hand-writing migration SQL or relying on InfluxDB's implicit
+// schema-on-write.
+type Schema struct {
+	mu           sync.Mutex
+	measurements map[string]MeasurementSchema
+}
+
+// NewSchema returns an empty Schema.
+func NewSchema() *Schema {
+	return &Schema{measurements: make(map[string]MeasurementSchema)}
+}
+
+// Register derives a MeasurementSchema for each of structs (a struct
+// or pointer to one, the same shape ToPoint accepts) from its `inf`
+// tags and adds it to s under db, replacing any schema already
+// registered for the same measurement.
+func (s *Schema) Register(db string, structs ...interface{}) error {
+	for _, structure := range structs {
+		ms, err := measurementSchema(db, structure)
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.measurements[ms.Measurement] = ms
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// Measurements returns a snapshot of s's registered MeasurementSchemas.
+func (s *Schema) Measurements() []MeasurementSchema {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]MeasurementSchema, 0, len(s.measurements))
+	for _, ms := range s.measurements {
+		out = append(out, ms)
+	}
+	return out
+}
+
+// measurementSchema builds structure's MeasurementSchema.
+func measurementSchema(db string, structure interface{}) (MeasurementSchema, error) {
+	opts, err := parseSchema(structure)
+	if err != nil {
+		return MeasurementSchema{}, err
+	}
+
+	val := reflect.Indirect(reflect.ValueOf(structure))
+	plan := planType(val.Type())
+	if plan.err != nil {
+		return MeasurementSchema{}, plan.err
+	}
+
+	ms := MeasurementSchema{
+		Measurement:     opts.measurement,
+		Database:        db,
+		RetentionPolicy: opts.rp,
+		ShardDuration:   opts.shard,
+		Fields:          make(map[string]reflect.Kind),
+	}
+	for _, fp := range plan.fields {
+		if fp.isTime || fp.isMapFields || fp.isMapTags {
+			continue
+		}
+		if fp.isTag {
+			ms.Tags = append(ms.Tags, fp.name)
+			continue
+		}
+		ms.Fields[fp.name] = val.Type().FieldByIndex(fp.index).Type.Kind()
+	}
+	return ms, nil
+}
+
+// EnsureSchema creates the database and retention policy each of s's
+// registered measurements declares, so an environment can be
+// bootstrapped from code alone. Like Migrate, it is idempotent and
+// safe to call on every process start.
+func (c *Client) EnsureSchema(ctx context.Context, s *Schema) error {
+	createdDB := make(map[string]bool)
+	for _, ms := range s.Measurements() {
+		if ms.Database == "" {
+			return fmt.Errorf("influx: schema for measurement %q has no Database", ms.Measurement)
+		}
+		if !createdDB[ms.Database] {
+			if _, err := c.QueryContext(ctx, ms.Database, "CREATE DATABASE IF NOT EXISTS "+Ident(ms.Database)); err != nil {
+				return err
+			}
+			createdDB[ms.Database] = true
+		}
+
+		if ms.RetentionPolicy == "" {
+			continue
+		}
+		cmd := fmt.Sprintf("CREATE RETENTION POLICY %s ON %s DURATION %s REPLICATION 1",
+			Ident(retentionPolicyName(schemaOptions{measurement: ms.Measurement})), Ident(ms.Database), ms.RetentionPolicy)
+		if ms.ShardDuration != "" {
+			cmd += " SHARD DURATION " + ms.ShardDuration
+		}
+		if _, err := c.QueryContext(ctx, ms.Database, cmd); err != nil && !alreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnsureSchemaFor is EnsureSchema for a caller with a handful of struct
+// types in hand instead of a pre-built *Schema: it registers types
+// under db into a throwaway Schema, then provisions it the same way
+// EnsureSchema does, for a service that just wants to self-provision
+// its own measurements on startup.
+func (c *Client) EnsureSchemaFor(ctx context.Context, db string, types ...interface{}) error {
+	s := NewSchema()
+	if err := s.Register(db, types...); err != nil {
+		return err
+	}
+	return c.EnsureSchema(ctx, s)
+}
+
+// EnsureSchema creates db and provisions the retention policies types
+// declare via their rp= inf tag (see Schema, Client.EnsureSchema), using
+// the default Client — the package-level shorthand for bootstrapping
+// one database's schema from a handful of types on startup, without
+// building a *Schema by hand.
+func EnsureSchema(db string, types ...interface{}) error {
+	return EnsureSchemaContext(context.Background(), db, types...)
+}
+
+// EnsureSchemaContext is EnsureSchema with a context, using the default
+// Client.
+func EnsureSchemaContext(ctx context.Context, db string, types ...interface{}) error {
+	return gClient().EnsureSchemaFor(ctx, db, types...)
+}
@@ -0,0 +1,69 @@
+package influx
+
+import (
+	"reflect"
+	"testing"
+)
+
+type schemaMetric struct {
+	_     struct{} `inf:"measurement=cpu_usage,rp=30d,shard=1h"`
+	Host  string   `inf:"host,tag"`
+	Value float64
+	Count int64
+}
+
+// TestSchemaRegisterDerivesMeasurementSchema confirms Register builds
+// a MeasurementSchema matching the struct's `inf` tags, so it can't
+// drift from what ToPoint itself would write for the same struct.
+func TestSchemaRegisterDerivesMeasurementSchema(t *testing.T) {
+	s := NewSchema()
+	if err := s.Register("mydb", schemaMetric{}); err != nil {
+		t.Fatal(err)
+	}
+
+	measurements := s.Measurements()
+	if len(measurements) != 1 {
+		t.Fatalf("len(Measurements()) = %d, want 1", len(measurements))
+	}
+	ms := measurements[0]
+
+	if ms.Measurement != "cpu_usage" || ms.Database != "mydb" || ms.RetentionPolicy != "30d" || ms.ShardDuration != "1h" {
+		t.Fatalf("ms = %+v", ms)
+	}
+	if len(ms.Tags) != 1 || ms.Tags[0] != "host" {
+		t.Fatalf("Tags = %v, want [host]", ms.Tags)
+	}
+	wantFields := map[string]reflect.Kind{"value": reflect.Float64, "count": reflect.Int64}
+	if !reflect.DeepEqual(ms.Fields, wantFields) {
+		t.Fatalf("Fields = %v, want %v", ms.Fields, wantFields)
+	}
+}
+
+// TestSchemaRegisterReplacesSameMeasurement confirms registering the
+// same measurement twice keeps only the latest MeasurementSchema.
+func TestSchemaRegisterReplacesSameMeasurement(t *testing.T) {
+	s := NewSchema()
+	if err := s.Register("db1", schemaMetric{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Register("db2", schemaMetric{}); err != nil {
+		t.Fatal(err)
+	}
+
+	measurements := s.Measurements()
+	if len(measurements) != 1 {
+		t.Fatalf("len(Measurements()) = %d, want 1", len(measurements))
+	}
+	if measurements[0].Database != "db2" {
+		t.Fatalf("Database = %q, want db2", measurements[0].Database)
+	}
+}
+
+// TestSchemaRegisterRejectsNonStruct confirms Register reports an
+// error instead of panicking on a non-struct value.
+func TestSchemaRegisterRejectsNonStruct(t *testing.T) {
+	s := NewSchema()
+	if err := s.Register("mydb", 42); err == nil {
+		t.Fatal("want error")
+	}
+}
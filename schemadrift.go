@@ -0,0 +1,173 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// SchemaDriftKind classifies one SchemaDrift finding.
+type SchemaDriftKind string
+
+// SchemaDriftKinds CheckDrift can report.
+const (
+	// DriftFieldTypeConflict is a registered field whose Go type maps
+	// to a different InfluxDB field type than SHOW FIELD KEYS reports
+	// for it, e.g. a struct declares int64 but the server already has
+	// a float field under that name.
+	DriftFieldTypeConflict SchemaDriftKind = "field_type_conflict"
+	// DriftTagBecameField is a name registered as a tag that SHOW
+	// FIELD KEYS reports as a field instead, e.g. after a write that
+	// omitted the tag, so InfluxDB fell back to writing it as a field.
+	DriftTagBecameField SchemaDriftKind = "tag_became_field"
+	// DriftFieldBecameTag is the reverse of DriftTagBecameField: a
+	// name registered as a field that SHOW TAG KEYS reports instead.
+	DriftFieldBecameTag SchemaDriftKind = "field_became_tag"
+	// DriftUnexpectedField is a field SHOW FIELD KEYS reports that the
+	// schema never declared.
+	DriftUnexpectedField SchemaDriftKind = "unexpected_field"
+	// DriftUnexpectedTag is a tag SHOW TAG KEYS reports that the
+	// schema never declared.
+	DriftUnexpectedTag SchemaDriftKind = "unexpected_tag"
+)
+
+// SchemaDrift is one way a measurement's actual InfluxDB shape no
+// longer matches its registered MeasurementSchema, as found by
+// CheckDrift.
+type SchemaDrift struct {
+	Measurement string
+	Kind        SchemaDriftKind
+	Name        string
+	// Want and Got are the schema's and the server's disagreeing
+	// values; only set for DriftFieldTypeConflict.
+	Want, Got FieldDataType
+}
+
+// String renders d as a one-line message, e.g. for logging.
+func (d SchemaDrift) String() string {
+	switch d.Kind {
+	case DriftFieldTypeConflict:
+		return fmt.Sprintf("%s: field %q is %s on the server, schema declares %s", d.Measurement, d.Name, d.Got, d.Want)
+	case DriftTagBecameField:
+		return fmt.Sprintf("%s: %q is a tag in the schema but a field on the server", d.Measurement, d.Name)
+	case DriftFieldBecameTag:
+		return fmt.Sprintf("%s: %q is a field in the schema but a tag on the server", d.Measurement, d.Name)
+	case DriftUnexpectedField:
+		return fmt.Sprintf("%s: field %q (%s) exists on the server but isn't in the schema", d.Measurement, d.Name, d.Got)
+	case DriftUnexpectedTag:
+		return fmt.Sprintf("%s: tag %q exists on the server but isn't in the schema", d.Measurement, d.Name)
+	default:
+		return fmt.Sprintf("%s: %s %q", d.Measurement, d.Kind, d.Name)
+	}
+}
+
+// CheckDrift compares each of s's registered MeasurementSchemas
+// against its actual shape on InfluxDB, via SHOW FIELD KEYS and SHOW
+// TAG KEYS, and reports every field whose server type conflicts with
+// its declared Go type, every tag/field that swapped kind, and every
+// field/tag the server has that the schema never declared. A
+// measurement with no series written yet reports no drift, since
+// there's nothing on the server yet to conflict with.
+func (c *Client) CheckDrift(ctx context.Context, s *Schema) ([]SchemaDrift, error) {
+	var drift []SchemaDrift
+	for _, ms := range s.Measurements() {
+		fieldKeys, err := c.ShowFieldKeysContext(ctx, ms.Database, ms.Measurement)
+		if err != nil {
+			return nil, err
+		}
+		tagKeys, err := c.ShowTagKeysContext(ctx, ms.Database, ms.Measurement)
+		if err != nil {
+			return nil, err
+		}
+		drift = append(drift, ms.diff(fieldKeys, tagKeys)...)
+	}
+	return drift, nil
+}
+
+// ValidateSchema is CheckDrift for a single struct v (a struct or
+// pointer to one, the same shape ToPoint accepts), without requiring it
+// to be registered in a Schema first: it derives v's MeasurementSchema
+// the same way Schema.Register would, then reports every drift against
+// db's actual SHOW FIELD KEYS/SHOW TAG KEYS shape. Meant as a pre-write
+// sanity check (e.g. in a CI smoke test, or once at startup) so a field
+// type conflict surfaces before ingestion starts instead of as a
+// confusing write error.
+func (c *Client) ValidateSchema(ctx context.Context, db string, v interface{}) ([]SchemaDrift, error) {
+	ms, err := measurementSchema(db, v)
+	if err != nil {
+		return nil, err
+	}
+	fieldKeys, err := c.ShowFieldKeysContext(ctx, db, ms.Measurement)
+	if err != nil {
+		return nil, err
+	}
+	tagKeys, err := c.ShowTagKeysContext(ctx, db, ms.Measurement)
+	if err != nil {
+		return nil, err
+	}
+	return ms.diff(fieldKeys, tagKeys), nil
+}
+
+// diff compares ms against a measurement's actual field and tag keys.
+func (ms MeasurementSchema) diff(fieldKeys []FieldKey, tagKeys []string) []SchemaDrift {
+	var drift []SchemaDrift
+
+	for _, fk := range fieldKeys {
+		if stringsContain(ms.Tags, fk.Name) {
+			drift = append(drift, SchemaDrift{Measurement: ms.Measurement, Kind: DriftTagBecameField, Name: fk.Name})
+			continue
+		}
+		wantKind, ok := ms.Fields[fk.Name]
+		if !ok {
+			drift = append(drift, SchemaDrift{Measurement: ms.Measurement, Kind: DriftUnexpectedField, Name: fk.Name, Got: fk.Type})
+			continue
+		}
+		if wantType, ok := goKindFieldType(wantKind); ok && wantType != fk.Type {
+			drift = append(drift, SchemaDrift{Measurement: ms.Measurement, Kind: DriftFieldTypeConflict, Name: fk.Name, Want: wantType, Got: fk.Type})
+		}
+	}
+
+	for _, tag := range tagKeys {
+		if stringsContain(ms.Tags, tag) {
+			continue
+		}
+		if _, ok := ms.Fields[tag]; ok {
+			drift = append(drift, SchemaDrift{Measurement: ms.Measurement, Kind: DriftFieldBecameTag, Name: tag})
+			continue
+		}
+		drift = append(drift, SchemaDrift{Measurement: ms.Measurement, Kind: DriftUnexpectedTag, Name: tag})
+	}
+
+	return drift
+}
+
+func stringsContain(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// goKindFieldType maps a struct field's reflect.Kind to the
+// InfluxDB field type ToPoint would write it as, for comparison
+// against SHOW FIELD KEYS. ok is false for a Kind ToPoint doesn't
+// write as a plain scalar field (e.g. a slice), which CheckDrift
+// can't validate the type of and so skips.
+func goKindFieldType(kind reflect.Kind) (typ FieldDataType, ok bool) {
+	switch kind {
+	case reflect.Float32, reflect.Float64:
+		return FieldDataTypeFloat, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return FieldDataTypeInteger, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return FieldDataTypeUnsigned, true
+	case reflect.Bool:
+		return FieldDataTypeBoolean, true
+	case reflect.String:
+		return FieldDataTypeString, true
+	default:
+		return "", false
+	}
+}
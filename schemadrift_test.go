@@ -0,0 +1,193 @@
+package influx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+func driftMS() MeasurementSchema {
+	return MeasurementSchema{
+		Measurement: "cpu",
+		Database:    "mydb",
+		Tags:        []string{"host"},
+		Fields: map[string]reflect.Kind{
+			"value": reflect.Float64,
+			"count": reflect.Int64,
+		},
+	}
+}
+
+// TestDiffNoDriftWhenServerMatchesSchema confirms a server shape that
+// matches the schema exactly reports no drift.
+func TestDiffNoDriftWhenServerMatchesSchema(t *testing.T) {
+	ms := driftMS()
+	drift := ms.diff(
+		[]FieldKey{{Name: "value", Type: FieldDataTypeFloat}, {Name: "count", Type: FieldDataTypeInteger}},
+		[]string{"host"},
+	)
+	if len(drift) != 0 {
+		t.Fatalf("drift = %v, want none", drift)
+	}
+}
+
+// TestDiffDetectsFieldTypeConflict confirms a field whose server type
+// disagrees with the schema's declared Go type is reported.
+func TestDiffDetectsFieldTypeConflict(t *testing.T) {
+	ms := driftMS()
+	drift := ms.diff(
+		[]FieldKey{{Name: "value", Type: FieldDataTypeString}, {Name: "count", Type: FieldDataTypeInteger}},
+		[]string{"host"},
+	)
+	if len(drift) != 1 || drift[0].Kind != DriftFieldTypeConflict || drift[0].Name != "value" {
+		t.Fatalf("drift = %v", drift)
+	}
+	if drift[0].Want != FieldDataTypeFloat || drift[0].Got != FieldDataTypeString {
+		t.Fatalf("drift[0] = %+v", drift[0])
+	}
+}
+
+// TestDiffDetectsTagBecameField confirms a name declared as a tag
+// that the server reports as a field is flagged.
+func TestDiffDetectsTagBecameField(t *testing.T) {
+	ms := driftMS()
+	drift := ms.diff(
+		[]FieldKey{{Name: "value", Type: FieldDataTypeFloat}, {Name: "count", Type: FieldDataTypeInteger}, {Name: "host", Type: FieldDataTypeString}},
+		nil,
+	)
+	if len(drift) != 1 || drift[0].Kind != DriftTagBecameField || drift[0].Name != "host" {
+		t.Fatalf("drift = %v", drift)
+	}
+}
+
+// TestDiffDetectsFieldBecameTag confirms a name declared as a field
+// that the server reports as a tag is flagged.
+func TestDiffDetectsFieldBecameTag(t *testing.T) {
+	ms := driftMS()
+	drift := ms.diff(
+		[]FieldKey{{Name: "count", Type: FieldDataTypeInteger}},
+		[]string{"host", "value"},
+	)
+	if len(drift) != 1 || drift[0].Kind != DriftFieldBecameTag || drift[0].Name != "value" {
+		t.Fatalf("drift = %v", drift)
+	}
+}
+
+// TestDiffDetectsUnexpectedFieldAndTag confirms columns the server has
+// that the schema never declared are reported.
+func TestDiffDetectsUnexpectedFieldAndTag(t *testing.T) {
+	ms := driftMS()
+	drift := ms.diff(
+		[]FieldKey{{Name: "value", Type: FieldDataTypeFloat}, {Name: "count", Type: FieldDataTypeInteger}, {Name: "extra", Type: FieldDataTypeBoolean}},
+		[]string{"host", "region"},
+	)
+	if len(drift) != 2 {
+		t.Fatalf("drift = %v, want 2 findings", drift)
+	}
+	var gotField, gotTag bool
+	for _, d := range drift {
+		if d.Kind == DriftUnexpectedField && d.Name == "extra" {
+			gotField = true
+		}
+		if d.Kind == DriftUnexpectedTag && d.Name == "region" {
+			gotTag = true
+		}
+	}
+	if !gotField || !gotTag {
+		t.Fatalf("drift = %v, want unexpected_field extra and unexpected_tag region", drift)
+	}
+}
+
+// TestValidateSchemaDetectsFieldTypeConflict drives ValidateSchema
+// against an httptest server standing in for SHOW FIELD KEYS/SHOW TAG
+// KEYS, confirming it derives the MeasurementSchema straight from v
+// without requiring a Schema/Register round trip first.
+func TestValidateSchemaDetectsFieldTypeConflict(t *testing.T) {
+	type cpu struct {
+		Host  string  `inf:"host,tag"`
+		Value float64 `inf:"value"`
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cmd := r.URL.Query().Get("q")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasPrefix(cmd, "SHOW FIELD KEYS"):
+			json.NewEncoder(w).Encode(seriesResponse("cpu", []string{"fieldKey", "fieldType"}, [][]interface{}{{"value", "string"}}))
+		case strings.HasPrefix(cmd, "SHOW TAG KEYS"):
+			json.NewEncoder(w).Encode(seriesResponse("cpu", []string{"tagKey"}, [][]interface{}{{"host"}}))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	drift, err := c.ValidateSchema(context.Background(), "mydb", cpu{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drift) != 1 || drift[0].Kind != DriftFieldTypeConflict || drift[0].Name != "value" {
+		t.Fatalf("drift = %v", drift)
+	}
+	if drift[0].Want != FieldDataTypeFloat || drift[0].Got != FieldDataTypeString {
+		t.Fatalf("drift[0] = %+v", drift[0])
+	}
+}
+
+// TestValidateSchemaNoDriftWhenServerMatches confirms a server shape
+// matching v's fields and tags reports no drift.
+func TestValidateSchemaNoDriftWhenServerMatches(t *testing.T) {
+	type cpu struct {
+		Host  string  `inf:"host,tag"`
+		Value float64 `inf:"value"`
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cmd := r.URL.Query().Get("q")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasPrefix(cmd, "SHOW FIELD KEYS"):
+			json.NewEncoder(w).Encode(seriesResponse("cpu", []string{"fieldKey", "fieldType"}, [][]interface{}{{"value", "float"}}))
+		case strings.HasPrefix(cmd, "SHOW TAG KEYS"):
+			json.NewEncoder(w).Encode(seriesResponse("cpu", []string{"tagKey"}, [][]interface{}{{"host"}}))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	drift, err := c.ValidateSchema(context.Background(), "mydb", &cpu{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drift) != 0 {
+		t.Fatalf("drift = %v, want none", drift)
+	}
+}
+
+// TestDiffSkipsFieldsNotYetWritten confirms a declared field/tag the
+// server hasn't seen any data for yet (absent from both SHOW FIELD
+// KEYS and SHOW TAG KEYS) isn't reported as drift.
+func TestDiffSkipsFieldsNotYetWritten(t *testing.T) {
+	ms := driftMS()
+	drift := ms.diff(nil, nil)
+	if len(drift) != 0 {
+		t.Fatalf("drift = %v, want none", drift)
+	}
+}
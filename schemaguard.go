@@ -0,0 +1,284 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// DefaultSchemaValidationTTL is the default SchemaValidationConfig.TTL.
+const DefaultSchemaValidationTTL = time.Minute
+
+// SchemaValidationPolicy selects what a schema-validated write does
+// with a point whose field type conflicts with the server's cached
+// SHOW FIELD KEYS type for that field.
+type SchemaValidationPolicy int
+
+// SchemaValidationPolicies EnableSchemaValidation accepts.
+const (
+	// SchemaValidationReject fails the write with an error identifying
+	// the conflicting field, the zero value: a type conflict usually
+	// means the caller's code has a bug worth surfacing, not silently
+	// masking.
+	SchemaValidationReject SchemaValidationPolicy = iota
+	// SchemaValidationCoerce converts the point's field to the server's
+	// type when a lossless-ish numeric conversion exists (e.g. an int64
+	// field to float64), and fails the write only when it doesn't (e.g.
+	// a string field can't become a number).
+	SchemaValidationCoerce
+)
+
+// SchemaValidationConfig configures EnableSchemaValidation.
+type SchemaValidationConfig struct {
+	// Policy is what to do with a field type conflict.
+	Policy SchemaValidationPolicy
+	// TTL is how long a measurement's cached field types are trusted
+	// before the next write to it re-fetches them with SHOW FIELD KEYS.
+	// Defaults to DefaultSchemaValidationTTL.
+	TTL time.Duration
+}
+
+// schemaCacheEntry is one measurement's cached field types.
+type schemaCacheEntry struct {
+	types     map[string]FieldDataType
+	expiresAt time.Time
+}
+
+// schemaGuard backs EnableSchemaValidation: it caches each
+// measurement's field types from SHOW FIELD KEYS and validates
+// outgoing points against them, catching a field type conflict (and
+// rejecting or coercing it, per cfg.Policy) before InfluxDB's own write
+// path would otherwise reject the whole batch, or silently widen the
+// field's stored type. It guards its cache with its own mutex,
+// independently of any Client.
+type schemaGuard struct {
+	cfg SchemaValidationConfig
+
+	mu      sync.Mutex
+	entries map[string]*schemaCacheEntry // measurement -> entry
+}
+
+func newSchemaGuard(cfg SchemaValidationConfig) *schemaGuard {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultSchemaValidationTTL
+	}
+	return &schemaGuard{cfg: cfg, entries: make(map[string]*schemaCacheEntry)}
+}
+
+// fieldTypes returns measurement's cached field types, fetching them
+// with SHOW FIELD KEYS if the cache is empty or has expired.
+func (g *schemaGuard) fieldTypes(ctx context.Context, c *Client, db, measurement string) (map[string]FieldDataType, error) {
+	g.mu.Lock()
+	entry := g.entries[measurement]
+	if entry != nil && clockNow().Before(entry.expiresAt) {
+		types := entry.types
+		g.mu.Unlock()
+		return types, nil
+	}
+	g.mu.Unlock()
+
+	keys, err := c.ShowFieldKeysContext(ctx, db, measurement)
+	if err != nil {
+		return nil, err
+	}
+	types := make(map[string]FieldDataType, len(keys))
+	for _, k := range keys {
+		types[k.Name] = k.Type
+	}
+
+	g.mu.Lock()
+	g.entries[measurement] = &schemaCacheEntry{types: types, expiresAt: clockNow().Add(g.cfg.TTL)}
+	g.mu.Unlock()
+	return types, nil
+}
+
+// invalidate drops measurement's cached field types, or every
+// measurement's if measurement is "".
+func (g *schemaGuard) invalidate(measurement string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if measurement == "" {
+		g.entries = make(map[string]*schemaCacheEntry)
+		return
+	}
+	delete(g.entries, measurement)
+}
+
+// validate applies g's policy to bp's points, rebuilding bp only if at
+// least one point's fields actually changed under SchemaValidationCoerce.
+func (g *schemaGuard) validate(ctx context.Context, c *Client, bp client.BatchPoints) (client.BatchPoints, error) {
+	var changed bool
+	points := make([]*client.Point, 0, len(bp.Points()))
+	for _, p := range bp.Points() {
+		types, err := g.fieldTypes(ctx, c, bp.Database(), p.Name())
+		if err != nil {
+			return nil, err
+		}
+		if len(types) == 0 {
+			// No series written for this measurement yet: nothing to
+			// conflict with.
+			points = append(points, p)
+			continue
+		}
+
+		fields, err := p.Fields()
+		if err != nil {
+			return nil, err
+		}
+
+		var mutated bool
+		for name, v := range fields {
+			want, ok := types[name]
+			if !ok {
+				continue
+			}
+			got, ok := goValueFieldType(v)
+			if !ok || got == want {
+				continue
+			}
+			if g.cfg.Policy != SchemaValidationCoerce {
+				return nil, fmt.Errorf("influx: field %q on %q is %s on the server, point has %s", name, p.Name(), want, got)
+			}
+			coerced, ok := coerceFieldValue(v, want)
+			if !ok {
+				return nil, fmt.Errorf("influx: field %q on %q is %s on the server, cannot coerce %T to it", name, p.Name(), want, v)
+			}
+			fields[name] = coerced
+			mutated = true
+		}
+
+		if !mutated {
+			points = append(points, p)
+			continue
+		}
+		newP, err := client.NewPoint(p.Name(), p.Tags(), fields, p.Time())
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, newP)
+		changed = true
+	}
+	if !changed {
+		return bp, nil
+	}
+
+	newBP, err := client.NewBatchPoints(client.BatchPointsConfig{
+		Database:         bp.Database(),
+		Precision:        bp.Precision(),
+		RetentionPolicy:  bp.RetentionPolicy(),
+		WriteConsistency: bp.WriteConsistency(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	newBP.AddPoints(points)
+	return newBP, nil
+}
+
+// goValueFieldType maps a field's decoded Go value (one of the types
+// client.Point.Fields can return) to the FieldDataType InfluxDB would
+// store it as, the value-based counterpart to goKindFieldType.
+func goValueFieldType(v interface{}) (typ FieldDataType, ok bool) {
+	switch v.(type) {
+	case float32, float64:
+		return FieldDataTypeFloat, true
+	case int, int8, int16, int32, int64:
+		return FieldDataTypeInteger, true
+	case uint, uint8, uint16, uint32, uint64:
+		return FieldDataTypeUnsigned, true
+	case bool:
+		return FieldDataTypeBoolean, true
+	case string:
+		return FieldDataTypeString, true
+	default:
+		return "", false
+	}
+}
+
+// coerceFieldValue converts v, a field's current Go value, to want's
+// Go representation, if a lossless-ish numeric conversion exists; ok is
+// false when no reasonable conversion applies (e.g. a string field
+// can't become a number, and a boolean or string target never accepts
+// a coercion since a widened/narrowed number is the only case worth
+// silently allowing).
+func coerceFieldValue(v interface{}, want FieldDataType) (coerced interface{}, ok bool) {
+	switch want {
+	case FieldDataTypeFloat:
+		switch n := v.(type) {
+		case int64:
+			return float64(n), true
+		case uint64:
+			return float64(n), true
+		}
+	case FieldDataTypeInteger:
+		switch n := v.(type) {
+		case float64:
+			return int64(n), true
+		case uint64:
+			return int64(n), true
+		}
+	case FieldDataTypeUnsigned:
+		switch n := v.(type) {
+		case float64:
+			return uint64(n), true
+		case int64:
+			if n >= 0 {
+				return uint64(n), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// EnableSchemaValidation turns on pre-write validation of outgoing
+// points against their measurement's actual field types, cached from
+// SHOW FIELD KEYS per cfg.TTL: WriteBatchPointsContext then rejects or
+// coerces (per cfg.Policy) any point whose field type conflicts with
+// what's already on the server, before a type conflict either fails
+// InfluxDB's own write or, for a type it tolerates across points in the
+// same batch, poisons the shard with a mismatched field going forward.
+// Call EnableSchemaValidation again to replace the config, discarding
+// the existing cache; call DisableSchemaValidation to turn it back off.
+func (c *Client) EnableSchemaValidation(cfg SchemaValidationConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schemaGuard = newSchemaGuard(cfg)
+}
+
+// DisableSchemaValidation turns pre-write schema validation back off;
+// WriteBatchPointsContext then writes every point unvalidated.
+func (c *Client) DisableSchemaValidation() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schemaGuard = nil
+}
+
+// InvalidateSchemaCache drops measurement's cached field types (every
+// measurement's, if measurement is ""), so the next write to it
+// re-fetches with SHOW FIELD KEYS instead of trusting a cache that may
+// now be stale, e.g. right after a CheckDrift-reported change. It is a
+// no-op if schema validation is disabled.
+func (c *Client) InvalidateSchemaCache(measurement string) {
+	c.mu.RLock()
+	g := c.schemaGuard
+	c.mu.RUnlock()
+	if g == nil {
+		return
+	}
+	g.invalidate(measurement)
+}
+
+// applySchemaValidation validates bp's points against c.schemaGuard, if
+// schema validation is enabled.
+func (c *Client) applySchemaValidation(ctx context.Context, bp client.BatchPoints) (client.BatchPoints, error) {
+	c.mu.RLock()
+	g := c.schemaGuard
+	c.mu.RUnlock()
+	if g == nil {
+		return bp, nil
+	}
+	return g.validate(ctx, c, bp)
+}
@@ -0,0 +1,127 @@
+package influx
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// DeleteSeries runs DELETE FROM measurement WHERE where AND timeRange
+// on db, deleting points without dropping the series' metadata. where
+// and timeRange are raw InfluxQL predicates (e.g. built with Ident/
+// QuoteString for where, Last/Since/Between for timeRange); either may
+// be "" to omit its half of the WHERE clause, but timeRange itself is
+// required — an accidental "DELETE FROM measurement" with no time bound
+// wipes every point ever written to it. Use DeleteSeriesAll when that
+// really is the intent.
+func (c *Client) DeleteSeries(db, measurement, where, timeRange string) error {
+	return c.DeleteSeriesContext(context.Background(), db, measurement, where, timeRange)
+}
+
+// DeleteSeriesContext is DeleteSeries with a context that aborts the
+// request as soon as it is canceled.
+func (c *Client) DeleteSeriesContext(ctx context.Context, db, measurement, where, timeRange string) error {
+	if timeRange == "" {
+		return errors.New("influx: DeleteSeries requires an explicit timeRange to avoid deleting every point in measurement; use DeleteSeriesAll to delete without one")
+	}
+	return c.runSeriesCmd(ctx, db, seriesCmd("DELETE", measurement, where, timeRange))
+}
+
+// DeleteSeriesAll is DeleteSeries without DeleteSeries' time range
+// requirement, for the rare case where deleting every point in
+// measurement really is the intent.
+func (c *Client) DeleteSeriesAll(db, measurement, where string) error {
+	return c.DeleteSeriesAllContext(context.Background(), db, measurement, where)
+}
+
+// DeleteSeriesAllContext is DeleteSeriesAll with a context that aborts
+// the request as soon as it is canceled.
+func (c *Client) DeleteSeriesAllContext(ctx context.Context, db, measurement, where string) error {
+	return c.runSeriesCmd(ctx, db, seriesCmd("DELETE", measurement, where, ""))
+}
+
+// DropSeries runs DROP SERIES FROM measurement WHERE where on db,
+// dropping the series themselves (points and tag metadata both), not
+// just their points. where is required — an accidental "DROP SERIES
+// FROM measurement" with no WHERE drops every series in measurement.
+// Use DropSeriesAll when that really is the intent.
+func (c *Client) DropSeries(db, measurement, where string) error {
+	return c.DropSeriesContext(context.Background(), db, measurement, where)
+}
+
+// DropSeriesContext is DropSeries with a context that aborts the
+// request as soon as it is canceled.
+func (c *Client) DropSeriesContext(ctx context.Context, db, measurement, where string) error {
+	if where == "" {
+		return errors.New("influx: DropSeries requires an explicit where to avoid dropping every series in measurement; use DropSeriesAll to drop without one")
+	}
+	return c.runSeriesCmd(ctx, db, seriesCmd("DROP", measurement, where, ""))
+}
+
+// DropSeriesAll is DropSeries without DropSeries' where requirement,
+// for the rare case where dropping every series in measurement really
+// is the intent. measurement itself may also be "" to drop every
+// series in db outright.
+func (c *Client) DropSeriesAll(db, measurement string) error {
+	return c.DropSeriesAllContext(context.Background(), db, measurement)
+}
+
+// DropSeriesAllContext is DropSeriesAll with a context that aborts the
+// request as soon as it is canceled.
+func (c *Client) DropSeriesAllContext(ctx context.Context, db, measurement string) error {
+	return c.runSeriesCmd(ctx, db, seriesCmd("DROP", measurement, "", ""))
+}
+
+// seriesCmd builds the DELETE/DROP SERIES statement DeleteSeries and
+// DropSeries both send, ANDing where and timeRange together into a
+// single WHERE clause when both are given.
+func seriesCmd(verb, measurement, where, timeRange string) string {
+	var cmd string
+	if verb == "DELETE" {
+		cmd = "DELETE"
+	} else {
+		cmd = "DROP SERIES"
+	}
+	if measurement != "" {
+		cmd += " FROM " + Ident(measurement)
+	}
+
+	var conds []string
+	if where != "" {
+		conds = append(conds, where)
+	}
+	if timeRange != "" {
+		conds = append(conds, timeRange)
+	}
+	if len(conds) > 0 {
+		cmd += " WHERE " + strings.Join(conds, " AND ")
+	}
+	return cmd
+}
+
+func (c *Client) runSeriesCmd(ctx context.Context, db, cmd string) error {
+	_, err := c.QueryContext(ctx, db, cmd)
+	return err
+}
+
+// DeleteSeries deletes points using the default Client.
+func DeleteSeries(db, measurement, where, timeRange string) error {
+	return gClient().DeleteSeries(db, measurement, where, timeRange)
+}
+
+// DeleteSeriesAll deletes points without a time range using the
+// default Client.
+func DeleteSeriesAll(db, measurement, where string) error {
+	return gClient().DeleteSeriesAll(db, measurement, where)
+}
+
+// DropSeries drops series using the default Client.
+func DropSeries(db, measurement, where string) error {
+	return gClient().DropSeries(db, measurement, where)
+}
+
+// DropSeriesAll drops series without a where clause using the default
+// Client.
+func DropSeriesAll(db, measurement string) error {
+	return gClient().DropSeriesAll(db, measurement)
+}
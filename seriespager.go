@@ -0,0 +1,108 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// SeriesPager walks a GROUP BY query's result series page by page,
+// driving SLIMIT/SOFFSET across successive requests, so a UI listing
+// thousands of series doesn't have to fetch them all in one response.
+// cmd must not already set SLIMIT/SOFFSET; SeriesPager appends its own.
+type SeriesPager struct {
+	c        *Client
+	ctx      context.Context
+	db       string
+	cmd      string
+	pageSize int
+
+	offset   int
+	page     []models.Row
+	index    int
+	lastPage bool
+	done     bool
+	err      error
+}
+
+// PageSeries pages through cmd's series pageSize at a time, using the
+// default Client.
+func PageSeries(db, cmd string, pageSize int) *SeriesPager {
+	return gClient().PageSeries(db, cmd, pageSize)
+}
+
+// PageSeriesContext is PageSeries with a context, using the default
+// Client.
+func PageSeriesContext(ctx context.Context, db, cmd string, pageSize int) *SeriesPager {
+	return gClient().PageSeriesContext(ctx, db, cmd, pageSize)
+}
+
+// PageSeries pages through cmd's series pageSize at a time.
+func (c *Client) PageSeries(db, cmd string, pageSize int) *SeriesPager {
+	return c.PageSeriesContext(context.Background(), db, cmd, pageSize)
+}
+
+// PageSeriesContext is PageSeries with a context that aborts a page
+// fetch as soon as it is canceled.
+func (c *Client) PageSeriesContext(ctx context.Context, db, cmd string, pageSize int) *SeriesPager {
+	return &SeriesPager{c: c, ctx: ctx, db: db, cmd: cmd, pageSize: pageSize}
+}
+
+// Next advances to the next series, transparently fetching the next
+// SLIMIT/SOFFSET page once the current one is exhausted, and returns
+// false once every series has been returned or a terminal error is set
+// (see Err). Call Series to read the series Next just advanced to.
+func (p *SeriesPager) Next() bool {
+	if p.done || p.err != nil {
+		return false
+	}
+	if p.index >= len(p.page) {
+		if p.lastPage {
+			p.done = true
+			return false
+		}
+		page, err := p.fetchPage()
+		if err != nil {
+			p.err = err
+			p.done = true
+			return false
+		}
+		p.page = page
+		p.index = 0
+		p.offset += p.pageSize
+		p.lastPage = len(page) < p.pageSize
+		if len(page) == 0 {
+			p.done = true
+			return false
+		}
+	}
+	p.index++
+	return true
+}
+
+// Series returns the series Next just advanced to.
+func (p *SeriesPager) Series() models.Row {
+	return p.page[p.index-1]
+}
+
+// Err returns the first terminal error encountered fetching a page, or
+// nil if iteration simply ran out of series.
+func (p *SeriesPager) Err() error {
+	return p.err
+}
+
+func (p *SeriesPager) fetchPage() ([]models.Row, error) {
+	cmd := p.cmd + fmt.Sprintf(" SLIMIT %d SOFFSET %d", p.pageSize, p.offset)
+	results, err := p.c.QueryContext(p.ctx, p.db, cmd)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+	if results[0].Err != "" {
+		return nil, fmt.Errorf("influx: %s", results[0].Err)
+	}
+	return results[0].Series, nil
+}
@@ -0,0 +1,176 @@
+package influx
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// ServerWriteStats is SHOW STATS' "write" series, one row per node.
+type ServerWriteStats struct {
+	PointReq   int64 `inf:"pointReq"`
+	WriteReq   int64 `inf:"writeReq"`
+	WriteOk    int64 `inf:"writeOk"`
+	WriteError int64 `inf:"writeError"`
+	WriteDrop  int64 `inf:"writeDrop"`
+}
+
+// ServerDatabaseStats is SHOW STATS' "database" series, one row per
+// database, Database coming from its "database" tag.
+type ServerDatabaseStats struct {
+	Database        string `inf:"database"`
+	NumMeasurements int64  `inf:"numMeasurements"`
+	NumSeries       int64  `inf:"numSeries"`
+}
+
+// ServerRuntimeStats is SHOW STATS' "runtime" series: Go's own runtime
+// counters (allocated memory, goroutine count, GC cycles), the same
+// ones runtime.ReadMemStats and runtime.NumGoroutine report for the
+// InfluxDB server's process.
+type ServerRuntimeStats struct {
+	Alloc        int64 `inf:"Alloc"`
+	HeapAlloc    int64 `inf:"HeapAlloc"`
+	TotalAlloc   int64 `inf:"TotalAlloc"`
+	Sys          int64 `inf:"Sys"`
+	NumGC        int64 `inf:"NumGC"`
+	NumGoroutine int64 `inf:"NumGoroutine"`
+}
+
+// ServerStats is the decoded result of SHOW STATS, as returned by
+// ShowStats. Write, Database and Runtime hold the series dashboards
+// check most often, decoded into a typed row each; Raw holds every
+// series SHOW STATS returned exactly as InfluxDB sent it (serie.Name
+// is the category, e.g. "httpd" or "shard"), so a category this
+// package doesn't special-case is still reachable.
+type ServerStats struct {
+	Write    []ServerWriteStats
+	Database []ServerDatabaseStats
+	Runtime  []ServerRuntimeStats
+	Raw      []models.Row
+}
+
+// ShowStats runs SHOW STATS and decodes its "write", "database" and
+// "runtime" series into ServerStats' typed fields, enabling
+// self-monitoring of the InfluxDB server (write throughput, series
+// count, memory/GC pressure) without the application standing up a
+// separate metrics pipeline against the server itself.
+func (c *Client) ShowStats() (*ServerStats, error) {
+	return c.ShowStatsContext(context.Background())
+}
+
+// ShowStatsContext is ShowStats with a context that aborts the request
+// as soon as it is canceled.
+func (c *Client) ShowStatsContext(ctx context.Context) (*ServerStats, error) {
+	results, err := c.QueryContext(ctx, "", "SHOW STATS")
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ServerStats{}
+	for _, result := range results {
+		for _, serie := range result.Series {
+			stats.Raw = append(stats.Raw, serie)
+			switch serie.Name {
+			case "write":
+				var rows []ServerWriteStats
+				if err := ParseResult(&rows, serie); err != nil {
+					return nil, err
+				}
+				stats.Write = append(stats.Write, rows...)
+			case "database":
+				var rows []ServerDatabaseStats
+				if err := ParseResult(&rows, serie); err != nil {
+					return nil, err
+				}
+				stats.Database = append(stats.Database, rows...)
+			case "runtime":
+				var rows []ServerRuntimeStats
+				if err := ParseResult(&rows, serie); err != nil {
+					return nil, err
+				}
+				stats.Runtime = append(stats.Runtime, rows...)
+			}
+		}
+	}
+	return stats, nil
+}
+
+// SystemDiagnostics is SHOW DIAGNOSTICS' "system" series: the server
+// process' identity and uptime.
+type SystemDiagnostics struct {
+	PID        int64  `inf:"PID"`
+	OS         string `inf:"OS"`
+	Hostname   string `inf:"Hostname"`
+	Uptime     string `inf:"Uptime"`
+	ClusterID  string `inf:"ClusterID"`
+	ServerID   string `inf:"ServerID"`
+	GoVersion  string `inf:"GoVersion"`
+	GoMaxProcs int64  `inf:"GOMAXPROCS"`
+}
+
+// BuildDiagnostics is SHOW DIAGNOSTICS' "build" series: the InfluxDB
+// binary's version and build metadata.
+type BuildDiagnostics struct {
+	Version string `inf:"Version"`
+	Commit  string `inf:"Commit"`
+	Branch  string `inf:"Branch"`
+	Build   string `inf:"Build Time"`
+}
+
+// Diagnostics is the decoded result of SHOW DIAGNOSTICS, as returned
+// by ShowDiagnostics. System and Build hold the two series every
+// InfluxDB version reports; Raw holds every series exactly as InfluxDB
+// sent it, including ones this package doesn't special-case (e.g.
+// "network", "go-plugins").
+type Diagnostics struct {
+	System *SystemDiagnostics
+	Build  *BuildDiagnostics
+	Raw    []models.Row
+}
+
+// ShowDiagnostics runs SHOW DIAGNOSTICS and decodes its "system" and
+// "build" series into Diagnostics' typed fields.
+func (c *Client) ShowDiagnostics() (*Diagnostics, error) {
+	return c.ShowDiagnosticsContext(context.Background())
+}
+
+// ShowDiagnosticsContext is ShowDiagnostics with a context that aborts
+// the request as soon as it is canceled.
+func (c *Client) ShowDiagnosticsContext(ctx context.Context) (*Diagnostics, error) {
+	results, err := c.QueryContext(ctx, "", "SHOW DIAGNOSTICS")
+	if err != nil {
+		return nil, err
+	}
+
+	diag := &Diagnostics{}
+	for _, result := range results {
+		for _, serie := range result.Series {
+			diag.Raw = append(diag.Raw, serie)
+			switch serie.Name {
+			case "system":
+				var row SystemDiagnostics
+				if err := ParseResult(&row, serie); err != nil {
+					return nil, err
+				}
+				diag.System = &row
+			case "build":
+				var row BuildDiagnostics
+				if err := ParseResult(&row, serie); err != nil {
+					return nil, err
+				}
+				diag.Build = &row
+			}
+		}
+	}
+	return diag, nil
+}
+
+// ShowStats runs ShowStats using the default Client.
+func ShowStats() (*ServerStats, error) {
+	return gClient().ShowStats()
+}
+
+// ShowDiagnostics runs ShowDiagnostics using the default Client.
+func ShowDiagnostics() (*Diagnostics, error) {
+	return gClient().ShowDiagnostics()
+}
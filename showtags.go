@@ -0,0 +1,81 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ShowTagKeys runs SHOW TAG KEYS for measurement on db and returns every
+// tag key, covering one of the most common metadata queries behind a
+// UI's dropdowns without requiring a raw Query call and manual
+// models.Row decoding. where's conditions are ANDed onto the query's
+// own WHERE clause, the same as ShowTagValues, when given.
+func (c *Client) ShowTagKeys(db, measurement string, where ...string) ([]string, error) {
+	return c.ShowTagKeysContext(context.Background(), db, measurement, where...)
+}
+
+// ShowTagKeysContext is ShowTagKeys with a context that aborts the
+// request as soon as it is canceled.
+func (c *Client) ShowTagKeysContext(ctx context.Context, db, measurement string, where ...string) ([]string, error) {
+	cmd := fmt.Sprintf("SHOW TAG KEYS ON %s FROM %s", Ident(db), Ident(measurement))
+	if len(where) > 0 {
+		cmd += " WHERE " + strings.Join(where, " AND ")
+	}
+	results, err := c.QueryContext(ctx, db, cmd)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, result := range results {
+		for _, serie := range result.Series {
+			var rows []string
+			if err := ParseResult(&rows, serie, "tagKey"); err != nil {
+				return nil, err
+			}
+			keys = append(keys, rows...)
+		}
+	}
+	return keys, nil
+}
+
+// ShowTagValues runs SHOW TAG VALUES for measurement's key on db and
+// returns every value, ANDing where's conditions onto the query's own
+// WHERE clause (e.g. a time range or another tag's value) when given.
+func (c *Client) ShowTagValues(db, measurement, key string, where ...string) ([]string, error) {
+	return c.ShowTagValuesContext(context.Background(), db, measurement, key, where...)
+}
+
+// ShowTagValuesContext is ShowTagValues with a context that aborts the
+// request as soon as it is canceled.
+func (c *Client) ShowTagValuesContext(ctx context.Context, db, measurement, key string, where ...string) ([]string, error) {
+	cmd := fmt.Sprintf("SHOW TAG VALUES ON %s FROM %s WITH KEY = %s", Ident(db), Ident(measurement), Ident(key))
+	if len(where) > 0 {
+		cmd += " WHERE " + strings.Join(where, " AND ")
+	}
+	results, err := c.QueryContext(ctx, db, cmd)
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	for _, result := range results {
+		for _, serie := range result.Series {
+			var rows []string
+			if err := ParseResult(&rows, serie, "value"); err != nil {
+				return nil, err
+			}
+			values = append(values, rows...)
+		}
+	}
+	return values, nil
+}
+
+// ShowTagKeys runs SHOW TAG KEYS using the default Client.
+func ShowTagKeys(db, measurement string, where ...string) ([]string, error) {
+	return gClient().ShowTagKeys(db, measurement, where...)
+}
+
+// ShowTagValues runs SHOW TAG VALUES using the default Client.
+func ShowTagValues(db, measurement, key string, where ...string) ([]string, error) {
+	return gClient().ShowTagValues(db, measurement, key, where...)
+}
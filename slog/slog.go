@@ -0,0 +1,235 @@
+// Package slog is a log/slog.Handler that converts log records into
+// points and writes them through a Writer, so low-volume structured
+// events (a startup notice, a rare warning, a business event) can be
+// graphed alongside metrics instead of living only in a log stream.
+//
+// Level and, when set, a top-level "logger" attribute become tags.
+// An attr placed under a group named "tags" (via
+// logger.WithGroup("tags").Info(...) or slog.With(slog.Group("tags",
+// ...))) becomes a tag too, stringified; every other attr becomes a
+// field, keyed by its dot-joined group path, using its native numeric,
+// bool or string value. This isn't a general-purpose log shipper: it's
+// meant for a modest volume of structured events, not a firehose of
+// debug logs, so MessageSampler lets a caller drop the message field
+// (still recording level, tags and other fields) for whichever
+// fraction of records it doesn't want to pay to store as a field.
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// DefaultMeasurement is the measurement Handler writes points to when
+// Handler.Measurement is unset.
+const DefaultMeasurement = "log"
+
+// Writer is the subset of *influx.BufferedWriter a Handler needs.
+type Writer interface {
+	Write(point *client.Point) error
+}
+
+// Handler is a log/slog.Handler writing one point per log record
+// through Writer.
+type Handler struct {
+	Writer Writer
+
+	// Measurement is the measurement every point is written under.
+	// Defaults to DefaultMeasurement.
+	Measurement string
+	// Level sets the minimum level Enabled reports as loggable.
+	// Defaults to slog.LevelInfo.
+	Level slog.Leveler
+	// MessageSampler, if set, is called with every record to decide
+	// whether its message text is included as a field. A record whose
+	// sampler returns false is still written, with every tag and
+	// field except the message. Defaults to always including it.
+	MessageSampler func(r slog.Record) bool
+	// OnError, if set, is called when building or writing a record's
+	// point fails, instead of the error only reaching slog's own
+	// (frequently ignored) Handle return value.
+	OnError func(err error)
+
+	prefix      string
+	inTagsGroup bool
+	boundTags   map[string]string
+	boundFields map[string]interface{}
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	min := slog.LevelInfo
+	if h.Level != nil {
+		min = h.Level.Level()
+	}
+	return level >= min
+}
+
+// Handle implements slog.Handler.
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	tmp := h.clone()
+	r.Attrs(func(a slog.Attr) bool {
+		tmp.addAttr(a)
+		return true
+	})
+
+	tags := map[string]string{"level": r.Level.String()}
+	for k, v := range tmp.boundTags {
+		tags[k] = v
+	}
+
+	fields := make(map[string]interface{}, len(tmp.boundFields)+1)
+	for k, v := range tmp.boundFields {
+		fields[k] = v
+	}
+	if h.MessageSampler == nil || h.MessageSampler(r) {
+		fields["message"] = r.Message
+	}
+	if len(fields) == 0 {
+		fields["count"] = int64(1)
+	}
+
+	p, err := client.NewPoint(h.measurement(), tags, fields, r.Time)
+	if err != nil {
+		if h.OnError != nil {
+			h.OnError(err)
+		}
+		return err
+	}
+	if err := h.Writer.Write(p); err != nil {
+		if h.OnError != nil {
+			h.OnError(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := h.clone()
+	for _, a := range attrs {
+		c.addAttr(a)
+	}
+	return c
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	c := h.clone()
+	if name == "tags" {
+		c.inTagsGroup = true
+	} else if !c.inTagsGroup {
+		c.prefix = h.key(name)
+	}
+	return c
+}
+
+func (h *Handler) clone() *Handler {
+	c := *h
+	c.boundTags = make(map[string]string, len(h.boundTags))
+	for k, v := range h.boundTags {
+		c.boundTags[k] = v
+	}
+	c.boundFields = make(map[string]interface{}, len(h.boundFields))
+	for k, v := range h.boundFields {
+		c.boundFields[k] = v
+	}
+	return &c
+}
+
+// addAttr resolves a and files it under boundTags or boundFields. A
+// group-valued attr is flattened one level, its own key joining the
+// current prefix, rather than tracked as a nested group of its own —
+// WithGroup covers the common case of a handler-wide prefix; an
+// inline slog.Group value is rarer and doesn't warrant recursing
+// through arbitrary nesting depth here. An inline group named "tags"
+// files its members as tags, the same as WithGroup("tags").
+func (h *Handler) addAttr(a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key == "tags" && !h.inTagsGroup {
+			h.inTagsGroup = true
+			for _, ga := range a.Value.Group() {
+				h.addFlatAttr(h.prefix, ga)
+			}
+			h.inTagsGroup = false
+			return
+		}
+		prefix := h.key(a.Key)
+		for _, ga := range a.Value.Group() {
+			h.addFlatAttr(prefix, ga)
+		}
+		return
+	}
+	h.addFlatAttr(h.prefix, a)
+}
+
+func (h *Handler) addFlatAttr(prefix string, a slog.Attr) {
+	key := a.Key
+	if prefix != "" {
+		key = prefix + "." + key
+	}
+	if h.inTagsGroup {
+		if h.boundTags == nil {
+			h.boundTags = map[string]string{}
+		}
+		h.boundTags[key] = a.Value.String()
+		return
+	}
+	if prefix == "" && key == "logger" && a.Value.Kind() == slog.KindString {
+		if h.boundTags == nil {
+			h.boundTags = map[string]string{}
+		}
+		h.boundTags[key] = a.Value.String()
+		return
+	}
+	if h.boundFields == nil {
+		h.boundFields = map[string]interface{}{}
+	}
+	h.boundFields[key] = attrValue(a.Value)
+}
+
+func (h *Handler) key(name string) string {
+	if h.prefix == "" {
+		return name
+	}
+	return h.prefix + "." + name
+}
+
+func (h *Handler) measurement() string {
+	if h.Measurement != "" {
+		return h.Measurement
+	}
+	return DefaultMeasurement
+}
+
+// attrValue converts v to a type client.NewPoint accepts as a field
+// value. A uint64 is passed through as-is rather than run through
+// this package's own overflow check for values above math.MaxInt64 —
+// ToPoint's SetUint64OverflowPolicy already covers that for
+// applications writing points both ways, and a log record's numeric
+// attrs are rarely large enough for it to matter.
+func attrValue(v slog.Value) interface{} {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindInt64:
+		return v.Int64()
+	case slog.KindUint64:
+		return v.Uint64()
+	case slog.KindFloat64:
+		return v.Float64()
+	case slog.KindBool:
+		return v.Bool()
+	case slog.KindDuration:
+		return v.Duration().Seconds()
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	default:
+		return v.String()
+	}
+}
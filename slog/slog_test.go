@@ -0,0 +1,139 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// fakeWriter records every point written to it, in place of a real
+// *influx.BufferedWriter.
+type fakeWriter struct {
+	mu     sync.Mutex
+	points []*client.Point
+}
+
+func (w *fakeWriter) Write(p *client.Point) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.points = append(w.points, p)
+	return nil
+}
+
+func (w *fakeWriter) last() *client.Point {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.points) == 0 {
+		return nil
+	}
+	return w.points[len(w.points)-1]
+}
+
+// TestHandleTagsLevelAndFieldsAttrs confirms the level is tagged and a
+// plain attr becomes a numeric field.
+func TestHandleTagsLevelAndFieldsAttrs(t *testing.T) {
+	w := &fakeWriter{}
+	logger := slog.New(&Handler{Writer: w})
+	logger.Info("started", "workers", 4)
+
+	p := w.last()
+	if p == nil {
+		t.Fatal("no point written")
+	}
+	if p.Tags()["level"] != "INFO" {
+		t.Fatalf("level tag = %q", p.Tags()["level"])
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["workers"] != int64(4) {
+		t.Fatalf("workers = %v, want int64(4)", fields["workers"])
+	}
+	if fields["message"] != "started" {
+		t.Fatalf("message = %v", fields["message"])
+	}
+}
+
+// TestHandleLoggerAttrBecomesTag confirms a top-level "logger" attr is
+// tagged instead of turned into a field.
+func TestHandleLoggerAttrBecomesTag(t *testing.T) {
+	w := &fakeWriter{}
+	logger := slog.New(&Handler{Writer: w}).With("logger", "db")
+	logger.Info("connected")
+
+	p := w.last()
+	if p.Tags()["logger"] != "db" {
+		t.Fatalf("logger tag = %q", p.Tags()["logger"])
+	}
+	fields, _ := p.Fields()
+	if _, ok := fields["logger"]; ok {
+		t.Fatal("logger should not also be a field")
+	}
+}
+
+// TestHandleTagsGroupBecomesTags confirms attrs under a "tags" group
+// are tagged, stringified.
+func TestHandleTagsGroupBecomesTags(t *testing.T) {
+	w := &fakeWriter{}
+	logger := slog.New(&Handler{Writer: w}).WithGroup("tags").With("tenant", "acme")
+	logger.Info("request handled")
+
+	p := w.last()
+	if p.Tags()["tenant"] != "acme" {
+		t.Fatalf("tags = %v", p.Tags())
+	}
+}
+
+// TestHandleGroupPrefixesFieldKeys confirms a non-"tags" group prefixes
+// its attrs' field keys.
+func TestHandleGroupPrefixesFieldKeys(t *testing.T) {
+	w := &fakeWriter{}
+	logger := slog.New(&Handler{Writer: w}).WithGroup("http").With("status", int64(200))
+	logger.Info("request")
+
+	fields, err := w.last().Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["http.status"] != int64(200) {
+		t.Fatalf("fields = %v", fields)
+	}
+}
+
+// TestHandleMessageSamplerDropsMessage confirms a MessageSampler
+// returning false omits the message field but still writes the point.
+func TestHandleMessageSamplerDropsMessage(t *testing.T) {
+	w := &fakeWriter{}
+	logger := slog.New(&Handler{
+		Writer:         w,
+		MessageSampler: func(r slog.Record) bool { return false },
+	})
+	logger.Info("noisy", "n", 1)
+
+	fields, err := w.last().Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fields["message"]; ok {
+		t.Fatal("message should have been sampled out")
+	}
+	if fields["n"] != int64(1) {
+		t.Fatalf("fields = %v", fields)
+	}
+}
+
+// TestEnabledRespectsLevel confirms a Handler filters below its
+// configured Level.
+func TestEnabledRespectsLevel(t *testing.T) {
+	h := &Handler{Writer: &fakeWriter{}, Level: slog.LevelWarn}
+	if h.Enabled(context.Background(), slog.LevelInfo) {
+		t.Fatal("Info should be disabled under LevelWarn")
+	}
+	if !h.Enabled(context.Background(), slog.LevelError) {
+		t.Fatal("Error should be enabled under LevelWarn")
+	}
+}
@@ -0,0 +1,64 @@
+package influx
+
+import (
+	"log"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// SlowQueryConfig configures slow-query reporting, enabled by
+// LogSlowQueries.
+type SlowQueryConfig struct {
+	// Threshold is the minimum query duration that counts as slow. A
+	// zero or negative Threshold disables reporting.
+	Threshold time.Duration
+
+	// OnSlowQuery, if non-nil, is called instead of the default
+	// log.Printf line for every query at or beyond Threshold, with its
+	// database, command, how long it took, and how many rows it
+	// returned across every series of every statement.
+	OnSlowQuery func(db, cmd string, duration time.Duration, rows int)
+}
+
+// LogSlowQueries reports every query against c that takes at least
+// cfg.Threshold — via log.Printf, or cfg.OnSlowQuery if set — with its
+// command, database, duration and row count, enough to pick the
+// dashboard panel query that's hurting the cluster out of a wall of
+// ordinary query traffic. Call with a zero SlowQueryConfig to disable
+// it.
+func (c *Client) LogSlowQueries(cfg SlowQueryConfig) {
+	if cfg.Threshold <= 0 {
+		c.slowQuery = nil
+		return
+	}
+	c.slowQuery = &cfg
+}
+
+// reportSlowQuery calls c.slowQuery's callback (or logs) if duration
+// is at or beyond its Threshold. The caller has already checked
+// c.slowQuery is non-nil.
+func (c *Client) reportSlowQuery(db, cmd string, duration time.Duration, results []client.Result) {
+	if duration < c.slowQuery.Threshold {
+		return
+	}
+
+	var rows int
+	for _, result := range results {
+		for _, serie := range result.Series {
+			rows += len(serie.Values)
+		}
+	}
+
+	if c.slowQuery.OnSlowQuery != nil {
+		c.slowQuery.OnSlowQuery(db, cmd, duration, rows)
+		return
+	}
+	log.Printf("influx: slow query (%s, %d rows) on %q: %s", duration, rows, db, cmd)
+}
+
+// LogSlowQueries configures slow-query reporting on the default
+// Client.
+func LogSlowQueries(cfg SlowQueryConfig) {
+	gClient().LogSlowQueries(cfg)
+}
@@ -0,0 +1,193 @@
+package influx
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/models"
+)
+
+// DefaultSpoolRetryInterval is the default SpoolConfig.RetryInterval.
+const DefaultSpoolRetryInterval = 30 * time.Second
+
+// SpoolConfig configures the on-disk write-ahead spool enabled by
+// Client.Spool.
+type SpoolConfig struct {
+	// Dir is the directory spooled batches are written to; created if
+	// missing.
+	Dir string
+	// MaxBytes bounds the total size of spooled files; once reached, a
+	// batch that fails to write is dropped instead of spooled. 0 means
+	// unbounded.
+	MaxBytes int64
+	// RetryInterval is how often the background goroutine replays
+	// spooled batches. Defaults to DefaultSpoolRetryInterval.
+	RetryInterval time.Duration
+}
+
+// spoolHeader is the first line of every spooled file, recording the
+// BatchPoints metadata that line protocol itself doesn't carry.
+type spoolHeader struct {
+	Database         string
+	Precision        string
+	RetentionPolicy  string
+	WriteConsistency string
+}
+
+// Spool enables a disk-backed write-ahead spool: whenever
+// WriteBatchPointsContext fails, the batch is also serialized to line
+// protocol under cfg.Dir, and a background goroutine replays spooled
+// files every cfg.RetryInterval, deleting each once it writes
+// successfully, so a short InfluxDB outage doesn't lose the points.
+// WriteBatchPointsContext still returns the original error to the
+// caller; spooling only affects whether the batch is retried later.
+//
+// Calling Spool again replaces the previous config and restarts the
+// background goroutine; Close stops it.
+func (c *Client) Spool(cfg SpoolConfig) error {
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = DefaultSpoolRetryInterval
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if c.spoolStop != nil {
+		close(c.spoolStop)
+	}
+	c.spoolCfg = &cfg
+	stop := make(chan struct{})
+	c.spoolStop = stop
+	c.mu.Unlock()
+
+	go c.spoolLoop(cfg, stop)
+	return nil
+}
+
+func (c *Client) spoolLoop(cfg SpoolConfig, stop chan struct{}) {
+	ticker := newTicker(cfg.RetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C():
+			c.replaySpool(cfg.Dir)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// spoolBatch serializes bp to cfg.Dir, dropping it if doing so would
+// push the spool past cfg.MaxBytes.
+func (c *Client) spoolBatch(cfg *SpoolConfig, bp client.BatchPoints) error {
+	header, err := json.Marshal(spoolHeader{
+		Database:         bp.Database(),
+		Precision:        bp.Precision(),
+		RetentionPolicy:  bp.RetentionPolicy(),
+		WriteConsistency: bp.WriteConsistency(),
+	})
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	body = append(body, header...)
+	body = append(body, '\n')
+	for _, p := range bp.Points() {
+		body = append(body, p.PrecisionString(bp.Precision())...)
+		body = append(body, '\n')
+	}
+
+	if cfg.MaxBytes > 0 && atomic.LoadInt64(&c.spoolSize)+int64(len(body)) > cfg.MaxBytes {
+		return fmt.Errorf("influx: spool at %s is full", cfg.Dir)
+	}
+
+	name := filepath.Join(cfg.Dir, fmt.Sprintf("%d.lp", clockNow().UnixNano()))
+	if err := os.WriteFile(name, body, 0o644); err != nil {
+		return err
+	}
+	atomic.AddInt64(&c.spoolSize, int64(len(body)))
+	return nil
+}
+
+// replaySpool attempts to write every file spooled under dir, oldest
+// first, deleting each one that writes successfully.
+func (c *Client) replaySpool(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if err := c.replaySpoolFile(path); err != nil {
+			return // oldest file still failing; try again next tick
+		}
+	}
+}
+
+func (c *Client) replaySpoolFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return err
+	}
+	var header spoolHeader
+	if err := json.Unmarshal(line, &header); err != nil {
+		return err
+	}
+
+	lineProtocol, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	points, err := models.ParsePointsWithPrecision(lineProtocol, clockNow(), header.Precision)
+	if err != nil {
+		return err
+	}
+
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{
+		Database:         header.Database,
+		Precision:        header.Precision,
+		RetentionPolicy:  header.RetentionPolicy,
+		WriteConsistency: header.WriteConsistency,
+	})
+	if err != nil {
+		return err
+	}
+	for _, pt := range points {
+		bp.AddPoint(client.NewPointFrom(pt))
+	}
+
+	if err := c.WriteBatchPointsContext(context.Background(), bp); err != nil {
+		return err
+	}
+
+	if info, serr := os.Stat(path); serr == nil {
+		atomic.AddInt64(&c.spoolSize, -info.Size())
+	}
+	return os.Remove(path)
+}
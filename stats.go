@@ -0,0 +1,104 @@
+package influx
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// WriteStats is a snapshot of a Client's write counters, returned by
+// Stats, meant for health endpoints.
+type WriteStats struct {
+	// PointsWritten and BytesWritten count points and line-protocol
+	// bytes in batches that WriteBatchPointsContext wrote successfully.
+	PointsWritten int64
+	BytesWritten  int64
+	// BatchesFlushed counts successful WriteBatchPointsContext calls.
+	BatchesFlushed int64
+	// Retries counts retry attempts made by WriteRetry, regardless of
+	// whether the retried write eventually succeeded.
+	Retries int64
+	// DroppedPoints counts points in batches that failed to write and,
+	// if Spool was enabled, also failed to spool.
+	DroppedPoints int64
+	// ConsecutiveFailures counts WriteBatchPointsContext calls that
+	// have failed since the last one that succeeded, reset to 0 by the
+	// next successful call.
+	ConsecutiveFailures int64
+	// LastError is the error of the most recent failed
+	// WriteBatchPointsContext call, or nil if none has failed yet.
+	LastError error
+	// LastFlushTime is when WriteBatchPointsContext last returned,
+	// successfully or not.
+	LastFlushTime time.Time
+}
+
+// Stats returns a snapshot of c's write counters.
+func (c *Client) Stats() WriteStats {
+	c.writeStats.mu.Lock()
+	lastErr, lastFlush := c.writeStats.lastErr, c.writeStats.lastFlush
+	c.writeStats.mu.Unlock()
+
+	return WriteStats{
+		PointsWritten:       atomic.LoadInt64(&c.writeStats.points),
+		BytesWritten:        atomic.LoadInt64(&c.writeStats.bytes),
+		BatchesFlushed:      atomic.LoadInt64(&c.writeStats.batches),
+		Retries:             atomic.LoadInt64(&c.writeStats.retries),
+		DroppedPoints:       atomic.LoadInt64(&c.writeStats.dropped),
+		ConsecutiveFailures: atomic.LoadInt64(&c.writeStats.consecutiveFailures),
+		LastError:           lastErr,
+		LastFlushTime:       lastFlush,
+	}
+}
+
+// recordWriteResult updates c's write counters after a
+// WriteBatchPointsContext call for bp finished with err.
+func (c *Client) recordWriteResult(bp client.BatchPoints, err error) {
+	c.writeStats.mu.Lock()
+	c.writeStats.lastFlush = clockNow()
+	if err != nil {
+		c.writeStats.lastErr = err
+	}
+	c.writeStats.mu.Unlock()
+
+	if err != nil {
+		atomic.AddInt64(&c.writeStats.consecutiveFailures, 1)
+		return
+	}
+	atomic.StoreInt64(&c.writeStats.consecutiveFailures, 0)
+	atomic.AddInt64(&c.writeStats.batches, 1)
+	atomic.AddInt64(&c.writeStats.points, int64(len(bp.Points())))
+	atomic.AddInt64(&c.writeStats.bytes, batchBytes(bp))
+}
+
+// recordDropped counts every point in bp as dropped: its write failed
+// and, if Spool was enabled, spooling it also failed.
+func (c *Client) recordDropped(bp client.BatchPoints) {
+	atomic.AddInt64(&c.writeStats.dropped, int64(len(bp.Points())))
+}
+
+// batchBytes estimates the line-protocol size of bp, for BytesWritten.
+func batchBytes(bp client.BatchPoints) int64 {
+	var n int64
+	for _, p := range bp.Points() {
+		n += int64(len(p.PrecisionString(bp.Precision()))) + 1
+	}
+	return n
+}
+
+// measurementSet returns the sorted set of distinct measurement names
+// written in bp, for RequestInfo.Measurements.
+func measurementSet(bp client.BatchPoints) []string {
+	seen := make(map[string]bool)
+	for _, p := range bp.Points() {
+		seen[p.Name()] = true
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
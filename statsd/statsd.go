@@ -0,0 +1,253 @@
+// Package statsd is a minimal StatsD server: a UDP Listener that
+// aggregates counters, gauges and timers over FlushInterval and writes
+// one summary point per stat through a Writer, so a small deployment
+// can point its StatsD client library straight at this process instead
+// of running a separate statsd-to-InfluxDB daemon alongside it.
+//
+// Only the "c" (counter), "g" (gauge) and "ms"/"h" (timer/histogram)
+// StatsD metric types are supported; sets ("s") and relative gauge
+// deltas ("gaugor:+5|g") are rejected as unrecognized instead of
+// silently misreported.
+package statsd
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// Tuning defaults for Listener.
+const (
+	DefaultFlushInterval = 10 * time.Second
+	DefaultMeasurement   = "statsd"
+)
+
+// Writer is the subset of *influx.BufferedWriter a Listener needs.
+type Writer interface {
+	Write(point *client.Point) error
+}
+
+// Listener aggregates StatsD metrics received over UDP and flushes
+// them through Writer once per FlushInterval. The zero value is ready
+// to use once Writer is set.
+type Listener struct {
+	Writer Writer
+
+	// FlushInterval is how often aggregated stats are written as
+	// points. Defaults to DefaultFlushInterval.
+	FlushInterval time.Duration
+	// Measurement is the measurement every point is written under, the
+	// stat name and metric type distinguishing one point from another
+	// as tags instead. Defaults to DefaultMeasurement.
+	Measurement string
+	// OnError, if set, is called with a malformed line (empty for a
+	// downstream Writer error) and the error that resulted, instead of
+	// silently dropping it.
+	OnError func(line string, err error)
+
+	mu       sync.Mutex
+	counters map[string]float64
+	gauges   map[string]float64
+	timers   map[string][]float64
+}
+
+// ListenAndServe listens for UDP packets on addr and serves them until
+// the listener is closed or a read fails.
+func (l *Listener) ListenAndServe(addr string) error {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return err
+	}
+	return l.Serve(conn)
+}
+
+// Serve reads StatsD packets off conn, aggregating them, until a read
+// fails (including conn being closed), starting a background flush
+// loop for the duration of the call.
+func (l *Listener) Serve(conn net.PacketConn) error {
+	defer conn.Close()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go l.flushLoop(stop)
+
+	buf := make([]byte, 65535)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+		l.handlePacket(string(buf[:n]))
+	}
+}
+
+func (l *Listener) flushLoop(stop chan struct{}) {
+	interval := l.FlushInterval
+	if interval <= 0 {
+		interval = DefaultFlushInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			l.Flush()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// handlePacket parses every newline-separated metric in data — a
+// single UDP packet commonly batches several — and aggregates each,
+// routing a malformed line to OnError instead of aborting the rest of
+// the packet.
+func (l *Listener) handlePacket(data string) {
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if err := l.handleLine(line); err != nil {
+			if l.OnError != nil {
+				l.OnError(line, err)
+			}
+		}
+	}
+}
+
+func (l *Listener) handleLine(line string) error {
+	name, value, typ, sampleRate, err := parseMetric(line)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch typ {
+	case "c":
+		if l.counters == nil {
+			l.counters = map[string]float64{}
+		}
+		l.counters[name] += value / sampleRate
+	case "g":
+		if l.gauges == nil {
+			l.gauges = map[string]float64{}
+		}
+		l.gauges[name] = value
+	case "ms", "h":
+		if l.timers == nil {
+			l.timers = map[string][]float64{}
+		}
+		l.timers[name] = append(l.timers[name], value)
+	default:
+		return fmt.Errorf("statsd: unsupported metric type %q", typ)
+	}
+	return nil
+}
+
+// parseMetric parses a single StatsD line: "name:value|type" with an
+// optional "|@sampleRate" suffix, e.g. "gorets:1|c|@0.1". sampleRate
+// is 1 when absent.
+func parseMetric(line string) (name string, value float64, typ string, sampleRate float64, err error) {
+	parts := strings.Split(line, "|")
+	if len(parts) < 2 {
+		return "", 0, "", 0, fmt.Errorf("statsd: malformed metric %q", line)
+	}
+
+	nameValue := strings.SplitN(parts[0], ":", 2)
+	if len(nameValue) != 2 {
+		return "", 0, "", 0, fmt.Errorf("statsd: malformed metric %q", line)
+	}
+	name, typ = nameValue[0], parts[1]
+
+	value, err = strconv.ParseFloat(nameValue[1], 64)
+	if err != nil {
+		return "", 0, "", 0, fmt.Errorf("statsd: malformed value in %q: %w", line, err)
+	}
+	if typ == "g" && (nameValue[1][0] == '+' || nameValue[1][0] == '-') {
+		return "", 0, "", 0, fmt.Errorf("statsd: relative gauge delta in %q is not supported", line)
+	}
+
+	sampleRate = 1
+	for _, p := range parts[2:] {
+		if strings.HasPrefix(p, "@") {
+			sampleRate, err = strconv.ParseFloat(p[1:], 64)
+			if err != nil || sampleRate <= 0 {
+				return "", 0, "", 0, fmt.Errorf("statsd: malformed sample rate in %q", line)
+			}
+		}
+	}
+	return name, value, typ, sampleRate, nil
+}
+
+// Flush writes one point per aggregated stat through Writer: a
+// "count" field for each counter, a "value" field for each gauge, and
+// count/sum/mean/min/max fields for each timer. Counters and timers
+// reset to zero afterward; gauges keep reporting their last value
+// until a new one arrives, the same persistence a real StatsD server
+// gives gauges.
+func (l *Listener) Flush() {
+	l.mu.Lock()
+	counters, gauges, timers := l.counters, l.gauges, l.timers
+	l.counters, l.timers = nil, nil
+	l.mu.Unlock()
+
+	now := time.Now()
+	measurement := l.measurement()
+	for name, count := range counters {
+		l.writePoint(measurement, name, "counter", map[string]interface{}{"count": count}, now)
+	}
+	for name, value := range gauges {
+		l.writePoint(measurement, name, "gauge", map[string]interface{}{"value": value}, now)
+	}
+	for name, samples := range timers {
+		l.writePoint(measurement, name, "timer", timerFields(samples), now)
+	}
+}
+
+func (l *Listener) measurement() string {
+	if l.Measurement != "" {
+		return l.Measurement
+	}
+	return DefaultMeasurement
+}
+
+func (l *Listener) writePoint(measurement, stat, typ string, fields map[string]interface{}, ts time.Time) {
+	p, err := client.NewPoint(measurement, map[string]string{"stat": stat, "type": typ}, fields, ts)
+	if err != nil {
+		if l.OnError != nil {
+			l.OnError("", err)
+		}
+		return
+	}
+	if err := l.Writer.Write(p); err != nil && l.OnError != nil {
+		l.OnError("", err)
+	}
+}
+
+func timerFields(samples []float64) map[string]interface{} {
+	count := len(samples)
+	sum, min, max := 0.0, samples[0], samples[0]
+	for _, s := range samples {
+		sum += s
+		if s < min {
+			min = s
+		}
+		if s > max {
+			max = s
+		}
+	}
+	return map[string]interface{}{
+		"count": count,
+		"sum":   sum,
+		"mean":  sum / float64(count),
+		"min":   min,
+		"max":   max,
+	}
+}
@@ -0,0 +1,191 @@
+package statsd
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// fakeWriter records every point written to it, in place of a real
+// *influx.BufferedWriter.
+type fakeWriter struct {
+	mu     sync.Mutex
+	points []*client.Point
+}
+
+func (w *fakeWriter) Write(p *client.Point) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.points = append(w.points, p)
+	return nil
+}
+
+func (w *fakeWriter) len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.points)
+}
+
+func (w *fakeWriter) byStat(stat string) *client.Point {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, p := range w.points {
+		if p.Tags()["stat"] == stat {
+			return p
+		}
+	}
+	return nil
+}
+
+// TestParseMetricCounter confirms a plain counter parses with a
+// default sample rate of 1.
+func TestParseMetricCounter(t *testing.T) {
+	name, value, typ, rate, err := parseMetric("gorets:1|c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "gorets" || value != 1 || typ != "c" || rate != 1 {
+		t.Fatalf("got %q %v %q %v", name, value, typ, rate)
+	}
+}
+
+// TestParseMetricSampleRate confirms a trailing "|@rate" is parsed as
+// the metric's sample rate.
+func TestParseMetricSampleRate(t *testing.T) {
+	_, _, _, rate, err := parseMetric("gorets:1|c|@0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 0.1 {
+		t.Fatalf("rate = %v, want 0.1", rate)
+	}
+}
+
+// TestParseMetricRejectsMalformed confirms a line missing a value or
+// type is rejected.
+func TestParseMetricRejectsMalformed(t *testing.T) {
+	if _, _, _, _, err := parseMetric("gorets"); err == nil {
+		t.Fatal("want error")
+	}
+	if _, _, _, _, err := parseMetric("gorets:notanumber|c"); err == nil {
+		t.Fatal("want error")
+	}
+}
+
+// TestFlushAggregatesCounter confirms two samples of the same counter
+// sum into one point's "count" field, and reset after Flush.
+func TestFlushAggregatesCounter(t *testing.T) {
+	w := &fakeWriter{}
+	l := &Listener{Writer: w}
+	l.handlePacket("gorets:1|c\ngorets:2|c\n")
+	l.Flush()
+
+	p := w.byStat("gorets")
+	if p == nil {
+		t.Fatal("no point written for gorets")
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["count"] != 3.0 {
+		t.Fatalf("count = %v, want 3", fields["count"])
+	}
+
+	w.points = nil
+	l.Flush()
+	if w.len() != 0 {
+		t.Fatalf("counter should reset after Flush, got %d points", w.len())
+	}
+}
+
+// TestFlushKeepsGaugeAcrossFlushes confirms a gauge keeps reporting
+// its last value on every Flush, unlike a counter or timer.
+func TestFlushKeepsGaugeAcrossFlushes(t *testing.T) {
+	w := &fakeWriter{}
+	l := &Listener{Writer: w}
+	l.handlePacket("gaugor:333|g\n")
+	l.Flush()
+	l.Flush()
+
+	if w.len() != 2 {
+		t.Fatalf("len(points) = %d, want 2 (one per Flush)", w.len())
+	}
+	fields, err := w.points[1].Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["value"] != 333.0 {
+		t.Fatalf("value = %v, want 333", fields["value"])
+	}
+}
+
+// TestFlushSummarizesTimer confirms a timer's samples reduce to
+// count/sum/mean/min/max fields.
+func TestFlushSummarizesTimer(t *testing.T) {
+	w := &fakeWriter{}
+	l := &Listener{Writer: w}
+	l.handlePacket("glork:10|ms\nglork:30|ms\n")
+	l.Flush()
+
+	p := w.byStat("glork")
+	if p == nil {
+		t.Fatal("no point written for glork")
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["count"] != int64(2) || fields["sum"] != 40.0 || fields["mean"] != 20.0 || fields["min"] != 10.0 || fields["max"] != 30.0 {
+		t.Fatalf("fields = %v", fields)
+	}
+}
+
+// TestServeAggregatesUDPPackets confirms a Listener reads UDP packets,
+// aggregates them and flushes through Writer on FlushInterval.
+func TestServeAggregatesUDPPackets(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := &fakeWriter{}
+	l := &Listener{Writer: w, FlushInterval: 20 * time.Millisecond}
+	go l.Serve(conn)
+	defer conn.Close()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if _, err := client.Write([]byte("gorets:1|c\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for w.len() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if w.len() == 0 {
+		t.Fatal("no point flushed")
+	}
+}
+
+// TestHandlePacketReportsParseErrors confirms a malformed line reaches
+// OnError instead of being silently dropped.
+func TestHandlePacketReportsParseErrors(t *testing.T) {
+	var got error
+	l := &Listener{
+		Writer:  &fakeWriter{},
+		OnError: func(line string, err error) { got = err },
+	}
+	l.handlePacket("not a valid line\n")
+	if got == nil {
+		t.Fatal("want OnError to be called with a non-nil error")
+	}
+}
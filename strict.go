@@ -0,0 +1,29 @@
+package influx
+
+import (
+	"sync/atomic"
+)
+
+// strictTagging controls whether buildTypePlan requires every mapped
+// field to declare its role (`,tag`, `,field`, `,flatten`, `,fields`,
+// `,tags`, the time field, or `-`) in its inf tag instead of defaulting
+// an untagged or bare-named field to a regular field, set by
+// SetStrictTagging. Off by default.
+var strictTagging int32
+
+// SetStrictTagging toggles strict role declaration for every type
+// ToPoint/ParseResult plans from here on: with it on, a field with no
+// inf tag, or a named one missing `,tag` or `,field`, makes ToPoint and
+// ParseResult fail instead of silently treating it as a regular field,
+// catching the common mistake of adding a new string field that should
+// have been a tag (or vice versa) before it quietly explodes series
+// cardinality or goes un-indexed. It drops every cached type plan so
+// already-seen types are replanned under the new setting.
+func SetStrictTagging(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&strictTagging, 1)
+	} else {
+		atomic.StoreInt32(&strictTagging, 0)
+	}
+	resetTypePlans()
+}
@@ -0,0 +1,42 @@
+package influx
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// strictDecoding controls whether alignToStruct requires a row's
+// columns and tags to line up 1:1 with its destination struct's
+// fields instead of silently skipping whichever side has no match, set
+// by SetStrictDecoding. Off by default.
+var strictDecoding int32
+
+// SetStrictDecoding toggles strict column/field alignment for
+// ParseResult from here on: with it on, a column (or tag) with no
+// matching field, or a field with no matching column or tag, makes
+// ParseResult fail with a *StrictDecodeError instead of silently
+// leaving the column unused or the field zero, catching an inf tag or
+// column-name typo immediately instead of letting it quietly decode
+// into the wrong (or a zero) field.
+func SetStrictDecoding(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&strictDecoding, 1)
+	} else {
+		atomic.StoreInt32(&strictDecoding, 0)
+	}
+}
+
+// StrictDecodeError is returned by ParseResult under SetStrictDecoding
+// when a row doesn't align 1:1 with its destination struct.
+type StrictDecodeError struct {
+	// UnmatchedColumns lists columns (and tags) the row carried that no
+	// struct field claimed.
+	UnmatchedColumns []string
+	// UnfilledFields lists struct fields that received no column or
+	// tag value from the row.
+	UnfilledFields []string
+}
+
+func (e *StrictDecodeError) Error() string {
+	return fmt.Sprintf("influx: unmatched columns %v, unfilled fields %v", e.UnmatchedColumns, e.UnfilledFields)
+}
@@ -0,0 +1,45 @@
+package influx
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// activeStructTagKey is the struct tag key collectField/addField,
+// buildBindPlan, planWhere and parseSchema read field names and
+// options from, set by SetStructTagKey. Boxed in an atomic.Value since
+// it holds a string, not a flag a single atomic int32 bool could hold.
+var activeStructTagKey atomic.Value // string
+
+func init() {
+	activeStructTagKey.Store("inf")
+}
+
+// SetStructTagKey replaces the struct tag key ToPoint, ParseResult,
+// QueryWithParams and WhereFromStruct read field names and options
+// from, in place of the default "inf", so a codebase already tagged
+// for another InfluxDB mapper (e.g. `influx:"..."` or `db:"..."`) can
+// adopt this package without re-tagging every struct. It drops every
+// cached type, bind and where plan so already-seen types are replanned
+// under the new key. Pass "" to restore the default "inf".
+//
+// ToPoint and ParseResult are package-level functions rather than
+// Client methods, so there is no separate per-Client tag key; a
+// service that needs different tag keys for different Clients should
+// call SetStructTagKey before building/decoding points for each, or
+// keep distinct struct types per tag key — the same tradeoff
+// SetNamingStrategy makes.
+func SetStructTagKey(key string) {
+	if key == "" {
+		key = "inf"
+	}
+	activeStructTagKey.Store(key)
+	resetTypePlans()
+	resetBindPlans()
+	resetWherePlans()
+}
+
+// structTag returns ft's tag value under the active struct tag key.
+func structTag(ft reflect.StructField) string {
+	return ft.Tag.Get(activeStructTagKey.Load().(string))
+}
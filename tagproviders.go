@@ -0,0 +1,23 @@
+package influx
+
+// TagProvider returns tags to merge into every point written at the
+// time it is called, so tags that change at runtime (leader status,
+// deployment color, pod name) stay fresh without the caller having to
+// pass them in on every write.
+type TagProvider func() map[string]string
+
+// AddTagProviders appends providers to c's tag-provider chain. Providers
+// are evaluated in registration order on every WriteBatchPointsContext
+// call; a later provider overrides an earlier one for the same tag
+// name, and c's static SetGlobalTags/WithDefaultTags tags override all
+// providers. A tag already set on the point being written takes
+// precedence over everything.
+func (c *Client) AddTagProviders(providers ...TagProvider) {
+	c.tagProviders = append(c.tagProviders, providers...)
+}
+
+// AddTagProviders appends providers to the package-level default
+// Client's tag-provider chain.
+func AddTagProviders(providers ...TagProvider) {
+	gClient().AddTagProviders(providers...)
+}
@@ -0,0 +1,178 @@
+package influx
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// TagSanitizePolicy controls how ToPoint handles a tag value containing
+// a newline, leading/trailing whitespace, or longer than
+// TagSanitizeConfig.MaxLen, since InfluxDB either rejects such a value
+// outright or accepts it into a series that's painful to find and
+// delete later.
+type TagSanitizePolicy int
+
+// Tag sanitize policies for TagSanitizeConfig.Policy.
+const (
+	// TagSanitizeKeep leaves tag values as ToPoint built them, performing
+	// no validation. This is the default (zero value).
+	TagSanitizeKeep TagSanitizePolicy = iota
+	// TagSanitizeTrim strips leading/trailing whitespace and replaces
+	// newlines with a single space, then truncates to MaxLen if set.
+	TagSanitizeTrim
+	// TagSanitizeEscape replaces newlines with their "\n"/"\r" escape
+	// sequences instead of a space, preserving the value's content at
+	// the cost of it no longer round-tripping byte-for-byte, then
+	// truncates to MaxLen if set.
+	TagSanitizeEscape
+	// TagSanitizeError makes ToPoint fail instead of writing an invalid
+	// tag value.
+	TagSanitizeError
+)
+
+// TagSanitizeConfig configures the tag validation policy enabled by
+// SetTagSanitizePolicy.
+type TagSanitizeConfig struct {
+	Policy TagSanitizePolicy
+	// MaxLen rejects (TagSanitizeError) or truncates (TagSanitizeTrim
+	// and TagSanitizeEscape) a tag value longer than MaxLen bytes;
+	// TagSanitizeKeep ignores it. Zero means no limit.
+	MaxLen int
+}
+
+var (
+	tagSanitizeMu    sync.Mutex
+	tagSanitizeCfg   *TagSanitizeConfig // nil means no validation, the default
+	tagSanitizeCount int64              // atomic
+)
+
+// SetTagSanitizePolicy enables validation of every tag value ToPoint (and
+// so Insert, InsertMany and their variants) writes, per cfg. TagSanitizedCount
+// reports how many tag values have been trimmed/escaped/rejected so far.
+//
+// Calling SetTagSanitizePolicy again replaces the previous policy; there
+// is no way to disable validation once enabled.
+func SetTagSanitizePolicy(cfg TagSanitizeConfig) {
+	tagSanitizeMu.Lock()
+	tagSanitizeCfg = &cfg
+	tagSanitizeMu.Unlock()
+}
+
+// TagSanitizedCount returns the number of tag values SetTagSanitizePolicy
+// has trimmed, escaped or rejected so far.
+func TagSanitizedCount() int64 {
+	return atomic.LoadInt64(&tagSanitizeCount)
+}
+
+// ErrInvalidTag is returned under TagSanitizeError when a tag key, its
+// value, or a measurement name fails validation: an empty or
+// underscore-prefixed tag key (InfluxDB reserves the `_`-prefixed
+// namespace for itself), or a newline, leading/trailing whitespace, or
+// over-MaxLen value.
+type ErrInvalidTag struct {
+	Name   string
+	Value  string
+	Reason string
+}
+
+func (e *ErrInvalidTag) Error() string {
+	if e.Name == "measurement" {
+		return fmt.Sprintf("influx: invalid measurement %q: %s", e.Value, e.Reason)
+	}
+	return fmt.Sprintf("influx: invalid tag %q=%q: %s", e.Name, e.Value, e.Reason)
+}
+
+// validateTagKey rejects an empty or underscore-prefixed tag key under
+// TagSanitizeError; TagSanitizeTrim/Escape have no way to fix either, so
+// they leave the key as-is, same as TagSanitizeKeep.
+func validateTagKey(cfg *TagSanitizeConfig, name string) error {
+	if cfg.Policy != TagSanitizeError {
+		return nil
+	}
+	switch {
+	case name == "":
+		return &ErrInvalidTag{Name: name, Reason: "empty tag key"}
+	case strings.HasPrefix(name, "_"):
+		return &ErrInvalidTag{Name: name, Reason: "tag key has reserved leading underscore"}
+	}
+	return nil
+}
+
+// sanitizeTagValue applies the active TagSanitizeConfig (if any) to
+// name/value, returning the (possibly modified) value, or an error
+// under TagSanitizeError.
+func sanitizeTagValue(name, value string) (string, error) {
+	tagSanitizeMu.Lock()
+	cfg := tagSanitizeCfg
+	tagSanitizeMu.Unlock()
+	if cfg == nil {
+		return value, nil
+	}
+
+	if err := validateTagKey(cfg, name); err != nil {
+		atomic.AddInt64(&tagSanitizeCount, 1)
+		return "", err
+	}
+
+	bad := strings.ContainsAny(value, "\n\r") || value != strings.TrimSpace(value) ||
+		(cfg.MaxLen > 0 && len(value) > cfg.MaxLen)
+	if !bad {
+		return value, nil
+	}
+
+	switch cfg.Policy {
+	case TagSanitizeTrim:
+		value = strings.NewReplacer("\n", " ", "\r", " ").Replace(strings.TrimSpace(value))
+	case TagSanitizeEscape:
+		value = strings.NewReplacer("\n", `\n`, "\r", `\r`).Replace(strings.TrimSpace(value))
+	case TagSanitizeError:
+		atomic.AddInt64(&tagSanitizeCount, 1)
+		return "", &ErrInvalidTag{Name: name, Value: value, Reason: fmt.Sprintf("newline, leading/trailing space, or over %d bytes", cfg.MaxLen)}
+	default: // TagSanitizeKeep
+		return value, nil
+	}
+	if cfg.MaxLen > 0 && len(value) > cfg.MaxLen {
+		value = value[:cfg.MaxLen]
+	}
+
+	atomic.AddInt64(&tagSanitizeCount, 1)
+	return value, nil
+}
+
+// validateMeasurement applies the active TagSanitizeConfig's
+// TagSanitizeError check to measurement, since InfluxDB's line protocol
+// escapes a measurement name the same way it does a tag value, and
+// rejects one that's empty. TagSanitizeTrim/Escape don't apply: a
+// rewritten measurement name would silently redirect a point to a
+// different series, which, unlike trimming a tag value, is rarely what
+// the caller wants.
+func validateMeasurement(measurement string) error {
+	tagSanitizeMu.Lock()
+	cfg := tagSanitizeCfg
+	tagSanitizeMu.Unlock()
+	if cfg == nil || cfg.Policy != TagSanitizeError {
+		return nil
+	}
+
+	bad := measurement == "" || strings.ContainsAny(measurement, "\n\r") ||
+		measurement != strings.TrimSpace(measurement) ||
+		(cfg.MaxLen > 0 && len(measurement) > cfg.MaxLen)
+	if !bad {
+		return nil
+	}
+	return &ErrInvalidTag{Name: "measurement", Value: measurement, Reason: fmt.Sprintf("empty, newline, leading/trailing space, or over %d bytes", cfg.MaxLen)}
+}
+
+// setSanitizedTag sanitizes value per the active TagSanitizeConfig
+// before writing it into tags via setTag, which may still drop it if it
+// becomes empty and SetSkipEmptyTags is in effect.
+func setSanitizedTag(tags map[string]string, name, value string) error {
+	value, err := sanitizeTagValue(name, value)
+	if err != nil {
+		return err
+	}
+	setTag(tags, name, value)
+	return nil
+}
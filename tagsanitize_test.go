@@ -0,0 +1,82 @@
+package influx
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestValidateTagKeyRejectsEmptyAndUnderscorePrefixed confirms
+// TagSanitizeError rejects an empty tag key or one starting with the
+// underscore InfluxDB reserves for itself, while leaving both alone
+// under any other policy.
+func TestValidateTagKeyRejectsEmptyAndUnderscorePrefixed(t *testing.T) {
+	SetTagSanitizePolicy(TagSanitizeConfig{Policy: TagSanitizeError})
+	defer SetTagSanitizePolicy(TagSanitizeConfig{})
+
+	var invalid *ErrInvalidTag
+	if _, err := sanitizeTagValue("", "x"); !errors.As(err, &invalid) {
+		t.Fatalf("empty key err = %v, want *ErrInvalidTag", err)
+	}
+	if _, err := sanitizeTagValue("_reserved", "x"); !errors.As(err, &invalid) {
+		t.Fatalf("underscore key err = %v, want *ErrInvalidTag", err)
+	}
+	if _, err := sanitizeTagValue("host", "web1"); err != nil {
+		t.Fatalf("valid key err = %v, want nil", err)
+	}
+}
+
+// TestSanitizeTagValueErrorsOnNewlineUnderTagSanitizeError confirms a
+// tag value containing a newline fails closed with *ErrInvalidTag
+// under TagSanitizeError, instead of being silently trimmed.
+func TestSanitizeTagValueErrorsOnNewlineUnderTagSanitizeError(t *testing.T) {
+	SetTagSanitizePolicy(TagSanitizeConfig{Policy: TagSanitizeError})
+	defer SetTagSanitizePolicy(TagSanitizeConfig{})
+
+	var invalid *ErrInvalidTag
+	if _, err := sanitizeTagValue("host", "web1\nweb2"); !errors.As(err, &invalid) {
+		t.Fatalf("err = %v, want *ErrInvalidTag", err)
+	}
+}
+
+// TestValidateMeasurementRejectsEmptyAndNewline confirms
+// validateMeasurement only fails closed under TagSanitizeError, and
+// only for an empty or otherwise invalid measurement name.
+func TestValidateMeasurementRejectsEmptyAndNewline(t *testing.T) {
+	SetTagSanitizePolicy(TagSanitizeConfig{Policy: TagSanitizeError})
+	defer SetTagSanitizePolicy(TagSanitizeConfig{})
+
+	var invalid *ErrInvalidTag
+	if err := validateMeasurement(""); !errors.As(err, &invalid) {
+		t.Fatalf("empty measurement err = %v, want *ErrInvalidTag", err)
+	}
+	if err := validateMeasurement("cpu\nusage"); !errors.As(err, &invalid) {
+		t.Fatalf("newline measurement err = %v, want *ErrInvalidTag", err)
+	}
+	if err := validateMeasurement("cpu_usage"); err != nil {
+		t.Fatalf("valid measurement err = %v, want nil", err)
+	}
+}
+
+// TestValidateMeasurementIsNoOpWithoutTagSanitizeError confirms
+// validateMeasurement leaves an empty measurement name alone under the
+// default TagSanitizeKeep policy, so PointFromMap doesn't start
+// rejecting callers that never opted into validation.
+func TestValidateMeasurementIsNoOpWithoutTagSanitizeError(t *testing.T) {
+	if err := validateMeasurement(""); err != nil {
+		t.Fatalf("err = %v, want nil", err)
+	}
+}
+
+// TestPointFromMapRejectsReservedTagKeyUnderTagSanitizeError confirms
+// PointFromMap runs its tags through the same TagSanitizeConfig ToPoint
+// applies to a `,tag` struct field.
+func TestPointFromMapRejectsReservedTagKeyUnderTagSanitizeError(t *testing.T) {
+	SetTagSanitizePolicy(TagSanitizeConfig{Policy: TagSanitizeError})
+	defer SetTagSanitizePolicy(TagSanitizeConfig{})
+
+	_, err := PointFromMap("cpu", map[string]string{"_bad": "x"}, map[string]interface{}{"value": 1}, clockNow())
+	var invalid *ErrInvalidTag
+	if !errors.As(err, &invalid) {
+		t.Fatalf("err = %v, want *ErrInvalidTag", err)
+	}
+}
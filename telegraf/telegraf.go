@@ -0,0 +1,117 @@
+// Package telegraf adapts a connection to a Telegraf socket_listener
+// input into a client.Client, so influx.NewBufferedWriterWithClient
+// (or influx.NewWithClient) can ship line protocol straight to
+// Telegraf over TCP, UDP or a Unix socket instead of writing to
+// InfluxDB directly — for an environment where all ingest is required
+// to flow through Telegraf.
+package telegraf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// errQueryUnsupported is returned by every query method: a
+// socket_listener input is write-only.
+var errQueryUnsupported = errors.New("telegraf: sink is write-only; queries are not supported")
+
+// Client is a client.Client that writes each batch it's given as line
+// protocol to a Telegraf socket_listener input over a dialed
+// net.Conn. Queries always fail, the same way influx.NewUDP's Client
+// already fails every query.
+//
+// socket_listener's TCP and Unix transports are framed as one line
+// per point over a persistent connection; its UDP transport is the
+// same framing, one packet per Write/WriteCtx call. Client dials once
+// in NewClient and reconnects automatically if a write finds the
+// connection gone — the case after Telegraf's own idle timeout closes
+// a TCP or Unix connection, or after a Telegraf restart.
+type Client struct {
+	network, address string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewClient dials network (one of "tcp", "udp", "unix", or their
+// "*4"/"*6" variants) and address, matching whatever Telegraf's
+// socket_listener is configured to listen on.
+func NewClient(network, address string) (*Client, error) {
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{network: network, address: address, conn: conn}, nil
+}
+
+// Write writes bp's points as line protocol.
+func (c *Client) Write(bp client.BatchPoints) error {
+	return c.WriteCtx(context.Background(), bp)
+}
+
+// WriteCtx is Write; ctx is accepted for interface compatibility but
+// isn't honored mid-write, since a raw net.Conn write to a local or
+// otherwise fast socket_listener target isn't expected to block long
+// enough to need canceling.
+func (c *Client) WriteCtx(_ context.Context, bp client.BatchPoints) error {
+	var buf bytes.Buffer
+	for _, p := range bp.Points() {
+		buf.WriteString(p.PrecisionString(bp.Precision()))
+		buf.WriteByte('\n')
+	}
+	return c.write(buf.Bytes())
+}
+
+// write sends b over the dialed connection, redialing once and
+// retrying if the first attempt fails.
+func (c *Client) write(b []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.conn.Write(b); err == nil {
+		return nil
+	}
+
+	conn, err := net.Dial(c.network, c.address)
+	if err != nil {
+		return err
+	}
+	c.conn.Close()
+	c.conn = conn
+	_, err = c.conn.Write(b)
+	return err
+}
+
+// Ping always succeeds: there is no Telegraf health check this
+// package can answer on a client.Client's behalf.
+func (c *Client) Ping(timeout time.Duration) (time.Duration, string, error) {
+	return 0, "", nil
+}
+
+// Query always fails: see errQueryUnsupported.
+func (c *Client) Query(q client.Query) (*client.Response, error) {
+	return nil, errQueryUnsupported
+}
+
+// QueryCtx always fails: see errQueryUnsupported.
+func (c *Client) QueryCtx(ctx context.Context, q client.Query) (*client.Response, error) {
+	return nil, errQueryUnsupported
+}
+
+// QueryAsChunk always fails: see errQueryUnsupported.
+func (c *Client) QueryAsChunk(q client.Query) (*client.ChunkedResponse, error) {
+	return nil, errQueryUnsupported
+}
+
+// Close closes the dialed connection.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.Close()
+}
@@ -0,0 +1,149 @@
+package telegraf
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// TestWriteCtxSendsLineProtocol confirms points are written as
+// newline-separated line protocol over a TCP connection, the shape
+// socket_listener's tcp transport expects.
+func TestWriteCtxSendsLineProtocol(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	lines := make(chan string, 2)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	c, err := NewClient("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	p, err := client.NewPoint("cpu", map[string]string{"host": "a"}, map[string]interface{}{"value": 1.5}, time.Unix(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{Precision: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bp.AddPoint(p)
+
+	if err := c.Write(bp); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case line := <-lines:
+		if line != p.PrecisionString("s") {
+			t.Fatalf("got %q, want %q", line, p.PrecisionString("s"))
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for line")
+	}
+}
+
+// TestWriteReconnectsAfterConnectionClosed confirms a Client redials
+// and retries once if the underlying connection was closed out from
+// under it.
+func TestWriteReconnectsAfterConnectionClosed(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	accepted := make(chan net.Conn, 2)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	c, err := NewClient("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	<-accepted
+
+	// Close the client's own side of the connection directly: closing
+	// only the server's accepted conn doesn't reliably make a
+	// subsequent local Write fail (TCP can buffer a write before
+	// noticing the peer is gone), so exercising the reconnect path
+	// deterministically means breaking the connection client-side.
+	c.mu.Lock()
+	c.conn.Close()
+	c.mu.Unlock()
+
+	p, err := client.NewPoint("cpu", nil, map[string]interface{}{"value": 1.0}, time.Unix(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{Precision: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bp.AddPoint(p)
+
+	if err := c.Write(bp); err != nil {
+		t.Fatalf("Write never succeeded: %v", err)
+	}
+
+	select {
+	case <-accepted:
+	case <-time.After(2 * time.Second):
+		t.Fatal("client never redialed")
+	}
+}
+
+// TestQueryUnsupported confirms every query method fails instead of
+// silently returning an empty result.
+func TestQueryUnsupported(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go ln.Accept()
+
+	c, err := NewClient("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err := c.Query(client.Query{}); err == nil {
+		t.Fatal("want error")
+	}
+	if _, err := c.QueryCtx(nil, client.Query{}); err == nil {
+		t.Fatal("want error")
+	}
+	if _, err := c.QueryAsChunk(client.Query{}); err == nil {
+		t.Fatal("want error")
+	}
+}
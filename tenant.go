@@ -0,0 +1,85 @@
+package influx
+
+import "context"
+
+// Tenant is the database/retention-policy pair a tenant ID resolves to
+// via a TenantResolver registered with SetTenantResolver.
+type Tenant struct {
+	Database        string
+	RetentionPolicy string
+}
+
+// TenantResolver maps a tenant ID to the Tenant it writes and queries
+// against. ok is false for an unrecognized tenant ID, in which case the
+// caller's db argument or Client.DB is used instead.
+type TenantResolver func(tenantID string) (tenant Tenant, ok bool)
+
+// tenantIDKey is the context.Context key WithTenant stores a tenant ID
+// under, read back by TenantFromContext.
+type tenantIDKey struct{}
+
+// WithTenant returns a copy of ctx carrying tenantID, so a call that
+// takes a ctx resolves it through the Client's TenantResolver instead
+// of every call site threading a database string by hand.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantIDKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant ID WithTenant stored in ctx, or
+// "" if none was stored.
+func TenantFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(tenantIDKey{}).(string)
+	return id
+}
+
+// SetTenantResolver registers the resolver InsertContext, InsertRPContext,
+// InsertWithOptionsContext, InsertManyContext, QueryContext and
+// QueryWithParamsContext consult when called with an empty db: ctx's
+// tenant ID (see WithTenant) is looked up via resolver, and a matching
+// Tenant's Database and RetentionPolicy are used in place of c.DB and
+// c.RetentionPolicy. An explicit db argument always wins over tenant
+// resolution. Pass nil to disable tenant-aware routing, the default.
+func (c *Client) SetTenantResolver(resolver TenantResolver) {
+	c.tenantResolver = resolver
+}
+
+// resolveTenant returns the database and retention policy a call should
+// use: db if the caller gave one, else the database WithDatabaseContext stored
+// in ctx if any, else the tenant resolved from ctx if c.tenantResolver
+// is set and resolves it, else c's own default database. The retention
+// policy is whatever WithRetentionPolicyContext stored in ctx if any,
+// otherwise the chosen source's own retention policy (a resolved
+// tenant's, or else c's).
+func (c *Client) resolveTenant(ctx context.Context, db string) (database, retentionPolicy string) {
+	database, retentionPolicy = c.resolveDatabase(ctx, db)
+	if rp := RetentionPolicyFromContext(ctx); rp != "" {
+		retentionPolicy = rp
+	}
+	return database, retentionPolicy
+}
+
+// resolveDatabase returns the database and its own retention policy,
+// before WithRetentionPolicyContext's ctx override (applied by resolveTenant)
+// is considered.
+func (c *Client) resolveDatabase(ctx context.Context, db string) (database, retentionPolicy string) {
+	if db != "" {
+		return db, c.RetentionPolicy
+	}
+	if d := DatabaseFromContext(ctx); d != "" {
+		return d, c.RetentionPolicy
+	}
+	if c.tenantResolver != nil {
+		if id := TenantFromContext(ctx); id != "" {
+			if t, ok := c.tenantResolver(id); ok {
+				return t.Database, t.RetentionPolicy
+			}
+		}
+	}
+	return c.DB, c.RetentionPolicy
+}
+
+// SetTenantResolver registers the tenant resolver for the package-level
+// default Client.
+func SetTenantResolver(resolver TenantResolver) {
+	gClient().SetTenantResolver(resolver)
+}
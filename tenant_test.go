@@ -0,0 +1,46 @@
+package influx
+
+import (
+	"context"
+	"testing"
+)
+
+// TestResolveTenantPrecedence confirms resolveTenant's precedence: an
+// explicit db argument beats WithDatabaseContext, which beats a
+// resolved tenant, which beats the Client's own DB/RetentionPolicy —
+// and WithRetentionPolicyContext overrides whichever retention policy
+// that chain would otherwise pick.
+func TestResolveTenantPrecedence(t *testing.T) {
+	c := &Client{DB: "default-db", RetentionPolicy: "default-rp"}
+	c.SetTenantResolver(func(id string) (Tenant, bool) {
+		if id == "acme" {
+			return Tenant{Database: "acme-db", RetentionPolicy: "acme-rp"}, true
+		}
+		return Tenant{}, false
+	})
+
+	cases := []struct {
+		name   string
+		ctx    context.Context
+		db     string
+		wantDB string
+		wantRP string
+	}{
+		{"client defaults", context.Background(), "", "default-db", "default-rp"},
+		{"explicit db wins over everything", WithDatabaseContext(WithTenant(context.Background(), "acme"), "ctx-db"), "explicit-db", "explicit-db", "default-rp"},
+		{"ctx database wins over tenant", WithDatabaseContext(WithTenant(context.Background(), "acme"), "ctx-db"), "", "ctx-db", "default-rp"},
+		{"tenant resolves when no ctx database", WithTenant(context.Background(), "acme"), "", "acme-db", "acme-rp"},
+		{"unknown tenant falls back to client defaults", WithTenant(context.Background(), "nobody"), "", "default-db", "default-rp"},
+		{"ctx retention policy overrides tenant's", WithRetentionPolicyContext(WithTenant(context.Background(), "acme"), "ctx-rp"), "", "acme-db", "ctx-rp"},
+		{"ctx retention policy overrides client default", WithRetentionPolicyContext(context.Background(), "ctx-rp"), "", "default-db", "ctx-rp"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gotDB, gotRP := c.resolveTenant(tc.ctx, tc.db)
+			if gotDB != tc.wantDB || gotRP != tc.wantRP {
+				t.Fatalf("resolveTenant = (%q, %q), want (%q, %q)", gotDB, gotRP, tc.wantDB, tc.wantRP)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package influx
+
+import "sync"
+
+// timeLayoutsMu guards timeLayouts.
+var timeLayoutsMu sync.RWMutex
+
+// timeLayouts are additional time.Parse layouts parseTime falls back
+// to, in registration order, when a string time value doesn't parse
+// as RFC3339; set by RegisterTimeLayout.
+var timeLayouts []string
+
+// RegisterTimeLayout adds layout to the list parseTime tries when
+// decoding a string time value (a "time" column, or a string-backed
+// isTime field) that isn't RFC3339 — RFC3339Nano, or a custom format
+// an upstream system emits its own timestamps in. Layouts are tried in
+// registration order, after RFC3339; the first one time.Parse accepts
+// wins. It applies process-wide, to every Client and every ParseResult
+// call, the same as RegisterConverter.
+//
+// A numeric epoch timestamp, string or not, decodes without
+// registering anything: give it an integer (or *time.Time) isTime
+// field instead of a string one; see fieldPlan.isTime.
+func RegisterTimeLayout(layout string) {
+	timeLayoutsMu.Lock()
+	timeLayouts = append(timeLayouts, layout)
+	timeLayoutsMu.Unlock()
+}
@@ -0,0 +1,45 @@
+package influx
+
+import (
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/models"
+)
+
+// timeLocation is the *time.Location parseTime converts a decoded
+// "time" value into before returning it. Set for the duration of a
+// call by ParseResultWithLocation/ParseResultsWithLocation; nil
+// otherwise, leaving time.Time/time.Unix's own default Location
+// untouched, the same as before this option existed.
+var timeLocation *time.Location
+
+// timeLocationMu serializes ParseResultWithLocation/
+// ParseResultsWithLocation calls, since the *time.Location they
+// install in timeLocation is process-wide state.
+var timeLocationMu sync.Mutex
+
+// ParseResultWithLocation is ParseResult, but converts every decoded
+// "time" value into loc instead of leaving it in whatever Location
+// parsing it happened to produce (UTC for an RFC3339 string, the Local
+// zone for a numeric epoch via time.Unix) — for a caller building
+// daily aggregates who needs "time" read back in the zone the report
+// is meant for, e.g. time.LoadLocation("Asia/Shanghai").
+func ParseResultWithLocation(dst interface{}, serie models.Row, loc *time.Location, columns ...string) error {
+	timeLocationMu.Lock()
+	defer timeLocationMu.Unlock()
+	timeLocation = loc
+	defer func() { timeLocation = nil }()
+	return ParseResult(dst, serie, columns...)
+}
+
+// ParseResultsWithLocation is ParseResults, under the same
+// *time.Location ParseResultWithLocation applies.
+func ParseResultsWithLocation(dst interface{}, results []client.Result, loc *time.Location, columns ...string) error {
+	timeLocationMu.Lock()
+	defer timeLocationMu.Unlock()
+	timeLocation = loc
+	defer func() { timeLocation = nil }()
+	return ParseResults(dst, results, columns...)
+}
@@ -0,0 +1,112 @@
+package influx
+
+import (
+	"fmt"
+	"time"
+)
+
+// durationLiteral formats d as an InfluxQL duration literal in
+// nanoseconds (e.g. "900000000000ns" for 15*time.Minute), the one unit
+// precise enough to hold a time.Duration exactly without converting it
+// to an InfluxQL "15m"-style literal, which Go's own duration units
+// (time.Duration.String()'s "1h30m0s" form included) don't line up
+// with one-for-one.
+func durationLiteral(d time.Duration) string {
+	return fmt.Sprintf("%dns", d.Nanoseconds())
+}
+
+// timeLiteral formats t as an InfluxQL timestamp literal: an absolute
+// RFC3339Nano string in UTC, so the predicate doesn't depend on the
+// server's timezone or clock the way a bare duration offset would.
+func timeLiteral(t time.Time) string {
+	return "'" + t.UTC().Format(time.RFC3339Nano) + "'"
+}
+
+// DurationLiteral is durationLiteral exported for a caller hand-building
+// an InfluxQL fragment Last/RelativeExpr/GroupByTime don't already cover
+// (a subquery's own bound, a custom clause assembled with fmt.Sprintf),
+// so it doesn't need its own ad hoc, less precise duration formatting.
+func DurationLiteral(d time.Duration) string {
+	return durationLiteral(d)
+}
+
+// TimeLiteral is timeLiteral exported for a caller hand-building an
+// InfluxQL fragment Since/Between don't already cover, for the same
+// reason DurationLiteral exists.
+func TimeLiteral(t time.Time) string {
+	return timeLiteral(t)
+}
+
+// Last returns a "time > now() - d" InfluxQL WHERE predicate selecting
+// rows from the last d, e.g. Last(15*time.Minute). Appending further
+// conditions (an AND host = $host, say) is left to the caller;
+// QueryWithParams/QueryBind still bind those safely.
+func Last(d time.Duration) string {
+	return fmt.Sprintf("time > now() - %s", durationLiteral(d))
+}
+
+// Since returns a "time >= t" InfluxQL WHERE predicate selecting rows
+// at or after the absolute time t, rendered as an RFC3339Nano
+// timestamp instead of a now()-relative duration, so it isn't affected
+// by a gap between when the query is built and when it runs.
+func Since(t time.Time) string {
+	return fmt.Sprintf("time >= %s", timeLiteral(t))
+}
+
+// Between returns a "time >= t1 AND time <= t2" InfluxQL WHERE
+// predicate selecting rows in the closed interval [t1, t2], both
+// rendered as absolute RFC3339Nano timestamps.
+func Between(t1, t2 time.Time) string {
+	return fmt.Sprintf("time >= %s AND time <= %s", timeLiteral(t1), timeLiteral(t2))
+}
+
+// RelativeExpr returns the now()-relative InfluxQL expression
+// "now() - d" (or "now() + d" for a negative d), rendered via
+// durationLiteral the same way Last is, for use anywhere a
+// now()-relative offset is needed but a WHERE predicate isn't, e.g.
+// inside a subquery's own time bound or a hand-built GROUP BY time()
+// offset.
+func RelativeExpr(d time.Duration) string {
+	if d < 0 {
+		return fmt.Sprintf("now() + %s", durationLiteral(-d))
+	}
+	return fmt.Sprintf("now() - %s", durationLiteral(d))
+}
+
+// StartOf returns a "time >= t" predicate for the most recent boundary
+// of unit in loc, e.g. StartOf(24*time.Hour, time.Local) for the most
+// recent midnight, or StartOf(time.Hour, loc) for the top of the
+// current hour — the rounded boundary a "today so far" or "this hour
+// so far" dashboard panel needs, which a fixed now()-relative duration
+// like Last(24*time.Hour) can't express: it drifts away from midnight
+// as the day goes on instead of resetting at it. A unit of a day or
+// more is always treated as a calendar day, computed from loc's
+// year/month/day rather than by truncating a Unix timestamp, so
+// midnight lands correctly across a daylight-saving transition instead
+// of drifting by an hour the way time.Time.Truncate would.
+func StartOf(unit time.Duration, loc *time.Location) string {
+	now := clockNow().In(loc)
+	if unit >= 24*time.Hour {
+		return Since(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc))
+	}
+	return Since(now.Truncate(unit))
+}
+
+// ValidateRelativeDuration parses s the way a caller-supplied
+// "?range=12h"-style query parameter would be, returning an error for
+// anything time.ParseDuration itself would reject — a malformed unit,
+// a bare number with no unit — before it reaches Last or RelativeExpr.
+// It exists because a hand-formatted duration string built some other
+// way (e.g. fmt.Sprintf("%gh", hours) for a fractional hours value)
+// can produce something like "1.5h" that InfluxQL's own duration
+// literal syntax doesn't accept, silently selecting the wrong time
+// range instead of failing; parsing with time.ParseDuration up front
+// and passing the resulting time.Duration to Last/RelativeExpr avoids
+// ever building such a literal in the first place.
+func ValidateRelativeDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("influx: invalid relative duration %q: %w", s, err)
+	}
+	return d, nil
+}
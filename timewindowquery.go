@@ -0,0 +1,119 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// WindowQueryOptions configures WindowQuery/WindowQueryContext.
+type WindowQueryOptions struct {
+	// Window is each window's width, e.g. time.Hour for a week-long
+	// export run as 168 one-hour queries instead of one query spanning
+	// the whole week. Window must be positive.
+	Window time.Duration
+
+	// Concurrency bounds how many windows run at once. <= 0 runs
+	// windows sequentially, one at a time, in order — the safe default
+	// for an export whose fn assumes windows arrive in order. Set it
+	// (mirroring QueryGroupOptions.Concurrency) to overlap queries when
+	// fn doesn't care which window it's called with next.
+	Concurrency int
+
+	// StopOnError cancels every window still in flight, and every
+	// window that hasn't started yet, as soon as one window's query or
+	// fn call returns an error — the same semantics
+	// QueryGroupOptions.StopOnError has.
+	StopOnError bool
+}
+
+// WindowQuery runs WindowQueryContext using the default Client.
+func WindowQuery(db, cmd string, from, to time.Time, opts WindowQueryOptions, fn func([]client.Result) error) error {
+	return gClient().WindowQuery(db, cmd, from, to, opts, fn)
+}
+
+// WindowQueryContext runs WindowQueryContext using the default Client.
+func WindowQueryContext(ctx context.Context, db, cmd string, from, to time.Time, opts WindowQueryOptions, fn func([]client.Result) error) error {
+	return gClient().WindowQueryContext(ctx, db, cmd, from, to, opts, fn)
+}
+
+// WindowQuery runs WindowQueryContext with context.Background().
+func (c *Client) WindowQuery(db, cmd string, from, to time.Time, opts WindowQueryOptions, fn func([]client.Result) error) error {
+	return c.WindowQueryContext(context.Background(), db, cmd, from, to, opts, fn)
+}
+
+// WindowQueryContext runs cmd against db once per [from, to) window of
+// opts.Window width, substituting each window's own Between clause for
+// cmd's single %s verb, and calls fn with each window's results as soon
+// as they're decoded — a query too large to run (or whose result is too
+// large to hold) in one shot split into a series of smaller ones the
+// same way Paginator splits a too-large SELECT into LIMIT/OFFSET pages,
+// here bounded by time instead of row count.
+//
+// With the default opts.Concurrency (<= 0), windows run strictly in
+// order, one at a time, and fn is called in that same order. A positive
+// opts.Concurrency runs windows over a bounded worker pool exactly like
+// QueryGroupContext, trading fn's call order for wall-clock time; fn
+// must be safe to call concurrently with itself in that case.
+func (c *Client) WindowQueryContext(ctx context.Context, db, cmd string, from, to time.Time, opts WindowQueryOptions, fn func([]client.Result) error) error {
+	if opts.Window <= 0 {
+		return fmt.Errorf("influx: WindowQuery: Window must be positive, got %s", opts.Window)
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	sem := make(chan struct{}, concurrency)
+
+	for start := from; start.Before(to); start = start.Add(opts.Window) {
+		end := start.Add(opts.Window)
+		if end.After(to) {
+			end = to
+		}
+
+		mu.Lock()
+		stop := firstErr != nil
+		mu.Unlock()
+		if stop || ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(start, end time.Time) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			windowCmd := fmt.Sprintf(cmd, Between(start, end))
+			results, err := c.QueryContext(ctx, db, windowCmd)
+			if err == nil {
+				err = fn(results)
+			}
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				if opts.StopOnError {
+					cancel()
+				}
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return firstErr
+}
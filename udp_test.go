@@ -0,0 +1,45 @@
+package influx
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// TestInsertOverUDPWritesLineProtocol confirms a Client built with
+// NewUDP (the path InitUDPClient uses) sends Insert's point as line
+// protocol over UDP instead of an HTTP request.
+func TestInsertOverUDPWritesLineProtocol(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	c, err := NewUDP(client.UDPConfig{Addr: conn.LocalAddr().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := client.NewPoint("cpu", map[string]string{"host": "a"}, map[string]interface{}{"usage": 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Insert("", p); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom: %v", err)
+	}
+	got := string(buf[:n])
+	if !strings.Contains(got, "cpu,host=a usage=0.5") {
+		t.Fatalf("got packet %q, want it to contain line protocol for cpu,host=a usage=0.5", got)
+	}
+}
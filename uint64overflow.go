@@ -0,0 +1,83 @@
+package influx
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
+)
+
+// Uint64OverflowPolicy controls how ToPoint handles a uint64 (or uint,
+// on a platform where it's 64 bits wide) field value greater than
+// math.MaxInt64, since InfluxDB's line protocol write fails outright on
+// an unsigned integer that large instead of storing it.
+type Uint64OverflowPolicy int32
+
+// Uint64 overflow policies for SetUint64OverflowPolicy.
+const (
+	// Uint64OverflowClamp caps the value at math.MaxInt64. This is the
+	// default (zero value).
+	Uint64OverflowClamp Uint64OverflowPolicy = iota
+	// Uint64OverflowFloat converts the value to float64 instead,
+	// trading exact precision above 2^53 for a value InfluxDB accepts.
+	Uint64OverflowFloat
+	// Uint64OverflowString formats the value as a decimal string field
+	// instead, keeping its exact value at the cost of InfluxDB no longer
+	// treating it as numeric.
+	Uint64OverflowString
+	// Uint64OverflowError makes ToPoint fail instead of writing a
+	// clamped or lossy value.
+	Uint64OverflowError
+)
+
+// uint64OverflowPolicy is the active Uint64OverflowPolicy, set by
+// SetUint64OverflowPolicy.
+var uint64OverflowPolicy int32 // atomic Uint64OverflowPolicy
+
+// uint64OverflowCount counts how many field values
+// SetUint64OverflowPolicy has clamped or converted so far, atomic.
+var uint64OverflowCount int64
+
+// SetUint64OverflowPolicy sets the policy ToPoint applies to a uint64
+// field value above math.MaxInt64, in every point ToPoint (and so
+// Insert, InsertMany and their variants) builds from here on.
+func SetUint64OverflowPolicy(policy Uint64OverflowPolicy) {
+	atomic.StoreInt32(&uint64OverflowPolicy, int32(policy))
+}
+
+// Uint64OverflowCount returns the number of field values
+// SetUint64OverflowPolicy has clamped or converted so far.
+func Uint64OverflowCount() int64 {
+	return atomic.LoadInt64(&uint64OverflowCount)
+}
+
+// adjustUint64Field applies the active Uint64OverflowPolicy to value if
+// it's a uint or uint64 greater than math.MaxInt64, returning the
+// (possibly converted) value unchanged otherwise, or an error under
+// Uint64OverflowError.
+func adjustUint64Field(value interface{}) (interface{}, error) {
+	var u uint64
+	switch v := value.(type) {
+	case uint64:
+		u = v
+	case uint:
+		u = uint64(v)
+	default:
+		return value, nil
+	}
+	if u <= math.MaxInt64 {
+		return value, nil
+	}
+
+	atomic.AddInt64(&uint64OverflowCount, 1)
+	switch Uint64OverflowPolicy(atomic.LoadInt32(&uint64OverflowPolicy)) {
+	case Uint64OverflowFloat:
+		return float64(u), nil
+	case Uint64OverflowString:
+		return strconv.FormatUint(u, 10), nil
+	case Uint64OverflowError:
+		return nil, fmt.Errorf("influx: uint64 field value %d overflows int64", u)
+	default: // Uint64OverflowClamp
+		return uint64(math.MaxInt64), nil
+	}
+}
@@ -0,0 +1,98 @@
+package influx
+
+import (
+	"math"
+	"testing"
+)
+
+// TestAdjustUint64FieldClampsByDefault confirms the default
+// Uint64OverflowClamp policy caps an over-MaxInt64 uint64 at MaxInt64
+// instead of writing a value InfluxDB's line protocol would reject.
+func TestAdjustUint64FieldClampsByDefault(t *testing.T) {
+	got, err := adjustUint64Field(uint64(math.MaxInt64) + 100)
+	if err != nil {
+		t.Fatalf("adjustUint64Field: %v", err)
+	}
+	if got != uint64(math.MaxInt64) {
+		t.Fatalf("got = %v, want %d", got, uint64(math.MaxInt64))
+	}
+}
+
+// TestAdjustUint64FieldConvertsToFloat confirms Uint64OverflowFloat
+// converts an over-MaxInt64 uint64 to float64 instead of clamping it.
+func TestAdjustUint64FieldConvertsToFloat(t *testing.T) {
+	SetUint64OverflowPolicy(Uint64OverflowFloat)
+	defer SetUint64OverflowPolicy(Uint64OverflowClamp)
+
+	got, err := adjustUint64Field(uint64(math.MaxInt64) + 100)
+	if err != nil {
+		t.Fatalf("adjustUint64Field: %v", err)
+	}
+	if got != float64(uint64(math.MaxInt64)+100) {
+		t.Fatalf("got = %v, want %v", got, float64(uint64(math.MaxInt64)+100))
+	}
+}
+
+// TestAdjustUint64FieldEncodesAsString confirms Uint64OverflowString
+// formats an over-MaxInt64 uint64 as its exact decimal string instead
+// of losing precision to a float64 conversion.
+func TestAdjustUint64FieldEncodesAsString(t *testing.T) {
+	SetUint64OverflowPolicy(Uint64OverflowString)
+	defer SetUint64OverflowPolicy(Uint64OverflowClamp)
+
+	got, err := adjustUint64Field(uint64(math.MaxInt64) + 100)
+	if err != nil {
+		t.Fatalf("adjustUint64Field: %v", err)
+	}
+	if got != "9223372036854775907" {
+		t.Fatalf("got = %v, want %q", got, "9223372036854775907")
+	}
+}
+
+// TestAdjustUint64FieldErrorsUnderErrorPolicy confirms
+// Uint64OverflowError fails closed instead of writing a clamped or
+// lossy value.
+func TestAdjustUint64FieldErrorsUnderErrorPolicy(t *testing.T) {
+	SetUint64OverflowPolicy(Uint64OverflowError)
+	defer SetUint64OverflowPolicy(Uint64OverflowClamp)
+
+	if _, err := adjustUint64Field(uint64(math.MaxInt64) + 100); err == nil {
+		t.Fatal("adjustUint64Field succeeded, want an error")
+	}
+}
+
+// TestAdjustUint64FieldLeavesInRangeValuesAlone confirms a uint64
+// within int64's range passes through unchanged, regardless of policy.
+func TestAdjustUint64FieldLeavesInRangeValuesAlone(t *testing.T) {
+	got, err := adjustUint64Field(uint64(42))
+	if err != nil {
+		t.Fatalf("adjustUint64Field: %v", err)
+	}
+	if got != uint64(42) {
+		t.Fatalf("got = %v, want 42", got)
+	}
+}
+
+// TestToPointEncodesOverflowingUint64FieldAsString confirms ToPoint
+// applies Uint64OverflowString to a struct's uint64 field, not just the
+// unexported helper.
+func TestToPointEncodesOverflowingUint64FieldAsString(t *testing.T) {
+	SetUint64OverflowPolicy(Uint64OverflowString)
+	defer SetUint64OverflowPolicy(Uint64OverflowClamp)
+
+	type counter struct {
+		Count uint64 `inf:"count"`
+	}
+
+	p, err := ToPoint(counter{Count: uint64(math.MaxInt64) + 100})
+	if err != nil {
+		t.Fatalf("ToPoint: %v", err)
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if fields["count"] != "9223372036854775907" {
+		t.Fatalf("fields = %v, want count=9223372036854775907", fields)
+	}
+}
@@ -0,0 +1,15 @@
+package influx
+
+import "github.com/influxdata/influxql"
+
+// Validate parses cmd as InfluxQL and reports a syntax error — an
+// unbalanced quote, a malformed clause, a statement that never
+// terminates — without sending it to InfluxDB, so a unit test can
+// catch an obviously broken query before it ever runs against
+// production. It only checks syntax: InfluxDB itself still has the
+// final say on anything that needs a schema to judge, like an unknown
+// function name or a field that doesn't exist in a given measurement.
+func Validate(cmd string) error {
+	_, err := influxql.ParseQuery(cmd)
+	return err
+}
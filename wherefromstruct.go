@@ -0,0 +1,84 @@
+package influx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// whereField is one field WhereFromStruct considers, compiled once per
+// type by planWhere instead of WhereFromStruct re-walking the struct's
+// fields and re-splitting every inf tag on every call.
+type whereField struct {
+	index int
+	name  string
+}
+
+var wherePlans sync.Map // reflect.Type -> []whereField
+
+// planWhere returns t's cached []whereField, building and caching it
+// the first time t is seen.
+func planWhere(t reflect.Type) []whereField {
+	if v, ok := wherePlans.Load(t); ok {
+		return v.([]whereField)
+	}
+	var fields []whereField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := structTag(field)
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name := strings.SplitN(tag, ",", 2)[0]
+		if name == "" {
+			continue
+		}
+		fields = append(fields, whereField{index: i, name: name})
+	}
+	actual, _ := wherePlans.LoadOrStore(t, fields)
+	return actual.([]whereField)
+}
+
+// resetWherePlans drops every cached where plan, for SetStructTagKey,
+// the same way resetTypePlans does for typePlans.
+func resetWherePlans() {
+	wherePlans.Range(func(k, _ interface{}) bool {
+		wherePlans.Delete(k)
+		return true
+	})
+}
+
+// WhereFromStruct builds a WHERE clause (without the leading WHERE
+// keyword) ANDing together field = value for every non-zero field of
+// v, a struct whose fields carry the same `inf:"name"` tags ToPoint
+// uses, so an HTTP handler's filter parameters can be mapped to a safe
+// query without hand-building string concatenation. A field holding
+// its type's zero value is skipped, so an omitted filter doesn't turn
+// into an impossible "field = 0" condition; a field with no inf tag,
+// or tagged `inf:"-"`, is skipped unconditionally. Values are quoted
+// per Go type the same way QueryTemplate.Execute quotes them.
+func WhereFromStruct(v interface{}) (string, error) {
+	val := reflect.Indirect(reflect.ValueOf(v))
+	if val.Kind() != reflect.Struct {
+		return "", fmt.Errorf("influx: WhereFromStruct: %w", ErrNotPointer)
+	}
+
+	var conds []string
+	for _, wf := range planWhere(val.Type()) {
+		fv := val.Field(wf.index)
+		if fv.IsZero() {
+			continue
+		}
+
+		lit, err := quoteTemplateValue(fv.Interface())
+		if err != nil {
+			return "", fmt.Errorf("influx: WhereFromStruct field %q: %w", val.Type().Field(wf.index).Name, err)
+		}
+		conds = append(conds, fmt.Sprintf("%s = %s", Ident(wf.name), lit))
+	}
+	return strings.Join(conds, " AND "), nil
+}
@@ -0,0 +1,117 @@
+package influx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// WriteBatchPointsGzip is WriteBatchPointsGzipContext using
+// context.Background.
+func (c *Client) WriteBatchPointsGzip(bp client.BatchPoints) error {
+	return c.WriteBatchPointsGzipContext(context.Background(), bp)
+}
+
+// WriteBatchPointsGzipContext writes bp like WriteBatchPointsContext,
+// but gzip-compresses the request body first: InfluxDB's /write
+// endpoint decompresses any request whose Content-Encoding is "gzip",
+// which is worth the trouble for large batches over a WAN link.
+//
+// This bypasses client.Client, which has no way to compress what
+// WriteCtx sends: it builds the line-protocol body and POST request
+// itself with no hook to set Content-Encoding or swap in a compressed
+// body (see WriteBatchPointsContext), so this POSTs to addr+"/write"
+// directly, the same bypass QueryStreamContext uses for streaming
+// query decode. It only applies to Clients built with New, and unlike
+// WriteBatchPointsContext it runs no interceptors, point middleware,
+// schema validation, write retry, or spool/file fallback — callers
+// who need those should use WriteBatchPointsContext instead.
+func (c *Client) WriteBatchPointsGzipContext(ctx context.Context, bp client.BatchPoints) error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+	if c.cfg == nil {
+		return errors.New("influx: WriteBatchPointsGzipContext needs a Client built with New")
+	}
+	if err := c.applyCredentials(); err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	gz := gzip.NewWriter(&body)
+	for _, p := range bp.Points() {
+		if _, err := io.WriteString(gz, p.PrecisionString(bp.Precision())); err != nil {
+			return err
+		}
+		if _, err := gz.Write([]byte{'\n'}); err != nil {
+			return err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	u, err := url.Parse(c.addr)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, "write")
+	q := url.Values{}
+	q.Set("db", bp.Database())
+	if rp := bp.RetentionPolicy(); rp != "" {
+		q.Set("rp", rp)
+	}
+	q.Set("precision", bp.Precision())
+	if wc := bp.WriteConsistency(); wc != "" {
+		q.Set("consistency", wc)
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("Content-Encoding", "gzip")
+	c.mu.RLock()
+	if c.cfg.Username != "" {
+		req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+	}
+	for k, vs := range c.headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	c.mu.RUnlock()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("influx: gzip write failed: %s: %s", resp.Status, respBody)
+	}
+	return nil
+}
+
+// WriteBatchPointsGzip writes bp using the default Client, compressing
+// the request body the way WriteBatchPointsGzipContext does.
+func WriteBatchPointsGzip(bp client.BatchPoints) error {
+	return gClient().WriteBatchPointsGzip(bp)
+}
+
+// WriteBatchPointsGzipContext is WriteBatchPointsGzip with a context,
+// using the default Client.
+func WriteBatchPointsGzipContext(ctx context.Context, bp client.BatchPoints) error {
+	return gClient().WriteBatchPointsGzipContext(ctx, bp)
+}
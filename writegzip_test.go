@@ -0,0 +1,87 @@
+package influx
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// TestWriteBatchPointsGzipContextCompressesBody confirms the request
+// carries Content-Encoding: gzip and a body the server can decompress
+// back into the original line protocol.
+func TestWriteBatchPointsGzipContextCompressesBody(t *testing.T) {
+	var gotEncoding, gotPath, gotDB string
+	var gotLines string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotEncoding = r.Header.Get("Content-Encoding")
+		gotPath = r.URL.Path
+		gotDB = r.URL.Query().Get("db")
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(gz)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotLines = string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c, err := New(client.HTTPConfig{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{Database: "mydb", Precision: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := client.NewPoint("cpu", map[string]string{"host": "a"}, map[string]interface{}{"value": 1.5}, time.Unix(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bp.AddPoint(p)
+
+	if err := c.WriteBatchPointsGzip(bp); err != nil {
+		t.Fatal(err)
+	}
+
+	if gotEncoding != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want %q", gotEncoding, "gzip")
+	}
+	if gotPath != "/write" {
+		t.Fatalf("path = %q, want %q", gotPath, "/write")
+	}
+	if gotDB != "mydb" {
+		t.Fatalf("db = %q, want %q", gotDB, "mydb")
+	}
+	if gotLines != p.PrecisionString("s")+"\n" {
+		t.Fatalf("lines = %q, want %q", gotLines, p.PrecisionString("s")+"\n")
+	}
+}
+
+// TestWriteBatchPointsGzipContextRejectsReadOnly confirms a read-only
+// Client refuses to write, the same as WriteBatchPointsContext.
+func TestWriteBatchPointsGzipContextRejectsReadOnly(t *testing.T) {
+	c, err := New(client.HTTPConfig{Addr: "http://127.0.0.1:0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.ReadOnly(true)
+
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{Database: "mydb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.WriteBatchPointsGzip(bp); err != ErrReadOnly {
+		t.Fatalf("err = %v, want %v", err, ErrReadOnly)
+	}
+}
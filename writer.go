@@ -0,0 +1,670 @@
+package influx
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// Tuning defaults for BufferedWriter.
+const (
+	DefaultBatchSize     = 5000
+	DefaultFlushInterval = time.Second
+	DefaultRetryInterval = 500 * time.Millisecond
+	DefaultMaxRetries    = 3
+)
+
+// WriterConfig configures a BufferedWriter.
+type WriterConfig struct {
+	// HTTP configures an HTTP transport. Exactly one of HTTP or UDP must
+	// be set.
+	HTTP *client.HTTPConfig
+	// UDP configures a UDP transport. Exactly one of HTTP or UDP must be
+	// set. UDP is the standard high-throughput write path for InfluxDB
+	// 1.x, trading delivery confirmation for lower write latency.
+	UDP *client.UDPConfig
+
+	// Database, Precision and RetentionPolicy describe the batches
+	// written to InfluxDB. Database and RetentionPolicy are ignored when
+	// UDP is set, which carries neither. Precision still applies: it is
+	// used to round each point's timestamp before it's encoded onto the
+	// wire, so it defaults to "s" even for UDP and will silently
+	// truncate sub-second timestamps unless set explicitly.
+	Database        string
+	Precision       string
+	RetentionPolicy string
+
+	// BatchSize is the number of points flushed per batch, once
+	// reached. Defaults to DefaultBatchSize.
+	BatchSize int
+	// FlushInterval is the maximum time a point waits in the buffer
+	// before being flushed, regardless of BatchSize. Defaults to
+	// DefaultFlushInterval.
+	FlushInterval time.Duration
+	// QueueSize bounds the number of points queued for write. Once
+	// full, Write drops the oldest queued point to make room for the
+	// newest. Defaults to 10 * BatchSize.
+	QueueSize int
+
+	// MaxRetries is the number of additional attempts made to write a
+	// batch before it is dropped. Defaults to DefaultMaxRetries.
+	MaxRetries int
+	// RetryInterval is the delay before the first retry; each
+	// subsequent retry doubles it. Defaults to DefaultRetryInterval.
+	RetryInterval time.Duration
+
+	// OnError, if set, is called with the points and error of every
+	// batch that still fails after MaxRetries attempts, so the caller
+	// can alert or re-route the data instead of losing it silently. It
+	// runs on the sender goroutine, so it must not block or call back
+	// into the BufferedWriter (e.g. Flush or Close) without risking
+	// deadlock.
+	OnError func(points []*client.Point, err error)
+
+	// Backpressure controls what Write does when the internal queue is
+	// full. Defaults to BackpressureDropOldest.
+	Backpressure BackpressurePolicy
+
+	// Dedupe deduplicates points within a batch that share measurement,
+	// tag set and timestamp, keeping the last one, so producers that
+	// double-emit don't silently overwrite each other's fields server
+	// side and don't pay for the extra payload on the wire.
+	Dedupe bool
+
+	// AdaptiveBatch enables automatic tuning of the flush batch size
+	// within [MinBatchSize, MaxBatchSize] instead of always flushing
+	// exactly BatchSize points: a batch that times out or comes back
+	// with a 413 (request entity too large) halves the current target,
+	// and one that writes successfully well within RetryInterval grows
+	// it by a tenth, both clamped to the configured bounds — so
+	// BatchSize doesn't have to be hand-tuned per environment's network
+	// and InfluxDB capacity. BatchSize remains the starting point, and,
+	// with AdaptiveBatch false (the default), the fixed, unchanging
+	// target.
+	AdaptiveBatch bool
+	// MinBatchSize and MaxBatchSize bound BatchSize's adjustment under
+	// AdaptiveBatch. Default to BatchSize/10 (at least 1) and
+	// BatchSize*10 respectively.
+	MinBatchSize int
+	MaxBatchSize int
+
+	// Pacing, if set, throttles the sender goroutine's batch sends to
+	// stay within it, the BufferedWriter counterpart of Client.RateLimit
+	// — for re-ingesting a large backlog of historical points (a
+	// backfill) through this same BufferedWriter without it outrunning
+	// live traffic for InfluxDB's write capacity. Unlike Client.
+	// RateLimit, exceeding it always blocks the sender until budget
+	// frees up rather than ever failing fast; RateLimitConfig.Block is
+	// ignored.
+	Pacing *RateLimitConfig
+	// OnPace, if set, is called after every batch send attempt while
+	// Pacing is set, with that batch's size and the running totals, so a
+	// long backfill can report its own progress. It runs on the sender
+	// goroutine, under the same no-blocking, no-callback-into-w
+	// constraint OnError documents.
+	OnPace func(PaceProgress)
+}
+
+// PaceProgress reports one batch sent under WriterConfig.Pacing, passed
+// to WriterConfig.OnPace.
+type PaceProgress struct {
+	// Points is this batch's own size.
+	Points int
+	// PointsTotal and Batches are running totals across every batch sent
+	// under Pacing so far, this one included.
+	PointsTotal int64
+	Batches     int64
+	// Err is this batch's send error, if any, the same error OnError
+	// would also see.
+	Err error
+}
+
+func (cfg *WriterConfig) setDefaults() {
+	if cfg.Precision == "" {
+		cfg.Precision = "s"
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = cfg.BatchSize * 10
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = DefaultRetryInterval
+	}
+	if cfg.AdaptiveBatch {
+		if cfg.MinBatchSize <= 0 {
+			cfg.MinBatchSize = cfg.BatchSize / 10
+			if cfg.MinBatchSize < 1 {
+				cfg.MinBatchSize = 1
+			}
+		}
+		if cfg.MaxBatchSize <= 0 {
+			cfg.MaxBatchSize = cfg.BatchSize * 10
+		}
+	}
+}
+
+// sendRequest hands a batch off from the buffering loop to the sender
+// goroutine. reply is non-nil only for batches flushed by an explicit
+// Flush call. ctx is the context the write itself is run under: Flush's
+// caller-supplied ctx for an explicit flush, context.Background() for a
+// batch flushed by BatchSize or FlushInterval.
+type sendRequest struct {
+	ctx    context.Context
+	points []*client.Point
+	reply  chan error
+}
+
+// flushRequest carries Flush's context alongside its reply channel, so
+// a canceled Flush can abandon the in-flight write instead of just
+// giving up on waiting for it.
+type flushRequest struct {
+	ctx   context.Context
+	reply chan error
+}
+
+// BufferedWriter batches points written with Write or WriteStruct and
+// flushes them asynchronously in BatchPoints of WriterConfig.BatchSize
+// or WriterConfig.FlushInterval, whichever comes first, retrying failed
+// flushes with exponential backoff. Retries run on a separate goroutine
+// from the buffering loop, so a batch backing off from a failed write
+// never stalls FlushInterval ticks or Flush/Close calls for the next
+// one. It is safe for concurrent use by multiple goroutines, and must
+// be closed with Close.
+type BufferedWriter struct {
+	cli client.Client
+	cfg WriterConfig
+
+	points chan *client.Point
+	flush  chan flushRequest
+	closed chan struct{}
+	toSend chan sendRequest
+	done   chan struct{}
+
+	closeOnce   sync.Once
+	cliClose    sync.Once
+	cliCloseErr error
+
+	shuttingDown int32 // atomic
+	undelivered  int64 // atomic
+
+	// targetBatchSize is the current flush threshold under
+	// cfg.AdaptiveBatch; unused otherwise. Starts at cfg.BatchSize and
+	// is adjusted by growBatchSize/shrinkBatchSize after each send.
+	targetBatchSize int32 // atomic
+
+	// pacer enforces cfg.Pacing; nil when cfg.Pacing is unset.
+	pacer *rateLimiter
+	// pacedPoints and pacedBatches are running totals reported via
+	// PaceProgress; unused unless cfg.Pacing is set.
+	pacedPoints  int64 // atomic
+	pacedBatches int64 // atomic
+
+	stats WriterStats
+}
+
+// NewBufferedWriter creates a BufferedWriter from cfg, selecting an
+// HTTP or UDP transport depending on which of cfg.HTTP/cfg.UDP is set,
+// and starts its background buffering and sending goroutines.
+func NewBufferedWriter(cfg WriterConfig) (*BufferedWriter, error) {
+	var cli client.Client
+	var err error
+	switch {
+	case cfg.HTTP != nil:
+		cli, err = client.NewHTTPClient(*cfg.HTTP)
+	case cfg.UDP != nil:
+		cli, err = client.NewUDPClient(*cfg.UDP)
+	default:
+		return nil, errors.New("influx: WriterConfig needs HTTP or UDP")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewBufferedWriterWithClient(cli, cfg), nil
+}
+
+// NewBufferedWriterWithClient wraps an already-constructed client.Client,
+// skipping NewBufferedWriter's own dialing — for a transport neither HTTP
+// nor UDP covers, such as the kafka subpackage's Client, which publishes
+// each batch as line protocol to a Kafka topic instead of sending it
+// over the wire itself. cfg.HTTP and cfg.UDP are ignored.
+func NewBufferedWriterWithClient(cli client.Client, cfg WriterConfig) *BufferedWriter {
+	cfg.setDefaults()
+	w := &BufferedWriter{
+		cli:             cli,
+		cfg:             cfg,
+		points:          make(chan *client.Point, cfg.QueueSize),
+		flush:           make(chan flushRequest),
+		closed:          make(chan struct{}),
+		toSend:          make(chan sendRequest, 1),
+		done:            make(chan struct{}),
+		targetBatchSize: int32(cfg.BatchSize),
+	}
+	if cfg.Pacing != nil {
+		w.pacer = newRateLimiter(*cfg.Pacing)
+	}
+	go w.sendLoop()
+	go w.loop()
+	return w
+}
+
+// Write enqueues point for the next flush, applying
+// WriterConfig.Backpressure if the internal queue is full. It only
+// returns an error under BackpressureReject; every other policy always
+// returns nil.
+func (w *BufferedWriter) Write(point *client.Point) error {
+	if atomic.LoadInt32(&w.shuttingDown) != 0 {
+		return ErrWriterClosed
+	}
+
+	select {
+	case w.points <- point:
+		return nil
+	default:
+	}
+
+	switch w.cfg.Backpressure {
+	case BackpressureBlock:
+		w.points <- point
+		return nil
+	case BackpressureDropNewest:
+		atomic.AddInt64(&w.stats.DroppedNewest, 1)
+		return nil
+	case BackpressureReject:
+		atomic.AddInt64(&w.stats.Rejected, 1)
+		return ErrBufferFull
+	default: // BackpressureDropOldest
+		select {
+		case <-w.points:
+			atomic.AddInt64(&w.stats.DroppedOldest, 1)
+		default:
+		}
+		select {
+		case w.points <- point:
+		default:
+		}
+		return nil
+	}
+}
+
+// WriteStruct converts structure to a point with ToPoint and enqueues
+// it, same as Write.
+func (w *BufferedWriter) WriteStruct(structure interface{}) error {
+	p, err := ToPoint(structure)
+	if err != nil {
+		return err
+	}
+	return w.Write(p)
+}
+
+// BackpressurePolicy controls what Write does when the internal queue
+// is full.
+type BackpressurePolicy int
+
+// Backpressure policies for WriterConfig.Backpressure.
+const (
+	// BackpressureDropOldest discards the oldest queued point to make
+	// room for the new one. This is the default.
+	BackpressureDropOldest BackpressurePolicy = iota
+	// BackpressureDropNewest discards the point passed to Write
+	// instead of a queued one.
+	BackpressureDropNewest
+	// BackpressureBlock makes Write wait until the queue has room.
+	BackpressureBlock
+	// BackpressureReject makes Write return ErrBufferFull instead of
+	// blocking or dropping anything.
+	BackpressureReject
+)
+
+// ErrBufferFull is returned by Write under BackpressureReject when the
+// internal queue is full.
+var ErrBufferFull = errors.New("influx: write buffer is full")
+
+// ErrWriterClosed is returned by Write and WriteStruct once Shutdown
+// has been called, instead of silently queuing a point that will never
+// be flushed.
+var ErrWriterClosed = errors.New("influx: writer is shutting down")
+
+// WriterStats counts the outcomes of Write calls that found the
+// internal queue full, broken down by WriterConfig.Backpressure policy.
+type WriterStats struct {
+	DroppedOldest int64
+	DroppedNewest int64
+	Rejected      int64
+}
+
+// Stats returns a snapshot of w's backpressure counters.
+func (w *BufferedWriter) Stats() WriterStats {
+	return WriterStats{
+		DroppedOldest: atomic.LoadInt64(&w.stats.DroppedOldest),
+		DroppedNewest: atomic.LoadInt64(&w.stats.DroppedNewest),
+		Rejected:      atomic.LoadInt64(&w.stats.Rejected),
+	}
+}
+
+// Len returns the number of points currently queued, waiting for the
+// next flush — the buffer depth behind Write, for a caller that wants
+// to watch it approach WriterConfig.QueueSize instead of only learning
+// about backpressure after Stats' drop/reject counters already moved.
+func (w *BufferedWriter) Len() int {
+	return len(w.points)
+}
+
+// loop only buffers points and decides when a batch is due; the actual
+// write (and its retries) happens in sendLoop so a slow or backing-off
+// write never stalls the ticker or a concurrent Flush/Close.
+func (w *BufferedWriter) loop() {
+	defer close(w.toSend)
+	ticker := newTicker(w.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*client.Point, 0, w.cfg.BatchSize)
+	enqueue := func(ctx context.Context, reply chan error) {
+		if len(batch) == 0 {
+			if reply != nil {
+				reply <- nil
+			}
+			return
+		}
+		w.toSend <- sendRequest{ctx: ctx, points: batch, reply: reply}
+		batch = make([]*client.Point, 0, w.cfg.BatchSize)
+	}
+
+	for {
+		select {
+		case p := <-w.points:
+			batch = append(batch, p)
+			if len(batch) >= w.batchSizeTarget() {
+				enqueue(context.Background(), nil)
+			}
+		case <-ticker.C():
+			enqueue(context.Background(), nil)
+		case req := <-w.flush:
+			enqueue(req.ctx, req.reply)
+		case <-w.closed:
+			for {
+				select {
+				case p := <-w.points:
+					batch = append(batch, p)
+				default:
+					enqueue(context.Background(), nil)
+					return
+				}
+			}
+		}
+	}
+}
+
+// sendLoop writes batches handed off by loop one at a time, retrying
+// each with exponential backoff. It exits once loop closes w.toSend
+// after draining the remaining points on Close.
+func (w *BufferedWriter) sendLoop() {
+	defer close(w.done)
+	for req := range w.toSend {
+		err := w.send(req.ctx, req.points)
+		if err != nil && w.cfg.OnError != nil {
+			w.cfg.OnError(req.points, err)
+		}
+		if w.cfg.Pacing != nil {
+			points := atomic.AddInt64(&w.pacedPoints, int64(len(req.points)))
+			batches := atomic.AddInt64(&w.pacedBatches, 1)
+			if w.cfg.OnPace != nil {
+				w.cfg.OnPace(PaceProgress{Points: len(req.points), PointsTotal: points, Batches: batches, Err: err})
+			}
+		}
+		if req.reply != nil {
+			req.reply <- err
+		}
+	}
+}
+
+// send writes points as a single batch, aborting the attempt in
+// progress as soon as ctx is canceled instead of blocking until the
+// underlying HTTP client's own timeout fires.
+func (w *BufferedWriter) send(ctx context.Context, points []*client.Point) error {
+	if err := w.waitForPace(ctx, len(points)); err != nil {
+		return err
+	}
+
+	if w.cfg.Dedupe {
+		points = dedupePoints(points)
+	}
+
+	bp, release, err := newPooledBatchPoints(client.BatchPointsConfig{
+		Database:        w.cfg.Database,
+		Precision:       w.cfg.Precision,
+		RetentionPolicy: w.cfg.RetentionPolicy,
+	})
+	if err != nil {
+		return err
+	}
+	defer release()
+	bp.AddPoints(points)
+
+	start := clockNow()
+	delay := w.cfg.RetryInterval
+	var lastErr error
+retryLoop:
+	for attempt := 0; attempt <= w.cfg.MaxRetries; attempt++ {
+		if lastErr = w.cli.WriteCtx(ctx, bp); lastErr == nil {
+			if w.cfg.AdaptiveBatch && clockNow().Sub(start) < w.cfg.RetryInterval {
+				w.growBatchSize()
+			}
+			return nil
+		}
+		if ctx.Err() != nil {
+			lastErr = ctx.Err()
+			break
+		}
+		if attempt < w.cfg.MaxRetries {
+			select {
+			case <-after(delay):
+			case <-ctx.Done():
+				lastErr = ctx.Err()
+				break retryLoop
+			}
+			delay *= 2
+		}
+	}
+	if w.cfg.AdaptiveBatch && shouldShrinkBatchSize(lastErr) {
+		w.shrinkBatchSize()
+	}
+	atomic.AddInt64(&w.undelivered, int64(len(points)))
+	return lastErr
+}
+
+// waitForPace blocks until w.pacer has budget for a batch of npoints
+// points, the BufferedWriter counterpart of Client.waitForBudget — it
+// always blocks rather than ever failing fast, since a paced batch has
+// nowhere useful to be rejected back to.
+func (w *BufferedWriter) waitForPace(ctx context.Context, npoints int) error {
+	if w.pacer == nil {
+		return nil
+	}
+	for {
+		if w.pacer.allow(npoints, 0) {
+			return nil
+		}
+		select {
+		case <-after(time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// batchSizeTarget returns the current flush threshold: the adaptively
+// tuned size under cfg.AdaptiveBatch, or the fixed cfg.BatchSize
+// otherwise.
+func (w *BufferedWriter) batchSizeTarget() int {
+	if !w.cfg.AdaptiveBatch {
+		return w.cfg.BatchSize
+	}
+	return int(atomic.LoadInt32(&w.targetBatchSize))
+}
+
+// growBatchSize increases the adaptive batch target by a tenth (at
+// least 1), capped at MaxBatchSize, the additive-increase half of
+// AdaptiveBatch's tuning.
+func (w *BufferedWriter) growBatchSize() {
+	for {
+		cur := atomic.LoadInt32(&w.targetBatchSize)
+		next := cur + cur/10
+		if next <= cur {
+			next = cur + 1
+		}
+		if int(next) > w.cfg.MaxBatchSize {
+			next = int32(w.cfg.MaxBatchSize)
+		}
+		if next == cur || atomic.CompareAndSwapInt32(&w.targetBatchSize, cur, next) {
+			return
+		}
+	}
+}
+
+// shrinkBatchSize halves the adaptive batch target, floored at
+// MinBatchSize, the multiplicative-decrease half of AdaptiveBatch's
+// tuning.
+func (w *BufferedWriter) shrinkBatchSize() {
+	for {
+		cur := atomic.LoadInt32(&w.targetBatchSize)
+		next := cur / 2
+		if int(next) < w.cfg.MinBatchSize {
+			next = int32(w.cfg.MinBatchSize)
+		}
+		if next == cur || atomic.CompareAndSwapInt32(&w.targetBatchSize, cur, next) {
+			return
+		}
+	}
+}
+
+// shouldShrinkBatchSize reports whether err is a sign the batch itself
+// was too big for the path it took: a network timeout, or InfluxDB's
+// 413 (request entity too large).
+func shouldShrinkBatchSize(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	code, ok := responseStatusCode(err)
+	return ok && code == http.StatusRequestEntityTooLarge
+}
+
+// Flush blocks until every point queued before the call has been
+// written, or returns ctx.Err() if ctx is canceled first.
+func (w *BufferedWriter) Flush(ctx context.Context) error {
+	reply := make(chan error, 1)
+	select {
+	case w.flush <- flushRequest{ctx: ctx, reply: reply}:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-w.done:
+		return errors.New("influx: writer closed")
+	}
+	select {
+	case err := <-reply:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close flushes any remaining points, stops the background flush loop
+// and releases the underlying client, or returns ctx.Err() if ctx is
+// canceled first. Close is idempotent: calling it again after it has
+// already completed returns the same result instead of panicking on an
+// already-closed channel.
+func (w *BufferedWriter) Close(ctx context.Context) error {
+	w.closeOnce.Do(func() {
+		close(w.closed)
+	})
+	select {
+	case <-w.done:
+		w.cliClose.Do(func() {
+			w.cliCloseErr = w.cli.Close()
+		})
+		return w.cliCloseErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ShutdownReport summarizes the outcome of Shutdown.
+type ShutdownReport struct {
+	// Undelivered counts points that could not be written: every point
+	// in a batch that exhausted WriterConfig.MaxRetries, plus, if ctx
+	// was canceled before the flush finished, every point still queued
+	// at that point.
+	Undelivered int64
+}
+
+// Shutdown stops Write and WriteStruct from accepting new points (they
+// return ErrWriterClosed), flushes everything already queued, stops the
+// background goroutines and releases the underlying client, then
+// reports how many points could not be delivered. It blocks until the
+// flush completes or ctx is canceled, whichever comes first.
+func (w *BufferedWriter) Shutdown(ctx context.Context) (ShutdownReport, error) {
+	atomic.StoreInt32(&w.shuttingDown, 1)
+	err := w.Close(ctx)
+	if ctx.Err() != nil {
+		atomic.AddInt64(&w.undelivered, int64(len(w.points)))
+	}
+	return ShutdownReport{Undelivered: atomic.LoadInt64(&w.undelivered)}, err
+}
+
+// dedupePoints drops points that share a measurement, tag set and
+// timestamp with a later point in the slice, keeping the later one and
+// otherwise preserving order.
+func dedupePoints(points []*client.Point) []*client.Point {
+	type key struct {
+		name string
+		tags string
+		time int64
+	}
+	seen := make(map[key]int, len(points))
+	out := make([]*client.Point, 0, len(points))
+	for _, p := range points {
+		k := key{name: p.Name(), tags: tagsKey(p.Tags()), time: p.Time().UnixNano()}
+		if i, ok := seen[k]; ok {
+			out[i] = p
+			continue
+		}
+		seen[k] = len(out)
+		out = append(out, p)
+	}
+	return out
+}
+
+// tagsKey builds a canonical string for a point's tag set so two points
+// with the same tags in a different order compare equal.
+func tagsKey(tags map[string]string) string {
+	names := make([]string, 0, len(tags))
+	for k := range tags {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, k := range names {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(tags[k])
+		b.WriteByte(',')
+	}
+	return b.String()
+}
@@ -0,0 +1,555 @@
+package influx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// fakeClient is a minimal client.Client used to exercise BufferedWriter's
+// lifecycle without a real InfluxDB server.
+type fakeClient struct {
+	mu     sync.Mutex
+	writes int
+	closes int
+	lastBP client.BatchPoints
+}
+
+func (f *fakeClient) Ping(timeout time.Duration) (time.Duration, string, error) {
+	return 0, "", nil
+}
+
+func (f *fakeClient) Write(bp client.BatchPoints) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.writes++
+	f.lastBP = bp
+	return nil
+}
+
+func (f *fakeClient) WriteCtx(ctx context.Context, bp client.BatchPoints) error {
+	return f.Write(bp)
+}
+
+func (f *fakeClient) Query(q client.Query) (*client.Response, error) {
+	return nil, errors.New("fakeClient: Query not implemented")
+}
+
+func (f *fakeClient) QueryCtx(ctx context.Context, q client.Query) (*client.Response, error) {
+	return f.Query(q)
+}
+
+func (f *fakeClient) QueryAsChunk(q client.Query) (*client.ChunkedResponse, error) {
+	return nil, errors.New("fakeClient: QueryAsChunk not implemented")
+}
+
+func (f *fakeClient) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closes++
+	return nil
+}
+
+// newTestWriter builds a BufferedWriter around cli, bypassing
+// NewBufferedWriter's HTTP/UDP transport construction.
+func newTestWriter(cli client.Client) *BufferedWriter {
+	return newTestWriterWithConfig(cli, WriterConfig{Database: "db"})
+}
+
+// newTestWriterWithConfig is newTestWriter, but with the caller's own
+// cfg (Database is not defaulted for it), for a test that needs to set
+// fields setDefaults wouldn't otherwise touch, e.g. AdaptiveBatch.
+func newTestWriterWithConfig(cli client.Client, cfg WriterConfig) *BufferedWriter {
+	cfg.setDefaults()
+	w := &BufferedWriter{
+		cli:             cli,
+		cfg:             cfg,
+		points:          make(chan *client.Point, cfg.QueueSize),
+		flush:           make(chan flushRequest),
+		closed:          make(chan struct{}),
+		toSend:          make(chan sendRequest, 1),
+		done:            make(chan struct{}),
+		targetBatchSize: int32(cfg.BatchSize),
+	}
+	if cfg.Pacing != nil {
+		w.pacer = newRateLimiter(*cfg.Pacing)
+	}
+	go w.sendLoop()
+	go w.loop()
+	return w
+}
+
+// newUnstartedTestWriter builds a BufferedWriter like
+// newTestWriterWithConfig, but without starting its background loop or
+// sendLoop goroutines, so its points channel fills deterministically —
+// for exercising Write's backpressure policies in isolation from the
+// buffering machinery that would otherwise race to drain it.
+func newUnstartedTestWriter(cfg WriterConfig) *BufferedWriter {
+	cfg.setDefaults()
+	return &BufferedWriter{
+		cfg:    cfg,
+		points: make(chan *client.Point, cfg.QueueSize),
+		flush:  make(chan flushRequest),
+		closed: make(chan struct{}),
+		toSend: make(chan sendRequest, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+// timeoutError is a net.Error whose Timeout method reports true, for
+// exercising AdaptiveBatch's shrink-on-timeout path without a real
+// network timeout.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// flakyClient fails every WriteCtx call with err, for exercising
+// BufferedWriter's adaptive batch shrinking without a real server.
+type flakyClient struct {
+	fakeClient
+	err error
+}
+
+func (f *flakyClient) WriteCtx(ctx context.Context, bp client.BatchPoints) error {
+	f.mu.Lock()
+	f.writes++
+	f.mu.Unlock()
+	return f.err
+}
+
+func TestBufferedWriterWriteFlushClose(t *testing.T) {
+	fc := &fakeClient{}
+	w := newTestWriter(fc)
+
+	p, err := client.NewPoint("m", nil, map[string]interface{}{"v": 1}, time.Now())
+	if err != nil {
+		t.Fatalf("NewPoint: %v", err)
+	}
+	w.Write(p)
+
+	if err := w.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	fc.mu.Lock()
+	writes := fc.writes
+	fc.mu.Unlock()
+	if writes != 1 {
+		t.Fatalf("writes = %d, want 1", writes)
+	}
+
+	if err := w.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestBufferedWriterCloseIdempotent(t *testing.T) {
+	fc := &fakeClient{}
+	w := newTestWriter(fc)
+
+	ctx := context.Background()
+	if err := w.Close(ctx); err != nil {
+		t.Fatalf("first Close: %v", err)
+	}
+	if err := w.Close(ctx); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+
+	fc.mu.Lock()
+	closes := fc.closes
+	fc.mu.Unlock()
+	if closes != 1 {
+		t.Fatalf("cli.Close called %d times, want 1", closes)
+	}
+}
+
+// TestBufferedWriterAdaptiveBatchGrows confirms a fast successful send
+// grows the adaptive batch target under AdaptiveBatch, capped at
+// MaxBatchSize.
+func TestBufferedWriterAdaptiveBatchGrows(t *testing.T) {
+	fc := &fakeClient{}
+	w := newTestWriterWithConfig(fc, WriterConfig{
+		Database:      "db",
+		BatchSize:     10,
+		AdaptiveBatch: true,
+		MinBatchSize:  2,
+		MaxBatchSize:  12,
+		RetryInterval: time.Hour, // any real send is "fast" relative to this
+	})
+	defer w.Close(context.Background())
+
+	p, err := client.NewPoint("m", nil, map[string]interface{}{"v": 1}, time.Now())
+	if err != nil {
+		t.Fatalf("NewPoint: %v", err)
+	}
+
+	if err := w.send(context.Background(), []*client.Point{p}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if got := w.batchSizeTarget(); got != 11 {
+		t.Fatalf("batchSizeTarget = %d, want 11", got)
+	}
+
+	// Grows again, but clamped at MaxBatchSize instead of overshooting.
+	for i := 0; i < 5; i++ {
+		if err := w.send(context.Background(), []*client.Point{p}); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+	if got := w.batchSizeTarget(); got != 12 {
+		t.Fatalf("batchSizeTarget = %d, want 12 (clamped)", got)
+	}
+}
+
+// TestBufferedWriterAdaptiveBatchShrinksOnTimeout confirms a send that
+// fails with a network timeout shrinks the adaptive batch target,
+// floored at MinBatchSize.
+func TestBufferedWriterAdaptiveBatchShrinksOnTimeout(t *testing.T) {
+	fc := &flakyClient{err: timeoutError{}}
+	w := newTestWriterWithConfig(fc, WriterConfig{
+		Database:      "db",
+		BatchSize:     10,
+		AdaptiveBatch: true,
+		MinBatchSize:  2,
+		MaxBatchSize:  100,
+		MaxRetries:    1,
+		RetryInterval: time.Millisecond,
+	})
+	defer w.Close(context.Background())
+
+	p, err := client.NewPoint("m", nil, map[string]interface{}{"v": 1}, time.Now())
+	if err != nil {
+		t.Fatalf("NewPoint: %v", err)
+	}
+
+	if err := w.send(context.Background(), []*client.Point{p}); err == nil {
+		t.Fatal("send: want a timeout error, got nil")
+	}
+	if got := w.batchSizeTarget(); got != 5 {
+		t.Fatalf("batchSizeTarget = %d, want 5", got)
+	}
+
+	for i := 0; i < 5; i++ {
+		w.send(context.Background(), []*client.Point{p})
+	}
+	if got := w.batchSizeTarget(); got != 2 {
+		t.Fatalf("batchSizeTarget = %d, want 2 (floored)", got)
+	}
+}
+
+// TestBufferedWriterAdaptiveBatchShrinksOn413 confirms a send that
+// fails with InfluxDB's 413 response also shrinks the adaptive batch
+// target.
+func TestBufferedWriterAdaptiveBatchShrinksOn413(t *testing.T) {
+	fc := &flakyClient{err: errors.New("received status code 413 from server")}
+	w := newTestWriterWithConfig(fc, WriterConfig{
+		Database:      "db",
+		BatchSize:     10,
+		AdaptiveBatch: true,
+		MinBatchSize:  2,
+		MaxBatchSize:  100,
+		MaxRetries:    1,
+		RetryInterval: time.Millisecond,
+	})
+	defer w.Close(context.Background())
+
+	p, err := client.NewPoint("m", nil, map[string]interface{}{"v": 1}, time.Now())
+	if err != nil {
+		t.Fatalf("NewPoint: %v", err)
+	}
+
+	w.send(context.Background(), []*client.Point{p})
+	if got := w.batchSizeTarget(); got != 5 {
+		t.Fatalf("batchSizeTarget = %d, want 5", got)
+	}
+}
+
+// TestBufferedWriterFlushesOnBatchSize confirms Write alone, with no
+// explicit Flush, triggers a send once BatchSize points have been
+// queued.
+func TestBufferedWriterFlushesOnBatchSize(t *testing.T) {
+	fc := &fakeClient{}
+	w := newTestWriterWithConfig(fc, WriterConfig{
+		Database:      "db",
+		BatchSize:     3,
+		FlushInterval: time.Hour, // long enough that only BatchSize can trigger the flush
+	})
+	defer w.Close(context.Background())
+
+	p, err := client.NewPoint("m", nil, map[string]interface{}{"v": 1}, time.Now())
+	if err != nil {
+		t.Fatalf("NewPoint: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		w.Write(p)
+	}
+
+	deadline := time.After(time.Second)
+	for {
+		fc.mu.Lock()
+		writes := fc.writes
+		fc.mu.Unlock()
+		if writes == 1 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("writes = %d, want 1 once BatchSize is reached", writes)
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+// TestBufferedWriterFlushesOnInterval confirms a point queued below
+// BatchSize is still flushed once FlushInterval elapses.
+func TestBufferedWriterFlushesOnInterval(t *testing.T) {
+	fc := &fakeClient{}
+	w := newTestWriterWithConfig(fc, WriterConfig{
+		Database:      "db",
+		BatchSize:     1000, // high enough that only FlushInterval can trigger the flush
+		FlushInterval: 10 * time.Millisecond,
+	})
+	defer w.Close(context.Background())
+
+	p, err := client.NewPoint("m", nil, map[string]interface{}{"v": 1}, time.Now())
+	if err != nil {
+		t.Fatalf("NewPoint: %v", err)
+	}
+	w.Write(p)
+
+	deadline := time.After(time.Second)
+	for {
+		fc.mu.Lock()
+		writes := fc.writes
+		fc.mu.Unlock()
+		if writes == 1 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("writes = %d, want 1 once FlushInterval elapses", writes)
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+// TestBufferedWriterBackpressureDropOldest confirms the default
+// BackpressureDropOldest policy never blocks Write and discards the
+// oldest queued point to make room for the newest.
+func TestBufferedWriterBackpressureDropOldest(t *testing.T) {
+	w := newUnstartedTestWriter(WriterConfig{Database: "db", QueueSize: 2})
+
+	p1, _ := client.NewPoint("m", nil, map[string]interface{}{"v": 1}, time.Now())
+	p2, _ := client.NewPoint("m", nil, map[string]interface{}{"v": 2}, time.Now())
+	p3, _ := client.NewPoint("m", nil, map[string]interface{}{"v": 3}, time.Now())
+
+	w.Write(p1)
+	w.Write(p2)
+	if err := w.Write(p3); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	if got := w.Stats().DroppedOldest; got != 1 {
+		t.Fatalf("DroppedOldest = %d, want 1", got)
+	}
+	if got := w.Len(); got != 2 {
+		t.Fatalf("Len = %d, want 2", got)
+	}
+	first := <-w.points
+	if fields, _ := first.Fields(); fields["v"] != int64(2) {
+		t.Fatalf("oldest queued point = %+v, want p2 (p1 dropped)", fields)
+	}
+}
+
+// TestBufferedWriterBackpressureDropNewest confirms BackpressureDropNewest
+// never blocks Write and discards the point just written instead of a
+// queued one.
+func TestBufferedWriterBackpressureDropNewest(t *testing.T) {
+	w := newUnstartedTestWriter(WriterConfig{Database: "db", QueueSize: 2, Backpressure: BackpressureDropNewest})
+
+	p1, _ := client.NewPoint("m", nil, map[string]interface{}{"v": 1}, time.Now())
+	p2, _ := client.NewPoint("m", nil, map[string]interface{}{"v": 2}, time.Now())
+	p3, _ := client.NewPoint("m", nil, map[string]interface{}{"v": 3}, time.Now())
+
+	w.Write(p1)
+	w.Write(p2)
+	if err := w.Write(p3); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	if got := w.Stats().DroppedNewest; got != 1 {
+		t.Fatalf("DroppedNewest = %d, want 1", got)
+	}
+	if got := w.Len(); got != 2 {
+		t.Fatalf("Len = %d, want 2", got)
+	}
+	first := <-w.points
+	if fields, _ := first.Fields(); fields["v"] != int64(1) {
+		t.Fatalf("oldest queued point = %+v, want p1 (p3 dropped)", fields)
+	}
+}
+
+// TestBufferedWriterBackpressureReject confirms BackpressureReject never
+// blocks Write, instead returning ErrBufferFull once the queue is full.
+func TestBufferedWriterBackpressureReject(t *testing.T) {
+	w := newUnstartedTestWriter(WriterConfig{Database: "db", QueueSize: 1, Backpressure: BackpressureReject})
+
+	p1, _ := client.NewPoint("m", nil, map[string]interface{}{"v": 1}, time.Now())
+	p2, _ := client.NewPoint("m", nil, map[string]interface{}{"v": 2}, time.Now())
+
+	if err := w.Write(p1); err != nil {
+		t.Fatalf("Write p1: %v", err)
+	}
+	if err := w.Write(p2); err != ErrBufferFull {
+		t.Fatalf("Write p2 = %v, want ErrBufferFull", err)
+	}
+	if got := w.Stats().Rejected; got != 1 {
+		t.Fatalf("Rejected = %d, want 1", got)
+	}
+}
+
+// TestBufferedWriterBackpressureBlock confirms BackpressureBlock makes
+// Write wait until the queue has room instead of dropping or rejecting.
+func TestBufferedWriterBackpressureBlock(t *testing.T) {
+	w := newUnstartedTestWriter(WriterConfig{Database: "db", QueueSize: 1, Backpressure: BackpressureBlock})
+
+	p1, _ := client.NewPoint("m", nil, map[string]interface{}{"v": 1}, time.Now())
+	p2, _ := client.NewPoint("m", nil, map[string]interface{}{"v": 2}, time.Now())
+
+	if err := w.Write(p1); err != nil {
+		t.Fatalf("Write p1: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- w.Write(p2) }()
+
+	select {
+	case <-done:
+		t.Fatal("Write returned before the queue had room")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	<-w.points // drain p1, making room for p2
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Write p2: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Write never returned after the queue had room")
+	}
+}
+
+// TestBufferedWriterAppliesRetentionPolicy confirms WriterConfig.
+// RetentionPolicy is carried through to the BatchPoints every flush
+// writes, so points can target a non-default retention policy (e.g.
+// "rp_30d") without the caller building BatchPoints by hand.
+func TestBufferedWriterAppliesRetentionPolicy(t *testing.T) {
+	fc := &fakeClient{}
+	w := newTestWriterWithConfig(fc, WriterConfig{Database: "db", RetentionPolicy: "rp_30d"})
+
+	p, err := client.NewPoint("m", nil, map[string]interface{}{"v": 1}, time.Now())
+	if err != nil {
+		t.Fatalf("NewPoint: %v", err)
+	}
+	w.Write(p)
+	if err := w.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	defer w.Close(context.Background())
+
+	fc.mu.Lock()
+	bp := fc.lastBP
+	fc.mu.Unlock()
+	if bp == nil {
+		t.Fatal("no batch was written")
+	}
+	if rp := bp.RetentionPolicy(); rp != "rp_30d" {
+		t.Fatalf("RetentionPolicy = %q, want %q", rp, "rp_30d")
+	}
+}
+
+// TestBufferedWriterPacingReportsProgress confirms Pacing's OnPace is
+// called once per batch sent, with running point/batch totals, so a
+// backfill can report its own progress.
+func TestBufferedWriterPacingReportsProgress(t *testing.T) {
+	fc := &fakeClient{}
+
+	var mu sync.Mutex
+	var reports []PaceProgress
+	w := newTestWriterWithConfig(fc, WriterConfig{
+		Database: "db",
+		Pacing:   &RateLimitConfig{PointsPerSec: 1000, RequestsPerSec: 1000},
+		OnPace: func(p PaceProgress) {
+			mu.Lock()
+			reports = append(reports, p)
+			mu.Unlock()
+		},
+	})
+	defer w.Close(context.Background())
+
+	p, err := client.NewPoint("m", nil, map[string]interface{}{"v": 1}, time.Now())
+	if err != nil {
+		t.Fatalf("NewPoint: %v", err)
+	}
+
+	// Send directly on w.toSend, the same request loop() itself would
+	// build from Write+Flush, but without the buffering loop in between
+	// — the known race in loop()'s own Write/Flush interaction
+	// (writer_test.go's fakeClient-based tests hit it too) is out of
+	// scope for this test, which only cares about sendLoop's pacing and
+	// reporting.
+	for i := 0; i < 2; i++ {
+		reply := make(chan error, 1)
+		w.toSend <- sendRequest{ctx: context.Background(), points: []*client.Point{p}, reply: reply}
+		if err := <-reply; err != nil {
+			t.Fatalf("send %d: %v", i, err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reports) != 2 {
+		t.Fatalf("len(reports) = %d, want 2", len(reports))
+	}
+	if reports[0].PointsTotal != 1 || reports[0].Batches != 1 || reports[0].Err != nil {
+		t.Fatalf("reports[0] = %+v", reports[0])
+	}
+	if reports[1].PointsTotal != 2 || reports[1].Batches != 2 || reports[1].Err != nil {
+		t.Fatalf("reports[1] = %+v", reports[1])
+	}
+}
+
+// TestBufferedWriterPacingBlocksUntilContextDone confirms a send that
+// would exceed Pacing's budget blocks instead of failing fast, and gives
+// up as soon as its context is done instead of waiting out the whole
+// window.
+func TestBufferedWriterPacingBlocksUntilContextDone(t *testing.T) {
+	fc := &fakeClient{}
+	w := newTestWriterWithConfig(fc, WriterConfig{
+		Database: "db",
+		Pacing:   &RateLimitConfig{PointsPerSec: 1},
+	})
+	defer w.Close(context.Background())
+
+	p, err := client.NewPoint("m", nil, map[string]interface{}{"v": 1}, time.Now())
+	if err != nil {
+		t.Fatalf("NewPoint: %v", err)
+	}
+
+	if err := w.send(context.Background(), []*client.Point{p}); err != nil {
+		t.Fatalf("first send: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := w.send(ctx, []*client.Point{p}); err != ctx.Err() {
+		t.Fatalf("second send = %v, want %v", err, ctx.Err())
+	}
+}
@@ -0,0 +1,45 @@
+package influx
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// WriteReport is the per-call result of WriteBatchPointsWithReport, for
+// an application that wants to record its own ingest SLOs (write
+// latency, bytes/sec) without wrapping every write call with a timer.
+// See WriteStats for the equivalent running totals across all calls.
+type WriteReport struct {
+	Points   int
+	Bytes    int64
+	Duration time.Duration
+	Retries  int64
+}
+
+// WriteBatchPointsWithReport is WriteBatchPointsContext, but also
+// returns a WriteReport. Points and Bytes are measured on bp as the
+// caller passed it, since the batch WriteBatchPointsContext actually
+// sends may differ slightly (e.g. a PointMiddleware dropping a point);
+// Stats reflects what was actually written.
+func (c *Client) WriteBatchPointsWithReport(ctx context.Context, bp client.BatchPoints) (WriteReport, error) {
+	report := WriteReport{
+		Points: len(bp.Points()),
+		Bytes:  batchBytes(bp),
+	}
+
+	retriesBefore := atomic.LoadInt64(&c.writeStats.retries)
+	start := clockNow()
+	err := c.WriteBatchPointsContext(ctx, bp)
+	report.Duration = time.Since(start)
+	report.Retries = atomic.LoadInt64(&c.writeStats.retries) - retriesBefore
+	return report, err
+}
+
+// WriteBatchPointsWithReport writes bp using the package-level default
+// Client and reports timing and size for it.
+func WriteBatchPointsWithReport(ctx context.Context, bp client.BatchPoints) (WriteReport, error) {
+	return gClient().WriteBatchPointsWithReport(ctx, bp)
+}
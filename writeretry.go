@@ -0,0 +1,100 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// WriteRetryConfig configures WriteRetry.
+type WriteRetryConfig struct {
+	// MaxAttempts is the total number of attempts, including the first;
+	// 0 or 1 disables retrying.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry; each subsequent
+	// retry doubles it, capped at MaxDelay.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff delay. 0 means no cap.
+	MaxDelay time.Duration
+	// Jitter is the fraction (0 to 1) of each delay randomized away,
+	// so many Clients retrying together don't all hammer InfluxDB at
+	// once.
+	Jitter float64
+}
+
+// WriteError is returned by WriteBatchPointsContext when every retry
+// attempt WriteRetry allowed has been exhausted. It wraps the last
+// underlying error and reports how many attempts were made.
+type WriteError struct {
+	Err      error
+	Attempts int
+}
+
+func (e *WriteError) Error() string {
+	return fmt.Sprintf("influx: write failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *WriteError) Unwrap() error { return e.Err }
+
+// WriteRetry enables retrying Insert/WriteBatchPoints on retryable
+// errors (timeouts and InfluxDB's "hinted handoff queue full") with
+// exponential backoff and jitter, per cfg. Call with a zero
+// WriteRetryConfig to disable retrying.
+func (c *Client) WriteRetry(cfg WriteRetryConfig) {
+	if cfg.MaxAttempts <= 1 {
+		c.writeRetry = nil
+		return
+	}
+	c.writeRetry = &cfg
+}
+
+// withWriteRetry runs op, retrying it per c.writeRetry while it keeps
+// failing with a retryable error. If every attempt fails it returns a
+// *WriteError wrapping the last error and the attempt count.
+func (c *Client) withWriteRetry(ctx context.Context, op func() error) error {
+	if c.writeRetry == nil {
+		return op()
+	}
+
+	cfg := c.writeRetry
+	delay := cfg.BaseDelay
+	var err error
+	for attempt := 1; attempt <= cfg.MaxAttempts; attempt++ {
+		if err = op(); err == nil {
+			return nil
+		}
+		if attempt == cfg.MaxAttempts || !isRetryableWriteError(err) {
+			return &WriteError{Err: err, Attempts: attempt}
+		}
+		atomic.AddInt64(&c.writeStats.retries, 1)
+
+		wait := delay
+		if cfg.Jitter > 0 {
+			wait -= time.Duration(rand.Float64() * cfg.Jitter * float64(wait))
+		}
+		select {
+		case <-after(wait):
+		case <-ctx.Done():
+			return &WriteError{Err: ctx.Err(), Attempts: attempt}
+		}
+
+		delay *= 2
+		if cfg.MaxDelay > 0 && delay > cfg.MaxDelay {
+			delay = cfg.MaxDelay
+		}
+	}
+	return &WriteError{Err: err, Attempts: cfg.MaxAttempts}
+}
+
+// isRetryableWriteError reports whether err is worth retrying: a
+// network timeout, or InfluxDB's "hinted handoff queue full" backpressure
+// signal. client.Client surfaces write failures as a plain
+// errors.New(body), with no status code attached, so 5xx responses
+// cannot be distinguished from 4xx ones by error alone and are treated
+// as non-retryable; IsRetryable checks for one anyway, for errors that
+// do carry one (e.g. from QueryContext).
+func isRetryableWriteError(err error) bool {
+	return IsRetryable(err)
+}
@@ -0,0 +1,161 @@
+// Package zap is a go.uber.org/zap/zapcore.Core that converts log
+// entries into points and writes them through a Writer, so low-volume
+// structured events (a startup notice, a rare warning, a business
+// event) can be graphed alongside metrics instead of living only in a
+// log stream.
+//
+// Level and, when set, the entry's logger name become tags; every
+// field becomes a field, keyed by its own name (With-bound fields
+// included). This isn't a general-purpose log shipper: it's meant for
+// a modest volume of structured events, not a firehose of debug logs,
+// so MessageSampler lets a caller drop the message field (still
+// recording level, tags and other fields) for whichever fraction of
+// records it doesn't want to pay to store as a field.
+package zap
+
+import (
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultMeasurement is the measurement Core writes points to when
+// Core.Measurement is unset.
+const DefaultMeasurement = "log"
+
+// Writer is the subset of *influx.BufferedWriter a Core needs.
+type Writer interface {
+	Write(point *client.Point) error
+}
+
+// Core is a zapcore.Core writing one point per log entry through
+// Writer.
+type Core struct {
+	Writer Writer
+
+	// Measurement is the measurement every point is written under.
+	// Defaults to DefaultMeasurement.
+	Measurement string
+	// LevelEnabler sets the minimum level Enabled reports as loggable.
+	// Defaults to zapcore.InfoLevel.
+	LevelEnabler zapcore.LevelEnabler
+	// MessageSampler, if set, is called with every entry to decide
+	// whether its message text is included as a field. An entry whose
+	// sampler returns false is still written, with every tag and
+	// field except the message. Defaults to always including it.
+	MessageSampler func(ent zapcore.Entry) bool
+	// OnError, if set, is called when building or writing an entry's
+	// point fails, instead of the error only reaching zap's own
+	// (frequently ignored) Write return value.
+	OnError func(err error)
+
+	fields map[string]interface{}
+}
+
+// NewCore returns a Core writing through w.
+func NewCore(w Writer) *Core {
+	return &Core{Writer: w}
+}
+
+// Enabled implements zapcore.LevelEnabler.
+func (c *Core) Enabled(level zapcore.Level) bool {
+	if c.LevelEnabler != nil {
+		return c.LevelEnabler.Enabled(level)
+	}
+	return level >= zapcore.InfoLevel
+}
+
+// With implements zapcore.Core.
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	clone := c.clone()
+	for k, v := range encodeFields(fields) {
+		clone.fields[k] = v
+	}
+	return clone
+}
+
+// Check implements zapcore.Core.
+func (c *Core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *Core) Write(ent zapcore.Entry, extra []zapcore.Field) error {
+	tags := map[string]string{"level": ent.Level.String()}
+	if ent.LoggerName != "" {
+		tags["logger"] = ent.LoggerName
+	}
+
+	fields := make(map[string]interface{}, len(c.fields)+len(extra)+1)
+	for k, v := range c.fields {
+		fields[k] = v
+	}
+	for k, v := range encodeFields(extra) {
+		fields[k] = v
+	}
+	if c.MessageSampler == nil || c.MessageSampler(ent) {
+		fields["message"] = ent.Message
+	}
+	if len(fields) == 0 {
+		fields["count"] = int64(1)
+	}
+
+	p, err := client.NewPoint(c.measurement(), tags, fields, ent.Time)
+	if err != nil {
+		if c.OnError != nil {
+			c.OnError(err)
+		}
+		return err
+	}
+	if err := c.Writer.Write(p); err != nil {
+		if c.OnError != nil {
+			c.OnError(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// Sync implements zapcore.Core. Writer (typically a
+// *influx.BufferedWriter) buffers and flushes on its own schedule;
+// there is nothing synchronous for Sync to flush here.
+func (c *Core) Sync() error {
+	return nil
+}
+
+func (c *Core) clone() *Core {
+	clone := *c
+	clone.fields = make(map[string]interface{}, len(c.fields))
+	for k, v := range c.fields {
+		clone.fields[k] = v
+	}
+	return &clone
+}
+
+func (c *Core) measurement() string {
+	if c.Measurement != "" {
+		return c.Measurement
+	}
+	return DefaultMeasurement
+}
+
+// encodeFields renders fields as a plain map via zapcore's own
+// MapObjectEncoder, then converts any value client.NewPoint wouldn't
+// accept (e.g. a time.Duration) to one it does, the same conversion
+// slog's attrValue does for the equivalent slog.Value kinds.
+func encodeFields(fields []zapcore.Field) map[string]interface{} {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	for k, v := range enc.Fields {
+		if d, ok := v.(time.Duration); ok {
+			enc.Fields[k] = d.Seconds()
+		}
+	}
+	return enc.Fields
+}
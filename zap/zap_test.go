@@ -0,0 +1,121 @@
+package zap
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fakeWriter records every point written to it, in place of a real
+// *influx.BufferedWriter.
+type fakeWriter struct {
+	mu     sync.Mutex
+	points []*client.Point
+}
+
+func (w *fakeWriter) Write(p *client.Point) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.points = append(w.points, p)
+	return nil
+}
+
+func (w *fakeWriter) last() *client.Point {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.points) == 0 {
+		return nil
+	}
+	return w.points[len(w.points)-1]
+}
+
+// TestWriteTagsLevelAndFieldsAttrs confirms the level is tagged and a
+// plain field becomes a numeric field.
+func TestWriteTagsLevelAndFieldsAttrs(t *testing.T) {
+	w := &fakeWriter{}
+	logger := zap.New(NewCore(w))
+	logger.Info("started", zap.Int("workers", 4))
+
+	p := w.last()
+	if p == nil {
+		t.Fatal("no point written")
+	}
+	if p.Tags()["level"] != "info" {
+		t.Fatalf("level tag = %q", p.Tags()["level"])
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["workers"] != int64(4) {
+		t.Fatalf("workers = %v, want int64(4)", fields["workers"])
+	}
+	if fields["message"] != "started" {
+		t.Fatalf("message = %v", fields["message"])
+	}
+}
+
+// TestWriteLoggerNameBecomesTag confirms a named logger's entries are
+// tagged with it instead of turning it into a field.
+func TestWriteLoggerNameBecomesTag(t *testing.T) {
+	w := &fakeWriter{}
+	logger := zap.New(NewCore(w)).Named("db")
+	logger.Info("connected")
+
+	p := w.last()
+	if p.Tags()["logger"] != "db" {
+		t.Fatalf("logger tag = %q", p.Tags()["logger"])
+	}
+}
+
+// TestWithAddsBoundFields confirms fields bound via With are merged
+// into every subsequent entry's point.
+func TestWithAddsBoundFields(t *testing.T) {
+	w := &fakeWriter{}
+	logger := zap.New(NewCore(w)).With(zap.String("tenant", "acme"))
+	logger.Info("request handled")
+
+	fields, err := w.last().Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["tenant"] != "acme" {
+		t.Fatalf("fields = %v", fields)
+	}
+}
+
+// TestWriteMessageSamplerDropsMessage confirms a MessageSampler
+// returning false omits the message field but still writes the point.
+func TestWriteMessageSamplerDropsMessage(t *testing.T) {
+	w := &fakeWriter{}
+	core := NewCore(w)
+	core.MessageSampler = func(ent zapcore.Entry) bool { return false }
+	logger := zap.New(core)
+	logger.Info("noisy", zap.Int("n", 1))
+
+	fields, err := w.last().Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fields["message"]; ok {
+		t.Fatal("message should have been sampled out")
+	}
+	if fields["n"] != int64(1) {
+		t.Fatalf("fields = %v", fields)
+	}
+}
+
+// TestEnabledRespectsLevel confirms a Core filters below its
+// configured LevelEnabler.
+func TestEnabledRespectsLevel(t *testing.T) {
+	c := &Core{Writer: &fakeWriter{}, LevelEnabler: zapcore.WarnLevel}
+	if c.Enabled(zapcore.InfoLevel) {
+		t.Fatal("Info should be disabled under WarnLevel")
+	}
+	if !c.Enabled(zapcore.ErrorLevel) {
+		t.Fatal("Error should be enabled under WarnLevel")
+	}
+}